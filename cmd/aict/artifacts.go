@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/authorship"
+	"github.com/y-hirakaw/ai-code-tracker/internal/git"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// excludeArtifacts は行数の集計対象から artifacts のファイルを除く判定を返します。
+// artifacts が未設定の場合は includePath をそのまま返します。
+func excludeArtifacts(includePath func(string) bool, cfg *tracker.Config) func(string) bool {
+	if cfg == nil || cfg.Artifacts == nil {
+		return includePath
+	}
+	return func(filePath string) bool {
+		if tracker.IsArtifactFile(filePath, cfg) {
+			return false
+		}
+		return includePath == nil || includePath(filePath)
+	}
+}
+
+// excludeIgnored は exclude_patterns・.aictignore で除外したファイルを集計対象から除く判定を返します。
+// Authorship Log は記録時点の設定で絞り込まれているため、後から追加したパターンをレポートに反映するために使います。
+func excludeIgnored(includePath func(string) bool, cfg *tracker.Config) func(string) bool {
+	if cfg == nil || len(cfg.ExcludePatterns)+len(cfg.IgnorePatterns) == 0 {
+		return includePath
+	}
+	exclude := cfg.ExcludeMatcher()
+	return func(filePath string) bool {
+		if exclude.Match(filePath) {
+			return false
+		}
+		return includePath == nil || includePath(filePath)
+	}
+}
+
+// collectArtifactStats はコミット範囲内の artifacts の変更をファイル単位で集計します。
+// コミット・サンプリングの対象は collectAuthorStats と同じで、git呼び出しは CachingExecutor で共有されます。
+func collectArtifactStats(rangeSpec string, sampleRate float64, includePath func(string) bool, cfg *tracker.Config) (*tracker.ArtifactStats, error) {
+	_, commits, err := git.GetRangeNumstat(newExecutor(), rangeSpec)
+	if err != nil {
+		return nil, err
+	}
+	nm := gitnotes.NewNotesManagerWithExecutor(newExecutor())
+	allLogs, _ := nm.GetAuthorshipLogsForRange(rangeSpec)
+
+	targetCommits := commits
+	if sampleRate > 0 && sampleRate < 1 {
+		targetCommits = sampleCommits(commits, sampleRate)
+	}
+
+	stats := &tracker.ArtifactStats{}
+	files := make(map[string]bool)
+	for _, commitHash := range targetCommits {
+		alog := allLogs[commitHash]
+		if alog == nil {
+			continue
+		}
+		for filePath, fileInfo := range alog.Files {
+			if !tracker.IsArtifactFile(filePath, cfg) || (includePath != nil && !includePath(filePath)) {
+				continue
+			}
+			files[filePath] = true
+			stats.Changes++
+			if isAIArtifactChange(fileInfo) {
+				stats.AIChanges++
+			} else {
+				stats.HumanChanges++
+			}
+		}
+	}
+	stats.Files = len(files)
+
+	// サンプリング時は変更件数のみ全コミット数に外挿（ファイル数はサンプル内の実数）
+	if len(targetCommits) > 0 && len(targetCommits) < len(commits) {
+		factor := float64(len(commits)) / float64(len(targetCommits))
+		stats.AIChanges = int(math.Round(float64(stats.AIChanges) * factor))
+		stats.HumanChanges = int(math.Round(float64(stats.HumanChanges) * factor))
+		stats.Changes = stats.AIChanges + stats.HumanChanges
+	}
+	stats.AIPercentage = metrics.SafePercent(stats.AIChanges, stats.Changes)
+	return stats, nil
+}
+
+// isAIArtifactChange は1ファイルの変更をAIの変更とみなすかを判定します。
+// AIの行数が人間以上ならAIとし、行数を記録できないファイル（行範囲が空）はAIの作成者がいればAIとします。
+func isAIArtifactChange(fileInfo tracker.FileInfo) bool {
+	var aiLines, humanLines int
+	hasAI := false
+	for i := range fileInfo.Authors {
+		share := fileInfo.Authors[i].AIShare()
+		ai, human := splitByAIShare(authorship.CountLines(fileInfo.Authors[i].Lines), share)
+		hasAI = hasAI || share > 0
+		aiLines += ai
+		humanLines += human
+	}
+	return hasAI && aiLines >= humanLines
+}
+
+// printArtifactStats は artifacts の変更数をファイル単位で出力します。
+func printArtifactStats(a *tracker.ArtifactStats) {
+	fmt.Printf("■ Other tracked artifacts: %d files, %d changes\n", a.Files, a.Changes)
+	fmt.Printf("  AI:    %d changes (%.1f%%)\n", a.AIChanges, a.AIPercentage)
+	fmt.Printf("  Human: %d changes (%.1f%%)\n", a.HumanChanges, metrics.SafePercent(a.HumanChanges, a.Changes))
+	fmt.Println()
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestGenerateRangeReport_Artifacts(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	cfg.Artifacts = &tracker.ArtifactsConfig{Patterns: []string{"*.sql", "schemas/*"}}
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	base := testutil.GitCommit(t, tmpDir, "initial")
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+
+	// AIがマイグレーションとスキーマを作成
+	testutil.CreateTestFile(t, tmpDir, "db/001_init.sql", "CREATE TABLE users (\n  id INTEGER\n);\n")
+	testutil.CreateTestFile(t, tmpDir, "schemas/user.json", "{\n  \"type\": \"object\"\n}\n")
+	if err := recordCheckpoint("Claude", "", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc main() {}\n")
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("human checkpoint error = %v", err)
+	}
+	testutil.GitCommit(t, tmpDir, "Add schema and migration")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	// 人間がマイグレーションを修正
+	testutil.CreateTestFile(t, tmpDir, "db/001_init.sql", "CREATE TABLE users (\n  id INTEGER PRIMARY KEY\n);\n")
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("human checkpoint error = %v", err)
+	}
+	testutil.GitCommit(t, tmpDir, "Fix migration")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	report, _, err := generateRangeReport(&ReportOptions{Range: base + "..HEAD"})
+	if err != nil {
+		t.Fatalf("generateRangeReport() error = %v", err)
+	}
+	want := tracker.ArtifactStats{Files: 2, Changes: 3, AIChanges: 2, HumanChanges: 1, AIPercentage: metrics.SafePercent(2, 3)}
+	if report.Artifacts == nil || *report.Artifacts != want {
+		t.Fatalf("Artifacts = %+v, want %+v", report.Artifacts, want)
+	}
+	// artifacts の行は行数の集計に含めない
+	if report.Summary.TotalLines != 3 || report.Summary.AILines != 0 {
+		t.Errorf("Summary = %+v, want 3 human lines of main.go only", report.Summary)
+	}
+	if err := formatRangeReport(report, "table", nil); err != nil {
+		t.Errorf("formatRangeReport() error = %v", err)
+	}
+}
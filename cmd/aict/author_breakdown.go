@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// buildAuthorBreakdown は作成者別統計をAuthorMappingsで解決した名前ごとに集約します。
+// 集約後のコミット数は各エイリアスのコミット数の合計です（同一コミットに複数エイリアスが
+// 参加した場合は重複して数えます）。
+func buildAuthorBreakdown(byAuthor []tracker.AuthorStats, mappings map[string]string) []tracker.AuthorBreakdown {
+	grouped := make(map[string]*tracker.AuthorBreakdown)
+	var order []string
+
+	for _, stats := range byAuthor {
+		name := stats.Name
+		if mapped, ok := mappings[name]; ok && mapped != "" {
+			name = mapped
+		}
+
+		b, exists := grouped[name]
+		if !exists {
+			b = &tracker.AuthorBreakdown{Name: name}
+			grouped[name] = b
+			order = append(order, name)
+		}
+		if stats.Name != name {
+			b.Aliases = append(b.Aliases, stats.Name)
+		}
+		b.Added += stats.Lines
+		b.Deleted += stats.Deleted
+		b.Commits += stats.Commits
+		b.AILines += stats.AILines
+	}
+
+	breakdown := make([]tracker.AuthorBreakdown, 0, len(order))
+	for _, name := range order {
+		b := grouped[name]
+		b.AIPercentage = metrics.SafePercent(b.AILines, b.Added)
+		sort.Strings(b.Aliases)
+		breakdown = append(breakdown, *b)
+	}
+
+	// 追加行数の多い順（同数は名前順）
+	sort.SliceStable(breakdown, func(i, j int) bool {
+		if breakdown[i].Added != breakdown[j].Added {
+			return breakdown[i].Added > breakdown[j].Added
+		}
+		return breakdown[i].Name < breakdown[j].Name
+	})
+	return breakdown
+}
+
+// printAuthorBreakdown は作成者別の内訳をテーブル形式で出力します。
+func printAuthorBreakdown(breakdown []tracker.AuthorBreakdown) {
+	fmt.Println("By Author:")
+	fmt.Printf("  %-20s %8s %8s %7s %8s\n", "Author", "Added", "Deleted", "AI%", "Commits")
+	for _, b := range breakdown {
+		fmt.Printf("  %-20s %8d %8d %6.1f%% %8d\n", b.Name, b.Added, b.Deleted, b.AIPercentage, b.Commits)
+		if len(b.Aliases) > 0 {
+			fmt.Printf("    aliases: %s\n", strings.Join(b.Aliases, ", "))
+		}
+	}
+	fmt.Println()
+}
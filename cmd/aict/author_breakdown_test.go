@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestBuildAuthorBreakdown(t *testing.T) {
+	byAuthor := []tracker.AuthorStats{
+		{Name: "Claude Code", Type: tracker.AuthorTypeAI, Lines: 60, AILines: 60, Deleted: 5, Commits: 3},
+		{Name: "claude-opus", Type: tracker.AuthorTypeAI, Lines: 20, AILines: 20, Deleted: 1, Commits: 1},
+		{Name: "yhirakaw", Type: tracker.AuthorTypeHuman, Lines: 15, Deleted: 10, Commits: 2},
+		{Name: "Yoshi", Type: tracker.AuthorTypeHuman, Lines: 5, Deleted: 0, Commits: 1},
+	}
+	mappings := map[string]string{
+		"claude-opus": "Claude Code",
+		"yhirakaw":    "Yoshi",
+	}
+
+	got := buildAuthorBreakdown(byAuthor, mappings)
+	want := []tracker.AuthorBreakdown{
+		{Name: "Claude Code", Aliases: []string{"claude-opus"}, Added: 80, Deleted: 6, AILines: 80, AIPercentage: 100, Commits: 4},
+		{Name: "Yoshi", Aliases: []string{"yhirakaw"}, Added: 20, Deleted: 10, AILines: 0, AIPercentage: 0, Commits: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildAuthorBreakdown() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestBuildAuthorBreakdown_MixedTypes(t *testing.T) {
+	// 同じ名前に集約されたAIと人間の行はAI%に反映される
+	byAuthor := []tracker.AuthorStats{
+		{Name: "pair", Type: tracker.AuthorTypeHuman, Lines: 30},
+		{Name: "pair-bot", Type: tracker.AuthorTypeAI, Lines: 10, AILines: 10},
+	}
+	got := buildAuthorBreakdown(byAuthor, map[string]string{"pair-bot": "pair"})
+	if len(got) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(got))
+	}
+	if got[0].AIPercentage != 25 {
+		t.Errorf("AIPercentage = %v, want 25", got[0].AIPercentage)
+	}
+}
+
+func TestBuildAuthorBreakdown_NoMappings(t *testing.T) {
+	byAuthor := []tracker.AuthorStats{
+		{Name: "b", Type: tracker.AuthorTypeHuman, Lines: 0},
+		{Name: "a", Type: tracker.AuthorTypeHuman, Lines: 0},
+	}
+	got := buildAuthorBreakdown(byAuthor, nil)
+	if len(got) != 2 || got[0].Name != "a" || got[1].Name != "b" {
+		t.Errorf("buildAuthorBreakdown() = %+v, want a, b ordered by name on ties", got)
+	}
+	if got[0].AIPercentage != 0 {
+		t.Errorf("AIPercentage with zero lines = %v, want 0", got[0].AIPercentage)
+	}
+}
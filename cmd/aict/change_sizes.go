@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+const (
+	// minOutlierChanges は外れ値を判定する最小の変更数（少ないと四分位数が安定しないため）
+	minOutlierChanges = 8
+	// outlierFenceIQR は外れ値とする第3四分位数からの距離（IQRの倍数。Tukeyの far out）
+	outlierFenceIQR = 3.0
+	// maxListedOutliers はテーブルに表示する外れ値の最大数（JSONはすべて出力）
+	maxListedOutliers = 10
+)
+
+// buildAIChangeSizes はAIの変更（コミット・ファイルごとのAIの追加行数）の分布と外れ値を求めます。
+// 変更がない場合は nil を返します。
+func buildAIChangeSizes(changes []tracker.AIChange) *tracker.AIChangeSizes {
+	if len(changes) == 0 {
+		return nil
+	}
+	sizes := make([]int, len(changes))
+	total := 0
+	for i, c := range changes {
+		sizes[i] = c.Lines
+		total += c.Lines
+	}
+	sort.Ints(sizes)
+
+	stats := &tracker.AIChangeSizes{
+		Changes: len(changes),
+		Mean:    float64(total) / float64(len(changes)),
+		P50:     metrics.Percentile(sizes, 50),
+		P90:     metrics.Percentile(sizes, 90),
+		Max:     sizes[len(sizes)-1],
+	}
+	if len(sizes) < minOutlierChanges {
+		return stats
+	}
+
+	q1, q3 := metrics.Percentile(sizes, 25), metrics.Percentile(sizes, 75)
+	stats.OutlierThreshold = float64(q3) + outlierFenceIQR*float64(q3-q1)
+	for _, c := range changes {
+		if float64(c.Lines) > stats.OutlierThreshold {
+			stats.Outliers = append(stats.Outliers, c)
+		}
+	}
+	sort.SliceStable(stats.Outliers, func(i, j int) bool {
+		return stats.Outliers[i].Lines > stats.Outliers[j].Lines
+	})
+	return stats
+}
+
+// printAIChangeSizes はAIの変更の大きさの分布と、レビューが必要そうな外れ値を表示します。
+func printAIChangeSizes(s *tracker.AIChangeSizes) {
+	fmt.Println("AI Change Sizes (AI-added lines per file per commit):")
+	fmt.Printf("  Changes: %d  Mean: %.1f  p50: %d  p90: %d  Max: %d\n", s.Changes, s.Mean, s.P50, s.P90, s.Max)
+	if len(s.Outliers) > 0 {
+		fmt.Printf("  Outliers (> %.0f lines, worth a closer review):\n", s.OutlierThreshold)
+		for i, o := range s.Outliers {
+			if i == maxListedOutliers {
+				fmt.Printf("    ... and %d more (see --format json)\n", len(s.Outliers)-maxListedOutliers)
+				break
+			}
+			fmt.Printf("    %s  %-40s %6d行\n", shortCommit(o.Commit), o.Path, o.Lines)
+		}
+	}
+	fmt.Println()
+}
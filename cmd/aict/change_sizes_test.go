@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestBuildAIChangeSizes(t *testing.T) {
+	if got := buildAIChangeSizes(nil); got != nil {
+		t.Errorf("buildAIChangeSizes(nil) = %+v, want nil", got)
+	}
+
+	// 10〜19行の変更が10件と、800行の変更が1件
+	var changes []tracker.AIChange
+	for i := 0; i < 10; i++ {
+		changes = append(changes, tracker.AIChange{Commit: fmt.Sprintf("c%d", i), Path: "small.go", Lines: 10 + i})
+	}
+	changes = append(changes, tracker.AIChange{Commit: "big", Path: "dump.go", Lines: 800})
+
+	got := buildAIChangeSizes(changes)
+	if got.Changes != 11 || got.P50 != 15 || got.P90 != 19 || got.Max != 800 {
+		t.Errorf("distribution = %+v, want 11 changes, p50 15, p90 19, max 800", got)
+	}
+	if len(got.Outliers) != 1 || got.Outliers[0].Commit != "big" {
+		t.Errorf("outliers = %+v, want only the 800-line change", got.Outliers)
+	}
+	// Q1=12, Q3=18 → 18 + 3×6
+	if got.OutlierThreshold != 36 {
+		t.Errorf("OutlierThreshold = %v, want 36", got.OutlierThreshold)
+	}
+
+	// 件数が少ない場合は外れ値を判定しない
+	few := buildAIChangeSizes(changes[8:])
+	if few.Changes != 3 || few.Max != 800 || len(few.Outliers) != 0 {
+		t.Errorf("few changes = %+v, want no outliers", few)
+	}
+}
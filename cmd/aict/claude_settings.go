@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/templates"
+)
+
+// setup-hooks --settings で指定できるClaude Code設定の書き込み先
+const (
+	claudeSettingsProject = "project" // .claude/settings.json（リポジトリにコミットしてチームで共有）
+	claudeSettingsLocal   = "local"   // .claude/settings.local.json（コミットしない個人設定）
+)
+
+// resolveClaudeSettingsPath は --settings の指定からClaude Code設定ファイルのパスを返します。
+// project・local 以外はファイルパスとして扱い、相対パスはリポジトリルートからの相対とします。
+func resolveClaudeSettingsPath(repoRoot, target string) string {
+	switch target {
+	case "", claudeSettingsProject:
+		return filepath.Join(repoRoot, ".claude", "settings.json")
+	case claudeSettingsLocal:
+		return filepath.Join(repoRoot, ".claude", "settings.local.json")
+	}
+	if filepath.IsAbs(target) {
+		return target
+	}
+	return filepath.Join(repoRoot, target)
+}
+
+// claudeSettingsPaths はhooks status・uninstall が検査するリポジトリ内の設定ファイル（project, local の順）を返します。
+func claudeSettingsPaths(repoRoot string) []string {
+	return []string{
+		resolveClaudeSettingsPath(repoRoot, claudeSettingsProject),
+		resolveClaudeSettingsPath(repoRoot, claudeSettingsLocal),
+	}
+}
+
+// hookMatchers はイベントの値をmatcherの配列として返します。
+// 配列形式（[{matcher, hooks}, ...]）に加え、matcherを1つだけ書いたオブジェクト形式も受け付けます。
+func hookMatchers(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		return []interface{}{v}
+	}
+	return nil
+}
+
+// hasAICTHooks は設定ファイルのいずれかのイベントにaictのhookが登録されているかを判定します。
+func hasAICTHooks(settingsPath string) bool {
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		return false
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return false
+	}
+	hooks, _ := settings["hooks"].(map[string]interface{})
+	for _, value := range hooks {
+		if len(aictMatchers(value)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeAICTSettings は既存の設定JSONからaictのエントリを取り除き、テンプレートのエントリを追加します。
+// aictに関係しないキー・イベント・matcherは元のJSONの値をそのまま残します（キー順と空白は整形されます）。
+// オブジェクト形式のイベントにaictのエントリを追加する場合は配列形式に変換します。
+func mergeAICTSettings(data []byte) ([]byte, error) {
+	settings := make(map[string]json.RawMessage)
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, fmt.Errorf("parsing settings: %w", err)
+		}
+	}
+
+	hooks := make(map[string]json.RawMessage)
+	if raw, ok := settings["hooks"]; ok {
+		if err := json.Unmarshal(raw, &hooks); err != nil {
+			return nil, fmt.Errorf("parsing settings hooks: must be an object: %w", err)
+		}
+	}
+
+	for event, raw := range hooks {
+		kept, changed, err := stripAICTMatchersRaw(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parsing settings hooks.%s: %w", event, err)
+		}
+		if !changed {
+			continue
+		}
+		if len(kept) == 0 {
+			delete(hooks, event)
+			continue
+		}
+		if hooks[event], err = json.Marshal(kept); err != nil {
+			return nil, err
+		}
+	}
+
+	for event, matchers := range templateSettingsHooks() {
+		var entries []json.RawMessage
+		if raw, ok := hooks[event]; ok {
+			entries = rawMatchers(raw)
+		}
+		for _, m := range matchers {
+			b, err := json.Marshal(m)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, b)
+		}
+		b, err := json.Marshal(entries)
+		if err != nil {
+			return nil, err
+		}
+		hooks[event] = b
+	}
+
+	hooksRaw, err := json.Marshal(hooks)
+	if err != nil {
+		return nil, err
+	}
+	settings["hooks"] = hooksRaw
+
+	out, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// rawMatchers はイベントの値（配列またはオブジェクト）をmatcherごとのJSONに分割します。
+func rawMatchers(raw json.RawMessage) []json.RawMessage {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(raw, &entries); err == nil {
+		return entries
+	}
+	return []json.RawMessage{raw}
+}
+
+// stripAICTMatchersRaw はイベントの値からaictのmatcher・コマンドを除去します。
+// aictを含まないmatcherは元のJSONのまま返します。変更がない場合は changed=false です。
+func stripAICTMatchersRaw(raw json.RawMessage) (kept []json.RawMessage, changed bool, err error) {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, false, err
+	}
+	if _, isArray := value.([]interface{}); !isArray {
+		if _, isObject := value.(map[string]interface{}); !isObject {
+			return nil, false, fmt.Errorf("must be an array or object")
+		}
+	}
+
+	for _, entry := range rawMatchers(raw) {
+		var m interface{}
+		if err := json.Unmarshal(entry, &m); err != nil {
+			return nil, false, err
+		}
+		switch {
+		case isAICTMatcher(m):
+			changed = true
+		case stripAICTCommands(m):
+			changed = true
+			b, err := json.Marshal(m)
+			if err != nil {
+				return nil, false, err
+			}
+			kept = append(kept, b)
+		default:
+			kept = append(kept, entry)
+		}
+	}
+	return kept, changed, nil
+}
+
+// writeAICTSettings は設定ファイルにaictのhookを書き込みます。
+// ファイルがない場合はテンプレートを書き、ある場合は mergeAICTSettings で既存の設定と統合します。
+func writeAICTSettings(settingsPath string) error {
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(settingsPath), err)
+	}
+
+	data, err := os.ReadFile(settingsPath)
+	if os.IsNotExist(err) {
+		return os.WriteFile(settingsPath, []byte(templates.ClaudeSettingsJSON), 0644)
+	}
+	if err != nil {
+		return err
+	}
+
+	merged, err := mergeAICTSettings(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", settingsPath, err)
+	}
+	return os.WriteFile(settingsPath, merged, 0644)
+}
+
+// excludeFromGit はリポジトリ内の個人設定ファイルがコミットされないよう、
+// gitの無視設定に含まれていなければ .git/info/exclude に追加します。
+func excludeFromGit(repoRoot, gitDir, path string) error {
+	rel, err := filepath.Rel(repoRoot, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil // リポジトリ外のファイル
+	}
+	rel = filepath.ToSlash(rel)
+
+	if _, err := newExecutor().Run("check-ignore", "-q", "--", rel); err == nil {
+		return nil // 既に無視されている
+	}
+
+	excludePath := filepath.Join(gitDir, "info", "exclude")
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return err
+	}
+	existing, _ := os.ReadFile(excludePath)
+	entry := "/" + rel + "\n"
+	if len(existing) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+		entry = "\n" + entry
+	}
+	f, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(entry)
+	return err
+}
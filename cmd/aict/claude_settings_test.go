@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestResolveClaudeSettingsPath(t *testing.T) {
+	repoRoot := filepath.FromSlash("/repo")
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"", filepath.Join(repoRoot, ".claude", "settings.json")},
+		{"project", filepath.Join(repoRoot, ".claude", "settings.json")},
+		{"local", filepath.Join(repoRoot, ".claude", "settings.local.json")},
+		{"config/claude.json", filepath.Join(repoRoot, "config", "claude.json")},
+		{filepath.FromSlash("/etc/claude/settings.json"), filepath.FromSlash("/etc/claude/settings.json")},
+	}
+	for _, tt := range tests {
+		if got := resolveClaudeSettingsPath(repoRoot, tt.target); got != tt.want {
+			t.Errorf("resolveClaudeSettingsPath(%q) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestMergeAICTSettings(t *testing.T) {
+	userMatcher := map[string]interface{}{
+		"matcher": "Bash",
+		"hooks":   []interface{}{map[string]interface{}{"type": "command", "command": "./lint.sh", "timeout": 30.0}},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		check func(t *testing.T, settings map[string]interface{})
+	}{
+		{
+			name:  "empty file",
+			input: "",
+			check: func(t *testing.T, settings map[string]interface{}) {
+				assertAICTEntries(t, settings, "PreToolUse", 1, 1)
+				assertAICTEntries(t, settings, "PostToolUse", 1, 1)
+			},
+		},
+		{
+			name:  "keeps unrelated keys and events",
+			input: `{"permissions": {"allow": ["Bash(go test:*)"]}, "hooks": {"Stop": [{"hooks": [{"type": "command", "command": "notify-send done"}]}]}}`,
+			check: func(t *testing.T, settings map[string]interface{}) {
+				want := map[string]interface{}{"allow": []interface{}{"Bash(go test:*)"}}
+				if !reflect.DeepEqual(settings["permissions"], want) {
+					t.Errorf("permissions = %v, want %v", settings["permissions"], want)
+				}
+				// ユーザーのStop hookは残し、aictのStop hookを追加する
+				assertAICTEntries(t, settings, "Stop", 2, 1)
+				assertAICTEntries(t, settings, "PreToolUse", 1, 1)
+			},
+		},
+		{
+			name:  "object format event is converted to array with the user matcher first",
+			input: `{"hooks": {"PreToolUse": {"matcher": "Bash", "hooks": [{"type": "command", "command": "./lint.sh", "timeout": 30}]}}}`,
+			check: func(t *testing.T, settings map[string]interface{}) {
+				assertAICTEntries(t, settings, "PreToolUse", 2, 1)
+				pre := settings["hooks"].(map[string]interface{})["PreToolUse"].([]interface{})
+				if !reflect.DeepEqual(pre[0], userMatcher) {
+					t.Errorf("user matcher = %v, want %v", pre[0], userMatcher)
+				}
+			},
+		},
+		{
+			name: "replaces stale and duplicated aict entries",
+			input: `{"hooks": {"PreToolUse": [
+				{"matcher": "Write", "hooks": [{"type": "command", "command": "\"$CLAUDE_PROJECT_DIR/.git/aict/hooks/pre-tool-use.sh\""}]},
+				{"matcher": "Write", "hooks": [{"type": "command", "command": "aict hook pre-tool-use"}]}
+			]}}`,
+			check: func(t *testing.T, settings map[string]interface{}) {
+				assertAICTEntries(t, settings, "PreToolUse", 1, 1)
+			},
+		},
+		{
+			name:  "strips aict command from a mixed matcher",
+			input: `{"hooks": {"PostToolUse": [{"matcher": "Bash", "hooks": [{"type": "command", "command": "./lint.sh", "timeout": 30}, {"type": "command", "command": "aict hook post-tool-use"}]}]}}`,
+			check: func(t *testing.T, settings map[string]interface{}) {
+				assertAICTEntries(t, settings, "PostToolUse", 2, 1)
+				post := settings["hooks"].(map[string]interface{})["PostToolUse"].([]interface{})
+				if !reflect.DeepEqual(post[0], userMatcher) {
+					t.Errorf("user matcher = %v, want %v", post[0], userMatcher)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := mergeAICTSettings([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("mergeAICTSettings() error = %v", err)
+			}
+			var settings map[string]interface{}
+			if err := json.Unmarshal(out, &settings); err != nil {
+				t.Fatalf("merged settings is not valid JSON: %v\n%s", err, out)
+			}
+			tt.check(t, settings)
+
+			// 2回目の統合で内容が変わらない（冪等）
+			again, err := mergeAICTSettings(out)
+			if err != nil || string(again) != string(out) {
+				t.Errorf("merge is not idempotent:\n%s\n---\n%s", out, again)
+			}
+		})
+	}
+}
+
+func TestMergeAICTSettings_Invalid(t *testing.T) {
+	for _, input := range []string{`{"hooks": `, `{"hooks": []}`, `{"hooks": {"PreToolUse": "aict"}}`} {
+		if _, err := mergeAICTSettings([]byte(input)); err == nil {
+			t.Errorf("mergeAICTSettings(%s) should fail", input)
+		}
+	}
+}
+
+func TestHandleSetupHooks_LocalSettings(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+	os.Args = []string{"aict", "setup-hooks", "--settings", "local"}
+
+	if err := handleSetupHooksV2(); err != nil {
+		t.Fatalf("handleSetupHooksV2() error = %v", err)
+	}
+
+	localPath := filepath.Join(tmpDir, ".claude", "settings.local.json")
+	if !hasAICTHooks(localPath) {
+		t.Fatal("settings.local.json should contain aict hooks")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".claude", "settings.json")); !os.IsNotExist(err) {
+		t.Error("settings.json should not be created with --settings local")
+	}
+
+	exclude, _ := os.ReadFile(filepath.Join(tmpDir, ".git", "info", "exclude"))
+	if !strings.Contains(string(exclude), "/.claude/settings.local.json") {
+		t.Errorf(".git/info/exclude should ignore settings.local.json, got %q", exclude)
+	}
+
+	// hooks status は settings.local.json のみの設置を正常とみなす
+	if n := countHookProblems(inspectHooks(tmpDir, filepath.Join(tmpDir, ".git"))); n != 0 {
+		t.Errorf("inspectHooks() found %d problem(s) for a local-only install", n)
+	}
+
+	// uninstall は settings.local.json からも除去する
+	os.Args = []string{"aict", "uninstall"}
+	if err := handleUninstall(); err != nil {
+		t.Fatalf("handleUninstall() error = %v", err)
+	}
+	if _, err := os.Stat(localPath); !os.IsNotExist(err) {
+		t.Error("settings.local.json with only aict hooks should be removed")
+	}
+}
+
+// assertAICTEntries はイベントのmatcher数とそのうちaictのmatcher数を検証します。
+func assertAICTEntries(t *testing.T, settings map[string]interface{}, event string, wantTotal, wantAICT int) {
+	t.Helper()
+	hooks, _ := settings["hooks"].(map[string]interface{})
+	matchers := hookMatchers(hooks[event])
+	if len(matchers) != wantTotal {
+		t.Errorf("%s: %d matchers, want %d", event, len(matchers), wantTotal)
+	}
+	if got := aictMatchers(hooks[event]); len(got) != wantAICT {
+		t.Errorf("%s: %d aict matchers, want %d", event, len(got), wantAICT)
+	} else if wantAICT == 1 && !reflect.DeepEqual(got, templateSettingsHooks()[event]) {
+		t.Errorf("%s: aict matcher = %v, want template", event, got)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// defaultCommitPatterns は aict init で設定する既定のコミットパターン
+var defaultCommitPatterns = []tracker.CommitPattern{
+	{
+		// Aider は作成者名に " (aider)" を付与し、Co-authored-by トレーラーにモデル名を記録する
+		Name:           "Aider",
+		AuthorPattern:  `\(aider\)$`,
+		MessagePattern: `(?im)^Co-authored-by: aider \((?P<model>[^)]+)\)`,
+	},
+}
+
+// matchCommitPattern はコミットの作成者名・メッセージがいずれかのコミットパターンに一致するか判定し、
+// 一致した場合はそのコミットの変更を表すAIチェックポイントを返します（一致しなければ nil）。
+func matchCommitPattern(patterns []tracker.CommitPattern, authorName, message string) (*tracker.CheckpointV2, error) {
+	for _, p := range patterns {
+		var matchedBy string
+		metadata := map[string]string{}
+
+		if p.AuthorPattern != "" {
+			re, err := regexp.Compile(p.AuthorPattern)
+			if err != nil {
+				return nil, fmt.Errorf("commit pattern %s: invalid author_pattern: %w", p.Name, err)
+			}
+			if re.MatchString(authorName) {
+				matchedBy = "author"
+			}
+		}
+		if p.MessagePattern != "" {
+			re, err := regexp.Compile(p.MessagePattern)
+			if err != nil {
+				return nil, fmt.Errorf("commit pattern %s: invalid message_pattern: %w", p.Name, err)
+			}
+			if m := re.FindStringSubmatch(message); m != nil {
+				if matchedBy == "" {
+					matchedBy = "message"
+				}
+				if i := re.SubexpIndex("model"); i > 0 && m[i] != "" {
+					metadata["model"] = strings.TrimSpace(m[i])
+				}
+			}
+		}
+
+		if matchedBy != "" {
+			metadata["message"] = fmt.Sprintf("Detected by commit pattern %q (%s)", p.Name, matchedBy)
+			return &tracker.CheckpointV2{
+				Timestamp: time.Now(),
+				Author:    p.Name,
+				Type:      tracker.AuthorTypeAI,
+				Metadata:  metadata,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// applyCommitPatterns はコミットがコミットパターンに一致する場合、変更された全ファイルの
+// 作成者をパターンのAIチェックポイントに置き換えた作成者マッピングを返します。
+// 一致しない場合は authorMap をそのまま返します（authorMap 自体は変更しません）。
+func applyCommitPatterns(authorMap map[string]*tracker.CheckpointV2, changedFiles map[string]bool, commitHash string, cfg *tracker.Config) map[string]*tracker.CheckpointV2 {
+	if len(cfg.CommitPatterns) == 0 {
+		return authorMap
+	}
+
+	out, err := newExecutor().Run("log", "-1", "--format=%an%x00%B", commitHash)
+	if err != nil {
+		debugf("commit patterns skipped: %v", err)
+		return authorMap
+	}
+	authorName, message, _ := strings.Cut(out, "\x00")
+
+	cp, err := matchCommitPattern(cfg.CommitPatterns, authorName, message)
+	if err != nil {
+		debugf("commit patterns skipped: %v", err)
+		return authorMap
+	}
+	if cp == nil {
+		return authorMap
+	}
+
+	debugf("commit %s matched commit pattern %s", commitHash, cp.Author)
+	overridden := make(map[string]*tracker.CheckpointV2, len(changedFiles))
+	for f := range changedFiles {
+		overridden[f] = cp
+	}
+	return overridden
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestMatchCommitPattern(t *testing.T) {
+	tests := []struct {
+		name      string
+		author    string
+		message   string
+		wantMatch bool
+		wantModel string
+	}{
+		{"aider author suffix", "Jane Doe (aider)", "feat: add parser", true, ""},
+		{"aider co-authored-by trailer", "Jane Doe", "feat: add parser\n\nCo-authored-by: aider (anthropic/claude-sonnet) <noreply@aider.chat>", true, "anthropic/claude-sonnet"},
+		{"both", "Jane Doe (aider)", "fix\n\nco-authored-by: aider (gpt-4o) <noreply@aider.chat>", true, "gpt-4o"},
+		{"plain human commit", "Jane Doe", "feat: mention aider in text", false, ""},
+		{"aider in the middle of the name", "aider fan", "docs", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp, err := matchCommitPattern(defaultCommitPatterns, tt.author, tt.message)
+			if err != nil {
+				t.Fatalf("matchCommitPattern() error = %v", err)
+			}
+			if (cp != nil) != tt.wantMatch {
+				t.Fatalf("matchCommitPattern() = %v, wantMatch %v", cp, tt.wantMatch)
+			}
+			if cp == nil {
+				return
+			}
+			if cp.Author != "Aider" || cp.Type != tracker.AuthorTypeAI {
+				t.Errorf("checkpoint = %s (%s), want Aider (ai)", cp.Author, cp.Type)
+			}
+			if cp.Metadata["model"] != tt.wantModel {
+				t.Errorf("model = %q, want %q", cp.Metadata["model"], tt.wantModel)
+			}
+		})
+	}
+}
+
+func TestMatchCommitPattern_InvalidRegex(t *testing.T) {
+	patterns := []tracker.CommitPattern{{Name: "bad", AuthorPattern: "("}}
+	if _, err := matchCommitPattern(patterns, "x", ""); err == nil {
+		t.Error("expected error for invalid author_pattern")
+	}
+}
+
+func TestHandleCommit_AiderCommitPattern(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	cfg.CommitPatterns = defaultCommitPatterns
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	// フックなしでAiderがコミット（チェックポイントなし）
+	testutil.CreateTestFile(t, tmpDir, "parser.go", "package main\n\nfunc parse() {}\n")
+	for _, args := range [][]string{
+		{"add", "."},
+		{"commit", "--author", "Test User (aider) <test@example.com>", "-m", "feat: add parser\n\nCo-authored-by: aider (gpt-4o) <noreply@aider.chat>"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	nm := gitnotes.NewNotesManagerWithExecutor(newExecutor())
+	commit, _ := newExecutor().Run("rev-parse", "HEAD")
+	alog, err := nm.GetAuthorshipLog(commit)
+	if err != nil || alog == nil {
+		t.Fatalf("GetAuthorshipLog() = %v, %v", alog, err)
+	}
+	author := alog.Files["parser.go"].Authors[0]
+	if author.Name != "Aider" || author.Type != tracker.AuthorTypeAI || author.Metadata["model"] != "gpt-4o" {
+		t.Errorf("author = %+v, want Aider (ai, model gpt-4o)", author)
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// config set の更新方法
+const (
+	configSetReplace = "set"    // 値を置き換える
+	configSetAdd     = "add"    // リストに追加する（既にある値は追加しない）
+	configSetRemove  = "remove" // リストから削除する
+)
+
+// configToMap は設定をJSONのキーで辿れるマップに変換します（数値は json.Number のまま保持）。
+func configToMap(cfg *tracker.Config) (map[string]interface{}, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var m map[string]interface{}
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// getConfigValue はドット区切りのキーの値を返します。未設定の場合は ok=false を返します。
+func getConfigValue(cfg *tracker.Config, key string) (value interface{}, ok bool, err error) {
+	if _, err := storage.ConfigFieldType(key); err != nil {
+		return nil, false, err
+	}
+	m, err := configToMap(cfg)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var cur interface{} = m
+	for _, part := range strings.Split(key, ".") {
+		obj, isObj := cur.(map[string]interface{})
+		if !isObj {
+			return nil, false, nil
+		}
+		if cur, ok = obj[part]; !ok || cur == nil {
+			return nil, false, nil
+		}
+	}
+	return cur, true, nil
+}
+
+// formatConfigValue は値を表示用に整形します。
+// スカラーはそのまま、文字列のリストは1行1要素、それ以外はインデント付きJSONで出力します。
+func formatConfigValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []interface{}:
+		lines := make([]string, 0, len(v))
+		for _, item := range v {
+			s, isString := item.(string)
+			if !isString {
+				return formatConfigJSON(v)
+			}
+			lines = append(lines, s)
+		}
+		return strings.Join(lines, "\n"), nil
+	}
+	return formatConfigJSON(v)
+}
+
+func formatConfigJSON(v interface{}) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	return string(data), err
+}
+
+// setConfigValue はキーに値を設定した新しい設定を検証して返します（cfg は変更しません）。
+// mode が add / remove の場合は文字列のリスト（tracked_extensions 等）に要素を追加・削除します。
+func setConfigValue(cfg *tracker.Config, key string, args []string, mode string) (*tracker.Config, error) {
+	typ, err := storage.ConfigFieldType(key)
+	if err != nil {
+		return nil, err
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	isStringList := typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.String
+
+	current, _, err := getConfigValue(cfg, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch {
+	case mode != configSetReplace:
+		if !isStringList {
+			return nil, fmt.Errorf("--%s is only supported for list keys such as tracked_extensions and exclude_patterns", mode)
+		}
+		if len(args) == 0 {
+			return nil, fmt.Errorf("usage: aict config set --%s <key> <value>...", mode)
+		}
+		value = updateStringList(current, args, mode)
+	case isStringList:
+		list := make([]interface{}, len(args))
+		for i, a := range args {
+			list[i] = a
+		}
+		value = list
+	default:
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s takes exactly one value, got %d", key, len(args))
+		}
+		if value, err = parseConfigScalar(args[0], typ); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	m, err := configToMap(cfg)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(key, ".")
+	obj := m
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := obj[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			obj[part] = next
+		}
+		obj = next
+	}
+	obj[parts[len(parts)-1]] = value
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ParseConfigStrict(data, false)
+}
+
+// updateStringList は現在のリストに要素を追加（重複なし）または削除したリストを返します。
+func updateStringList(current interface{}, args []string, mode string) []interface{} {
+	items, _ := current.([]interface{})
+	remove := make(map[string]bool, len(args))
+	for _, a := range args {
+		remove[a] = true
+	}
+
+	result := []interface{}{}
+	seen := make(map[string]bool)
+	for _, item := range items {
+		s, _ := item.(string)
+		if mode == configSetRemove && remove[s] {
+			continue
+		}
+		seen[s] = true
+		result = append(result, item)
+	}
+	if mode == configSetAdd {
+		for _, a := range args {
+			if !seen[a] {
+				seen[a] = true
+				result = append(result, a)
+			}
+		}
+	}
+	return result
+}
+
+// parseConfigScalar は文字列をキーの型の値に変換します。
+// 構造体・マップ・文字列以外のリストはJSONで指定します（例: '{"paths": ["web"]}'）。
+func parseConfigScalar(s string, typ reflect.Type) (interface{}, error) {
+	switch typ.Kind() {
+	case reflect.String:
+		return s, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false, got %q", s)
+		}
+		return b, nil
+	case reflect.Int, reflect.Int64, reflect.Float64:
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", s)
+		}
+		return json.Number(s), nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, fmt.Errorf("expected a JSON value for this key: %w", err)
+	}
+	return v, nil
+}
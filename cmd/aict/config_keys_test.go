@@ -0,0 +1,131 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestGetConfigValue(t *testing.T) {
+	cfg := &tracker.Config{
+		TargetAIPercentage: 80,
+		TrackedExtensions:  []string{".go", ".ts"},
+		DefaultAuthor:      "dev",
+		Badge:              &tracker.BadgeConfig{Label: "AI"},
+	}
+
+	tests := []struct {
+		key    string
+		want   string
+		wantOK bool
+	}{
+		{"target_ai_percentage", "80", true},
+		{"tracked_extensions", ".go\n.ts", true},
+		{"badge.label", "AI", true},
+		{"badge", "{\n  \"label\": \"AI\"\n}", true},
+		{"contexts.web", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			v, ok, err := getConfigValue(cfg, tt.key)
+			if err != nil {
+				t.Fatalf("getConfigValue() error = %v", err)
+			}
+			if ok != tt.wantOK {
+				t.Fatalf("getConfigValue() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			got, err := formatConfigValue(v)
+			if err != nil {
+				t.Fatalf("formatConfigValue() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("formatConfigValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, _, err := getConfigValue(cfg, "trackd_extensions"); err == nil || !strings.Contains(err.Error(), `did you mean "tracked_extensions"`) {
+		t.Errorf("getConfigValue() error = %v, want suggestion", err)
+	}
+}
+
+func TestSetConfigValue(t *testing.T) {
+	base := func() *tracker.Config {
+		return &tracker.Config{
+			TargetAIPercentage: 80,
+			TrackedExtensions:  []string{".go", ".ts"},
+			ExcludePatterns:    []string{"*_test.go", "vendor/*"},
+			DefaultAuthor:      "dev",
+		}
+	}
+
+	tests := []struct {
+		name  string
+		key   string
+		args  []string
+		mode  string
+		check func(*tracker.Config) bool
+	}{
+		{"number", "target_ai_percentage", []string{"65.5"}, configSetReplace,
+			func(c *tracker.Config) bool { return c.TargetAIPercentage == 65.5 }},
+		{"replace list", "tracked_extensions", []string{".rs", ".py"}, configSetReplace,
+			func(c *tracker.Config) bool { return reflect.DeepEqual(c.TrackedExtensions, []string{".rs", ".py"}) }},
+		{"add to list", "tracked_extensions", []string{".go", ".rs"}, configSetAdd,
+			func(c *tracker.Config) bool {
+				return reflect.DeepEqual(c.TrackedExtensions, []string{".go", ".ts", ".rs"})
+			}},
+		{"remove from list", "exclude_patterns", []string{"*_test.go"}, configSetRemove,
+			func(c *tracker.Config) bool { return reflect.DeepEqual(c.ExcludePatterns, []string{"vendor/*"}) }},
+		{"nested string creates object", "badge.label", []string{"AI share"}, configSetReplace,
+			func(c *tracker.Config) bool { return c.Badge != nil && c.Badge.Label == "AI share" }},
+		{"object as JSON", "contexts.web", []string{`{"paths": ["web"], "target_ai_percentage": 90}`}, configSetReplace,
+			func(c *tracker.Config) bool { return c.Contexts["web"].TargetAIPercentage == 90 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base()
+			got, err := setConfigValue(cfg, tt.key, tt.args, tt.mode)
+			if err != nil {
+				t.Fatalf("setConfigValue() error = %v", err)
+			}
+			if !tt.check(got) {
+				t.Errorf("setConfigValue() = %+v", got)
+			}
+			if !reflect.DeepEqual(cfg, base()) {
+				t.Error("setConfigValue() must not modify the original config")
+			}
+		})
+	}
+}
+
+func TestSetConfigValue_Errors(t *testing.T) {
+	cfg := &tracker.Config{TargetAIPercentage: 80, TrackedExtensions: []string{".go"}, DefaultAuthor: "dev"}
+
+	tests := []struct {
+		name    string
+		key     string
+		args    []string
+		mode    string
+		wantErr string
+	}{
+		{"unknown key", "exclude_pattern", []string{"x"}, configSetReplace, `did you mean "exclude_patterns"`},
+		{"not a number", "target_ai_percentage", []string{"high"}, configSetReplace, "expected a number"},
+		{"out of range", "target_ai_percentage", []string{"150"}, configSetReplace, "target_ai_percentage"},
+		{"too many values", "default_author", []string{"a", "b"}, configSetReplace, "exactly one value"},
+		{"add to scalar", "default_author", []string{"a"}, configSetAdd, "only supported for list keys"},
+		{"empty list", "tracked_extensions", []string{".go"}, configSetRemove, "tracked_extensions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := setConfigValue(cfg, tt.key, tt.args, tt.mode)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("setConfigValue() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
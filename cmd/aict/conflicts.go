@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/git"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// metadataConflictResolution はコンフリクト解消の行であることを示すAuthorship Logのメタデータキー
+const metadataConflictResolution = "conflict_resolution"
+
+// hasConflictMarkers はファイル内容にマージのコンフリクトマーカー（<<<<<<<・=======・>>>>>>>）が
+// 揃っているかを判定します。
+func hasConflictMarkers(content []byte) bool {
+	if !bytes.Contains(content, []byte("<<<<<<<")) {
+		return false
+	}
+	var start, sep bool
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		switch {
+		case isConflictMarker(line, "<<<<<<<"):
+			start = true
+		case start && bytes.Equal(line, []byte("=======")):
+			sep = true
+		case sep && isConflictMarker(line, ">>>>>>>"):
+			return true
+		}
+	}
+	return false
+}
+
+// isConflictMarker は行がマーカーのみ、またはマーカーと空白に続くラベル（ブランチ名等）かを判定します。
+func isConflictMarker(line []byte, marker string) bool {
+	if !bytes.HasPrefix(line, []byte(marker)) {
+		return false
+	}
+	rest := line[len(marker):]
+	return len(rest) == 0 || rest[0] == ' '
+}
+
+// applyConflictResolutions はマージコミットでコンフリクトを解消したファイルの作成者を分割します。
+// チェックポイントの変更がコンフリクト解消（変更前にマーカーあり）の場合、すべての親と異なる行だけを
+// そのチェックポイントの作成者に conflict_resolution 付きで帰属させ、他の親から取り込んだ行は
+// 既定の作成者（人間）に帰属させます（取り込んだ行は元のブランチのコミットで記録済みのため）。
+func applyConflictResolutions(executor gitexec.Executor, log *tracker.AuthorshipLog, authorMap map[string]*tracker.CheckpointV2, cfg *tracker.Config) {
+	var resolvedFiles []string
+	for fpath, cp := range authorMap {
+		if _, recorded := log.Files[fpath]; recorded && cp.Changes[fpath].ConflictResolution {
+			resolvedFiles = append(resolvedFiles, fpath)
+		}
+	}
+	if len(resolvedFiles) == 0 || !git.IsMergeCommit(executor, log.Commit) {
+		return
+	}
+
+	combined, err := git.GetCombinedLines(executor, log.Commit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to attribute conflict resolutions: %v\n", err)
+		return
+	}
+	for _, fpath := range resolvedFiles {
+		cp := authorMap[fpath]
+		lines := combined[fpath]
+		debugf("Conflict resolution in %s by %s: resolved=%v merged=%v", fpath, cp.Author, lines.Resolved, lines.Merged)
+
+		var authors []tracker.AuthorInfo
+		if len(lines.Resolved) > 0 {
+			metadata := make(map[string]string, len(cp.Metadata)+1)
+			for k, v := range cp.Metadata {
+				metadata[k] = v
+			}
+			metadata[metadataConflictResolution] = "true"
+			authors = append(authors, tracker.AuthorInfo{Name: cp.Author, Type: cp.Type, Lines: lines.Resolved, Metadata: metadata})
+		}
+		if len(lines.Merged) > 0 {
+			authors = append(authors, tracker.AuthorInfo{
+				Name:     cfg.DefaultAuthor,
+				Type:     tracker.AuthorTypeHuman,
+				Lines:    lines.Merged,
+				Metadata: map[string]string{"message": "Merged from another parent (not part of the conflict resolution)"},
+			})
+		}
+		if len(authors) == 0 {
+			// 解消結果がいずれかの親と同じ（片方の変更を採用しただけ）
+			delete(log.Files, fpath)
+			continue
+		}
+		log.Files[fpath] = tracker.FileInfo{Authors: authors}
+	}
+}
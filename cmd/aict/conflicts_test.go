@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestHasConflictMarkers(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"conflict", "a\n<<<<<<< HEAD\nb\n=======\nc\n>>>>>>> side\nd\n", true},
+		{"crlf", "<<<<<<< HEAD\r\nb\r\n=======\r\nc\r\n>>>>>>> side\r\n", true},
+		{"diff3 style", "<<<<<<< HEAD\nb\n||||||| base\na\n=======\nc\n>>>>>>>\n", true},
+		{"no markers", "package main\n", false},
+		{"start only", "<<<<<<< HEAD\nb\n", false},
+		{"markers out of order", ">>>>>>> side\n=======\n<<<<<<< HEAD\n", false},
+		{"marker inside a line", "s := \"<<<<<<< HEAD\"\n=======\n>>>>>>> side\n", false},
+		{"longer run is not a marker", "<<<<<<<< x\n=======\n>>>>>>> side\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasConflictMarkers([]byte(tt.content)); got != tt.want {
+				t.Errorf("hasConflictMarkers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipeline_ConflictResolution(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil && args[0] != "merge" {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "f.go", "a\nb\nc\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	git("checkout", "-q", "-b", "side")
+	testutil.CreateTestFile(t, tmpDir, "f.go", "a\nB-side\nc\nside2\n")
+	testutil.GitCommit(t, tmpDir, "side")
+	git("checkout", "-q", "-")
+	testutil.CreateTestFile(t, tmpDir, "f.go", "a\nB-main\nc\n")
+	testutil.GitCommit(t, tmpDir, "main")
+	git("merge", "side") // コンフリクトで失敗する
+
+	// pre-tool-use 相当: コンフリクトマーカーを含む状態を記録
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+	// post-tool-use 相当: AIがコンフリクトを解消
+	testutil.CreateTestFile(t, tmpDir, "f.go", "a\nB-resolved\nc\nside2\n")
+	if err := recordCheckpoint("Claude", "sonnet", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+
+	store, _, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	checkpoints, err := store.LoadCheckpoints()
+	if err != nil {
+		t.Fatalf("LoadCheckpoints() error = %v", err)
+	}
+	if !checkpoints[len(checkpoints)-2].Snapshot["f.go"].Conflicted {
+		t.Errorf("baseline snapshot should be marked as conflicted")
+	}
+	if !checkpoints[len(checkpoints)-1].Changes["f.go"].ConflictResolution {
+		t.Errorf("AI change should be marked as a conflict resolution")
+	}
+
+	git("commit", "-q", "-am", "Merge side")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	commit, _ := getLatestCommitHash()
+	log, err := gitnotes.NewNotesManager().GetAuthorshipLog(commit)
+	if err != nil || log == nil {
+		t.Fatalf("GetAuthorshipLog() = %v, %v", log, err)
+	}
+	authors := log.Files["f.go"].Authors
+	if len(authors) != 2 {
+		t.Fatalf("authors = %+v, want the resolver and the merged lines", authors)
+	}
+	ai := authors[0]
+	if ai.Name != "Claude" || ai.Type != tracker.AuthorTypeAI || !reflect.DeepEqual(ai.Lines, [][]int{{2}}) {
+		t.Errorf("resolution author = %+v, want Claude (ai) on line 2", ai)
+	}
+	if ai.Metadata[metadataConflictResolution] != "true" || ai.Metadata["model"] != "sonnet" {
+		t.Errorf("resolution metadata = %v", ai.Metadata)
+	}
+	if merged := authors[1]; merged.Type != tracker.AuthorTypeHuman || !reflect.DeepEqual(merged.Lines, [][]int{{4}}) {
+		t.Errorf("merged author = %+v, want human on line 4", merged)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// unassignedContext はどのコンテキストにも属さないファイルの集計名
+const unassignedContext = "(unassigned)"
+
+// lookupContext は設定から名前付きコンテキストを取得します。
+func lookupContext(cfg *tracker.Config, name string) (tracker.ContextConfig, error) {
+	if cfg != nil {
+		if ctx, ok := cfg.Contexts[name]; ok {
+			return ctx, nil
+		}
+	}
+
+	var names []string
+	if cfg != nil {
+		for n := range cfg.Contexts {
+			names = append(names, n)
+		}
+	}
+	if len(names) == 0 {
+		return tracker.ContextConfig{}, fmt.Errorf("unknown context: %s (no contexts defined in config; add \"contexts\" with 'aict config')", name)
+	}
+	sort.Strings(names)
+	return tracker.ContextConfig{}, fmt.Errorf("unknown context: %s (available: %s)", name, strings.Join(names, ", "))
+}
+
+// contextTarget はコンテキストの目標AI%を返します（未設定時は全体の目標値）。
+func contextTarget(ctx tracker.ContextConfig, cfg *tracker.Config) float64 {
+	if ctx.TargetAIPercentage > 0 || cfg == nil {
+		return ctx.TargetAIPercentage
+	}
+	return cfg.TargetAIPercentage
+}
+
+// normalizeContextPath は "./frontend/" のような指定を "frontend" に正規化します。
+func normalizeContextPath(p string) string {
+	p = path.Clean(strings.TrimPrefix(strings.ReplaceAll(p, "\\", "/"), "./"))
+	if p == "." {
+		return ""
+	}
+	return p
+}
+
+// contextPathMatch はファイルがコンテキストのパス配下にある場合、一致したパスの長さを返します（不一致は -1）。
+// パス "" （リポジトリルート）はすべてのファイルに長さ0で一致します。
+func contextPathMatch(filePath string, paths []string) int {
+	best := -1
+	for _, p := range paths {
+		p = normalizeContextPath(p)
+		if p == "" || filePath == p || strings.HasPrefix(filePath, p+"/") {
+			if len(p) > best {
+				best = len(p)
+			}
+		}
+	}
+	return best
+}
+
+// contextPathMatcher はコンテキストのパス配下のファイルかを判定する関数を返します。
+func contextPathMatcher(paths []string) func(string) bool {
+	return func(filePath string) bool {
+		return contextPathMatch(filePath, paths) >= 0
+	}
+}
+
+// assignContext は変更ファイルのパスからコンテキストを推定します。
+// 複数のコンテキストに一致する場合は最も長いパスで一致したもの（同じ長さなら名前順で先のもの）を選びます。
+func assignContext(filePath string, contexts map[string]tracker.ContextConfig) string {
+	assigned, bestLen := unassignedContext, -1
+	for name, ctx := range contexts {
+		n := contextPathMatch(filePath, ctx.Paths)
+		if n > bestLen || (n == bestLen && n >= 0 && name < assigned) {
+			assigned, bestLen = name, n
+		}
+	}
+	return assigned
+}
+
+// buildContextStats はファイル別統計をコンテキストごとに集約します。
+// 設定済みのコンテキストは変更がなくても名前順に表示し、未割り当てのファイルがあれば末尾に追加します。
+func buildContextStats(byFile map[string]*tracker.FileStats, cfg *tracker.Config) []tracker.ContextStats {
+	grouped := make(map[string]*tracker.ContextStats, len(cfg.Contexts)+1)
+	names := make([]string, 0, len(cfg.Contexts))
+	for name, ctx := range cfg.Contexts {
+		grouped[name] = &tracker.ContextStats{Name: name, TargetAIPercentage: contextTarget(ctx, cfg)}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for filePath, s := range byFile {
+		name := assignContext(filePath, cfg.Contexts)
+		c, exists := grouped[name]
+		if !exists {
+			c = &tracker.ContextStats{Name: name}
+			grouped[name] = c
+		}
+		c.AILines += s.AILines
+		c.HumanLines += s.HumanLines
+		c.TotalLines += s.TotalLines
+	}
+	if _, exists := grouped[unassignedContext]; exists {
+		names = append(names, unassignedContext)
+	}
+
+	stats := make([]tracker.ContextStats, 0, len(names))
+	for _, name := range names {
+		c := grouped[name]
+		c.Percentage = metrics.SafePercent(c.AILines, c.TotalLines)
+		stats = append(stats, *c)
+	}
+	return stats
+}
+
+// printContextStats はコンテキスト別の内訳と目標達成状況を出力します。
+func printContextStats(stats []tracker.ContextStats) {
+	fmt.Println("By Context:")
+	fmt.Printf("  %-20s %8s %8s %7s %8s\n", "Context", "Lines", "AI", "AI%", "Target")
+	for _, c := range stats {
+		if c.Name == unassignedContext {
+			fmt.Printf("  %-20s %8d %8d %6.1f%%\n", c.Name, c.TotalLines, c.AILines, c.Percentage)
+			continue
+		}
+		mark := "✗"
+		if c.Percentage >= c.TargetAIPercentage {
+			mark = "✓"
+		}
+		fmt.Printf("  %-20s %8d %8d %6.1f%% %7.1f%% %s\n", c.Name, c.TotalLines, c.AILines, c.Percentage, c.TargetAIPercentage, mark)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestAssignContext(t *testing.T) {
+	contexts := map[string]tracker.ContextConfig{
+		"frontend": {Paths: []string{"./frontend/"}},
+		"backend":  {Paths: []string{"backend", "shared"}},
+		"admin":    {Paths: []string{"frontend/admin"}},
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"frontend/src/app.ts", "frontend"},
+		{"frontend/admin/page.ts", "admin"}, // より長いパスが優先
+		{"backend/main.go", "backend"},
+		{"shared/util.go", "backend"},
+		{"frontend-legacy/app.js", unassignedContext},
+		{"main.go", unassignedContext},
+	}
+
+	for _, tt := range tests {
+		if got := assignContext(tt.path, contexts); got != tt.want {
+			t.Errorf("assignContext(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestBuildContextStats(t *testing.T) {
+	cfg := &tracker.Config{
+		TargetAIPercentage: 80,
+		Contexts: map[string]tracker.ContextConfig{
+			"frontend": {Paths: []string{"frontend"}, TargetAIPercentage: 60},
+			"backend":  {Paths: []string{"backend"}},
+			"docs":     {Paths: []string{"docs"}},
+		},
+	}
+	byFile := map[string]*tracker.FileStats{
+		"frontend/app.ts": {Path: "frontend/app.ts", TotalLines: 10, AILines: 8, HumanLines: 2},
+		"backend/main.go": {Path: "backend/main.go", TotalLines: 20, AILines: 5, HumanLines: 15},
+		"main.go":         {Path: "main.go", TotalLines: 4, AILines: 0, HumanLines: 4},
+	}
+
+	got := buildContextStats(byFile, cfg)
+	want := []tracker.ContextStats{
+		{Name: "backend", TotalLines: 20, AILines: 5, HumanLines: 15, Percentage: 25, TargetAIPercentage: 80},
+		{Name: "docs", TargetAIPercentage: 80},
+		{Name: "frontend", TotalLines: 10, AILines: 8, HumanLines: 2, Percentage: 80, TargetAIPercentage: 60},
+		{Name: unassignedContext, TotalLines: 4, AILines: 0, HumanLines: 4, Percentage: 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildContextStats() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestLookupContext_Unknown(t *testing.T) {
+	cfg := &tracker.Config{Contexts: map[string]tracker.ContextConfig{
+		"frontend": {Paths: []string{"frontend"}},
+		"backend":  {Paths: []string{"backend"}},
+	}}
+
+	_, err := lookupContext(cfg, "mobile")
+	if err == nil || !strings.Contains(err.Error(), "available: backend, frontend") {
+		t.Errorf("lookupContext() error = %v, want list of available contexts", err)
+	}
+	if _, err := lookupContext(&tracker.Config{}, "mobile"); err == nil {
+		t.Error("lookupContext() should fail when no contexts are defined")
+	}
+}
+
+func TestGenerateRangeReport_Context(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		t.Fatalf("NewAIctStorage() error = %v", err)
+	}
+	cfg, err := store.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	cfg.Contexts = map[string]tracker.ContextConfig{
+		"frontend": {Paths: []string{"frontend"}, TargetAIPercentage: 50},
+		"backend":  {Paths: []string{"backend"}},
+	}
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "README.go", "package main\n")
+	base := testutil.GitCommit(t, tmpDir, "Base commit")
+
+	os.MkdirAll("frontend", 0755)
+	os.MkdirAll("backend", 0755)
+	testutil.CreateTestFile(t, tmpDir, "frontend/app.go", "package frontend\n\nfunc App() {}\n")
+	testutil.CreateTestFile(t, tmpDir, "backend/main.go", "package backend\n")
+	testutil.GitCommit(t, tmpDir, "Add frontend and backend")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	report, _, err := generateRangeReport(&ReportOptions{Range: base + "..HEAD", Context: "frontend"})
+	if err != nil {
+		t.Fatalf("generateRangeReport() error = %v", err)
+	}
+	if report.Context != "frontend" || report.TargetAIPercentage != 50 {
+		t.Errorf("context = %q target = %.1f, want frontend/50", report.Context, report.TargetAIPercentage)
+	}
+	if report.Summary.TotalLines != 3 {
+		t.Errorf("TotalLines = %d, want 3 (frontend only)", report.Summary.TotalLines)
+	}
+	if len(report.ByContext) != 0 {
+		t.Errorf("ByContext should be empty with --context, got %+v", report.ByContext)
+	}
+
+	report, _, err = generateRangeReport(&ReportOptions{Range: base + "..HEAD"})
+	if err != nil {
+		t.Fatalf("generateRangeReport() error = %v", err)
+	}
+	if report.Summary.TotalLines != 4 {
+		t.Errorf("TotalLines = %d, want 4", report.Summary.TotalLines)
+	}
+	if len(report.ByContext) != 2 || report.ByContext[0].Name != "backend" || report.ByContext[0].TotalLines != 1 ||
+		report.ByContext[1].Name != "frontend" || report.ByContext[1].TotalLines != 3 {
+		t.Errorf("ByContext = %+v", report.ByContext)
+	}
+
+	if _, _, err := generateRangeReport(&ReportOptions{Range: base + "..HEAD", Context: "mobile"}); err == nil {
+		t.Error("expected error for unknown context")
+	}
+}
+
+func TestGenerateRangeReport_DocsCategory(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		t.Fatalf("NewAIctStorage() error = %v", err)
+	}
+	cfg, err := store.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	cfg.TrackedExtensions = append(cfg.TrackedExtensions, ".rst", ".md")
+	cfg.Docs = &tracker.DocsConfig{TargetAIPercentage: 30}
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	base := testutil.GitCommit(t, tmpDir, "Base commit")
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc main() {}\n")
+	testutil.CreateTestFile(t, tmpDir, "docs/index.rst", "Title\n=====\n")
+	// docs の .md はコードブロックのみでなく本文も数える
+	testutil.CreateTestFile(t, tmpDir, "README.md", "# Readme\n\nProse.\n")
+	testutil.GitCommit(t, tmpDir, "Add code and docs")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	report, _, err := generateRangeReport(&ReportOptions{Range: base + "..HEAD"})
+	if err != nil {
+		t.Fatalf("generateRangeReport() error = %v", err)
+	}
+	if report.Code == nil || report.Docs == nil {
+		t.Fatalf("Code/Docs should be set when docs is configured, got %+v", report)
+	}
+	if report.Code.Summary.TotalLines != 3 || report.Docs.Summary.TotalLines != 5 {
+		t.Errorf("code lines = %d, docs lines = %d, want 3 and 5", report.Code.Summary.TotalLines, report.Docs.Summary.TotalLines)
+	}
+	if report.Summary.TotalLines != 8 {
+		t.Errorf("Summary.TotalLines = %d, want 8 (code + docs)", report.Summary.TotalLines)
+	}
+	if report.Code.TargetAIPercentage != cfg.TargetAIPercentage || report.Docs.TargetAIPercentage != 30 {
+		t.Errorf("targets = %.1f / %.1f, want %.1f / 30", report.Code.TargetAIPercentage, report.Docs.TargetAIPercentage, cfg.TargetAIPercentage)
+	}
+	if err := formatRangeReport(report, "table", nil); err != nil {
+		t.Errorf("formatRangeReport() error = %v", err)
+	}
+}
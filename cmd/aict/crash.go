@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+)
+
+// crashExitCode はパニックで終了した場合の終了コード（通常のエラーの 1 と区別する）。
+// Claude Code は hook の終了コード 2 をツール呼び出しのブロックとして扱うため 2 は使わない
+const crashExitCode = 3
+
+// handleCrash は main で捕捉したパニックのクラッシュレポートを書き出し、その場所を1行で表示して終了します。
+// レポートにはスタックトレース・バージョン・コマンドのみを含め、ファイル内容や引数の値は含めません。
+// hook コマンドは Claude Code・git の操作を妨げないよう、レポートの場所を hook.log に記録して正常終了します（handleHook と同じ）。
+func handleCrash(command string, recovered interface{}, stack []byte) {
+	now := time.Now()
+	report := buildCrashReport(command, os.Args[1:], recovered, stack, now)
+
+	var path string
+	var err error
+	store, serr := storage.NewAIctStorage()
+	if serr == nil {
+		path, err = store.SaveCrashReport([]byte(report), now)
+	} else {
+		// リポジトリ外ではユーザーのキャッシュディレクトリ（取得できない場合は一時ディレクトリ）に保存
+		dir, cerr := os.UserCacheDir()
+		if cerr != nil {
+			dir = os.TempDir()
+		}
+		path, err = storage.SaveCrashReportIn(filepath.Join(dir, "aict", storage.CrashDirName), []byte(report), now)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "aict crashed (%s); failed to write crash report: %v\n%s", redactCrashText(fmt.Sprint(recovered)), err, redactCrashText(string(stack)))
+	} else {
+		fmt.Fprintf(os.Stderr, "aict crashed: %s. Crash report: %s (please attach it to a bug report)\n", redactCrashText(fmt.Sprint(recovered)), path)
+	}
+
+	if command == "hook" {
+		if store != nil {
+			_ = store.AppendHookLog(fmt.Sprintf("crashed: %s (crash report: %s)", redactCrashText(fmt.Sprint(recovered)), path))
+		}
+		exitFunc(0)
+		return
+	}
+	exitFunc(crashExitCode)
+}
+
+// buildCrashReport はクラッシュレポートの本文を作成します。
+func buildCrashReport(command string, args []string, recovered interface{}, stack []byte, now time.Time) string {
+	var b strings.Builder
+	b.WriteString("aict crash report\n")
+	fmt.Fprintf(&b, "Time:    %s\n", now.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Version: %s\n", version)
+	fmt.Fprintf(&b, "Go:      %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "Command: aict %s\n", strings.Join(redactArgs(command, args), " "))
+	fmt.Fprintf(&b, "Panic:   %s\n", redactCrashText(fmt.Sprint(recovered)))
+	b.WriteString("\n")
+	b.WriteString(redactCrashText(string(stack)))
+	return b.String()
+}
+
+// redactArgs はコマンド名とフラグ名だけを残し、フラグの値や位置引数（パス・作成者名・範囲など）を伏せます。
+func redactArgs(command string, args []string) []string {
+	out := make([]string, 0, len(args))
+	for i, a := range args {
+		switch {
+		case i == 0 && a == command:
+			out = append(out, a)
+		case strings.HasPrefix(a, "-"):
+			if name, _, ok := strings.Cut(a, "="); ok {
+				out = append(out, name+"=<redacted>")
+			} else {
+				out = append(out, a)
+			}
+		default:
+			out = append(out, "<redacted>")
+		}
+	}
+	return out
+}
+
+// redactCrashText はパニックのメッセージとスタックトレースに含まれるリポジトリ・ホームディレクトリのパスを置き換えます。
+func redactCrashText(s string) string {
+	if root := storage.FindWorkTree(); root != "" {
+		s = strings.ReplaceAll(s, root, "<repo>")
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" && home != "/" {
+		s = strings.ReplaceAll(s, home, "~")
+	}
+	return s
+}
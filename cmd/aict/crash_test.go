@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestRedactArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    []string
+	}{
+		{"command only", "report", []string{"report"}, []string{"report"}},
+		{"flag values", "report", []string{"report", "--since", "7d", "--format=json"}, []string{"report", "--since", "<redacted>", "--format=<redacted>"}},
+		{"positional paths", "why", []string{"why", "secret/main.go:12"}, []string{"why", "<redacted>"}},
+		{"boolean flag", "sync", []string{"sync", "push", "--dry-run"}, []string{"sync", "<redacted>", "--dry-run"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactArgs(tt.command, tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("redactArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleCrash(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	origArgs := os.Args
+	origExit := exitFunc
+	origStderr := os.Stderr
+	defer func() {
+		os.Chdir(origDir)
+		os.Args = origArgs
+		exitFunc = origExit
+		os.Stderr = origStderr
+	}()
+	os.Chdir(tmpDir)
+
+	exitCode := -1
+	exitFunc = func(code int) { exitCode = code }
+	os.Args = []string{"aict", "report", "--since", "secret-branch"}
+
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+	root := storage.FindWorkTree()
+	handleCrash("report", "index out of range", []byte("goroutine 1 [running]:\nmain.handleReport()\n\t"+root+"/cmd/aict/handlers.go:10\n"))
+	w.Close()
+	os.Stderr = origStderr
+	buf := make([]byte, 4096)
+	n, _ := r.Read(buf)
+	stderr := string(buf[:n])
+
+	if exitCode != crashExitCode {
+		t.Errorf("exit code = %d, want %d", exitCode, crashExitCode)
+	}
+	if strings.Count(strings.TrimSpace(stderr), "\n") != 0 || !strings.Contains(stderr, "Crash report:") {
+		t.Errorf("stderr should be a one-line pointer, got:\n%s", stderr)
+	}
+
+	reports, _ := filepath.Glob(filepath.Join(tmpDir, ".git", "aict", storage.CrashDirName, "crash-*.txt"))
+	if len(reports) != 1 {
+		t.Fatalf("found %d crash reports, want 1", len(reports))
+	}
+	data, err := os.ReadFile(reports[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(data)
+	for _, want := range []string{"Version: " + version, "Command: aict report --since <redacted>", "Panic:   index out of range", "<repo>/cmd/aict/handlers.go:10"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "secret-branch") || strings.Contains(report, root) {
+		t.Errorf("report leaks arguments or repository path:\n%s", report)
+	}
+}
+
+// TestHandleCrash_Hook は hook コマンドのパニックでも正常終了し、hook.log にレポートの場所を残すことを検証する
+// （Claude Code は終了コード 2 をツール呼び出しのブロックとして扱う）
+func TestHandleCrash_Hook(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	origArgs := os.Args
+	origExit := exitFunc
+	origStderr := os.Stderr
+	defer func() {
+		os.Chdir(origDir)
+		os.Args = origArgs
+		exitFunc = origExit
+		os.Stderr = origStderr
+	}()
+	os.Chdir(tmpDir)
+
+	exitCode := -1
+	exitFunc = func(code int) { exitCode = code }
+	os.Args = []string{"aict", "hook", "pre-tool-use"}
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+	handleCrash("hook", "nil map", []byte("goroutine 1 [running]:\n"))
+	w.Close()
+	os.Stderr = origStderr
+
+	if exitCode != 0 {
+		t.Errorf("exit code = %d, want 0 for hook", exitCode)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".git", "aict", storage.HookLogFileName))
+	if err != nil || !strings.Contains(string(data), "crashed: nil map (crash report: ") {
+		t.Errorf("hook.log = %q (%v), want a crash entry", data, err)
+	}
+}
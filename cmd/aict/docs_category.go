@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// buildCategoryStats はコード・ドキュメント（設定の docs）それぞれの集計を行います。
+// includePath（--context）を指定した場合は、その範囲内でさらにコードとドキュメントに分けます。
+// 同じ範囲を再集計しますが、git呼び出しは CachingExecutor で共有されるため追加のプロセス起動はありません。
+func buildCategoryStats(opts *ReportOptions, sampleRate float64, includePath func(string) bool, cfg *tracker.Config, codeTarget float64) (code, docs *tracker.CategoryStats, err error) {
+	collect := func(wantDocs bool, target float64) (*tracker.CategoryStats, error) {
+		include := func(filePath string) bool {
+			if includePath != nil && !includePath(filePath) {
+				return false
+			}
+			return tracker.IsDocFile(filePath, cfg) == wantDocs
+		}
+		result, commitCount, err := collectAuthorStats(opts.Range, sampleRate, include)
+		if err != nil {
+			return nil, err
+		}
+		if result.sampledCommits > 0 {
+			extrapolateSample(result, commitCount)
+		}
+		total := result.totalAI + result.totalHuman
+		return &tracker.CategoryStats{
+			Summary: tracker.SummaryStats{
+				TotalLines:   total,
+				AILines:      result.totalAI,
+				HumanLines:   result.totalHuman,
+				AIPercentage: metrics.SafePercent(result.totalAI, total),
+			},
+			Metrics:            result.detailedMetrics,
+			TargetAIPercentage: target,
+		}, nil
+	}
+
+	if code, err = collect(false, codeTarget); err != nil {
+		return nil, nil, err
+	}
+	if docs, err = collect(true, cfg.GetDocsTarget()); err != nil {
+		return nil, nil, err
+	}
+	return code, docs, nil
+}
+
+// printCategoryStats はコード・ドキュメントの集計を目標達成状況付きで出力します。
+func printCategoryStats(title string, c *tracker.CategoryStats) {
+	mark := "✗"
+	if c.Summary.AIPercentage >= c.TargetAIPercentage {
+		mark = "✓"
+	}
+	fmt.Printf("■ %s: %d行, AI %.1f%% (target %.1f%%) %s\n",
+		title, c.Summary.TotalLines, c.Summary.AIPercentage, c.TargetAIPercentage, mark)
+	fmt.Println()
+	printDetailedMetrics(&c.Metrics)
+}
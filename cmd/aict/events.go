@@ -0,0 +1,80 @@
+package main
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/authorship"
+	"github.com/y-hirakaw/ai-code-tracker/internal/events"
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// newEvent はリポジトリ名（ディレクトリ名）・ブランチ・時刻を埋めたイベントを返します。
+func newEvent(name string) events.Event {
+	executor := newExecutor()
+	repoRoot, _ := executor.Run("rev-parse", "--show-toplevel")
+	branch, _ := executor.Run("rev-parse", "--abbrev-ref", "HEAD")
+	return events.Event{
+		Event:     name,
+		Repo:      filepath.Base(repoRoot),
+		Branch:    branch,
+		Timestamp: time.Now(),
+	}
+}
+
+// publishCheckpointEvent はチェックポイントの概要（作成者タイプ・行数）を通知します。
+func publishCheckpointEvent(cfg *tracker.EventsConfig, cp *tracker.CheckpointV2) error {
+	ev := newEvent(events.EventCheckpoint)
+	ev.AuthorType = string(cp.Type)
+	ev.Files = len(cp.Changes)
+	ev.Timestamp = cp.Timestamp
+	for _, change := range cp.Changes {
+		ev.LinesAdded += change.Added
+		ev.LinesDeleted += change.Deleted
+	}
+	return events.Notify(cfg, ev)
+}
+
+// publishCommitEvent はコミットのAuthorship Logの概要（AI/人間の行数）を通知します。
+func publishCommitEvent(cfg *tracker.EventsConfig, alog *tracker.AuthorshipLog, numstatMap map[string][2]int) error {
+	ev := newEvent(events.EventCommit)
+	ev.Commit = alog.Commit
+	ev.Files = len(alog.Files)
+	for path, info := range alog.Files {
+		ev.LinesAdded += numstatMap[path][0]
+		ev.LinesDeleted += numstatMap[path][1]
+		for i := range info.Authors {
+			ai, human := splitByAIShare(authorship.CountLines(info.Authors[i].Lines), info.Authors[i].AIShare())
+			ev.AILines += ai
+			ev.HumanLines += human
+		}
+	}
+	ev.AIPercentage = metrics.SafePercent(ev.AILines, ev.AILines+ev.HumanLines)
+	return events.Notify(cfg, ev)
+}
+
+// currentWeeklyAIPercentage は日次集計から aict status と同じ直近 statusWindowDays 日間のAI%を返します。
+// 日次集計を読めない場合・期間に追加行がない場合は nil です。
+func currentWeeklyAIPercentage(store *storage.AIctStorage, now time.Time) *float64 {
+	rollups, err := store.LoadDailyRollups()
+	if err != nil {
+		return nil
+	}
+	current, _ := weeklyAIPercentages(rollups, now)
+	return current
+}
+
+// publishMilestoneEvent はコミットの記録で直近 statusWindowDays 日間のAI%が目標を下回る状態（または未集計）から
+// 目標以上になった場合に milestone イベントを通知します。到達していなければ何もしません。
+func publishMilestoneEvent(cfg *tracker.EventsConfig, commit string, target float64, before, after *float64) error {
+	if target <= 0 || after == nil || *after < target || (before != nil && *before >= target) {
+		return nil
+	}
+	ev := newEvent(events.EventMilestone)
+	ev.Commit = commit
+	ev.AIPercentage = *after
+	ev.TargetAIPercentage = target
+	return events.Notify(cfg, ev)
+}
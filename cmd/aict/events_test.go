@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/events"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestHandleCommit_PublishesCommitEvent(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	received := make(chan events.Event, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev events.Event
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		received <- ev
+	}))
+	defer srv.Close()
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	cfg.Events = &tracker.EventsConfig{Webhooks: []tracker.WebhookConfig{{URL: srv.URL, Events: []string{events.EventCommit}}}}
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc main() {}\n")
+	if err := recordCheckpoint("Claude Code", "", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+	commit := testutil.GitCommit(t, tmpDir, "Add main")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	// checkpoint イベントは購読していないため commit イベントのみ届く
+	if len(received) != 1 {
+		t.Fatalf("received %d event(s), want 1", len(received))
+	}
+	ev := <-received
+	if ev.Event != events.EventCommit || ev.Repo != filepath.Base(tmpDir) || ev.Files != 1 ||
+		ev.AILines != 3 || ev.HumanLines != 0 || ev.AIPercentage != 100 || ev.LinesAdded != 3 {
+		t.Errorf("event = %+v", ev)
+	}
+	if len(ev.Commit) < len(commit) || ev.Commit[:len(commit)] != commit {
+		t.Errorf("event commit = %q, want prefix %q", ev.Commit, commit)
+	}
+}
+
+func TestHandleCommit_PublishesMilestoneEvent(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	received := make(chan events.Event, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev events.Event
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		received <- ev
+	}))
+	defer srv.Close()
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	cfg.Events = &tracker.EventsConfig{Webhooks: []tracker.WebhookConfig{{URL: srv.URL, Events: []string{events.EventMilestone}}}}
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+
+	// 1回目のAIのコミットで直近7日間のAI%が目標（80%）に達する
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc main() {}\n")
+	if err := recordCheckpoint("Claude Code", "", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+	testutil.GitCommit(t, tmpDir, "Add main")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("received %d event(s), want 1 milestone", len(received))
+	}
+	ev := <-received
+	if ev.Event != events.EventMilestone || ev.AIPercentage != 100 || ev.TargetAIPercentage != cfg.TargetAIPercentage || ev.Commit == "" {
+		t.Errorf("event = %+v", ev)
+	}
+
+	// 既に目標以上のため、2回目のコミットでは送信しない
+	testutil.CreateTestFile(t, tmpDir, "util.go", "package main\n\nfunc util() {}\n")
+	if err := recordCheckpoint("Claude Code", "", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+	testutil.GitCommit(t, tmpDir, "Add util")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+	if len(received) != 0 {
+		t.Errorf("received %d event(s) after the target was already reached, want 0", len(received))
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// fieldsFlagUsage は --fields フラグの共通の説明
+const fieldsFlagUsage = "Comma-separated JSON fields to output (dotted paths, e.g. 'summary.total_lines,summary.ai_percentage')"
+
+// fieldNode は --fields で指定したパスの木です（子がなければその値全体を出力）。
+type fieldNode struct {
+	name     string
+	children []*fieldNode
+}
+
+// parseFieldPaths は "a.b,c" を順序を保ったパスの木に変換します。
+// 親のパス（"summary"）と子のパス（"summary.total_lines"）を両方指定した場合は親全体を出力します。
+func parseFieldPaths(fields string) ([]*fieldNode, error) {
+	var roots []*fieldNode
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		nodes := &roots
+		parts := strings.Split(field, ".")
+		for i, part := range parts {
+			if part == "" {
+				return nil, fmt.Errorf("invalid field %q", field)
+			}
+			var node *fieldNode
+			for _, n := range *nodes {
+				if n.name == part {
+					node = n
+				}
+			}
+			if node == nil {
+				node = &fieldNode{name: part}
+				*nodes = append(*nodes, node)
+			} else if len(node.children) == 0 {
+				break // 親のパスが指定済み
+			}
+			if i == len(parts)-1 {
+				node.children = nil
+				break
+			}
+			nodes = &node.children
+		}
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("--fields is empty")
+	}
+	return roots, nil
+}
+
+// marshalJSONFields は v をインデント付きJSONにし、fields が指定されていればそのフィールドのみに絞り込みます。
+func marshalJSONFields(v interface{}, fields string) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil || fields == "" {
+		return data, err
+	}
+	return projectJSON(data, fields)
+}
+
+// projectJSON はJSONを fields のパスのみに絞り込みます。配列は要素ごとに絞り込みます。
+// 出力のキーは fields で指定した順です。
+func projectJSON(data []byte, fields string) ([]byte, error) {
+	nodes, err := parseFieldPaths(fields)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := decodeOrderedJSON(dec)
+	if err != nil {
+		return nil, err
+	}
+	projected, err := projectValue(v, nodes, "")
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(projected, "", "  ")
+}
+
+func projectValue(v interface{}, nodes []*fieldNode, path string) (interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			p, err := projectValue(elem, nodes, path)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = p
+		}
+		return out, nil
+	case orderedObject:
+		out := make(orderedObject, 0, len(nodes))
+		for _, n := range nodes {
+			fieldPath := n.name
+			if path != "" {
+				fieldPath = path + "." + n.name
+			}
+			child, ok := v.get(n.name)
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q (available: %s)", fieldPath, strings.Join(v.sortedKeys(), ", "))
+			}
+			if len(n.children) > 0 && child != nil {
+				p, err := projectValue(child, n.children, fieldPath)
+				if err != nil {
+					return nil, err
+				}
+				child = p
+			}
+			out = append(out, objectMember{n.name, child})
+		}
+		return out, nil
+	case nil:
+		return nil, nil // 省略可能なオブジェクトが null の場合はそのまま
+	default:
+		return nil, fmt.Errorf("field %q is not an object", path)
+	}
+}
+
+type objectMember struct {
+	key   string
+	value interface{}
+}
+
+// orderedObject はキーの順序を保ったJSONオブジェクトです（元の構造体のフィールド順で出力するため）。
+type orderedObject []objectMember
+
+func (o orderedObject) get(key string) (interface{}, bool) {
+	for _, m := range o {
+		if m.key == key {
+			return m.value, true
+		}
+	}
+	return nil, false
+}
+
+func (o orderedObject) sortedKeys() []string {
+	keys := make([]string, 0, len(o))
+	for _, m := range o {
+		keys = append(keys, m.key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// decodeOrderedJSON はオブジェクトを orderedObject、配列を []interface{} として読みます。
+func decodeOrderedJSON(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := orderedObject{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrderedJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, objectMember{key.(string), value})
+		}
+		_, err := dec.Token() // '}'
+		return obj, err
+	case json.Delim('['):
+		arr := []interface{}{}
+		for dec.More() {
+			value, err := decodeOrderedJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err := dec.Token() // ']'
+		return arr, err
+	}
+	return tok, nil
+}
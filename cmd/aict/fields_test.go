@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestProjectJSON(t *testing.T) {
+	input := `{"range":"a..b","summary":{"total_lines":10,"ai_lines":8,"ai_percentage":80},` +
+		`"by_author":[{"name":"Claude","lines":8,"type":"ai"},{"name":"Alice","lines":2,"type":"human"}],"sample":null}`
+
+	tests := []struct {
+		name    string
+		fields  string
+		want    string
+		wantErr string
+	}{
+		{"nested fields in requested order", "summary.ai_percentage, summary.total_lines", `{"summary":{"ai_percentage":80,"total_lines":10}}`, ""},
+		{"array elements", "by_author.name,by_author.lines", `{"by_author":[{"name":"Claude","lines":8},{"name":"Alice","lines":2}]}`, ""},
+		{"parent wins over child", "summary.ai_lines,summary,range", `{"summary":{"total_lines":10,"ai_lines":8,"ai_percentage":80},"range":"a..b"}`, ""},
+		{"null object is kept", "sample.rate", `{"sample":null}`, ""},
+		{"unknown field", "summary.total", "", `unknown field "summary.total" (available: ai_lines, ai_percentage, total_lines)`},
+		{"path into scalar", "range.start", "", `field "range" is not an object`},
+		{"empty", " , ", "", "--fields is empty"},
+		{"empty path segment", "summary..total_lines", "", "invalid field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := projectJSON([]byte(input), tt.fields)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("projectJSON() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("projectJSON() error = %v", err)
+			}
+			var compact bytes.Buffer
+			json.Compact(&compact, got)
+			if compact.String() != tt.want {
+				t.Errorf("projectJSON() = %s, want %s", compact.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldsRequiresJSON(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	tests := []struct {
+		name string
+		args []string
+		run  func() error
+	}{
+		{"report", []string{"aict", "report", "--since", "7d", "--fields", "summary"}, handleRangeReport},
+		{"backstage-metadata", []string{"aict", "backstage-metadata", "--fields", "ai_percentage"}, handleBackstageMetadata},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			if err := tt.run(); err == nil || !strings.Contains(err.Error(), "--fields requires --format json") {
+				t.Errorf("error = %v, want --fields requires --format json", err)
+			}
+		})
+	}
+}
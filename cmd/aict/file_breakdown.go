@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+const (
+	// fileSortLines は追加行数の多い順に並べる
+	fileSortLines = "lines"
+	// fileSortAI はAI%の高い順に並べる
+	fileSortAI = "ai"
+)
+
+// addFileStats はファイル単位の追加行数を集計結果に加算します。
+// byFile が nil の場合（日次集計用の一時集計など）は何もしません。
+func addFileStats(result *authorStatsResult, filePath string, aiLines, humanLines int) {
+	if result.byFile == nil {
+		return
+	}
+	stats, exists := result.byFile[filePath]
+	if !exists {
+		stats = &tracker.FileStats{Path: filePath}
+		result.byFile[filePath] = stats
+	}
+	stats.AILines += aiLines
+	stats.HumanLines += humanLines
+	stats.TotalLines += aiLines + humanLines
+}
+
+// buildFileStats はファイル別統計をAI%を付与して並べ替えたスライスにします。
+func buildFileStats(byFile map[string]*tracker.FileStats, sortKey string) []tracker.FileStats {
+	stats := make([]tracker.FileStats, 0, len(byFile))
+	for _, s := range byFile {
+		stats = append(stats, *s)
+	}
+	return finishFileStats(stats, sortKey)
+}
+
+// buildDirStats はファイル別統計を depth 階層のディレクトリ単位に集約します。
+// リポジトリ直下のファイルは "." に集約されます。
+func buildDirStats(byFile map[string]*tracker.FileStats, depth int, sortKey string) []tracker.FileStats {
+	grouped := make(map[string]*tracker.FileStats)
+	for filePath, s := range byFile {
+		dir := dirAtDepth(filePath, depth)
+		d, exists := grouped[dir]
+		if !exists {
+			d = &tracker.FileStats{Path: dir}
+			grouped[dir] = d
+		}
+		d.AILines += s.AILines
+		d.HumanLines += s.HumanLines
+		d.TotalLines += s.TotalLines
+	}
+	return buildFileStats(grouped, sortKey)
+}
+
+// dirAtDepth はファイルパスの親ディレクトリを先頭から depth 階層までに切り詰めます。
+// 例: dirAtDepth("internal/git/diff.go", 1) = "internal"
+func dirAtDepth(filePath string, depth int) string {
+	dir := path.Dir(filePath)
+	if dir == "." {
+		return dir
+	}
+	parts := strings.Split(dir, "/")
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return strings.Join(parts, "/")
+}
+
+// finishFileStats はAI%を計算し、sortKey に従って並べ替えます（同値はパス順）。
+func finishFileStats(stats []tracker.FileStats, sortKey string) []tracker.FileStats {
+	for i := range stats {
+		stats[i].Percentage = metrics.SafePercent(stats[i].AILines, stats[i].TotalLines)
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if sortKey == fileSortAI && a.Percentage != b.Percentage {
+			return a.Percentage > b.Percentage
+		}
+		if a.TotalLines != b.TotalLines {
+			return a.TotalLines > b.TotalLines
+		}
+		return a.Path < b.Path
+	})
+	return stats
+}
+
+// printFileStats はファイル・ディレクトリ別の内訳をテーブル形式で出力します。
+func printFileStats(title, column string, stats []tracker.FileStats) {
+	fmt.Println(title)
+	fmt.Printf("  %-40s %8s %8s %8s %7s\n", column, "Lines", "AI", "Human", "AI%")
+	for _, s := range stats {
+		fmt.Printf("  %-40s %8d %8d %8d %6.1f%%\n", s.Path, s.TotalLines, s.AILines, s.HumanLines, s.Percentage)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestDirAtDepth(t *testing.T) {
+	tests := []struct {
+		path  string
+		depth int
+		want  string
+	}{
+		{"main.go", 1, "."},
+		{"internal/git/diff.go", 1, "internal"},
+		{"internal/git/diff.go", 2, "internal/git"},
+		{"internal/git/diff.go", 5, "internal/git"},
+		{"cmd/aict/main.go", 1, "cmd"},
+	}
+
+	for _, tt := range tests {
+		if got := dirAtDepth(tt.path, tt.depth); got != tt.want {
+			t.Errorf("dirAtDepth(%q, %d) = %q, want %q", tt.path, tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestBuildDirStats(t *testing.T) {
+	byFile := map[string]*tracker.FileStats{
+		"main.go":              {Path: "main.go", TotalLines: 10, AILines: 0, HumanLines: 10},
+		"internal/git/diff.go": {Path: "internal/git/diff.go", TotalLines: 40, AILines: 30, HumanLines: 10},
+		"internal/api/api.go":  {Path: "internal/api/api.go", TotalLines: 20, AILines: 20, HumanLines: 0},
+		"cmd/aict/main.go":     {Path: "cmd/aict/main.go", TotalLines: 5, AILines: 5, HumanLines: 0},
+	}
+
+	tests := []struct {
+		name    string
+		depth   int
+		sortKey string
+		want    []string
+	}{
+		{"depth 1 by lines", 1, fileSortLines, []string{"internal", ".", "cmd"}},
+		{"depth 1 by ai", 1, fileSortAI, []string{"cmd", "internal", "."}},
+		{"depth 2 by lines", 2, fileSortLines, []string{"internal/git", "internal/api", ".", "cmd/aict"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := buildDirStats(byFile, tt.depth, tt.sortKey)
+			var got []string
+			for _, s := range stats {
+				got = append(got, s.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	stats := buildDirStats(byFile, 1, fileSortLines)
+	want := tracker.FileStats{Path: "internal", TotalLines: 60, AILines: 50, HumanLines: 10, Percentage: metrics.SafePercent(50, 60)}
+	if stats[0] != want {
+		t.Errorf("internal = %+v, want %+v", stats[0], want)
+	}
+}
+
+func TestProcessCommitFiles_ByFile(t *testing.T) {
+	result := &authorStatsResult{
+		byAuthor: make(map[string]*tracker.AuthorStats),
+		byFile:   make(map[string]*tracker.FileStats),
+	}
+	alog := &tracker.AuthorshipLog{
+		Files: map[string]tracker.FileInfo{
+			"a.go": {Authors: []tracker.AuthorInfo{
+				{Name: "Claude Code", Type: tracker.AuthorTypeAI, Lines: [][]int{{1, 6}}},
+				{Name: "dev", Type: tracker.AuthorTypeHuman, Lines: [][]int{{7, 8}}},
+			}},
+			"b.go": {Authors: []tracker.AuthorInfo{
+				{Name: "dev", Type: tracker.AuthorTypeHuman, Lines: [][]int{{1, 3}}},
+			}},
+		},
+	}
+	processCommitFiles(result, alog, map[string][2]int{"a.go": {8, 0}, "b.go": {3, 1}})
+
+	if got := *result.byFile["a.go"]; got != (tracker.FileStats{Path: "a.go", TotalLines: 8, AILines: 6, HumanLines: 2}) {
+		t.Errorf("a.go = %+v", got)
+	}
+	if got := *result.byFile["b.go"]; got != (tracker.FileStats{Path: "b.go", TotalLines: 3, AILines: 0, HumanLines: 3}) {
+		t.Errorf("b.go = %+v", got)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/generated"
+)
+
+// excludeGeneratedFiles はコミットの変更ファイルから生成ファイル（生成コード・minified・ロックファイル）を取り除き、
+// 取り除いたパスを返します。include が false を返すファイルは判定しません。
+// ロックファイルはファイル名で、それ以外はコミット時点の内容で判定します（git cat-file --batch の1プロセス）。
+func excludeGeneratedFiles(numstatMap map[string][2]int, commit string, include func(string) bool) []string {
+	var removed, candidates []string
+	for path := range numstatMap {
+		if include != nil && !include(path) {
+			continue
+		}
+		if generated.IsLockfile(path) {
+			removed = append(removed, path)
+			continue
+		}
+		candidates = append(candidates, path)
+	}
+
+	if len(candidates) > 0 {
+		sort.Strings(candidates)
+		var stdin strings.Builder
+		for _, path := range candidates {
+			stdin.WriteString(commit + ":" + path + "\n")
+		}
+		output, err := newExecutor().RunWithStdin(stdin.String(), "cat-file", "--batch")
+		if err != nil {
+			debugf("cat-file --batch failed, generated files not detected: %v", err)
+		} else {
+			// 形式: "<sha> <type> <size>\n<content (size bytes)>\n"（削除されたファイルは "<object> missing\n"）
+			remaining := output
+			for _, path := range candidates {
+				headerEnd := strings.Index(remaining, "\n")
+				if headerEnd == -1 {
+					break
+				}
+				fields := strings.Fields(remaining[:headerEnd])
+				remaining = remaining[headerEnd+1:]
+				if len(fields) < 3 {
+					continue
+				}
+				size, err := strconv.Atoi(fields[2])
+				if err != nil || size < 0 || len(remaining) < size+1 {
+					break
+				}
+				content := remaining[:size]
+				remaining = remaining[size+1:]
+				if fields[1] != "blob" {
+					continue
+				}
+				if reason := generated.Detect(path, []byte(content)); reason != "" {
+					debugf("skipping generated file %s (%s)", path, reason)
+					removed = append(removed, path)
+				}
+			}
+		}
+	}
+
+	for _, path := range removed {
+		delete(numstatMap, path)
+	}
+	sort.Strings(removed)
+	return removed
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestGeneratedFiles_CheckpointAndCommit(t *testing.T) {
+	minified := "var a=" + strings.Repeat("1+", 2000) + "1;\n"
+
+	tests := []struct {
+		name             string
+		includeGenerated bool
+		wantRecorded     []string
+		wantSkipped      []string
+		wantTotalLines   int
+	}{
+		{
+			name:           "generated files are skipped by default",
+			wantRecorded:   []string{"main.go"},
+			wantSkipped:    []string{"api/user.pb.go", "web/app.js", "package-lock.json"},
+			wantTotalLines: 3,
+		},
+		{
+			name:             "include_generated records everything",
+			includeGenerated: true,
+			wantRecorded:     []string{"main.go", "api/user.pb.go", "web/app.js", "package-lock.json"},
+			// main.go 3行 + user.pb.go 3行 + app.js 1行 + package-lock.json 3行
+			wantTotalLines: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := testutil.TempGitRepo(t)
+			testutil.InitAICT(t, tmpDir)
+
+			originalDir, _ := os.Getwd()
+			defer os.Chdir(originalDir)
+			os.Chdir(tmpDir)
+
+			store, cfg, err := loadStorageAndConfig()
+			if err != nil {
+				t.Fatalf("loadStorageAndConfig() error = %v", err)
+			}
+			cfg.TrackedExtensions = append(cfg.TrackedExtensions, ".json")
+			cfg.IncludeGenerated = tt.includeGenerated
+			if err := store.SaveConfig(cfg); err != nil {
+				t.Fatalf("SaveConfig() error = %v", err)
+			}
+
+			testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+			base := testutil.GitCommit(t, tmpDir, "initial")
+			if err := recordCheckpoint("human", "", ""); err != nil {
+				t.Fatalf("baseline checkpoint error = %v", err)
+			}
+
+			testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc main() {}\n")
+			testutil.CreateTestFile(t, tmpDir, "api/user.pb.go", "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n")
+			testutil.CreateTestFile(t, tmpDir, "web/app.js", minified)
+			testutil.CreateTestFile(t, tmpDir, "package-lock.json", "{\n  \"lockfileVersion\": 3\n}\n")
+			if err := recordCheckpoint("Claude", "", ""); err != nil {
+				t.Fatalf("AI checkpoint error = %v", err)
+			}
+
+			checkpoints, _ := store.LoadCheckpoints()
+			changes := checkpoints[len(checkpoints)-1].Changes
+			for _, path := range tt.wantRecorded {
+				if _, ok := changes[path]; !ok {
+					t.Errorf("checkpoint should record %s, got %v", path, changes)
+				}
+			}
+			for _, path := range tt.wantSkipped {
+				if _, ok := changes[path]; ok {
+					t.Errorf("checkpoint should not record generated file %s", path)
+				}
+			}
+
+			testutil.GitCommit(t, tmpDir, "Add files")
+			if err := handleCommit(); err != nil {
+				t.Fatalf("handleCommit() error = %v", err)
+			}
+
+			report, _, err := generateRangeReport(&ReportOptions{Range: base + "..HEAD"})
+			if err != nil {
+				t.Fatalf("generateRangeReport() error = %v", err)
+			}
+			if report.Summary.TotalLines != tt.wantTotalLines {
+				t.Errorf("TotalLines = %d, want %d", report.Summary.TotalLines, tt.wantTotalLines)
+			}
+			if report.Summary.AIPercentage != 100 {
+				t.Errorf("AIPercentage = %.1f, want 100", report.Summary.AIPercentage)
+			}
+		})
+	}
+}
+
+func TestExcludeGeneratedFiles(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "gen.go", "// Code generated by stringer; DO NOT EDIT.\n\npackage main\n")
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.CreateTestFile(t, tmpDir, "go.sum", "example.com/x v1.0.0 h1:abc=\n")
+	testutil.CreateTestFile(t, tmpDir, "skip.go", "// Code generated. DO NOT EDIT.\n")
+	commit := testutil.GitCommit(t, tmpDir, "initial")
+
+	numstatMap := map[string][2]int{
+		"gen.go":     {3, 0},
+		"main.go":    {1, 0},
+		"go.sum":     {1, 0},
+		"skip.go":    {1, 0},
+		"deleted.go": {0, 5},
+	}
+	removed := excludeGeneratedFiles(numstatMap, commit, func(path string) bool { return path != "skip.go" })
+
+	if got := strings.Join(removed, ","); got != "gen.go,go.sum" {
+		t.Errorf("removed = %q, want %q", got, "gen.go,go.sum")
+	}
+	for _, path := range []string{"main.go", "skip.go", "deleted.go"} {
+		if _, ok := numstatMap[path]; !ok {
+			t.Errorf("%s should remain in numstat", path)
+		}
+	}
+}
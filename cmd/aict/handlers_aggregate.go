@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// handleAggregate handles the aggregate command
+// 複数リポジトリのレポートを同じ範囲で集計し、組織全体の合計とリポジトリ別の内訳を出力します。
+func handleAggregate() error {
+	fs := flag.NewFlagSet("aggregate", flag.ExitOnError)
+	repos := fs.String("repos", "", "Comma-separated repository paths")
+	reposFile := fs.String("repos-file", "", "File listing repository paths, one per line ('#' comments, relative to the file)")
+	since := fs.String("since", "", "Report commits since date in each repository (e.g., '30d', '2025-01-01')")
+	rangeSpec := fs.String("range", "", "Commit range evaluated in each repository (e.g., 'origin/main..HEAD')")
+	format := fs.String("format", "table", "Output format: table, json or csv")
+	fields := fs.String("fields", "", fieldsFlagUsage)
+	fs.Parse(os.Args[2:])
+
+	paths, err := aggregateRepoPaths(*repos, *reposFile)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		fmt.Println("Usage: aict aggregate --repos <path>,<path>... | --repos-file <file> --since <date>|--range <spec> [--format table|json|csv]")
+		return fmt.Errorf("--repos or --repos-file is required")
+	}
+	if (*since == "") == (*rangeSpec == "") {
+		return fmt.Errorf("specify either --since or --range")
+	}
+	if *rangeSpec != "" {
+		if err := gitexec.ValidateRevisionArg(*rangeSpec); err != nil {
+			return err
+		}
+	}
+	if *since != "" {
+		if warning := validateSinceInput(*since); warning != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+	}
+	switch *format {
+	case "table", "json", "csv":
+	default:
+		return fmt.Errorf("unknown format: %s (available: table, json, csv)", *format)
+	}
+	if *fields != "" && *format != "json" {
+		return fmt.Errorf("--fields requires --format json")
+	}
+
+	agg := &tracker.AggregateReport{Range: *rangeSpec}
+	if *since != "" {
+		agg.Range = "since " + *since
+	}
+	failed := 0
+	for _, path := range paths {
+		repo := aggregateRepo(path, *since, *rangeSpec)
+		if repo.Error != "" {
+			failed++
+		} else {
+			agg.Commits += repo.Commits
+			agg.Summary.AILines += repo.Summary.AILines
+			agg.Summary.HumanLines += repo.Summary.HumanLines
+		}
+		agg.Repos = append(agg.Repos, repo)
+	}
+	agg.Summary.TotalLines = agg.Summary.AILines + agg.Summary.HumanLines
+	agg.Summary.AIPercentage = metrics.SafePercent(agg.Summary.AILines, agg.Summary.TotalLines)
+
+	switch *format {
+	case "json":
+		data, err := marshalJSONFields(agg, *fields)
+		if err != nil {
+			return fmt.Errorf("formatting JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "csv":
+		if err := writeAggregateCSV(os.Stdout, agg); err != nil {
+			return err
+		}
+	default:
+		printAggregateReport(agg)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d repositories could not be reported", failed, len(paths))
+	}
+	return nil
+}
+
+// aggregateRepoPaths は --repos（カンマ区切り）と --repos-file（1行1パス）のリポジトリのパスを返します。
+// --repos-file の相対パスはファイルのあるディレクトリからの相対パスです。
+func aggregateRepoPaths(repos, reposFile string) ([]string, error) {
+	var paths []string
+	for _, p := range strings.Split(repos, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	if reposFile == "" {
+		return paths, nil
+	}
+
+	f, err := os.Open(reposFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading --repos-file: %w", err)
+	}
+	defer f.Close()
+	base := filepath.Dir(reposFile)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !filepath.IsAbs(line) {
+			line = filepath.Join(base, line)
+		}
+		paths = append(paths, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading --repos-file: %w", err)
+	}
+	return paths, nil
+}
+
+// aggregateRepo はリポジトリのディレクトリでレポートを集計します（設定・Authorship Logはリポジトリごと）。
+// 集計できない場合は RepoReport.Error に理由を入れて返します。
+func aggregateRepo(path, since, rangeSpec string) tracker.RepoReport {
+	repo := tracker.RepoReport{Path: path, Name: filepath.Base(path)}
+	if abs, err := filepath.Abs(path); err == nil {
+		repo.Name = filepath.Base(abs) // "." などの相対パスもディレクトリ名で表示
+	}
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		repo.Error = err.Error()
+		return repo
+	}
+	defer os.Chdir(originalDir)
+	if err := os.Chdir(path); err != nil {
+		repo.Error = err.Error()
+		return repo
+	}
+	if _, err := newExecutor().Run("rev-parse", "--git-dir"); err != nil {
+		repo.Error = "not a git repository"
+		return repo
+	}
+
+	opts := &ReportOptions{Range: rangeSpec, Since: since, Format: "json", Sort: fileSortLines, Depth: 1}
+	if since != "" {
+		opts.Range, err = convertSinceToRangeOn(since, "HEAD")
+		if errors.Is(err, errNoCommitsSince) {
+			return repo
+		}
+		if err != nil {
+			repo.Error = err.Error()
+			return repo
+		}
+	}
+	report, _, err := computeRangeReport(opts)
+	if err != nil {
+		repo.Error = err.Error()
+		return repo
+	}
+	if report != nil {
+		repo.Commits = report.Commits
+		repo.Summary = report.Summary
+	}
+	return repo
+}
+
+// printAggregateReport はリポジトリ別の内訳と合計を表で表示します。
+func printAggregateReport(agg *tracker.AggregateReport) {
+	fmt.Printf("AI Code Generation Report: %d repositories (%s)\n", len(agg.Repos), agg.Range)
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println()
+	fmt.Printf("  %-24s %8s %10s %12s %8s\n", "Repository", "Commits", "AI lines", "Human lines", "AI%")
+	for _, r := range agg.Repos {
+		if r.Error != "" {
+			fmt.Printf("  %-24s error: %s\n", r.Name, r.Error)
+			continue
+		}
+		fmt.Printf("  %-24s %8d %10d %12d %7.1f%%\n", r.Name, r.Commits, r.Summary.AILines, r.Summary.HumanLines, r.Summary.AIPercentage)
+	}
+	fmt.Println("  " + strings.Repeat("─", 66))
+	fmt.Printf("  %-24s %8d %10d %12d %7.1f%%\n", "Total", agg.Commits, agg.Summary.AILines, agg.Summary.HumanLines, agg.Summary.AIPercentage)
+	fmt.Println()
+}
+
+// writeAggregateCSV はリポジトリごとの行と合計行（repo=TOTAL）をCSVで出力します。
+func writeAggregateCSV(w io.Writer, agg *tracker.AggregateReport) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"repo", "path", "commits", "total_lines", "ai_lines", "human_lines", "ai_percentage", "error"})
+	row := func(name, path string, commits int, s tracker.SummaryStats, errMsg string) {
+		cw.Write([]string{
+			name, path, strconv.Itoa(commits), strconv.Itoa(s.TotalLines), strconv.Itoa(s.AILines), strconv.Itoa(s.HumanLines),
+			strconv.FormatFloat(s.AIPercentage, 'f', 1, 64), errMsg,
+		})
+	}
+	for _, r := range agg.Repos {
+		row(r.Name, r.Path, r.Commits, r.Summary, r.Error)
+	}
+	row("TOTAL", "", agg.Commits, agg.Summary, "")
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/git"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// rollupIssue は日次集計レコードとAuthorship Logからの再計算結果の不一致1件
+type rollupIssue struct {
+	Date   string
+	Branch string
+	Detail string
+}
+
+// handleAuditMetrics handles the audit-metrics command
+func handleAuditMetrics() error {
+	fs := flag.NewFlagSet("audit-metrics", flag.ExitOnError)
+	fix := fs.Bool("fix", false, "Rewrite daily rollups with the values recomputed from authorship logs")
+	fs.Parse(os.Args[2:])
+
+	store, _, err := loadStorageAndConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	var (
+		issues  []rollupIssue
+		checked int
+	)
+	if *fix {
+		// 再計算中に aict commit が集計を追記しないようロックを保持したまま書き直す
+		err = store.UpdateDailyRollups(func(rollups []*tracker.DailyRollup) []*tracker.DailyRollup {
+			var fixed []*tracker.DailyRollup
+			fixed, issues = auditDailyRollups(rollups)
+			checked = len(rollups)
+			if len(issues) == 0 {
+				return nil
+			}
+			return fixed
+		})
+	} else {
+		var rollups []*tracker.DailyRollup
+		rollups, err = store.LoadDailyRollups()
+		if err == nil {
+			_, issues = auditDailyRollups(rollups)
+			checked = len(rollups)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("auditing daily rollups: %w", err)
+	}
+
+	fmt.Printf("Audited %d daily rollup(s) against authorship logs\n", checked)
+	if len(issues) == 0 {
+		fmt.Println("✓ Metrics match records")
+		return nil
+	}
+
+	for _, issue := range issues {
+		key := issue.Date
+		if issue.Branch != "" {
+			key += " " + issue.Branch
+		}
+		fmt.Printf("  ✗ %s: %s\n", key, issue.Detail)
+	}
+
+	if *fix {
+		fmt.Printf("✓ Repaired %d discrepancy(ies) in %s\n", len(issues), storage.RollupsFileName)
+		return nil
+	}
+	return fmt.Errorf("found %d discrepancy(ies); run 'aict audit-metrics --fix' to repair", len(issues))
+}
+
+// auditDailyRollups は各日次集計レコードをAuthorship Logとnumstatから再計算し、
+// 再計算後のレコードと不一致の一覧を返します。
+// 存在しなくなったコミットと、複数のレコードに重複して含まれるコミットは除外します。
+// Authorship Logが読み込めないコミットを含むレコードは再計算できないため、そのまま残します。
+func auditDailyRollups(rollups []*tracker.DailyRollup) ([]*tracker.DailyRollup, []rollupIssue) {
+	executor := newExecutor()
+	nm := gitnotes.NewNotesManagerWithExecutor(executor)
+
+	var (
+		fixed  []*tracker.DailyRollup
+		issues []rollupIssue
+	)
+	seen := make(map[string]bool)
+
+	for _, r := range rollups {
+		report := func(format string, args ...interface{}) {
+			issues = append(issues, rollupIssue{Date: r.Date, Branch: r.Branch, Detail: fmt.Sprintf(format, args...)})
+		}
+
+		expected := &tracker.DailyRollup{Date: r.Date, Branch: r.Branch, Commits: []string{}}
+		recomputable := true
+		for _, c := range r.Commits {
+			if seen[c] {
+				report("commit %s is counted in more than one rollup", shortCommit(c))
+				continue
+			}
+			seen[c] = true
+
+			if _, err := executor.Run("cat-file", "-e", c+"^{commit}"); err != nil {
+				report("commit %s no longer exists", shortCommit(c))
+				continue
+			}
+
+			alog, err := nm.GetAuthorshipLog(c)
+			if err != nil {
+				report("cannot recompute: %v", err)
+				recomputable = false
+				break
+			}
+			numstatOutput, err := executor.Run("show", "--numstat", "--format=", c)
+			if err != nil {
+				report("cannot recompute: numstat for %s: %v", shortCommit(c), err)
+				recomputable = false
+				break
+			}
+			numstatMap, _ := git.ParseNumstat(numstatOutput)
+			if alog != nil {
+				applyLineCounters(numstatMap, c, func(path string) bool { _, ok := alog.Files[path]; return ok })
+			}
+
+			delta := &tracker.DailyRollup{Commits: []string{c}}
+			fillRollupDelta(delta, alog, numstatMap)
+			expected.Add(delta)
+		}
+
+		if !recomputable {
+			fixed = append(fixed, r)
+			continue
+		}
+		for _, d := range diffRollupValues(r, expected) {
+			report("%s", d)
+		}
+		if len(expected.Commits) > 0 {
+			fixed = append(fixed, expected)
+		}
+	}
+	return fixed, issues
+}
+
+// diffRollupValues は保存済みと再計算後の集計値の差分を "項目 保存値 → 再計算値" 形式で返します。
+func diffRollupValues(stored, expected *tracker.DailyRollup) []string {
+	var diffs []string
+	compare := func(field string, got, want int) {
+		if got != want {
+			diffs = append(diffs, fmt.Sprintf("%s %d → %d", field, got, want))
+		}
+	}
+	compare("ai_added", stored.AIAdded, expected.AIAdded)
+	compare("ai_deleted", stored.AIDeleted, expected.AIDeleted)
+	compare("human_added", stored.HumanAdded, expected.HumanAdded)
+	compare("human_deleted", stored.HumanDeleted, expected.HumanDeleted)
+	compare("ai_edited_by_human", stored.AIEditedByHuman, expected.AIEditedByHuman)
+	compare("human_edited_by_ai", stored.HumanEditedByAI, expected.HumanEditedByAI)
+
+	names := make(map[string]bool)
+	for name := range stored.Authors {
+		names[name] = true
+	}
+	for name := range expected.Authors {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		got, want := stored.Authors[name], expected.Authors[name]
+		switch {
+		case got == nil:
+			diffs = append(diffs, fmt.Sprintf("authors[%s] missing", name))
+		case want == nil:
+			diffs = append(diffs, fmt.Sprintf("authors[%s] not in authorship logs", name))
+		default:
+			if got.Type != want.Type {
+				diffs = append(diffs, fmt.Sprintf("authors[%s].type %s → %s", name, got.Type, want.Type))
+			}
+			compare(fmt.Sprintf("authors[%s].lines", name), got.Lines, want.Lines)
+			compare(fmt.Sprintf("authors[%s].deleted", name), got.Deleted, want.Deleted)
+			compare(fmt.Sprintf("authors[%s].commits", name), got.Commits, want.Commits)
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestDiffRollupValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		stored   *tracker.DailyRollup
+		expected *tracker.DailyRollup
+		want     []string
+	}{
+		{
+			"identical",
+			&tracker.DailyRollup{AIAdded: 3, Authors: map[string]*tracker.RollupAuthor{"a": {Type: tracker.AuthorTypeAI, Lines: 3, Commits: 1}}},
+			&tracker.DailyRollup{AIAdded: 3, Authors: map[string]*tracker.RollupAuthor{"a": {Type: tracker.AuthorTypeAI, Lines: 3, Commits: 1}}},
+			nil,
+		},
+		{
+			"totals and authors differ",
+			&tracker.DailyRollup{AIAdded: 10, HumanAdded: 1, Authors: map[string]*tracker.RollupAuthor{
+				"a":   {Type: tracker.AuthorTypeAI, Lines: 10, Commits: 1},
+				"old": {Type: tracker.AuthorTypeHuman, Lines: 1, Commits: 1},
+			}},
+			&tracker.DailyRollup{AIAdded: 12, Authors: map[string]*tracker.RollupAuthor{
+				"a":   {Type: tracker.AuthorTypeAI, Lines: 12, Commits: 1},
+				"new": {Type: tracker.AuthorTypeHuman},
+			}},
+			[]string{
+				"ai_added 10 → 12",
+				"human_added 1 → 0",
+				"authors[a].lines 10 → 12",
+				"authors[new] missing",
+				"authors[old] not in authorship logs",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffRollupValues(tt.stored, tt.expected); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffRollupValues() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleAuditMetrics(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+	testutil.CreateTestFile(t, tmpDir, "a.go", "package main\n\nfunc a() {}\n")
+	testutil.GitCommit(t, tmpDir, "Add a")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	store, _, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	original, _ := store.LoadDailyRollups()
+	if len(original) != 1 || len(original[0].Commits) != 2 {
+		t.Fatalf("rollups = %+v, want one record with 2 commits", original)
+	}
+
+	os.Args = []string{"aict", "audit-metrics"}
+	if err := handleAuditMetrics(); err != nil {
+		t.Fatalf("audit of consistent rollups error = %v", err)
+	}
+
+	// 手動編集による不整合（値の改ざんと存在しないコミット）
+	if err := store.UpdateDailyRollups(func(rollups []*tracker.DailyRollup) []*tracker.DailyRollup {
+		rollups[0].HumanAdded += 100
+		rollups[0].Commits = append(rollups[0].Commits, "0000000000000000000000000000000000000000")
+		return rollups
+	}); err != nil {
+		t.Fatalf("UpdateDailyRollups() error = %v", err)
+	}
+
+	rollups, _ := store.LoadDailyRollups()
+	if _, issues := auditDailyRollups(rollups); len(issues) != 2 {
+		t.Errorf("issues = %+v, want 2", issues)
+	}
+
+	os.Args = []string{"aict", "audit-metrics"}
+	if err := handleAuditMetrics(); err == nil {
+		t.Fatal("expected error for mismatched rollups")
+	}
+
+	os.Args = []string{"aict", "audit-metrics", "--fix"}
+	if err := handleAuditMetrics(); err != nil {
+		t.Fatalf("audit-metrics --fix error = %v", err)
+	}
+
+	repaired, _ := store.LoadDailyRollups()
+	if len(repaired) != 1 || !reflect.DeepEqual(repaired[0].Commits, original[0].Commits) || repaired[0].HumanAdded != original[0].HumanAdded {
+		t.Errorf("repaired = %+v, want %+v", repaired[0], original[0])
+	}
+
+	os.Args = []string{"aict", "audit-metrics"}
+	if err := handleAuditMetrics(); err != nil {
+		t.Errorf("audit after --fix error = %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+const (
+	// backstageMetadataDir はリポジトリ内でメタデータを配置する固定ディレクトリ
+	backstageMetadataDir = ".backstage"
+	// backstageMetadataBaseName はメタデータファイルの基本名（拡張子は --format による）
+	backstageMetadataBaseName = "aict-metadata"
+	// backstageAnnotationPrefix はBackstageのannotationキーの接頭辞
+	backstageAnnotationPrefix = "ai-code-tracker/"
+)
+
+// backstageMetadata はBackstageプラグインが読み込むサービス単位のメタデータ
+type backstageMetadata struct {
+	AIPercentage       float64   `json:"ai_percentage"`
+	TargetAIPercentage float64   `json:"target_ai_percentage"`
+	Commits            int       `json:"commits"`
+	Period             string    `json:"period"`
+	LastUpdated        time.Time `json:"last_updated"`
+	DashboardURL       string    `json:"dashboard_url,omitempty"`
+}
+
+// handleBackstageMetadata handles the backstage-metadata command
+func handleBackstageMetadata() error {
+	fs := flag.NewFlagSet("backstage-metadata", flag.ExitOnError)
+	since := fs.String("since", "30d", "Period to aggregate (same format as 'aict report --since')")
+	format := fs.String("format", "yaml", "Output format: yaml (catalog-info annotations) or json")
+	dashboardURL := fs.String("dashboard-url", "", "Dashboard URL (default: dashboard_url in config)")
+	write := fs.Bool("write", false, "Write to "+backstageMetadataDir+"/"+backstageMetadataBaseName+".<format> instead of stdout")
+	fields := fs.String("fields", "", fieldsFlagUsage)
+	fs.Parse(os.Args[2:])
+
+	if *format != "yaml" && *format != "json" {
+		return fmt.Errorf("unknown format: %s (available: yaml, json)", *format)
+	}
+	if *fields != "" && *format != "json" {
+		return fmt.Errorf("--fields requires --format json")
+	}
+
+	_, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return err
+	}
+
+	meta, err := collectBackstageMetadata(*since, cfg.TargetAIPercentage)
+	if err != nil {
+		return err
+	}
+	meta.DashboardURL = cfg.DashboardURL
+	if *dashboardURL != "" {
+		meta.DashboardURL = *dashboardURL
+	}
+
+	var out string
+	if *format == "json" {
+		data, err := marshalJSONFields(meta, *fields)
+		if err != nil {
+			return err
+		}
+		out = string(data) + "\n"
+	} else {
+		out = formatBackstageYAML(meta)
+	}
+
+	if !*write {
+		fmt.Print(out)
+		return nil
+	}
+
+	repoRoot, err := newExecutor().Run("rev-parse", "--show-toplevel")
+	if err != nil {
+		return fmt.Errorf("failed to get repository root: %w", err)
+	}
+	path := filepath.Join(repoRoot, backstageMetadataDir, backstageMetadataBaseName+"."+*format)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(out), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	fmt.Printf("✓ Backstage metadata written to %s\n", path)
+	return nil
+}
+
+// collectBackstageMetadata は指定期間のレポートからメタデータを組み立てます。
+// 期間内にコミットがない場合はAI%を0、コミット数を0とします。
+func collectBackstageMetadata(since string, target float64) (*backstageMetadata, error) {
+	meta := &backstageMetadata{
+		TargetAIPercentage: target,
+		Period:             "since " + since,
+	}
+
+	executor := newExecutor()
+	if lastCommit, err := executor.Run("log", "-1", "--format=%cI"); err == nil {
+		meta.LastUpdated, _ = time.Parse(time.RFC3339, lastCommit)
+	}
+
+	report, err := generateSinceReport(since)
+	if err != nil {
+		return nil, err
+	}
+	if report != nil {
+		meta.AIPercentage = report.Summary.AIPercentage
+		meta.Commits = report.Commits
+	}
+	return meta, nil
+}
+
+// generateSinceReport は指定期間のレポートを集計します。
+// 期間内にコミットがない場合は nil Report を返します（エラーにはしません）。
+func generateSinceReport(since string) (*tracker.Report, error) {
+	rangeSpec, err := convertSinceToRange(since)
+	if err != nil {
+		if strings.Contains(err.Error(), "no commits found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	report, _, err := generateRangeReport(&ReportOptions{Range: rangeSpec, Since: since})
+	return report, err
+}
+
+// formatBackstageYAML はcatalog-info.yaml にマージできるannotation断片を生成します。
+// annotationの値はBackstageの仕様上すべて文字列のため、引用符付きで出力します。
+func formatBackstageYAML(meta *backstageMetadata) string {
+	var b strings.Builder
+	b.WriteString("# Generated by 'aict backstage-metadata'. Merge into catalog-info.yaml.\n")
+	b.WriteString("metadata:\n")
+	b.WriteString("  annotations:\n")
+
+	annotations := []struct{ key, value string }{
+		{"ai-percentage", strconv.FormatFloat(meta.AIPercentage, 'f', 1, 64)},
+		{"target-ai-percentage", strconv.FormatFloat(meta.TargetAIPercentage, 'f', 1, 64)},
+		{"commits", strconv.Itoa(meta.Commits)},
+		{"period", meta.Period},
+	}
+	if !meta.LastUpdated.IsZero() {
+		annotations = append(annotations, struct{ key, value string }{"last-updated", meta.LastUpdated.Format(time.RFC3339)})
+	}
+	if meta.DashboardURL != "" {
+		annotations = append(annotations, struct{ key, value string }{"dashboard-url", meta.DashboardURL})
+	}
+
+	for _, a := range annotations {
+		fmt.Fprintf(&b, "    %s%s: %s\n", backstageAnnotationPrefix, a.key, strconv.Quote(a.value))
+	}
+	return b.String()
+}
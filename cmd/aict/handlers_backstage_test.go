@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestFormatBackstageYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    *backstageMetadata
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "all fields",
+			meta: &backstageMetadata{
+				AIPercentage:       62.5,
+				TargetAIPercentage: 80,
+				Commits:            12,
+				Period:             "since 30d",
+				LastUpdated:        time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+				DashboardURL:       "https://dashboard.example.com/svc",
+			},
+			want: []string{
+				"metadata:\n  annotations:\n",
+				`    ai-code-tracker/ai-percentage: "62.5"`,
+				`    ai-code-tracker/target-ai-percentage: "80.0"`,
+				`    ai-code-tracker/commits: "12"`,
+				`    ai-code-tracker/period: "since 30d"`,
+				`    ai-code-tracker/last-updated: "2025-01-15T10:30:00Z"`,
+				`    ai-code-tracker/dashboard-url: "https://dashboard.example.com/svc"`,
+			},
+		},
+		{
+			name: "optional fields omitted",
+			meta: &backstageMetadata{Period: "since 7d"},
+			want: []string{
+				`    ai-code-tracker/ai-percentage: "0.0"`,
+				`    ai-code-tracker/commits: "0"`,
+			},
+			notWant: []string{"last-updated", "dashboard-url"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatBackstageYAML(tt.meta)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("output missing %q\n%s", w, got)
+				}
+			}
+			for _, nw := range tt.notWant {
+				if strings.Contains(got, nw) {
+					t.Errorf("output should not contain %q\n%s", nw, got)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleBackstageMetadataWrite(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+	os.Args = []string{"aict", "backstage-metadata", "--format", "json", "--dashboard-url", "https://dash.example/svc", "--write"}
+
+	if err := handleBackstageMetadata(); err != nil {
+		t.Fatalf("handleBackstageMetadata() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, backstageMetadataDir, backstageMetadataBaseName+".json"))
+	if err != nil {
+		t.Fatalf("metadata file not written: %v", err)
+	}
+
+	var meta backstageMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if meta.DashboardURL != "https://dash.example/svc" {
+		t.Errorf("DashboardURL = %q", meta.DashboardURL)
+	}
+	if meta.Period != "since 30d" {
+		t.Errorf("Period = %q, want %q", meta.Period, "since 30d")
+	}
+	if meta.TargetAIPercentage != 80 {
+		t.Errorf("TargetAIPercentage = %v, want 80", meta.TargetAIPercentage)
+	}
+	if meta.LastUpdated.IsZero() {
+		t.Error("LastUpdated should be set from HEAD commit date")
+	}
+}
+
+func TestHandleBackstageMetadataInvalidFormat(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+	os.Args = []string{"aict", "backstage-metadata", "--format", "xml"}
+
+	if err := handleBackstageMetadata(); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+const (
+	// defaultBadgeLabel はバッジ左側のデフォルトラベル
+	defaultBadgeLabel = "AI code"
+	// badgeNoDataColor は期間内にコミットがない場合の色
+	badgeNoDataColor = "lightgrey"
+	// badgeCharWidth はVerdana 11pxの1文字あたりの概算幅（px）
+	badgeCharWidth = 7
+	// badgePadding はラベル・値それぞれの左右余白の合計（px）
+	badgePadding = 10
+)
+
+// defaultBadgeThresholds は config.json で badge.thresholds 未指定時の色分け
+var defaultBadgeThresholds = []tracker.BadgeThreshold{
+	{Min: 80, Color: "brightgreen"},
+	{Min: 60, Color: "green"},
+	{Min: 40, Color: "yellowgreen"},
+	{Min: 20, Color: "yellow"},
+	{Min: 0, Color: "orange"},
+}
+
+// badgeNamedColors はshields.ioの色名と色コードの対応表
+var badgeNamedColors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellowgreen": "#a4a61d",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+	"blue":        "#007ec6",
+	"lightgrey":   "#9f9f9f",
+	"grey":        "#555",
+}
+
+var hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// handleBadge handles the badge command
+func handleBadge() error {
+	fs := flag.NewFlagSet("badge", flag.ExitOnError)
+	since := fs.String("since", "30d", "Period to aggregate (same format as 'aict report --since')")
+	output := fs.String("output", "", "Write the SVG to this file instead of stdout")
+	label := fs.String("label", "", "Badge label (default: badge.label in config, or \""+defaultBadgeLabel+"\")")
+	fs.Parse(os.Args[2:])
+
+	_, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return err
+	}
+
+	badgeLabel := defaultBadgeLabel
+	thresholds := defaultBadgeThresholds
+	if cfg.Badge != nil {
+		if cfg.Badge.Label != "" {
+			badgeLabel = cfg.Badge.Label
+		}
+		if len(cfg.Badge.Thresholds) > 0 {
+			thresholds = cfg.Badge.Thresholds
+		}
+	}
+	if *label != "" {
+		badgeLabel = *label
+	}
+	for _, th := range thresholds {
+		if _, err := resolveBadgeColor(th.Color); err != nil {
+			return fmt.Errorf("badge.thresholds in config: %w", err)
+		}
+	}
+
+	report, err := generateSinceReport(*since)
+	if err != nil {
+		return err
+	}
+
+	value, colorName := "n/a", badgeNoDataColor
+	if report != nil {
+		pct := report.Summary.AIPercentage
+		value = fmt.Sprintf("%.1f%%", pct)
+		colorName = badgeColorFor(pct, thresholds)
+	}
+	color, err := resolveBadgeColor(colorName)
+	if err != nil {
+		return err
+	}
+
+	svg := renderBadgeSVG(badgeLabel, value, color)
+
+	if *output == "" {
+		fmt.Print(svg)
+		return nil
+	}
+	if dir := filepath.Dir(*output); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("creating %s: %w", dir, err)
+		}
+	}
+	if err := os.WriteFile(*output, []byte(svg), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", *output, err)
+	}
+	fmt.Printf("✓ Badge written to %s (%s: %s)\n", *output, badgeLabel, value)
+	return nil
+}
+
+// badgeColorFor はAI%が Min 以上となる閾値のうち最も高いものの色を返します。
+// どの閾値にも届かない場合は最も低い閾値の色を使います。
+func badgeColorFor(pct float64, thresholds []tracker.BadgeThreshold) string {
+	sorted := append([]tracker.BadgeThreshold(nil), thresholds...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Min > sorted[j].Min })
+
+	for _, th := range sorted {
+		if pct >= th.Min {
+			return th.Color
+		}
+	}
+	return sorted[len(sorted)-1].Color
+}
+
+// resolveBadgeColor はshields.ioの色名または "#rgb"/"#rrggbb" を色コードに変換します。
+func resolveBadgeColor(color string) (string, error) {
+	if hex, ok := badgeNamedColors[strings.ToLower(color)]; ok {
+		return hex, nil
+	}
+	if hexColorPattern.MatchString(color) {
+		return color, nil
+	}
+	return "", fmt.Errorf("unknown badge color: %s (use a shields.io color name or #rrggbb)", color)
+}
+
+// renderBadgeSVG はshields.ioのflatスタイル相当のSVGバッジを生成します。
+// 文字幅はフォントを読み込まずに概算するため、実際の描画幅と多少ずれることがあります。
+func renderBadgeSVG(label, value, color string) string {
+	labelWidth := len([]rune(label))*badgeCharWidth + badgePadding
+	valueWidth := len([]rune(value))*badgeCharWidth + badgePadding
+	width := labelWidth + valueWidth
+	labelX := labelWidth / 2
+	valueX := labelWidth + valueWidth/2
+
+	l := html.EscapeString(label)
+	v := html.EscapeString(value)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`+"\n", width, l, v)
+	fmt.Fprintf(&b, "  <title>%s: %s</title>\n", l, v)
+	b.WriteString(`  <linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` + "\n")
+	fmt.Fprintf(&b, `  <clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+"\n", width)
+	b.WriteString(`  <g clip-path="url(#r)">` + "\n")
+	fmt.Fprintf(&b, `    <rect width="%d" height="20" fill="#555"/>`+"\n", labelWidth)
+	fmt.Fprintf(&b, `    <rect x="%d" width="%d" height="20" fill="%s"/>`+"\n", labelWidth, valueWidth, color)
+	fmt.Fprintf(&b, `    <rect width="%d" height="20" fill="url(#s)"/>`+"\n", width)
+	b.WriteString("  </g>\n")
+	b.WriteString(`  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` + "\n")
+	fmt.Fprintf(&b, `    <text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`+"\n", labelX, l, labelX, l)
+	fmt.Fprintf(&b, `    <text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`+"\n", valueX, v, valueX, v)
+	b.WriteString("  </g>\n")
+	b.WriteString("</svg>\n")
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestBadgeColorFor(t *testing.T) {
+	custom := []tracker.BadgeThreshold{
+		{Min: 50, Color: "green"},
+		{Min: 90, Color: "blue"},
+		{Min: 10, Color: "red"},
+	}
+
+	tests := []struct {
+		name       string
+		pct        float64
+		thresholds []tracker.BadgeThreshold
+		want       string
+	}{
+		{"default high", 85, defaultBadgeThresholds, "brightgreen"},
+		{"default boundary", 60, defaultBadgeThresholds, "green"},
+		{"default low", 5, defaultBadgeThresholds, "orange"},
+		{"custom unsorted", 95, custom, "blue"},
+		{"custom middle", 50, custom, "green"},
+		{"below lowest threshold", 3, custom, "red"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := badgeColorFor(tt.pct, tt.thresholds); got != tt.want {
+				t.Errorf("badgeColorFor(%.1f) = %q, want %q", tt.pct, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveBadgeColor(t *testing.T) {
+	tests := []struct {
+		color   string
+		want    string
+		wantErr bool
+	}{
+		{"brightgreen", "#4c1", false},
+		{"Yellow", "#dfb317", false},
+		{"#abc", "#abc", false},
+		{"#12ab9F", "#12ab9F", false},
+		{"#12ab9", "", true},
+		{"pink", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := resolveBadgeColor(tt.color)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveBadgeColor(%q) error = %v, wantErr %v", tt.color, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("resolveBadgeColor(%q) = %q, want %q", tt.color, got, tt.want)
+		}
+	}
+}
+
+func TestRenderBadgeSVG(t *testing.T) {
+	svg := renderBadgeSVG("AI <code>", "62.5%", "#4c1")
+
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+		`aria-label="AI &lt;code&gt;: 62.5%"`,
+		`fill="#4c1"`,
+		`>62.5%</text>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG missing %q\n%s", want, svg)
+		}
+	}
+	if strings.Contains(svg, "<code>") {
+		t.Error("label should be XML-escaped")
+	}
+}
+
+func TestHandleBadge_ConfigThresholds(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		t.Fatalf("NewAIctStorage() error = %v", err)
+	}
+	cfg, err := store.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	cfg.Badge = &tracker.BadgeConfig{Label: "AI share", Thresholds: []tracker.BadgeThreshold{{Min: 0, Color: "#123456"}}}
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	testutil.CreateTestFile(t, tmpDir, "a.go", "package main\n\nfunc a() {}\n")
+	testutil.GitCommit(t, tmpDir, "Add a")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	output := filepath.Join(tmpDir, "badges", "ai.svg")
+	os.Args = []string{"aict", "badge", "--output", output}
+	if err := handleBadge(); err != nil {
+		t.Fatalf("handleBadge() error = %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("badge not written: %v", err)
+	}
+	svg := string(data)
+	if !strings.Contains(svg, "AI share") || !strings.Contains(svg, `fill="#123456"`) {
+		t.Errorf("badge should use configured label and color:\n%s", svg)
+	}
+
+	cfg.Badge.Thresholds = []tracker.BadgeThreshold{{Min: 0, Color: "pink"}}
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	os.Args = []string{"aict", "badge"}
+	if err := handleBadge(); err == nil {
+		t.Error("expected error for unknown color in config")
+	}
+}
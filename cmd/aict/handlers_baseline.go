@@ -0,0 +1,152 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func handleBaseline() error {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: aict baseline [create|list]")
+		return fmt.Errorf("baseline subcommand required")
+	}
+
+	subcommand := os.Args[2]
+
+	switch subcommand {
+	case "create":
+		return handleBaselineCreate()
+	case "list":
+		return handleBaselineList()
+	default:
+		fmt.Printf("Unknown subcommand: %s\n", subcommand)
+		fmt.Println("Usage: aict baseline [create|list]")
+		return fmt.Errorf("unknown subcommand: %s", subcommand)
+	}
+}
+
+func handleBaselineCreate() error {
+	fs := flag.NewFlagSet("baseline create", flag.ExitOnError)
+	label := fs.String("label", "", "Label for the baseline (e.g., 'sprint-12')")
+	scheduled := fs.Bool("scheduled", false, "For cron/CI: create only if the latest baseline is older than baseline.interval_days")
+	fs.Parse(os.Args[3:])
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	now := time.Now()
+	if *scheduled {
+		latest, err := latestBaseline(store)
+		if err != nil {
+			return err
+		}
+		if due := baselineDueAt(latest, cfg); now.Before(due) {
+			fmt.Printf("✓ Latest baseline %s is recent; next baseline due %s\n", shortCommit(latest.Commit), due.Format("2006-01-02 15:04"))
+			return nil
+		}
+	}
+
+	executor := newExecutor()
+	commit, err := executor.Run("rev-parse", "HEAD")
+	if err != nil {
+		return fmt.Errorf("getting HEAD commit: %w", err)
+	}
+	branch, _ := executor.Run("rev-parse", "--abbrev-ref", "HEAD")
+
+	b := &tracker.Baseline{
+		CreatedAt: now,
+		Commit:    commit,
+		Branch:    branch,
+		Label:     *label,
+		Scheduled: *scheduled,
+	}
+	pruned, err := store.AddBaseline(b, cfg.GetBaselineRetention())
+	if err != nil {
+		return fmt.Errorf("saving baseline: %w", err)
+	}
+
+	fmt.Printf("✓ Baseline created at %s\n", shortCommit(commit))
+	if pruned > 0 {
+		fmt.Printf("  Removed %d old baseline(s) (retention: %d)\n", pruned, cfg.GetBaselineRetention())
+	}
+	return nil
+}
+
+func handleBaselineList() error {
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	baselines, err := store.LoadBaselines()
+	if err != nil {
+		return fmt.Errorf("loading baselines: %w", err)
+	}
+	if len(baselines) == 0 {
+		fmt.Println("No baselines recorded. Run 'aict baseline create' to add one.")
+		return nil
+	}
+
+	// 新しい順に表示
+	fmt.Printf("Baselines (retention: %d)\n", cfg.GetBaselineRetention())
+	for i := len(baselines) - 1; i >= 0; i-- {
+		b := baselines[i]
+		line := fmt.Sprintf("  %s  %s", b.CreatedAt.Local().Format("2006-01-02 15:04"), shortCommit(b.Commit))
+		if b.Branch != "" {
+			line += "  " + b.Branch
+		}
+		if b.Label != "" {
+			line += "  " + b.Label
+		}
+		if b.Scheduled {
+			line += "  (scheduled)"
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// latestBaseline は最新のベースラインを返します。記録がない場合は nil を返します。
+func latestBaseline(store *storage.AIctStorage) (*tracker.Baseline, error) {
+	baselines, err := store.LoadBaselines()
+	if err != nil {
+		return nil, fmt.Errorf("loading baselines: %w", err)
+	}
+	if len(baselines) == 0 {
+		return nil, nil
+	}
+	return baselines[len(baselines)-1], nil
+}
+
+// baselineDueAt は次のベースラインを作成すべき時刻を返します（記録がない場合はゼロ値＝即時）。
+func baselineDueAt(latest *tracker.Baseline, cfg *tracker.Config) time.Time {
+	if latest == nil {
+		return time.Time{}
+	}
+	return latest.CreatedAt.Add(cfg.GetBaselineInterval())
+}
+
+// baselineReportRange は最新ベースライン以降のコミット範囲を返します。
+// ベースラインがない、またはベースラインのコミットが存在しない（gc済み等）場合は空文字を返します。
+func baselineReportRange(head string) (string, *tracker.Baseline) {
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		return "", nil
+	}
+	latest, err := latestBaseline(store)
+	if err != nil || latest == nil {
+		return "", nil
+	}
+	if _, err := newExecutor().Run("rev-parse", "--verify", "--quiet", latest.Commit+"^{commit}"); err != nil {
+		debugf("baseline commit %s not found: %v", latest.Commit, err)
+		return "", nil
+	}
+	return latest.Commit + ".." + head, latest
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestBaselineDueAt(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		latest *tracker.Baseline
+		cfg    *tracker.Config
+		want   time.Time
+	}{
+		{"no baseline", nil, &tracker.Config{}, time.Time{}},
+		{"default weekly", &tracker.Baseline{CreatedAt: created}, &tracker.Config{}, created.AddDate(0, 0, 7)},
+		{"configured interval", &tracker.Baseline{CreatedAt: created}, &tracker.Config{Baseline: &tracker.BaselineConfig{IntervalDays: 1}}, created.AddDate(0, 0, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := baselineDueAt(tt.latest, tt.cfg); !got.Equal(tt.want) {
+				t.Errorf("baselineDueAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleBaseline_CreateScheduledAndReportDefault(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	// ベースラインがなければ report は範囲指定が必要
+	os.Args = []string{"aict", "report"}
+	if err := handleRangeReport(); err == nil {
+		t.Fatal("expected error without baseline")
+	}
+
+	os.Args = []string{"aict", "baseline", "create", "--label", "sprint-1"}
+	if err := handleBaseline(); err != nil {
+		t.Fatalf("baseline create error = %v", err)
+	}
+
+	// 直近のベースラインがあるため --scheduled では作成しない
+	os.Args = []string{"aict", "baseline", "create", "--scheduled"}
+	if err := handleBaseline(); err != nil {
+		t.Fatalf("baseline create --scheduled error = %v", err)
+	}
+
+	store, _, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	baselines, _ := store.LoadBaselines()
+	if len(baselines) != 1 || baselines[0].Label != "sprint-1" || baselines[0].Scheduled {
+		t.Fatalf("baselines = %+v, want one manual baseline", baselines)
+	}
+
+	r, b := baselineReportRange("HEAD")
+	if b == nil || r != baselines[0].Commit+"..HEAD" {
+		t.Errorf("baselineReportRange() = %q, %+v", r, b)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "a.go", "package main\n\nfunc a() {}\n")
+	testutil.GitCommit(t, tmpDir, "Add a")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	os.Args = []string{"aict", "report", "--format", "json"}
+	if err := handleRangeReport(); err != nil {
+		t.Errorf("report since baseline error = %v", err)
+	}
+
+	os.Args = []string{"aict", "baseline", "list"}
+	if err := handleBaseline(); err != nil {
+		t.Errorf("baseline list error = %v", err)
+	}
+}
+
+func TestHandleBaseline_Retention(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	cfg.Baseline = &tracker.BaselineConfig{Retention: 2}
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		os.Args = []string{"aict", "baseline", "create", "--label", strings.Repeat("x", i+1)}
+		if err := handleBaseline(); err != nil {
+			t.Fatalf("baseline create error = %v", err)
+		}
+	}
+
+	baselines, _ := store.LoadBaselines()
+	if len(baselines) != 2 || baselines[0].Label != "xx" || baselines[1].Label != "xxx" {
+		t.Errorf("baselines = %+v, want the last 2", baselines)
+	}
+
+	os.Args = []string{"aict", "baseline", "unknown"}
+	if err := handleBaseline(); err == nil {
+		t.Error("expected error for unknown subcommand")
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/blame"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/ownership"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// lineTypeUncommitted は作業ツリーで未コミットの行の種別（aict blame・aict why）
+const lineTypeUncommitted = "uncommitted"
+
+// blameLine は aict blame の1行分の表示内容です。
+type blameLine struct {
+	Number  int
+	Content string
+	Range   tracker.OwnershipRange // Type が uncommitted の場合は未コミット
+}
+
+// handleBlame handles the blame command
+func handleBlame() error {
+	fs := flag.NewFlagSet("blame", flag.ExitOnError)
+	rev := fs.String("rev", "", "Revision to annotate (default: the working tree)")
+	format := fs.String("format", "text", "Output format: text or html")
+	output := fs.String("output", "", "Write to this file instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: aict blame [--rev <rev>] [--format text|html] [--output <file>] <file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(os.Args[2:])
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("exactly one file is required")
+	}
+	if *format != "text" && *format != "html" {
+		return fmt.Errorf("unknown format: %s (available: text, html)", *format)
+	}
+	path := fs.Arg(0)
+
+	lines, err := annotateFile(*rev, path)
+	if err != nil {
+		return err
+	}
+
+	title := path
+	if *rev != "" {
+		title = path + " @ " + *rev
+	}
+	var out string
+	if *format == "html" {
+		out = renderBlameHTML(title, lines)
+	} else {
+		out = renderBlameText(lines)
+	}
+
+	if *output == "" {
+		fmt.Print(out)
+		return nil
+	}
+	if dir := filepath.Dir(*output); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("creating %s: %w", dir, err)
+		}
+	}
+	if err := os.WriteFile(*output, []byte(out), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", *output, err)
+	}
+	fmt.Printf("✓ Annotated %s written to %s (%d lines)\n", path, *output, len(lines))
+	return nil
+}
+
+// annotateFile はファイルの内容を読み、git blame とAuthorship Logで各行の作成者を付与します。
+// rev が空の場合は作業ツリーの内容を対象とします。
+func annotateFile(rev, path string) ([]blameLine, error) {
+	executor := newExecutor()
+
+	var content string
+	if rev == "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		content = string(data)
+	} else {
+		// Run は出力の前後の空白を取り除くため、末尾の空行も保つ RunWithStdin を使う
+		out, err := executor.RunWithStdin("", "show", rev+":./"+filepath.ToSlash(path))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s at %s: %w", path, rev, err)
+		}
+		content = out
+	}
+
+	origins, err := blame.File(executor, rev, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("blaming %s: %w", path, err)
+	}
+	logs := ownership.NewLogCache(gitnotes.NewNotesManagerWithExecutor(executor))
+	logs.Debugf = debugf
+
+	byLine := make(map[int]tracker.OwnershipRange)
+	for _, r := range ownership.BuildRanges(origins, logs) {
+		if r.Commit == blame.UncommittedHash {
+			r.Type = lineTypeUncommitted
+		}
+		for n := r.Start; n <= r.End; n++ {
+			byLine[n] = r
+		}
+	}
+
+	text := splitLines(content)
+	lines := make([]blameLine, len(text))
+	for i, s := range text {
+		r, ok := byLine[i+1]
+		if !ok {
+			r = tracker.OwnershipRange{Start: i + 1, End: i + 1, Type: ownership.TypeUnknown}
+		}
+		lines[i] = blameLine{Number: i + 1, Content: s, Range: r}
+	}
+	return lines, nil
+}
+
+// blameSummary は行の種別ごとの行数とAIの割合を返します（未コミットの行は unknown に含めます）。
+func blameSummary(lines []blameLine) tracker.FileOwnership {
+	ranges := make([]tracker.OwnershipRange, len(lines))
+	for i, l := range lines {
+		// 範囲は複数行にまたがるため、1行ずつの範囲にして数える
+		r := l.Range
+		r.Start, r.End = l.Number, l.Number
+		ranges[i] = r
+	}
+	return ownership.NewFileOwnership("", ranges)
+}
+
+// blameAuthor は表示用の作成者（AIの場合はモデル名付き）を返します。
+func blameAuthor(r tracker.OwnershipRange) string {
+	if r.Tool != "" {
+		return r.Author + " (" + r.Tool + ")"
+	}
+	return r.Author
+}
+
+// renderBlameText は "行番号 種別 作成者 コミット | 内容" の形式で出力します。
+func renderBlameText(lines []blameLine) string {
+	width := len(fmt.Sprint(len(lines)))
+	var b strings.Builder
+	for _, l := range lines {
+		commit := ""
+		if l.Range.Type != lineTypeUncommitted {
+			commit = shortCommit(l.Range.Commit)
+		}
+		fmt.Fprintf(&b, "%*d %-11s %-24s %-7s | %s\n", width, l.Number, l.Range.Type, truncate(blameAuthor(l.Range), 24), commit, l.Content)
+	}
+	return b.String()
+}
+
+// truncate は表示幅を揃えるため、文字数が n を超える文字列を切り詰めます。
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// blameCSS は aict blame --format html のスタイルです（外部リソースを読み込まない単体のページにする）。
+const blameCSS = `body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 24px; color: #24292f; }
+h1 { font-size: 18px; margin: 0 0 8px; }
+.summary { margin: 0 0 16px; font-size: 14px; }
+.legend span { display: inline-block; padding: 1px 8px; margin-right: 6px; border-radius: 3px; font-size: 12px; }
+table { border-collapse: collapse; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: 12px; width: 100%; }
+td { padding: 0 8px; white-space: pre; vertical-align: top; }
+td.ln { color: #6e7781; text-align: right; user-select: none; border-right: 1px solid #d0d7de; }
+td.who { color: #57606a; border-right: 1px solid #d0d7de; }
+.ai { background: #fff1e5; }
+.human { background: #ffffff; }
+.unknown { background: #f6f8fa; }
+.uncommitted { background: #ddf4ff; }
+tr.ai td.who { color: #bc4c00; font-weight: 600; }`
+
+// renderBlameHTML はAIの行を強調した単体のHTMLページを生成します。
+// 各行の title 属性（ツールチップ）に作成者・モデル・コミットを表示します。
+func renderBlameHTML(title string, lines []blameLine) string {
+	sum := blameSummary(lines)
+	t := html.EscapeString(title)
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>aict blame: %s</title>\n", t)
+	fmt.Fprintf(&b, "<style>\n%s\n</style>\n</head>\n<body>\n", blameCSS)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", t)
+	fmt.Fprintf(&b, "<p class=\"summary\">%.1f%% AI (%d AI / %d human / %d unknown or uncommitted lines)</p>\n",
+		sum.AIPercentage, sum.AILines, sum.HumanLines, sum.UnknownLines)
+	b.WriteString("<p class=\"legend\"><span class=\"ai\">ai</span><span class=\"human\">human</span><span class=\"unknown\">unknown</span><span class=\"uncommitted\">uncommitted</span></p>\n")
+	b.WriteString("<table>\n")
+
+	prev := tracker.OwnershipRange{}
+	for _, l := range lines {
+		r := l.Range
+		tip := r.Type
+		if r.Author != "" {
+			tip = blameAuthor(r) + " · " + r.Type
+		}
+		if r.Type != lineTypeUncommitted && r.Commit != "" {
+			tip += " · " + shortCommit(r.Commit)
+		}
+		// 同じ範囲が続く間は作成者欄を空にして、範囲の先頭だけに表示する
+		who := ""
+		if l.Number == r.Start || r != prev {
+			who = truncate(blameAuthor(r), 24)
+			if who == "" {
+				who = r.Type
+			}
+		}
+		prev = r
+		fmt.Fprintf(&b, "<tr class=\"%s\" title=\"%s\"><td class=\"ln\">%d</td><td class=\"who\">%s</td><td>%s</td></tr>\n",
+			html.EscapeString(r.Type), html.EscapeString(tip), l.Number, html.EscapeString(who), html.EscapeString(l.Content))
+	}
+	b.WriteString("</table>\n</body>\n</html>\n")
+	return b.String()
+}
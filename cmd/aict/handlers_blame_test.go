@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestHandleBlame(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc a() { _ = 1 < 2 }\nfunc b() {}\n")
+	commit := testutil.GitCommit(t, tmpDir, "Add a and b")
+
+	nm := gitnotes.NewNotesManager()
+	if err := nm.AddAuthorshipLog(&tracker.AuthorshipLog{
+		Version: "1.0",
+		Commit:  commit,
+		Files: map[string]tracker.FileInfo{
+			"main.go": {Authors: []tracker.AuthorInfo{
+				{Name: "Claude Code", Type: tracker.AuthorTypeAI, Lines: [][]int{{2, 3}}, Metadata: map[string]string{"model": "sonnet"}},
+				{Name: "dev", Type: tracker.AuthorTypeHuman, Lines: [][]int{{4}}},
+			}},
+		},
+	}); err != nil {
+		t.Fatalf("AddAuthorshipLog() error = %v", err)
+	}
+	// 未コミットの行
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc a() { _ = 1 < 2 }\nfunc b() {}\nfunc c() {}\n")
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantErr  bool
+		wantText []string
+		notText  []string
+	}{
+		{"text", []string{"main.go"}, false, []string{
+			"1 unknown",
+			"3 ai          Claude Code (sonnet)     " + shortCommit(commit) + " | func a() { _ = 1 < 2 }",
+			"4 human       dev",
+			"5 uncommitted",
+		}, nil},
+		{"html", []string{"--format", "html", "main.go"}, false, []string{
+			"<!DOCTYPE html>",
+			`<tr class="ai" title="Claude Code (sonnet) · ai · ` + shortCommit(commit) + `">`,
+			`<tr class="human" title="dev · human · `,
+			`<tr class="uncommitted"`,
+			"func a() { _ = 1 &lt; 2 }",
+			"40.0% AI (2 AI / 1 human / 2 unknown or uncommitted lines)",
+		}, []string{"1 < 2"}},
+		{"committed revision", []string{"--rev", "HEAD", "main.go"}, false, []string{"4 human"}, []string{"func c()"}},
+		{"unknown format", []string{"--format", "pdf", "main.go"}, true, nil, nil},
+		{"missing file", []string{"missing.go"}, true, nil, nil},
+		{"no file", []string{}, true, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"aict", "blame"}, tt.args...)
+			origStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := handleBlame()
+
+			w.Close()
+			os.Stdout = origStdout
+			var buf bytes.Buffer
+			buf.ReadFrom(r)
+			output := buf.String()
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handleBlame() error = %v, wantErr %v\n%s", err, tt.wantErr, output)
+			}
+			for _, want := range tt.wantText {
+				if !strings.Contains(output, want) {
+					t.Errorf("output missing %q:\n%s", want, output)
+				}
+			}
+			for _, not := range tt.notText {
+				if strings.Contains(output, not) {
+					t.Errorf("output should not contain %q:\n%s", not, output)
+				}
+			}
+		})
+	}
+
+	t.Run("output file", func(t *testing.T) {
+		outPath := filepath.Join(tmpDir, "out", "main.html")
+		os.Args = []string{"aict", "blame", "--format", "html", "--output", outPath, "main.go"}
+		origStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := handleBlame()
+
+		w.Close()
+		os.Stdout = origStdout
+		var buf bytes.Buffer
+		buf.ReadFrom(r)
+
+		if err != nil {
+			t.Fatalf("handleBlame() error = %v", err)
+		}
+		if !strings.Contains(buf.String(), "✓ Annotated main.go written to") {
+			t.Errorf("unexpected output: %s", buf.String())
+		}
+		data, err := os.ReadFile(outPath)
+		if err != nil {
+			t.Fatalf("reading output: %v", err)
+		}
+		if !strings.Contains(string(data), `<tr class="ai"`) {
+			t.Errorf("output file has no AI rows:\n%s", data)
+		}
+	})
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// checkAnnotationTitle はGitHub Actionsアノテーションのタイトル
+const checkAnnotationTitle = "AI Code Tracker"
+
+// annotateFilesUsage は --annotate-files の説明（check と ci で共通）
+const annotateFilesUsage = "Warn about each file whose added lines are at least this AI percentage (e.g., 100)"
+
+// checkThresholds は aict check の閾値（負の値は未指定）
+type checkThresholds struct {
+	Min float64
+	Max float64
+}
+
+// violations は閾値違反の説明を返します（違反がなければ空）。
+func (th checkThresholds) violations(pct float64) []string {
+	var v []string
+	if th.Min >= 0 && pct < th.Min {
+		v = append(v, fmt.Sprintf("AI percentage %.1f%% is below the minimum %.1f%%", pct, th.Min))
+	}
+	if th.Max >= 0 && pct > th.Max {
+		v = append(v, fmt.Sprintf("AI percentage %.1f%% is above the maximum %.1f%%", pct, th.Max))
+	}
+	return v
+}
+
+// describe は閾値を "min 50.0%, max 90.0%" の形式で返します（閾値がなければ "none"）。
+func (th checkThresholds) describe() string {
+	var parts []string
+	if th.Min >= 0 {
+		parts = append(parts, fmt.Sprintf("min %.1f%%", th.Min))
+	}
+	if th.Max >= 0 {
+		parts = append(parts, fmt.Sprintf("max %.1f%%", th.Max))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// handleCheck handles the check command
+func handleCheck() error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	opts := &ReportOptions{}
+	fs.StringVar(&opts.Range, "range", "", "Commit range (e.g., 'origin/main..HEAD')")
+	fs.StringVar(&opts.Since, "since", "", "Check commits since date (e.g., '7d', '2025-01-01')")
+	fs.StringVar(&opts.Context, "context", "", "Check only files in the named context (config \"contexts\")")
+	minAI := fs.Float64("min-ai", -1, "Fail when the AI percentage is below this value (default: target_ai_percentage if no threshold is given)")
+	maxAI := fs.Float64("max-ai", -1, "Fail when the AI percentage is above this value")
+	annotateFiles := fs.Float64("annotate-files", -1, annotateFilesUsage)
+	fs.Parse(os.Args[2:])
+
+	if opts.Range != "" && opts.Since != "" {
+		return fmt.Errorf("--range and --since are mutually exclusive")
+	}
+	if opts.Range == "" && opts.Since == "" {
+		return fmt.Errorf("either --range or --since is required (e.g., aict check --range origin/main..HEAD --min-ai 50)")
+	}
+	for name, v := range map[string]float64{"--min-ai": *minAI, "--max-ai": *maxAI} {
+		if v > 100 {
+			return fmt.Errorf("%s must be between 0 and 100, got %.1f", name, v)
+		}
+	}
+	if *minAI >= 0 && *maxAI >= 0 && *minAI > *maxAI {
+		return fmt.Errorf("--min-ai (%.1f) must not exceed --max-ai (%.1f)", *minAI, *maxAI)
+	}
+	if *annotateFiles > 100 {
+		return fmt.Errorf("--annotate-files must be between 0 and 100, got %.1f", *annotateFiles)
+	}
+	opts.ByFile = *annotateFiles >= 0
+
+	if opts.Since != "" {
+		rangeSpec, err := convertSinceToRange(opts.Since)
+		if err != nil {
+			if strings.Contains(err.Error(), "no commits found") {
+				emitCheckResult(nil, checkThresholds{Min: *minAI, Max: *maxAI}, nil)
+				return nil
+			}
+			return err
+		}
+		opts.Range = rangeSpec
+	}
+
+	report, _, err := generateRangeReport(opts)
+	if err != nil {
+		return err
+	}
+
+	th := checkThresholds{Min: *minAI, Max: *maxAI}
+	if th.Min < 0 && th.Max < 0 {
+		// 閾値未指定時は目標AI%を下限とする（--context 指定時はコンテキストの目標）
+		if report != nil && report.Context != "" {
+			th.Min = report.TargetAIPercentage
+		} else if _, cfg, err := loadStorageAndConfig(); err == nil {
+			th.Min = cfg.TargetAIPercentage
+		}
+	}
+
+	var violations []string
+	if report != nil {
+		violations = th.violations(report.Summary.AIPercentage)
+	}
+	emitCheckResult(report, th, violations)
+	emitFileAnnotations(report, *annotateFiles)
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%s", strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// emitCheckResult は判定結果を出力します。
+// GitHub Actions上ではワークフローコマンド（::notice / ::error）で出力し、
+// $GITHUB_STEP_SUMMARY が設定されていればMarkdownのサマリーを追記します。
+func emitCheckResult(report *tracker.Report, th checkThresholds, violations []string) {
+	inActions := os.Getenv("GITHUB_ACTIONS") == "true"
+
+	var message string
+	switch {
+	case report == nil:
+		message = "No commits to check; skipping AI percentage threshold"
+	case th.Min < 0 && th.Max < 0:
+		// aict ci で閾値を指定しない場合
+		message = fmt.Sprintf("AI percentage %.1f%% across %d commits", report.Summary.AIPercentage, report.Commits)
+	case len(violations) == 0:
+		message = fmt.Sprintf("AI percentage %.1f%% is within the threshold (%s) across %d commits",
+			report.Summary.AIPercentage, th.describe(), report.Commits)
+	}
+
+	if inActions {
+		if message != "" {
+			fmt.Println(githubAnnotation("notice", message))
+		}
+		for _, v := range violations {
+			fmt.Println(githubAnnotation("error", v))
+		}
+	} else {
+		if message != "" {
+			fmt.Printf("✓ %s\n", message)
+		}
+		for _, v := range violations {
+			fmt.Printf("✗ %s\n", v)
+		}
+	}
+
+	if summaryPath := os.Getenv("GITHUB_STEP_SUMMARY"); summaryPath != "" {
+		if err := appendStepSummary(summaryPath, report, th, violations); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write step summary: %v\n", err)
+		}
+	}
+}
+
+// emitFileAnnotations は範囲内で追加された行のAI%が threshold 以上のファイルを警告として出力します（threshold < 0 で無効）。
+// GitHub Actions上ではファイル単位のアノテーション（Files Changed タブに表示）、
+// それ以外では問題マッチャーで扱える "path:1: warning: message" 形式で出力します。
+func emitFileAnnotations(report *tracker.Report, threshold float64) {
+	if report == nil || threshold < 0 {
+		return
+	}
+	inActions := os.Getenv("GITHUB_ACTIONS") == "true"
+	for _, f := range report.ByFile {
+		if f.TotalLines == 0 || f.Percentage < threshold {
+			continue
+		}
+		message := fmt.Sprintf("%.0f%% AI-generated in %s (%d of %d added lines); review it carefully",
+			f.Percentage, report.Range, f.AILines, f.TotalLines)
+		if inActions {
+			fmt.Println(githubFileAnnotation("warning", f.Path, message))
+		} else {
+			fmt.Printf("%s:1: warning: %s\n", f.Path, message)
+		}
+	}
+}
+
+// githubAnnotation はGitHub Actionsのワークフローコマンドを組み立てます。
+func githubAnnotation(level, message string) string {
+	return fmt.Sprintf("::%s title=%s::%s", level, checkAnnotationTitle, escapeWorkflowData(message))
+}
+
+// githubFileAnnotation はファイルを対象とするワークフローコマンドを組み立てます。
+func githubFileAnnotation(level, path, message string) string {
+	return fmt.Sprintf("::%s file=%s,title=%s::%s", level, escapeWorkflowProperty(path), checkAnnotationTitle, escapeWorkflowData(message))
+}
+
+// escapeWorkflowData はワークフローコマンドのメッセージ部をエスケープします。
+func escapeWorkflowData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeWorkflowProperty はワークフローコマンドのプロパティ値（file= 等）をエスケープします。
+func escapeWorkflowProperty(s string) string {
+	s = escapeWorkflowData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
+
+// appendStepSummary は判定結果をMarkdownで $GITHUB_STEP_SUMMARY に追記します。
+func appendStepSummary(path string, report *tracker.Report, th checkThresholds, violations []string) error {
+	var b strings.Builder
+	b.WriteString("### AI Code Tracker\n\n")
+
+	if report == nil {
+		b.WriteString("No commits to check.\n\n")
+	} else {
+		status := "✅ Passed"
+		if len(violations) > 0 {
+			status = "❌ Failed"
+		}
+		fmt.Fprintf(&b, "| Range | Commits | AI lines | Human lines | AI %% | Threshold | Result |\n")
+		fmt.Fprintf(&b, "|---|---:|---:|---:|---:|---|---|\n")
+		fmt.Fprintf(&b, "| `%s` | %d | %d | %d | %.1f%% | %s | %s |\n\n",
+			report.Range, report.Commits, report.Summary.AILines, report.Summary.HumanLines,
+			report.Summary.AIPercentage, th.describe(), status)
+		for _, v := range violations {
+			fmt.Fprintf(&b, "- %s\n", v)
+		}
+		if len(violations) > 0 {
+			b.WriteString("\n")
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(b.String())
+	return err
+}
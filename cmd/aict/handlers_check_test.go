@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestCheckThresholdsViolations(t *testing.T) {
+	tests := []struct {
+		name string
+		th   checkThresholds
+		pct  float64
+		want int
+	}{
+		{"no thresholds", checkThresholds{Min: -1, Max: -1}, 50, 0},
+		{"above min", checkThresholds{Min: 40, Max: -1}, 50, 0},
+		{"equal to min", checkThresholds{Min: 50, Max: -1}, 50, 0},
+		{"below min", checkThresholds{Min: 60, Max: -1}, 50, 1},
+		{"above max", checkThresholds{Min: -1, Max: 40}, 50, 1},
+		{"within range", checkThresholds{Min: 40, Max: 60}, 50, 0},
+		{"zero min is enforced", checkThresholds{Min: 0, Max: -1}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.th.violations(tt.pct); len(got) != tt.want {
+				t.Errorf("violations(%.1f) = %v, want %d violations", tt.pct, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGithubAnnotation(t *testing.T) {
+	got := githubAnnotation("error", "AI 50% is low\nsecond line")
+	want := "::error title=AI Code Tracker::AI 50%25 is low%0Asecond line"
+	if got != want {
+		t.Errorf("githubAnnotation() = %q, want %q", got, want)
+	}
+}
+
+func TestGithubFileAnnotation(t *testing.T) {
+	got := githubFileAnnotation("warning", "dir/a,b:c.go", "100% AI")
+	want := "::warning file=dir/a%2Cb%3Ac.go,title=AI Code Tracker::100%25 AI"
+	if got != want {
+		t.Errorf("githubFileAnnotation() = %q, want %q", got, want)
+	}
+}
+
+func TestEmitFileAnnotations(t *testing.T) {
+	report := &tracker.Report{
+		Range: "main..HEAD",
+		ByFile: []tracker.FileStats{
+			{Path: "gen.go", TotalLines: 12, AILines: 12, Percentage: 100},
+			{Path: "mixed.go", TotalLines: 10, AILines: 6, Percentage: 60},
+			{Path: "human.go", TotalLines: 5, HumanLines: 5},
+			{Path: "deleted.go"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		actions   string
+		threshold float64
+		want      []string
+		notWant   []string
+	}{
+		{"actions 100%", "true", 100,
+			[]string{"::warning file=gen.go,title=AI Code Tracker::100%25 AI-generated in main..HEAD (12 of 12 added lines)"},
+			[]string{"mixed.go", "human.go"}},
+		{"problem matcher text", "", 50,
+			[]string{"gen.go:1: warning: 100% AI-generated", "mixed.go:1: warning: 60% AI-generated in main..HEAD (6 of 10 added lines)"},
+			[]string{"human.go", "::warning"}},
+		{"zero threshold skips files without added lines", "", 0,
+			[]string{"human.go:1: warning: 0% AI-generated"},
+			[]string{"deleted.go"}},
+		{"disabled", "true", -1, nil, []string{"warning"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_ACTIONS", tt.actions)
+			origStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			emitFileAnnotations(report, tt.threshold)
+
+			w.Close()
+			os.Stdout = origStdout
+			var buf bytes.Buffer
+			buf.ReadFrom(r)
+			output := buf.String()
+
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("output missing %q:\n%s", want, output)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(output, notWant) {
+					t.Errorf("output should not contain %q:\n%s", notWant, output)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleCheck(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	testutil.CreateTestFile(t, tmpDir, "a.go", "package main\n\nfunc a() {}\n")
+	testutil.GitCommit(t, tmpDir, "Add a")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	summaryPath := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_STEP_SUMMARY", summaryPath)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantErr  bool
+		wantText string
+	}{
+		// フックなしのコミットは人間の変更として記録されるためAI率は0%
+		{"passes max", []string{"--since", "2000-01-01", "--max-ai", "10"}, false, "::notice title=AI Code Tracker::"},
+		{"violates min", []string{"--since", "2000-01-01", "--min-ai", "10"}, true, "::error title=AI Code Tracker::"},
+		{"annotates files", []string{"--since", "2000-01-01", "--max-ai", "10", "--annotate-files", "0"}, false, "::warning file=a.go,title=AI Code Tracker::0%25 AI-generated"},
+		{"missing range", []string{"--min-ai", "10"}, true, ""},
+		{"min exceeds max", []string{"--since", "2000-01-01", "--min-ai", "60", "--max-ai", "40"}, true, ""},
+		{"out of range threshold", []string{"--since", "2000-01-01", "--min-ai", "120"}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"aict", "check"}, tt.args...)
+			origStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := handleCheck()
+
+			w.Close()
+			os.Stdout = origStdout
+			var buf bytes.Buffer
+			buf.ReadFrom(r)
+			output := buf.String()
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handleCheck() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantText != "" && !strings.Contains(output, tt.wantText) {
+				t.Errorf("output missing %q:\n%s", tt.wantText, output)
+			}
+		})
+	}
+
+	data, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatalf("step summary not written: %v", err)
+	}
+	summary := string(data)
+	for _, want := range []string{"### AI Code Tracker", "✅ Passed", "❌ Failed", "below the minimum 10.0%"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("step summary missing %q:\n%s", want, summary)
+		}
+	}
+}
@@ -7,39 +7,113 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/y-hirakaw/ai-code-tracker/internal/generated"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/ignore"
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
 	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
 )
 
+// maxCheckpointHunks は1チェックポイントに記録するdiff hunkヘッダの上限です。
+// 大規模な一括変更でチェックポイントファイルが肥大化しないよう、超過分のファイルはHunksを省略します。
+const maxCheckpointHunks = 1000
+
 func handleCheckpoint() error {
+	if len(os.Args) > 2 && os.Args[2] == "migrate" {
+		return handleCheckpointMigrate()
+	}
+
 	fs := flag.NewFlagSet("checkpoint", flag.ExitOnError)
 	author := fs.String("author", "", "作成者名（デフォルト: config.default_author）")
 	model := fs.String("model", "", "AIモデル名（AIエージェントの場合）")
 	message := fs.String("message", "", "メモ（オプション）")
+	weight := fs.Float64("weight", 0, "変更のうちAIに帰属させる割合 0〜1（例: 0.5 = AIと人間で半々。省略時は作成者の種別に従う）")
 	fs.Parse(os.Args[2:])
 
+	// --weight は指定された場合のみ記録する（0 = 全て人間、も有効な値のため）
+	var aiWeight *float64
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "weight" {
+			aiWeight = weight
+		}
+	})
+	if aiWeight != nil && (*aiWeight < 0 || *aiWeight > 1) {
+		return fmt.Errorf("--weight must be between 0 and 1, got %v", *aiWeight)
+	}
+	return recordWeightedCheckpoint(*author, *model, *message, aiWeight)
+}
+
+// handleCheckpointMigrate はチェックポイントの保存先（file / notes）を切り替え、保存済みのチェックポイントを移します。
+func handleCheckpointMigrate() error {
+	fs := flag.NewFlagSet("checkpoint migrate", flag.ExitOnError)
+	to := fs.String("to", "", "Checkpoint storage to switch to: notes (git notes) or file (.git/aict/checkpoints/latest.json)")
+	fs.Parse(os.Args[3:])
+	if *to == "" {
+		return fmt.Errorf("--to is required (notes or file)")
+	}
+
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		return fmt.Errorf("initializing storage: %w", err)
+	}
+	moved, err := store.MigrateCheckpoints(*to)
+	if err != nil {
+		return fmt.Errorf("migrating checkpoints: %w", err)
+	}
+
+	if *to == storage.CheckpointStorageNotes {
+		fmt.Printf("✓ Checkpoints are now stored in git notes (%s); moved %d checkpoint(s)\n", gitnotes.CheckpointNotesFullRef, moved)
+		fmt.Println("  Share them with 'aict notes sync'")
+	} else {
+		fmt.Printf("✓ Checkpoints are now stored in .git/aict/%s/%s; moved %d checkpoint(s) from git notes\n", storage.CheckpointsDirName, storage.LatestFileName, moved)
+	}
+	return nil
+}
+
+// recordCheckpoint はチェックポイント記録の本体です。
+// hookから呼ばれる `aict checkpoint` と verify-setup で同じ処理経路を共有します。
+func recordCheckpoint(author, model, message string) error {
+	return recordWeightedCheckpoint(author, model, message, nil)
+}
+
+// recordWeightedCheckpoint は変更のうちAIに帰属させる割合（aiWeight、nil=作成者の種別に従う）を付けてチェックポイントを記録します。
+func recordWeightedCheckpoint(author, model, message string, aiWeight *float64) error {
+	_, err := createCheckpoint(author, model, message, aiWeight, nil)
+	return err
+}
+
+// createCheckpoint はチェックポイントを記録し、記録したチェックポイントを返します。
+// metadata はチェックポイントのメタデータに追加します（hookのセッションIDなど）。
+func createCheckpoint(author, model, message string, aiWeight *float64, metadata map[string]string) (*tracker.CheckpointV2, error) {
+	start := time.Now()
+
 	// Gitリポジトリのルートディレクトリに移動
 	executor := newExecutor()
 	repoRoot, err := executor.Run("rev-parse", "--show-toplevel")
 	if err != nil {
-		return fmt.Errorf("not in a git repository")
+		return nil, fmt.Errorf("not in a git repository")
 	}
 	if err := os.Chdir(repoRoot); err != nil {
-		return fmt.Errorf("failed to change directory to %s: %w", repoRoot, err)
+		return nil, fmt.Errorf("failed to change directory to %s: %w", repoRoot, err)
 	}
 
 	// ストレージと設定を読み込み
 	store, config, err := loadStorageAndConfig()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Run 'aict init' first\n")
-		return err
+		return nil, err
 	}
 
 	// 作成者名を決定
-	authorName := *author
+	authorName := author
+	if authorName == "" {
+		authorName, _ = activeHumanAuthor(store)
+	}
 	if authorName == "" {
 		if config.DefaultAuthor != "" {
 			authorName = config.DefaultAuthor
@@ -49,7 +123,7 @@ func handleCheckpoint() error {
 			if err == nil {
 				authorName = output
 			} else {
-				return fmt.Errorf("author name not specified and default_author not configured. Use --author flag or configure default_author")
+				return nil, fmt.Errorf("author name not specified and default_author not configured. Use --author flag or configure default_author")
 			}
 		}
 	}
@@ -63,7 +137,7 @@ func handleCheckpoint() error {
 	// 前回のチェックポイントを読み込む
 	checkpoints, err := store.LoadCheckpoints()
 	if err != nil {
-		return fmt.Errorf("loading checkpoints: %w", err)
+		return nil, fmt.Errorf("loading checkpoints: %w", err)
 	}
 
 	var lastCheckpoint *tracker.CheckpointV2
@@ -72,16 +146,21 @@ func handleCheckpoint() error {
 	}
 
 	// 現在のスナップショットを作成
-	currentSnapshot, err := captureSnapshot(config.TrackedExtensions)
+	var artifactPatterns []string
+	if config.Artifacts != nil {
+		artifactPatterns = config.Artifacts.Patterns
+	}
+	currentSnapshot, err := captureSnapshot(config.TrackedExtensions, artifactPatterns, config.ExcludeMatcher(), !config.IncludeGenerated)
 	if err != nil {
-		return fmt.Errorf("capturing snapshot: %w", err)
+		return nil, fmt.Errorf("capturing snapshot: %w", err)
 	}
 
 	// 前回のチェックポイントとの差分を検出
 	changes, err := detectChangesFromSnapshot(lastCheckpoint, currentSnapshot)
 	if err != nil {
-		return fmt.Errorf("detecting changes: %w", err)
+		return nil, fmt.Errorf("detecting changes: %w", err)
 	}
+	capCheckpointHunks(changes, maxCheckpointHunks)
 
 	// 変更がない場合でもチェックポイントを記録（初回やbaseline）
 	if len(changes) == 0 {
@@ -111,20 +190,28 @@ func handleCheckpoint() error {
 		Changes:    changes,
 		Snapshot:   currentSnapshot,
 		BaseCommit: currentHead,
+		AIWeight:   aiWeight,
 	}
 
 	// メタデータを追加
-	if *model != "" {
-		checkpoint.Metadata["model"] = *model
+	if model != "" {
+		checkpoint.Metadata["model"] = model
+	}
+	if message != "" {
+		checkpoint.Metadata["message"] = message
 	}
-	if *message != "" {
-		checkpoint.Metadata["message"] = *message
+	for key, value := range metadata {
+		checkpoint.Metadata[key] = value
 	}
 
+	// 設定の redaction に従ってメッセージ等の秘密情報らしき文字列を伏せる
+	redactCheckpoint(store, config, checkpoint)
+
 	// チェックポイントを保存
 	if err := store.SaveCheckpoint(checkpoint); err != nil {
-		return fmt.Errorf("saving checkpoint: %w", err)
+		return nil, fmt.Errorf("saving checkpoint: %w", err)
 	}
+	logTiming(store, timingEventCheckpoint, time.Since(start))
 
 	// 変更行数をカウント
 	totalAdded := 0
@@ -134,12 +221,25 @@ func handleCheckpoint() error {
 		totalFiles++
 	}
 
-	fmt.Printf("✓ Checkpoint created (%s, %d files, %d lines added)\n", authorName, totalFiles, totalAdded)
-	return nil
+	if aiWeight != nil {
+		fmt.Printf("✓ Checkpoint created (%s, %d files, %d lines added, AI weight %g)\n", authorName, totalFiles, totalAdded, *aiWeight)
+	} else {
+		fmt.Printf("✓ Checkpoint created (%s, %d files, %d lines added)\n", authorName, totalFiles, totalAdded)
+	}
+
+	// 設定の events があればメッセージバス・Webhookへ通知（失敗してもチェックポイントは記録済みのため警告のみ）
+	if config.Events != nil {
+		if err := publishCheckpointEvent(config.Events, checkpoint); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to publish checkpoint event: %v\n", err)
+		}
+	}
+	return checkpoint, nil
 }
 
 // captureSnapshot は作業ディレクトリ内のすべての追跡対象ファイルのスナップショットを作成します
-func captureSnapshot(trackedExtensions []string) (map[string]tracker.FileSnapshot, error) {
+// artifactPatterns（設定の artifacts）に一致するファイルは拡張子に関係なく含め、
+// exclude（exclude_patterns と .aictignore）に一致するファイルは含めません
+func captureSnapshot(trackedExtensions, artifactPatterns []string, exclude *ignore.Matcher, skipGenerated bool) (map[string]tracker.FileSnapshot, error) {
 	snapshot := make(map[string]tracker.FileSnapshot)
 
 	// Git管理下のファイル一覧を取得（追跡されているファイル + 未追跡の新規ファイル）
@@ -155,44 +255,79 @@ func captureSnapshot(trackedExtensions []string) (map[string]tracker.FileSnapsho
 		extMap[ext] = true
 	}
 
+	// 大文字小文字を区別しないファイルシステムでは、インデックスの表記を正としてパスを揃える
+	canon := newPathCanonicalizer(nil, false)
+	if gitIgnoresCase() {
+		cached, _ := executor.Run("ls-files", "--cached")
+		canon = newPathCanonicalizer(strings.Split(cached, "\n"), true)
+	}
+
 	files := strings.Split(output, "\n")
 	for _, filepath := range files {
 		if filepath == "" {
 			continue
 		}
+		filepath = canon.canonical(filepath)
+		canon.add(filepath)
+		if _, seen := snapshot[filepath]; seen {
+			continue
+		}
 
 		// 拡張子チェック
 		ext := ""
 		if idx := strings.LastIndex(filepath, "."); idx != -1 {
 			ext = filepath[idx:]
 		}
-		if !extMap[ext] {
+		if !extMap[ext] && !matchesArtifactPattern(filepath, artifactPatterns) {
+			continue
+		}
+		if exclude.Match(filepath) {
 			continue
 		}
 
 		// 作業ディレクトリのファイル内容を読み込み（コミット済みでなくても良い）
-		content, err := os.ReadFile(filepath)
+		content, err := readTrackedContent(filepath)
 		if err != nil {
 			debugf("skipping file %s: %v", filepath, err)
 			continue
 		}
+		if skipGenerated {
+			if reason := generated.Detect(filepath, content); reason != "" {
+				debugf("skipping generated file %s (%s)", filepath, reason)
+				continue
+			}
+		}
 
 		// ハッシュ計算
 		hash := sha256.Sum256(content)
 		hashStr := hex.EncodeToString(hash[:])
 
-		// 行数カウント（メモリ効率: strings.Split でスライス生成せず bytes.Count で数える）
-		lines := bytes.Count(content, []byte{'\n'}) + 1
+		// 行数カウント（改行コード・エンコーディングの違いを吸収。.ipynb・.md 等は登録済みCounterで数える）
+		lines := countContentLines(filepath, content)
+		if enc := detectTextEncoding(content); enc != encodingUTF8 {
+			debugf("file %s: detected %s content", filepath, enc)
+		}
 
 		snapshot[filepath] = tracker.FileSnapshot{
-			Hash:  hashStr,
-			Lines: lines,
+			Hash:       hashStr,
+			Lines:      lines,
+			Conflicted: hasConflictMarkers(content),
 		}
 	}
 
 	return snapshot, nil
 }
 
+// matchesArtifactPattern はファイルが artifacts のパターンのいずれかに一致するかを判定します
+func matchesArtifactPattern(path string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if tracker.MatchesPattern(path, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
 // detectChangesFromSnapshot は2つのスナップショット間の変更を検出します
 func detectChangesFromSnapshot(lastCheckpoint *tracker.CheckpointV2, currentSnapshot map[string]tracker.FileSnapshot) (map[string]tracker.Change, error) {
 	changes := make(map[string]tracker.Change)
@@ -214,6 +349,7 @@ func detectChangesFromSnapshot(lastCheckpoint *tracker.CheckpointV2, currentSnap
 				Added:   currentFile.Lines,
 				Deleted: 0,
 				Lines:   [][]int{{1, currentFile.Lines}},
+				Hunks:   [][4]int{{0, 0, 1, currentFile.Lines}},
 			}
 		} else if currentFile.Hash != lastFile.Hash {
 			// ファイルが変更された場合、git diffで詳細を取得
@@ -234,12 +370,19 @@ func detectChangesFromSnapshot(lastCheckpoint *tracker.CheckpointV2, currentSnap
 					}
 				}
 			} else {
+				hunks, _ := getDiffHunks(filepath)
 				changes[filepath] = tracker.Change{
 					Added:   added,
 					Deleted: deleted,
 					Lines:   lineRanges,
+					Hunks:   hunks,
 				}
 			}
+			// 変更前にコンフリクトマーカーがあった = この変更でマージのコンフリクトを解消した
+			if change, ok := changes[filepath]; ok && lastFile.Conflicted {
+				change.ConflictResolution = true
+				changes[filepath] = change
+			}
 		}
 	}
 
@@ -250,6 +393,7 @@ func detectChangesFromSnapshot(lastCheckpoint *tracker.CheckpointV2, currentSnap
 				Added:   0,
 				Deleted: lastFile.Lines,
 				Lines:   [][]int{},
+				Hunks:   [][4]int{{1, lastFile.Lines, 0, 0}},
 			}
 		}
 	}
@@ -260,7 +404,7 @@ func detectChangesFromSnapshot(lastCheckpoint *tracker.CheckpointV2, currentSnap
 // getDetailedDiff gets detailed diff information for a file by comparing file content directly
 func getDetailedDiff(filepath string) (added, deleted int, lineRanges [][]int, err error) {
 	// 作業ディレクトリの現在のファイル内容を取得
-	currentContent, err := os.ReadFile(filepath)
+	currentContent, err := readTrackedContent(filepath)
 	if err != nil {
 		return 0, 0, nil, fmt.Errorf("failed to read current file: %w", err)
 	}
@@ -270,13 +414,24 @@ func getDetailedDiff(filepath string) (added, deleted int, lineRanges [][]int, e
 	headContentStr, err := executor.Run("show", fmt.Sprintf("HEAD:%s", filepath))
 	if err != nil {
 		// HEADに存在しない（新規ファイル）の場合
-		lineCount := bytes.Count(bytes.TrimSpace(currentContent), []byte{'\n'}) + 1
+		lineCount := countContentLines(filepath, bytes.TrimSpace(currentContent))
+		if lineCount == 0 {
+			return 0, 0, [][]int{}, nil
+		}
 		return lineCount, 0, [][]int{{1, lineCount}}, nil
 	}
 
-	// 両方の内容を行単位で比較
-	currentLines := strings.Split(strings.TrimSpace(string(currentContent)), "\n")
-	headLines := strings.Split(headContentStr, "\n")
+	// Counterが登録された拡張子は抽出した行同士で比較（出力セルや本文の変更は数えない）
+	if a, d, ok := countedDiffStat(filepath, []byte(headContentStr), currentContent); ok {
+		if a == 0 {
+			return a, d, [][]int{}, nil
+		}
+		return a, d, [][]int{{1, a}}, nil
+	}
+
+	// 両方の内容を行単位で比較（改行コードを正規化し、CRLF/LFの違いを変更とみなさない）
+	currentLines := splitLines(strings.TrimSpace(string(currentContent)))
+	headLines := splitLines(headContentStr)
 
 	// 簡易的なdiff計算（追加・削除行数）
 	currentLineCount := len(currentLines)
@@ -320,52 +475,105 @@ func getDetailedDiff(filepath string) (added, deleted int, lineRanges [][]int, e
 
 // getLineRangesFromDiff extracts line ranges using git diff
 func getLineRangesFromDiff(filepath string) ([][]int, error) {
-	executor := newExecutor()
-	output, err := executor.Run("diff", "--unified=0", "HEAD", "--", filepath)
+	hunks, err := getDiffHunks(filepath)
 	if err != nil {
 		return nil, err
 	}
 
 	var ranges [][]int
+	for _, h := range hunks {
+		start, count := h[2], h[3]
+		if start <= 0 || count <= 0 {
+			continue // 削除のみのhunk
+		}
+		if count == 1 {
+			// 単一行: +10
+			ranges = append(ranges, []int{start})
+		} else {
+			// 範囲: +10,5 (10行目から5行)
+			ranges = append(ranges, []int{start, start + count - 1})
+		}
+	}
+
+	return ranges, nil
+}
+
+// getDiffHunks はHEADと作業ツリーの差分のhunkヘッダを [old_start, old_count, new_start, new_count] で返します。
+func getDiffHunks(filepath string) ([][4]int, error) {
+	executor := newExecutor()
+	output, err := executor.Run("diff", "--unified=0", "HEAD", "--", filepath)
+	if err != nil {
+		return nil, err
+	}
+	return parseHunkHeaders(output), nil
+}
 
-	// @@ -1,2 +3,4 @@ 形式の行範囲を解析
-	for _, line := range strings.Split(output, "\n") {
+// parseHunkHeaders は "@@ -1,2 +3,4 @@" 形式のhunkヘッダを解析します。
+// 行数が省略された場合（"-5" や "+10"）は1行として扱います。
+func parseHunkHeaders(diffOutput string) [][4]int {
+	var hunks [][4]int
+	for _, line := range strings.Split(diffOutput, "\n") {
 		if !strings.HasPrefix(line, "@@") {
 			continue
 		}
 
-		// +3,4 の部分を抽出
 		parts := strings.Split(line, "@@")
 		if len(parts) < 2 {
 			continue
 		}
 
-		rangePart := strings.TrimSpace(parts[1])
-		plusIdx := strings.Index(rangePart, "+")
-		if plusIdx == -1 {
+		fields := strings.Fields(parts[1])
+		if len(fields) < 2 || !strings.HasPrefix(fields[0], "-") || !strings.HasPrefix(fields[1], "+") {
 			continue
 		}
 
-		rangeStr := strings.Fields(rangePart[plusIdx+1:])[0]
-		rangeNums := strings.Split(rangeStr, ",")
-
-		if len(rangeNums) == 1 {
-			// 単一行: +10
-			lineNum, err := strconv.Atoi(rangeNums[0])
-			if err == nil && lineNum > 0 {
-				ranges = append(ranges, []int{lineNum})
-			}
-		} else if len(rangeNums) == 2 {
-			// 範囲: +10,5 (10行目から5行)
-			start, err1 := strconv.Atoi(rangeNums[0])
-			count, err2 := strconv.Atoi(rangeNums[1])
-			if err1 == nil && err2 == nil && start > 0 && count > 0 {
-				ranges = append(ranges, []int{start, start + count - 1})
-			}
+		oldStart, oldCount, ok1 := parseHunkRange(fields[0][1:])
+		newStart, newCount, ok2 := parseHunkRange(fields[1][1:])
+		if !ok1 || !ok2 {
+			continue
 		}
+		hunks = append(hunks, [4]int{oldStart, oldCount, newStart, newCount})
 	}
+	return hunks
+}
 
-	return ranges, nil
+// parseHunkRange は "10,5" または "10" を開始行と行数に分解します。
+func parseHunkRange(s string) (start, count int, ok bool) {
+	startStr, countStr, hasCount := strings.Cut(s, ",")
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	if !hasCount {
+		return start, 1, true
+	}
+	count, err = strconv.Atoi(countStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, count, true
+}
+
+// capCheckpointHunks はチェックポイント全体のhunk数を limit 以下に抑えます。
+// ファイル名順に記録し、上限を超えるファイルはHunksを省略して HunksTruncated を立てます。
+func capCheckpointHunks(changes map[string]tracker.Change, limit int) {
+	files := getFileList(changes)
+	sort.Strings(files)
+
+	total := 0
+	for _, f := range files {
+		change := changes[f]
+		if len(change.Hunks) == 0 {
+			continue
+		}
+		if total+len(change.Hunks) > limit {
+			change.Hunks = nil
+			change.HunksTruncated = true
+			changes[f] = change
+			continue
+		}
+		total += len(change.Hunks)
+	}
 }
 
 // getFileList returns a list of filenames from changes map
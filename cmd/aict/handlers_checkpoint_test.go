@@ -1,8 +1,19 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/y-hirakaw/ai-code-tracker/internal/events"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/ignore"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
 	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
 )
 
@@ -119,3 +130,198 @@ func TestCheckpointValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordCheckpoint_PublishesEvent(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	// PUB のペイロードを受け取るNATSサーバーの代わり
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer ln.Close()
+	payloads := make(chan string, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			io.WriteString(conn, "INFO {}\r\n")
+			r := bufio.NewReader(conn)
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					break
+				}
+				if strings.HasPrefix(line, "PUB ") {
+					payload, _ := r.ReadString('\n')
+					payloads <- strings.TrimSpace(payload)
+				}
+				if line == "PING\r\n" {
+					io.WriteString(conn, "PONG\r\n")
+					break
+				}
+			}
+			conn.Close()
+		}
+	}()
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	cfg.Events = &tracker.EventsConfig{Bus: "nats", Address: ln.Addr().String()}
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+	<-payloads
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc main() {}\n")
+	if err := recordCheckpoint("Claude Code", "", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+	var ev events.Event
+	if err := json.Unmarshal([]byte(<-payloads), &ev); err != nil {
+		t.Fatalf("invalid event payload: %v", err)
+	}
+	if ev.Event != "checkpoint" || ev.Repo != filepath.Base(tmpDir) || ev.AuthorType != "ai" || ev.Files != 1 || ev.LinesAdded != 3 {
+		t.Errorf("event = %+v", ev)
+	}
+	if ev.Branch == "" {
+		t.Error("event branch should be set")
+	}
+
+	// バスが停止していてもチェックポイントは記録する
+	ln.Close()
+	testutil.CreateTestFile(t, tmpDir, "util.go", "package main\n")
+	if err := recordCheckpoint("Claude Code", "", ""); err != nil {
+		t.Errorf("recordCheckpoint() should not fail when the bus is unreachable: %v", err)
+	}
+	checkpoints, _ := store.LoadCheckpoints()
+	if len(checkpoints) != 3 {
+		t.Errorf("got %d checkpoints, want 3", len(checkpoints))
+	}
+}
+
+func TestAictignore_CheckpointAndReport(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, ignore.FileName, "third_party/\napi/**/*.pb.go\n!api/v1/keep.pb.go\n")
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	base := testutil.GitCommit(t, tmpDir, "initial")
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "third_party/lib/a.go", "package lib\n\nfunc A() {}\n")
+	testutil.CreateTestFile(t, tmpDir, "api/v1/user.pb.go", "package v1\n\ntype User struct{}\n")
+	testutil.CreateTestFile(t, tmpDir, "api/v1/keep.pb.go", "package v1\n")
+	testutil.CreateTestFile(t, tmpDir, "gen/old.go", "package gen\n\nvar X = 1\n")
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc main() {}\n")
+	if err := recordCheckpoint("Claude", "", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	checkpoints, _ := store.LoadCheckpoints()
+	changes := checkpoints[len(checkpoints)-1].Changes
+	for _, path := range []string{"third_party/lib/a.go", "api/v1/user.pb.go"} {
+		if _, ok := changes[path]; ok {
+			t.Errorf("checkpoint should not record ignored file %s", path)
+		}
+	}
+	for _, path := range []string{"api/v1/keep.pb.go", "gen/old.go", "main.go"} {
+		if _, ok := changes[path]; !ok {
+			t.Errorf("checkpoint should record %s, got %v", path, changes)
+		}
+	}
+	if len(cfg.IgnorePatterns) == 0 {
+		t.Error("LoadConfig should read .aictignore")
+	}
+
+	testutil.GitCommit(t, tmpDir, "Add files")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	// 記録後に追加したパターンもレポートに反映される
+	testutil.CreateTestFile(t, tmpDir, ignore.FileName, "third_party/\napi/**/*.pb.go\n!api/v1/keep.pb.go\n/gen/\n")
+	report, _, err := generateRangeReport(&ReportOptions{Range: base + "..HEAD"})
+	if err != nil {
+		t.Fatalf("generateRangeReport() error = %v", err)
+	}
+	// main.go（3行）と keep.pb.go（1行）のみ
+	if report.Summary.TotalLines != 4 {
+		t.Errorf("TotalLines = %d, want 4", report.Summary.TotalLines)
+	}
+}
+
+// TestHandleCheckpoint_Weight は --weight で記録したAIへの帰属割合がAuthorship Logと集計に反映されることを検証する
+func TestHandleCheckpoint_Weight(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	originalArgs := os.Args
+	defer func() { os.Args = originalArgs }()
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	base := testutil.GitCommit(t, tmpDir, "initial")
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+
+	os.Args = []string{"aict", "checkpoint", "--weight", "1.5"}
+	if err := handleCheckpoint(); err == nil {
+		t.Error("handleCheckpoint() should reject --weight outside 0-1")
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "pair.go", "package main\n\nfunc a() {}\nfunc b() {}\n")
+	os.Args = []string{"aict", "checkpoint", "--author", "human", "--weight", "0.5"}
+	if err := handleCheckpoint(); err != nil {
+		t.Fatalf("handleCheckpoint() error = %v", err)
+	}
+	testutil.GitCommit(t, tmpDir, "pair programming")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	alog, err := gitnotes.NewNotesManager().GetAuthorshipLog("HEAD")
+	if err != nil || alog == nil {
+		t.Fatalf("GetAuthorshipLog() = %v, %v", alog, err)
+	}
+	author := alog.Files["pair.go"].Authors[0]
+	if author.Type != tracker.AuthorTypeHuman || author.AIWeight == nil || *author.AIWeight != 0.5 {
+		t.Errorf("recorded author = %+v, want human with ai_weight 0.5", author)
+	}
+
+	result, _, err := collectAuthorStats(base+"..HEAD", 1, nil)
+	if err != nil {
+		t.Fatalf("collectAuthorStats() error = %v", err)
+	}
+	if result.totalAI != 2 || result.totalHuman != 2 {
+		t.Errorf("totals = %d/%d, want 2/2", result.totalAI, result.totalHuman)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"reflect"
 	"os/exec"
 	"testing"
 
@@ -435,3 +436,79 @@ func TestDetectChangesFromSnapshot(t *testing.T) {
 		})
 	}
 }
+
+func TestParseHunkHeaders(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want [][4]int
+	}{
+		{
+			name: "ranges and single lines",
+			diff: "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -3,2 +3,4 @@ func a() {\n-x\n+y\n@@ -10 +12 @@\n-z\n+w\n",
+			want: [][4]int{{3, 2, 3, 4}, {10, 1, 12, 1}},
+		},
+		{
+			name: "deletion only",
+			diff: "@@ -5,3 +4,0 @@\n-a\n-b\n-c\n",
+			want: [][4]int{{5, 3, 4, 0}},
+		},
+		{
+			name: "malformed header ignored",
+			diff: "@@ bogus @@\n@@ -1,x +1 @@\n",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseHunkHeaders(tt.diff); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseHunkHeaders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCapCheckpointHunks(t *testing.T) {
+	changes := map[string]tracker.Change{
+		"a.go": {Added: 3, Hunks: [][4]int{{1, 0, 1, 1}, {5, 0, 6, 2}}},
+		"b.go": {Added: 4, Hunks: [][4]int{{1, 0, 1, 1}, {3, 0, 3, 1}, {8, 0, 9, 2}}},
+		"c.go": {Added: 1, Hunks: [][4]int{{2, 0, 2, 1}}},
+		"d.go": {Deleted: 2},
+	}
+
+	capCheckpointHunks(changes, 3)
+
+	if len(changes["a.go"].Hunks) != 2 || changes["a.go"].HunksTruncated {
+		t.Errorf("a.go should keep its hunks: %+v", changes["a.go"])
+	}
+	if changes["b.go"].Hunks != nil || !changes["b.go"].HunksTruncated {
+		t.Errorf("b.go should be truncated: %+v", changes["b.go"])
+	}
+	if len(changes["c.go"].Hunks) != 1 || changes["c.go"].HunksTruncated {
+		t.Errorf("c.go fits under the cap and should keep its hunks: %+v", changes["c.go"])
+	}
+	if changes["d.go"].HunksTruncated {
+		t.Errorf("d.go has no hunks and should not be marked truncated: %+v", changes["d.go"])
+	}
+}
+
+func TestDetectChangesFromSnapshot_Hunks(t *testing.T) {
+	last := &tracker.CheckpointV2{Snapshot: map[string]tracker.FileSnapshot{
+		"removed.go": {Hash: "old", Lines: 4},
+	}}
+	current := map[string]tracker.FileSnapshot{
+		"new.go": {Hash: "new", Lines: 7},
+	}
+
+	changes, err := detectChangesFromSnapshot(last, current)
+	if err != nil {
+		t.Fatalf("detectChangesFromSnapshot() error = %v", err)
+	}
+	if got := changes["new.go"].Hunks; !reflect.DeepEqual(got, [][4]int{{0, 0, 1, 7}}) {
+		t.Errorf("new.go hunks = %v", got)
+	}
+	if got := changes["removed.go"].Hunks; !reflect.DeepEqual(got, [][4]int{{1, 4, 0, 0}}) {
+		t.Errorf("removed.go hunks = %v", got)
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/blame"
+	"github.com/y-hirakaw/ai-code-tracker/internal/git"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/ownership"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// churn の集計期間
+const (
+	churnPeriodDay   = "day"
+	churnPeriodWeek  = "week"
+	churnPeriodMonth = "month"
+)
+
+// churnCommit は期間集計とAI行の残存率の確認に使うコミット1件分の情報です。
+type churnCommit struct {
+	hash    string
+	date    time.Time
+	aiAdded int
+	alog    *tracker.AuthorshipLog
+}
+
+// handleChurn handles the churn command
+// 期間ごとのAI・人間の追加/削除/純増行数と、AIが追加した行のN日後の残存率を表示します。
+func handleChurn() error {
+	fs := flag.NewFlagSet("churn", flag.ExitOnError)
+	rangeFlag := fs.String("range", "", "Commit range (e.g., 'origin/main..HEAD')")
+	since := fs.String("since", "", "Analyze commits since date (e.g., '90d', '2025-01-01'; default: 90d)")
+	period := fs.String("period", churnPeriodWeek, "Aggregation period: day, week or month")
+	survivalDays := fs.Int("survival-days", 30, "Measure how many AI-added lines remain this many days after their commit (0 = skip)")
+	survivalSample := fs.Int("survival-sample", 50, "Maximum number of commits to check with git blame for the survival rate")
+	format := fs.String("format", "table", "Output format: table or json")
+	fields := fs.String("fields", "", fieldsFlagUsage)
+	fs.Parse(os.Args[2:])
+
+	if *rangeFlag != "" && *since != "" {
+		return fmt.Errorf("--range and --since are mutually exclusive")
+	}
+	switch *period {
+	case churnPeriodDay, churnPeriodWeek, churnPeriodMonth:
+	default:
+		return fmt.Errorf("unknown period: %s (available: day, week, month)", *period)
+	}
+	if *survivalDays < 0 || *survivalSample < 1 {
+		return fmt.Errorf("--survival-days must not be negative and --survival-sample must be at least 1")
+	}
+	if *format != "table" && *format != "json" {
+		return fmt.Errorf("unknown format: %s (available: table, json)", *format)
+	}
+	if *fields != "" && *format != "json" {
+		return fmt.Errorf("--fields requires --format json")
+	}
+
+	rangeSpec := *rangeFlag
+	if rangeSpec == "" {
+		sinceValue := *since
+		if sinceValue == "" {
+			sinceValue = "90d"
+		}
+		spec, err := convertSinceToRange(sinceValue)
+		if err != nil {
+			if strings.Contains(err.Error(), "no commits found") {
+				fmt.Printf("No commits found since %s\n", sinceValue)
+				return nil
+			}
+			return err
+		}
+		rangeSpec = spec
+	}
+	ensureRangeHistory(rangeSpec)
+
+	report, commits, err := collectChurn(rangeSpec, *period)
+	if err != nil {
+		return err
+	}
+	if *survivalDays > 0 {
+		report.Survival = measureSurvival(commits, *survivalDays, *survivalSample, time.Now())
+	}
+
+	if *format == "json" {
+		data, err := marshalJSONFields(report, *fields)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	printChurnReport(report)
+	return nil
+}
+
+// collectChurn はコミット範囲のAuthorship Logとnumstatを期間ごとに集計します。
+// 行数の按分・ai_weight・設定の overrides・除外パターン・artifacts の扱いは aict report と同じです。
+func collectChurn(rangeSpec, period string) (*tracker.ChurnReport, []churnCommit, error) {
+	executor := newExecutor()
+	allNumstats, hashes, err := git.GetRangeNumstat(executor, rangeSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+	dates, err := getCommitDates(rangeSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+	allLogs, _ := gitnotes.NewNotesManagerWithExecutor(executor).GetAuthorshipLogsForRange(rangeSpec)
+	var cfg *tracker.Config
+	if _, loaded, err := loadStorageAndConfig(); err == nil {
+		cfg = loaded
+	}
+	overrides, err := newOverrideEngine(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	includePath := excludeIgnored(excludeArtifacts(nil, cfg), cfg)
+
+	report := &tracker.ChurnReport{Range: rangeSpec, Period: period, Commits: len(hashes)}
+	byStart := make(map[string]*tracker.ChurnPeriod)
+	var commits []churnCommit
+	for _, hash := range hashes {
+		date, ok := dates[hash]
+		if !ok {
+			continue
+		}
+		start := churnPeriodStart(date, period)
+		p, exists := byStart[start]
+		if !exists {
+			p = &tracker.ChurnPeriod{Start: start}
+			byStart[start] = p
+		}
+		p.Commits++
+
+		alog, numstat := allLogs[hash], allNumstats[hash]
+		if alog == nil || numstat == nil {
+			continue
+		}
+		result := &authorStatsResult{byAuthor: make(map[string]*tracker.AuthorStats), includePath: includePath, overrides: overrides}
+		if overrides != nil {
+			result.override = overrides.forCommit(hash, alog.Timestamp)
+		}
+		applyLineCounters(numstat, hash, func(path string) bool { _, ok := alog.Files[path]; return ok })
+		processCommitFiles(result, alog, numstat)
+
+		wv := result.detailedMetrics.WorkVolume
+		p.AIAdded += wv.AIAdded
+		p.AIDeleted += wv.AIDeleted
+		p.HumanAdded += wv.HumanAdded
+		p.HumanDeleted += wv.HumanDeleted
+		commits = append(commits, churnCommit{hash: hash, date: date, aiAdded: wv.AIAdded, alog: alog})
+	}
+
+	for _, p := range byStart {
+		p.AINet = p.AIAdded - p.AIDeleted
+		p.HumanNet = p.HumanAdded - p.HumanDeleted
+		report.Total.Commits += p.Commits
+		report.Total.AIAdded += p.AIAdded
+		report.Total.AIDeleted += p.AIDeleted
+		report.Total.HumanAdded += p.HumanAdded
+		report.Total.HumanDeleted += p.HumanDeleted
+		report.Periods = append(report.Periods, *p)
+	}
+	report.Total.AINet = report.Total.AIAdded - report.Total.AIDeleted
+	report.Total.HumanNet = report.Total.HumanAdded - report.Total.HumanDeleted
+	sort.Slice(report.Periods, func(i, j int) bool { return report.Periods[i].Start < report.Periods[j].Start })
+	return report, commits, nil
+}
+
+// getCommitDates はコミット範囲の各コミットのコミット日時を返します。
+func getCommitDates(rangeSpec string) (map[string]time.Time, error) {
+	output, err := newExecutor().Run("log", "--format=%H %cI", "--end-of-options", rangeSpec)
+	if err != nil {
+		return nil, err
+	}
+	dates := make(map[string]time.Time)
+	for _, line := range strings.Split(output, "\n") {
+		hash, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			dates[hash] = t
+		}
+	}
+	return dates, nil
+}
+
+// churnPeriodStart はコミット日時が属する期間の開始日を返します（week は月曜始まり）。
+func churnPeriodStart(t time.Time, period string) string {
+	switch period {
+	case churnPeriodMonth:
+		return t.Format("2006-01")
+	case churnPeriodWeek:
+		offset := (int(t.Weekday()) + 6) % 7 // 月曜=0
+		return t.AddDate(0, 0, -offset).Format("2006-01-02")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// measureSurvival は days 日以上前のコミットでAIが追加した行のうち、days 日後のツリーに残っている行の割合を求めます。
+// 対象コミットが maxSample 件を超える場合はコミットハッシュで決定的にサンプリングします。
+// 残っている行は days 日後の最新コミットで git blame し、そのコミットが変更元でAIの作成者の行を数えます。
+func measureSurvival(commits []churnCommit, days, maxSample int, now time.Time) *tracker.SurvivalStats {
+	stats := &tracker.SurvivalStats{Days: days}
+	var eligible []string
+	byHash := make(map[string]churnCommit)
+	for _, c := range commits {
+		if c.aiAdded > 0 && !c.date.AddDate(0, 0, days).After(now) {
+			eligible = append(eligible, c.hash)
+			byHash[c.hash] = c
+		}
+	}
+	stats.EligibleCommits = len(eligible)
+	if len(eligible) == 0 {
+		return stats
+	}
+
+	sampled := eligible
+	if len(eligible) > maxSample {
+		sampled = sampleCommits(eligible, float64(maxSample)/float64(len(eligible)))
+	}
+
+	executor := newExecutor()
+	logs := ownership.NewLogCache(gitnotes.NewNotesManagerWithExecutor(executor))
+	logs.Debugf = debugf
+	for _, hash := range sampled {
+		c := byHash[hash]
+		at := c.date.AddDate(0, 0, days).Format(time.RFC3339)
+		rev, err := executor.Run("rev-list", "-1", "--before="+at, "HEAD")
+		if err != nil || rev == "" {
+			debugf("survival: no commit %d days after %s", days, shortCommit(hash))
+			continue
+		}
+
+		var requests []blame.Request
+		for path, info := range c.alog.Files {
+			for i := range info.Authors {
+				if info.Authors[i].AIShare() > 0 {
+					requests = append(requests, blame.Request{Path: path})
+					break
+				}
+			}
+		}
+		sort.Slice(requests, func(i, j int) bool { return requests[i].Path < requests[j].Path })
+
+		var surviving float64
+		_ = blame.Stream(executor, rev, requests, blame.DefaultWorkers(), func(r blame.Result) error {
+			if r.Err != nil {
+				debugf("survival: %s not found at %s: %v", r.Path, shortCommit(rev), r.Err)
+				return nil // 削除・リネームされたファイルの行は残っていないものとする
+			}
+			for _, origin := range r.Origins {
+				if origin.Commit != hash {
+					continue
+				}
+				if author := logs.Author(origin); author != nil {
+					surviving += author.AIShare()
+				}
+			}
+			return nil
+		})
+
+		stats.SampledCommits++
+		stats.AIAdded += c.aiAdded
+		stats.AISurviving += min(int(surviving+0.5), c.aiAdded)
+	}
+	stats.Rate = metrics.SafePercent(stats.AISurviving, stats.AIAdded)
+	return stats
+}
+
+// printChurnReport は期間ごとの追加・削除・純増行数と残存率をテーブルで表示します。
+func printChurnReport(report *tracker.ChurnReport) {
+	fmt.Printf("AI Code Churn Report (%s, by %s)\n\n", report.Range, report.Period)
+	fmt.Printf("Commits: %d\n", report.Commits)
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("  %-12s %7s %8s %8s %8s %8s %8s %9s\n", "Period", "Commits", "AI +", "AI -", "AI net", "Human +", "Human -", "Human net")
+	for _, p := range append(report.Periods, report.Total) {
+		label := p.Start
+		if label == "" {
+			label = "Total"
+		}
+		fmt.Printf("  %-12s %7d %8d %8d %8d %8d %8d %9d\n", label, p.Commits, p.AIAdded, p.AIDeleted, p.AINet, p.HumanAdded, p.HumanDeleted, p.HumanNet)
+	}
+	fmt.Println()
+
+	if s := report.Survival; s != nil {
+		if s.SampledCommits == 0 {
+			fmt.Printf("AI line survival after %d days: n/a (no commits with AI lines older than %d days)\n", s.Days, s.Days)
+			return
+		}
+		fmt.Printf("AI line survival after %d days: %.1f%% (%d of %d lines, %d of %d commits checked)\n",
+			s.Days, s.Rate, s.AISurviving, s.AIAdded, s.SampledCommits, s.EligibleCommits)
+	}
+}
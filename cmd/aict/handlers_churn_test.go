@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestChurnPeriodStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		date   string
+		period string
+		want   string
+	}{
+		{"day", "2025-01-15T10:00:00+09:00", churnPeriodDay, "2025-01-15"},
+		{"week_水曜", "2025-01-15T10:00:00+09:00", churnPeriodWeek, "2025-01-13"},
+		{"week_月曜", "2025-01-13T00:00:00+09:00", churnPeriodWeek, "2025-01-13"},
+		{"week_日曜は前の月曜", "2025-01-19T23:00:00+09:00", churnPeriodWeek, "2025-01-13"},
+		{"week_月をまたぐ", "2025-03-01T10:00:00Z", churnPeriodWeek, "2025-02-24"},
+		{"month", "2025-01-31T10:00:00Z", churnPeriodMonth, "2025-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, err := time.Parse(time.RFC3339, tt.date)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := churnPeriodStart(date, tt.period); got != tt.want {
+				t.Errorf("churnPeriodStart(%s, %s) = %s, want %s", tt.date, tt.period, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCollectChurnAndSurvival はAIの追加行を人間が一部削除した範囲の期間集計と残存率を検証する
+func TestCollectChurnAndSurvival(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	base := testutil.GitCommit(t, tmpDir, "initial")
+
+	// AIが4行追加
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc a() {}\nfunc b() {}\n")
+	if err := recordCheckpoint("Claude", "", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+	testutil.GitCommit(t, tmpDir, "AI adds main.go")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	// 人間がAIの2行を削除
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\n")
+	testutil.GitCommit(t, tmpDir, "human removes functions")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	report, commits, err := collectChurn(base+"..HEAD", churnPeriodMonth)
+	if err != nil {
+		t.Fatalf("collectChurn() error = %v", err)
+	}
+	if report.Commits != 2 || len(report.Periods) != 1 {
+		t.Fatalf("report = %+v, want 2 commits in 1 period", report)
+	}
+	total := report.Total
+	if total.AIAdded != 4 || total.AIDeleted != 0 || total.AINet != 4 || total.HumanDeleted != 2 || total.HumanNet != -2 {
+		t.Errorf("total = %+v, want AI +4/-0 (net 4), human -2 (net -2)", total)
+	}
+	period := report.Periods[0]
+	period.Start = ""
+	if period != total {
+		t.Errorf("period = %+v, want the same as total %+v", report.Periods[0], total)
+	}
+
+	// 1日後のツリー（現在のHEAD）にはAIの4行のうち2行が残っている
+	survival := measureSurvival(commits, 1, 50, time.Now().AddDate(0, 0, 2))
+	if survival.EligibleCommits != 1 || survival.SampledCommits != 1 || survival.AIAdded != 4 || survival.AISurviving != 2 || survival.Rate != 50 {
+		t.Errorf("survival = %+v, want 2 of 4 lines (50%%) from 1 commit", survival)
+	}
+
+	// まだN日経っていないコミットは対象外
+	if s := measureSurvival(commits, 30, 50, time.Now()); s.EligibleCommits != 0 || s.SampledCommits != 0 {
+		t.Errorf("survival for recent commits = %+v, want none eligible", s)
+	}
+}
+
+func TestHandleChurn_FieldsRequiresJSON(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"aict", "churn", "--fields", "periods"}
+	if err := handleChurn(); err == nil || !strings.Contains(err.Error(), "--fields requires --format json") {
+		t.Errorf("handleChurn() error = %v, want --fields requires --format json", err)
+	}
+}
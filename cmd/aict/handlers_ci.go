@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// zeroCommit はpushイベントの before がブランチの新規作成を表す値です。
+const zeroCommit = "0000000000000000000000000000000000000000"
+
+// handleCI は CI（GitHub Actions）向けに、準備・範囲の特定・集計・結果の出力をまとめて行います。
+//   - .git/aict の設定がなければデフォルト設定（または --config のファイル）で初期化
+//   - origin から Authorship Log（refs/aict/authorship）とPRのベースブランチを取得
+//   - --range/--since 未指定時は GitHub Actions のイベントから範囲を決定（PR: origin/<base>..HEAD、push: before..after）
+//   - aict check と同じアノテーション・ジョブサマリーに加え、$GITHUB_OUTPUT に集計値を出力
+//
+// aict check と異なり、閾値（--min-ai / --max-ai）を指定しない場合は失敗しません。
+func handleCI() error {
+	fs := flag.NewFlagSet("ci", flag.ExitOnError)
+	opts := &ReportOptions{}
+	fs.StringVar(&opts.Range, "range", "", "Commit range (default: detected from the GitHub Actions event)")
+	fs.StringVar(&opts.Since, "since", "", "Report commits since date (e.g., '7d', '2025-01-01')")
+	fs.StringVar(&opts.Context, "context", "", "Report only files in the named context (config \"contexts\")")
+	configFile := fs.String("config", "", "Config file (JSON or YAML) to use instead of .git/aict/config")
+	minAI := fs.Float64("min-ai", -1, "Fail when the AI percentage is below this value")
+	maxAI := fs.Float64("max-ai", -1, "Fail when the AI percentage is above this value")
+	annotateFiles := fs.Float64("annotate-files", -1, annotateFilesUsage)
+	noFetch := fs.Bool("no-fetch", false, "Do not fetch authorship logs and the base branch from origin")
+	fs.Parse(os.Args[2:])
+
+	if opts.Range != "" && opts.Since != "" {
+		return fmt.Errorf("--range and --since are mutually exclusive")
+	}
+	th := checkThresholds{Min: *minAI, Max: *maxAI}
+	if th.Min > 100 || th.Max > 100 {
+		return fmt.Errorf("--min-ai and --max-ai must be between 0 and 100")
+	}
+	if th.Min >= 0 && th.Max >= 0 && th.Min > th.Max {
+		return fmt.Errorf("--min-ai (%.1f) must not exceed --max-ai (%.1f)", th.Min, th.Max)
+	}
+	if *annotateFiles > 100 {
+		return fmt.Errorf("--annotate-files must be between 0 and 100, got %.1f", *annotateFiles)
+	}
+	opts.ByFile = *annotateFiles >= 0
+
+	if err := prepareCIStorage(*configFile); err != nil {
+		return err
+	}
+
+	baseRef := os.Getenv("GITHUB_BASE_REF")
+	if !*noFetch {
+		fetchCIRefs(baseRef)
+	}
+
+	if opts.Range == "" && opts.Since == "" {
+		rangeSpec, err := detectCIRange(baseRef)
+		if err != nil {
+			return err
+		}
+		opts.Range = rangeSpec
+	}
+
+	var report *tracker.Report
+	if opts.Since != "" {
+		rangeSpec, err := convertSinceToRange(opts.Since)
+		if err != nil && !strings.Contains(err.Error(), "no commits found") {
+			return err
+		}
+		opts.Range = rangeSpec
+	}
+	if opts.Range != "" {
+		var err error
+		if report, _, err = generateRangeReport(opts); err != nil {
+			return err
+		}
+	}
+
+	var violations []string
+	if report != nil {
+		violations = th.violations(report.Summary.AIPercentage)
+	}
+	emitCheckResult(report, th, violations)
+	emitFileAnnotations(report, *annotateFiles)
+
+	if outputPath := os.Getenv("GITHUB_OUTPUT"); outputPath != "" {
+		if err := appendGitHubOutputs(outputPath, ciOutputs(report, violations)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write step outputs: %v\n", err)
+		}
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%s", strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// prepareCIStorage は .git/aict の設定を用意します。
+// configFile を指定した場合はその内容で上書きし、設定がなければデフォルト設定を保存します
+// （CIのチェックアウトには .git/aict がないため。フックは設定しません）。
+func prepareCIStorage(configFile string) error {
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		return fmt.Errorf("initializing storage: %w", err)
+	}
+
+	if configFile != "" {
+		data, err := os.ReadFile(configFile)
+		if err != nil {
+			return fmt.Errorf("reading config: %w", err)
+		}
+		cfg, err := storage.ParseConfigStrict(data, storage.IsYAMLConfigPath(configFile))
+		if err != nil {
+			return fmt.Errorf("invalid config %s: %w", configFile, err)
+		}
+		if err := store.SaveConfig(cfg); err != nil {
+			return fmt.Errorf("saving config: %w", err)
+		}
+		fmt.Printf("✓ Using config %s\n", configFile)
+		return nil
+	}
+
+	if _, err := os.Stat(store.ConfigPath()); os.IsNotExist(err) {
+		if err := store.SaveConfig(newDefaultConfig()); err != nil {
+			return fmt.Errorf("saving config: %w", err)
+		}
+		fmt.Println("✓ No .git/aict config found; using the default configuration")
+	}
+	return nil
+}
+
+// fetchCIRefs は origin から Authorship Log と（PRの場合は）ベースブランチを取得します。
+// 失敗しても集計は続けるため警告のみ出力します。
+func fetchCIRefs(baseRef string) {
+	executor := newExecutor()
+
+	if out, err := executor.Run("rev-parse", "--is-shallow-repository"); err == nil && out == "true" {
+		fmt.Fprintln(os.Stderr, "Warning: shallow clone detected; use actions/checkout with 'fetch-depth: 0' so the commit range can be resolved")
+	}
+
+	if err := gitnotes.NewNotesManagerWithExecutor(executor).Fetch(defaultNotesRemote); err != nil && !errors.Is(err, gitnotes.ErrNoRemoteNotes) {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch authorship logs: %v\n", err)
+	}
+	if baseRef != "" {
+		baseRefspec := "+refs/heads/" + baseRef + ":refs/remotes/origin/" + baseRef
+		if _, err := executor.Run("fetch", "--no-tags", "origin", baseRefspec); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to fetch base branch %s: %v\n", baseRef, err)
+		}
+	}
+}
+
+// detectCIRange は GitHub Actions のイベントから集計するコミット範囲を決定します。
+// pull_request では origin/<base>..HEAD、push では before..after（ブランチの新規作成時を除く）を返します。
+func detectCIRange(baseRef string) (string, error) {
+	if baseRef != "" {
+		return "origin/" + baseRef + "..HEAD", nil
+	}
+
+	if os.Getenv("GITHUB_EVENT_NAME") == "push" {
+		if eventPath := os.Getenv("GITHUB_EVENT_PATH"); eventPath != "" {
+			data, err := os.ReadFile(eventPath)
+			if err != nil {
+				return "", fmt.Errorf("reading GitHub event: %w", err)
+			}
+			var event struct {
+				Before string `json:"before"`
+				After  string `json:"after"`
+			}
+			if err := json.Unmarshal(data, &event); err != nil {
+				return "", fmt.Errorf("parsing GitHub event: %w", err)
+			}
+			if event.Before != "" && event.Before != zeroCommit && event.After != "" {
+				return event.Before + ".." + event.After, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("cannot determine the commit range from the CI environment; pass --range or --since (e.g., aict ci --range origin/main..HEAD)")
+}
+
+// ciOutputs は後続のステップで使う集計値を出力名の順に返します。
+func ciOutputs(report *tracker.Report, violations []string) [][2]string {
+	result := "passed"
+	switch {
+	case report == nil:
+		return [][2]string{{"result", "skipped"}, {"commits", "0"}}
+	case len(violations) > 0:
+		result = "failed"
+	}
+	return [][2]string{
+		{"result", result},
+		{"range", report.Range},
+		{"commits", fmt.Sprintf("%d", report.Commits)},
+		{"ai-lines", fmt.Sprintf("%d", report.Summary.AILines)},
+		{"human-lines", fmt.Sprintf("%d", report.Summary.HumanLines)},
+		{"total-lines", fmt.Sprintf("%d", report.Summary.TotalLines)},
+		{"ai-percentage", fmt.Sprintf("%.1f", report.Summary.AIPercentage)},
+	}
+}
+
+// appendGitHubOutputs は "name=value" の形式で $GITHUB_OUTPUT に追記します。
+func appendGitHubOutputs(path string, outputs [][2]string) error {
+	var b strings.Builder
+	for _, o := range outputs {
+		fmt.Fprintf(&b, "%s=%s\n", o[0], strings.ReplaceAll(o[1], "\n", " "))
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(b.String())
+	return err
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestDetectCIRange(t *testing.T) {
+	eventDir := t.TempDir()
+	writeEvent := func(name, content string) string {
+		path := filepath.Join(eventDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	pushEvent := writeEvent("push.json", `{"before":"abc123","after":"def456"}`)
+	newBranchEvent := writeEvent("new_branch.json", `{"before":"`+zeroCommit+`","after":"def456"}`)
+
+	tests := []struct {
+		name      string
+		baseRef   string
+		eventName string
+		eventPath string
+		want      string
+		wantErr   bool
+	}{
+		{"pull request", "main", "pull_request", "", "origin/main..HEAD", false},
+		{"push", "", "push", pushEvent, "abc123..def456", false},
+		{"new branch push", "", "push", newBranchEvent, "", true},
+		{"push without event file", "", "push", "", "", true},
+		{"outside GitHub Actions", "", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_EVENT_NAME", tt.eventName)
+			t.Setenv("GITHUB_EVENT_PATH", tt.eventPath)
+
+			got, err := detectCIRange(tt.baseRef)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("detectCIRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("detectCIRange() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepareCIStorage(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	// 設定がなければデフォルト設定で初期化
+	if err := prepareCIStorage(""); err != nil {
+		t.Fatalf("prepareCIStorage() error = %v", err)
+	}
+	_, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	if cfg.TargetAIPercentage != 80 {
+		t.Errorf("TargetAIPercentage = %.1f, want default 80", cfg.TargetAIPercentage)
+	}
+
+	// --config のファイルで上書き
+	testutil.CreateTestFile(t, tmpDir, ".aict.yaml", "target_ai_percentage: 40\ntracked_extensions: [.go]\ndefault_author: ci\n")
+	if err := prepareCIStorage(".aict.yaml"); err != nil {
+		t.Fatalf("prepareCIStorage(.aict.yaml) error = %v", err)
+	}
+	_, cfg, _ = loadStorageAndConfig()
+	if cfg.TargetAIPercentage != 40 {
+		t.Errorf("TargetAIPercentage = %.1f, want 40 from --config", cfg.TargetAIPercentage)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "bad.yaml", "target_ai_percntage: 40\n")
+	if err := prepareCIStorage("bad.yaml"); err == nil {
+		t.Error("prepareCIStorage() should reject unknown keys")
+	}
+}
+
+func TestHandleCI(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	base := testutil.GitCommit(t, tmpDir, "initial")
+	testutil.CreateTestFile(t, tmpDir, "a.go", "package main\n\nfunc a() {}\n")
+	testutil.GitCommit(t, tmpDir, "Add a")
+	if err := prepareCIStorage(""); err != nil {
+		t.Fatalf("prepareCIStorage() error = %v", err)
+	}
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	summaryPath := filepath.Join(t.TempDir(), "summary.md")
+	outputPath := filepath.Join(t.TempDir(), "output")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_STEP_SUMMARY", summaryPath)
+	t.Setenv("GITHUB_OUTPUT", outputPath)
+	t.Setenv("GITHUB_BASE_REF", "")
+	t.Setenv("GITHUB_EVENT_NAME", "")
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantErr     bool
+		wantText    string
+		wantOutputs []string
+	}{
+		// フックなしのコミットは人間の変更として記録されるためAI率は0%
+		{"report only", []string{"--range", base + "..HEAD"}, false, "AI percentage 0.0%25 across 1 commits",
+			[]string{"result=passed", "commits=1", "human-lines=3", "ai-percentage=0.0"}},
+		{"violates min", []string{"--range", base + "..HEAD", "--min-ai", "10"}, true, "::error title=AI Code Tracker::",
+			[]string{"result=failed"}},
+		{"no range outside Actions", nil, true, "", nil},
+		{"range and since", []string{"--range", base + "..HEAD", "--since", "7d"}, true, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(outputPath)
+			os.Args = append([]string{"aict", "ci", "--no-fetch"}, tt.args...)
+			origStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := handleCI()
+
+			w.Close()
+			os.Stdout = origStdout
+			var buf bytes.Buffer
+			buf.ReadFrom(r)
+			output := buf.String()
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handleCI() error = %v, wantErr %v\n%s", err, tt.wantErr, output)
+			}
+			if tt.wantText != "" && !strings.Contains(output, tt.wantText) {
+				t.Errorf("output missing %q:\n%s", tt.wantText, output)
+			}
+			data, _ := os.ReadFile(outputPath)
+			for _, want := range tt.wantOutputs {
+				if !strings.Contains(string(data), want+"\n") {
+					t.Errorf("step outputs missing %q:\n%s", want, data)
+				}
+			}
+		})
+	}
+
+	data, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatalf("step summary not written: %v", err)
+	}
+	if !strings.Contains(string(data), "| none | ✅ Passed |") {
+		t.Errorf("step summary should show a passed run without thresholds:\n%s", data)
+	}
+}
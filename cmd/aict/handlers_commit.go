@@ -37,6 +37,12 @@ func handleCommit() error {
 
 	// numstatから変更されたファイル一覧を取得
 	numstatMap, _ := git.ParseNumstat(numstatOutput)
+	if cfg != nil && !cfg.IncludeGenerated {
+		excludeGeneratedFiles(numstatMap, commitHash, func(path string) bool { return tracker.IsRecordedFile(path, cfg) })
+	}
+	if cfg != nil {
+		applyLineCounters(numstatMap, commitHash, func(path string) bool { return tracker.IsTrackedFile(path, cfg) })
+	}
 	changedFiles := make(map[string]bool, len(numstatMap))
 	for f := range numstatMap {
 		changedFiles[f] = true
@@ -47,6 +53,9 @@ func handleCommit() error {
 		if store != nil && cfg != nil {
 			_ = store.PurgeExpiredCheckpoints(cfg.GetCheckpointTTL())
 		}
+		if store != nil {
+			recordDailyRollup(store, commitHash, nil, nil)
+		}
 		return nil
 	}
 
@@ -74,6 +83,9 @@ func handleCommit() error {
 	// コミット親のファイルハッシュを取得（Phase 2 照合用）
 	parentSnapshot := buildParentFileHashes(commitHash, changedFiles)
 
+	// 大文字小文字だけが異なるパスをコミットの表記に揃えてから照合
+	canonicalizeCheckpointPaths(checkpoints, changedFiles, gitIgnoresCase())
+
 	// チェックポイントから作成者マッピングを構築
 	authorshipMap := authorship.BuildAuthorshipMap(checkpoints, changedFiles, parentSnapshot)
 
@@ -83,11 +95,24 @@ func handleCommit() error {
 		debugf("  %s -> %s (%s)", filepath, cp.Author, cp.Type)
 	}
 
+	// コミットパターン（Aider等）に一致するコミットはフックなしでもAIの変更として記録
+	// （チェックポイントの消費判定には元の authorshipMap を使う）
+	logAuthors := applyCommitPatterns(authorshipMap, changedFiles, commitHash, cfg)
+
 	// 完全な差分情報と作成者情報を統合してAuthorship Logを生成
-	log, err := authorship.BuildAuthorshipLogFromDiff(fullDiff, authorshipMap, commitHash, changedFiles, cfg)
+	log, err := authorship.BuildAuthorshipLogFromDiff(fullDiff, logAuthors, commitHash, changedFiles, cfg)
 	if err != nil {
 		return fmt.Errorf("building authorship log: %w", err)
 	}
+	// マージコミットでコンフリクトを解消したファイルは、解消した行だけを解消したチェックポイントの作成者に帰属
+	applyConflictResolutions(executor, log, logAuthors, cfg)
+	// 削除・変更した既存行を誰が書いた行か記録（AIのコードを人間が修正した行などの集計用）
+	recordRewrittenLines(executor, log)
+
+	// 設定の redaction に従ってパス・メッセージの秘密情報らしき文字列を伏せる（照合後、保存前）
+	if n := redactAuthorshipLog(store, cfg, log); n > 0 {
+		fmt.Printf("Redacted %d path(s)/message(s) (see 'aict debug redactions')\n", n)
+	}
 
 	// バリデーション
 	if err := authorship.ValidateAuthorshipLog(log); err != nil {
@@ -95,11 +120,29 @@ func handleCommit() error {
 	}
 
 	// Git notesに保存
-	nm := gitnotes.NewNotesManager()
+	nm := gitnotes.NewNotesManagerWithExecutor(newExecutor())
 	if err := nm.AddAuthorshipLog(log); err != nil {
 		return fmt.Errorf("saving authorship log: %w", err)
 	}
 
+	// 期間レポート高速化のための日次集計を更新（前後の直近7日間のAI%は milestone イベントの判定に使う）
+	var weeklyBefore *float64
+	if cfg != nil && cfg.Events != nil {
+		weeklyBefore = currentWeeklyAIPercentage(store, time.Now())
+	}
+	recordDailyRollup(store, commitHash, log, numstatMap)
+
+	// 設定の events があればメッセージバス・Webhookへ通知（失敗してもAuthorship Logは保存済みのため警告のみ）
+	if cfg != nil && cfg.Events != nil {
+		if err := publishCommitEvent(cfg.Events, log, numstatMap); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to publish commit event: %v\n", err)
+		}
+		weeklyAfter := currentWeeklyAIPercentage(store, time.Now())
+		if err := publishMilestoneEvent(cfg.Events, log.Commit, cfg.TargetAIPercentage, weeklyBefore, weeklyAfter); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to publish milestone event: %v\n", err)
+		}
+	}
+
 	// 使用済みチェックポイントのみ選択的に削除（stash対応）
 	consumedTimestamps := collectConsumedTimestamps(authorshipMap)
 	if err := store.RemoveConsumedCheckpoints(consumedTimestamps); err != nil {
@@ -149,6 +192,7 @@ func getCommitDiff(commitHash string) (map[string]tracker.Change, error) {
 	}
 
 	numstatMap, _ := git.ParseNumstat(numstatOutput)
+	applyLineCounters(numstatMap, commitHash, nil)
 	diffMap := make(map[string]tracker.Change, len(numstatMap))
 
 	for fpath, stats := range numstatMap {
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+)
+
+// bashCompletionScript はbash用の補完スクリプト。
+// --author / --range の候補は実行時に 'aict __complete' から動的に取得します。
+const bashCompletionScript = `# aict bash completion
+# Install: source <(aict completion bash)
+
+_aict_reply() {
+    local IFS=$'\n' word
+    COMPREPLY=()
+    for word in $(compgen -W "$1" -- "$cur"); do
+        COMPREPLY+=("$(printf '%q' "$word")")
+    done
+}
+
+_aict() {
+    local cur prev
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    case "$prev" in
+        --author)
+            _aict_reply "$(aict __complete authors 2>/dev/null)"
+            return
+            ;;
+        --range|--commits|--branch)
+            _aict_reply "$(aict __complete branches 2>/dev/null)"
+            return
+            ;;
+        --format)
+            _aict_reply $'table\njson'
+            return
+            ;;
+        --sort)
+            _aict_reply $'lines\nai'
+            return
+            ;;
+        --period|--trend)
+            _aict_reply $'day\nweek\nmonth'
+            return
+            ;;
+    esac
+
+    if [ "$COMP_CWORD" -eq 1 ]; then
+        _aict_reply "$(aict __complete commands 2>/dev/null)"
+        return
+    fi
+
+    local words=""
+    case "${COMP_WORDS[1]}" in
+        init)       words="--with-hooks" ;;
+        hook)       words=$'pre-tool-use\npost-tool-use\nstop\npost-commit' ;;
+        status)     words=$'--idle\n--format\n--fields\n--watch' ;;
+        whoami)     words=$'set\nclear' ;;
+        fsck)       words=$'--repair' ;;
+        version)    words=$'--verbose' ;;
+        verify-binary) words=$'--checksums\n--binary\n--name\n--signature\n--public-key\n--format\n--fields' ;;
+        hooks)      words=$'status\nrepair' ;;
+        setup-hooks) words=$'--settings\n--push-notes\n--ci' ;;
+        checkpoint) words=$'migrate\n--author\n--model\n--message\n--weight\n--to' ;;
+        import)     words=$'claude-transcripts\n--range\n--since\n--window\n--dry-run' ;;
+        report)     words=$'--range\n--commits\n--since\n--last\n--compare-to\n--format\n--fields\n--sample\n--branch\n--by-author\n--by-file\n--by-dir\n--by-language\n--context\n--depth\n--sort\n--trend\n--smooth\n--sizes' ;;
+        aggregate)  words=$'--repos\n--repos-file\n--since\n--range\n--format\n--fields' ;;
+        config)     words=$'get\nset\nvalidate\nmigrate\n--no-edit\n--stdin\n--add\n--remove' ;;
+        secret)     words=$'set\ndelete\ncheck' ;;
+        sync)       words=$'push\nfetch' ;;
+        notes)      words=$'push\nfetch\nsync\npropagate\n--remote\n--range\n--dry-run' ;;
+        baseline)   words=$'create\nlist\n--label\n--scheduled' ;;
+        debug)      words=$'show\nclean\nclear-notes\nhealth\nredactions' ;;
+        daemon)     words=$'start\nstop\nstatus' ;;
+        completion) words=$'bash\nzsh' ;;
+        telemetry)  words=$'on\noff\nstatus\n--endpoint' ;;
+        uninstall)  words="--purge" ;;
+        audit-metrics) words="--fix" ;;
+        ownership)  words=$'--rev\n--since\n--output\n--fields\n--top\n--by-dir\n--depth\n--sort' ;;
+        reconcile)  words=$'--from\n--commit\n--dry-run\n--force' ;;
+        grep-ai)    words=$'-i\n-F' ;;
+        blame)      words=$'--rev\n--format\n--output' ;;
+        query)      words=$'--format\n--fields' ;;
+        churn)      words=$'--range\n--since\n--period\n--survival-days\n--survival-sample\n--format\n--fields' ;;
+        check)      words=$'--range\n--since\n--context\n--min-ai\n--max-ai\n--annotate-files' ;;
+        ci)         words=$'--range\n--since\n--context\n--config\n--min-ai\n--max-ai\n--annotate-files\n--no-fetch' ;;
+        reclassify) words=$'--to\n--author\n--tool\n--since\n--until\n--branch\n--range\n--apply' ;;
+        badge)      words=$'--since\n--output\n--label' ;;
+        backstage-metadata) words=$'--since\n--format\n--fields\n--dashboard-url\n--write' ;;
+    esac
+    _aict_reply "$words"
+}
+
+complete -F _aict aict
+`
+
+// zshCompletionScript はzsh用の補完スクリプト（bashcompinit経由でbash版を利用）
+const zshCompletionScript = `# aict zsh completion
+# Install: source <(aict completion zsh)
+
+autoload -U +X bashcompinit && bashcompinit
+` + bashCompletionScript
+
+// completionCommands は補完候補として表示するサブコマンド
+var completionCommands = []string{
+	"init", "checkpoint", "commit", "status", "whoami", "report", "notes", "sync", "import", "baseline", "setup-hooks", "hook", "hooks",
+	"config", "secret", "debug", "verify-setup", "daemon", "audit-metrics", "ownership", "reconcile", "aggregate", "grep-ai", "why", "blame", "query", "churn", "check", "ci", "reclassify", "badge", "backstage-metadata", "uninstall", "telemetry", "completion", "version", "verify-binary", "fsck", "help",
+}
+
+// handleCompletion handles the completion command
+func handleCompletion() error {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: aict completion [bash|zsh]")
+		return fmt.Errorf("shell name required")
+	}
+
+	switch os.Args[2] {
+	case "bash":
+		io.WriteString(os.Stdout, bashCompletionScript)
+	case "zsh":
+		io.WriteString(os.Stdout, zshCompletionScript)
+	default:
+		fmt.Println("Usage: aict completion [bash|zsh]")
+		return fmt.Errorf("unsupported shell: %s", os.Args[2])
+	}
+	return nil
+}
+
+// handleCompleteCandidates は補完スクリプトから呼ばれ、動的な候補を1行1件で出力します。
+// 補完を妨げないよう、候補が取得できない場合もエラーにせず空出力とします。
+func handleCompleteCandidates() error {
+	if len(os.Args) < 3 {
+		return nil
+	}
+
+	var candidates []string
+	switch os.Args[2] {
+	case "commands":
+		candidates = completionCommands
+	case "authors":
+		candidates = knownAuthors()
+	case "branches":
+		candidates = knownBranches()
+	}
+
+	for _, c := range candidates {
+		fmt.Println(c)
+	}
+	return nil
+}
+
+// knownAuthors は設定・チェックポイント・日次集計に現れる作成者名を返します。
+func knownAuthors() []string {
+	seen := make(map[string]bool)
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return nil
+	}
+
+	seen[cfg.DefaultAuthor] = true
+	for _, agent := range cfg.AIAgents {
+		seen[agent] = true
+	}
+	for from, to := range cfg.AuthorMappings {
+		seen[from] = true
+		seen[to] = true
+	}
+
+	if checkpoints, err := store.LoadCheckpoints(); err == nil {
+		for _, cp := range checkpoints {
+			seen[cp.Author] = true
+		}
+	}
+	if rollups, err := store.LoadDailyRollups(); err == nil {
+		for _, r := range rollups {
+			for name := range r.Authors {
+				seen[name] = true
+			}
+		}
+	}
+
+	return sortedNonEmpty(seen)
+}
+
+// knownBranches はローカル・リモートのブランチ名と日次集計に記録されたブランチ名を返します。
+func knownBranches() []string {
+	seen := make(map[string]bool)
+
+	executor := newExecutor()
+	if output, err := executor.Run("for-each-ref", "--format=%(refname:short)", "refs/heads", "refs/remotes"); err == nil {
+		for _, line := range strings.Split(output, "\n") {
+			if line != "" && !strings.HasSuffix(line, "/HEAD") {
+				seen[line] = true
+			}
+		}
+	}
+
+	if store, err := storage.NewAIctStorage(); err == nil {
+		if rollups, err := store.LoadDailyRollups(); err == nil {
+			for _, r := range rollups {
+				seen[r.Branch] = true
+			}
+		}
+	}
+
+	return sortedNonEmpty(seen)
+}
+
+func sortedNonEmpty(set map[string]bool) []string {
+	result := make([]string, 0, len(set))
+	for s := range set {
+		if s != "" {
+			result = append(result, s)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
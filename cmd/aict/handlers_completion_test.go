@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestKnownAuthors(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		t.Fatalf("NewAIctStorage() error = %v", err)
+	}
+	store.SaveCheckpoint(&tracker.CheckpointV2{Timestamp: time.Now(), Author: "Claude Code", Type: tracker.AuthorTypeAI})
+	store.MergeDailyRollup(&tracker.DailyRollup{
+		Date: "2026-01-10", Branch: "feature/x", Commits: []string{"c1"},
+		Authors: map[string]*tracker.RollupAuthor{"Alice": {Type: tracker.AuthorTypeHuman}},
+	})
+
+	// InitAICT の設定: default_author=human, ai_agents=[Claude, AI]
+	want := []string{"AI", "Alice", "Claude", "Claude Code", "human"}
+	if got := knownAuthors(); !reflect.DeepEqual(got, want) {
+		t.Errorf("knownAuthors() = %q, want %q", got, want)
+	}
+
+	branches := knownBranches()
+	if !containsString(branches, "feature/x") {
+		t.Errorf("knownBranches() = %q, want rollup branch feature/x", branches)
+	}
+}
+
+func TestKnownAuthors_NotInitialized(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	if got := knownAuthors(); len(got) != 0 {
+		t.Errorf("knownAuthors() = %q, want empty", got)
+	}
+}
+
+func TestHandleCompletion(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{"bash", []string{"aict", "completion", "bash"}, false},
+		{"zsh", []string{"aict", "completion", "zsh"}, false},
+		{"unsupported shell", []string{"aict", "completion", "tcsh"}, true},
+		{"missing shell", []string{"aict", "completion"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			if err := handleCompletion(); (err != nil) != tt.wantErr {
+				t.Errorf("handleCompletion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBashCompletionScript_CoversCommands(t *testing.T) {
+	for _, cmd := range []string{"checkpoint", "report", "config", "sync", "debug", "daemon"} {
+		if !strings.Contains(bashCompletionScript, cmd+")") {
+			t.Errorf("bash completion script has no flag/subcommand case for %q", cmd)
+		}
+		if !containsString(completionCommands, cmd) {
+			t.Errorf("completionCommands is missing %q", cmd)
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+)
+
+// handleConfig handles the config command
+func handleConfig() error {
+	if len(os.Args) > 2 {
+		switch os.Args[2] {
+		case "get":
+			return handleConfigGet()
+		case "set":
+			return handleConfigSet()
+		case "validate":
+			return handleConfigValidate()
+		case "migrate":
+			return handleConfigMigrate()
+		}
+	}
+
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+	noEdit := fs.Bool("no-edit", false, "Print the current config instead of opening an editor")
+	fromStdin := fs.Bool("stdin", false, "Read config JSON or YAML from stdin, validate it and save it")
+	fs.Parse(os.Args[2:])
+
+	if *noEdit && *fromStdin {
+		return fmt.Errorf("--no-edit and --stdin are mutually exclusive")
+	}
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *noEdit:
+		data, err := storage.MarshalConfig(cfg, storage.IsYAMLConfigPath(store.ConfigPath()))
+		if err != nil {
+			return err
+		}
+		fmt.Println(strings.TrimRight(string(data), "\n"))
+		return nil
+	case *fromStdin:
+		data, err := io.ReadAll(stdinReader)
+		if err != nil {
+			return fmt.Errorf("reading config from stdin: %w", err)
+		}
+		// 標準入力は拡張子がないため、"{" で始まればJSON、それ以外はYAMLとして読む
+		isYAML := !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+		return applyConfig(store, data, isYAML)
+	default:
+		return editConfig(store)
+	}
+}
+
+// applyConfig は設定（JSONまたはYAML）を未知のキーも含めて検証し、現在の形式で保存します。
+func applyConfig(store *storage.AIctStorage, data []byte, isYAML bool) error {
+	cfg, err := storage.ParseConfigStrict(data, isYAML)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	if err := store.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+	fmt.Printf("✓ Configuration saved to .git/aict/%s\n", filepath.Base(store.ConfigPath()))
+	return nil
+}
+
+// handleConfigGet はキーの値を表示します。未設定の場合はエラー（終了コード1）を返します。
+func handleConfigGet() error {
+	if len(os.Args) != 4 {
+		return fmt.Errorf("usage: aict config get <key>")
+	}
+	key := os.Args[3]
+
+	_, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return err
+	}
+	value, ok, err := getConfigValue(cfg, key)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s is not set", key)
+	}
+	out, err := formatConfigValue(value)
+	if err != nil {
+		return err
+	}
+	if out != "" {
+		fmt.Println(out)
+	}
+	return nil
+}
+
+// handleConfigSet はキーに値を設定し、検証してから保存します。
+// リストのキーは値を複数指定でき、--add / --remove で要素を追加・削除できます。
+func handleConfigSet() error {
+	fs := flag.NewFlagSet("config set", flag.ExitOnError)
+	add := fs.Bool("add", false, "Append values to a list key (e.g. tracked_extensions)")
+	remove := fs.Bool("remove", false, "Remove values from a list key")
+	fs.Parse(os.Args[3:])
+
+	if *add && *remove {
+		return fmt.Errorf("--add and --remove are mutually exclusive")
+	}
+	if fs.NArg() < 1 {
+		return fmt.Errorf("usage: aict config set [--add|--remove] <key> <value>...")
+	}
+	mode := configSetReplace
+	if *add {
+		mode = configSetAdd
+	} else if *remove {
+		mode = configSetRemove
+	}
+	key, args := fs.Arg(0), fs.Args()[1:]
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return err
+	}
+	updated, err := setConfigValue(cfg, key, args, mode)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	if err := store.SaveConfig(updated); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+
+	value, _, err := getConfigValue(updated, key)
+	if err != nil {
+		return err
+	}
+	compact, _ := json.Marshal(value)
+	fmt.Printf("✓ %s = %s\n", key, compact)
+	return nil
+}
+
+// handleConfigValidate は設定ファイルの構文・未知のキー・値を検証し、問題をすべて表示します。
+// ファイルを指定しない場合は .git/aict/ の現在の設定ファイルを検証します。
+func handleConfigValidate() error {
+	fs := flag.NewFlagSet("config validate", flag.ExitOnError)
+	fs.Parse(os.Args[3:])
+
+	var configPath string
+	switch fs.NArg() {
+	case 0:
+		store, err := storage.NewAIctStorage()
+		if err != nil {
+			return fmt.Errorf("initializing storage: %w", err)
+		}
+		configPath = store.ConfigPath()
+	case 1:
+		configPath = fs.Arg(0)
+	default:
+		return fmt.Errorf("usage: aict config validate [file]")
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	name := filepath.Base(configPath)
+
+	problems := validateConfigData(data, storage.IsYAMLConfigPath(configPath))
+	if len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Printf("✗ %s: %s\n", name, p)
+		}
+		return fmt.Errorf("%s has %d problem(s)", name, len(problems))
+	}
+	if jsonData, err := storage.ConfigToJSON(data, storage.IsYAMLConfigPath(configPath)); err == nil {
+		if cfg, err := storage.ParseConfig(jsonData); err == nil {
+			for _, w := range plaintextSecretWarnings(cfg) {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+			}
+		}
+	}
+	fmt.Printf("✓ %s is valid\n", name)
+	return nil
+}
+
+// validateConfigData は構文エラー、未知のキー、値の検証エラーを順に集めます。
+// 構文エラーの場合はそれ以降の検証を行いません。
+func validateConfigData(data []byte, isYAML bool) []string {
+	jsonData, err := storage.ConfigToJSON(data, isYAML)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	unknown, err := storage.UnknownConfigKeys(jsonData)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	problems := unknown
+	if _, err := storage.ParseConfig(jsonData); err != nil {
+		problems = append(problems, err.Error())
+	}
+	return problems
+}
+
+// handleConfigMigrate は config.json を config.yaml に移行します（元のファイルは config.json.bak に残す）。
+func handleConfigMigrate() error {
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		return fmt.Errorf("initializing storage: %w", err)
+	}
+	dropped, err := store.MigrateConfigToYAML()
+	if err != nil {
+		return fmt.Errorf("migrating config: %w", err)
+	}
+	for _, d := range dropped {
+		fmt.Fprintf(os.Stderr, "Warning: not migrated: %s\n", d)
+	}
+	fmt.Printf("✓ Migrated .git/aict/%s to .git/aict/%s (backup: %s.bak)\n",
+		storage.ConfigFileName, storage.ConfigYAMLFileName, storage.ConfigFileName)
+	return nil
+}
+
+// editConfig は設定を一時ファイルに書き出してエディタで編集し、検証後に保存します。
+// 検証に失敗した場合は編集内容を失わないよう一時ファイルを残します。
+func editConfig(store *storage.AIctStorage) error {
+	editor, err := resolveEditor()
+	if err != nil {
+		return err
+	}
+
+	configPath := store.ConfigPath()
+	original, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+
+	tmp, err := os.CreateTemp("", "aict-config-*"+filepath.Ext(configPath))
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.Write(original)
+	tmp.Close()
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+
+	cmd := exec.Command(editor[0], append(editor[1:], tmpPath)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("running editor %q: %w", strings.Join(editor, " "), err)
+	}
+
+	edited, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return fmt.Errorf("reading edited config: %w", err)
+	}
+	if string(edited) == string(original) {
+		os.Remove(tmpPath)
+		fmt.Println("No changes")
+		return nil
+	}
+
+	if err := applyConfig(store, edited, storage.IsYAMLConfigPath(configPath)); err != nil {
+		fmt.Fprintf(os.Stderr, "Edited config kept at %s\n", tmpPath)
+		fmt.Fprintf(os.Stderr, "Fix it and apply with: aict config --stdin < %s\n", tmpPath)
+		return err
+	}
+	os.Remove(tmpPath)
+	return nil
+}
+
+// resolveEditor は $VISUAL → $EDITOR の順にエディタコマンドを決定します。
+// 未設定の場合、Windowsでは notepad、端末に接続されていれば vi を使用します。
+// SSH経由のパイプ実行など端末がない場合はエラーを返します。
+func resolveEditor() ([]string, error) {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		value := strings.TrimSpace(os.Getenv(env))
+		if value == "" {
+			continue
+		}
+		args, err := splitShellArgs(value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing $%s: %w", env, err)
+		}
+		if len(args) > 0 {
+			return args, nil
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}, nil
+	}
+	if isTerminal(os.Stdin) {
+		return []string{"vi"}, nil
+	}
+	return nil, fmt.Errorf("no editor available: set $VISUAL or $EDITOR, or use 'aict config --no-edit' / 'aict config --stdin'")
+}
+
+// isTerminal は f が端末（キャラクタデバイス）かどうかを判定します。
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// splitShellArgs はPOSIXシェル風に文字列を引数へ分割します。
+// シングルクォート（エスケープなし）、ダブルクォート（\" \\ \$ \` のみエスケープ）、
+// クォート外のバックスラッシュエスケープに対応します。
+func splitShellArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\':
+			if i+1 >= len(runes) {
+				return nil, fmt.Errorf("trailing backslash in %q", s)
+			}
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated single quote in %q", s)
+			}
+			current.WriteString(string(runes[i+1 : end]))
+			i = end
+			inArg = true
+		case r == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`", runes[i+1]) {
+					i++
+				}
+				current.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated double quote in %q", s)
+			}
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestSplitShellArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{"単一コマンド", "vim", []string{"vim"}, false},
+		{"引数付き", "code --wait", []string{"code", "--wait"}, false},
+		{"連続空白", "  emacs   -nw  ", []string{"emacs", "-nw"}, false},
+		{"ダブルクォートのパス", `"/Applications/Sublime Text.app/subl" -w`, []string{"/Applications/Sublime Text.app/subl", "-w"}, false},
+		{"シングルクォート", `'my editor' '-c set ft=json'`, []string{"my editor", "-c set ft=json"}, false},
+		{"バックスラッシュエスケープ", `my\ editor -w`, []string{"my editor", "-w"}, false},
+		{"ダブルクォート内のエスケープ", `"a \"b\" c\d"`, []string{`a "b" c\d`}, false},
+		{"クォートの連結", `pre"fix"'ed'`, []string{"prefixed"}, false},
+		{"空のクォート", `vim ""`, []string{"vim", ""}, false},
+		{"閉じていないシングルクォート", `'vim`, nil, true},
+		{"閉じていないダブルクォート", `"vim`, nil, true},
+		{"末尾のバックスラッシュ", `vim\`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitShellArgs(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitShellArgs(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitShellArgs(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveEditor(t *testing.T) {
+	t.Run("VISUALを優先", func(t *testing.T) {
+		t.Setenv("VISUAL", "code --wait")
+		t.Setenv("EDITOR", "nano")
+		got, err := resolveEditor()
+		if err != nil {
+			t.Fatalf("resolveEditor() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, []string{"code", "--wait"}) {
+			t.Errorf("resolveEditor() = %q, want [code --wait]", got)
+		}
+	})
+
+	t.Run("EDITORにフォールバック", func(t *testing.T) {
+		t.Setenv("VISUAL", "")
+		t.Setenv("EDITOR", "nano -w")
+		got, err := resolveEditor()
+		if err != nil {
+			t.Fatalf("resolveEditor() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, []string{"nano", "-w"}) {
+			t.Errorf("resolveEditor() = %q, want [nano -w]", got)
+		}
+	})
+
+	t.Run("不正なクォート", func(t *testing.T) {
+		t.Setenv("VISUAL", `"broken`)
+		if _, err := resolveEditor(); err == nil {
+			t.Error("resolveEditor() should fail for unterminated quote")
+		}
+	})
+}
+
+func setupConfigTest(t *testing.T) string {
+	t.Helper()
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(originalDir) })
+	os.Chdir(tmpDir)
+
+	origArgs := os.Args
+	t.Cleanup(func() { os.Args = origArgs })
+
+	return filepath.Join(tmpDir, ".git", "aict", storage.ConfigFileName)
+}
+
+func TestHandleConfig_Stdin(t *testing.T) {
+	configPath := setupConfigTest(t)
+
+	input := `{"target_ai_percentage": 50, "tracked_extensions": [".rs"], "default_author": "dev"}`
+	defer setStdinReader(input)()
+	os.Args = []string{"aict", "config", "--stdin"}
+
+	if err := handleConfig(); err != nil {
+		t.Fatalf("handleConfig() error = %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("reading config: %v", err)
+	}
+	cfg, err := storage.ParseConfig(data)
+	if err != nil {
+		t.Fatalf("saved config is invalid: %v", err)
+	}
+	if cfg.TargetAIPercentage != 50 || cfg.DefaultAuthor != "dev" {
+		t.Errorf("saved config = %+v", cfg)
+	}
+}
+
+func TestHandleConfig_StdinInvalid(t *testing.T) {
+	configPath := setupConfigTest(t)
+	before, _ := os.ReadFile(configPath)
+
+	defer setStdinReader(`{"target_ai_percentage": 150, "tracked_extensions": [".go"], "default_author": "dev"}`)()
+	os.Args = []string{"aict", "config", "--stdin"}
+
+	err := handleConfig()
+	if err == nil || !strings.Contains(err.Error(), "target_ai_percentage") {
+		t.Fatalf("handleConfig() error = %v, want validation error", err)
+	}
+
+	after, _ := os.ReadFile(configPath)
+	if string(before) != string(after) {
+		t.Error("invalid config should not be saved")
+	}
+}
+
+func TestHandleConfig_Editor(t *testing.T) {
+	configPath := setupConfigTest(t)
+
+	// 渡されたファイルを書き換えるだけのエディタ
+	editorDir := t.TempDir()
+	newConfig := filepath.Join(editorDir, "new.json")
+	os.WriteFile(newConfig, []byte(`{"target_ai_percentage": 70, "tracked_extensions": [".go"], "default_author": "edited"}`), 0644)
+	script := filepath.Join(editorDir, "fake editor.sh")
+	os.WriteFile(script, []byte("#!/bin/sh\ncat \""+newConfig+"\" > \"$2\"\n"), 0755)
+
+	t.Setenv("VISUAL", "'"+script+"' --wait")
+	os.Args = []string{"aict", "config"}
+
+	if err := handleConfig(); err != nil {
+		t.Fatalf("handleConfig() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(configPath)
+	cfg, err := storage.ParseConfig(data)
+	if err != nil {
+		t.Fatalf("saved config is invalid: %v", err)
+	}
+	if cfg.DefaultAuthor != "edited" {
+		t.Errorf("DefaultAuthor = %q, want %q", cfg.DefaultAuthor, "edited")
+	}
+}
+
+func TestHandleConfig_MutuallyExclusive(t *testing.T) {
+	setupConfigTest(t)
+	os.Args = []string{"aict", "config", "--stdin", "--no-edit"}
+
+	if err := handleConfig(); err == nil {
+		t.Error("handleConfig() should reject --stdin with --no-edit")
+	}
+}
+
+func TestHandleConfig_Validate(t *testing.T) {
+	setupConfigTest(t)
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{"current config", "", "", ""},
+		{"valid yaml", "ok.yaml", "target_ai_percentage: 80\ntracked_extensions: [.go]\ndefault_author: dev\n", ""},
+		{"unknown key", "typo.yaml", "tracked_extensions: [.go]\ndefault_author: dev\nexclude_pattern: [vendor/*]\n", "1 problem"},
+		{"unknown key and invalid value", "bad.json", `{"target_ai_percentage": 150, "default_author": "dev", "colour": 1}`, "2 problem"},
+		{"syntax error", "broken.yaml", "default_author: dev\n\tdocs: {}\n", "1 problem"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = []string{"aict", "config", "validate"}
+			if tt.file != "" {
+				path := filepath.Join(dir, tt.file)
+				os.WriteFile(path, []byte(tt.content), 0644)
+				os.Args = append(os.Args, path)
+			}
+
+			err := handleConfig()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("handleConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("handleConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandleConfig_MigrateToYAML(t *testing.T) {
+	configPath := setupConfigTest(t)
+	yamlPath := filepath.Join(filepath.Dir(configPath), storage.ConfigYAMLFileName)
+
+	os.Args = []string{"aict", "config", "migrate"}
+	if err := handleConfig(); err != nil {
+		t.Fatalf("handleConfig() migrate error = %v", err)
+	}
+	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+		t.Errorf("config.json should be renamed to a backup, stat err = %v", err)
+	}
+
+	// 移行後は標準入力のYAMLも config.yaml に保存される
+	defer setStdinReader("target_ai_percentage: 40\ntracked_extensions:\n  - .rs\ndefault_author: dev\n")()
+	os.Args = []string{"aict", "config", "--stdin"}
+	if err := handleConfig(); err != nil {
+		t.Fatalf("handleConfig() --stdin error = %v", err)
+	}
+
+	data, err := os.ReadFile(yamlPath)
+	if err != nil {
+		t.Fatalf("reading config.yaml: %v", err)
+	}
+	cfg, err := storage.ParseConfigStrict(data, true)
+	if err != nil {
+		t.Fatalf("saved config.yaml is invalid: %v\n%s", err, data)
+	}
+	if cfg.TargetAIPercentage != 40 || !reflect.DeepEqual(cfg.TrackedExtensions, []string{".rs"}) {
+		t.Errorf("saved config = %+v", cfg)
+	}
+
+	// 未知のキーは保存しない
+	defer setStdinReader("target_ai_percentage: 40\ndefault_author: dev\ntrackd_extensions: [.go]\n")()
+	if err := handleConfig(); err == nil || !strings.Contains(err.Error(), `did you mean "tracked_extensions"`) {
+		t.Errorf("handleConfig() error = %v, want unknown key error", err)
+	}
+}
+
+func TestHandleConfig_Set(t *testing.T) {
+	configPath := setupConfigTest(t)
+
+	steps := [][]string{
+		{"aict", "config", "set", "target_ai_percentage", "70"},
+		{"aict", "config", "set", "--add", "tracked_extensions", ".rs"},
+		{"aict", "config", "set", "--remove", "tracked_extensions", ".rs"},
+		{"aict", "config", "set", "--add", "exclude_patterns", "gen/*"},
+	}
+	for _, args := range steps {
+		os.Args = args
+		if err := handleConfig(); err != nil {
+			t.Fatalf("%v: error = %v", args[2:], err)
+		}
+	}
+
+	data, _ := os.ReadFile(configPath)
+	cfg, err := storage.ParseConfig(data)
+	if err != nil {
+		t.Fatalf("saved config is invalid: %v", err)
+	}
+	if cfg.TargetAIPercentage != 70 {
+		t.Errorf("target_ai_percentage = %v, want 70", cfg.TargetAIPercentage)
+	}
+	for _, ext := range cfg.TrackedExtensions {
+		if ext == ".rs" {
+			t.Errorf("tracked_extensions = %v, .rs should be removed", cfg.TrackedExtensions)
+		}
+	}
+	if n := len(cfg.ExcludePatterns); n == 0 || cfg.ExcludePatterns[n-1] != "gen/*" {
+		t.Errorf("exclude_patterns = %v, want gen/* appended", cfg.ExcludePatterns)
+	}
+
+	os.Args = []string{"aict", "config", "set", "--add", "--remove", "tracked_extensions", ".go"}
+	if err := handleConfig(); err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Errorf("handleConfig() error = %v, want mutually exclusive", err)
+	}
+	os.Args = []string{"aict", "config", "get", "badge.label"}
+	if err := handleConfig(); err == nil || !strings.Contains(err.Error(), "is not set") {
+		t.Errorf("handleConfig() error = %v, want not set", err)
+	}
+}
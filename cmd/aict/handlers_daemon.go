@@ -0,0 +1,374 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/git"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/ignore"
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+const (
+	// daemonSocketName は .git/aict/ 配下に作成するUNIXソケットのファイル名
+	daemonSocketName = "daemon.sock"
+	// daemonDialTimeout はCLIからdaemonへの接続タイムアウト
+	daemonDialTimeout = 200 * time.Millisecond
+	// daemonReadTimeout はdaemonがリクエストを読み込む際のタイムアウト
+	daemonReadTimeout = 5 * time.Second
+	// daemonCacheSize はdaemonが保持するレポートの上限（超えた場合は最も長く使われていないものから破棄）
+	daemonCacheSize = 32
+)
+
+// daemonRequest はCLI → daemon のリクエスト（1接続1リクエスト、JSON）
+type daemonRequest struct {
+	Command string         `json:"command"` // ping, report, shutdown
+	Report  *ReportOptions `json:"report,omitempty"`
+}
+
+// daemonResponse はdaemon → CLI のレスポンス
+type daemonResponse struct {
+	Error    string                   `json:"error,omitempty"`
+	Report   *tracker.Report          `json:"report,omitempty"`
+	Metrics  *tracker.DetailedMetrics `json:"metrics,omitempty"`
+	Cached   bool                     `json:"cached,omitempty"`
+	PID      int                      `json:"pid,omitempty"`
+	Uptime   string                   `json:"uptime,omitempty"`
+	Requests int                      `json:"requests,omitempty"`
+	Hits     int                      `json:"hits,omitempty"`
+}
+
+// reportDaemon は集計済みレポートをメモリに保持し、同一状態への再集計を省略します。
+// キャッシュキーにはレンジ端点とnotes refの解決済みハッシュを含めるため、
+// 新しいコミットやAuthorship Logが追加されると自動的に再集計されます。
+// 古い状態のキーは参照されなくなるため、保持数を daemonCacheSize に制限して長時間の実行でもメモリを増やしません。
+type reportDaemon struct {
+	started  time.Time
+	cache    map[string]*daemonResponse
+	order    []string // キャッシュキーを最後に使われた順（末尾が最新）に並べたもの
+	requests int
+	hits     int
+}
+
+func newReportDaemon() *reportDaemon {
+	return &reportDaemon{
+		started: time.Now(),
+		cache:   make(map[string]*daemonResponse),
+	}
+}
+
+// handleDaemon handles the daemon command
+func handleDaemon() error {
+	subcommand := "start"
+	if len(os.Args) > 2 {
+		subcommand = os.Args[2]
+	}
+
+	switch subcommand {
+	case "start":
+		return runDaemon()
+	case "stop":
+		return stopDaemon()
+	case "status":
+		return showDaemonStatus()
+	default:
+		fmt.Println("Usage: aict daemon [start|stop|status]")
+		return fmt.Errorf("unknown daemon subcommand: %s", subcommand)
+	}
+}
+
+// daemonSocketPath はdaemonソケットのパスを返します（worktreeでは共通gitディレクトリ配下）。
+func daemonSocketPath() (string, error) {
+	gitDir, err := getGitCommonDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, storage.AictDirName, daemonSocketName), nil
+}
+
+// runDaemon はフォアグラウンドでdaemonを起動し、SIGINT/SIGTERMで終了します。
+func runDaemon() error {
+	executor := newExecutor()
+	repoRoot, err := executor.Run("rev-parse", "--show-toplevel")
+	if err != nil {
+		return fmt.Errorf("not in a git repository")
+	}
+	if err := os.Chdir(repoRoot); err != nil {
+		return fmt.Errorf("failed to change directory to %s: %w", repoRoot, err)
+	}
+
+	if _, _, err := loadStorageAndConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "Run 'aict init' first\n")
+		return err
+	}
+
+	socketPath, err := daemonSocketPath()
+	if err != nil {
+		return err
+	}
+
+	if _, err := sendDaemonRequest(socketPath, &daemonRequest{Command: "ping"}); err == nil {
+		return fmt.Errorf("daemon is already running (%s)", socketPath)
+	}
+	// 前回異常終了時のソケットファイルを除去
+	os.Remove(socketPath)
+
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", socketPath, err)
+	}
+	defer os.Remove(socketPath)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		<-sigCh
+		ln.Close()
+	}()
+
+	fmt.Printf("✓ aict daemon listening on %s (pid %d)\n", socketPath, os.Getpid())
+	fmt.Println("  Stop with 'aict daemon stop' or Ctrl+C")
+
+	return newReportDaemon().serve(ln)
+}
+
+// serve はリスナーが閉じられるかshutdownリクエストを受けるまで接続を順次処理します。
+// ハンドラは作業ディレクトリやExecutorを共有するため、リクエストは直列に処理します。
+func (d *reportDaemon) serve(ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("accepting connection: %w", err)
+		}
+
+		if shutdown := d.handleConn(conn); shutdown {
+			ln.Close()
+			return nil
+		}
+	}
+}
+
+// handleConn は1接続分のリクエストを処理します。shutdownを受けた場合は true を返します。
+func (d *reportDaemon) handleConn(conn net.Conn) bool {
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(daemonReadTimeout))
+
+	var req daemonRequest
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		json.NewEncoder(conn).Encode(&daemonResponse{Error: fmt.Sprintf("invalid request: %v", err)})
+		return false
+	}
+
+	d.requests++
+
+	var resp *daemonResponse
+	shutdown := false
+	switch req.Command {
+	case "ping":
+		resp = d.status()
+	case "report":
+		resp = d.report(req.Report)
+	case "shutdown":
+		resp = d.status()
+		shutdown = true
+	default:
+		resp = &daemonResponse{Error: fmt.Sprintf("unknown command: %s", req.Command)}
+	}
+
+	if err := json.NewEncoder(conn).Encode(resp); err != nil {
+		debugf("daemon: failed to write response: %v", err)
+	}
+	return shutdown
+}
+
+func (d *reportDaemon) status() *daemonResponse {
+	return &daemonResponse{
+		PID:      os.Getpid(),
+		Uptime:   time.Since(d.started).Round(time.Second).String(),
+		Requests: d.requests,
+		Hits:     d.hits,
+	}
+}
+
+// report はキャッシュを参照し、未集計の状態であれば generateRangeReport で集計します。
+func (d *reportDaemon) report(opts *ReportOptions) *daemonResponse {
+	if opts == nil || opts.Range == "" {
+		return &daemonResponse{Error: "report range is required"}
+	}
+
+	key := reportCacheKey(opts)
+	if cached, ok := d.cache[key]; ok {
+		d.hits++
+		d.touch(key)
+		hit := *cached
+		hit.Cached = true
+		return &hit
+	}
+
+	report, metrics, err := generateRangeReport(opts)
+	if err != nil {
+		return &daemonResponse{Error: err.Error()}
+	}
+
+	resp := &daemonResponse{Report: report, Metrics: metrics}
+	d.cache[key] = resp
+	d.touch(key)
+	for len(d.order) > daemonCacheSize {
+		delete(d.cache, d.order[0])
+		d.order = d.order[1:]
+	}
+	return resp
+}
+
+// touch はキャッシュキーを最近使われたものとして order の末尾に移動します。
+func (d *reportDaemon) touch(key string) {
+	for i, k := range d.order {
+		if k == key {
+			d.order = append(d.order[:i], d.order[i+1:]...)
+			break
+		}
+	}
+	d.order = append(d.order, key)
+}
+
+// reportCacheKey はレンジ指定とその時点のリポジトリ状態（コミット・notes・設定）からキャッシュキーを生成します。
+func reportCacheKey(opts *ReportOptions) string {
+	executor := newExecutor()
+	resolve := func(rev string) string {
+		out, err := executor.Run("rev-parse", "--verify", "--quiet", "--end-of-options", rev)
+		if err != nil {
+			return ""
+		}
+		return out
+	}
+
+	key := opts.Range + "\x00" + opts.Since + "\x00" + opts.Sample + "\x00" + opts.Branch + "\x00" + fmt.Sprint(opts.ByAuthor, opts.ByFile, opts.ByDir, opts.ByLanguage, opts.Depth, opts.Sizes) + "\x00" + opts.Sort + "\x00" + opts.Context + "\x00" + resolve("HEAD") + "\x00" + resolve(gitnotes.AuthorshipNotesFullRef) + "\x00" + configStateKey()
+	if base, head, ok := git.SplitRange(opts.Range); ok {
+		key += "\x00" + resolve(base) + "\x00" + resolve(head)
+	}
+	return key
+}
+
+// configStateKey は集計に影響する設定ファイル（config.json・config.yaml・.aictignore）の内容のハッシュを返します。
+// 除外パターン・overrides・docs/artifacts・contexts を変更したあとに古いレポートを返さないよう、キャッシュキーに含めます。
+func configStateKey() string {
+	var paths []string
+	if gitDir, err := getGitCommonDir(); err == nil {
+		aictDir := filepath.Join(gitDir, storage.AictDirName)
+		paths = append(paths, filepath.Join(aictDir, storage.ConfigFileName), filepath.Join(aictDir, storage.ConfigYAMLFileName))
+	}
+	if root := storage.FindWorkTree(); root != "" {
+		paths = append(paths, filepath.Join(root, ignore.FileName))
+	}
+
+	h := sha256.New()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(h, "%s\x00-\x00", path) // 存在しないファイル
+			continue
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", path, len(data))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// queryDaemonReport は起動中のdaemonにレポート集計を依頼します。
+// daemonが起動していない場合やAICT_NO_DAEMONが設定されている場合はエラーを返します。
+func queryDaemonReport(opts *ReportOptions) (*tracker.Report, *tracker.DetailedMetrics, error) {
+	if os.Getenv("AICT_NO_DAEMON") != "" {
+		return nil, nil, fmt.Errorf("daemon disabled by AICT_NO_DAEMON")
+	}
+
+	socketPath, err := daemonSocketPath()
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := os.Stat(socketPath); err != nil {
+		return nil, nil, fmt.Errorf("daemon not running")
+	}
+
+	resp, err := sendDaemonRequest(socketPath, &daemonRequest{Command: "report", Report: opts})
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.Error != "" {
+		return nil, nil, fmt.Errorf("daemon: %s", resp.Error)
+	}
+
+	debugf("report served by daemon (cached=%v)", resp.Cached)
+	return resp.Report, resp.Metrics, nil
+}
+
+// sendDaemonRequest はdaemonソケットに接続してリクエストを送信し、レスポンスを返します。
+func sendDaemonRequest(socketPath string, req *daemonRequest) (*daemonResponse, error) {
+	conn, err := net.DialTimeout("unix", socketPath, daemonDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+
+	var resp daemonResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	return &resp, nil
+}
+
+// stopDaemon は起動中のdaemonに停止を依頼します。
+func stopDaemon() error {
+	socketPath, err := daemonSocketPath()
+	if err != nil {
+		return err
+	}
+
+	if _, err := sendDaemonRequest(socketPath, &daemonRequest{Command: "shutdown"}); err != nil {
+		fmt.Println("aict daemon is not running")
+		return nil
+	}
+
+	fmt.Println("✓ aict daemon stopped")
+	return nil
+}
+
+// showDaemonStatus は起動中のdaemonの状態を表示します。
+func showDaemonStatus() error {
+	socketPath, err := daemonSocketPath()
+	if err != nil {
+		return err
+	}
+
+	resp, err := sendDaemonRequest(socketPath, &daemonRequest{Command: "ping"})
+	if err != nil {
+		fmt.Println("aict daemon is not running")
+		return nil
+	}
+
+	fmt.Printf("aict daemon is running (pid %d)\n", resp.PID)
+	fmt.Printf("  Socket:     %s\n", socketPath)
+	fmt.Printf("  Uptime:     %s\n", resp.Uptime)
+	fmt.Printf("  Requests:   %d\n", resp.Requests)
+	fmt.Printf("  Cache hits: %d\n", resp.Hits)
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+// startTestDaemon はテスト用リポジトリでdaemonを起動し、ソケットパスと終了待ちチャネルを返します。
+func startTestDaemon(t *testing.T, tmpDir string) (string, chan error) {
+	t.Helper()
+
+	socketPath := filepath.Join(tmpDir, ".git", "aict", daemonSocketName)
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- newReportDaemon().serve(ln)
+	}()
+	return socketPath, done
+}
+
+func TestReportDaemon_ReportAndCache(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	socketPath, done := startTestDaemon(t, tmpDir)
+
+	opts := &ReportOptions{Range: "HEAD", Format: "json"}
+	report, _, err := queryDaemonReport(opts)
+	if err != nil {
+		t.Fatalf("queryDaemonReport() error = %v", err)
+	}
+	if report == nil || report.Commits != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	resp, err := sendDaemonRequest(socketPath, &daemonRequest{Command: "report", Report: opts})
+	if err != nil {
+		t.Fatalf("sendDaemonRequest() error = %v", err)
+	}
+	if !resp.Cached {
+		t.Error("second identical report should be served from cache")
+	}
+
+	// 新しいコミットでキャッシュキーが変わる
+	testutil.CreateTestFile(t, tmpDir, "util.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "second")
+	resp, err = sendDaemonRequest(socketPath, &daemonRequest{Command: "report", Report: opts})
+	if err != nil {
+		t.Fatalf("sendDaemonRequest() error = %v", err)
+	}
+	if resp.Cached || resp.Report == nil || resp.Report.Commits != 2 {
+		t.Errorf("report after new commit should be recomputed: cached=%v report=%+v", resp.Cached, resp.Report)
+	}
+
+	// .aictignore・設定ファイルの変更でもキャッシュキーが変わる
+	for _, change := range []struct {
+		name string
+		edit func()
+	}{
+		{".aictignore", func() { testutil.CreateTestFile(t, tmpDir, ".aictignore", "util.go\n") }},
+		{"config.json", func() {
+			path := filepath.Join(tmpDir, ".git", "aict", "config.json")
+			data, _ := os.ReadFile(path)
+			os.WriteFile(path, append(data, '\n'), 0644)
+		}},
+	} {
+		change.edit()
+		resp, err = sendDaemonRequest(socketPath, &daemonRequest{Command: "report", Report: opts})
+		if err != nil {
+			t.Fatalf("sendDaemonRequest() error = %v", err)
+		}
+		if resp.Cached {
+			t.Errorf("report after editing %s should be recomputed", change.name)
+		}
+	}
+
+	if _, err := sendDaemonRequest(socketPath, &daemonRequest{Command: "shutdown"}); err != nil {
+		t.Fatalf("shutdown error = %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("serve() error = %v", err)
+	}
+}
+
+func TestReportDaemon_CacheEviction(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	d := newReportDaemon()
+	optsFor := func(depth int) *ReportOptions { return &ReportOptions{Range: "HEAD", Format: "json", Depth: depth} }
+	for depth := 0; depth < daemonCacheSize; depth++ {
+		d.report(optsFor(depth))
+	}
+	// 最も古いエントリを使い直してから1件追加すると、2番目に古いエントリが破棄される
+	if resp := d.report(optsFor(0)); !resp.Cached {
+		t.Fatal("depth 0 should still be cached")
+	}
+	d.report(optsFor(daemonCacheSize))
+
+	if len(d.cache) != daemonCacheSize || len(d.order) != daemonCacheSize {
+		t.Fatalf("cache holds %d entries (order %d), want %d", len(d.cache), len(d.order), daemonCacheSize)
+	}
+	if resp := d.report(optsFor(0)); !resp.Cached {
+		t.Error("recently used entry should not be evicted")
+	}
+	if _, ok := d.cache[reportCacheKey(optsFor(1))]; ok {
+		t.Error("least recently used entry should be evicted")
+	}
+}
+
+func TestReportDaemon_UnknownCommand(t *testing.T) {
+	d := newReportDaemon()
+	client, server := net.Pipe()
+	defer client.Close()
+	go d.handleConn(server)
+
+	if err := json.NewEncoder(client).Encode(&daemonRequest{Command: "bogus"}); err != nil {
+		t.Fatalf("encode error = %v", err)
+	}
+	var resp daemonResponse
+	if err := json.NewDecoder(client).Decode(&resp); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if resp.Error == "" {
+		t.Error("expected error for unknown command")
+	}
+}
+
+func TestQueryDaemonReport_NotRunning(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	if _, _, err := queryDaemonReport(&ReportOptions{Range: "HEAD"}); err == nil {
+		t.Error("expected error when daemon is not running")
+	}
+}
+
+func TestQueryDaemonReport_Disabled(t *testing.T) {
+	t.Setenv("AICT_NO_DAEMON", "1")
+
+	if _, _, err := queryDaemonReport(&ReportOptions{Range: "HEAD"}); err == nil {
+		t.Error("expected error when AICT_NO_DAEMON is set")
+	}
+}
@@ -16,7 +16,9 @@ func handleDebug() error {
 		fmt.Println("  aict debug show              # チェックポイント情報を表示")
 		fmt.Println("  aict debug clean             # チェックポイントを削除")
 		fmt.Println("  aict debug clear-notes       # Git notesのAuthorship Logをクリア")
-		return fmt.Errorf("debug subcommand required (show, clean, clear-notes)")
+		fmt.Println("  aict debug health            # トラッカー自身の健全性指標を表示")
+		fmt.Println("  aict debug redactions        # 設定の redaction で伏せたパス・メッセージを表示")
+		return fmt.Errorf("debug subcommand required (show, clean, clear-notes, health, redactions)")
 	}
 
 	subcommand := os.Args[2]
@@ -27,8 +29,12 @@ func handleDebug() error {
 		return handleDebugClean()
 	case "clear-notes":
 		return handleDebugClearNotes()
+	case "health":
+		return handleDebugHealth()
+	case "redactions":
+		return handleDebugRedactions()
 	default:
-		return fmt.Errorf("unknown debug subcommand: %s (available: show, clean, clear-notes)", subcommand)
+		return fmt.Errorf("unknown debug subcommand: %s (available: show, clean, clear-notes, health, redactions)", subcommand)
 	}
 }
 
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+)
+
+// fsckMaxShownLines は不正な行をファイルごとに表示する上限
+const fsckMaxShownLines = 5
+
+// handleFsck handles the fsck command
+// .git/aict のチェックポイント・日次集計等のJSONLファイルを1行ずつ検証し、--repair で不正な行を quarantine/ に移します。
+func handleFsck() error {
+	fs := flag.NewFlagSet("fsck", flag.ExitOnError)
+	repair := fs.Bool("repair", false, "Move corrupt lines to .git/aict/quarantine/ and rewrite the files without them")
+	fs.Parse(os.Args[2:])
+
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		return fmt.Errorf("initializing storage: %w", err)
+	}
+
+	var checks []storage.RecordFileCheck
+	var quarantined []string
+	if *repair {
+		checks, quarantined, err = store.RepairRecordFiles()
+	} else {
+		checks, err = store.CheckRecordFiles()
+	}
+	if err != nil {
+		return err
+	}
+
+	corrupt := 0
+	for i, c := range checks {
+		switch {
+		case c.Legacy:
+			fmt.Printf("- %-28s legacy JSON array format (converted on the next checkpoint)\n", c.Name)
+			continue
+		case len(c.Corrupt) == 0:
+			fmt.Printf("✓ %-28s %d record(s)\n", c.Name, c.Records)
+			continue
+		}
+		corrupt += len(c.Corrupt)
+		fmt.Printf("✗ %-28s %d record(s), %d corrupt line(s)\n", c.Name, c.Records, len(c.Corrupt))
+		for j, line := range c.Corrupt {
+			if j == fsckMaxShownLines {
+				fmt.Printf("    ... and %d more\n", len(c.Corrupt)-fsckMaxShownLines)
+				break
+			}
+			fmt.Printf("    line %d: %s\n", line.Line, line.Err)
+		}
+		if *repair && quarantined[i] != "" {
+			rel, _ := filepath.Rel(filepath.Dir(store.GetAictDir()), quarantined[i])
+			fmt.Printf("    → moved to %s\n", rel)
+		}
+	}
+
+	if corrupt == 0 {
+		fmt.Println("No corrupt records found")
+		return nil
+	}
+	if *repair {
+		fmt.Printf("Repaired: removed %d corrupt line(s)\n", corrupt)
+		return nil
+	}
+	return fmt.Errorf("found %d corrupt line(s); run 'aict fsck --repair' to move them to .git/aict/%s/", corrupt, storage.QuarantineDirName)
+}
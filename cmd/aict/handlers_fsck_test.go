@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestHandleFsck(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	latest := filepath.Join(tmpDir, ".git", "aict", storage.CheckpointsDirName, storage.LatestFileName)
+	content := "{\"timestamp\":\"2025-01-01T00:00:00Z\",\"author\":\"dev\",\"type\":\"human\",\"changes\":{},\"snapshot\":{}}\n" +
+		"{\"timestamp\":\"yesterday\",\"author\":\"dev\"}\n"
+	if err := os.WriteFile(latest, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Args = []string{"aict", "fsck"}
+	if err := handleFsck(); err == nil {
+		t.Error("fsck should fail when corrupt lines are found")
+	}
+
+	os.Args = []string{"aict", "fsck", "--repair"}
+	if err := handleFsck(); err != nil {
+		t.Fatalf("fsck --repair error = %v", err)
+	}
+	quarantine, _ := os.ReadDir(filepath.Join(tmpDir, ".git", "aict", storage.QuarantineDirName))
+	if len(quarantine) != 1 {
+		t.Errorf("quarantine files = %v, want 1", quarantine)
+	}
+
+	os.Args = []string{"aict", "fsck"}
+	if err := handleFsck(); err != nil {
+		t.Errorf("fsck after repair error = %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/blame"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/ownership"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// grepMatch は git grep の1件の一致行です。
+type grepMatch struct {
+	Path    string
+	Line    int
+	Content string
+}
+
+// handleGrepAI handles the grep-ai command
+func handleGrepAI() error {
+	fs := flag.NewFlagSet("grep-ai", flag.ExitOnError)
+	ignoreCase := fs.Bool("i", false, "Ignore case distinctions")
+	fixed := fs.Bool("F", false, "Interpret pattern as a fixed string")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: aict grep-ai [-i] [-F] <pattern> [<path>...]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(os.Args[2:])
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return fmt.Errorf("pattern is required")
+	}
+
+	matches, err := gitGrep(fs.Arg(0), fs.Args()[1:], *ignoreCase, *fixed)
+	if err != nil {
+		return err
+	}
+
+	aiMatches, err := filterAIAuthoredMatches(matches)
+	if err != nil {
+		return err
+	}
+
+	// grep -n 互換の "path:line:content" 形式（エディタのquickfix等で利用可能）
+	for _, m := range aiMatches {
+		fmt.Printf("%s:%d:%s\n", m.Path, m.Line, m.Content)
+	}
+	return nil
+}
+
+// gitGrep は作業ツリーのファイルを git grep で検索します（バイナリは除外）。
+func gitGrep(pattern string, paths []string, ignoreCase, fixed bool) ([]grepMatch, error) {
+	args := []string{"grep", "-n", "-z", "-I", "--no-color"}
+	if ignoreCase {
+		args = append(args, "-i")
+	}
+	if fixed {
+		args = append(args, "-F")
+	} else {
+		args = append(args, "-E")
+	}
+	args = append(args, "-e", pattern, "--")
+	args = append(args, paths...)
+
+	output, err := newExecutor().Run(args...)
+	if err != nil {
+		// git grep は一致なしの場合に終了コード1を返す
+		if strings.Contains(err.Error(), "exit status 1") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("searching files: %w", err)
+	}
+	return parseGitGrepOutput(output), nil
+}
+
+// parseGitGrepOutput は `git grep -n -z` の出力（path\0line\0content）を解析します。
+func parseGitGrepOutput(output string) []grepMatch {
+	var matches []grepMatch
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, "\x00", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		n, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		matches = append(matches, grepMatch{Path: parts[0], Line: n, Content: parts[2]})
+	}
+	return matches
+}
+
+// filterAIAuthoredMatches は git blame と Authorship Log で一致行の作成者を判定し、
+// AIが書いた行のみを返します。未コミットの行やAuthorship Logのないコミットの行は除外します。
+func filterAIAuthoredMatches(matches []grepMatch) ([]grepMatch, error) {
+	byFile := make(map[string][]grepMatch)
+	var files []string
+	for _, m := range matches {
+		if _, ok := byFile[m.Path]; !ok {
+			files = append(files, m.Path)
+		}
+		byFile[m.Path] = append(byFile[m.Path], m)
+	}
+	sort.Strings(files)
+
+	requests := make([]blame.Request, len(files))
+	for i, path := range files {
+		for _, m := range byFile[path] {
+			requests[i].Lines = append(requests[i].Lines, m.Line)
+		}
+		requests[i].Path = path
+	}
+
+	executor := newExecutor()
+	logs := ownership.NewLogCache(gitnotes.NewNotesManagerWithExecutor(executor))
+	logs.Debugf = debugf
+
+	var result []grepMatch
+	err := blame.Stream(executor, "", requests, blame.DefaultWorkers(), func(r blame.Result) error {
+		if r.Err != nil {
+			debugf("grep-ai: skipping %s: %v", r.Path, r.Err)
+			return nil
+		}
+		for _, m := range byFile[r.Path] {
+			origin, ok := r.Origins[m.Line]
+			if !ok || origin.Commit == blame.UncommittedHash {
+				continue
+			}
+			if a := logs.Author(origin); a != nil && a.Type == tracker.AuthorTypeAI {
+				result = append(result, m)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestParseGitGrepOutput(t *testing.T) {
+	output := "a.go\x0012\x00// TODO: fix\nweird:name.go\x003\x00x := \"a:b\"\nbroken line"
+	got := parseGitGrepOutput(output)
+	want := []grepMatch{
+		{Path: "a.go", Line: 12, Content: "// TODO: fix"},
+		{Path: "weird:name.go", Line: 3, Content: `x := "a:b"`},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseGitGrepOutput() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("match %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestHandleGrepAI(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	// AIが書いたファイル
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+	testutil.CreateTestFile(t, tmpDir, "ai.go", "package main\n\n// TODO: handle errors\nfunc ai() {}\n")
+	if err := recordCheckpoint("Claude", "", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+	testutil.GitCommit(t, tmpDir, "Add ai.go")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	// 人間が書いたファイル（チェックポイントなし → default_author）
+	testutil.CreateTestFile(t, tmpDir, "human.go", "package main\n\n// TODO: human note\n")
+	testutil.GitCommit(t, tmpDir, "Add human.go")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	// 未コミットのTODOは対象外
+	testutil.CreateTestFile(t, tmpDir, "ai.go", "package main\n\n// TODO: handle errors\nfunc ai() {}\n// todo: uncommitted\n")
+
+	os.Args = []string{"aict", "grep-ai", "-i", "todo"}
+	origStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := handleGrepAI()
+
+	w.Close()
+	os.Stdout = origStdout
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+
+	if err != nil {
+		t.Fatalf("handleGrepAI() error = %v", err)
+	}
+	if got, want := buf.String(), "ai.go:3:// TODO: handle errors\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+const (
+	hookEventPreToolUse  = "pre-tool-use"
+	hookEventPostToolUse = "post-tool-use"
+	hookEventPostCommit  = "post-commit"
+	hookEventStop        = "stop"
+
+	// hookAIAuthor はClaude Codeのhookで記録するAI側の作成者名
+	hookAIAuthor = "Claude Code"
+	// hookFallbackAuthor は git config user.name が未設定の場合の作成者名
+	hookFallbackAuthor = "Developer"
+	// maxHookInputSize はhookの標準入力から読み込む上限（Writeのtool_inputはファイル全体を含む）
+	maxHookInputSize = 16 * 1024 * 1024
+)
+
+// hookInput はClaude Codeがhookの標準入力に渡すJSONのうち、aictが使う項目
+type hookInput struct {
+	SessionID     string `json:"session_id"`
+	CWD           string `json:"cwd"`
+	HookEventName string `json:"hook_event_name"`
+	ToolName      string `json:"tool_name"`
+}
+
+// handleHook handles the hook command.
+// Claude Code・gitの操作を妨げないよう、失敗は .git/aict/hook.log に記録して常に正常終了します。
+func handleHook() error {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: aict hook [pre-tool-use|post-tool-use|stop|post-commit]")
+		return fmt.Errorf("hook event required")
+	}
+
+	event := os.Args[2]
+	switch event {
+	case hookEventPreToolUse, hookEventPostToolUse:
+		input := readHookInput(os.Stdin)
+		runToolUseHook(event, input)
+	case hookEventStop:
+		runStopHook(readHookInput(os.Stdin))
+	case hookEventPostCommit:
+		runPostCommitHook()
+	default:
+		fmt.Printf("Unknown hook event: %s\n", event)
+		fmt.Println("Usage: aict hook [pre-tool-use|post-tool-use|stop|post-commit]")
+		return fmt.Errorf("unknown hook event: %s", event)
+	}
+	return nil
+}
+
+// readHookInput はhookの標準入力のJSONを読み込みます。
+// 端末から実行された場合や解析できない場合は空の入力を返します。
+func readHookInput(r *os.File) *hookInput {
+	input := &hookInput{}
+	if info, err := r.Stat(); err != nil || info.Mode()&os.ModeCharDevice != 0 {
+		return input
+	}
+	data, err := io.ReadAll(io.LimitReader(r, maxHookInputSize))
+	if err != nil || len(data) == 0 {
+		return input
+	}
+	if err := json.Unmarshal(data, input); err != nil {
+		debugf("hook: ignoring unparsable stdin: %v", err)
+	}
+	return input
+}
+
+// openHookStore はhookの対象リポジトリに移動し、aict初期化済みの場合にストレージを返します。
+// 未初期化のリポジトリでは何もしないよう nil を返します。
+func openHookStore(projectDir string) *storage.AIctStorage {
+	if projectDir == "" {
+		projectDir = os.Getenv("CLAUDE_PROJECT_DIR")
+	}
+	if projectDir != "" {
+		if err := os.Chdir(projectDir); err != nil {
+			return nil
+		}
+	}
+
+	gitDir, err := getGitCommonDir()
+	if err != nil {
+		return nil
+	}
+	if info, err := os.Stat(filepath.Join(gitDir, "aict")); err != nil || !info.IsDir() {
+		return nil
+	}
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		return nil
+	}
+	return store
+}
+
+// runToolUseHook はClaude Codeの編集前（開発者）・編集後（AI）のチェックポイントを記録します。
+func runToolUseHook(event string, input *hookInput) {
+	store := openHookStore(input.CWD)
+	if store == nil {
+		return
+	}
+	hookLog := func(format string, args ...interface{}) {
+		_ = store.AppendHookLog(event + ": " + fmt.Sprintf(format, args...))
+	}
+
+	author, message := hookAIAuthor, "Claude Code edits"
+	if event == hookEventPreToolUse {
+		author, message = hookFallbackAuthor, "Before Claude Code edits"
+		if name, _ := activeHumanAuthor(store); name != "" {
+			author = name
+		} else if name, err := newExecutor().Run("config", "user.name"); err == nil && name != "" {
+			author = name
+		}
+	}
+
+	if input.ToolName != "" {
+		hookLog("Recording checkpoint for %s (%s)", author, input.ToolName)
+	} else {
+		hookLog("Recording checkpoint for %s", author)
+	}
+	var metadata map[string]string
+	if input.SessionID != "" {
+		metadata = map[string]string{"session_id": input.SessionID}
+	}
+	checkpoint, err := createCheckpoint(author, "", message, nil, metadata)
+	if err != nil {
+		hookLog("Failed to record checkpoint: %v", err)
+		return
+	}
+	hookLog("Checkpoint recorded successfully")
+
+	// aict status 向けにセッションの状態と行数を更新（セッションIDのないhook入力では記録しない）
+	if input.SessionID == "" {
+		return
+	}
+	err = store.UpdateSession(func(current *tracker.AISession) *tracker.AISession {
+		return applySessionActivity(current, event, input, checkpoint, time.Now())
+	})
+	if err != nil {
+		hookLog("Failed to update session: %v", err)
+	}
+}
+
+// runStopHook はClaude Codeの応答が完了したことをセッションの状態に記録します。
+func runStopHook(input *hookInput) {
+	store := openHookStore(input.CWD)
+	if store == nil {
+		return
+	}
+	err := store.UpdateSession(func(current *tracker.AISession) *tracker.AISession {
+		if current == nil || (input.SessionID != "" && current.SessionID != input.SessionID) {
+			return nil
+		}
+		now := time.Now()
+		current.StoppedAt = &now
+		return current
+	})
+	if err != nil {
+		_ = store.AppendHookLog(hookEventStop + ": Failed to update session: " + err.Error())
+	}
+}
+
+// runPostCommitHook はコミット直後にAuthorship Logを生成します。
+func runPostCommitHook() {
+	store := openHookStore("")
+	if store == nil {
+		return
+	}
+	if err := handleCommit(); err != nil {
+		_ = store.AppendHookLog(hookEventPostCommit + ": Failed to create authorship log: " + err.Error())
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestReadHookInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		stdin    string
+		wantCWD  string
+		wantTool string
+	}{
+		{"claude code payload", `{"session_id":"s1","cwd":"/repo","hook_event_name":"PreToolUse","tool_name":"Edit","tool_input":{"file_path":"/repo/a.go"}}`, "/repo", "Edit"},
+		{"empty", "", "", ""},
+		{"not json", "hello", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.CreateTemp(t.TempDir(), "stdin")
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.WriteString(tt.stdin)
+			f.Seek(0, 0)
+			defer f.Close()
+
+			got := readHookInput(f)
+			if got.CWD != tt.wantCWD || got.ToolName != tt.wantTool {
+				t.Errorf("readHookInput() = %+v, want cwd=%q tool=%q", got, tt.wantCWD, tt.wantTool)
+			}
+		})
+	}
+}
+
+func TestHookEvents(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(t.TempDir())
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	// Claude Codeからは cwd がJSONで渡される（カレントディレクトリは別の場所）
+	runToolUseHook(hookEventPreToolUse, &hookInput{SessionID: "s1", CWD: tmpDir, ToolName: "Edit"})
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc ai() {}\n")
+	runToolUseHook(hookEventPostToolUse, &hookInput{SessionID: "s1", CWD: tmpDir, ToolName: "Edit"})
+	runStopHook(&hookInput{SessionID: "s1", CWD: tmpDir})
+
+	store, _, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	checkpoints, err := store.LoadCheckpoints()
+	if err != nil {
+		t.Fatalf("LoadCheckpoints() error = %v", err)
+	}
+	if len(checkpoints) != 2 || checkpoints[0].Type != tracker.AuthorTypeHuman ||
+		checkpoints[1].Author != hookAIAuthor || checkpoints[1].Type != tracker.AuthorTypeAI {
+		t.Fatalf("checkpoints = %+v, want human then Claude Code", checkpoints)
+	}
+
+	if checkpoints[1].Metadata["session_id"] != "s1" {
+		t.Errorf("checkpoint metadata = %v, want session_id s1", checkpoints[1].Metadata)
+	}
+	session, err := store.LoadSession()
+	if err != nil || session == nil {
+		t.Fatalf("LoadSession() = %v, %v", session, err)
+	}
+	if session.SessionID != "s1" || session.ToolUses != 1 || session.AIAdded != 2 || session.LastTool != "Edit" || session.StoppedAt == nil {
+		t.Errorf("session = %+v, want s1 with 1 tool use, 2 AI lines and stopped", session)
+	}
+
+	hookLog, _ := store.LoadHookLog()
+	for _, want := range []string{"pre-tool-use: Recording checkpoint for", "(Edit)", "post-tool-use: Checkpoint recorded successfully"} {
+		if !strings.Contains(string(hookLog), want) {
+			t.Errorf("hook.log should contain %q:\n%s", want, hookLog)
+		}
+	}
+
+	commit := testutil.GitCommit(t, tmpDir, "AI edit")
+	runPostCommitHook()
+
+	alog, err := gitnotes.NewNotesManager().GetAuthorshipLog(commit)
+	if err != nil || alog == nil {
+		t.Fatalf("authorship log not created: %v", err)
+	}
+	if a := alog.Files["main.go"].Authors; len(a) != 1 || a[0].Type != tracker.AuthorTypeAI {
+		t.Errorf("main.go authors = %+v, want AI", a)
+	}
+}
+
+func TestHookEvents_NotInitialized(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	runToolUseHook(hookEventPreToolUse, &hookInput{CWD: tmpDir})
+	runPostCommitHook()
+
+	if _, err := os.Stat(filepath.Join(tmpDir, ".git", "aict")); !os.IsNotExist(err) {
+		t.Error("hooks should not create .git/aict in an uninitialized repository")
+	}
+}
+
+func TestHandleHook_UnknownEvent(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	for _, args := range [][]string{{"aict", "hook"}, {"aict", "hook", "pre-commit-ish"}} {
+		os.Args = args
+		if err := handleHook(); err == nil {
+			t.Errorf("handleHook(%v) expected error", args[1:])
+		}
+	}
+}
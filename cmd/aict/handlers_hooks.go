@@ -0,0 +1,358 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/templates"
+)
+
+// postCommitHookCommand は既存のユーザーhookに追記するaictの呼び出し行
+const postCommitHookCommand = "aict hook post-commit"
+
+// hookComponent はhook設置物1つの検査結果です。
+// problems が空でない場合、repair で現在のバージョンの内容に書き直せます。
+type hookComponent struct {
+	name     string
+	path     string
+	problems []string
+	repair   func() error
+}
+
+// handleHooks handles the hooks command
+func handleHooks() error {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: aict hooks [status|repair]")
+		return fmt.Errorf("hooks subcommand required")
+	}
+
+	subcommand := os.Args[2]
+
+	switch subcommand {
+	case "status":
+		return handleHooksStatus()
+	case "repair":
+		return handleHooksRepair()
+	default:
+		fmt.Printf("Unknown subcommand: %s\n", subcommand)
+		fmt.Println("Usage: aict hooks [status|repair]")
+		return fmt.Errorf("unknown subcommand: %s", subcommand)
+	}
+}
+
+// hookPaths はリポジトリルートと共通gitディレクトリを返します。
+func hookPaths() (repoRoot, gitDir string, err error) {
+	repoRoot, err = newExecutor().Run("rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get repository root (are you in a git repo?): %w", err)
+	}
+	gitDir, err = getGitCommonDir()
+	if err != nil {
+		return "", "", err
+	}
+	return repoRoot, gitDir, nil
+}
+
+func handleHooksStatus() error {
+	repoRoot, gitDir, err := hookPaths()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("AI Code Tracker hook status:")
+	if problems := printHookComponents(inspectHooks(repoRoot, gitDir)); problems > 0 {
+		fmt.Println()
+		return fmt.Errorf("%d hook problem(s) found; run 'aict hooks repair' to fix them", problems)
+	}
+	fmt.Println()
+	fmt.Println("✓ Hooks are up to date")
+	return nil
+}
+
+func handleHooksRepair() error {
+	repoRoot, gitDir, err := hookPaths()
+	if err != nil {
+		return err
+	}
+
+	repaired := 0
+	for _, c := range inspectHooks(repoRoot, gitDir) {
+		if len(c.problems) == 0 {
+			continue
+		}
+		if err := c.repair(); err != nil {
+			return fmt.Errorf("repairing %s: %w", c.name, err)
+		}
+		fmt.Printf("✓ Repaired %s (%s)\n", c.name, strings.Join(c.problems, "; "))
+		repaired++
+	}
+
+	// 書き直した結果を再検査（修復できない問題が残っていないか）
+	if problems := countHookProblems(inspectHooks(repoRoot, gitDir)); problems > 0 {
+		return fmt.Errorf("%d hook problem(s) remain after repair; run 'aict hooks status' for details", problems)
+	}
+	if repaired == 0 {
+		fmt.Println("✓ Hooks are already up to date")
+	} else {
+		fmt.Println("✓ Hooks repaired")
+	}
+	return nil
+}
+
+// printHookComponents は検査結果を表示し、問題の数を返します。
+func printHookComponents(components []*hookComponent) int {
+	for _, c := range components {
+		if len(c.problems) == 0 {
+			fmt.Printf("  ✓ %s\n", c.name)
+			continue
+		}
+		for _, p := range c.problems {
+			fmt.Printf("  ✗ %s: %s (%s)\n", c.name, p, c.path)
+		}
+	}
+	return countHookProblems(components)
+}
+
+func countHookProblems(components []*hookComponent) int {
+	n := 0
+	for _, c := range components {
+		n += len(c.problems)
+	}
+	return n
+}
+
+// inspectHooks はpost-commit hookとClaude Code設定を検査します。
+// post-commit は husky・lefthook を使うリポジトリではその設定を検査します。
+// Claude Code設定は settings.json・settings.local.json のうちaictのhookを含むものを検査し、
+// どちらにもない場合は settings.json を未設定として報告します。
+// 以前のバージョンの設置物（pre-commit hook、.git/aict/hooks/）は残っている場合のみ結果に含めます。
+func inspectHooks(repoRoot, gitDir string) []*hookComponent {
+	components := []*hookComponent{
+		inspectPostCommit(repoRoot, gitDir),
+	}
+
+	var settingsComponents []*hookComponent
+	for _, path := range claudeSettingsPaths(repoRoot) {
+		if hasAICTHooks(path) {
+			settingsComponents = append(settingsComponents, inspectClaudeSettings(path))
+		}
+	}
+	if len(settingsComponents) == 0 {
+		settingsComponents = append(settingsComponents, inspectClaudeSettings(claudeSettingsPaths(repoRoot)[0]))
+	}
+	components = append(components, settingsComponents...)
+	if c := inspectStalePreCommitHook(filepath.Join(gitHooksDir(repoRoot, gitDir), "pre-commit")); c != nil {
+		components = append(components, c)
+	}
+	if c := inspectLegacyHookScripts(filepath.Join(gitDir, storage.AictDirName, "hooks")); c != nil {
+		components = append(components, c)
+	}
+	return components
+}
+
+// inspectPostCommitHook はpost-commit hookが現在のバージョンのaict呼び出しを1回だけ含むかを検査します。
+// aictが生成したhookはテンプレートと一致すること、ユーザー独自のhookは 'aict hook post-commit' を1行だけ含むことを確認します。
+func inspectPostCommitHook(hookPath string) *hookComponent {
+	c := &hookComponent{name: "post-commit hook", path: hookPath}
+	writeTemplate := func() error {
+		if err := os.MkdirAll(filepath.Dir(hookPath), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(hookPath, []byte(templates.PostCommitHook), 0755)
+	}
+
+	data, err := os.ReadFile(hookPath)
+	if err != nil {
+		c.problems = append(c.problems, "not installed")
+		c.repair = writeTemplate
+		return c
+	}
+	content := string(data)
+
+	if strings.Contains(content, aictHookMarker) {
+		if content != templates.PostCommitHook {
+			c.problems = append(c.problems, "outdated aict hook from another version")
+		}
+		c.repair = writeTemplate
+	} else {
+		calls := aictCommandLines(content)
+		switch {
+		case len(calls) == 0:
+			c.problems = append(c.problems, "existing hook does not call aict")
+		case len(calls) > 1:
+			c.problems = append(c.problems, fmt.Sprintf("aict is called %d times (duplicated)", len(calls)))
+		case calls[0] != postCommitHookCommand:
+			c.problems = append(c.problems, fmt.Sprintf("stale aict call %q", calls[0]))
+		}
+		c.repair = func() error {
+			return os.WriteFile(hookPath, []byte(appendPostCommitCall(content)), 0755)
+		}
+	}
+
+	// Windowsのファイルシステムには実行権限がないため確認しない
+	if info, err := os.Stat(hookPath); err == nil && runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+		c.problems = append(c.problems, "not executable")
+		repairContent := c.repair
+		c.repair = func() error {
+			if err := repairContent(); err != nil {
+				return err
+			}
+			return os.Chmod(hookPath, 0755)
+		}
+	}
+	return c
+}
+
+// aictCommandLines はhookスクリプト中のaict呼び出し行（stripAICTLines が除去する行）を返します。
+func aictCommandLines(content string) []string {
+	var calls []string
+	for _, line := range strings.Split(content, "\n") {
+		if _, changed := stripAICTLines(line); changed {
+			calls = append(calls, strings.TrimSpace(line))
+		}
+	}
+	return calls
+}
+
+// appendPostCommitCall はユーザーhookからaictの呼び出し行を除去し、現在の呼び出しを1行だけ追加します。
+// 末尾が exit の場合はその直前に挿入します。
+func appendPostCommitCall(content string) string {
+	stripped, _ := stripAICTLines(content)
+	lines := strings.Split(strings.TrimRight(stripped, "\n"), "\n")
+
+	insertAt := len(lines)
+	if last := strings.TrimSpace(lines[len(lines)-1]); last == "exit" || strings.HasPrefix(last, "exit ") {
+		insertAt = len(lines) - 1
+	}
+	lines = append(lines[:insertAt], append([]string{postCommitHookCommand}, lines[insertAt:]...)...)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// inspectClaudeSettings はClaude Code設定の各イベントにテンプレートと同じaictのhookが1つだけ登録されているかを検査します。
+// 以前のバージョンの .git/aict/hooks/ 呼び出しやマージで重複したエントリを問題として報告します。
+func inspectClaudeSettings(settingsPath string) *hookComponent {
+	c := &hookComponent{
+		name:   "Claude Code settings (" + filepath.Base(settingsPath) + ")",
+		path:   settingsPath,
+		repair: func() error { return writeAICTSettings(settingsPath) },
+	}
+
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		c.problems = append(c.problems, "not found")
+		return c
+	}
+
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		c.problems = append(c.problems, fmt.Sprintf("invalid JSON: %v", err))
+		c.repair = func() error {
+			return fmt.Errorf("%s is not valid JSON; fix it manually or re-run 'aict setup-hooks'", settingsPath)
+		}
+		return c
+	}
+
+	wantHooks := templateSettingsHooks()
+	hooks, _ := settings["hooks"].(map[string]interface{})
+
+	var wantEvents, events []string
+	for event := range wantHooks {
+		wantEvents = append(wantEvents, event)
+	}
+	for event := range hooks {
+		events = append(events, event)
+	}
+	sort.Strings(wantEvents)
+	sort.Strings(events)
+
+	for _, event := range wantEvents {
+		matchers := aictMatchers(hooks[event])
+		switch {
+		case len(matchers) == 0:
+			c.problems = append(c.problems, event+": aict hook not configured")
+		case len(matchers) > 1:
+			c.problems = append(c.problems, fmt.Sprintf("%s: %d aict entries (duplicated)", event, len(matchers)))
+		case !reflect.DeepEqual(matchers, wantHooks[event]):
+			c.problems = append(c.problems, event+": outdated aict entry")
+		}
+	}
+	for _, event := range events {
+		if _, ok := wantHooks[event]; !ok && len(aictMatchers(hooks[event])) > 0 {
+			c.problems = append(c.problems, event+": stale aict entry")
+		}
+	}
+	return c
+}
+
+// templateSettingsHooks はテンプレートのhook設定をイベントごとのmatcher配列として返します。
+func templateSettingsHooks() map[string][]interface{} {
+	var tmpl struct {
+		Hooks map[string][]interface{} `json:"hooks"`
+	}
+	// テンプレートは定数のため解析に失敗しない（hooks_test.go で検証済み）
+	_ = json.Unmarshal([]byte(templates.ClaudeSettingsJSON), &tmpl)
+	return tmpl.Hooks
+}
+
+// aictMatchers はイベントのmatcher（配列またはオブジェクト形式）のうち、aictのhookのみで構成されるエントリを返します。
+// aictとユーザーのコマンドが混在するエントリも、aictのコマンド部分を問題として検出できるよう含めます。
+func aictMatchers(value interface{}) []interface{} {
+	var found []interface{}
+	for _, m := range hookMatchers(value) {
+		if isAICTMatcher(m) || containsAICTCommand(m) {
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// containsAICTCommand はmatcherエントリのhookにaictのコマンドが1つでも含まれるかを判定します。
+func containsAICTCommand(m interface{}) bool {
+	entry, _ := m.(map[string]interface{})
+	hooks, _ := entry["hooks"].([]interface{})
+	for _, h := range hooks {
+		hook, _ := h.(map[string]interface{})
+		if command, _ := hook["command"].(string); isAICTHookCommand(command) {
+			return true
+		}
+	}
+	return false
+}
+
+// inspectStalePreCommitHook は以前のバージョンが設置したpre-commit hookの残骸を検出します。
+func inspectStalePreCommitHook(hookPath string) *hookComponent {
+	data, err := os.ReadFile(hookPath)
+	if err != nil {
+		return nil
+	}
+	if !strings.Contains(string(data), aictHookMarker) && len(aictCommandLines(string(data))) == 0 {
+		return nil
+	}
+	return &hookComponent{
+		name:     "pre-commit hook",
+		path:     hookPath,
+		problems: []string{"stale aict hook from an older version"},
+		repair:   func() error { return removeGitHook(hookPath) },
+	}
+}
+
+// inspectLegacyHookScripts は以前のバージョンが設置した .git/aict/hooks/ のスクリプトを検出します。
+// Claude Code設定がまだ参照している場合は、設定の修復後に削除されるよう inspectHooks の最後に置きます。
+func inspectLegacyHookScripts(dir string) *hookComponent {
+	if _, err := os.Stat(dir); err != nil {
+		return nil
+	}
+	return &hookComponent{
+		name:     "legacy hook scripts",
+		path:     dir,
+		problems: []string{"scripts from an older version remain"},
+		repair:   func() error { return os.RemoveAll(dir) },
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/templates"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+// setupHooksTest はhook設置済みのリポジトリを作成し、カレントディレクトリを移動します。
+func setupHooksTest(t *testing.T) (repoRoot, gitDir string) {
+	t.Helper()
+	tmpDir := testutil.TempGitRepo(t)
+
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(originalDir) })
+	os.Chdir(tmpDir)
+
+	origArgs := os.Args
+	t.Cleanup(func() { os.Args = origArgs })
+
+	gitDir = filepath.Join(tmpDir, ".git")
+	if err := setupPostCommitHook(filepath.Join(gitDir, "hooks")); err != nil {
+		t.Fatalf("setupPostCommitHook() error = %v", err)
+	}
+	if err := setupClaudeSettings(resolveClaudeSettingsPath(tmpDir, claudeSettingsProject)); err != nil {
+		t.Fatalf("setupClaudeSettings() error = %v", err)
+	}
+	return tmpDir, gitDir
+}
+
+func TestHandleHooksStatus_UpToDate(t *testing.T) {
+	setupHooksTest(t)
+	os.Args = []string{"aict", "hooks", "status"}
+
+	if err := handleHooks(); err != nil {
+		t.Errorf("handleHooks(status) error = %v", err)
+	}
+}
+
+func TestInspectHooks_Problems(t *testing.T) {
+	legacyCommand := `test -x "$CLAUDE_PROJECT_DIR/.git/aict/hooks/pre-tool-use.sh" && "$CLAUDE_PROJECT_DIR/.git/aict/hooks/pre-tool-use.sh" || true`
+
+	tests := []struct {
+		name        string
+		modify      func(t *testing.T, repoRoot, gitDir string)
+		wantProblem string
+	}{
+		{
+			name: "outdated post-commit hook",
+			modify: func(t *testing.T, repoRoot, gitDir string) {
+				writeTestFile(t, filepath.Join(gitDir, "hooks", "post-commit"), "#!/bin/bash\n"+aictHookMarker+"\naict commit\n", 0755)
+			},
+			wantProblem: "post-commit hook: outdated aict hook",
+		},
+		{
+			name: "user hook with duplicated aict calls",
+			modify: func(t *testing.T, repoRoot, gitDir string) {
+				writeTestFile(t, filepath.Join(gitDir, "hooks", "post-commit"), "#!/bin/sh\nmake lint\naict commit\naict hook post-commit\n", 0755)
+			},
+			wantProblem: "aict is called 2 times (duplicated)",
+		},
+		{
+			name: "user hook with stale aict call",
+			modify: func(t *testing.T, repoRoot, gitDir string) {
+				writeTestFile(t, filepath.Join(gitDir, "hooks", "post-commit"), "#!/bin/sh\naict commit\n", 0755)
+			},
+			wantProblem: `stale aict call "aict commit"`,
+		},
+		{
+			name: "duplicated settings entries from a merge",
+			modify: func(t *testing.T, repoRoot, gitDir string) {
+				modifySettingsHooks(t, repoRoot, func(hooks map[string]interface{}) {
+					pre := hooks["PreToolUse"].([]interface{})
+					hooks["PreToolUse"] = append(pre, pre[0])
+				})
+			},
+			wantProblem: "PreToolUse: 2 aict entries (duplicated)",
+		},
+		{
+			name: "legacy script command in settings",
+			modify: func(t *testing.T, repoRoot, gitDir string) {
+				modifySettingsHooks(t, repoRoot, func(hooks map[string]interface{}) {
+					hooks["PostToolUse"] = []interface{}{map[string]interface{}{
+						"matcher": "Write",
+						"hooks":   []interface{}{map[string]interface{}{"type": "command", "command": legacyCommand}},
+					}}
+				})
+			},
+			wantProblem: "PostToolUse: outdated aict entry",
+		},
+		{
+			name: "unguarded command from an older version",
+			modify: func(t *testing.T, repoRoot, gitDir string) {
+				modifySettingsHooks(t, repoRoot, func(hooks map[string]interface{}) {
+					hooks["Stop"] = []interface{}{map[string]interface{}{
+						"hooks": []interface{}{map[string]interface{}{"type": "command", "command": "aict hook stop"}},
+					}}
+				})
+			},
+			wantProblem: "Stop: outdated aict entry",
+		},
+		{
+			name: "stale pre-commit hook",
+			modify: func(t *testing.T, repoRoot, gitDir string) {
+				writeTestFile(t, filepath.Join(gitDir, "hooks", "pre-commit"), "#!/bin/sh\n"+aictHookMarker+"\n", 0755)
+			},
+			wantProblem: "pre-commit hook: stale aict hook",
+		},
+		{
+			name: "legacy hook scripts",
+			modify: func(t *testing.T, repoRoot, gitDir string) {
+				os.MkdirAll(filepath.Join(gitDir, "aict", "hooks"), 0755)
+			},
+			wantProblem: "legacy hook scripts: scripts from an older version remain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoRoot, gitDir := setupHooksTest(t)
+			tt.modify(t, repoRoot, gitDir)
+
+			var problems []string
+			for _, c := range inspectHooks(repoRoot, gitDir) {
+				for _, p := range c.problems {
+					problems = append(problems, c.name+": "+p)
+				}
+			}
+			if !containsSubstring(problems, tt.wantProblem) {
+				t.Fatalf("problems = %q, want one containing %q", problems, tt.wantProblem)
+			}
+
+			os.Args = []string{"aict", "hooks", "status"}
+			if err := handleHooks(); err == nil {
+				t.Error("hooks status should fail when problems are found")
+			}
+
+			os.Args = []string{"aict", "hooks", "repair"}
+			if err := handleHooks(); err != nil {
+				t.Fatalf("hooks repair error = %v", err)
+			}
+			if n := countHookProblems(inspectHooks(repoRoot, gitDir)); n != 0 {
+				t.Errorf("%d problem(s) remain after repair", n)
+			}
+		})
+	}
+}
+
+func TestHandleHooksRepair_KeepsUserContent(t *testing.T) {
+	repoRoot, gitDir := setupHooksTest(t)
+
+	hookPath := filepath.Join(gitDir, "hooks", "post-commit")
+	writeTestFile(t, hookPath, "#!/bin/sh\nmake lint\naict commit\naict commit\nexit 0\n", 0755)
+	modifySettingsHooks(t, repoRoot, func(hooks map[string]interface{}) {
+		pre := hooks["PreToolUse"].([]interface{})
+		hooks["PreToolUse"] = append(pre, pre[0], map[string]interface{}{
+			"matcher": "Bash",
+			"hooks":   []interface{}{map[string]interface{}{"type": "command", "command": "./lint.sh"}},
+		})
+	})
+
+	os.Args = []string{"aict", "hooks", "repair"}
+	if err := handleHooks(); err != nil {
+		t.Fatalf("hooks repair error = %v", err)
+	}
+
+	data, _ := os.ReadFile(hookPath)
+	if want := "#!/bin/sh\nmake lint\naict hook post-commit\nexit 0\n"; string(data) != want {
+		t.Errorf("post-commit = %q, want %q", data, want)
+	}
+
+	var settings map[string]interface{}
+	data, _ = os.ReadFile(filepath.Join(repoRoot, ".claude", "settings.json"))
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("settings.json is not valid JSON: %v", err)
+	}
+	pre := settings["hooks"].(map[string]interface{})["PreToolUse"].([]interface{})
+	if len(pre) != 2 {
+		t.Fatalf("PreToolUse should keep the user matcher and one aict matcher, got %d entries", len(pre))
+	}
+	if !strings.Contains(string(data), "./lint.sh") {
+		t.Error("user hook command should be kept")
+	}
+}
+
+func TestAppendPostCommitCall(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"append at end", "#!/bin/sh\nmake lint\n", "#!/bin/sh\nmake lint\naict hook post-commit\n"},
+		{"insert before exit", "#!/bin/sh\nmake lint\nexit 0\n", "#!/bin/sh\nmake lint\naict hook post-commit\nexit 0\n"},
+		{"replace stale calls", "#!/bin/sh\naict commit\n./bin/aict commit\n", "#!/bin/sh\naict hook post-commit\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := appendPostCommitCall(tt.content); got != tt.want {
+				t.Errorf("appendPostCommitCall() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateSettingsHooks(t *testing.T) {
+	hooks := templateSettingsHooks()
+	for _, event := range []string{"PreToolUse", "PostToolUse", "Stop"} {
+		if len(hooks[event]) != 1 {
+			t.Errorf("template %s should have one matcher, got %d", event, len(hooks[event]))
+		}
+	}
+	if !strings.Contains(templates.ClaudeSettingsJSON, "aict hook pre-tool-use") {
+		t.Error("template should call aict hook pre-tool-use")
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string, perm os.FileMode) {
+	t.Helper()
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatalf("WriteFile(%s) error = %v", path, err)
+	}
+}
+
+func modifySettingsHooks(t *testing.T, repoRoot string, modify func(hooks map[string]interface{})) {
+	t.Helper()
+	settingsPath := filepath.Join(repoRoot, ".claude", "settings.json")
+	var settings map[string]interface{}
+	data, _ := os.ReadFile(settingsPath)
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("parsing settings.json: %v", err)
+	}
+	modify(settings["hooks"].(map[string]interface{}))
+	out, _ := json.MarshalIndent(settings, "", "  ")
+	writeTestFile(t, settingsPath, string(out), 0644)
+}
+
+func containsSubstring(list []string, sub string) bool {
+	for _, s := range list {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// handleImport handles the import command
+// 他のマシン・ブランチからコピーしたチェックポイントファイルを、重複を除いてローカルのチェックポイントに取り込みます。
+func handleImport() error {
+	if len(os.Args) > 2 && os.Args[2] == "claude-transcripts" {
+		return handleImportClaudeTranscripts(os.Args[3:])
+	}
+
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: aict import <file-or-dir>...")
+		fmt.Fprintln(os.Stderr, "       aict import claude-transcripts [--range|--since <spec>] [--window <dur>] [--dry-run] <file-or-dir>...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[2:])
+
+	if flags.NArg() < 1 {
+		flags.Usage()
+		return fmt.Errorf("file or directory is required")
+	}
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return err
+	}
+
+	files, err := collectCheckpointFiles(flags.Args())
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no checkpoint files found (expected latest.json or *.jsonl)")
+	}
+
+	var incoming []*tracker.CheckpointV2
+	for _, file := range files {
+		checkpoints, err := storage.ReadCheckpointsFile(file)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", file, err)
+		}
+		incoming = append(incoming, checkpoints...)
+	}
+
+	// 照合に使われずに期限切れで削除されるもの・チェックポイントでないレコードは取り込まない
+	ttl := cfg.GetCheckpointTTL()
+	now := time.Now()
+	var valid []*tracker.CheckpointV2
+	invalid, expired := 0, 0
+	for _, cp := range incoming {
+		switch {
+		case cp.Timestamp.IsZero() || cp.Author == "":
+			invalid++
+		case now.Sub(cp.Timestamp) >= ttl:
+			expired++
+		default:
+			valid = append(valid, cp)
+		}
+	}
+
+	added, err := store.ImportCheckpoints(valid)
+	if err != nil {
+		return fmt.Errorf("importing checkpoints: %w", err)
+	}
+
+	fmt.Printf("✓ Imported %d checkpoint(s) from %d file(s)\n", added, len(files))
+	if dup := len(valid) - added; dup > 0 {
+		fmt.Printf("  Skipped %d duplicate(s) (same timestamp, author and base commit)\n", dup)
+	}
+	if expired > 0 {
+		fmt.Printf("  Skipped %d checkpoint(s) older than %s (checkpoint_ttl_hours)\n", expired, ttl)
+	}
+	if invalid > 0 {
+		fmt.Printf("  Skipped %d record(s) that are not checkpoints\n", invalid)
+	}
+	if added > 0 {
+		fmt.Println("  They will be matched on the next 'aict commit'")
+	}
+	return nil
+}
+
+// collectCheckpointFiles は引数のファイルと、ディレクトリ配下の latest.json・*.jsonl を重複なく順に返します。
+func collectCheckpointFiles(paths []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(path string) {
+		if abs, err := filepath.Abs(path); err == nil && !seen[abs] {
+			seen[abs] = true
+			files = append(files, path)
+		}
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			add(path)
+			continue
+		}
+
+		var found []string
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && (d.Name() == storage.LatestFileName || strings.HasSuffix(d.Name(), ".jsonl")) {
+				found = append(found, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(found)
+		for _, p := range found {
+			add(p)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// writeCheckpointsFile はチェックポイントをJSONLで書き出します。
+func writeCheckpointsFile(t *testing.T, path string, checkpoints ...*tracker.CheckpointV2) {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, cp := range checkpoints {
+		data, err := json.Marshal(cp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(append(data, '\n'))
+	}
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHandleImport(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+	origDir, _ := os.Getwd()
+	origArgs := os.Args
+	defer func() {
+		os.Chdir(origDir)
+		os.Args = origArgs
+	}()
+	os.Chdir(tmpDir)
+
+	now := time.Now().Truncate(time.Second)
+	recent := &tracker.CheckpointV2{Author: "claude", Type: tracker.AuthorTypeAI, BaseCommit: "abc", Timestamp: now.Add(-time.Hour)}
+	other := &tracker.CheckpointV2{Author: "human", Type: tracker.AuthorTypeHuman, BaseCommit: "abc", Timestamp: now.Add(-30 * time.Minute)}
+	old := &tracker.CheckpointV2{Author: "claude", Type: tracker.AuthorTypeAI, BaseCommit: "abc", Timestamp: now.Add(-48 * time.Hour)}
+
+	// 別のマシンからコピーしたディレクトリ（同じチェックポイントが2つのファイルにある）と単独のファイル
+	importDir := t.TempDir()
+	writeCheckpointsFile(t, filepath.Join(importDir, "laptop", "checkpoints", "latest.json"), recent, old)
+	writeCheckpointsFile(t, filepath.Join(importDir, "desktop.jsonl"), recent)
+	os.WriteFile(filepath.Join(importDir, "notes.txt"), []byte("not a checkpoint file"), 0644)
+	single := filepath.Join(t.TempDir(), "export.json")
+	writeCheckpointsFile(t, single, other)
+
+	run := func(args ...string) (string, error) {
+		os.Args = append([]string{"aict", "import"}, args...)
+		origStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := handleImport()
+		w.Close()
+		os.Stdout = origStdout
+		var buf bytes.Buffer
+		buf.ReadFrom(r)
+		return buf.String(), err
+	}
+
+	output, err := run(importDir, single)
+	if err != nil {
+		t.Fatalf("handleImport() error = %v", err)
+	}
+	for _, want := range []string{"✓ Imported 2 checkpoint(s) from 3 file(s)", "Skipped 1 duplicate(s)", "Skipped 1 checkpoint(s) older than 24h0m0s"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	store, _ := storage.NewAIctStorage()
+	checkpoints, _ := store.LoadCheckpoints()
+	if len(checkpoints) != 2 || checkpoints[0].Author != "claude" || checkpoints[1].Author != "human" {
+		t.Errorf("stored checkpoints = %+v", checkpoints)
+	}
+
+	// 再度取り込んでも増えない
+	output, err = run(importDir)
+	if err != nil || !strings.Contains(output, "✓ Imported 0 checkpoint(s)") {
+		t.Errorf("second import = %q, %v", output, err)
+	}
+
+	if _, err := run(t.TempDir()); err == nil {
+		t.Error("handleImport() should fail when no checkpoint files are found")
+	}
+}
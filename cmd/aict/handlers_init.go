@@ -26,33 +26,18 @@ func handleInitV2WithOptions(withHooks bool) error {
 	}
 
 	// デフォルト設定を作成
-	gitUserName := getGitUserName()
-	if gitUserName == "" {
-		gitUserName = "Developer"
-	}
+	config := newDefaultConfig()
 
-	config := &tracker.Config{
-		TargetAIPercentage: 80.0,
-		TrackedExtensions: []string{
-			".go", ".py", ".js", ".ts", ".java",
-			".cpp", ".c", ".h", ".rs", ".rb",
-			".php", ".swift", ".kt", ".cs",
-		},
-		ExcludePatterns: []string{
-			"*_test.go",
-			"*_generated.go",
-			"vendor/*",
-			"node_modules/*",
-			"*.min.js",
-		},
-		DefaultAuthor: gitUserName,
-		AIAgents: []string{
-			"Claude Code",
-			"Claude",
-			"GitHub Copilot",
-			"ChatGPT",
-			"Cursor",
-		},
+	// リポジトリの言語構成を計測（レポートでAI%を解釈する際の参考情報）
+	if patterns, err := storage.LoadIgnorePatterns(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else {
+		config.IgnorePatterns = patterns
+	}
+	if profile, err := scanRepoLanguages(config.ExcludeMatcher()); err != nil {
+		debugf("repository language scan skipped: %v", err)
+	} else if len(profile.Languages) > 0 {
+		config.RepoLanguages = profile
 	}
 
 	// 設定を保存
@@ -64,6 +49,9 @@ func handleInitV2WithOptions(withHooks bool) error {
 	fmt.Printf("✓ Configuration saved to .git/aict/config.json\n")
 	fmt.Printf("✓ Default author: %s\n", config.DefaultAuthor)
 	fmt.Printf("✓ Target AI percentage: %.0f%%\n", config.TargetAIPercentage)
+	if config.RepoLanguages != nil {
+		fmt.Printf("✓ Repository languages: %s\n", repoLanguageSummary(config.RepoLanguages.Languages, repoLanguageSummaryLimit))
+	}
 	fmt.Println()
 
 	// hooks設定の判定
@@ -77,7 +65,7 @@ func handleInitV2WithOptions(withHooks bool) error {
 
 	if setupHooks {
 		fmt.Println()
-		if err := handleSetupHooksV2(); err != nil {
+		if err := installHooks(claudeSettingsProject, false); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: hook setup failed: %v\n", err)
 			fmt.Println("You can set up hooks later with 'aict setup-hooks'")
 		}
@@ -91,3 +79,36 @@ func handleInitV2WithOptions(withHooks bool) error {
 	}
 	return nil
 }
+
+// newDefaultConfig は aict init が作成するデフォルト設定を返します（作成者名は git config user.name）。
+func newDefaultConfig() *tracker.Config {
+	gitUserName := getGitUserName()
+	if gitUserName == "" {
+		gitUserName = "Developer"
+	}
+
+	return &tracker.Config{
+		TargetAIPercentage: 80.0,
+		TrackedExtensions: []string{
+			".go", ".py", ".js", ".ts", ".java",
+			".cpp", ".c", ".h", ".rs", ".rb",
+			".php", ".swift", ".kt", ".cs",
+		},
+		ExcludePatterns: []string{
+			"*_test.go",
+			"*_generated.go",
+			"vendor/*",
+			"node_modules/*",
+			"*.min.js",
+		},
+		DefaultAuthor: gitUserName,
+		AIAgents: []string{
+			"Claude Code",
+			"Claude",
+			"GitHub Copilot",
+			"ChatGPT",
+			"Cursor",
+		},
+		CommitPatterns: defaultCommitPatterns,
+	}
+}
@@ -97,8 +97,8 @@ func TestHandleInitV2_InteractiveYes(t *testing.T) {
 	}
 
 	// hooks が作成されている
-	preHookPath := filepath.Join(tmpDir, ".git", "aict", "hooks", "pre-tool-use.sh")
-	testutil.AssertFileExists(t, preHookPath)
+	testutil.AssertFileExists(t, filepath.Join(tmpDir, ".git", "hooks", "post-commit"))
+	testutil.AssertFileExists(t, filepath.Join(tmpDir, ".claude", "settings.json"))
 }
 
 func TestHandleInitV2_InteractiveDefaultIsYes(t *testing.T) {
@@ -114,8 +114,7 @@ func TestHandleInitV2_InteractiveDefaultIsYes(t *testing.T) {
 		t.Fatalf("handleInitV2() error = %v", err)
 	}
 
-	preHookPath := filepath.Join(tmpDir, ".git", "aict", "hooks", "pre-tool-use.sh")
-	testutil.AssertFileExists(t, preHookPath)
+	testutil.AssertFileExists(t, filepath.Join(tmpDir, ".git", "hooks", "post-commit"))
 }
 
 func TestHandleInitV2WithOptions_WithHooks(t *testing.T) {
@@ -134,8 +133,6 @@ func TestHandleInitV2WithOptions_WithHooks(t *testing.T) {
 	configPath := filepath.Join(tmpDir, ".git", "aict", "config.json")
 	testutil.AssertFileExists(t, configPath)
 
-	preHookPath := filepath.Join(tmpDir, ".git", "aict", "hooks", "pre-tool-use.sh")
-	testutil.AssertFileExists(t, preHookPath)
-	postHookPath := filepath.Join(tmpDir, ".git", "aict", "hooks", "post-tool-use.sh")
-	testutil.AssertFileExists(t, postHookPath)
+	testutil.AssertFileExists(t, filepath.Join(tmpDir, ".git", "hooks", "post-commit"))
+	testutil.AssertFileExists(t, filepath.Join(tmpDir, ".claude", "settings.json"))
 }
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/git"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// defaultNotesRemote はAuthorship Logを同期する既定のリモート
+const defaultNotesRemote = "origin"
+
+const notesUsage = "Usage: aict notes [push|fetch|sync] [--remote <name>] | aict notes propagate [--range <spec>] [--dry-run]"
+
+// defaultPropagateCommits は notes propagate で --range を省略した場合に調べる HEAD からのコミット数
+const defaultPropagateCommits = 200
+
+// handleNotes handles the notes command
+// Authorship Logの Git notes をリモートと push・fetch・sync（fetchして取り込んでからpush）します。
+func handleNotes() error {
+	if len(os.Args) < 3 {
+		fmt.Println(notesUsage)
+		return fmt.Errorf("notes subcommand required")
+	}
+
+	subcommand := os.Args[2]
+	if subcommand == "propagate" {
+		return handleNotesPropagate(os.Args[3:])
+	}
+	fs := flag.NewFlagSet("notes "+subcommand, flag.ExitOnError)
+	remote := fs.String("remote", defaultNotesRemote, "Remote to sync authorship logs with")
+	fs.Parse(os.Args[3:])
+
+	switch subcommand {
+	case "push":
+		return notesPush(*remote)
+	case "fetch":
+		return notesFetch(*remote)
+	case "sync":
+		if err := notesFetch(*remote); err != nil {
+			return err
+		}
+		return notesPush(*remote)
+	default:
+		fmt.Printf("Unknown subcommand: %s\n", subcommand)
+		fmt.Println(notesUsage)
+		return fmt.Errorf("unknown subcommand: %s", subcommand)
+	}
+}
+
+// notesPush はローカルのAuthorship Logをリモートにpushします。
+func notesPush(remote string) error {
+	err := gitnotes.NewNotesManagerWithExecutor(newExecutor()).Push(remote)
+	if errors.Is(err, gitnotes.ErrNoLocalNotes) {
+		fmt.Println("No authorship logs to push yet")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("pushing authorship logs to %s (if the remote has newer logs, run 'aict notes sync'): %w", remote, err)
+	}
+	fmt.Printf("✓ Authorship logs pushed to %s\n", remote)
+	return syncCheckpointNotes(remote, true)
+}
+
+// notesFetch はリモートのAuthorship Logを取得してローカルのノートに取り込みます。
+func notesFetch(remote string) error {
+	err := gitnotes.NewNotesManagerWithExecutor(newExecutor()).Fetch(remote)
+	if errors.Is(err, gitnotes.ErrNoRemoteNotes) {
+		fmt.Printf("No authorship logs on %s yet\n", remote)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fetching authorship logs from %s: %w", remote, err)
+	}
+	fmt.Printf("✓ Authorship logs fetched from %s\n", remote)
+	return syncCheckpointNotes(remote, false)
+}
+
+// handleNotesPropagate は cherry-pick・rebase で作り直されたコミットに、patch-id が同じ元のコミットの
+// Authorship Log をコピーします。対象はAuthorship Logのないマージ以外のコミットです。
+func handleNotesPropagate(args []string) error {
+	fs := flag.NewFlagSet("notes propagate", flag.ExitOnError)
+	rangeSpec := fs.String("range", "", fmt.Sprintf("Commits to fill in (default: the last %d commits of HEAD)", defaultPropagateCommits))
+	dryRun := fs.Bool("dry-run", false, "Show the commits that would receive authorship logs without writing them")
+	fs.Parse(args)
+
+	store, _, err := loadStorageAndConfig()
+	if err != nil {
+		return err
+	}
+	executor := newExecutor()
+	revListArgs := []string{"rev-list", "--no-merges"}
+	if *rangeSpec == "" {
+		revListArgs = append(revListArgs, fmt.Sprintf("--max-count=%d", defaultPropagateCommits), "HEAD")
+	} else {
+		if err := gitexec.ValidateRevisionArg(*rangeSpec); err != nil {
+			return err
+		}
+		revListArgs = append(revListArgs, *rangeSpec)
+	}
+	output, err := executor.Run(revListArgs...)
+	if err != nil {
+		return fmt.Errorf("listing commits: %w", err)
+	}
+
+	nm := gitnotes.NewNotesManagerWithExecutor(executor)
+	logs, err := nm.ListAuthorshipLogs()
+	if err != nil {
+		return err
+	}
+	var targets []string
+	for _, commit := range strings.Fields(output) {
+		if _, ok := logs[commit]; !ok {
+			targets = append(targets, commit)
+		}
+	}
+	if len(targets) == 0 || len(logs) == 0 {
+		fmt.Println("No commits to propagate authorship logs to")
+		return nil
+	}
+
+	copies, err := findPropagationSources(executor, targets, logs)
+	if err != nil {
+		return err
+	}
+	if len(copies) == 0 {
+		fmt.Printf("No cherry-picked or rebased commits found among %d commits without authorship logs\n", len(targets))
+		return nil
+	}
+
+	verb := "Copied"
+	if *dryRun {
+		verb = "Would copy"
+	}
+	var written []string
+	for _, target := range targets {
+		source, ok := copies[target]
+		if !ok {
+			continue
+		}
+		if !*dryRun {
+			log := *logs[source]
+			log.Commit = target
+			if committed, err := executor.Run("log", "-1", "--format=%cI", target); err == nil {
+				if t, err := time.Parse(time.RFC3339, committed); err == nil {
+					log.Timestamp = t
+				}
+			}
+			if err := nm.AddAuthorshipLog(&log); err != nil {
+				return fmt.Errorf("saving authorship log for %s: %w", shortCommit(target), err)
+			}
+			written = append(written, target)
+		}
+		fmt.Printf("✓ %s authorship log %s → %s\n", verb, shortCommit(source), shortCommit(target))
+	}
+
+	if len(written) > 0 {
+		// コピー先のコミットを含む日次集計は作成者のない状態で集計されているため破棄
+		if _, err := store.DropDailyRollups(written); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to invalidate daily rollups: %v\n", err)
+		}
+		fmt.Println("  Run 'aict notes push' to share the propagated authorship logs")
+	}
+	return nil
+}
+
+// findPropagationSources は targets のコミットごとに、patch-id が同じで Authorship Log のあるコミットを返します。
+// 同じ patch-id のコミットが複数ある場合はハッシュ順で最初のものを使います。
+func findPropagationSources(executor gitexec.Executor, targets []string, logs map[string]*tracker.AuthorshipLog) (map[string]string, error) {
+	sources := make([]string, 0, len(logs))
+	for commit := range logs {
+		sources = append(sources, commit)
+	}
+	sort.Strings(sources)
+
+	sourceIDs, err := git.PatchIDs(executor, sources)
+	if err != nil {
+		return nil, err
+	}
+	byPatchID := make(map[string]string, len(sourceIDs))
+	for _, commit := range sources {
+		if id, ok := sourceIDs[commit]; ok {
+			if _, seen := byPatchID[id]; !seen {
+				byPatchID[id] = commit
+			}
+		}
+	}
+
+	targetIDs, err := git.PatchIDs(executor, targets)
+	if err != nil {
+		return nil, err
+	}
+	copies := make(map[string]string)
+	for commit, id := range targetIDs {
+		if source, ok := byPatchID[id]; ok && source != commit {
+			copies[commit] = source
+		}
+	}
+	return copies, nil
+}
+
+// syncCheckpointNotes は checkpoint_storage が notes の場合に、チェックポイントのノートもpush（fetch）します。
+func syncCheckpointNotes(remote string, push bool) error {
+	store, err := storage.NewAIctStorage()
+	if err != nil || store.CheckpointStorage() != storage.CheckpointStorageNotes {
+		return nil
+	}
+	nm := gitnotes.NewNotesManagerWithExecutor(newExecutor())
+	if push {
+		err = nm.PushCheckpoints(remote)
+	} else {
+		err = nm.FetchCheckpoints(remote)
+	}
+	if errors.Is(err, gitnotes.ErrNoLocalNotes) || errors.Is(err, gitnotes.ErrNoRemoteNotes) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("syncing checkpoints with %s: %w", remote, err)
+	}
+	if push {
+		fmt.Printf("✓ Checkpoints pushed to %s\n", remote)
+	} else {
+		fmt.Printf("✓ Checkpoints fetched from %s\n", remote)
+	}
+	return nil
+}
+
+// configureNotesRefspecs はリモートの設定にAuthorship Logの refspec を追加し、
+// 通常の git fetch（pushNotes の場合は git push も）でノートが同期されるようにします。
+// 追加した場合は true を返します。リモートが設定されていない場合は何もしません。
+func configureNotesRefspecs(executor gitexec.Executor, remote string, pushNotes bool) (bool, error) {
+	if _, err := executor.Run("remote", "get-url", remote); err != nil {
+		return false, nil
+	}
+
+	added := false
+	add := func(key, value string) error {
+		// 既に同じ値があれば追加しない（--get の値は正規表現のためエスケープする）
+		if _, err := executor.Run("config", "--get", key, "^"+regexp.QuoteMeta(value)+"$"); err == nil {
+			return nil
+		}
+		if _, err := executor.Run("config", "--add", key, value); err != nil {
+			return fmt.Errorf("adding %s: %w", key, err)
+		}
+		added = true
+		return nil
+	}
+
+	if err := add("remote."+remote+".fetch", gitnotes.FetchRefspec(remote)); err != nil {
+		return added, err
+	}
+	if pushNotes {
+		// push の refspec を設定すると引数なしの git push はそれだけをpushするため、現在のブランチも含める
+		if err := add("remote."+remote+".push", "HEAD"); err != nil {
+			return added, err
+		}
+		if err := add("remote."+remote+".push", gitnotes.AuthorshipPushRefspec); err != nil {
+			return added, err
+		}
+	}
+	return added, nil
+}
+
+// removeNotesRefspecs は configureNotesRefspecs が追加した refspec を取り除きます。
+// push の "HEAD" は利用者が設定した可能性もあるため、ノートの refspec がある場合のみ取り除きます。
+func removeNotesRefspecs(executor gitexec.Executor, remote string) bool {
+	removed := false
+	unset := func(key, value string) bool {
+		if _, err := executor.Run("config", "--unset-all", key, "^"+regexp.QuoteMeta(value)+"$"); err != nil {
+			return false
+		}
+		removed = true
+		return true
+	}
+
+	unset("remote."+remote+".fetch", gitnotes.FetchRefspec(remote))
+	if unset("remote."+remote+".push", gitnotes.AuthorshipPushRefspec) {
+		unset("remote."+remote+".push", "HEAD")
+	}
+	return removed
+}
+
+// configureNotesRewrite は notes.rewriteRef にAuthorship Logの ref を追加し、
+// git rebase・git commit --amend で書き換えたコミットにノートがコピーされるようにします。
+// 追加した場合は true を返します。
+func configureNotesRewrite(executor gitexec.Executor) (bool, error) {
+	if _, err := executor.Run("config", "--get", "notes.rewriteRef", "^"+regexp.QuoteMeta(gitnotes.AuthorshipNotesFullRef)+"$"); err == nil {
+		return false, nil
+	}
+	if _, err := executor.Run("config", "--add", "notes.rewriteRef", gitnotes.AuthorshipNotesFullRef); err != nil {
+		return false, fmt.Errorf("adding notes.rewriteRef: %w", err)
+	}
+	return true, nil
+}
+
+// removeNotesRewrite は configureNotesRewrite が追加した notes.rewriteRef を取り除きます。
+func removeNotesRewrite(executor gitexec.Executor) bool {
+	_, err := executor.Run("config", "--unset-all", "notes.rewriteRef", "^"+regexp.QuoteMeta(gitnotes.AuthorshipNotesFullRef)+"$")
+	return err == nil
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// runNotesCommand は aict notes を実行し、標準出力を返します。
+func runNotesCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	os.Args = append([]string{"aict", "notes"}, args...)
+	origStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := handleNotes()
+
+	w.Close()
+	os.Stdout = origStdout
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	return buf.String(), err
+}
+
+func TestHandleNotes_Sync(t *testing.T) {
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	remote := t.TempDir()
+	if out, err := exec.Command("git", "init", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare failed: %v\n%s", err, out)
+	}
+
+	// 2つのクローン相当のリポジトリでそれぞれAuthorship Logを作成
+	addNote := func(dir, file string) {
+		t.Helper()
+		os.Chdir(dir)
+		if out, err := exec.Command("git", "remote", "add", "origin", remote).CombinedOutput(); err != nil {
+			t.Fatalf("git remote add failed: %v\n%s", err, out)
+		}
+		testutil.CreateTestFile(t, dir, file, "package main\n")
+		commit := testutil.GitCommit(t, dir, "Add "+file)
+		if err := gitnotes.NewNotesManager().AddAuthorshipLog(&tracker.AuthorshipLog{
+			Version: "1.0",
+			Commit:  commit,
+			Files: map[string]tracker.FileInfo{
+				file: {Authors: []tracker.AuthorInfo{{Name: "Claude Code", Type: tracker.AuthorTypeAI, Lines: [][]int{{1}}}}},
+			},
+		}); err != nil {
+			t.Fatalf("AddAuthorshipLog() error = %v", err)
+		}
+	}
+	noteCount := func() int {
+		t.Helper()
+		out, err := gitexec.NewExecutor().Run("notes", "--ref="+gitnotes.AuthorshipNotesRef, "list")
+		if err != nil || out == "" {
+			return 0
+		}
+		return len(strings.Split(out, "\n"))
+	}
+
+	repoA := testutil.TempGitRepo(t)
+	repoB := testutil.TempGitRepo(t)
+	addNote(repoA, "a.go")
+	addNote(repoB, "b.go")
+
+	tests := []struct {
+		name      string
+		dir       string
+		args      []string
+		wantText  []string
+		wantNotes int
+	}{
+		{"first sync pushes to an empty remote", repoA, []string{"sync"}, []string{"No authorship logs on origin yet", "✓ Authorship logs pushed to origin"}, 1},
+		{"sync merges remote logs before pushing", repoB, []string{"sync", "--remote", "origin"}, []string{"✓ Authorship logs fetched from origin", "✓ Authorship logs pushed to origin"}, 2},
+		{"fetch brings in logs pushed by others", repoA, []string{"fetch"}, []string{"✓ Authorship logs fetched from origin"}, 2},
+		{"push after fetch", repoA, []string{"push"}, []string{"✓ Authorship logs pushed to origin"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Chdir(tt.dir)
+			output, err := runNotesCommand(t, tt.args...)
+			if err != nil {
+				t.Fatalf("handleNotes() error = %v\n%s", err, output)
+			}
+			for _, want := range tt.wantText {
+				if !strings.Contains(output, want) {
+					t.Errorf("output missing %q:\n%s", want, output)
+				}
+			}
+			if got := noteCount(); got != tt.wantNotes {
+				t.Errorf("local notes = %d, want %d", got, tt.wantNotes)
+			}
+		})
+	}
+
+	t.Run("unknown subcommand", func(t *testing.T) {
+		if _, err := runNotesCommand(t, "pull"); err == nil {
+			t.Error("expected error for unknown subcommand")
+		}
+	})
+	t.Run("push without local logs", func(t *testing.T) {
+		os.Chdir(testutil.TempGitRepo(t))
+		output, err := runNotesCommand(t, "push")
+		if err != nil || !strings.Contains(output, "No authorship logs to push yet") {
+			t.Errorf("handleNotes() = %q, %v", output, err)
+		}
+	})
+}
+
+func TestConfigureNotesRefspecs(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	executor := gitexec.NewExecutor()
+	getAll := func(key string) string {
+		out, _ := executor.Run("config", "--get-all", key)
+		return out
+	}
+
+	// リモートがなければ何もしない
+	if added, err := configureNotesRefspecs(executor, "origin", true); err != nil || added {
+		t.Fatalf("configureNotesRefspecs() without remote = %v, %v", added, err)
+	}
+
+	executor.Run("remote", "add", "origin", "https://example.com/repo.git")
+	for i, wantAdded := range []bool{true, false} {
+		added, err := configureNotesRefspecs(executor, "origin", true)
+		if err != nil || added != wantAdded {
+			t.Fatalf("configureNotesRefspecs() call %d = %v, %v; want added %v", i+1, added, err, wantAdded)
+		}
+	}
+	fetch := getAll("remote.origin.fetch")
+	if !strings.Contains(fetch, "+refs/heads/*:refs/remotes/origin/*") || !strings.Contains(fetch, gitnotes.FetchRefspec("origin")) {
+		t.Errorf("remote.origin.fetch = %q", fetch)
+	}
+	if push := getAll("remote.origin.push"); push != "HEAD\n"+gitnotes.AuthorshipPushRefspec {
+		t.Errorf("remote.origin.push = %q", push)
+	}
+
+	if !removeNotesRefspecs(executor, "origin") {
+		t.Fatal("removeNotesRefspecs() = false, want true")
+	}
+	if fetch := getAll("remote.origin.fetch"); fetch != "+refs/heads/*:refs/remotes/origin/*" {
+		t.Errorf("remote.origin.fetch after removal = %q", fetch)
+	}
+	if push := getAll("remote.origin.push"); push != "" {
+		t.Errorf("remote.origin.push after removal = %q", push)
+	}
+	if removeNotesRefspecs(executor, "origin") {
+		t.Error("second removeNotesRefspecs() = true, want false")
+	}
+}
+
+func TestHandleNotes_Propagate(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	executor := newExecutor()
+	mainBranch, _ := executor.Run("symbolic-ref", "--short", "HEAD")
+
+	git("checkout", "-q", "-b", "feature")
+	testutil.CreateTestFile(t, tmpDir, "ai.go", "package main\n\nfunc ai() {}\n")
+	original := testutil.GitCommit(t, tmpDir, "Add ai")
+	nm := gitnotes.NewNotesManager()
+	if err := nm.AddAuthorshipLog(&tracker.AuthorshipLog{
+		Version: "1.0",
+		Commit:  original,
+		Files: map[string]tracker.FileInfo{
+			"ai.go": {Authors: []tracker.AuthorInfo{{Name: "Claude Code", Type: tracker.AuthorTypeAI, Lines: [][]int{{1, 3}}}}},
+		},
+	}); err != nil {
+		t.Fatalf("AddAuthorshipLog() error = %v", err)
+	}
+
+	// main に別のコミットを作ってから cherry-pick（ハッシュは変わるが差分は同じ）
+	git("checkout", "-q", mainBranch)
+	testutil.CreateTestFile(t, tmpDir, "human.go", "package main\n")
+	unrelated := testutil.GitCommit(t, tmpDir, "Add human")
+	git("cherry-pick", original)
+	picked, _ := executor.Run("rev-parse", "HEAD")
+
+	if _, err := runNotesCommand(t, "propagate", "--dry-run"); err != nil {
+		t.Fatalf("notes propagate --dry-run error = %v", err)
+	}
+	if log, _ := nm.GetAuthorshipLog(picked); log != nil {
+		t.Fatal("--dry-run should not write authorship logs")
+	}
+
+	out, err := runNotesCommand(t, "propagate")
+	if err != nil {
+		t.Fatalf("notes propagate error = %v", err)
+	}
+	if !strings.Contains(out, shortCommit(picked)) {
+		t.Errorf("output should mention the cherry-picked commit:\n%s", out)
+	}
+	log, err := nm.GetAuthorshipLog(picked)
+	if err != nil || log == nil {
+		t.Fatalf("cherry-picked commit should have an authorship log (err=%v)", err)
+	}
+	if log.Commit != picked || log.Files["ai.go"].Authors[0].Type != tracker.AuthorTypeAI {
+		t.Errorf("propagated log = %+v", log)
+	}
+	if log, _ := nm.GetAuthorshipLog(unrelated); log != nil {
+		t.Error("commits with a different patch should not receive a log")
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/ownership"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// handleOwnership handles the ownership command
+func handleOwnership() error {
+	fs := flag.NewFlagSet("ownership", flag.ExitOnError)
+	rev := fs.String("rev", "HEAD", "Revision to export")
+	output := fs.String("output", "", "Write JSON to this file instead of stdout")
+	fields := fs.String("fields", "", fieldsFlagUsage)
+	top := fs.Int("top", 0, "Print a table of the N files with the most AI-generated code instead of JSON")
+	byDir := fs.Bool("by-dir", false, "Print a per-directory table instead of JSON")
+	depth := fs.Int("depth", 1, "Directory depth for --by-dir")
+	sortKey := fs.String("sort", fileSortAI, "Sort order for --top/--by-dir: ai or lines")
+	since := fs.String("since", "", "Only attribute lines committed on or after this date (YYYY-MM-DD or RFC3339; default: adoption_date in config, 'all' for every line)")
+	fs.Parse(os.Args[2:])
+
+	tableView := *top > 0 || *byDir
+	if *top < 0 {
+		return fmt.Errorf("--top must be positive, got %d", *top)
+	}
+	if *sortKey != fileSortLines && *sortKey != fileSortAI {
+		return fmt.Errorf("unknown sort order: %s (available: %s, %s)", *sortKey, fileSortLines, fileSortAI)
+	}
+	if tableView && (*output != "" || *fields != "") {
+		return fmt.Errorf("--top and --by-dir print a table; they cannot be combined with --output or --fields")
+	}
+
+	_, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	analyzer := ownership.NewAnalyzer(newExecutor())
+	analyzer.Include = func(path string) bool { return tracker.IsTrackedFile(path, cfg) }
+	analyzer.Debugf = debugf
+	if analyzer.Since, err = ownershipSince(*since, cfg); err != nil {
+		return err
+	}
+	m, err := analyzer.Analyze(*rev, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	if tableView {
+		printOwnershipTables(m, *top, *byDir, *depth, *sortKey)
+		return nil
+	}
+
+	data, err := marshalJSONFields(m, *fields)
+	if err != nil {
+		return fmt.Errorf("formatting JSON: %w", err)
+	}
+
+	if *output == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+	if dir := filepath.Dir(*output); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("creating output directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(*output, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing ownership map: %w", err)
+	}
+	fmt.Printf("✓ Ownership map for %d files written to %s\n", len(m.Files), *output)
+	return nil
+}
+
+// ownershipSince は --since（未指定時は設定の adoption_date）から帰属の対象とする期間の開始を返します。
+// "all" の場合と、どちらも未指定の場合はゼロ値（すべての行が対象）です。
+func ownershipSince(flagValue string, cfg *tracker.Config) (time.Time, error) {
+	switch {
+	case flagValue == "all":
+		return time.Time{}, nil
+	case flagValue != "":
+		t, err := tracker.ParseDateTime(flagValue)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --since %q: use YYYY-MM-DD, RFC3339 or 'all'", flagValue)
+		}
+		return t, nil
+	case cfg.AdoptionDate != "":
+		return tracker.ParseDateTime(cfg.AdoptionDate)
+	}
+	return time.Time{}, nil
+}
+
+// printOwnershipTables はAI生成コードの多いファイル（--top）またはディレクトリ（--by-dir）を表形式で出力します。
+// top が正の場合は先頭の top 件に絞ります。Authorship Logのない行は Lines にのみ含まれます。
+func printOwnershipTables(m *tracker.OwnershipMap, top int, byDir bool, depth int, sortKey string) {
+	byFile := make(map[string]*tracker.FileStats, len(m.Files))
+	for _, f := range m.Files {
+		byFile[f.Path] = &tracker.FileStats{
+			Path:       f.Path,
+			AILines:    f.AILines,
+			HumanLines: f.HumanLines,
+			TotalLines: f.AILines + f.HumanLines + f.UnknownLines,
+		}
+	}
+
+	sum := m.Summary
+	fmt.Printf("Ownership at %s: %d files, %.1f%% AI (%d AI / %d human / %d unknown lines)\n",
+		shortCommit(m.Commit), len(m.Files), sum.AIPercentage, sum.AILines, sum.HumanLines, sum.UnknownLines)
+	if m.Since != nil {
+		fmt.Printf("Lines committed since %s only (%d older lines excluded)\n", m.Since.Local().Format("2006-01-02"), sum.ExcludedLines)
+	}
+	fmt.Println()
+
+	title, column, stats := "By File:", "File", buildFileStats(byFile, sortKey)
+	if byDir {
+		title, column, stats = "By Directory:", "Directory", buildDirStats(byFile, depth, sortKey)
+	}
+	if top > 0 && len(stats) > top {
+		stats = stats[:top]
+		title = fmt.Sprintf("Top %d %s", top, strings.TrimPrefix(title, "By "))
+	}
+	printFileStats(title, column, stats)
+}
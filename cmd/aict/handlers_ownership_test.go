@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/ownership"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestHandleOwnership(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	base := testutil.GitCommit(t, tmpDir, "initial")
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc a() {}\n")
+	testutil.CreateTestFile(t, tmpDir, "README.md", "# readme\n")
+	commit := testutil.GitCommit(t, tmpDir, "Add a")
+
+	nm := gitnotes.NewNotesManager()
+	if err := nm.AddAuthorshipLog(&tracker.AuthorshipLog{
+		Version: "1.0",
+		Commit:  commit,
+		Files: map[string]tracker.FileInfo{
+			"main.go": {Authors: []tracker.AuthorInfo{{Name: "Claude Code", Type: tracker.AuthorTypeAI, Lines: [][]int{{1, 2}}, Metadata: map[string]string{"model": "sonnet"}}}},
+		},
+	}); err != nil {
+		t.Fatalf("AddAuthorshipLog() error = %v", err)
+	}
+
+	// GitCommit は短縮ハッシュを返すため、出力と比較する完全なハッシュに解決
+	executor := newExecutor()
+	base, _ = executor.Run("rev-parse", base)
+	commit, _ = executor.Run("rev-parse", commit)
+
+	out := filepath.Join(tmpDir, "out", "ownership.json")
+	os.Args = []string{"aict", "ownership", "--output", out}
+	if err := handleOwnership(); err != nil {
+		t.Fatalf("handleOwnership() error = %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	var m tracker.OwnershipMap
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if m.Commit != commit || m.Version != ownership.MapVersion {
+		t.Errorf("commit/version = %s/%d", m.Commit, m.Version)
+	}
+	// README.md は追跡対象外
+	if len(m.Files) != 1 || m.Files[0].Path != "main.go" {
+		t.Fatalf("files = %+v, want only main.go", m.Files)
+	}
+	want := []tracker.OwnershipRange{
+		{Start: 1, End: 1, Type: ownership.TypeUnknown, Commit: base},
+		{Start: 2, End: 3, Type: "ai", Author: "Claude Code", Tool: "sonnet", Commit: commit},
+	}
+	if f := m.Files[0]; f.AILines != 2 || f.HumanLines != 0 || f.UnknownLines != 1 {
+		t.Errorf("file totals = %+v, want 2 AI and 1 unknown lines", f)
+	}
+	if s := m.Summary; s.AILines != 2 || s.UnknownLines != 1 || s.Commits != 2 || s.CommitsWithLogs != 1 || s.AIPercentage < 66.6 || s.AIPercentage > 66.7 {
+		t.Errorf("summary = %+v", s)
+	}
+	got := m.Files[0].Ranges
+	if len(got) != len(want) {
+		t.Fatalf("ranges = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("range %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	os.Args = []string{"aict", "ownership", "--rev", "no-such-rev"}
+	if err := handleOwnership(); err == nil {
+		t.Error("expected error for invalid revision")
+	}
+
+	tableTests := []struct {
+		name     string
+		args     []string
+		wantErr  bool
+		wantText []string
+	}{
+		{"top files", []string{"--top", "1"}, false, []string{"Ownership at " + shortCommit(commit) + ": 1 files, 66.7% AI (2 AI / 0 human / 1 unknown lines)", "By File:", "main.go"}},
+		{"by dir", []string{"--by-dir"}, false, []string{"By Directory:", "  .  "}},
+		{"top with output", []string{"--top", "5", "--output", out}, true, nil},
+		{"unknown sort", []string{"--top", "5", "--sort", "size"}, true, nil},
+		{"negative top", []string{"--top", "-1"}, true, nil},
+	}
+	for _, tt := range tableTests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"aict", "ownership"}, tt.args...)
+			origStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := handleOwnership()
+
+			w.Close()
+			os.Stdout = origStdout
+			var buf bytes.Buffer
+			buf.ReadFrom(r)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handleOwnership() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.wantText {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestHandleOwnership_Since(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	// 導入前の既存コード
+	t.Setenv("GIT_COMMITTER_DATE", "2020-01-01T00:00:00Z")
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "legacy")
+	os.Unsetenv("GIT_COMMITTER_DATE")
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc a() {}\n")
+	commit := testutil.GitCommit(t, tmpDir, "Add a")
+	if err := gitnotes.NewNotesManager().AddAuthorshipLog(&tracker.AuthorshipLog{
+		Version: "1.0",
+		Commit:  commit,
+		Files: map[string]tracker.FileInfo{
+			"main.go": {Authors: []tracker.AuthorInfo{{Name: "Claude Code", Type: tracker.AuthorTypeAI, Lines: [][]int{{1, 2}}}}},
+		},
+	}); err != nil {
+		t.Fatalf("AddAuthorshipLog() error = %v", err)
+	}
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.AdoptionDate = "2024-01-01"
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) tracker.OwnershipMap {
+		t.Helper()
+		out := filepath.Join(t.TempDir(), "ownership.json")
+		os.Args = append([]string{"aict", "ownership", "--output", out}, args...)
+		if err := handleOwnership(); err != nil {
+			t.Fatalf("handleOwnership(%v) error = %v", args, err)
+		}
+		data, _ := os.ReadFile(out)
+		var m tracker.OwnershipMap
+		if err := json.Unmarshal(data, &m); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return m
+	}
+
+	// 既定は adoption_date 以降の行のみ
+	m := run()
+	if s := m.Summary; m.Since == nil || s.AILines != 2 || s.UnknownLines != 0 || s.ExcludedLines != 1 || s.AIPercentage != 100 {
+		t.Errorf("since adoption_date: since=%v summary=%+v, want 2 AI lines and 1 excluded", m.Since, s)
+	}
+
+	// --since all は導入前の行も含める
+	m = run("--since", "all")
+	if s := m.Summary; m.Since != nil || s.UnknownLines != 1 || s.ExcludedLines != 0 {
+		t.Errorf("--since all: since=%v summary=%+v, want the legacy line counted", m.Since, s)
+	}
+
+	os.Args = []string{"aict", "ownership", "--since", "last spring"}
+	if err := handleOwnership(); err == nil {
+		t.Error("handleOwnership() should reject an unparsable --since")
+	}
+}
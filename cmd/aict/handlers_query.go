@@ -0,0 +1,246 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/query"
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+const (
+	// querySourceRollup は日次集計（コミット済み）の作成者別レコード
+	querySourceRollup = "rollup"
+	// querySourceCheckpoint は未コミットのチェックポイントのレコード
+	querySourceCheckpoint = "checkpoint"
+)
+
+// queryRecord は aict query の1レコードです。
+type queryRecord struct {
+	Source  string `json:"source"`
+	Date    string `json:"date"`
+	Branch  string `json:"branch"`
+	Author  string `json:"author"`
+	Type    string `json:"type"`
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+	Commits int    `json:"commits"` // rollup のみ（その日にその作成者の行を含むコミット数）
+	Files   int    `json:"files"`   // checkpoint のみ
+}
+
+// queryFields はフィルタ式で使えるフィールドです（CSVの列順も兼ねる）。
+var queryFields = []struct {
+	name string
+	typ  query.FieldType
+}{
+	{"source", query.String},
+	{"date", query.Date},
+	{"branch", query.String},
+	{"author", query.String},
+	{"type", query.String},
+	{"added", query.Number},
+	{"deleted", query.Number},
+	{"commits", query.Number},
+	{"files", query.Number},
+}
+
+// handleQuery handles the query command
+func handleQuery() error {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	format := fs.String("format", "json", "Output format: json or csv")
+	fields := fs.String("fields", "", "Comma-separated fields (JSON keys / CSV columns) to output")
+	fs.Parse(os.Args[2:])
+
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("unsupported format %q (want json or csv)", *format)
+	}
+
+	schema := make(query.Schema, len(queryFields))
+	for _, f := range queryFields {
+		schema[f.name] = f.typ
+	}
+	filter, err := query.Parse(strings.Join(fs.Args(), " "), schema, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+
+	store, _, err := loadStorageAndConfig()
+	if err != nil {
+		return err
+	}
+	records, err := collectQueryRecords(store)
+	if err != nil {
+		return err
+	}
+
+	var matched []queryRecord
+	for _, r := range records {
+		if filter.Match(r.fields()) {
+			matched = append(matched, r)
+		}
+	}
+	return writeQueryResults(os.Stdout, matched, *format, *fields)
+}
+
+// collectQueryRecords は日次集計の作成者別の行と、未コミットのチェックポイントをレコードにします。
+// チェックポイントはブランチを記録していないため、現在のブランチを使います。
+func collectQueryRecords(store *storage.AIctStorage) ([]queryRecord, error) {
+	rollups, err := store.LoadDailyRollups()
+	if err != nil {
+		return nil, fmt.Errorf("loading daily rollups: %w", err)
+	}
+	checkpoints, err := store.LoadCheckpoints()
+	if err != nil {
+		return nil, fmt.Errorf("loading checkpoints: %w", err)
+	}
+
+	var records []queryRecord
+	for _, r := range rollups {
+		for name, a := range r.Authors {
+			records = append(records, queryRecord{
+				Source:  querySourceRollup,
+				Date:    r.Date,
+				Branch:  r.Branch,
+				Author:  name,
+				Type:    string(a.Type),
+				Added:   a.Lines,
+				Deleted: a.Deleted,
+				Commits: a.Commits,
+			})
+		}
+	}
+
+	if len(checkpoints) > 0 {
+		branch, _ := newExecutor().Run("rev-parse", "--abbrev-ref", "HEAD")
+		if branch == "HEAD" {
+			branch = "" // detached HEAD
+		}
+		for _, cp := range checkpoints {
+			records = append(records, checkpointQueryRecord(cp, branch))
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.Source != b.Source {
+			return a.Source > b.Source // 同じ日は rollup → checkpoint の順
+		}
+		if a.Branch != b.Branch {
+			return a.Branch < b.Branch
+		}
+		return a.Author < b.Author
+	})
+	return records, nil
+}
+
+func checkpointQueryRecord(cp *tracker.CheckpointV2, branch string) queryRecord {
+	r := queryRecord{
+		Source: querySourceCheckpoint,
+		Date:   cp.Timestamp.Local().Format("2006-01-02"),
+		Branch: branch,
+		Author: cp.Author,
+		Type:   string(cp.Type),
+		Files:  len(cp.Changes),
+	}
+	for _, change := range cp.Changes {
+		r.Added += change.Added
+		r.Deleted += change.Deleted
+	}
+	return r
+}
+
+// fields はフィルタ式で評価するための値を返します。
+func (r queryRecord) fields() query.Record {
+	date, _ := time.Parse("2006-01-02", r.Date)
+	return query.Record{
+		"source":  r.Source,
+		"date":    date,
+		"branch":  r.Branch,
+		"author":  r.Author,
+		"type":    r.Type,
+		"added":   r.Added,
+		"deleted": r.Deleted,
+		"commits": r.Commits,
+		"files":   r.Files,
+	}
+}
+
+// writeQueryResults はレコードをJSON配列またはヘッダー付きCSVで書き出します。
+// fields を指定した場合はそのフィールド（CSVでは列）のみを指定順に出力します。
+func writeQueryResults(w io.Writer, records []queryRecord, format, fields string) error {
+	if format == "json" {
+		if records == nil {
+			records = []queryRecord{}
+		}
+		data, err := marshalJSONFields(records, fields)
+		if err != nil {
+			return fmt.Errorf("formatting JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	columns, err := queryCSVColumns(fields)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.Write(columns)
+	for _, r := range records {
+		values := r.fields()
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			switch v := values[c].(type) {
+			case int:
+				row[i] = strconv.Itoa(v)
+			case string:
+				row[i] = v
+			default:
+				row[i] = r.Date // date は time.Time のため元の文字列を使う
+			}
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// queryCSVColumns は --fields からCSVの列を決めます（未指定時はすべての列）。
+func queryCSVColumns(fields string) ([]string, error) {
+	var columns []string
+	known := make(map[string]bool, len(queryFields))
+	for _, f := range queryFields {
+		columns = append(columns, f.name)
+		known[f.name] = true
+	}
+	if fields == "" {
+		return columns, nil
+	}
+
+	var selected []string
+	for _, name := range strings.Split(fields, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			return nil, fmt.Errorf("unknown field %q (available: %s)", name, strings.Join(columns, ", "))
+		}
+		selected = append(selected, name)
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("--fields is empty")
+	}
+	return selected, nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestCollectQueryRecords(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		t.Fatalf("NewAIctStorage() error = %v", err)
+	}
+	store.MergeDailyRollup(&tracker.DailyRollup{
+		Date: "2026-01-10", Branch: "feature/login", Commits: []string{"c1", "c2"},
+		Authors: map[string]*tracker.RollupAuthor{
+			"Claude Code": {Type: tracker.AuthorTypeAI, Lines: 150, Deleted: 4, Commits: 2},
+			"Alice":       {Type: tracker.AuthorTypeHuman, Lines: 20, Commits: 1},
+		},
+	})
+	store.MergeDailyRollup(&tracker.DailyRollup{
+		Date: "2026-01-11", Branch: "main", Commits: []string{"c3"},
+		Authors: map[string]*tracker.RollupAuthor{"Claude Code": {Type: tracker.AuthorTypeAI, Lines: 300, Commits: 1}},
+	})
+	store.SaveCheckpoint(&tracker.CheckpointV2{
+		Timestamp: time.Date(2026, 1, 12, 9, 0, 0, 0, time.Local),
+		Author:    "Claude Code",
+		Type:      tracker.AuthorTypeAI,
+		Changes:   map[string]tracker.Change{"a.go": {Added: 7, Deleted: 1}, "b.go": {Added: 3}},
+	})
+
+	records, err := collectQueryRecords(store)
+	if err != nil {
+		t.Fatalf("collectQueryRecords() error = %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("len(records) = %d, want 4: %+v", len(records), records)
+	}
+	cp := records[3]
+	if cp.Source != querySourceCheckpoint || cp.Date != "2026-01-12" || cp.Added != 10 || cp.Deleted != 1 || cp.Files != 2 {
+		t.Errorf("checkpoint record = %+v", cp)
+	}
+
+	tests := []struct {
+		expr string
+		want int
+	}{
+		{"", 4},
+		{`author~"claude*" and branch~"feature/*" and added>100`, 1},
+		{"type=human", 1},
+		{"source=checkpoint or branch=main", 2},
+		{"since 2026-01-11 and author=\"claude code\"", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			os.Args = []string{"aict", "query", "--format", "json", tt.expr}
+			out := captureQueryOutput(t)
+
+			var got []queryRecord
+			if err := json.Unmarshal(out, &got); err != nil {
+				t.Fatalf("output is not JSON: %v\n%s", err, out)
+			}
+			if len(got) != tt.want {
+				t.Errorf("matched %d records, want %d: %+v", len(got), tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWriteQueryResults_CSV(t *testing.T) {
+	records := []queryRecord{
+		{Source: querySourceRollup, Date: "2026-01-10", Branch: "feature/a,b", Author: "Claude Code", Type: "ai", Added: 5, Commits: 1},
+	}
+	var buf bytes.Buffer
+	if err := writeQueryResults(&buf, records, "csv", ""); err != nil {
+		t.Fatalf("writeQueryResults() error = %v", err)
+	}
+	want := "source,date,branch,author,type,added,deleted,commits,files\n" +
+		"rollup,2026-01-10,\"feature/a,b\",Claude Code,ai,5,0,1,0\n"
+	if buf.String() != want {
+		t.Errorf("CSV = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := writeQueryResults(&buf, records, "csv", "author, date,added"); err != nil {
+		t.Fatalf("writeQueryResults() error = %v", err)
+	}
+	if want := "author,date,added\nClaude Code,2026-01-10,5\n"; buf.String() != want {
+		t.Errorf("CSV with --fields = %q, want %q", buf.String(), want)
+	}
+	if err := writeQueryResults(&buf, records, "csv", "lines"); err == nil || !strings.Contains(err.Error(), `unknown field "lines"`) {
+		t.Errorf("writeQueryResults() error = %v, want unknown field", err)
+	}
+
+	buf.Reset()
+	if err := writeQueryResults(&buf, nil, "json", ""); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("empty JSON = %q, err = %v", buf.String(), err)
+	}
+}
+
+func TestHandleQuery_InvalidExpression(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"aict", "query", "lines>10"}
+	if err := handleQuery(); err == nil || !strings.Contains(err.Error(), `unknown field "lines"`) {
+		t.Errorf("handleQuery() error = %v, want unknown field error", err)
+	}
+}
+
+// captureQueryOutput は handleQuery を実行し、標準出力を返します。
+func captureQueryOutput(t *testing.T) []byte {
+	t.Helper()
+	origStdout := os.Stdout
+	defer func() { os.Stdout = origStdout }()
+
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := handleQuery()
+	w.Close()
+	os.Stdout = origStdout
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	if err != nil {
+		t.Fatalf("handleQuery() error = %v", err)
+	}
+	return buf.Bytes()
+}
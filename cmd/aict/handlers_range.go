@@ -2,22 +2,39 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"math"
 	"os"
 	"strings"
 
 	"github.com/y-hirakaw/ai-code-tracker/internal/authorship"
 	"github.com/y-hirakaw/ai-code-tracker/internal/git"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
 	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
 	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
 )
 
 // ReportOptions holds options for the report command
 type ReportOptions struct {
-	Range  string
-	Since  string
-	Format string
+	Range      string
+	Since      string
+	Format     string
+	Sample     string // e.g. "10%": コミットを決定的にサンプリングして合計を外挿
+	Branch     string // 集計対象ブランチ（--since と組み合わせ可、未指定時はHEAD）
+	ByAuthor   bool   // AuthorMappingsで集約した作成者別の内訳を表示
+	ByFile     bool   // ファイル別の内訳を表示
+	ByDir      bool   // ディレクトリ別の内訳を表示
+	ByLanguage bool   // 言語別の内訳を表示
+	Depth      int    // --by-dir で集約するディレクトリ階層の深さ
+	Sort       string // --by-file/--by-dir/--by-language の並び順: lines（追加行数）または ai（AI%）
+	Context    string // 設定の contexts で定義したコンテキスト名（対象パス配下のファイルのみ集計）
+	Fields     string // --format json で出力するフィールド（カンマ区切りのドット区切りパス、空=すべて）
+	Trend      string // AI%の推移を集計する期間: day, week, month（空=推移なし）
+	Smooth     string // --trend の移動平均の期間（7d, 4w, 3m。空=平滑化なし）
+	Sizes      bool   // AIの変更（コミット・ファイルごとの追加行数）の大きさの分布と外れ値を表示
 }
 
 // handleRangeReport is the entry point called from main
@@ -26,21 +43,120 @@ func handleRangeReport() error {
 
 	opts := &ReportOptions{}
 	fs.StringVar(&opts.Range, "range", "", "Commit range (e.g., 'origin/main..HEAD')")
+	commits := fs.String("commits", "", "Alias of --range for release ranges (e.g., 'v1.3.0..v1.4.0')")
 	fs.StringVar(&opts.Since, "since", "", "Show commits since date (e.g., '7 days ago', '2025-01-01')")
+	last := fs.String("last", "", "Alias of --since for a period ending now (e.g., '30d')")
+	compareTo := fs.String("compare-to", "", "Compare with 'previous' (the same-length period before --since) or a commit range")
 	fs.StringVar(&opts.Format, "format", "table", "Output format: table or json")
+	fs.StringVar(&opts.Sample, "sample", "", "Deterministically sample commits and extrapolate totals (e.g., '10%')")
+	fs.StringVar(&opts.Branch, "branch", "", "Branch to report on (default: HEAD)")
+	fs.BoolVar(&opts.ByAuthor, "by-author", false, "Show per-author breakdown grouped by author_mappings")
+	fs.BoolVar(&opts.ByFile, "by-file", false, "Show per-file breakdown")
+	fs.BoolVar(&opts.ByDir, "by-dir", false, "Show per-directory breakdown")
+	fs.BoolVar(&opts.ByLanguage, "by-language", false, "Show per-language breakdown")
+	fs.IntVar(&opts.Depth, "depth", 1, "Directory depth for --by-dir rollups")
+	fs.StringVar(&opts.Context, "context", "", "Report only on files in the named context (config \"contexts\")")
+	fs.StringVar(&opts.Sort, "sort", "lines", "Sort order for --by-file/--by-dir/--by-language: lines or ai")
+	fs.StringVar(&opts.Fields, "fields", "", fieldsFlagUsage)
+	fs.StringVar(&opts.Trend, "trend", "", "Show AI% movement per period: day, week or month")
+	fs.StringVar(&opts.Smooth, "smooth", "", "Rolling average window for --trend (e.g., '7d', '4w')")
+	fs.BoolVar(&opts.Sizes, "sizes", false, "Show the distribution of AI change sizes (p50/p90/max) and outliers")
 
 	fs.Parse(os.Args[2:])
 
+	// --commits は --range の別名（リリース間の集計。チェックポイントの日時に依存しない）
+	if *commits != "" {
+		if opts.Range != "" && opts.Range != *commits {
+			return fmt.Errorf("--commits is an alias of --range; specify only one of them")
+		}
+		opts.Range = *commits
+	}
+	// --last は --since の別名（--compare-to previous と組み合わせる期間の指定）
+	if *last != "" {
+		if opts.Since != "" && opts.Since != *last {
+			return fmt.Errorf("--last is an alias of --since; specify only one of them")
+		}
+		opts.Since = *last
+	}
+
+	if opts.Fields != "" && opts.Format != "json" {
+		return fmt.Errorf("--fields requires --format json")
+	}
+
+	if opts.Sample != "" {
+		if _, err := parseSampleRate(opts.Sample); err != nil {
+			return err
+		}
+	}
+
+	if opts.Depth < 1 {
+		return fmt.Errorf("--depth must be at least 1, got %d", opts.Depth)
+	}
+	if opts.Sort != fileSortLines && opts.Sort != fileSortAI {
+		return fmt.Errorf("unknown sort order: %s (available: %s, %s)", opts.Sort, fileSortLines, fileSortAI)
+	}
+	if opts.Trend != "" {
+		switch opts.Trend {
+		case churnPeriodDay, churnPeriodWeek, churnPeriodMonth:
+		default:
+			return fmt.Errorf("unknown trend period: %s (available: day, week, month)", opts.Trend)
+		}
+		if opts.Sample != "" || opts.Context != "" {
+			return fmt.Errorf("--trend cannot be combined with --sample or --context")
+		}
+	}
+	if opts.Smooth != "" {
+		if opts.Trend == "" {
+			return fmt.Errorf("--smooth requires --trend")
+		}
+		if _, err := smoothWindowPeriods(opts.Smooth, opts.Trend); err != nil {
+			return err
+		}
+	}
+
 	// --range と --since の排他チェック
 	if opts.Range != "" && opts.Since != "" {
 		return fmt.Errorf("--range and --since are mutually exclusive. Please use either --range or --since, not both")
 	}
 
-	// どちらも指定されていない場合
+	if *compareTo != "" {
+		if opts.Trend != "" || opts.Sizes || opts.ByAuthor || opts.ByFile || opts.ByDir || opts.ByLanguage {
+			return fmt.Errorf("--compare-to compares summary totals and cannot be combined with --trend, --sizes or --by-* breakdowns")
+		}
+		if *compareTo == compareToPrevious {
+			if opts.Since == "" {
+				return fmt.Errorf("--compare-to previous requires --since (or --last) to define the period")
+			}
+		} else if err := gitexec.ValidateRevisionArg(*compareTo); err != nil {
+			return err
+		}
+	}
+
+	if opts.Branch != "" {
+		if opts.Range != "" {
+			return fmt.Errorf("--branch cannot be combined with --range. Use --branch with --since, or specify the branch in --range")
+		}
+		if err := gitexec.ValidateRevisionArg(opts.Branch); err != nil {
+			return err
+		}
+		if opts.Since == "" {
+			opts.Range = opts.Branch
+		}
+	}
+
+	// どちらも指定されていない場合は最新ベースライン以降を集計
+	if opts.Range == "" && opts.Since == "" {
+		if r, b := baselineReportRange("HEAD"); r != "" {
+			fmt.Fprintf(os.Stderr, "Reporting since baseline %s (%s)\n", shortCommit(b.Commit), b.CreatedAt.Local().Format("2006-01-02"))
+			opts.Range = r
+		}
+	}
+
 	if opts.Range == "" && opts.Since == "" {
 		fmt.Println("Usage:")
 		fmt.Println("  aict report --range <base>..<head>")
 		fmt.Println("  aict report --since <date>")
+		fmt.Println("  aict report                   # since the latest 'aict baseline create'")
 		fmt.Println()
 		fmt.Println("Examples:")
 		fmt.Println("  aict report --range origin/main..HEAD")
@@ -50,7 +166,8 @@ func handleRangeReport() error {
 		fmt.Println("  aict report --since '7 days ago'")
 		fmt.Println("  aict report --since '2025-01-01'")
 		fmt.Println("  aict report --since yesterday")
-		return fmt.Errorf("either --range or --since is required")
+		fmt.Println("  aict report --since 1y --sample 10%")
+		return fmt.Errorf("either --range or --since is required (or create a baseline with 'aict baseline create')")
 	}
 
 	// --since を --range に変換
@@ -58,13 +175,20 @@ func handleRangeReport() error {
 		if warning := validateSinceInput(opts.Since); warning != "" {
 			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
 		}
-		convertedRange, err := convertSinceToRange(opts.Since)
+		head := "HEAD"
+		if opts.Branch != "" {
+			head = opts.Branch
+		}
+		convertedRange, err := convertSinceToRangeOn(opts.Since, head)
 		if err != nil {
 			return err
 		}
 		opts.Range = convertedRange
 	}
 
+	if *compareTo != "" {
+		return handleCompareReport(opts, *compareTo)
+	}
 	return handleRangeReportWithOptions(opts)
 }
 
@@ -74,16 +198,26 @@ type authorStatsResult struct {
 	totalAI         int
 	totalHuman      int
 	detailedMetrics tracker.DetailedMetrics
+	sampledCommits  int                           // サンプリング時に実際に集計したコミット数（0=全件集計）
+	byFile          map[string]*tracker.FileStats // ファイル別の追加行数（日次集計からの構築時はnil）
+	includePath     func(string) bool             // 集計対象ファイルの判定（nil=全ファイル）
+	aiChanges       []tracker.AIChange            // コミット・ファイルごとのAIの追加行数（日次集計からの構築時はnil）
+	overrides       *overrideEngine               // 設定の overrides（nil=上書きなし）
+
+	// override は集計中のコミットの作成者エントリに適用する種別と重みを返します（nil=上書きなし）
+	override func(*tracker.AuthorInfo) (tracker.AuthorType, float64)
 }
 
 // handleRangeReportWithOptions handles report for commit range (SPEC.md準拠)
 func handleRangeReportWithOptions(opts *ReportOptions) error {
-	result, commitCount, err := collectAuthorStats(opts.Range)
+	ensureRangeHistory(opts.Range)
+
+	report, metrics, err := computeRangeReport(opts)
 	if err != nil {
-		return fmt.Errorf("getting commits: %w", err)
+		return err
 	}
 
-	if commitCount == 0 {
+	if report == nil {
 		rangeDisplay := opts.Range
 		if opts.Since != "" {
 			rangeDisplay = "since " + opts.Since
@@ -92,17 +226,224 @@ func handleRangeReportWithOptions(opts *ReportOptions) error {
 		return nil
 	}
 
+	// 推移はdaemonのキャッシュに含めず、レポートとは別に集計
+	if opts.Trend != "" {
+		report.Trend, err = collectTrend(opts.Range, opts.Trend, opts.Since != "" && !trendNeedsPerCommit())
+		if err != nil {
+			return fmt.Errorf("collecting trend: %w", err)
+		}
+		if opts.Smooth != "" {
+			n, _ := smoothWindowPeriods(opts.Smooth, opts.Trend) // 引数の検証で確認済み
+			smoothTrend(report.Trend, opts.Smooth, n)
+		}
+	}
+
+	if opts.Fields != "" {
+		data, err := marshalJSONFields(report, opts.Fields)
+		if err != nil {
+			return fmt.Errorf("formatting JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	return formatRangeReport(report, opts.Format, metrics)
+}
+
+// computeRangeReport は起動中のdaemonがあれば集計を委譲し、なければローカルで集計します。
+func computeRangeReport(opts *ReportOptions) (*tracker.Report, *tracker.DetailedMetrics, error) {
+	report, metrics, err := queryDaemonReport(opts)
+	if err != nil {
+		debugf("daemon unavailable, computing locally: %v", err)
+		return generateRangeReport(opts)
+	}
+	return report, metrics, nil
+}
+
+// generateRangeReport はコミット範囲の統計を集計してReportを構築します。
+// 範囲内にコミットがない場合は nil Report を返します。
+func generateRangeReport(opts *ReportOptions) (*tracker.Report, *tracker.DetailedMetrics, error) {
+	sampleRate := 1.0
+	if opts.Sample != "" {
+		rate, err := parseSampleRate(opts.Sample)
+		if err != nil {
+			return nil, nil, err
+		}
+		sampleRate = rate
+	}
+
+	var cfg *tracker.Config
+	if _, loaded, err := loadStorageAndConfig(); err == nil {
+		cfg = loaded
+	}
+
+	var (
+		includePath func(string) bool
+		ctxTarget   float64
+	)
+	if opts.Context != "" {
+		ctx, err := lookupContext(cfg, opts.Context)
+		if err != nil {
+			return nil, nil, err
+		}
+		includePath = contextPathMatcher(ctx.Paths)
+		ctxTarget = contextTarget(ctx, cfg)
+	}
+	byContext := opts.Context == "" && cfg != nil && len(cfg.Contexts) > 0
+	splitDocs := cfg != nil && cfg.Docs != nil
+	withArtifacts := cfg != nil && cfg.Artifacts != nil
+	contextPath := includePath
+	includePath = excludeArtifacts(includePath, cfg) // artifacts は行数ではなくファイル数で別集計
+	includePath = excludeIgnored(includePath, cfg)   // 記録後に追加した exclude_patterns・.aictignore も反映
+
+	// 範囲の両端をフルハッシュに固定し、同じ範囲の git log を集計ごとに実行しないようキャッシュさせる（表示は元の範囲のまま）
+	pinned := *opts
+	pinned.Range = git.PinRange(newExecutor(), opts.Range)
+
+	// 期間レポートは日次集計で範囲を網羅できればコミット単位の集計を省略
+	// （日次集計はファイル単位の情報を持たないため、ファイル別・言語別・コンテキスト別・コード/ドキュメント別・artifacts ありの集計では使用しない）
+	var (
+		result      *authorStatsResult
+		commitCount int
+		fromRollups bool
+		err         error
+	)
+	perFile := opts.ByFile || opts.ByDir || opts.ByLanguage || opts.Sizes || opts.Context != "" || byContext || splitDocs || withArtifacts
+	// 日次集計は記録時の分類のため、overrides がある場合はコミット単位で集計する
+	withOverrides := cfg != nil && len(cfg.Overrides) > 0
+	if opts.Since != "" && sampleRate == 1 && !perFile && !withOverrides {
+		result, commitCount, fromRollups = collectRollupStats(pinned.Range)
+	}
+	if !fromRollups {
+		result, commitCount, err = collectAuthorStats(pinned.Range, sampleRate, includePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting commits: %w", err)
+		}
+	}
+
+	if commitCount == 0 {
+		return nil, nil, nil
+	}
+
+	if result.sampledCommits > 0 {
+		extrapolateSample(result, commitCount)
+	}
+
 	report := buildReport(opts, commitCount, result)
+	report.Branch = opts.Branch
+	report.Overrides = result.overrides.matchedNames()
+	if opts.ByAuthor {
+		var mappings map[string]string
+		if cfg != nil {
+			mappings = cfg.AuthorMappings
+		}
+		report.AuthorBreakdown = buildAuthorBreakdown(report.ByAuthor, mappings)
+	}
+	if cfg != nil && cfg.RepoLanguages != nil {
+		report.RepoLanguages = cfg.RepoLanguages.Languages
+	}
+	if opts.Context != "" {
+		report.Context = opts.Context
+		report.TargetAIPercentage = ctxTarget
+	}
+	if byContext {
+		report.ByContext = buildContextStats(result.byFile, cfg)
+	}
+	if splitDocs {
+		codeTarget := cfg.TargetAIPercentage
+		if opts.Context != "" {
+			codeTarget = ctxTarget
+		}
+		report.Code, report.Docs, err = buildCategoryStats(&pinned, sampleRate, includePath, cfg, codeTarget)
+		if err != nil {
+			return nil, nil, fmt.Errorf("splitting code and docs: %w", err)
+		}
+	}
+	if withArtifacts {
+		report.Artifacts, err = collectArtifactStats(pinned.Range, sampleRate, contextPath, cfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("counting artifacts: %w", err)
+		}
+	}
+	if opts.ByFile {
+		report.ByFile = buildFileStats(result.byFile, opts.Sort)
+	}
+	if opts.ByDir {
+		report.ByDir = buildDirStats(result.byFile, opts.Depth, opts.Sort)
+	}
+	if opts.ByLanguage {
+		report.ByLanguage = buildLanguageStats(result.byFile, opts.Sort)
+	}
+	if opts.Sizes {
+		report.AIChangeSizes = buildAIChangeSizes(result.aiChanges)
+	}
+	if result.sampledCommits > 0 {
+		report.Sample = &tracker.SampleInfo{
+			Rate:           sampleRate,
+			SampledCommits: result.sampledCommits,
+			TotalCommits:   commitCount,
+			MarginOfError:  sampleMarginOfError(report.Summary.AIPercentage, result.sampledCommits, commitCount),
+		}
+	}
+	return report, &result.detailedMetrics, nil
+}
+
+const (
+	// shallowDeepenStep はshallow clone時に1回で追加取得するコミット数
+	shallowDeepenStep = 100
+	// shallowDeepenAttempts はmerge-baseが見つかるまで履歴を追加取得する最大回数
+	shallowDeepenAttempts = 3
+)
+
+// ensureRangeHistory はCI等のshallow clone環境でレポートに必要な情報を補完します。
+//   - Authorship Logのnotes refが未取得ならoriginからfetch
+//   - "base..head" のmerge-baseが見つかるまで履歴をdeepen
+//
+// 補完に失敗してもレポートは続行し、警告のみ表示します。
+func ensureRangeHistory(rangeSpec string) {
+	executor := newExecutor()
+	shallow := git.IsShallowRepository(executor)
+
+	if (shallow || os.Getenv("CI") != "") && !git.RefExists(executor, gitnotes.AuthorshipNotesFullRef) {
+		debugf("Authorship notes ref not found locally, fetching from origin")
+		if err := gitnotes.NewNotesManagerWithExecutor(executor).Fetch(defaultNotesRemote); err != nil && !errors.Is(err, gitnotes.ErrNoRemoteNotes) {
+			fmt.Fprintf(os.Stderr, "Warning: failed to fetch authorship logs from origin: %v\n", err)
+		}
+	}
+
+	if !shallow {
+		return
+	}
 
-	return formatRangeReport(report, opts.Format, &result.detailedMetrics)
+	base, head, ok := git.SplitRange(rangeSpec)
+	if !ok {
+		return
+	}
+
+	for i := 0; i < shallowDeepenAttempts; i++ {
+		if _, err := git.MergeBase(executor, base, head); err == nil {
+			return
+		}
+		debugf("Merge base of %s and %s not found in shallow clone, deepening by %d", base, head, shallowDeepenStep)
+		if err := git.Deepen(executor, "origin", shallowDeepenStep); err != nil {
+			debugf("deepen failed: %v", err)
+			break
+		}
+	}
+
+	if _, err := git.MergeBase(executor, base, head); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: shallow clone: merge base of %s and %s not found; "+
+			"the report may include commits outside the range. Run 'git fetch --unshallow' for accurate results\n", base, head)
+	}
 }
 
 // collectAuthorStats はコミット範囲内の作成者統計をバッチ取得で集計します。
 // 従来の2N回のgitプロセス起動（N×GetAuthorshipLog + N×git show --numstat）を
 // 2回のバッチ呼び出し（GetRangeNumstat + GetAuthorshipLogsForRange）に削減します。
-func collectAuthorStats(rangeSpec string) (*authorStatsResult, int, error) {
+// sampleRate が1未満の場合はサンプリングしたコミットのみ集計します（外挿は呼び出し元）。
+// includePath を指定した場合は、該当するファイルのみ集計します（コミット数は範囲全体）。
+func collectAuthorStats(rangeSpec string, sampleRate float64, includePath func(string) bool) (*authorStatsResult, int, error) {
 	executor := newExecutor()
-	nm := gitnotes.NewNotesManager()
+	nm := gitnotes.NewNotesManagerWithExecutor(newExecutor())
 
 	// バッチ取得: 全コミットのnumstatを1回のgit呼び出しで取得
 	allNumstats, commits, err := git.GetRangeNumstat(executor, rangeSpec)
@@ -117,14 +458,29 @@ func collectAuthorStats(rangeSpec string) (*authorStatsResult, int, error) {
 	// バッチ取得: 全コミットのAuthorship Logを1回のgit呼び出しで取得
 	allLogs, _ := nm.GetAuthorshipLogsForRange(rangeSpec)
 
+	overrides, err := loadOverrideEngine()
+	if err != nil {
+		return nil, 0, err
+	}
+
 	result := &authorStatsResult{
-		byAuthor: make(map[string]*tracker.AuthorStats),
+		byAuthor:    make(map[string]*tracker.AuthorStats),
+		byFile:      make(map[string]*tracker.FileStats),
+		includePath: includePath,
+		overrides:   overrides,
+	}
+
+	targetCommits := commits
+	if sampleRate > 0 && sampleRate < 1 {
+		targetCommits = sampleCommits(commits, sampleRate)
+		result.sampledCommits = len(targetCommits)
+		debugf("Sampling %d of %d commits (rate=%.3f)", len(targetCommits), len(commits), sampleRate)
 	}
 
 	// 作成者ごとのコミット参加記録（重複カウント防止）
 	authorCommits := make(map[string]map[string]bool)
 
-	for _, commitHash := range commits {
+	for _, commitHash := range targetCommits {
 		alog := allLogs[commitHash]
 		if alog == nil {
 			continue
@@ -134,6 +490,10 @@ func collectAuthorStats(rangeSpec string) (*authorStatsResult, int, error) {
 		if numstatMap == nil {
 			continue
 		}
+		applyLineCounters(numstatMap, commitHash, func(path string) bool { _, ok := alog.Files[path]; return ok })
+		if overrides != nil {
+			result.override = overrides.forCommit(commitHash, alog.Timestamp)
+		}
 
 		authorsInCommit := processCommitFiles(result, alog, numstatMap)
 
@@ -161,12 +521,23 @@ func processCommitFiles(result *authorStatsResult, alog *tracker.AuthorshipLog,
 	authorsInCommit := make(map[string]bool)
 
 	for filePath, fileInfo := range alog.Files {
+		if result.includePath != nil && !result.includePath(filePath) {
+			continue
+		}
 		numstat, found := numstatMap[filePath]
 		if !found {
 			continue
 		}
 
+		aiBefore, humanBefore := result.totalAI, result.totalHuman
+		deletedBefore := result.detailedMetrics.WorkVolume
 		processFileAuthors(result, fileInfo, numstat, authorsInCommit)
+		addFileStats(result, filePath, result.totalAI-aiBefore, result.totalHuman-humanBefore)
+		if ai := result.totalAI - aiBefore; ai > 0 {
+			result.aiChanges = append(result.aiChanges, tracker.AIChange{Commit: alog.Commit, Path: filePath, Lines: ai})
+		}
+		wv := result.detailedMetrics.WorkVolume
+		accumulateRewrites(result, fileInfo.Rewritten, wv.AIDeleted-deletedBefore.AIDeleted, wv.HumanDeleted-deletedBefore.HumanDeleted)
 	}
 
 	return authorsInCommit
@@ -174,49 +545,81 @@ func processCommitFiles(result *authorStatsResult, alog *tracker.AuthorshipLog,
 
 // processFileAuthors は1つのファイルの作成者ごとの行数を按分して集計します。
 func processFileAuthors(result *authorStatsResult, fileInfo tracker.FileInfo, numstat [2]int, authorsInCommit map[string]bool) {
-	totalAdded := numstat[0]
-	totalDeleted := numstat[1]
-
 	// Authorship Logから各作成者の行数を計算
-	authorLineCount := make(map[string]int)
-	totalAuthorLines := 0
-	for _, author := range fileInfo.Authors {
-		lines := authorship.CountLines(author.Lines)
-		authorLineCount[author.Name] = lines
-		totalAuthorLines += lines
-	}
+	authorLines := make([]int, len(fileInfo.Authors))
+	for i, author := range fileInfo.Authors {
+		authorLines[i] = authorship.CountLines(author.Lines)
+	}
+
+	added, deleted := splitFileContribution(authorLines, numstat[0], numstat[1])
+
+	for i, author := range fileInfo.Authors {
+		// 設定の overrides に一致したエントリは種別・行数の重みを上書き
+		authorType, a, d := author.Type, added[i], deleted[i]
+		share := fileInfo.Authors[i].AIShare()
+		if result.override != nil {
+			var weight float64
+			authorType, weight = result.override(&fileInfo.Authors[i])
+			a, d = applyWeight(a, weight), applyWeight(d, weight)
+			// 種別を上書きしたエントリは ai_weight を使わず、全行を上書き後の種別に数える
+			if authorType != author.Type {
+				share = (&tracker.AuthorInfo{Type: authorType}).AIShare()
+			}
+		}
 
-	for _, author := range fileInfo.Authors {
 		stats, exists := result.byAuthor[author.Name]
 		if !exists {
 			stats = &tracker.AuthorStats{
 				Name: author.Name,
-				Type: author.Type,
+				Type: authorType,
 			}
 			result.byAuthor[author.Name] = stats
 		}
 
-		added, deleted := calculateAuthorContribution(
-			authorLineCount[author.Name], totalAuthorLines,
-			totalAdded, totalDeleted, len(fileInfo.Authors),
-		)
+		// ai_weight のあるエントリ（ペアプログラミング等）は行数をAIと人間に按分する
+		aiAdded, humanAdded := splitByAIShare(a, share)
+		aiDeleted, humanDeleted := splitByAIShare(d, share)
 
-		stats.Lines += added
+		stats.Lines += a
+		stats.Deleted += d
+		stats.AILines += aiAdded
 		authorsInCommit[author.Name] = true
-		accumulateMetrics(result, author.Type, added, deleted)
+		accumulateMetrics(result, tracker.AuthorTypeAI, aiAdded, aiDeleted)
+		accumulateMetrics(result, tracker.AuthorTypeHuman, humanAdded, humanDeleted)
+	}
+}
+
+// splitByAIShare は行数をAIへの帰属割合で按分します（AI側を四捨五入し、残りを人間とする）。
+func splitByAIShare(lines int, share float64) (ai, human int) {
+	switch {
+	case share <= 0:
+		return 0, lines
+	case share >= 1:
+		return lines, 0
 	}
+	ai = int(math.Round(float64(lines) * share))
+	return ai, lines - ai
 }
 
-// calculateAuthorContribution は作成者の按分比率に基づいて追加・削除行数を計算します。
-func calculateAuthorContribution(authorLines, totalAuthorLines, totalAdded, totalDeleted, authorCount int) (added, deleted int) {
+// splitFileContribution はファイルの追加・削除行数を作成者の行数比で按分します。
+// 按分の合計はnumstatと一致します（端数は最大剰余法で配分）。
+// 作成者の行数がすべて0の場合、単独作成者なら削除行のみを割り当て、複数なら0とします。
+func splitFileContribution(authorLines []int, totalAdded, totalDeleted int) (added, deleted []int) {
+	totalAuthorLines := 0
+	for _, n := range authorLines {
+		totalAuthorLines += n
+	}
+
 	if totalAuthorLines > 0 {
-		ratio := float64(authorLines) / float64(totalAuthorLines)
-		return int(float64(totalAdded) * ratio), int(float64(totalDeleted) * ratio)
+		return metrics.SplitByRatio(totalAdded, authorLines), metrics.SplitByRatio(totalDeleted, authorLines)
 	}
-	if authorCount == 1 {
-		return 0, totalDeleted
+
+	added = make([]int, len(authorLines))
+	deleted = make([]int, len(authorLines))
+	if len(authorLines) == 1 {
+		deleted[0] = totalDeleted
 	}
-	return 0, 0
+	return added, deleted
 }
 
 // accumulateMetrics は作成者タイプに基づいてメトリクスを累積します。
@@ -254,51 +657,62 @@ func buildReport(opts *ReportOptions, commitCount int, result *authorStatsResult
 		},
 	}
 
-	if report.Summary.TotalLines > 0 {
-		report.Summary.AIPercentage = float64(result.totalAI) / float64(result.totalAI+result.totalHuman) * 100
-	}
+	report.Summary.AIPercentage = metrics.SafePercent(result.totalAI, report.Summary.TotalLines)
 
 	for _, stats := range result.byAuthor {
-		if report.Summary.TotalLines > 0 {
-			stats.Percentage = float64(stats.Lines) / float64(report.Summary.TotalLines) * 100
-		}
+		stats.Percentage = metrics.SafePercent(stats.Lines, report.Summary.TotalLines)
 		report.ByAuthor = append(report.ByAuthor, *stats)
 	}
 
 	return report
 }
 
+// errNoCommitsSince は --since の期間にコミットがない場合のエラーです。
+var errNoCommitsSince = errors.New("no commits found")
+
 // convertSinceToRange converts --since date to --range format
 func convertSinceToRange(since string) (string, error) {
+	return convertSinceToRangeOn(since, "HEAD")
+}
+
+// convertSinceToRangeOn converts --since date to a range ending at head
+func convertSinceToRangeOn(since, head string) (string, error) {
 	// 簡潔な表記を展開（3d → 3 days ago, 2w → 2 weeks ago, 1m → 1 month ago）
 	expandedSince := expandShorthandDate(since)
 
 	// git log --since でコミットハッシュリストを取得（古い順）
 	executor := newExecutor()
-	output, err := executor.Run("log", "--since="+expandedSince, "--format=%H", "--reverse")
+	args := []string{"log", "--since=" + expandedSince, "--format=%H", "--reverse"}
+	if head != "HEAD" {
+		args = append(args, "--end-of-options", head)
+	}
+	output, err := executor.Run(args...)
 	if err != nil {
 		return "", fmt.Errorf("failed to get commits since %s: %w", since, err)
 	}
 
 	commits := strings.Split(output, "\n")
 	if len(commits) == 0 || commits[0] == "" {
-		return "", fmt.Errorf("no commits found since %s", since)
+		return "", fmt.Errorf("%w since %s", errNoCommitsSince, since)
 	}
 
 	// 最初のコミットの1つ前からHEADまでの範囲を作成
-	firstCommit := commits[0]
+	return rangeFromCommit(executor, commits[0], head), nil
+}
 
+// rangeFromCommit は firstCommit から head までのコミット範囲を返します。
+func rangeFromCommit(executor gitexec.Executor, firstCommit, head string) string {
 	// 最初のコミットの親が存在するか確認
-	_, err = executor.Run("rev-parse", firstCommit+"^")
+	_, err := executor.Run("rev-parse", firstCommit+"^")
 	if err != nil {
 		// 親がない（初回コミット、またはリポジトリ初期化直後）場合
 		// 最初のコミット自体から開始: firstCommit..HEAD
 		// ただし、firstCommitのみが対象の場合もあるので、firstCommit^..HEAD を使う
 		// git では ^ が無効な場合でも --not を使える
-		return firstCommit + "..HEAD", nil
+		return firstCommit + ".." + head
 	}
 
-	return firstCommit + "^..HEAD", nil
+	return firstCommit + "^.." + head
 }
 
 // expandShorthandDate expands shorthand date notation to git-compatible format
@@ -406,17 +820,73 @@ func formatRangeReport(report *tracker.Report, format string, metrics *tracker.D
 		// Table format
 		fmt.Printf("AI Code Generation Report (%s)\n", report.Range)
 		fmt.Println()
+		if report.Branch != "" {
+			fmt.Printf("Branch: %s\n", report.Branch)
+		}
+		if report.Context != "" {
+			fmt.Printf("Context: %s (target %.1f%%)\n", report.Context, report.TargetAIPercentage)
+		}
 		fmt.Printf("Commits: %d\n", report.Commits)
+		if len(report.RepoLanguages) > 0 {
+			fmt.Printf("Repository: mostly %s (%s)\n", report.RepoLanguages[0].Language,
+				repoLanguageSummary(report.RepoLanguages, repoLanguageSummaryLimit))
+		}
+		if len(report.Overrides) > 0 {
+			fmt.Printf("Overrides: %s (applied at analysis time; Authorship Logs are unchanged)\n", strings.Join(report.Overrides, ", "))
+		}
+		if report.Sample != nil {
+			fmt.Printf("Sampled: %d of %d commits (%.0f%%); totals are extrapolated, AI%% ±%.1fpt (95%% CI)\n",
+				report.Sample.SampledCommits, report.Sample.TotalCommits, report.Sample.Rate*100, report.Sample.MarginOfError)
+		}
 		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 		fmt.Println()
 
-		// 詳細メトリクスを常時表示
-		if metrics != nil {
+		// 詳細メトリクスを常時表示（設定の docs がある場合はコードとドキュメントに分けて表示）
+		if report.Code != nil && report.Docs != nil {
+			printCategoryStats("Code", report.Code)
+			printCategoryStats("Docs", report.Docs)
+		} else if metrics != nil {
 			printDetailedMetrics(metrics)
 		}
 
+		// 行数で集計しないファイルの変更数（設定の artifacts）
+		if report.Artifacts != nil {
+			printArtifactStats(report.Artifacts)
+		}
+
+		// コンテキスト別の内訳（設定の contexts）
+		if len(report.ByContext) > 0 {
+			printContextStats(report.ByContext)
+		}
+
+		// 言語・ファイル・ディレクトリ別の内訳（--by-language/--by-file/--by-dir）
+		if len(report.ByLanguage) > 0 {
+			printLanguageStats(report.ByLanguage)
+		}
+		if len(report.ByDir) > 0 {
+			printFileStats("By Directory:", "Directory", report.ByDir)
+		}
+		if len(report.ByFile) > 0 {
+			printFileStats("By File:", "File", report.ByFile)
+		}
+
+		// 作成者別の内訳（--by-author）
+		if len(report.AuthorBreakdown) > 0 {
+			printAuthorBreakdown(report.AuthorBreakdown)
+		}
+
+		// 期間ごとのAI%の推移（--trend）
+		if report.Trend != nil {
+			printTrend(report.Trend)
+		}
+
+		// AIの変更の大きさの分布（--sizes）
+		if report.AIChangeSizes != nil {
+			printAIChangeSizes(report.AIChangeSizes)
+		}
+
 		// By Author（追加行数ベース）
-		if len(report.ByAuthor) > 0 {
+		if len(report.ByAuthor) > 0 && len(report.AuthorBreakdown) == 0 {
 			fmt.Println("By Author:")
 			for _, author := range report.ByAuthor {
 				icon := "○"
@@ -436,53 +906,53 @@ func formatRangeReport(report *tracker.Report, format string, metrics *tracker.D
 }
 
 // printDetailedMetrics prints detailed metrics
-func printDetailedMetrics(metrics *tracker.DetailedMetrics) {
-	if metrics == nil {
+func printDetailedMetrics(m *tracker.DetailedMetrics) {
+	if m == nil {
 		return
 	}
 
 	// コードベース貢献（純粋な追加）
-	totalContributions := metrics.Contributions.AIAdditions + metrics.Contributions.HumanAdditions
-	aiContribPct := 0.0
-	humanContribPct := 0.0
-	if totalContributions > 0 {
-		aiContribPct = float64(metrics.Contributions.AIAdditions) / float64(totalContributions) * 100
-		humanContribPct = float64(metrics.Contributions.HumanAdditions) / float64(totalContributions) * 100
-	}
+	totalContributions := m.Contributions.AIAdditions + m.Contributions.HumanAdditions
+	aiContribPct := metrics.SafePercent(m.Contributions.AIAdditions, totalContributions)
+	humanContribPct := metrics.SafePercent(m.Contributions.HumanAdditions, totalContributions)
 
 	fmt.Println("【コードベース貢献】（最終的なコード量への寄与）")
 	fmt.Printf("  総変更行数: %d行\n", totalContributions)
-	fmt.Printf("    □ AI生成:   %6d行 (%.1f%%)\n", metrics.Contributions.AIAdditions, aiContribPct)
-	fmt.Printf("    ○ 開発者:   %6d行 (%.1f%%)\n", metrics.Contributions.HumanAdditions, humanContribPct)
+	fmt.Printf("    □ AI生成:   %6d行 (%.1f%%)\n", m.Contributions.AIAdditions, aiContribPct)
+	fmt.Printf("    ○ 開発者:   %6d行 (%.1f%%)\n", m.Contributions.HumanAdditions, humanContribPct)
 	fmt.Println()
 
 	// 作業量貢献（追加+削除）
-	totalWork := metrics.WorkVolume.AIChanges + metrics.WorkVolume.HumanChanges
-	aiWorkPct := 0.0
-	humanWorkPct := 0.0
-	if totalWork > 0 {
-		aiWorkPct = float64(metrics.WorkVolume.AIChanges) / float64(totalWork) * 100
-		humanWorkPct = float64(metrics.WorkVolume.HumanChanges) / float64(totalWork) * 100
-	}
+	totalWork := m.WorkVolume.AIChanges + m.WorkVolume.HumanChanges
+	aiWorkPct := metrics.SafePercent(m.WorkVolume.AIChanges, totalWork)
+	humanWorkPct := metrics.SafePercent(m.WorkVolume.HumanChanges, totalWork)
 
 	fmt.Println("【作業量貢献】（実際の作業量）")
 	fmt.Printf("  総作業量: %d行\n", totalWork)
-	fmt.Printf("    □ AI作業:   %6d行 (%.1f%%)\n", metrics.WorkVolume.AIChanges, aiWorkPct)
-	fmt.Printf("       └ 追加: %d行, 削除: %d行\n", metrics.WorkVolume.AIAdded, metrics.WorkVolume.AIDeleted)
-	fmt.Printf("    ○ 開発者作業: %6d行 (%.1f%%)\n", metrics.WorkVolume.HumanChanges, humanWorkPct)
-	fmt.Printf("       └ 追加: %d行, 削除: %d行\n", metrics.WorkVolume.HumanAdded, metrics.WorkVolume.HumanDeleted)
+	fmt.Printf("    □ AI作業:   %6d行 (%.1f%%)\n", m.WorkVolume.AIChanges, aiWorkPct)
+	fmt.Printf("       └ 追加: %d行, 削除: %d行\n", m.WorkVolume.AIAdded, m.WorkVolume.AIDeleted)
+	fmt.Printf("    ○ 開発者作業: %6d行 (%.1f%%)\n", m.WorkVolume.HumanChanges, humanWorkPct)
+	fmt.Printf("       └ 追加: %d行, 削除: %d行\n", m.WorkVolume.HumanAdded, m.WorkVolume.HumanDeleted)
 	fmt.Println()
 
 	// 新規ファイル（オプション）
-	totalNewFiles := metrics.NewFiles.AINewLines + metrics.NewFiles.HumanNewLines
+	totalNewFiles := m.NewFiles.AINewLines + m.NewFiles.HumanNewLines
 	if totalNewFiles > 0 {
-		aiNewPct := float64(metrics.NewFiles.AINewLines) / float64(totalNewFiles) * 100
-		humanNewPct := float64(metrics.NewFiles.HumanNewLines) / float64(totalNewFiles) * 100
+		aiNewPct := metrics.SafePercent(m.NewFiles.AINewLines, totalNewFiles)
+		humanNewPct := metrics.SafePercent(m.NewFiles.HumanNewLines, totalNewFiles)
 
 		fmt.Println("【新規ファイル】（完全新規のコードのみ）")
 		fmt.Printf("  新規コード: %d行\n", totalNewFiles)
-		fmt.Printf("    □ AI新規:   %6d行 (%.1f%%)\n", metrics.NewFiles.AINewLines, aiNewPct)
-		fmt.Printf("    ○ 開発者新規: %6d行 (%.1f%%)\n", metrics.NewFiles.HumanNewLines, humanNewPct)
+		fmt.Printf("    □ AI新規:   %6d行 (%.1f%%)\n", m.NewFiles.AINewLines, aiNewPct)
+		fmt.Printf("    ○ 開発者新規: %6d行 (%.1f%%)\n", m.NewFiles.HumanNewLines, humanNewPct)
+		fmt.Println()
+	}
+
+	// 混在（もう一方が書いた既存行の削除・変更）
+	if m.Mixed.AIEditedByHuman+m.Mixed.HumanEditedByAI > 0 {
+		fmt.Println("【混在】（もう一方が書いた既存行の修正・削除）")
+		fmt.Printf("    □→○ AIのコードを開発者が修正: %6d行\n", m.Mixed.AIEditedByHuman)
+		fmt.Printf("    ○→□ 開発者のコードをAIが修正: %6d行\n", m.Mixed.HumanEditedByAI)
 		fmt.Println()
 	}
 }
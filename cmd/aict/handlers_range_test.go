@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -354,101 +355,80 @@ func TestAuthorCommitCountAccuracy(t *testing.T) {
 }
 
 
-// TestCalculateAuthorContribution は按分計算ロジックをテーブル駆動で検証する
-func TestCalculateAuthorContribution(t *testing.T) {
+// TestSplitFileContribution は按分計算ロジックをテーブル駆動で検証する
+func TestSplitFileContribution(t *testing.T) {
 	tests := []struct {
-		name             string
-		authorLines      int
-		totalAuthorLines int
-		totalAdded       int
-		totalDeleted     int
-		authorCount      int
-		wantAdded        int
-		wantDeleted      int
+		name         string
+		authorLines  []int
+		totalAdded   int
+		totalDeleted int
+		wantAdded    []int
+		wantDeleted  []int
 	}{
 		{
-			name:             "正常系: 30/100の按分でtotalAdded=50",
-			authorLines:      30,
-			totalAuthorLines: 100,
-			totalAdded:       50,
-			totalDeleted:     10,
-			authorCount:      2,
-			wantAdded:        15, // int(50 * 30/100) = 15
-			wantDeleted:      3,  // int(10 * 30/100) = 3
+			name:         "正常系: 30/70の按分",
+			authorLines:  []int{30, 70},
+			totalAdded:   50,
+			totalDeleted: 10,
+			wantAdded:    []int{15, 35},
+			wantDeleted:  []int{3, 7},
 		},
 		{
-			name:             "正常系: 100%の按分（単独作成者）",
-			authorLines:      100,
-			totalAuthorLines: 100,
-			totalAdded:       50,
-			totalDeleted:     20,
-			authorCount:      1,
-			wantAdded:        50,
-			wantDeleted:      20,
+			name:         "正常系: 単独作成者",
+			authorLines:  []int{100},
+			totalAdded:   50,
+			totalDeleted: 20,
+			wantAdded:    []int{50},
+			wantDeleted:  []int{20},
 		},
 		{
-			name:             "正常系: 50/50の按分",
-			authorLines:      50,
-			totalAuthorLines: 100,
-			totalAdded:       80,
-			totalDeleted:     40,
-			authorCount:      2,
-			wantAdded:        40, // int(80 * 0.5) = 40
-			wantDeleted:      20, // int(40 * 0.5) = 20
+			name:         "正常系: 50/50の按分",
+			authorLines:  []int{50, 50},
+			totalAdded:   80,
+			totalDeleted: 40,
+			wantAdded:    []int{40, 40},
+			wantDeleted:  []int{20, 20},
 		},
 		{
-			name:             "エッジケース: totalAuthorLines=0, authorCount=1（削除のみ返す）",
-			authorLines:      0,
-			totalAuthorLines: 0,
-			totalAdded:       0,
-			totalDeleted:     15,
-			authorCount:      1,
-			wantAdded:        0,
-			wantDeleted:      15,
+			name:         "エッジケース: 作成者行数0, 単独作成者（削除のみ返す）",
+			authorLines:  []int{0},
+			totalAdded:   0,
+			totalDeleted: 15,
+			wantAdded:    []int{0},
+			wantDeleted:  []int{15},
 		},
 		{
-			name:             "エッジケース: totalAuthorLines=0, authorCount>1（ゼロ返却）",
-			authorLines:      0,
-			totalAuthorLines: 0,
-			totalAdded:       10,
-			totalDeleted:     5,
-			authorCount:      3,
-			wantAdded:        0,
-			wantDeleted:      0,
+			name:         "エッジケース: 作成者行数0, 複数作成者（ゼロ返却）",
+			authorLines:  []int{0, 0, 0},
+			totalAdded:   10,
+			totalDeleted: 5,
+			wantAdded:    []int{0, 0, 0},
+			wantDeleted:  []int{0, 0, 0},
 		},
 		{
-			name:             "ゼロ値テスト: すべて0",
-			authorLines:      0,
-			totalAuthorLines: 0,
-			totalAdded:       0,
-			totalDeleted:     0,
-			authorCount:      1,
-			wantAdded:        0,
-			wantDeleted:      0,
+			name:         "ゼロ値テスト: すべて0",
+			authorLines:  []int{0},
+			wantAdded:    []int{0},
+			wantDeleted:  []int{0},
 		},
 		{
-			name:             "按分の端数切り捨て",
-			authorLines:      1,
-			totalAuthorLines: 3,
-			totalAdded:       10,
-			totalDeleted:     7,
-			authorCount:      3,
-			wantAdded:        3, // int(10 * 1/3) = int(3.33) = 3
-			wantDeleted:      2, // int(7 * 1/3) = int(2.33) = 2
+			name:         "按分の端数は合計がnumstatと一致するよう配分",
+			authorLines:  []int{1, 1, 1},
+			totalAdded:   10,
+			totalDeleted: 7,
+			wantAdded:    []int{4, 3, 3}, // 切り捨てのみだと 3+3+3=9 で1行欠落していた
+			wantDeleted:  []int{3, 2, 2},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			added, deleted := calculateAuthorContribution(
-				tt.authorLines, tt.totalAuthorLines,
-				tt.totalAdded, tt.totalDeleted, tt.authorCount,
-			)
-			if added != tt.wantAdded {
-				t.Errorf("added = %d, want %d", added, tt.wantAdded)
+			added, deleted := splitFileContribution(tt.authorLines, tt.totalAdded, tt.totalDeleted)
+			if !reflect.DeepEqual(added, tt.wantAdded) {
+				t.Errorf("added = %v, want %v", added, tt.wantAdded)
 			}
-			if deleted != tt.wantDeleted {
-				t.Errorf("deleted = %d, want %d", deleted, tt.wantDeleted)
+			if !reflect.DeepEqual(deleted, tt.wantDeleted) {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantDeleted)
 			}
 		})
 	}
@@ -667,8 +647,8 @@ func TestProcessFileAuthors(t *testing.T) {
 		processFileAuthors(result, fileInfo, numstat, authorsInCommit)
 
 		// claude: ratio = 30/41
-		// added = int(41 * 30/41) = int(30.0) = 30
-		// deleted = int(10 * 30/41) = int(7.31) = 7
+		// added = 41 * 30/41 = 30
+		// deleted = 10 * 30/41 = 7.31 → 7
 		claudeStats := result.byAuthor["claude"]
 		if claudeStats == nil {
 			t.Fatal("claude のAuthorStatsが作成されていない")
@@ -678,7 +658,8 @@ func TestProcessFileAuthors(t *testing.T) {
 		}
 
 		// developer: ratio = 11/41
-		// added = int(41 * 11/41) = int(11.0) = 11
+		// added = 41 * 11/41 = 11
+		// deleted = 10 * 11/41 = 2.68 → 端数配分で3（合計10）
 		devStats := result.byAuthor["developer"]
 		if devStats == nil {
 			t.Fatal("developer のAuthorStatsが作成されていない")
@@ -703,6 +684,40 @@ func TestProcessFileAuthors(t *testing.T) {
 			t.Errorf("totalHuman = %d, want 11", result.totalHuman)
 		}
 	})
+
+	t.Run("AI帰属割合_ai_weight_按分", func(t *testing.T) {
+		result := &authorStatsResult{
+			byAuthor: make(map[string]*tracker.AuthorStats),
+		}
+		authorsInCommit := make(map[string]bool)
+
+		weight := 0.3
+		fileInfo := tracker.FileInfo{
+			Authors: []tracker.AuthorInfo{
+				{
+					Name:     "developer",
+					Type:     tracker.AuthorTypeHuman,
+					Lines:    [][]int{{1, 10}},
+					AIWeight: &weight,
+				},
+			},
+		}
+		numstat := [2]int{10, 4}
+
+		processFileAuthors(result, fileInfo, numstat, authorsInCommit)
+
+		// added=10 → AI 3 / Human 7、deleted=4 → AI 1.2→1 / Human 3
+		if result.totalAI != 3 || result.totalHuman != 7 {
+			t.Errorf("totalAI/totalHuman = %d/%d, want 3/7", result.totalAI, result.totalHuman)
+		}
+		if wv := result.detailedMetrics.WorkVolume; wv.AIDeleted != 1 || wv.HumanDeleted != 3 {
+			t.Errorf("AIDeleted/HumanDeleted = %d/%d, want 1/3", wv.AIDeleted, wv.HumanDeleted)
+		}
+		stats := result.byAuthor["developer"]
+		if stats == nil || stats.Lines != 10 || stats.AILines != 3 {
+			t.Errorf("developer stats = %+v, want Lines=10 AILines=3", stats)
+		}
+	})
 }
 
 // TestProcessCommitFiles はAuthorshipLogとnumstatMapから正しく集計されることを検証する
@@ -759,6 +774,12 @@ func TestProcessCommitFiles(t *testing.T) {
 		if !authorsInCommit["developer"] {
 			t.Error("developer がauthorsInCommitに登録されていない")
 		}
+
+		// AIの変更（--sizes）はAIが行を追加したファイルのみ
+		want := []tracker.AIChange{{Commit: "abc123", Path: "main.go", Lines: 20}}
+		if !reflect.DeepEqual(result.aiChanges, want) {
+			t.Errorf("aiChanges = %+v, want %+v", result.aiChanges, want)
+		}
 	})
 
 	t.Run("ファイルがnumstatにない場合スキップ", func(t *testing.T) {
@@ -955,3 +976,121 @@ func TestConvertSinceToRange(t *testing.T) {
 		}
 	})
 }
+
+func TestEnsureRangeHistory_ShallowClone(t *testing.T) {
+	origExecutor := newExecutor
+	defer func() { newExecutor = origExecutor }()
+
+	deepened := 0
+	fetchedNotes := false
+	mock := gitexec.NewMockExecutor()
+	mock.RunFunc = func(args ...string) (string, error) {
+		switch {
+		case args[0] == "rev-parse" && args[1] == "--is-shallow-repository":
+			return "true", nil
+		case args[0] == "rev-parse" && args[1] == "--verify":
+			return "", fmt.Errorf("exit status 1")
+		case args[0] == "fetch" && args[1] == "--quiet":
+			deepened++
+			return "", nil
+		case args[0] == "fetch":
+			fetchedNotes = true
+			return "", nil
+		case args[0] == "merge-base":
+			if deepened >= 2 {
+				return "base123", nil
+			}
+			return "", fmt.Errorf("no merge base")
+		}
+		return "", nil
+	}
+	newExecutor = func() gitexec.Executor { return mock }
+
+	ensureRangeHistory("origin/main..HEAD")
+
+	if !fetchedNotes {
+		t.Error("expected authorship notes to be fetched in shallow clone")
+	}
+	if deepened != 2 {
+		t.Errorf("expected 2 deepen calls, got %d", deepened)
+	}
+}
+
+func TestEnsureRangeHistory_FullClone(t *testing.T) {
+	origExecutor := newExecutor
+	defer func() { newExecutor = origExecutor }()
+	t.Setenv("CI", "")
+
+	mock := gitexec.NewMockExecutor()
+	mock.RunFunc = func(args ...string) (string, error) {
+		if args[0] == "rev-parse" && args[1] == "--is-shallow-repository" {
+			return "false", nil
+		}
+		t.Errorf("unexpected git call in full clone: %v", args)
+		return "", nil
+	}
+	newExecutor = func() gitexec.Executor { return mock }
+
+	ensureRangeHistory("origin/main..HEAD")
+}
+
+func TestConvertSinceToRangeOn_Branch(t *testing.T) {
+	origExecutor := newExecutor
+	defer func() { newExecutor = origExecutor }()
+
+	var logArgs []string
+	mock := gitexec.NewMockExecutor()
+	mock.RunFunc = func(args ...string) (string, error) {
+		switch args[0] {
+		case "log":
+			logArgs = args
+			return "first-commit\nsecond-commit", nil
+		case "rev-parse":
+			return "parent-hash", nil
+		}
+		return "", fmt.Errorf("unexpected call: %v", args)
+	}
+	newExecutor = func() gitexec.Executor { return mock }
+
+	got, err := convertSinceToRangeOn("7d", "feature/x")
+	if err != nil {
+		t.Fatalf("convertSinceToRangeOn() error = %v", err)
+	}
+	if got != "first-commit^..feature/x" {
+		t.Errorf("convertSinceToRangeOn() = %q, want %q", got, "first-commit^..feature/x")
+	}
+	if len(logArgs) < 2 || logArgs[len(logArgs)-2] != "--end-of-options" || logArgs[len(logArgs)-1] != "feature/x" {
+		t.Errorf("git log args = %v, want branch after --end-of-options", logArgs)
+	}
+}
+
+func TestHandleRangeReport_BranchWithRange(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"aict", "report", "--range", "HEAD~1..HEAD", "--branch", "main"}
+	err := handleRangeReport()
+	if err == nil || !strings.Contains(err.Error(), "--branch") {
+		t.Errorf("handleRangeReport() error = %v, want --branch/--range conflict", err)
+	}
+
+	os.Args = []string{"aict", "report", "--since", "7d", "--branch", "--upload-pack=evil"}
+	if err := handleRangeReport(); err == nil {
+		t.Error("handleRangeReport() should reject a branch starting with '-'")
+	}
+}
+
+func TestHandleRangeReport_CommitsAlias(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"aict", "report", "--commits", "v1.3.0..v1.4.0", "--range", "HEAD~1..HEAD"}
+	if err := handleRangeReport(); err == nil || !strings.Contains(err.Error(), "--commits") {
+		t.Errorf("handleRangeReport() error = %v, want --commits/--range conflict", err)
+	}
+
+	os.Args = []string{"aict", "report", "--commits", "HEAD~1..HEAD", "--since", "7d"}
+	if err := handleRangeReport(); err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Errorf("handleRangeReport() error = %v, want --since conflict", err)
+	}
+}
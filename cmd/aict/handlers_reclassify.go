@@ -0,0 +1,264 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/authorship"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// reclassifyOptions は aict reclassify のフィルタと動作指定
+type reclassifyOptions struct {
+	To     tracker.AuthorType
+	Author string // 作成者名（AuthorMappings解決後の名前でも一致）
+	Tool   string // チェックポイントの model メタデータ（aict checkpoint --model）
+	Since  string
+	Until  string
+	Branch string
+	Range  string
+	Apply  bool // false の場合はプレビューのみ
+}
+
+// filters は監査ログに記録する指定済みフィルタを返します。
+func (o *reclassifyOptions) filters() map[string]string {
+	f := make(map[string]string)
+	for key, value := range map[string]string{
+		"author": o.Author, "tool": o.Tool, "since": o.Since,
+		"until": o.Until, "branch": o.Branch, "range": o.Range,
+	} {
+		if value != "" {
+			f[key] = value
+		}
+	}
+	return f
+}
+
+// handleReclassify handles the reclassify command
+func handleReclassify() error {
+	fs := flag.NewFlagSet("reclassify", flag.ExitOnError)
+	opts := &reclassifyOptions{}
+	to := fs.String("to", "", "New author type: ai or human (required)")
+	fs.StringVar(&opts.Author, "author", "", "Only reclassify entries by this author")
+	fs.StringVar(&opts.Tool, "tool", "", "Only reclassify entries recorded with this model (checkpoint --model)")
+	fs.StringVar(&opts.Since, "since", "", "Only commits since date (e.g., '7d', '2025-01-01')")
+	fs.StringVar(&opts.Until, "until", "", "Only commits until date")
+	fs.StringVar(&opts.Branch, "branch", "", "Only commits reachable from this branch (default: HEAD)")
+	fs.StringVar(&opts.Range, "range", "", "Only commits in this range (e.g., 'origin/main..HEAD')")
+	fs.BoolVar(&opts.Apply, "apply", false, "Rewrite Authorship Logs (default: preview only)")
+	fs.Parse(os.Args[2:])
+
+	switch tracker.AuthorType(*to) {
+	case tracker.AuthorTypeAI, tracker.AuthorTypeHuman:
+		opts.To = tracker.AuthorType(*to)
+	default:
+		return fmt.Errorf("--to must be 'ai' or 'human'")
+	}
+	if opts.Author == "" && opts.Tool == "" {
+		return fmt.Errorf("--author or --tool is required to select the entries to reclassify")
+	}
+	if opts.Branch != "" && opts.Range != "" {
+		return fmt.Errorf("--branch and --range are mutually exclusive")
+	}
+	for _, rev := range []string{opts.Branch, opts.Range} {
+		if rev == "" {
+			continue
+		}
+		if err := gitexec.ValidateRevisionArg(rev); err != nil {
+			return err
+		}
+	}
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return err
+	}
+
+	return runReclassify(store, cfg, opts)
+}
+
+// runReclassify は対象のAuthorship Logを検索し、プレビュー表示または書き換えを行います。
+func runReclassify(store *storage.AIctStorage, cfg *tracker.Config, opts *reclassifyOptions) error {
+	commits, err := reclassifyTargetCommits(opts)
+	if err != nil {
+		return err
+	}
+
+	rev := reclassifyRevision(opts)
+	nm := gitnotes.NewNotesManagerWithExecutor(newExecutor())
+	logs, err := nm.GetAuthorshipLogsForRange(rev)
+	if err != nil {
+		return fmt.Errorf("loading authorship logs: %w", err)
+	}
+
+	var (
+		changes []tracker.ReclassifyChange
+		touched []*tracker.AuthorshipLog
+	)
+	for _, commit := range commits {
+		alog := logs[commit]
+		if alog == nil {
+			continue
+		}
+		if c := reclassifyLog(alog, commit, opts, cfg.AuthorMappings); len(c) > 0 {
+			changes = append(changes, c...)
+			touched = append(touched, alog)
+		}
+	}
+
+	mode := "preview"
+	if opts.Apply {
+		mode = "apply"
+	}
+	fmt.Printf("Reclassify to %s (%s)\n", opts.To, mode)
+
+	if len(changes) == 0 {
+		fmt.Println("No matching authorship entries found")
+		return nil
+	}
+
+	totalLines := 0
+	for _, c := range changes {
+		totalLines += c.Lines
+		fmt.Printf("  %s  %-20s %-5s → %-5s %4d files %7d lines\n",
+			shortCommit(c.Commit), c.Author, c.From, opts.To, c.Files, c.Lines)
+	}
+	fmt.Println()
+
+	if !opts.Apply {
+		fmt.Printf("%d entries in %d commits (%d lines) would be reclassified\n", len(changes), len(touched), totalLines)
+		fmt.Println("Run again with --apply to rewrite the Authorship Logs")
+		return nil
+	}
+
+	hashes := make([]string, 0, len(touched))
+	for _, alog := range touched {
+		if err := nm.AddAuthorshipLog(alog); err != nil {
+			return fmt.Errorf("updating authorship log for %s: %w", alog.Commit, err)
+		}
+		hashes = append(hashes, alog.Commit)
+	}
+
+	// 書き換えたコミットを含む日次集計は古い分類のため破棄（期間レポートはコミット単位で再集計される）
+	if dropped, err := store.DropDailyRollups(hashes); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to invalidate daily rollups: %v\n", err)
+	} else if dropped > 0 {
+		debugf("dropped %d stale daily rollups", dropped)
+	}
+
+	user, _ := newExecutor().Run("config", "user.name")
+	entry := &tracker.ReclassifyLogEntry{
+		Timestamp: time.Now(),
+		User:      user,
+		To:        opts.To,
+		Filters:   opts.filters(),
+		Changes:   changes,
+	}
+	if err := store.AppendReclassifyLog(entry); err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+
+	fmt.Printf("✓ Reclassified %d entries in %d commits (%d lines)\n", len(changes), len(touched), totalLines)
+	fmt.Printf("  Audit log: .git/aict/%s\n", storage.ReclassifyLogFileName)
+	fmt.Println("  Run 'aict sync push' to share the updated Authorship Logs")
+	fmt.Println("  Update ai_agents / author_mappings in config so future commits are classified correctly")
+	return nil
+}
+
+// reclassifyRevision はフィルタから対象のリビジョン指定を返します。
+func reclassifyRevision(opts *reclassifyOptions) string {
+	switch {
+	case opts.Range != "":
+		return opts.Range
+	case opts.Branch != "":
+		return opts.Branch
+	default:
+		return "HEAD"
+	}
+}
+
+// reclassifyTargetCommits は期間・ブランチ・範囲のフィルタに一致するコミットを古い順に返します。
+func reclassifyTargetCommits(opts *reclassifyOptions) ([]string, error) {
+	args := []string{"log", "--format=%H", "--reverse"}
+	if opts.Since != "" {
+		args = append(args, "--since="+expandShorthandDate(opts.Since))
+	}
+	if opts.Until != "" {
+		args = append(args, "--until="+expandShorthandDate(opts.Until))
+	}
+	args = append(args, "--end-of-options", reclassifyRevision(opts))
+
+	output, err := newExecutor().Run(args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing commits: %w", err)
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// reclassifyLog はAuthorship Log内でフィルタに一致する作成者の種別を書き換え、変更内容を返します。
+// 変更は作成者ごとに集約します（同じ作成者の複数ファイルは1件）。
+func reclassifyLog(alog *tracker.AuthorshipLog, commit string, opts *reclassifyOptions, mappings map[string]string) []tracker.ReclassifyChange {
+	byAuthor := make(map[string]*tracker.ReclassifyChange)
+	var order []string
+
+	files := make([]string, 0, len(alog.Files))
+	for filePath := range alog.Files {
+		files = append(files, filePath)
+	}
+	sort.Strings(files)
+
+	for _, filePath := range files {
+		fileInfo := alog.Files[filePath]
+		for i := range fileInfo.Authors {
+			author := &fileInfo.Authors[i]
+			if author.Type == opts.To || !matchesReclassifyFilter(author, opts, mappings) {
+				continue
+			}
+
+			c, ok := byAuthor[author.Name]
+			if !ok {
+				c = &tracker.ReclassifyChange{Commit: commit, Author: author.Name, From: author.Type}
+				byAuthor[author.Name] = c
+				order = append(order, author.Name)
+			}
+			c.Files++
+			c.Lines += authorship.CountLines(author.Lines)
+			author.Type = opts.To // Authors はスライスのため alog 本体が書き換わる
+		}
+	}
+
+	changes := make([]tracker.ReclassifyChange, 0, len(order))
+	for _, name := range order {
+		changes = append(changes, *byAuthor[name])
+	}
+	return changes
+}
+
+// matchesReclassifyFilter は作成者エントリが --author / --tool に一致するかを判定します。
+func matchesReclassifyFilter(author *tracker.AuthorInfo, opts *reclassifyOptions, mappings map[string]string) bool {
+	if opts.Author != "" && author.Name != opts.Author && mappings[author.Name] != opts.Author {
+		return false
+	}
+	if opts.Tool != "" && author.Metadata["model"] != opts.Tool {
+		return false
+	}
+	return true
+}
+
+// shortCommit は表示用にコミットハッシュを7文字に短縮します。
+func shortCommit(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestReclassifyLog(t *testing.T) {
+	newLog := func() *tracker.AuthorshipLog {
+		return &tracker.AuthorshipLog{
+			Files: map[string]tracker.FileInfo{
+				"a.go": {Authors: []tracker.AuthorInfo{
+					{Name: "copilot", Type: tracker.AuthorTypeHuman, Lines: [][]int{{1, 10}}, Metadata: map[string]string{"model": "gpt-4o"}},
+					{Name: "dev", Type: tracker.AuthorTypeHuman, Lines: [][]int{{11, 12}}},
+				}},
+				"b.go": {Authors: []tracker.AuthorInfo{
+					{Name: "copilot", Type: tracker.AuthorTypeHuman, Lines: [][]int{{1, 5}}},
+				}},
+			},
+		}
+	}
+	mappings := map[string]string{"copilot": "GitHub Copilot"}
+
+	tests := []struct {
+		name      string
+		opts      reclassifyOptions
+		wantLines int
+		wantFiles int
+	}{
+		{"by author", reclassifyOptions{To: tracker.AuthorTypeAI, Author: "copilot"}, 15, 2},
+		{"by mapped author", reclassifyOptions{To: tracker.AuthorTypeAI, Author: "GitHub Copilot"}, 15, 2},
+		{"by tool", reclassifyOptions{To: tracker.AuthorTypeAI, Tool: "gpt-4o"}, 10, 1},
+		{"already target type", reclassifyOptions{To: tracker.AuthorTypeHuman, Author: "copilot"}, 0, 0},
+		{"no match", reclassifyOptions{To: tracker.AuthorTypeAI, Author: "someone"}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alog := newLog()
+			changes := reclassifyLog(alog, "abc", &tt.opts, mappings)
+
+			lines, files := 0, 0
+			for _, c := range changes {
+				lines += c.Lines
+				files += c.Files
+				if c.Commit != "abc" || c.From == tt.opts.To {
+					t.Errorf("unexpected change %+v", c)
+				}
+			}
+			if lines != tt.wantLines || files != tt.wantFiles {
+				t.Errorf("reclassified %d lines in %d files, want %d in %d", lines, files, tt.wantLines, tt.wantFiles)
+			}
+
+			// dev は対象外のまま
+			if got := alog.Files["a.go"].Authors[1].Type; got != tracker.AuthorTypeHuman {
+				t.Errorf("dev type = %s, should be unchanged", got)
+			}
+			if tt.wantLines > 0 && alog.Files["a.go"].Authors[0].Type != tt.opts.To {
+				t.Errorf("copilot type = %s, want %s", alog.Files["a.go"].Authors[0].Type, tt.opts.To)
+			}
+		})
+	}
+}
+
+func TestRunReclassify_PreviewAndApply(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	testutil.CreateTestFile(t, tmpDir, "a.go", "package main\n\nfunc a() {}\n")
+	commit := testutil.GitCommit(t, tmpDir, "Add a")
+
+	nm := gitnotes.NewNotesManager()
+	if err := nm.AddAuthorshipLog(&tracker.AuthorshipLog{
+		Version: "1.0",
+		Commit:  commit,
+		Files: map[string]tracker.FileInfo{
+			"a.go": {Authors: []tracker.AuthorInfo{{Name: "cursor", Type: tracker.AuthorTypeHuman, Lines: [][]int{{1, 3}}}}},
+		},
+	}); err != nil {
+		t.Fatalf("AddAuthorshipLog() error = %v", err)
+	}
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	if err := store.MergeDailyRollup(&tracker.DailyRollup{Date: "2025-01-01", Commits: []string{commit}, HumanAdded: 3}); err != nil {
+		t.Fatalf("MergeDailyRollup() error = %v", err)
+	}
+
+	opts := &reclassifyOptions{To: tracker.AuthorTypeAI, Author: "cursor"}
+
+	// プレビューでは何も書き換えない
+	if err := runReclassify(store, cfg, opts); err != nil {
+		t.Fatalf("runReclassify(preview) error = %v", err)
+	}
+	alog, _ := nm.GetAuthorshipLog(commit)
+	if alog.Files["a.go"].Authors[0].Type != tracker.AuthorTypeHuman {
+		t.Error("preview should not modify the authorship log")
+	}
+	if entries, _ := store.LoadReclassifyLog(); len(entries) != 0 {
+		t.Errorf("preview should not write audit log, got %d entries", len(entries))
+	}
+
+	opts.Apply = true
+	if err := runReclassify(store, cfg, opts); err != nil {
+		t.Fatalf("runReclassify(apply) error = %v", err)
+	}
+
+	alog, _ = nm.GetAuthorshipLog(commit)
+	if alog.Files["a.go"].Authors[0].Type != tracker.AuthorTypeAI {
+		t.Errorf("type = %s, want ai", alog.Files["a.go"].Authors[0].Type)
+	}
+
+	entries, err := store.LoadReclassifyLog()
+	if err != nil {
+		t.Fatalf("LoadReclassifyLog() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].To != tracker.AuthorTypeAI || entries[0].Filters["author"] != "cursor" ||
+		len(entries[0].Changes) != 1 || entries[0].Changes[0].Lines != 3 {
+		t.Errorf("audit log = %+v", entries)
+	}
+
+	if rollups, _ := store.LoadDailyRollups(); len(rollups) != 0 {
+		t.Errorf("stale rollups should be dropped, got %+v", rollups)
+	}
+
+	// 再実行しても対象はない（既にai）
+	if err := runReclassify(store, cfg, opts); err != nil {
+		t.Fatalf("runReclassify(rerun) error = %v", err)
+	}
+	if entries, _ := store.LoadReclassifyLog(); len(entries) != 1 {
+		t.Errorf("no-op rerun should not append audit log, got %d entries", len(entries))
+	}
+}
+
+func TestHandleReclassify_Validation(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"missing --to", []string{"--author", "dev"}},
+		{"invalid --to", []string{"--to", "bot", "--author", "dev"}},
+		{"no selector", []string{"--to", "ai"}},
+		{"branch and range", []string{"--to", "ai", "--author", "dev", "--branch", "main", "--range", "a..b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"aict", "reclassify"}, tt.args...)
+			if err := handleReclassify(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/authorship"
+	"github.com/y-hirakaw/ai-code-tracker/internal/blame"
+	"github.com/y-hirakaw/ai-code-tracker/internal/git"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/ownership"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// reconcileResult はsquashコミットに引き継いだ行数です。
+type reconcileResult struct {
+	log        *tracker.AuthorshipLog
+	aiLines    int
+	humanLines int
+	matched    int // 元ブランチのAuthorship Logから作成者を引き継いだ行
+}
+
+// reconcileAuthorKey は引き継いだ作成者エントリをまとめる単位です。
+type reconcileAuthorKey struct {
+	name   string
+	typ    tracker.AuthorType
+	model  string
+	weight string
+}
+
+// handleReconcile handles the reconcile command
+// squash merge・rebase で作られたコミットに、元ブランチのコミットのAuthorship Logから行ごとの作成者を引き継ぎます。
+func handleReconcile() error {
+	fs := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	from := fs.String("from", "", "Source branch (or commit) whose authorship logs are copied, e.g. 'origin/feature-x'")
+	commit := fs.String("commit", "HEAD", "Squashed or rebased commit to write the authorship log for")
+	dryRun := fs.Bool("dry-run", false, "Show the reconciled line counts without writing the authorship log")
+	force := fs.Bool("force", false, "Replace an existing authorship log of the commit")
+	fs.Parse(os.Args[2:])
+
+	if *from == "" {
+		fmt.Println("Usage: aict reconcile --from <source-branch> [--commit <rev>] [--dry-run] [--force]")
+		return fmt.Errorf("--from is required")
+	}
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return err
+	}
+	executor := newExecutor()
+	target, err := resolveCommit(executor, *commit)
+	if err != nil {
+		return err
+	}
+	source, err := resolveCommit(executor, *from)
+	if err != nil {
+		return err
+	}
+	if parents, _ := executor.Run("rev-list", "--parents", "-n", "1", target); len(strings.Fields(parents)) > 2 {
+		return fmt.Errorf("%s is a merge commit; its branch commits keep their own authorship logs", shortCommit(target))
+	}
+
+	nm := gitnotes.NewNotesManagerWithExecutor(executor)
+	if existing, err := nm.GetAuthorshipLog(target); err != nil {
+		return err
+	} else if existing != nil && !*force {
+		return fmt.Errorf("%s already has an authorship log; use --force to replace it", shortCommit(target))
+	}
+
+	result, err := buildReconciledLog(executor, nm, target, source, *from, cfg)
+	if err != nil {
+		return err
+	}
+
+	verb := "Reconciled"
+	if *dryRun {
+		verb = "Would reconcile"
+	} else {
+		redactAuthorshipLog(store, cfg, result.log)
+		if err := nm.AddAuthorshipLog(result.log); err != nil {
+			return fmt.Errorf("saving authorship log for %s: %w", shortCommit(target), err)
+		}
+		// 置き換えたコミットを含む日次集計は古い帰属のため破棄（期間レポートはコミット単位で再集計される）
+		if _, err := store.DropDailyRollups([]string{target}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to invalidate daily rollups: %v\n", err)
+		}
+	}
+
+	fmt.Printf("✓ %s authorship log for %s from %s (%d files)\n", verb, shortCommit(target), *from, len(result.log.Files))
+	fmt.Printf("  Lines: □ AI %d / ○ Human %d (%d lines matched in %s)\n", result.aiLines, result.humanLines, result.matched, *from)
+	if !*dryRun {
+		fmt.Println("  Run 'aict sync push' to share the updated Authorship Log")
+	}
+	return nil
+}
+
+// resolveCommit はリビジョンをコミットの完全なハッシュに解決します。
+func resolveCommit(executor gitexec.Executor, rev string) (string, error) {
+	hash, err := executor.Run("rev-parse", "--verify", "--end-of-options", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("invalid revision %q: %w", rev, err)
+	}
+	return hash, nil
+}
+
+// buildReconciledLog は target で追加された行を source の同じファイルの行と内容（前後の空白を除く）で対応づけ、
+// source の行を git blame した変更元のAuthorship Logから作成者を引き継ぎます。
+// 対応する行がない、または変更元にAuthorship Logがない行は target の作成者（人間）の行とします。
+func buildReconciledLog(executor gitexec.Executor, nm *gitnotes.NotesManager, target, source, sourceName string, cfg *tracker.Config) (*reconcileResult, error) {
+	info, err := executor.Run("log", "-1", "--format=%cI %aN", target)
+	if err != nil {
+		return nil, fmt.Errorf("reading commit %s: %w", shortCommit(target), err)
+	}
+	fields := strings.SplitN(info, " ", 2)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("unexpected commit info for %s: %q", shortCommit(target), info)
+	}
+	committed, err := time.Parse(time.RFC3339, fields[0])
+	if err != nil {
+		return nil, err
+	}
+	authorName := fields[1]
+
+	added, err := git.GetAddedLines(executor, target)
+	if err != nil {
+		return nil, err
+	}
+	logs := ownership.NewLogCache(nm)
+	logs.Preload()
+
+	result := &reconcileResult{log: &tracker.AuthorshipLog{
+		Version:   authorship.AuthorshipLogVersion,
+		Commit:    target,
+		Timestamp: committed,
+		Files:     make(map[string]tracker.FileInfo),
+	}}
+	message := "Reconciled from " + sourceName
+
+	paths := make([]string, 0, len(added))
+	for path := range added {
+		if tracker.IsRecordedFile(path, cfg) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		sourceLines := sourceLineOrigins(executor, source, path)
+
+		var keys []reconcileAuthorKey
+		entries := make(map[reconcileAuthorKey]*tracker.AuthorInfo)
+		addLine := func(key reconcileAuthorKey, template tracker.AuthorInfo, line int) {
+			e, ok := entries[key]
+			if !ok {
+				e = &template
+				e.Lines = nil
+				e.Metadata = map[string]string{"message": message}
+				if key.model != "" {
+					e.Metadata["model"] = key.model
+				}
+				entries[key] = e
+				keys = append(keys, key)
+			}
+			e.Lines = append(e.Lines, []int{line})
+		}
+		human := tracker.AuthorInfo{Name: authorName, Type: tracker.AuthorTypeHuman}
+		humanKey := reconcileAuthorKey{name: authorName, typ: tracker.AuthorTypeHuman}
+
+		for _, l := range added[path] {
+			text := strings.TrimSpace(l.Text)
+			var a *tracker.AuthorInfo
+			if queue := sourceLines[text]; len(queue) > 0 {
+				a = logs.Author(queue[0])
+				sourceLines[text] = queue[1:]
+			}
+			if a == nil {
+				addLine(humanKey, human, l.Line)
+				result.humanLines++
+				continue
+			}
+			key := reconcileAuthorKey{name: a.Name, typ: a.Type, model: a.Metadata["model"]}
+			if a.AIWeight != nil {
+				key.weight = fmt.Sprint(*a.AIWeight)
+			}
+			addLine(key, *a, l.Line)
+			result.matched++
+			if a.Type == tracker.AuthorTypeAI {
+				result.aiLines++
+			} else {
+				result.humanLines++
+			}
+		}
+
+		// 追加行のない（削除のみの）ファイルは target の作成者に帰属させる
+		if len(keys) == 0 {
+			result.log.Files[path] = tracker.FileInfo{Authors: []tracker.AuthorInfo{{
+				Name: authorName, Type: tracker.AuthorTypeHuman, Lines: [][]int{}, Metadata: map[string]string{"message": message},
+			}}}
+			continue
+		}
+		var fileInfo tracker.FileInfo
+		for _, key := range keys {
+			e := entries[key]
+			lines := make([]int, 0, len(e.Lines))
+			for _, r := range e.Lines {
+				lines = append(lines, r[0])
+			}
+			e.Lines = git.LineRanges(lines)
+			fileInfo.Authors = append(fileInfo.Authors, *e)
+		}
+		result.log.Files[path] = fileInfo
+	}
+
+	if err := authorship.ValidateAuthorshipLog(result.log); err != nil {
+		return nil, fmt.Errorf("invalid authorship log for %s: %w", shortCommit(target), err)
+	}
+	return result, nil
+}
+
+// sourceLineOrigins は source のファイルの各行の内容（前後の空白を除く）ごとに、その行の変更元を行番号順に返します。
+// source にファイルがない場合は空のマップを返します。
+func sourceLineOrigins(executor gitexec.Executor, source, path string) map[string][]blame.Origin {
+	result := make(map[string][]blame.Origin)
+	origins, err := blame.File(executor, source, path, nil)
+	if err != nil {
+		return result
+	}
+	lines := make([]int, 0, len(origins))
+	for n := range origins {
+		lines = append(lines, n)
+	}
+	sort.Ints(lines)
+	for _, n := range lines {
+		text := strings.TrimSpace(origins[n].Text)
+		result[text] = append(result[text], origins[n])
+	}
+	return result
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestHandleReconcile_SquashMerge(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	executor := newExecutor()
+	mainBranch, _ := executor.Run("symbolic-ref", "--short", "HEAD")
+
+	// feature ブランチ: AIが関数を追加し、人間が別の関数を追加
+	git("checkout", "-q", "-b", "feature")
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc ai() {}\n")
+	aiCommit := testutil.GitCommit(t, tmpDir, "Add ai")
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc ai() {}\n\nfunc human() {}\n")
+	humanCommit := testutil.GitCommit(t, tmpDir, "Add human")
+
+	nm := gitnotes.NewNotesManager()
+	for _, log := range []*tracker.AuthorshipLog{
+		{Version: "1.0", Commit: aiCommit, Files: map[string]tracker.FileInfo{
+			"main.go": {Authors: []tracker.AuthorInfo{{Name: "Claude Code", Type: tracker.AuthorTypeAI, Lines: [][]int{{2, 3}}, Metadata: map[string]string{"model": "sonnet"}}}},
+		}},
+		{Version: "1.0", Commit: humanCommit, Files: map[string]tracker.FileInfo{
+			"main.go": {Authors: []tracker.AuthorInfo{{Name: "Alice", Type: tracker.AuthorTypeHuman, Lines: [][]int{{4, 5}}}}},
+		}},
+	} {
+		if err := nm.AddAuthorshipLog(log); err != nil {
+			t.Fatalf("AddAuthorshipLog() error = %v", err)
+		}
+	}
+
+	// main へ squash merge（squash コミットにはAuthorship Logがない）
+	git("checkout", "-q", mainBranch)
+	git("merge", "-q", "--squash", "feature")
+	squash := testutil.GitCommit(t, tmpDir, "Squashed feature")
+
+	os.Args = []string{"aict", "reconcile", "--from", "feature", "--dry-run"}
+	if err := handleReconcile(); err != nil {
+		t.Fatalf("handleReconcile(--dry-run) error = %v", err)
+	}
+	if log, _ := nm.GetAuthorshipLog(squash); log != nil {
+		t.Fatal("--dry-run should not write the authorship log")
+	}
+
+	os.Args = []string{"aict", "reconcile", "--from", "feature"}
+	if err := handleReconcile(); err != nil {
+		t.Fatalf("handleReconcile() error = %v", err)
+	}
+	log, err := nm.GetAuthorshipLog(squash)
+	if err != nil || log == nil {
+		t.Fatalf("squash commit should have an authorship log, got %v (err=%v)", log, err)
+	}
+
+	authors := log.Files["main.go"].Authors
+	if len(authors) != 2 {
+		t.Fatalf("authors = %+v, want AI and human entries", authors)
+	}
+	for _, a := range authors {
+		switch a.Type {
+		case tracker.AuthorTypeAI:
+			if a.Name != "Claude Code" || a.Metadata["model"] != "sonnet" || !reflect.DeepEqual(a.Lines, [][]int{{2, 3}}) {
+				t.Errorf("AI entry = %+v, want Claude Code/sonnet on lines 2-3", a)
+			}
+		case tracker.AuthorTypeHuman:
+			if a.Name != "Alice" || !reflect.DeepEqual(a.Lines, [][]int{{4, 5}}) {
+				t.Errorf("human entry = %+v, want Alice on lines 4-5", a)
+			}
+		}
+	}
+
+	// 既存のログは --force なしでは置き換えない
+	os.Args = []string{"aict", "reconcile", "--from", "feature"}
+	if err := handleReconcile(); err == nil {
+		t.Error("handleReconcile() should fail when the commit already has an authorship log")
+	}
+}
+
+func TestConfigureNotesRewrite(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	executor := newExecutor()
+	if added, err := configureNotesRewrite(executor); err != nil || !added {
+		t.Fatalf("configureNotesRewrite() = %v, %v, want added", added, err)
+	}
+	if added, _ := configureNotesRewrite(executor); added {
+		t.Error("configureNotesRewrite() should be idempotent")
+	}
+	if got, _ := executor.Run("config", "--get-all", "notes.rewriteRef"); got != gitnotes.AuthorshipNotesFullRef {
+		t.Errorf("notes.rewriteRef = %q, want %q", got, gitnotes.AuthorshipNotesFullRef)
+	}
+
+	if !removeNotesRewrite(executor) {
+		t.Error("removeNotesRewrite() should report the removal")
+	}
+	if _, err := executor.Run("config", "--get", "notes.rewriteRef"); err == nil {
+		t.Error("notes.rewriteRef should be removed")
+	}
+}
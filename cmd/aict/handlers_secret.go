@@ -0,0 +1,196 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/secrets"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+const secretUsage = "Usage: aict secret [set|delete|check] <name>"
+
+// secretHeaderWords は値が秘密情報と思われるHTTPヘッダ名に含まれる語です（config validate の警告用）。
+var secretHeaderWords = []string{"authorization", "token", "key", "secret", "password"}
+
+func handleSecret() error {
+	if len(os.Args) < 3 {
+		fmt.Println(secretUsage)
+		return fmt.Errorf("secret subcommand required")
+	}
+
+	switch os.Args[2] {
+	case "set":
+		return handleSecretSet()
+	case "delete":
+		return handleSecretDelete()
+	case "check":
+		return handleSecretCheck()
+	default:
+		fmt.Printf("Unknown subcommand: %s\n", os.Args[2])
+		fmt.Println(secretUsage)
+		return fmt.Errorf("unknown subcommand: %s", os.Args[2])
+	}
+}
+
+// handleSecretSet は標準入力の1行目を秘密情報としてOSのキーチェーンに保存します。
+// シェルの履歴に残らないよう、値は引数では受け取りません。
+func handleSecretSet() error {
+	fs := flag.NewFlagSet("secret set", flag.ExitOnError)
+	fs.Parse(os.Args[3:])
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: aict secret set <name>  (value is read from stdin)")
+	}
+	name := fs.Arg(0)
+	if err := secrets.ValidateName(name); err != nil {
+		return err
+	}
+
+	keychain, err := secrets.Keychain()
+	if err != nil {
+		return fmt.Errorf("%w; set %s in the environment instead", err, secrets.EnvName(name))
+	}
+
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+	}
+	value, err := stdinReader.ReadString('\n')
+	value = strings.TrimRight(value, "\r\n")
+	if value == "" {
+		if err != nil {
+			return fmt.Errorf("reading secret value: %w", err)
+		}
+		return fmt.Errorf("secret value is empty")
+	}
+
+	if err := keychain.Set(name, value); err != nil {
+		return fmt.Errorf("storing secret in %s: %w", keychain.Name(), err)
+	}
+	fmt.Printf("✓ Stored secret %s in %s (reference it as ${secret:%s})\n", name, keychain.Name(), name)
+	return nil
+}
+
+func handleSecretDelete() error {
+	fs := flag.NewFlagSet("secret delete", flag.ExitOnError)
+	fs.Parse(os.Args[3:])
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: aict secret delete <name>")
+	}
+	name := fs.Arg(0)
+	if err := secrets.ValidateName(name); err != nil {
+		return err
+	}
+
+	keychain, err := secrets.Keychain()
+	if err != nil {
+		return err
+	}
+	if err := keychain.Delete(name); err != nil {
+		if errors.Is(err, secrets.ErrNotFound) {
+			return fmt.Errorf("secret %s is not stored in %s", name, keychain.Name())
+		}
+		return fmt.Errorf("deleting secret from %s: %w", keychain.Name(), err)
+	}
+	fmt.Printf("✓ Deleted secret %s from %s\n", name, keychain.Name())
+	return nil
+}
+
+// handleSecretCheck は秘密情報を解決できるかを、値を表示せずに確認します。
+// 名前を省略した場合は設定から参照されているすべての秘密情報を確認します。
+func handleSecretCheck() error {
+	fs := flag.NewFlagSet("secret check", flag.ExitOnError)
+	fs.Parse(os.Args[3:])
+
+	names := fs.Args()
+	if len(names) == 0 {
+		_, cfg, err := loadStorageAndConfig()
+		if err != nil {
+			return err
+		}
+		names = configSecretReferences(cfg)
+		if len(names) == 0 {
+			fmt.Println("No secrets referenced in config")
+			return nil
+		}
+	}
+
+	missing := 0
+	for _, name := range names {
+		_, source, err := secrets.Lookup(name)
+		if err != nil {
+			fmt.Printf("✗ %s: %v\n", name, err)
+			missing++
+			continue
+		}
+		fmt.Printf("✓ %s (%s)\n", name, source)
+	}
+	if missing > 0 {
+		return fmt.Errorf("%d secret(s) could not be resolved", missing)
+	}
+	return nil
+}
+
+// configSecretReferences は設定（Webhook のURL・ヘッダ）が参照する秘密情報の名前を重複なしで返します。
+func configSecretReferences(cfg *tracker.Config) []string {
+	if cfg.Events == nil {
+		return nil
+	}
+	var names []string
+	seen := make(map[string]bool)
+	add := func(value string) {
+		for _, name := range secrets.References(value) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	for _, w := range cfg.Events.Webhooks {
+		add(w.URL)
+		for _, key := range sortedHeaderKeys(w.Headers) {
+			add(w.Headers[key])
+		}
+	}
+	return names
+}
+
+// plaintextSecretWarnings は秘密情報と思われるヘッダの値が平文で書かれている箇所を返します。
+func plaintextSecretWarnings(cfg *tracker.Config) []string {
+	if cfg.Events == nil {
+		return nil
+	}
+	var warnings []string
+	for i, w := range cfg.Events.Webhooks {
+		for _, key := range sortedHeaderKeys(w.Headers) {
+			value := w.Headers[key]
+			if value == "" || len(secrets.References(value)) > 0 || !looksLikeSecretHeader(key) {
+				continue
+			}
+			warnings = append(warnings, fmt.Sprintf("events.webhooks[%d].headers.%s is stored in plaintext; use ${secret:<name>} (see 'aict secret set')", i, key))
+		}
+	}
+	return warnings
+}
+
+func looksLikeSecretHeader(key string) bool {
+	lower := strings.ToLower(key)
+	for _, word := range secretHeaderWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedHeaderKeys(headers map[string]string) []string {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestConfigSecretReferences(t *testing.T) {
+	cfg := &tracker.Config{Events: &tracker.EventsConfig{Webhooks: []tracker.WebhookConfig{
+		{URL: "${secret:slack_url}"},
+		{URL: "https://example.com/hook", Headers: map[string]string{
+			"X-Api-Key":     "${secret:api_key}",
+			"Authorization": "Bearer ${secret:api_key}",
+		}},
+	}}}
+	want := []string{"slack_url", "api_key"}
+	if got := configSecretReferences(cfg); !reflect.DeepEqual(got, want) {
+		t.Errorf("configSecretReferences() = %q, want %q", got, want)
+	}
+	if got := configSecretReferences(&tracker.Config{}); got != nil {
+		t.Errorf("configSecretReferences() without events = %q, want nil", got)
+	}
+}
+
+func TestPlaintextSecretWarnings(t *testing.T) {
+	cfg := &tracker.Config{Events: &tracker.EventsConfig{Webhooks: []tracker.WebhookConfig{
+		{URL: "https://example.com", Headers: map[string]string{
+			"Authorization": "Bearer abc123",
+			"X-Auth-Token":  "${secret:token}",
+			"X-Source":      "aict",
+		}},
+	}}}
+	got := plaintextSecretWarnings(cfg)
+	if len(got) != 1 || !strings.HasPrefix(got[0], "events.webhooks[0].headers.Authorization is stored in plaintext") {
+		t.Errorf("plaintextSecretWarnings() = %q", got)
+	}
+}
+
+func TestHandleSecret(t *testing.T) {
+	setupConfigTest(t)
+	t.Setenv("AICT_SECRET_BACKEND", "env")
+
+	_, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	cfg.Events = &tracker.EventsConfig{Webhooks: []tracker.WebhookConfig{
+		{URL: "https://example.com", Headers: map[string]string{"Authorization": "Bearer ${secret:hook.token}"}},
+	}}
+	store, _, _ := loadStorageAndConfig()
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	os.Args = []string{"aict", "secret", "check"}
+	if err := handleSecret(); err == nil || !strings.Contains(err.Error(), "1 secret(s) could not be resolved") {
+		t.Errorf("check without secret: error = %v", err)
+	}
+
+	t.Setenv("AICT_SECRET_HOOK_TOKEN", "abc")
+	if err := handleSecret(); err != nil {
+		t.Errorf("check with env secret: error = %v", err)
+	}
+
+	// キーチェーンが使えない場合は環境変数を案内する
+	defer setStdinReader("value\n")()
+	os.Args = []string{"aict", "secret", "set", "hook.token"}
+	if err := handleSecret(); err == nil || !strings.Contains(err.Error(), "AICT_SECRET_HOOK_TOKEN") {
+		t.Errorf("set without keychain: error = %v", err)
+	}
+
+	os.Args = []string{"aict", "secret", "set", "bad name"}
+	if err := handleSecret(); err == nil || !strings.Contains(err.Error(), "invalid secret name") {
+		t.Errorf("set with invalid name: error = %v", err)
+	}
+}
@@ -2,16 +2,99 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
 	"github.com/y-hirakaw/ai-code-tracker/internal/templates"
 )
 
 // handleSetupHooksV2 handles SPEC.md準拠のhookセットアップ
 func handleSetupHooksV2() error {
+	fs := flag.NewFlagSet("setup-hooks", flag.ExitOnError)
+	settingsTarget := fs.String("settings", claudeSettingsProject, "Claude Code settings to install into: project (.claude/settings.json), local (.claude/settings.local.json) or a file path")
+	pushNotes := fs.Bool("push-notes", false, "Also push authorship logs with 'git push' (adds remote.origin.push refspecs)")
+	ci := fs.Bool("ci", false, "Non-interactive, idempotent install for CI: write the post-commit hook into .git/hooks and create .git/aict if missing")
+	fs.Parse(os.Args[2:])
+
+	if *ci {
+		// CIではClaude Code設定（作業ツリーのファイル）は --settings を明示した場合のみ書き込む
+		settingsGiven := false
+		fs.Visit(func(f *flag.Flag) {
+			settingsGiven = settingsGiven || f.Name == "settings"
+		})
+		if !settingsGiven {
+			*settingsTarget = ""
+		}
+		return installCIHooks(*settingsTarget, *pushNotes)
+	}
+	return installHooks(*settingsTarget, *pushNotes)
+}
+
+// installCIHooks はCIコンテナ向けに、確認なしで何度実行しても同じ結果になるようhookを設置します。
+// husky・lefthook の設定は使わず、埋め込みテンプレートから .git/hooks/post-commit（core.hooksPath が
+// 設定されていればそのディレクトリ）を直接生成します
+// （aict以外の既存hookには呼び出し行を1行だけ追加）。.git/aict がなければデフォルト設定で作成するため、
+// 事前の 'aict init' は不要です。settingsTarget が空の場合はClaude Code設定を書き込みません。
+func installCIHooks(settingsTarget string, pushNotes bool) error {
+	repoRoot, gitDir, err := hookPaths()
+	if err != nil {
+		return err
+	}
+
+	// post-commit hookが処理をスキップしないよう .git/aict と設定を用意（既存の設定はそのまま）
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		return fmt.Errorf("initializing storage: %w", err)
+	}
+	if _, err := os.Stat(store.ConfigPath()); os.IsNotExist(err) {
+		if err := store.SaveConfig(newDefaultConfig()); err != nil {
+			return fmt.Errorf("saving config: %w", err)
+		}
+		fmt.Printf("✓ Default configuration saved to %s\n", store.ConfigPath())
+	}
+
+	hookPath := filepath.Join(gitHooksDir(repoRoot, gitDir), "post-commit")
+	if c := inspectPostCommitHook(hookPath); len(c.problems) > 0 {
+		if err := c.repair(); err != nil {
+			return fmt.Errorf("writing post-commit hook: %w", err)
+		}
+		fmt.Printf("✓ Git post-commit hook installed (%s)\n", hookPath)
+	} else {
+		fmt.Printf("✓ Git post-commit hook up to date (%s)\n", hookPath)
+	}
+
+	if settingsTarget != "" {
+		settingsPath := resolveClaudeSettingsPath(repoRoot, settingsTarget)
+		if err := setupClaudeSettings(settingsPath); err != nil {
+			return fmt.Errorf("setting up Claude Code settings: %w", err)
+		}
+		if settingsTarget == claudeSettingsLocal {
+			if err := excludeFromGit(repoRoot, gitDir, settingsPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to add %s to .git/info/exclude: %v\n", settingsPath, err)
+			}
+		}
+	}
+
+	if added, err := configureNotesRefspecs(newExecutor(), defaultNotesRemote, pushNotes); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to configure authorship log refspecs: %v\n", err)
+	} else if added {
+		fmt.Printf("✓ Authorship log refspecs added to remote.%s\n", defaultNotesRemote)
+	}
+	if added, err := configureNotesRewrite(newExecutor()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to configure notes.rewriteRef: %v\n", err)
+	} else if added {
+		fmt.Println("✓ Authorship logs follow rebased and amended commits (notes.rewriteRef)")
+	}
+	return nil
+}
+
+// installHooks はgit post-commit hookを設置し、指定先のClaude Code設定にaictのhookを統合します。
+// origin があれば Authorship Log の refspec も設定します（pushNotes の場合は push 側も）。
+func installHooks(settingsTarget string, pushNotes bool) error {
 	fmt.Println("Setting up AI Code Tracker hooks (SPEC.md)...")
 
 	// Gitリポジトリのルートディレクトリを取得
@@ -21,39 +104,57 @@ func handleSetupHooksV2() error {
 		return fmt.Errorf("failed to get repository root (are you in a git repo?): %w", err)
 	}
 
-	// .git ディレクトリの絶対パスを決定
-	// 注: 将来的には git rev-parse --git-dir を使うのがより堅牢だが、
-	// 現状の構成では .git がルート直下にあると仮定
-	gitDir := filepath.Join(repoRoot, ".git")
-
-	// .git/aict/hooks/ ディレクトリを作成
-	aictHooksDir := filepath.Join(gitDir, "aict", "hooks")
-	if err := os.MkdirAll(aictHooksDir, 0755); err != nil {
-		return fmt.Errorf("creating hooks directory: %w", err)
-	}
-
-	// Claude Code hooksを作成
-	if err := createClaudeHooks(aictHooksDir); err != nil {
-		return fmt.Errorf("creating Claude Code hooks: %w", err)
+	// .git ディレクトリの絶対パスを決定（worktreeでは共通gitディレクトリ）
+	gitDir, err := getGitCommonDir()
+	if err != nil {
+		return err
 	}
 
-	// Git post-commit hookを作成
-	if err := setupPostCommitHook(repoRoot); err != nil {
+	// Git post-commit hookを作成（husky・lefthook を使うリポジトリではその設定に追加、
+	// core.hooksPath が設定されていればそのディレクトリに作成）
+	hooksDir := gitHooksDir(repoRoot, gitDir)
+	postCommitTarget := filepath.Join(hooksDir, "post-commit")
+	if manager, path := detectHookManager(repoRoot); manager != "" {
+		if err := setupManagedPostCommit(manager, path); err != nil {
+			return fmt.Errorf("setting up %s post-commit hook: %w", manager, err)
+		}
+		postCommitTarget = path
+	} else if err := setupPostCommitHook(hooksDir); err != nil {
 		return fmt.Errorf("setting up post-commit hook: %w", err)
 	}
 
-	// .claude/settings.json を更新
-	if err := setupClaudeSettings(repoRoot); err != nil {
+	// Claude Code設定を更新（local はコミットされないよう .git/info/exclude に追加）
+	settingsPath := resolveClaudeSettingsPath(repoRoot, settingsTarget)
+	if err := setupClaudeSettings(settingsPath); err != nil {
 		return fmt.Errorf("setting up Claude Code settings: %w", err)
 	}
+	if settingsTarget == claudeSettingsLocal {
+		if err := excludeFromGit(repoRoot, gitDir, settingsPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to add %s to .git/info/exclude: %v\n", settingsPath, err)
+		}
+	}
+
+	// 通常の git fetch（・git push）で Authorship Log も同期されるよう refspec を設定
+	if added, err := configureNotesRefspecs(executor, defaultNotesRemote, pushNotes); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to configure authorship log refspecs: %v\n", err)
+	} else if added {
+		fmt.Printf("✓ Authorship log refspecs added to remote.%s\n", defaultNotesRemote)
+	}
+	// git rebase・git commit --amend で書き換えたコミットにも Authorship Log をコピー
+	if added, err := configureNotesRewrite(executor); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to configure notes.rewriteRef: %v\n", err)
+	} else if added {
+		fmt.Println("✓ Authorship logs follow rebased and amended commits (notes.rewriteRef)")
+	}
 
 	fmt.Println()
 	fmt.Println("✓ Hook setup complete!")
 	fmt.Println()
-	fmt.Println("Hooks created:")
-	fmt.Printf("  - %s/pre-tool-use.sh  (records human checkpoint)\n", aictHooksDir)
-	fmt.Printf("  - %s/post-tool-use.sh (records AI checkpoint)\n", aictHooksDir)
-	fmt.Printf("  - %s/hooks/post-commit           (generates Authorship Log)\n", gitDir)
+	fmt.Println("Hooks configured:")
+	fmt.Printf("  - %s: Claude Code PreToolUse/PostToolUse/Stop -> aict hook pre-tool-use / post-tool-use / stop\n", settingsPath)
+	fmt.Printf("  - %s -> aict hook post-commit\n", postCommitTarget)
+	fmt.Println()
+	fmt.Println("Note: 'aict' must be on PATH (aict.exe on Windows).")
 	fmt.Println()
 	fmt.Println("Claude Code will now automatically track AI vs Human contributions.")
 	fmt.Println()
@@ -61,31 +162,13 @@ func handleSetupHooksV2() error {
 	return nil
 }
 
-func createClaudeHooks(hooksDir string) error {
-	// pre-tool-use.sh
-	preHookPath := filepath.Join(hooksDir, "pre-tool-use.sh")
-	if err := os.WriteFile(preHookPath, []byte(templates.PreToolUseHook), 0755); err != nil {
-		return fmt.Errorf("failed to create pre-tool-use.sh: %w", err)
-	}
-
-	// post-tool-use.sh
-	postHookPath := filepath.Join(hooksDir, "post-tool-use.sh")
-	if err := os.WriteFile(postHookPath, []byte(templates.PostToolUseHook), 0755); err != nil {
-		return fmt.Errorf("failed to create post-tool-use.sh: %w", err)
-	}
-
-	fmt.Println("✓ Claude Code hooks created")
-	return nil
-}
-
-func setupPostCommitHook(repoRoot string) error {
-	// post-commit hookを.git/hooks/にコピー
-	gitHooksDir := filepath.Join(repoRoot, ".git", "hooks")
-	gitHookPath := filepath.Join(gitHooksDir, "post-commit")
+// setupPostCommitHook はhookディレクトリ（.git/hooks/ または core.hooksPath）にpost-commit hookを作成します。
+func setupPostCommitHook(hooksDir string) error {
+	gitHookPath := filepath.Join(hooksDir, "post-commit")
 
-	// .git/hooks/ディレクトリがなければ作成
-	if err := os.MkdirAll(gitHooksDir, 0755); err != nil {
-		return fmt.Errorf("failed to create .git/hooks directory: %w", err)
+	// hookディレクトリがなければ作成
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return fmt.Errorf("failed to create hooks directory %s: %w", hooksDir, err)
 	}
 
 	// 既存のpost-commit hookをチェック
@@ -103,6 +186,14 @@ func setupPostCommitHook(repoRoot string) error {
 			fmt.Println("  aict commit")
 			return nil
 		}
+
+		// aict以外のhookは 'aict uninstall' で復元できるよう退避
+		if !isAICTHookFile(gitHookPath) {
+			if err := os.Rename(gitHookPath, gitHookPath+hookBackupSuffix); err != nil {
+				return fmt.Errorf("failed to back up existing post-commit hook: %w", err)
+			}
+			fmt.Printf("✓ Existing hook backed up to %s%s\n", gitHookPath, hookBackupSuffix)
+		}
 	}
 
 	// post-commit hookを作成
@@ -110,39 +201,22 @@ func setupPostCommitHook(repoRoot string) error {
 		return fmt.Errorf("failed to create post-commit hook: %w", err)
 	}
 
-	fmt.Println("✓ Git post-commit hook installed")
+	fmt.Printf("✓ Git post-commit hook installed (%s)\n", gitHookPath)
 	return nil
 }
 
-func setupClaudeSettings(repoRoot string) error {
-	settingsDir := filepath.Join(repoRoot, ".claude")
-	if err := os.MkdirAll(settingsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create .claude directory: %w", err)
-	}
-
-	settingsPath := filepath.Join(settingsDir, "settings.json")
-
-	// 既存のsettings.jsonをチェック
-	if _, err := os.Stat(settingsPath); err == nil {
-		fmt.Printf("Warning: Claude Code settings already exist at %s\n", settingsPath)
-		fmt.Print("Do you want to overwrite it? (y/N): ")
-
-		reader := bufio.NewReader(os.Stdin)
-		response, _ := reader.ReadString('\n')
-		response = strings.TrimSpace(strings.ToLower(response))
-
-		if response != "y" && response != "yes" {
-			fmt.Println("Claude Code settings setup cancelled.")
-			fmt.Println("Please manually add hook configuration to .claude/settings.json")
-			return nil
-		}
+// setupClaudeSettings はClaude Code設定ファイルにaictのhookを書き込みます。
+// 既存のファイルは上書きせず、aict以外の設定・hookを残したままaictのエントリのみ入れ替えます。
+func setupClaudeSettings(settingsPath string) error {
+	_, statErr := os.Stat(settingsPath)
+	if err := writeAICTSettings(settingsPath); err != nil {
+		return err
 	}
 
-	// settings.jsonを作成
-	if err := os.WriteFile(settingsPath, []byte(templates.ClaudeSettingsJSON), 0644); err != nil {
-		return fmt.Errorf("failed to create settings.json: %w", err)
+	if statErr == nil {
+		fmt.Printf("✓ aict hooks merged into %s (other settings kept)\n", settingsPath)
+	} else {
+		fmt.Printf("✓ Claude Code settings configured (%s)\n", settingsPath)
 	}
-
-	fmt.Println("✓ Claude Code settings configured")
 	return nil
 }
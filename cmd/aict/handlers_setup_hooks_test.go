@@ -6,59 +6,9 @@ import (
 	"testing"
 
 	"github.com/y-hirakaw/ai-code-tracker/internal/templates"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
 )
 
-func TestCreateClaudeHooks(t *testing.T) {
-	// Create a temp directory to serve as the hooks directory
-	hooksDir := t.TempDir()
-
-	// Call createClaudeHooks
-	err := createClaudeHooks(hooksDir)
-	if err != nil {
-		t.Fatalf("createClaudeHooks() error = %v", err)
-	}
-
-	// Verify pre-tool-use.sh
-	t.Run("pre-tool-use.sh created with correct content", func(t *testing.T) {
-		preHookPath := filepath.Join(hooksDir, "pre-tool-use.sh")
-		content, err := os.ReadFile(preHookPath)
-		if err != nil {
-			t.Fatalf("failed to read pre-tool-use.sh: %v", err)
-		}
-		if string(content) != templates.PreToolUseHook {
-			t.Errorf("pre-tool-use.sh content mismatch\ngot length: %d\nwant length: %d", len(content), len(templates.PreToolUseHook))
-		}
-	})
-
-	// Verify post-tool-use.sh
-	t.Run("post-tool-use.sh created with correct content", func(t *testing.T) {
-		postHookPath := filepath.Join(hooksDir, "post-tool-use.sh")
-		content, err := os.ReadFile(postHookPath)
-		if err != nil {
-			t.Fatalf("failed to read post-tool-use.sh: %v", err)
-		}
-		if string(content) != templates.PostToolUseHook {
-			t.Errorf("post-tool-use.sh content mismatch\ngot length: %d\nwant length: %d", len(content), len(templates.PostToolUseHook))
-		}
-	})
-
-	// Verify executable permissions
-	t.Run("hooks have executable permissions", func(t *testing.T) {
-		for _, name := range []string{"pre-tool-use.sh", "post-tool-use.sh"} {
-			hookPath := filepath.Join(hooksDir, name)
-			info, err := os.Stat(hookPath)
-			if err != nil {
-				t.Fatalf("failed to stat %s: %v", name, err)
-			}
-			perm := info.Mode().Perm()
-			// Check that owner execute bit is set (0755 -> 0o100 bit)
-			if perm&0100 == 0 {
-				t.Errorf("%s: expected executable permission, got %o", name, perm)
-			}
-		}
-	})
-}
-
 func TestSetupPostCommitHook_NewHook(t *testing.T) {
 	// Create a temp directory structure simulating a git repository
 	repoRoot := t.TempDir()
@@ -68,7 +18,7 @@ func TestSetupPostCommitHook_NewHook(t *testing.T) {
 	}
 
 	// Call setupPostCommitHook (no existing hook, so no stdin prompt)
-	err := setupPostCommitHook(repoRoot)
+	err := setupPostCommitHook(filepath.Join(gitDir, "hooks"))
 	if err != nil {
 		t.Fatalf("setupPostCommitHook() error = %v", err)
 	}
@@ -119,8 +69,8 @@ func TestSetupClaudeSettings_NewSettings(t *testing.T) {
 	// Create a temp directory simulating a repo root
 	repoRoot := t.TempDir()
 
-	// Call setupClaudeSettings (no existing file, so no stdin prompt)
-	err := setupClaudeSettings(repoRoot)
+	// Call setupClaudeSettings (no existing file, so the template is written as is)
+	err := setupClaudeSettings(resolveClaudeSettingsPath(repoRoot, claudeSettingsProject))
 	if err != nil {
 		t.Fatalf("setupClaudeSettings() error = %v", err)
 	}
@@ -166,3 +116,52 @@ func TestSetupClaudeSettings_NewSettings(t *testing.T) {
 		}
 	})
 }
+
+func TestInstallCIHooks(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	// CIイメージに既存のユーザーhookがある状態（aict init は未実行）
+	hookPath := filepath.Join(tmpDir, ".git", "hooks", "post-commit")
+	if err := os.MkdirAll(filepath.Dir(hookPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hookPath, []byte("#!/bin/sh\necho custom\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := installCIHooks("", false); err != nil {
+			t.Fatalf("installCIHooks() run %d error = %v", i+1, err)
+		}
+	}
+
+	data, err := os.ReadFile(hookPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "#!/bin/sh\necho custom\n" + postCommitHookCommand + "\nexit 0\n"; string(data) != want {
+		t.Errorf("post-commit hook = %q, want %q", data, want)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".git", "aict", "config.json")); err != nil {
+		t.Errorf("config should be created without aict init: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".claude")); !os.IsNotExist(err) {
+		t.Error("Claude Code settings should not be written unless --settings is given")
+	}
+
+	// aictのhookがない場合はテンプレートをそのまま書き込む
+	os.Remove(hookPath)
+	if err := installCIHooks(claudeSettingsLocal, false); err != nil {
+		t.Fatalf("installCIHooks() error = %v", err)
+	}
+	if data, _ := os.ReadFile(hookPath); string(data) != templates.PostCommitHook {
+		t.Errorf("post-commit hook should match the template, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".claude", "settings.local.json")); err != nil {
+		t.Errorf("settings.local.json should be written: %v", err)
+	}
+}
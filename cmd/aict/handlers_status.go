@@ -0,0 +1,250 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// aict status のセッションの状態
+const (
+	sessionStateRunning  = "running"  // ツールを使用中（Stop hook 以降にツール使用あり）
+	sessionStateWaiting  = "waiting"  // 応答が完了し次の指示を待っている
+	sessionStateInactive = "inactive" // 最後の活動から --idle 以上経過
+	sessionStateNone     = "none"     // hookがセッションを記録していない
+)
+
+// statusWindowDays は aict status のAI%を集計する日数（直近と、比較するその前の期間）
+const statusWindowDays = 7
+
+// handleStatus handles the status command
+// 追跡の状況（ブランチ、直近7日間のAI%と前週からの増減、記録数、最後のチェックポイント、hookの設置状態）と、
+// Claude Codeのhookが記録した直近のセッションの状態・セッション中のAI・人間の変更行数を1画面で表示します。
+func handleStatus() error {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	idle := fs.Duration("idle", 30*time.Minute, "Treat the session as inactive after this long without tool use")
+	format := fs.String("format", "table", "Output format: table or json")
+	fields := fs.String("fields", "", fieldsFlagUsage)
+	watch := fs.Duration("watch", 0, "Refresh the status at this interval until interrupted (e.g., '5s')")
+	fs.Parse(os.Args[2:])
+
+	if *format != "table" && *format != "json" {
+		return fmt.Errorf("unknown format: %s (available: table, json)", *format)
+	}
+	if *fields != "" && *format != "json" {
+		return fmt.Errorf("--fields requires --format json")
+	}
+	if *idle <= 0 {
+		return fmt.Errorf("--idle must be positive")
+	}
+	if *watch < 0 {
+		return fmt.Errorf("--watch must not be negative")
+	}
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Run 'aict init' first\n")
+		return err
+	}
+
+	for {
+		status := &tracker.SessionStatus{}
+		status.Session, err = store.LoadSession()
+		if err != nil {
+			return fmt.Errorf("loading session: %w", err)
+		}
+		checkpoints, err := store.LoadCheckpoints()
+		if err == nil {
+			status.PendingCheckpoints = len(checkpoints)
+		}
+		now := time.Now()
+		status.State = sessionState(status.Session, now, *idle)
+		status.Tracking = collectTrackingStatus(store, cfg, checkpoints, now)
+
+		if *format == "json" {
+			data, err := marshalJSONFields(status, *fields)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		} else {
+			if *watch > 0 {
+				fmt.Print("\033[H\033[2J") // 画面を消去して再表示
+			}
+			printTrackingStatus(status.Tracking, now)
+			printSessionStatus(status, now)
+		}
+
+		if *watch == 0 {
+			return nil
+		}
+		time.Sleep(*watch)
+	}
+}
+
+// collectTrackingStatus はリポジトリの追跡状況を集計します。
+// 取得できない項目（ブランチ、日次集計、hookの検査）は空のまま返します。
+func collectTrackingStatus(store *storage.AIctStorage, cfg *tracker.Config, checkpoints []*tracker.CheckpointV2, now time.Time) *tracker.TrackingStatus {
+	t := &tracker.TrackingStatus{
+		TargetAIPercentage: cfg.TargetAIPercentage,
+		AuthorshipLogs:     gitnotes.NewNotesManager().CountAuthorshipLogs(),
+	}
+
+	// 最初のコミット前のブランチも取得できるよう symbolic-ref を使う（detached HEAD ではエラー）
+	if branch, err := newExecutor().Run("symbolic-ref", "--short", "HEAD"); err == nil {
+		t.Branch = branch
+	}
+
+	if rollups, err := store.LoadDailyRollups(); err == nil {
+		t.AIPercentage, t.PreviousAIPercentage = weeklyAIPercentages(rollups, now)
+	}
+
+	for _, cp := range checkpoints {
+		if t.LastCheckpoint == nil || cp.Timestamp.After(*t.LastCheckpoint) {
+			ts := cp.Timestamp
+			t.LastCheckpoint = &ts
+		}
+	}
+
+	if repoRoot, gitDir, err := hookPaths(); err == nil {
+		t.HookProblems = countHookProblems(inspectHooks(repoRoot, gitDir))
+	}
+	return t
+}
+
+// weeklyAIPercentages は日次集計から直近 statusWindowDays 日間（今日を含む）と、その前の同じ日数の
+// 追加行のAI%を返します。期間に追加行がない場合は nil です。
+func weeklyAIPercentages(rollups []*tracker.DailyRollup, now time.Time) (current, previous *float64) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	currentStart := today.AddDate(0, 0, -(statusWindowDays - 1)).Format("2006-01-02")
+	previousStart := today.AddDate(0, 0, -(2*statusWindowDays - 1)).Format("2006-01-02")
+	end := today.Format("2006-01-02")
+
+	var cur, prev [2]int // AI追加行, 人間追加行
+	for _, r := range rollups {
+		switch {
+		case r.Date > end || r.Date < previousStart:
+		case r.Date >= currentStart:
+			cur[0], cur[1] = cur[0]+r.AIAdded, cur[1]+r.HumanAdded
+		default:
+			prev[0], prev[1] = prev[0]+r.AIAdded, prev[1]+r.HumanAdded
+		}
+	}
+	percentage := func(lines [2]int) *float64 {
+		if lines[0]+lines[1] == 0 {
+			return nil
+		}
+		p := float64(lines[0]) / float64(lines[0]+lines[1]) * 100
+		return &p
+	}
+	return percentage(cur), percentage(prev)
+}
+
+// printTrackingStatus は追跡の状況を表示します。
+func printTrackingStatus(t *tracker.TrackingStatus, now time.Time) {
+	branch := t.Branch
+	if branch == "" {
+		branch = "(detached HEAD)"
+	}
+	fmt.Printf("Branch:          %s\n", branch)
+
+	if t.AIPercentage == nil {
+		fmt.Printf("AI (%d days):     no commits recorded (target %.0f%%)\n", statusWindowDays, t.TargetAIPercentage)
+	} else {
+		delta := "no commits in the previous week"
+		if t.PreviousAIPercentage != nil {
+			delta = fmt.Sprintf("%+.1fpt vs previous week", *t.AIPercentage-*t.PreviousAIPercentage)
+		}
+		fmt.Printf("AI (%d days):     %.1f%% (target %.0f%%, %s)\n", statusWindowDays, *t.AIPercentage, t.TargetAIPercentage, delta)
+	}
+
+	fmt.Printf("Authorship logs: %d commit(s)\n", t.AuthorshipLogs)
+	if t.LastCheckpoint == nil {
+		fmt.Println("Last checkpoint: none since last commit")
+	} else {
+		fmt.Printf("Last checkpoint: %s (%s ago)\n", t.LastCheckpoint.Local().Format("2006-01-02 15:04"), now.Sub(*t.LastCheckpoint).Round(time.Second))
+	}
+
+	if t.HookProblems == 0 {
+		fmt.Println("Hooks:           ✓ installed")
+	} else {
+		fmt.Printf("Hooks:           ✗ %d problem(s) (run 'aict hooks status')\n", t.HookProblems)
+	}
+	fmt.Println()
+}
+
+// applySessionActivity はツール使用のhookで記録したチェックポイントをセッションの状態に加算します。
+// セッションIDが変わった場合は新しいセッションとして行数を数え直します。
+func applySessionActivity(current *tracker.AISession, event string, input *hookInput, checkpoint *tracker.CheckpointV2, now time.Time) *tracker.AISession {
+	if current == nil || current.SessionID != input.SessionID {
+		current = &tracker.AISession{SessionID: input.SessionID, StartedAt: now}
+	}
+	current.LastActivity = now
+	current.StoppedAt = nil
+	if input.ToolName != "" {
+		current.LastTool = input.ToolName
+	}
+	if event == hookEventPostToolUse {
+		current.ToolUses++
+	}
+
+	for _, change := range checkpoint.Changes {
+		if checkpoint.Type == tracker.AuthorTypeAI {
+			current.AIAdded += change.Added
+			current.AIDeleted += change.Deleted
+		} else {
+			current.HumanAdded += change.Added
+			current.HumanDeleted += change.Deleted
+		}
+	}
+	return current
+}
+
+// sessionState はセッションの状態を判定します。
+func sessionState(session *tracker.AISession, now time.Time, idle time.Duration) string {
+	switch {
+	case session == nil:
+		return sessionStateNone
+	case now.Sub(session.LastActivity) > idle:
+		return sessionStateInactive
+	case session.StoppedAt != nil:
+		return sessionStateWaiting
+	default:
+		return sessionStateRunning
+	}
+}
+
+// printSessionStatus はセッションの状態を表示します。
+func printSessionStatus(status *tracker.SessionStatus, now time.Time) {
+	s := status.Session
+	switch status.State {
+	case sessionStateNone:
+		fmt.Println("AI session: none recorded yet (requires the Claude Code hooks from 'aict setup-hooks')")
+	case sessionStateInactive:
+		fmt.Printf("AI session: none in progress (last session %s, last activity %s ago)\n", shortSessionID(s.SessionID), now.Sub(s.LastActivity).Round(time.Second))
+	case sessionStateWaiting:
+		fmt.Printf("● AI session in progress: waiting for the next prompt (responded %s ago)\n", now.Sub(*s.StoppedAt).Round(time.Second))
+	default:
+		fmt.Printf("● AI session in progress: running (%s, %s ago)\n", s.LastTool, now.Sub(s.LastActivity).Round(time.Second))
+	}
+
+	if s != nil {
+		fmt.Printf("  Session:   %s (started %s, %s ago)\n", shortSessionID(s.SessionID), s.StartedAt.Local().Format("2006-01-02 15:04"), now.Sub(s.StartedAt).Round(time.Second))
+		fmt.Printf("  Tool uses: %d\n", s.ToolUses)
+		fmt.Printf("  Lines:     □ AI +%d -%d / ○ Human +%d -%d\n", s.AIAdded, s.AIDeleted, s.HumanAdded, s.HumanDeleted)
+	}
+	fmt.Printf("Pending checkpoints since last commit: %d\n", status.PendingCheckpoints)
+}
+
+// shortSessionID はセッションIDの先頭8文字を返します。
+func shortSessionID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestSessionState(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	stopped := now.Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		session *tracker.AISession
+		want    string
+	}{
+		{"記録なし", nil, sessionStateNone},
+		{"ツール使用中", &tracker.AISession{LastActivity: now.Add(-10 * time.Second)}, sessionStateRunning},
+		{"応答完了後", &tracker.AISession{LastActivity: now.Add(-2 * time.Minute), StoppedAt: &stopped}, sessionStateWaiting},
+		{"idle超過", &tracker.AISession{LastActivity: now.Add(-31 * time.Minute)}, sessionStateInactive},
+		{"応答完了後にidle超過", &tracker.AISession{LastActivity: now.Add(-time.Hour), StoppedAt: &stopped}, sessionStateInactive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sessionState(tt.session, now, 30*time.Minute); got != tt.want {
+				t.Errorf("sessionState() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplySessionActivity(t *testing.T) {
+	start := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	stopped := start.Add(time.Minute)
+	human := &tracker.CheckpointV2{Type: tracker.AuthorTypeHuman, Changes: map[string]tracker.Change{"a.go": {Added: 1, Deleted: 2}}}
+	ai := &tracker.CheckpointV2{Type: tracker.AuthorTypeAI, Changes: map[string]tracker.Change{"a.go": {Added: 10}, "b.go": {Added: 5, Deleted: 3}}}
+
+	// 停止中のセッションにツール使用があれば再開し、行数を加算する
+	session := &tracker.AISession{SessionID: "s1", StartedAt: start, StoppedAt: &stopped, ToolUses: 1, AIAdded: 4}
+	session = applySessionActivity(session, hookEventPreToolUse, &hookInput{SessionID: "s1", ToolName: "Write"}, human, start.Add(2*time.Minute))
+	session = applySessionActivity(session, hookEventPostToolUse, &hookInput{SessionID: "s1", ToolName: "Write"}, ai, start.Add(3*time.Minute))
+	if session.StoppedAt != nil || session.ToolUses != 2 || session.AIAdded != 19 || session.AIDeleted != 3 ||
+		session.HumanAdded != 1 || session.HumanDeleted != 2 || session.LastTool != "Write" || !session.StartedAt.Equal(start) {
+		t.Errorf("session = %+v, want resumed s1 with 2 tool uses, AI +19 -3, human +1 -2", session)
+	}
+
+	// セッションIDが変わった場合は数え直す
+	next := applySessionActivity(session, hookEventPostToolUse, &hookInput{SessionID: "s2"}, ai, start.Add(time.Hour))
+	if next.SessionID != "s2" || next.ToolUses != 1 || next.AIAdded != 15 || next.HumanAdded != 0 || !next.StartedAt.Equal(start.Add(time.Hour)) {
+		t.Errorf("new session = %+v, want s2 counted from zero", next)
+	}
+}
+
+func TestWeeklyAIPercentages(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.Local)
+	rollups := []*tracker.DailyRollup{
+		{Date: "2025-01-15", AIAdded: 30, HumanAdded: 10},
+		{Date: "2025-01-09", AIAdded: 30, HumanAdded: 30}, // 直近7日間の初日
+		{Date: "2025-01-08", AIAdded: 10, HumanAdded: 30}, // 前の7日間
+		{Date: "2025-01-02", AIAdded: 10, HumanAdded: 10}, // 前の7日間の初日
+		{Date: "2025-01-01", AIAdded: 100},                // 対象外
+	}
+
+	current, previous := weeklyAIPercentages(rollups, now)
+	if current == nil || *current != 60 {
+		t.Errorf("current = %v, want 60", current)
+	}
+	if previous == nil || *previous != 33.33333333333333 {
+		t.Errorf("previous = %v, want 33.3", previous)
+	}
+
+	if current, previous := weeklyAIPercentages(nil, now); current != nil || previous != nil {
+		t.Errorf("weeklyAIPercentages(nil) = %v, %v, want nil", current, previous)
+	}
+}
+
+func TestHandleStatus_Tracking(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	os.Args = []string{"aict", "status", "--format", "json"}
+	r, w, _ := os.Pipe()
+	origStdout := os.Stdout
+	os.Stdout = w
+	err := handleStatus()
+	w.Close()
+	os.Stdout = origStdout
+	if err != nil {
+		t.Fatalf("handleStatus() error = %v", err)
+	}
+
+	var status tracker.SessionStatus
+	if err := json.NewDecoder(r).Decode(&status); err != nil {
+		t.Fatalf("decoding status: %v", err)
+	}
+	tr := status.Tracking
+	if tr == nil {
+		t.Fatal("status.Tracking should be set")
+	}
+	if tr.Branch == "" || tr.AIPercentage != nil || tr.LastCheckpoint != nil {
+		t.Errorf("tracking = %+v, want a branch and no AI%% or checkpoint yet", tr)
+	}
+	if tr.HookProblems == 0 {
+		t.Error("hook problems should be reported before 'aict setup-hooks'")
+	}
+
+	os.Args = []string{"aict", "status", "--format", "json", "--fields", "state,tracking.target_ai_percentage"}
+	r, w, _ = os.Pipe()
+	os.Stdout = w
+	err = handleStatus()
+	w.Close()
+	os.Stdout = origStdout
+	if err != nil {
+		t.Fatalf("handleStatus(--fields) error = %v", err)
+	}
+	var filtered map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&filtered); err != nil {
+		t.Fatalf("decoding filtered status: %v", err)
+	}
+	if _, ok := filtered["pending_checkpoints"]; ok || filtered["state"] != sessionStateNone {
+		t.Errorf("filtered status = %v, want only state and tracking", filtered)
+	}
+	if tracking, _ := filtered["tracking"].(map[string]interface{}); len(tracking) != 1 || tracking["target_ai_percentage"] == nil {
+		t.Errorf("filtered tracking = %v, want only target_ai_percentage", filtered["tracking"])
+	}
+
+	os.Args = []string{"aict", "status", "--fields", "state"}
+	if err := handleStatus(); err == nil || !strings.Contains(err.Error(), "--fields requires --format json") {
+		t.Errorf("handleStatus(--fields without json) error = %v", err)
+	}
+}
@@ -3,10 +3,10 @@ package main
 import (
 	"fmt"
 	"os"
-
-	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
 )
 
+// handleSync handles the sync command
+// aict notes push・fetch の従来の名前です（リモートは origin 固定）。
 func handleSync() error {
 	if len(os.Args) < 3 {
 		fmt.Println("Usage: aict sync [push|fetch]")
@@ -28,27 +28,9 @@ func handleSync() error {
 }
 
 func handleSyncPush() error {
-	// refs/aict/authorship/* をリモートにpush
-	refspec := gitnotes.AuthorshipNotesRef + "/*:" + gitnotes.AuthorshipNotesRef + "/*"
-	executor := newExecutor()
-	_, err := executor.Run("push", "origin", refspec)
-	if err != nil {
-		return fmt.Errorf("pushing authorship logs: %w", err)
-	}
-
-	fmt.Println("✓ Authorship logs pushed to remote")
-	return nil
+	return notesPush(defaultNotesRemote)
 }
 
 func handleSyncFetch() error {
-	// リモートから refs/aict/authorship/* をfetch
-	refspec := gitnotes.AuthorshipNotesRef + "/*:" + gitnotes.AuthorshipNotesRef + "/*"
-	executor := newExecutor()
-	_, err := executor.Run("fetch", "origin", refspec)
-	if err != nil {
-		return fmt.Errorf("fetching authorship logs: %w", err)
-	}
-
-	fmt.Println("✓ Authorship logs fetched from remote")
-	return nil
+	return notesFetch(defaultNotesRemote)
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
 )
 
 func TestHandleSync_MissingSubcommand(t *testing.T) {
@@ -54,12 +55,13 @@ func TestHandleSyncPush(t *testing.T) {
 		t.Fatalf("handleSyncPush() error = %v", err)
 	}
 
+	// ローカルのnotes refを確認してからpush
 	calls := mock.GetCalls("Run")
-	if len(calls) != 1 {
-		t.Fatalf("expected 1 git call, got %d", len(calls))
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 git calls, got %d", len(calls))
 	}
-	if calls[0].Args[0] != "push" {
-		t.Errorf("expected 'push' command, got %q", calls[0].Args[0])
+	if calls[1].Args[0] != "push" || calls[1].Args[2] != gitnotes.AuthorshipPushRefspec {
+		t.Errorf("expected push of %s, got %v", gitnotes.AuthorshipPushRefspec, calls[1].Args)
 	}
 }
 
@@ -78,13 +80,17 @@ func TestHandleSyncFetch(t *testing.T) {
 		t.Fatalf("handleSyncFetch() error = %v", err)
 	}
 
+	// リモートのノートを取得してからローカルのノートに取り込む
 	calls := mock.GetCalls("Run")
-	if len(calls) != 1 {
-		t.Fatalf("expected 1 git call, got %d", len(calls))
+	if len(calls) != 3 {
+		t.Fatalf("expected 3 git calls, got %d", len(calls))
 	}
 	if calls[0].Args[0] != "fetch" {
 		t.Errorf("expected 'fetch' command, got %q", calls[0].Args[0])
 	}
+	if calls[2].Args[0] != "notes" || calls[2].Args[2] != "merge" {
+		t.Errorf("expected 'notes merge', got %v", calls[2].Args)
+	}
 }
 
 func TestHandleSyncPush_Error(t *testing.T) {
@@ -93,7 +99,10 @@ func TestHandleSyncPush_Error(t *testing.T) {
 
 	mock := gitexec.NewMockExecutor()
 	mock.RunFunc = func(args ...string) (string, error) {
-		return "", fmt.Errorf("remote not found")
+		if args[0] == "push" {
+			return "", fmt.Errorf("remote not found")
+		}
+		return "", nil
 	}
 	newExecutor = func() gitexec.Executor { return mock }
 
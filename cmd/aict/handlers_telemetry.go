@@ -0,0 +1,210 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/telemetry"
+)
+
+const telemetryUsage = "Usage: aict telemetry [on [--endpoint <url>]|off|status]"
+
+// エラー分類（エラーメッセージ自体はパスやブランチ名を含み得るため送らない）
+const (
+	errorClassDubiousOwnership = "dubious_ownership"
+	errorClassNotGitRepo       = "not_git_repo"
+	errorClassStorage          = "storage"
+	errorClassConfig           = "config"
+	errorClassGit              = "git"
+	errorClassUsage            = "usage"
+	errorClassOther            = "other"
+)
+
+// telemetryPath はテレメトリ設定のパスを返します（テストで差し替え可能）。
+var telemetryPath = telemetry.DefaultPath
+
+// telemetryNow は記録・送信に使う現在時刻です（テストで差し替え可能）。
+var telemetryNow = time.Now
+
+// handleTelemetry handles the telemetry command
+func handleTelemetry() error {
+	if len(os.Args) < 3 {
+		fmt.Println(telemetryUsage)
+		return fmt.Errorf("telemetry subcommand required")
+	}
+	path, err := telemetryPath()
+	if err != nil {
+		return err
+	}
+	state, err := telemetry.Load(path)
+	if err != nil {
+		return err
+	}
+
+	switch os.Args[2] {
+	case "on":
+		fs := flag.NewFlagSet("telemetry on", flag.ExitOnError)
+		endpoint := fs.String("endpoint", "", "URL to POST the aggregated counts to once a day")
+		fs.Parse(os.Args[3:])
+		if *endpoint != "" {
+			if u, err := url.Parse(*endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("endpoint must be an http(s) URL, got %q", *endpoint)
+			}
+			state.Endpoint = *endpoint
+		}
+		state.Enabled = true
+		if err := state.Save(path); err != nil {
+			return fmt.Errorf("saving %s: %w", path, err)
+		}
+		fmt.Println("✓ Telemetry enabled")
+		fmt.Println("  Collected: command names and error classes (counts only), aict version, OS and architecture")
+		fmt.Println("  Never collected: repository paths, file names, authors, commits, config or error messages")
+		if state.EffectiveEndpoint() == "" {
+			fmt.Println("  No endpoint configured: counts are kept locally (see 'aict telemetry status')")
+		}
+		return nil
+	case "off":
+		state.Enabled = false
+		state.Reset()
+		if err := state.Save(path); err != nil {
+			return fmt.Errorf("saving %s: %w", path, err)
+		}
+		fmt.Println("✓ Telemetry disabled (unsent counts deleted)")
+		return nil
+	case "status":
+		printTelemetryStatus(state, path)
+		return nil
+	default:
+		fmt.Printf("Unknown subcommand: %s\n", os.Args[2])
+		fmt.Println(telemetryUsage)
+		return fmt.Errorf("unknown subcommand: %s", os.Args[2])
+	}
+}
+
+// printTelemetryStatus はテレメトリの設定と未送信の集計を表示します。
+func printTelemetryStatus(state *telemetry.State, path string) {
+	status := "off"
+	if state.Enabled {
+		status = "on"
+	}
+	if telemetry.DoNotTrack() {
+		status += " (disabled by " + telemetry.DoNotTrackEnv + ")"
+	}
+	fmt.Printf("Telemetry: %s\n", status)
+	fmt.Printf("Settings:  %s\n", path)
+	endpoint := state.EffectiveEndpoint()
+	if endpoint == "" {
+		endpoint = "(none; counts are kept locally)"
+	}
+	fmt.Printf("Endpoint:  %s\n", endpoint)
+	if !state.LastSent.IsZero() {
+		fmt.Printf("Last sent: %s\n", state.LastSent.Local().Format("2006-01-02 15:04"))
+	}
+	if len(state.Commands) == 0 {
+		fmt.Println("No unsent counts")
+		return
+	}
+	fmt.Printf("Unsent counts since %s:\n", state.Since.Local().Format("2006-01-02 15:04"))
+	printTelemetryCounts("Commands", state.Commands)
+	printTelemetryCounts("Errors", state.Errors)
+}
+
+// printTelemetryCounts は回数の多い順に表示します。
+func printTelemetryCounts(title string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	fmt.Printf("  %s:\n", title)
+	for _, k := range keys {
+		fmt.Printf("    %-20s %d\n", k, counts[k])
+	}
+}
+
+// recordTelemetry は有効な場合にコマンドの実行を記録し、送信の時期であれば集計を送信します。
+// テレメトリの失敗でコマンドの結果を変えないよう、エラーはデバッグ出力のみです。
+func recordTelemetry(command string, cmdErr error) {
+	// シェル補完の候補取得はキー入力ごとに、hook はツールの使用ごとに呼ばれるため数えない
+	// （hook で送信するとClaude Codeのツールの実行を最大で送信のタイムアウト分待たせる）
+	if command == "__complete" || command == "hook" || telemetry.DoNotTrack() {
+		return
+	}
+	path, err := telemetryPath()
+	if err != nil {
+		return
+	}
+	state, err := telemetry.Load(path)
+	if err != nil {
+		debugf("telemetry: %v", err)
+		return
+	}
+	if !state.Enabled {
+		return
+	}
+
+	now := telemetryNow()
+	state.Record(telemetryCommand(command), classifyError(cmdErr), now)
+	if state.Due(now) {
+		if err := state.Send(version, now); err != nil {
+			debugf("telemetry: sending counts: %v", err)
+		}
+	}
+	if err := state.Save(path); err != nil {
+		debugf("telemetry: %v", err)
+	}
+}
+
+// telemetryCommand は記録するコマンド名を返します。既知のコマンド以外（タイプミス等）は入力をそのまま送らず "unknown" とします。
+func telemetryCommand(command string) string {
+	switch command {
+	case "--version", "-v":
+		return "version"
+	case "--help", "-h":
+		return "help"
+	}
+	for _, c := range completionCommands {
+		if c == command {
+			return c
+		}
+	}
+	return "unknown"
+}
+
+// classifyError はエラーを固定の分類に変換します（エラーがない場合は空文字列）。
+func classifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+	msg := err.Error()
+	switch {
+	case gitexec.IsDubiousOwnership(err):
+		return errorClassDubiousOwnership
+	case strings.Contains(msg, "not a git repository") || strings.Contains(msg, "not in a git repository"):
+		return errorClassNotGitRepo
+	case strings.Contains(msg, "initializing storage"):
+		return errorClassStorage
+	case strings.Contains(msg, "config"):
+		return errorClassConfig
+	case strings.Contains(msg, "git ") && strings.Contains(msg, " failed"):
+		return errorClassGit
+	case strings.Contains(msg, "required") || strings.Contains(msg, "unknown") || strings.Contains(msg, "invalid"):
+		return errorClassUsage
+	default:
+		return errorClassOther
+	}
+}
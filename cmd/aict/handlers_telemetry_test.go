@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/telemetry"
+)
+
+// useTempTelemetry はテレメトリ設定の保存先を一時ディレクトリに差し替えます。
+func useTempTelemetry(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "telemetry.json")
+	origPath := telemetryPath
+	telemetryPath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { telemetryPath = origPath })
+	t.Setenv(telemetry.DoNotTrackEnv, "")
+	t.Setenv(telemetry.EndpointEnv, "")
+	return path
+}
+
+func TestHandleTelemetry(t *testing.T) {
+	path := useTempTelemetry(t)
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	run := func(args ...string) (string, error) {
+		os.Args = append([]string{"aict", "telemetry"}, args...)
+		origStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := handleTelemetry()
+
+		w.Close()
+		os.Stdout = origStdout
+		var buf bytes.Buffer
+		buf.ReadFrom(r)
+		return buf.String(), err
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		before      func()
+		wantErr     bool
+		wantText    []string
+		wantEnabled bool
+	}{
+		{"status before opting in", []string{"status"}, nil, false, []string{"Telemetry: off", "Endpoint:  (none; counts are kept locally)"}, false},
+		{"on without endpoint", []string{"on"}, nil, false, []string{"✓ Telemetry enabled", "Never collected", "counts are kept locally"}, true},
+		{"status with counts", []string{"status"}, func() {
+			recordTelemetry("report", nil)
+			recordTelemetry("report", fmt.Errorf("loading config: bad"))
+			recordTelemetry("reprot", nil)
+		}, false, []string{"Telemetry: on", "report               2", "unknown              1", "config               1"}, true},
+		{"on with invalid endpoint", []string{"on", "--endpoint", "ftp://example.com"}, nil, true, nil, true},
+		{"on with endpoint", []string{"on", "--endpoint", "https://example.com/collect"}, nil, false, []string{"✓ Telemetry enabled"}, true},
+		{"off deletes counts", []string{"off"}, nil, false, []string{"✓ Telemetry disabled"}, false},
+		{"status after opting out", []string{"status"}, nil, false, []string{"Telemetry: off", "No unsent counts", "https://example.com/collect"}, false},
+		{"unknown subcommand", []string{"maybe"}, nil, true, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.before != nil {
+				tt.before()
+			}
+			output, err := run(tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handleTelemetry() error = %v, wantErr %v\n%s", err, tt.wantErr, output)
+			}
+			for _, want := range tt.wantText {
+				if !strings.Contains(output, want) {
+					t.Errorf("output missing %q:\n%s", want, output)
+				}
+			}
+			state, _ := telemetry.Load(path)
+			if state.Enabled != tt.wantEnabled {
+				t.Errorf("Enabled = %v, want %v", state.Enabled, tt.wantEnabled)
+			}
+		})
+	}
+}
+
+func TestRecordTelemetry(t *testing.T) {
+	path := useTempTelemetry(t)
+
+	var payloads []telemetry.Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p telemetry.Payload
+		json.NewDecoder(r.Body).Decode(&p)
+		payloads = append(payloads, p)
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	origNow := telemetryNow
+	telemetryNow = func() time.Time { return now }
+	defer func() { telemetryNow = origNow }()
+
+	// 無効の間は何も記録しない
+	recordTelemetry("report", nil)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("telemetry file should not be created while disabled: %v", err)
+	}
+
+	if err := (&telemetry.State{Enabled: true, Endpoint: server.URL, LastSent: now.Add(-time.Hour)}).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	recordTelemetry("report", nil)
+	recordTelemetry("__complete", nil)
+	recordTelemetry("hook", nil)
+	if len(payloads) != 0 {
+		t.Fatalf("sent %d payloads before the interval elapsed", len(payloads))
+	}
+
+	now = now.Add(telemetry.SendInterval)
+	recordTelemetry("commit", errors.New("git rev-parse HEAD failed: exit status 128"))
+	if len(payloads) != 1 {
+		t.Fatalf("sent %d payloads, want 1", len(payloads))
+	}
+	p := payloads[0]
+	if p.Commands["report"] != 1 || p.Commands["commit"] != 1 || p.Commands["__complete"] != 0 || p.Commands["hook"] != 0 || p.Errors[errorClassGit] != 1 {
+		t.Errorf("payload = %+v", p)
+	}
+
+	state, _ := telemetry.Load(path)
+	if len(state.Commands) != 0 || !state.LastSent.Equal(now) {
+		t.Errorf("state after sending = %+v", state)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{errors.New("git status failed: exit status 128\nstderr: fatal: detected dubious ownership in repository at '/repo'"), errorClassDubiousOwnership},
+		{errors.New("not in a git repository"), errorClassNotGitRepo},
+		{errors.New("initializing storage: permission denied"), errorClassStorage},
+		{errors.New("loading config: invalid character"), errorClassConfig},
+		{errors.New("git log -1 failed: exit status 128"), errorClassGit},
+		{errors.New("sync subcommand required"), errorClassUsage},
+		{errors.New("something else"), errorClassOther},
+	}
+	for _, tt := range tests {
+		name := "nil"
+		if tt.err != nil {
+			name = tt.err.Error()
+		}
+		t.Run(name, func(t *testing.T) {
+			if got := classifyError(tt.err); got != tt.want {
+				t.Errorf("classifyError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
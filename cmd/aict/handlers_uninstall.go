@@ -0,0 +1,312 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+)
+
+const (
+	// hookBackupSuffix は setup-hooks が既存のgit hookを退避する際の拡張子
+	hookBackupSuffix = ".aict-backup"
+	// aictHookMarker はaictが生成したgit hookを識別するコメント
+	aictHookMarker = "# AI Code Tracker"
+	// legacyTrackingDirName は旧バージョンの作業ディレクトリ
+	legacyTrackingDirName = ".ai_code_tracking"
+)
+
+// handleUninstall handles the uninstall command
+func handleUninstall() error {
+	fs := flag.NewFlagSet("uninstall", flag.ExitOnError)
+	purge := fs.Bool("purge", false, "Also delete tracking data (.git/aict and legacy .ai_code_tracking)")
+	fs.Parse(os.Args[2:])
+
+	executor := newExecutor()
+	repoRoot, err := executor.Run("rev-parse", "--show-toplevel")
+	if err != nil {
+		return fmt.Errorf("failed to get repository root (are you in a git repo?): %w", err)
+	}
+	gitDir, err := getGitCommonDir()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Uninstalling AI Code Tracker hooks...")
+
+	// core.hooksPath を設定する前に .git/hooks/ に設置したhookも削除する
+	hooksDirs := []string{filepath.Join(gitDir, "hooks")}
+	if dir := gitHooksDir(repoRoot, gitDir); dir != hooksDirs[0] {
+		hooksDirs = append(hooksDirs, dir)
+	}
+	for _, dir := range hooksDirs {
+		for _, name := range []string{"pre-commit", "post-commit"} {
+			if err := removeGitHook(filepath.Join(dir, name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch manager, path := detectHookManager(repoRoot); manager {
+	case hookManagerHusky:
+		if err := removeHuskyPostCommit(path); err != nil {
+			return err
+		}
+	case hookManagerLefthook:
+		if err := removeLefthookPostCommit(path); err != nil {
+			return err
+		}
+	}
+
+	for _, settingsPath := range claudeSettingsPaths(repoRoot) {
+		if err := removeClaudeSettings(settingsPath); err != nil {
+			return err
+		}
+	}
+
+	if removeNotesRefspecs(executor, defaultNotesRemote) {
+		fmt.Printf("✓ Removed authorship log refspecs from remote.%s\n", defaultNotesRemote)
+	}
+	if removeNotesRewrite(executor) {
+		fmt.Println("✓ Removed notes.rewriteRef for authorship logs")
+	}
+
+	aictDir := filepath.Join(gitDir, storage.AictDirName)
+	if *purge {
+		for _, dir := range []string{aictDir, filepath.Join(repoRoot, legacyTrackingDirName)} {
+			if _, err := os.Stat(dir); err != nil {
+				continue
+			}
+			if err := os.RemoveAll(dir); err != nil {
+				return fmt.Errorf("removing %s: %w", dir, err)
+			}
+			fmt.Printf("✓ Removed %s\n", dir)
+		}
+	} else {
+		// 以前のバージョンが設置したClaude Code hookスクリプト
+		hooksDir := filepath.Join(aictDir, "hooks")
+		if _, err := os.Stat(hooksDir); err == nil {
+			if err := os.RemoveAll(hooksDir); err != nil {
+				return fmt.Errorf("removing %s: %w", hooksDir, err)
+			}
+			fmt.Printf("✓ Removed %s\n", hooksDir)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("✓ AI Code Tracker uninstalled")
+	if !*purge {
+		fmt.Println("  Tracking data in .git/aict was kept (use --purge to delete it)")
+	}
+	fmt.Println("  Authorship logs in Git notes were kept (use 'aict debug clear-notes' to delete them)")
+	return nil
+}
+
+// isAICTHookFile はhookファイルがaictの生成したものかどうかを判定します。
+func isAICTHookFile(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(data), aictHookMarker)
+}
+
+// removeGitHook はaictのgit hookを削除し、退避済みのhookがあれば復元します。
+// ユーザー独自のhookに 'aict' の呼び出し行が追記されている場合は、その行のみ除去します。
+func removeGitHook(hookPath string) error {
+	backupPath := hookPath + hookBackupSuffix
+
+	data, err := os.ReadFile(hookPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", hookPath, err)
+	}
+
+	switch {
+	case err != nil:
+		// hookなし: 退避分のみ復元
+	case strings.Contains(string(data), aictHookMarker):
+		if err := os.Remove(hookPath); err != nil {
+			return fmt.Errorf("removing %s: %w", hookPath, err)
+		}
+		fmt.Printf("✓ Removed %s\n", hookPath)
+	default:
+		stripped, changed := stripAICTLines(string(data))
+		if changed {
+			if err := os.WriteFile(hookPath, []byte(stripped), 0755); err != nil {
+				return fmt.Errorf("updating %s: %w", hookPath, err)
+			}
+			fmt.Printf("✓ Removed aict commands from %s\n", hookPath)
+		}
+		return nil // ユーザーのhookが残っているため退避分は復元しない
+	}
+
+	if _, err := os.Stat(backupPath); err == nil {
+		if err := os.Rename(backupPath, hookPath); err != nil {
+			return fmt.Errorf("restoring %s: %w", backupPath, err)
+		}
+		fmt.Printf("✓ Restored %s\n", hookPath)
+	}
+	return nil
+}
+
+// stripAICTLines はaictを呼び出す行を除去します。
+func stripAICTLines(content string) (string, bool) {
+	lines := strings.Split(content, "\n")
+	kept := lines[:0]
+	changed := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "aict ") || strings.Contains(trimmed, "/aict commit") {
+			changed = true
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n"), changed
+}
+
+// removeClaudeSettings はClaude Code設定（settings.json・settings.local.json）からaictのhookエントリを除去します。
+// 他の設定が残らない場合はファイル自体を削除します。
+func removeClaudeSettings(settingsPath string) error {
+	data, err := os.ReadFile(settingsPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", settingsPath, err)
+	}
+
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("parsing %s: %w", settingsPath, err)
+	}
+
+	if !stripAICTSettings(settings) {
+		return nil
+	}
+
+	if len(settings) == 0 {
+		if err := os.Remove(settingsPath); err != nil {
+			return fmt.Errorf("removing %s: %w", settingsPath, err)
+		}
+		fmt.Printf("✓ Removed %s\n", settingsPath)
+		return nil
+	}
+
+	out, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(settingsPath, append(out, '\n'), 0644); err != nil {
+		return fmt.Errorf("updating %s: %w", settingsPath, err)
+	}
+	fmt.Printf("✓ Removed aict hooks from %s\n", settingsPath)
+	return nil
+}
+
+// stripAICTSettings はsettingsのhooksからaictのhookスクリプトを呼ぶエントリを除去します。
+// ユーザーのコマンドと同じエントリにあるaictのコマンドはそのコマンドのみ除去します。
+// 空になったイベント・hooksキーも削除します。変更があった場合は true を返します。
+func stripAICTSettings(settings map[string]interface{}) bool {
+	hooks, ok := settings["hooks"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	changed := false
+	for event, value := range hooks {
+		matchers := hookMatchers(value)
+		if matchers == nil {
+			continue
+		}
+
+		var kept []interface{}
+		for _, m := range matchers {
+			if isAICTMatcher(m) {
+				changed = true
+				continue
+			}
+			if stripAICTCommands(m) {
+				changed = true
+			}
+			kept = append(kept, m)
+		}
+
+		switch {
+		case len(kept) == 0:
+			delete(hooks, event)
+		case len(kept) == len(matchers):
+			// matcherの除去なし（オブジェクト形式もそのまま残す）
+		default:
+			hooks[event] = kept
+		}
+	}
+
+	if len(hooks) == 0 {
+		delete(settings, "hooks")
+	}
+	return changed
+}
+
+// stripAICTCommands はmatcherエントリのhookからaictのコマンドを除去し、除去した場合は true を返します。
+func stripAICTCommands(m interface{}) bool {
+	entry, ok := m.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	hooks, ok := entry["hooks"].([]interface{})
+	if !ok {
+		return false
+	}
+	var kept []interface{}
+	for _, h := range hooks {
+		if hook, ok := h.(map[string]interface{}); ok {
+			if command, _ := hook["command"].(string); isAICTHookCommand(command) {
+				continue
+			}
+		}
+		kept = append(kept, h)
+	}
+	if len(kept) == len(hooks) {
+		return false
+	}
+	entry["hooks"] = kept
+	return true
+}
+
+// isAICTMatcher はmatcherエントリのhookがすべてaictのスクリプト呼び出しかを判定します。
+func isAICTMatcher(m interface{}) bool {
+	entry, ok := m.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	hooks, ok := entry["hooks"].([]interface{})
+	if !ok || len(hooks) == 0 {
+		return false
+	}
+	for _, h := range hooks {
+		hook, ok := h.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		command, _ := hook["command"].(string)
+		if !isAICTHookCommand(command) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAICTHookCommand はClaude Codeのhookコマンドがaictのものかを判定します。
+// 'aict hook <event>'（PATHにない場合に bin/aict へフォールバックする形式を含む）に加え、
+// 以前のバージョンが設置した .git/aict/hooks/ のスクリプト呼び出しも対象とします。
+func isAICTHookCommand(command string) bool {
+	command = strings.TrimSpace(command)
+	return strings.HasPrefix(command, "aict hook ") ||
+		(strings.HasPrefix(command, "if command -v aict ") && strings.Contains(command, "aict hook ")) ||
+		strings.Contains(command, ".git/aict/hooks/")
+}
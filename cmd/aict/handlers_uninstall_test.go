@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/templates"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+// setupUninstallTest はhook設置済みのリポジトリを作成します。
+func setupUninstallTest(t *testing.T) (repoRoot, gitDir string) {
+	t.Helper()
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(originalDir) })
+	os.Chdir(tmpDir)
+
+	origArgs := os.Args
+	t.Cleanup(func() { os.Args = origArgs })
+
+	gitDir = filepath.Join(tmpDir, ".git")
+	// 以前のバージョンが設置したhookスクリプトのディレクトリ
+	os.MkdirAll(filepath.Join(gitDir, "aict", "hooks"), 0755)
+	if err := setupPostCommitHook(filepath.Join(gitDir, "hooks")); err != nil {
+		t.Fatalf("setupPostCommitHook() error = %v", err)
+	}
+	if err := setupClaudeSettings(resolveClaudeSettingsPath(tmpDir, claudeSettingsProject)); err != nil {
+		t.Fatalf("setupClaudeSettings() error = %v", err)
+	}
+	return tmpDir, gitDir
+}
+
+func TestHandleUninstall(t *testing.T) {
+	repoRoot, gitDir := setupUninstallTest(t)
+	os.Args = []string{"aict", "uninstall"}
+
+	if err := handleUninstall(); err != nil {
+		t.Fatalf("handleUninstall() error = %v", err)
+	}
+
+	for _, path := range []string{
+		filepath.Join(gitDir, "hooks", "post-commit"),
+		filepath.Join(gitDir, "aict", "hooks"),
+		filepath.Join(repoRoot, ".claude", "settings.json"),
+	} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed", path)
+		}
+	}
+
+	// トラッキングデータは残る
+	testutil.AssertFileExists(t, filepath.Join(gitDir, "aict", "config.json"))
+}
+
+func TestHandleUninstall_Purge(t *testing.T) {
+	repoRoot, gitDir := setupUninstallTest(t)
+	os.MkdirAll(filepath.Join(repoRoot, legacyTrackingDirName), 0755)
+	os.Args = []string{"aict", "uninstall", "--purge"}
+
+	if err := handleUninstall(); err != nil {
+		t.Fatalf("handleUninstall() error = %v", err)
+	}
+
+	for _, path := range []string{filepath.Join(gitDir, "aict"), filepath.Join(repoRoot, legacyTrackingDirName)} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed with --purge", path)
+		}
+	}
+}
+
+func TestRemoveGitHook_RestoresBackup(t *testing.T) {
+	hooksDir := t.TempDir()
+	hookPath := filepath.Join(hooksDir, "post-commit")
+	userHook := "#!/bin/sh\necho user hook\n"
+
+	os.WriteFile(hookPath, []byte(templates.PostCommitHook), 0755)
+	os.WriteFile(hookPath+hookBackupSuffix, []byte(userHook), 0755)
+
+	if err := removeGitHook(hookPath); err != nil {
+		t.Fatalf("removeGitHook() error = %v", err)
+	}
+
+	content, err := os.ReadFile(hookPath)
+	if err != nil {
+		t.Fatalf("hook should be restored: %v", err)
+	}
+	if string(content) != userHook {
+		t.Errorf("restored hook = %q, want %q", content, userHook)
+	}
+	if _, err := os.Stat(hookPath + hookBackupSuffix); !os.IsNotExist(err) {
+		t.Error("backup file should be consumed")
+	}
+}
+
+func TestRemoveGitHook_StripsAppendedLines(t *testing.T) {
+	hookPath := filepath.Join(t.TempDir(), "post-commit")
+	os.WriteFile(hookPath, []byte("#!/bin/sh\nmake lint\naict commit\n"), 0755)
+
+	if err := removeGitHook(hookPath); err != nil {
+		t.Fatalf("removeGitHook() error = %v", err)
+	}
+
+	content, _ := os.ReadFile(hookPath)
+	if string(content) != "#!/bin/sh\nmake lint\n" {
+		t.Errorf("hook content = %q", content)
+	}
+}
+
+func TestRemoveClaudeSettings_KeepsOtherEntries(t *testing.T) {
+	settingsPath := filepath.Join(t.TempDir(), "settings.json")
+
+	var settings map[string]interface{}
+	json.Unmarshal([]byte(templates.ClaudeSettingsJSON), &settings)
+	settings["permissions"] = map[string]interface{}{"allow": []interface{}{"Bash(go test:*)"}}
+	hooks := settings["hooks"].(map[string]interface{})
+	hooks["PreToolUse"] = append(hooks["PreToolUse"].([]interface{}), map[string]interface{}{
+		"matcher": "Bash",
+		"hooks":   []interface{}{map[string]interface{}{"type": "command", "command": "./lint.sh"}},
+	})
+	data, _ := json.Marshal(settings)
+	os.WriteFile(settingsPath, data, 0644)
+
+	if err := removeClaudeSettings(settingsPath); err != nil {
+		t.Fatalf("removeClaudeSettings() error = %v", err)
+	}
+
+	var got map[string]interface{}
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatalf("settings.json should remain: %v", err)
+	}
+	json.Unmarshal(data, &got)
+
+	if _, ok := got["permissions"]; !ok {
+		t.Error("permissions should be kept")
+	}
+	gotHooks := got["hooks"].(map[string]interface{})
+	if _, ok := gotHooks["PostToolUse"]; ok {
+		t.Error("PostToolUse with only aict hooks should be removed")
+	}
+	if pre := gotHooks["PreToolUse"].([]interface{}); len(pre) != 1 {
+		t.Errorf("PreToolUse should keep only the user matcher, got %d entries", len(pre))
+	}
+}
+
+func TestSetupPostCommitHook_BacksUpUserHook(t *testing.T) {
+	gitDir := t.TempDir()
+	hookPath := filepath.Join(gitDir, "hooks", "post-commit")
+	os.MkdirAll(filepath.Dir(hookPath), 0755)
+	os.WriteFile(hookPath, []byte("#!/bin/sh\necho user hook\n"), 0755)
+
+	// 上書き確認に "y" で応答
+	r, w, _ := os.Pipe()
+	w.WriteString("y\n")
+	w.Close()
+	origStdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = origStdin }()
+
+	if err := setupPostCommitHook(filepath.Join(gitDir, "hooks")); err != nil {
+		t.Fatalf("setupPostCommitHook() error = %v", err)
+	}
+
+	testutil.AssertFileExists(t, hookPath+hookBackupSuffix)
+	if !isAICTHookFile(hookPath) {
+		t.Error("post-commit should be replaced with the aict hook")
+	}
+}
+
+func TestIsAICTHookCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{"aict hook pre-tool-use", true},
+		{`if command -v aict >/dev/null 2>&1; then aict hook stop; elif [ -f "$CLAUDE_PROJECT_DIR/bin/aict" ]; then "$CLAUDE_PROJECT_DIR/bin/aict" hook stop; fi || true`, true},
+		{`test -x "$CLAUDE_PROJECT_DIR/.git/aict/hooks/pre-tool-use.sh" && "$CLAUDE_PROJECT_DIR/.git/aict/hooks/pre-tool-use.sh" || true`, true},
+		{"./lint.sh", false},
+		{"echo aict hook", false},
+	}
+	for _, tt := range tests {
+		if got := isAICTHookCommand(tt.command); got != tt.want {
+			t.Errorf("isAICTHookCommand(%q) = %v, want %v", tt.command, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/provenance"
+)
+
+const (
+	// verifyBinaryFetchTimeout は --checksums・--signature にURLを指定した場合の取得のタイムアウト
+	verifyBinaryFetchTimeout = 30 * time.Second
+	// maxVerifyBinaryFetchSize は取得するチェックサム一覧・署名の上限
+	maxVerifyBinaryFetchSize = 1 << 20
+)
+
+// verifyBinaryResult は aict verify-binary の結果（--format json）
+type verifyBinaryResult struct {
+	Binary          string           `json:"binary"`
+	SHA256          string           `json:"sha256"`
+	Artifact        string           `json:"artifact,omitempty"` // 一致したチェックサム一覧のファイル名
+	Matched         bool             `json:"matched"`
+	SignatureValid  *bool            `json:"signature_valid,omitempty"` // --signature 未指定時は省略
+	Provenance      *provenance.Info `json:"provenance,omitempty"`      // 検証したのが実行中のバイナリの場合のみ
+	ProvenanceNotes []string         `json:"provenance_notes,omitempty"`
+}
+
+// handleVerifyBinary handles the verify-binary command
+// 実行中（または --binary で指定した）バイナリのSHA-256を、リリースで公開されたチェックサム一覧と照合します。
+// --signature・--public-key を指定した場合は、チェックサム一覧のEd25519署名も検証します。
+func handleVerifyBinary() error {
+	fs := flag.NewFlagSet("verify-binary", flag.ExitOnError)
+	checksums := fs.String("checksums", "", "Published SHA256SUMS file or URL (required)")
+	binary := fs.String("binary", "", "Binary to verify (default: the running aict)")
+	name := fs.String("name", "", "Require the checksum of this artifact name (default: any entry)")
+	signature := fs.String("signature", "", "Ed25519 signature of the checksums file (file or URL)")
+	publicKey := fs.String("public-key", "", "Ed25519 public key (PEM or base64) to verify --signature")
+	format := fs.String("format", "text", "Output format: text or json")
+	fields := fs.String("fields", "", fieldsFlagUsage)
+	fs.Parse(os.Args[2:])
+
+	if *checksums == "" {
+		return fmt.Errorf("--checksums is required (e.g., the release's SHA256SUMS)")
+	}
+	if (*signature == "") != (*publicKey == "") {
+		return fmt.Errorf("--signature and --public-key must be used together")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format: %s (available: text, json)", *format)
+	}
+	if *fields != "" && *format != "json" {
+		return fmt.Errorf("--fields requires --format json")
+	}
+
+	result := &verifyBinaryResult{Binary: *binary}
+	if result.Binary == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("locating the running binary: %w", err)
+		}
+		if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+			exe = resolved
+		}
+		result.Binary = exe
+		if info, err := provenance.Current(); err == nil {
+			result.Provenance = info
+			result.ProvenanceNotes = provenanceNotes(info)
+		}
+	}
+
+	sum, err := provenance.FileSHA256(result.Binary)
+	if err != nil {
+		return fmt.Errorf("hashing %s: %w", result.Binary, err)
+	}
+	result.SHA256 = sum
+
+	sumsData, err := readVerifySource(*checksums)
+	if err != nil {
+		return fmt.Errorf("reading checksums: %w", err)
+	}
+	if *signature != "" {
+		sigErr := verifyChecksumsSignature(sumsData, *signature, *publicKey)
+		valid := sigErr == nil
+		result.SignatureValid = &valid
+		if sigErr != nil {
+			if err := printVerifyBinaryResult(result, *format, *fields); err != nil {
+				return err
+			}
+			return fmt.Errorf("checksums signature verification failed: %w", sigErr)
+		}
+	}
+	sums, err := provenance.ParseChecksums(sumsData)
+	if err != nil {
+		return fmt.Errorf("parsing checksums: %w", err)
+	}
+	result.Artifact, result.Matched = matchChecksum(sums, sum, *name)
+
+	if err := printVerifyBinaryResult(result, *format, *fields); err != nil {
+		return err
+	}
+	if !result.Matched {
+		if *name != "" {
+			return fmt.Errorf("binary does not match the published checksum of %s", *name)
+		}
+		return fmt.Errorf("binary does not match any published checksum")
+	}
+	return nil
+}
+
+// matchChecksum はチェックサム一覧から sum に一致するファイル名を返します。
+// name を指定した場合はそのファイルのチェックサムとのみ比較します。
+func matchChecksum(sums map[string]string, sum, name string) (string, bool) {
+	if name != "" {
+		return name, sums[name] == sum
+	}
+	names := make([]string, 0, len(sums))
+	for n := range sums {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if sums[n] == sum {
+			return n, true
+		}
+	}
+	return "", false
+}
+
+func verifyChecksumsSignature(data []byte, signature, publicKey string) error {
+	keyData, err := os.ReadFile(publicKey)
+	if err != nil {
+		return err
+	}
+	key, err := provenance.ParsePublicKey(keyData)
+	if err != nil {
+		return err
+	}
+	sig, err := readVerifySource(signature)
+	if err != nil {
+		return fmt.Errorf("reading signature: %w", err)
+	}
+	return provenance.VerifySignature(key, data, sig)
+}
+
+// provenanceNotes はリリースと同じ再現可能なビルドではない可能性を示すビルド情報の注意点です。
+func provenanceNotes(info *provenance.Info) []string {
+	var notes []string
+	if info.Commit == "" {
+		notes = append(notes, "built without VCS information (not from a release build)")
+	}
+	if info.Modified {
+		notes = append(notes, "built from a working tree with uncommitted changes")
+	}
+	if !info.Trimpath {
+		notes = append(notes, "built without -trimpath (release builds use 'make release')")
+	}
+	return notes
+}
+
+// readVerifySource はファイル、または http(s) のURLから内容を読み込みます。
+func readVerifySource(src string) ([]byte, error) {
+	if !strings.HasPrefix(src, "https://") && !strings.HasPrefix(src, "http://") {
+		return os.ReadFile(src)
+	}
+	client := &http.Client{Timeout: verifyBinaryFetchTimeout}
+	resp, err := client.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", src, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxVerifyBinaryFetchSize))
+}
+
+// printVerifyBinaryResult は検証結果を表示します。json の場合は fields（--fields）で出力するフィールドを絞り込みます。
+func printVerifyBinaryResult(result *verifyBinaryResult, format, fields string) error {
+	if format == "json" {
+		data, err := marshalJSONFields(result, fields)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Binary:  %s\n", result.Binary)
+	fmt.Printf("SHA-256: %s\n", result.SHA256)
+	if p := result.Provenance; p != nil {
+		commit := p.Commit
+		if commit == "" {
+			commit = "(unknown)"
+		} else if p.Modified {
+			commit += " (modified)"
+		}
+		fmt.Printf("Built:   %s from %s with %s\n", p.Target, commit, p.GoVersion)
+		fmt.Printf("Modules: sha256 %s\n", p.ModulesHash)
+	}
+	if result.SignatureValid != nil {
+		if *result.SignatureValid {
+			fmt.Println("✓ Checksums signature is valid")
+		} else {
+			fmt.Println("✗ Checksums signature is invalid")
+			return nil
+		}
+	}
+	if result.Matched {
+		fmt.Printf("✓ Matches the published checksum of %s\n", result.Artifact)
+	} else {
+		fmt.Println("✗ Does not match the published checksums")
+		for _, note := range result.ProvenanceNotes {
+			fmt.Printf("  Note: %s\n", note)
+		}
+	}
+	return nil
+}
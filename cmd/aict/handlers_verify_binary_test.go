@@ -0,0 +1,61 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHandleVerifyBinary(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "aict-linux-amd64")
+	os.WriteFile(binary, []byte("release build"), 0755)
+	sum := sha256.Sum256([]byte("release build"))
+	other := sha256.Sum256([]byte("other"))
+	sums := hex.EncodeToString(other[:]) + "  aict-darwin-arm64\n" + hex.EncodeToString(sum[:]) + "  aict-linux-amd64\n"
+	sumsFile := filepath.Join(dir, "SHA256SUMS")
+	os.WriteFile(sumsFile, []byte(sums), 0644)
+
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	keyFile := filepath.Join(dir, "aict.pub")
+	os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(pub)), 0644)
+	sigFile := filepath.Join(dir, "SHA256SUMS.sig")
+	os.WriteFile(sigFile, ed25519.Sign(priv, []byte(sums)), 0644)
+	badSigFile := filepath.Join(dir, "bad.sig")
+	os.WriteFile(badSigFile, ed25519.Sign(priv, []byte("tampered")), 0644)
+
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{"一致", []string{"--binary", binary, "--checksums", sumsFile}, false},
+		{"名前を指定して一致", []string{"--binary", binary, "--checksums", sumsFile, "--name", "aict-linux-amd64"}, false},
+		{"別の成果物の名前", []string{"--binary", binary, "--checksums", sumsFile, "--name", "aict-darwin-arm64"}, true},
+		{"一致しない", []string{"--binary", sumsFile, "--checksums", sumsFile}, true},
+		{"署名が正しい", []string{"--binary", binary, "--checksums", sumsFile, "--signature", sigFile, "--public-key", keyFile}, false},
+		{"署名が一致しない", []string{"--binary", binary, "--checksums", sumsFile, "--signature", badSigFile, "--public-key", keyFile}, true},
+		{"公開鍵なしの署名", []string{"--binary", binary, "--checksums", sumsFile, "--signature", sigFile}, true},
+		{"チェックサム一覧なし", []string{"--binary", binary}, true},
+		{"フィールドを絞り込み", []string{"--binary", binary, "--checksums", sumsFile, "--fields", "sha256,matched"}, false},
+		{"存在しないフィールド", []string{"--binary", binary, "--checksums", sumsFile, "--fields", "checksum"}, true},
+		{"--fields に json 以外の形式", []string{"--binary", binary, "--checksums", sumsFile, "--format", "text", "--fields", "sha256"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"aict", "verify-binary", "--format", "json"}, tt.args...)
+			err := handleVerifyBinary()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("handleVerifyBinary() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
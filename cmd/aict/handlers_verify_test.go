@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/git"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestRunVerifyPipeline(t *testing.T) {
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+
+	// main() と同じくキャッシュ付きExecutorで実行
+	origExecutor := newExecutor
+	defer func() { newExecutor = origExecutor }()
+	cached := git.NewCachingExecutor(gitexec.NewExecutor())
+	newExecutor = func() gitexec.Executor { return cached }
+
+	// default_author は省略可能な設定のため、空でも検証できること
+	for _, defaultAuthor := range []string{"Test User", ""} {
+		cfg := &tracker.Config{
+			TrackedExtensions: []string{".go"},
+			ExcludePatterns:   []string{"*_test.go"},
+			DefaultAuthor:     defaultAuthor,
+			AIAgents:          []string{"Claude Code"},
+			AuthorMappings:    map[string]string{},
+		}
+
+		if err := runVerifyPipeline(cfg); err != nil {
+			t.Fatalf("runVerifyPipeline(default_author=%q) error = %v", defaultAuthor, err)
+		}
+
+		cwd, _ := os.Getwd()
+		if cwd != originalDir {
+			t.Errorf("working directory not restored: got %s, want %s", cwd, originalDir)
+		}
+	}
+}
+
+func TestPickVerifyExtension(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *tracker.Config
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "first tracked extension",
+			cfg:  &tracker.Config{TrackedExtensions: []string{".go", ".py"}},
+			want: ".go",
+		},
+		{
+			name: "skip excluded extension",
+			cfg: &tracker.Config{
+				TrackedExtensions: []string{".go", ".py"},
+				ExcludePatterns:   []string{"*.go"},
+			},
+			want: ".py",
+		},
+		{
+			name: "all excluded",
+			cfg: &tracker.Config{
+				TrackedExtensions: []string{".go"},
+				ExcludePatterns:   []string{"aict_verify_*"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pickVerifyExtension(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pickVerifyExtension() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("pickVerifyExtension() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleVerifySetup_MissingHooks(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	err := handleVerifySetup()
+	if err == nil {
+		t.Fatal("expected error when hooks are not installed")
+	}
+	if !strings.Contains(err.Error(), "problem(s)") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+const whoamiUsage = "Usage: aict whoami [set <name>|clear]"
+
+// whoamiEnvVar は開発者を端末（シェル）ごとに指定する環境変数。.git/aict/whoami.json より優先します。
+const whoamiEnvVar = "AICT_WHOAMI"
+
+// handleWhoami handles the whoami command
+// 共有の作業端末で交代する開発者を、git config user.name とは別に設定します。
+func handleWhoami() error {
+	if len(os.Args) < 3 {
+		return handleWhoamiShow()
+	}
+
+	switch os.Args[2] {
+	case "set":
+		return handleWhoamiSet()
+	case "clear":
+		return handleWhoamiClear()
+	default:
+		fmt.Printf("Unknown subcommand: %s\n", os.Args[2])
+		fmt.Println(whoamiUsage)
+		return fmt.Errorf("unknown subcommand: %s", os.Args[2])
+	}
+}
+
+func handleWhoamiShow() error {
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Run 'aict init' first\n")
+		return err
+	}
+
+	if name, source := activeHumanAuthor(store); name != "" {
+		fmt.Printf("%s (%s)\n", name, source)
+		return nil
+	}
+	switch {
+	case cfg.DefaultAuthor != "":
+		fmt.Printf("%s (default_author)\n", cfg.DefaultAuthor)
+	case getGitUserName() != "":
+		fmt.Printf("%s (git config user.name)\n", getGitUserName())
+	default:
+		fmt.Println("Not set. Run 'aict whoami set <name>'")
+	}
+	return nil
+}
+
+// handleWhoamiSet は以降のチェックポイントで使う開発者を .git/aict/whoami.json に保存します。
+func handleWhoamiSet() error {
+	fs := flag.NewFlagSet("whoami set", flag.ExitOnError)
+	fs.Parse(os.Args[3:])
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: aict whoami set <name>")
+	}
+	name := strings.TrimSpace(fs.Arg(0))
+	if name == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Run 'aict init' first\n")
+		return err
+	}
+	if tracker.IsAIAgent(name, cfg.AIAgents, cfg.AuthorMappings) {
+		return fmt.Errorf("%s is configured as an AI agent; whoami sets the human author", name)
+	}
+	if err := store.SaveWhoami(&tracker.ActiveIdentity{Name: name, SetAt: time.Now()}); err != nil {
+		return fmt.Errorf("saving %s: %w", storage.WhoamiFileName, err)
+	}
+	fmt.Printf("✓ Human author set to %s (used instead of git config user.name)\n", name)
+	if env := os.Getenv(whoamiEnvVar); env != "" && env != name {
+		fmt.Printf("  Note: %s=%s in this shell takes precedence\n", whoamiEnvVar, env)
+	}
+	return nil
+}
+
+func handleWhoamiClear() error {
+	store, _, err := loadStorageAndConfig()
+	if err != nil {
+		return err
+	}
+	if err := store.ClearWhoami(); err != nil {
+		return fmt.Errorf("removing %s: %w", storage.WhoamiFileName, err)
+	}
+	fmt.Println("✓ Human author cleared (git config user.name is used again)")
+	return nil
+}
+
+// activeHumanAuthor は `aict whoami` で指定された開発者と、その指定元を返します。
+// 環境変数 AICT_WHOAMI、.git/aict/whoami.json の順に参照し、どちらもなければ空文字列です。
+func activeHumanAuthor(store *storage.AIctStorage) (name, source string) {
+	if env := strings.TrimSpace(os.Getenv(whoamiEnvVar)); env != "" {
+		return env, whoamiEnvVar
+	}
+	if store == nil {
+		return "", ""
+	}
+	if identity, err := store.LoadWhoami(); err == nil && identity != nil {
+		return identity.Name, "aict whoami"
+	}
+	return "", ""
+}
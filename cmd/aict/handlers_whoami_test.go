@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestWhoami_PreferredOverGitUserName(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	t.Setenv(whoamiEnvVar, "")
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	os.Args = []string{"aict", "whoami", "set", "Alice"}
+	if err := handleWhoami(); err != nil {
+		t.Fatalf("whoami set error = %v", err)
+	}
+	runToolUseHook(hookEventPreToolUse, &hookInput{CWD: tmpDir, ToolName: "Edit"})
+
+	// 環境変数は whoami.json より優先する
+	t.Setenv(whoamiEnvVar, "Bob")
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\n// bob\n")
+	if _, err := createCheckpoint("", "", "", nil, nil); err != nil {
+		t.Fatalf("createCheckpoint() error = %v", err)
+	}
+
+	t.Setenv(whoamiEnvVar, "")
+	os.Args = []string{"aict", "whoami", "clear"}
+	if err := handleWhoami(); err != nil {
+		t.Fatalf("whoami clear error = %v", err)
+	}
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\n// test user\n")
+	runToolUseHook(hookEventPreToolUse, &hookInput{CWD: tmpDir, ToolName: "Edit"})
+
+	store, _, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	checkpoints, err := store.LoadCheckpoints()
+	if err != nil {
+		t.Fatalf("LoadCheckpoints() error = %v", err)
+	}
+	var authors []string
+	for _, cp := range checkpoints {
+		authors = append(authors, cp.Author)
+	}
+	if len(authors) != 3 || authors[0] != "Alice" || authors[1] != "Bob" || authors[2] != "Test User" {
+		t.Errorf("checkpoint authors = %v, want [Alice Bob Test User]", authors)
+	}
+}
+
+func TestWhoami_RejectsAIAgent(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	os.Args = []string{"aict", "whoami", "set", "Claude Code"}
+	if err := handleWhoami(); err == nil {
+		t.Error("whoami set should reject an AI agent name")
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/blame"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/ownership"
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// handleWhy handles the why command
+// 作業ツリーの1行について、git blame のコミット、Authorship Logの有無、選ばれた作成者と分類の根拠、
+// reclassify の履歴、未コミットのチェックポイントを順に出力します（帰属の食い違いの調査用）。
+func handleWhy() error {
+	fs := flag.NewFlagSet("why", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: aict why <file>:<line>")
+	}
+	fs.Parse(os.Args[2:])
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("<file>:<line> is required")
+	}
+	path, line, err := parseFileLine(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return err
+	}
+
+	executor := newExecutor()
+	origins, err := blame.File(executor, "", path, []int{line})
+	if err != nil {
+		return fmt.Errorf("blaming %s:%d: %w", path, line, err)
+	}
+	origin, ok := origins[line]
+	if !ok {
+		return fmt.Errorf("line %d not found in %s", line, path)
+	}
+
+	repoPath := origin.Path
+	if out, err := executor.Run("ls-files", "--full-name", "--", path); err == nil && out != "" {
+		repoPath = strings.Split(out, "\n")[0]
+	}
+
+	fmt.Printf("%s:%d\n", repoPath, line)
+	result := explainCommittedLine(origin, cfg, store)
+	explainPendingCheckpoints(store, repoPath)
+	fmt.Printf("  Result:       %s\n", result)
+	return nil
+}
+
+// parseFileLine は "<file>:<line>" を分解します（ファイル名に ":" を含む場合は最後の ":" で区切る）。
+func parseFileLine(arg string) (string, int, error) {
+	i := strings.LastIndex(arg, ":")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("expected <file>:<line>, got %q", arg)
+	}
+	line, err := strconv.Atoi(arg[i+1:])
+	if err != nil || line < 1 {
+		return "", 0, fmt.Errorf("invalid line number in %q", arg)
+	}
+	return arg[:i], line, nil
+}
+
+// explainCommittedLine は blame の変更元コミットとAuthorship Logから帰属の根拠を出力し、
+// 判定結果（ai・human・unknown、未コミットは uncommitted）を返します。
+func explainCommittedLine(origin blame.Origin, cfg *tracker.Config, store *storage.AIctStorage) string {
+	if origin.Commit == blame.UncommittedHash {
+		fmt.Println("  Blame:        not committed yet (attributed by checkpoints when committed)")
+		return lineTypeUncommitted
+	}
+
+	executor := newExecutor()
+	summary := shortCommit(origin.Commit)
+	if out, err := executor.Run("log", "-1", "--format=%an%x00%as%x00%s", origin.Commit); err == nil {
+		if parts := strings.SplitN(out, "\x00", 3); len(parts) == 3 {
+			summary = fmt.Sprintf("%s by %s on %s: %q", summary, parts[0], parts[1], parts[2])
+		}
+	}
+	fmt.Printf("  Blame:        %s\n", summary)
+	fmt.Printf("  Origin:       %s:%d at that commit\n", origin.Path, origin.Line)
+
+	nm := gitnotes.NewNotesManagerWithExecutor(executor)
+	alog, err := nm.GetAuthorshipLog(origin.Commit)
+	if err != nil || alog == nil {
+		fmt.Printf("  Note:         none in %s (committed without the post-commit hook, or run 'aict notes fetch')\n", gitnotes.AuthorshipNotesRef)
+		return ownership.TypeUnknown
+	}
+	fmt.Printf("  Note:         found in %s\n", gitnotes.AuthorshipNotesRef)
+
+	info, ok := alog.Files[origin.Path]
+	if !ok {
+		fmt.Printf("  Author:       none (%s is not recorded in the note; untracked extension, excluded or generated at that time)\n", origin.Path)
+		return ownership.TypeUnknown
+	}
+	author := ownership.LineAuthor(info, origin.Line)
+	if author == nil {
+		fmt.Printf("  Author:       none (line %d is outside the recorded line ranges)\n", origin.Line)
+		return ownership.TypeUnknown
+	}
+
+	desc := fmt.Sprintf("%s (%s)", author.Name, author.Type)
+	if model := author.Metadata["model"]; model != "" {
+		desc += ", model " + model
+	}
+	fmt.Printf("  Author:       %s\n", desc)
+	if len(info.Authors) == 1 {
+		fmt.Printf("  Selected by:  only author recorded for %s (whole file)\n", origin.Path)
+	} else {
+		fmt.Printf("  Selected by:  line %d in recorded ranges %s (%d authors in file)\n", origin.Line, formatLineRanges(author.Lines), len(info.Authors))
+	}
+	if message := author.Metadata["message"]; message != "" {
+		fmt.Printf("  Message:      %s\n", message)
+	}
+
+	classified := fmt.Sprintf("recorded as %s", author.Type)
+	if match := tracker.AIAgentMatch(author.Name, cfg.AIAgents, cfg.AuthorMappings); match != "" {
+		classified += "; current config matches AI by " + match
+	} else {
+		classified += "; current config matches no AI rule (human)"
+	}
+	fmt.Printf("  Classified:   %s\n", classified)
+
+	if entries, err := store.LoadReclassifyLog(); err == nil {
+		for _, e := range entries {
+			for _, c := range e.Changes {
+				if c.Commit == origin.Commit && c.Author == author.Name {
+					fmt.Printf("  Reclassified: %s %s -> %s by %s (aict reclassify)\n", e.Timestamp.Local().Format("2006-01-02 15:04"), c.From, e.To, e.User)
+				}
+			}
+		}
+	}
+	return string(author.Type)
+}
+
+// explainPendingCheckpoints は未コミットのチェックポイントのうちファイルを変更したものを出力します。
+// コミット済みの行のチェックポイントはAuthorship Logの記録時に消費されるため、ここには現れません。
+func explainPendingCheckpoints(store *storage.AIctStorage, repoPath string) {
+	checkpoints, err := store.LoadCheckpoints()
+	if err != nil {
+		debugf("loading checkpoints: %v", err)
+		return
+	}
+	var lines []string
+	for _, cp := range checkpoints {
+		if change, ok := cp.Changes[repoPath]; ok {
+			lines = append(lines, fmt.Sprintf("    %s  %s (%s) +%d -%d",
+				cp.Timestamp.Local().Format("2006-01-02 15:04"), cp.Author, cp.Type, change.Added, change.Deleted))
+		}
+	}
+	if len(lines) == 0 {
+		fmt.Printf("  Checkpoints:  none pending for %s\n", repoPath)
+		return
+	}
+	fmt.Printf("  Checkpoints:  %d pending for %s\n", len(lines), repoPath)
+	for _, l := range lines {
+		fmt.Println(l)
+	}
+}
+
+// formatLineRanges は [[start, end], [line]] 形式の行範囲を "1-3, 7" の形式にします。
+func formatLineRanges(ranges [][]int) string {
+	parts := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		switch len(r) {
+		case 1:
+			parts = append(parts, strconv.Itoa(r[0]))
+		case 2:
+			parts = append(parts, fmt.Sprintf("%d-%d", r[0], r[1]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
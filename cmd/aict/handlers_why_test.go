@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestParseFileLine(t *testing.T) {
+	tests := []struct {
+		arg      string
+		wantPath string
+		wantLine int
+		wantErr  bool
+	}{
+		{"main.go:12", "main.go", 12, false},
+		{"dir/a:b.go:3", "dir/a:b.go", 3, false},
+		{"main.go", "", 0, true},
+		{"main.go:0", "", 0, true},
+		{"main.go:x", "", 0, true},
+		{":3", "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			path, line, err := parseFileLine(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFileLine(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if path != tt.wantPath || line != tt.wantLine {
+				t.Errorf("parseFileLine(%q) = %q, %d", tt.arg, path, line)
+			}
+		})
+	}
+}
+
+func TestHandleWhy(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc a() {}\nfunc b() {}\n")
+	commit := testutil.GitCommit(t, tmpDir, "Add a and b")
+
+	nm := gitnotes.NewNotesManager()
+	if err := nm.AddAuthorshipLog(&tracker.AuthorshipLog{
+		Version: "1.0",
+		Commit:  commit,
+		Files: map[string]tracker.FileInfo{
+			"main.go": {Authors: []tracker.AuthorInfo{
+				{Name: "Claude Code", Type: tracker.AuthorTypeAI, Lines: [][]int{{2, 3}}, Metadata: map[string]string{"model": "sonnet"}},
+				{Name: "dev", Type: tracker.AuthorTypeHuman, Lines: [][]int{{4}}},
+			}},
+		},
+	}); err != nil {
+		t.Fatalf("AddAuthorshipLog() error = %v", err)
+	}
+
+	// 未コミットの変更とそのチェックポイント
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc a() {}\nfunc b() {}\nfunc c() {}\n")
+	if err := recordCheckpoint("Claude", "", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		arg      string
+		wantErr  bool
+		wantText []string
+	}{
+		{"ai line", "main.go:3", false, []string{
+			"Note:         found in " + gitnotes.AuthorshipNotesRef,
+			"Author:       Claude Code (ai), model sonnet",
+			"Selected by:  line 3 in recorded ranges 2-3 (2 authors in file)",
+			`current config matches AI by name pattern "claude"`,
+			"Checkpoints:  1 pending for main.go",
+			"Result:       ai",
+		}},
+		{"human line", "main.go:4", false, []string{"Author:       dev (human)", "matches no AI rule", "Result:       human"}},
+		{"commit without note", "main.go:1", false, []string{"Note:         none", "Result:       unknown"}},
+		{"uncommitted line", "main.go:5", false, []string{"not committed yet", "Claude (ai) +1 -0", "Result:       uncommitted"}},
+		{"line out of range", "main.go:99", true, nil},
+		{"missing line number", "main.go", true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = []string{"aict", "why", tt.arg}
+			origStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := handleWhy()
+
+			w.Close()
+			os.Stdout = origStdout
+			var buf bytes.Buffer
+			buf.ReadFrom(r)
+			output := buf.String()
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handleWhy() error = %v, wantErr %v\n%s", err, tt.wantErr, output)
+			}
+			for _, want := range tt.wantText {
+				if !strings.Contains(output, want) {
+					t.Errorf("output missing %q:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
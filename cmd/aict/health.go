@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+)
+
+const (
+	// timingEventCheckpoint はチェックポイント記録（hook経由）の所要時間の記録名
+	timingEventCheckpoint = "checkpoint"
+	// timingEventRollup は日次集計（メトリクス）更新の所要時間の記録名
+	timingEventRollup = "rollup"
+	// healthLatencyWindow は平均hookレイテンシの算出に使う直近の記録数
+	healthLatencyWindow = 100
+)
+
+// hookTimingPattern は logTiming が書き込む行にマッチします。
+// 例: [2025-01-01 12:00:00] checkpoint: completed in 12.3ms
+var hookTimingPattern = regexp.MustCompile(`^\[([^\]]+)\] (\S+): completed in (\S+)$`)
+
+// logTiming は処理の所要時間をhook.logに記録します（aict debug health で集計）。
+// 計測の記録に失敗しても本来の処理は失敗させません。
+func logTiming(store *storage.AIctStorage, event string, d time.Duration) {
+	if err := store.AppendHookLog(fmt.Sprintf("%s: completed in %s", event, d.Round(time.Microsecond))); err != nil {
+		debugf("failed to record %s timing: %v", event, err)
+	}
+}
+
+// hookLogHealth はhook.logから集計した健全性情報です。
+type hookLogHealth struct {
+	checkpointDurations []time.Duration // 直近 healthLatencyWindow 件
+	failures            int             // hookスクリプトが記録した失敗の件数
+	lastRollup          time.Duration
+	lastRollupAt        string
+}
+
+// averageLatency は直近のチェックポイント記録の平均・最大所要時間を返します。
+func (h *hookLogHealth) averageLatency() (avg, max time.Duration) {
+	if len(h.checkpointDurations) == 0 {
+		return 0, 0
+	}
+	var total time.Duration
+	for _, d := range h.checkpointDurations {
+		total += d
+		if d > max {
+			max = d
+		}
+	}
+	return total / time.Duration(len(h.checkpointDurations)), max
+}
+
+// parseHookLog はhook.logから所要時間の記録とhookの失敗を集計します。
+func parseHookLog(data []byte) *hookLogHealth {
+	h := &hookLogHealth{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.Contains(line, "Failed to record checkpoint") || strings.Contains(line, "aict binary not found") {
+			h.failures++
+			continue
+		}
+
+		m := hookTimingPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		d, err := time.ParseDuration(m[3])
+		if err != nil {
+			continue
+		}
+		switch m[2] {
+		case timingEventCheckpoint:
+			h.checkpointDurations = append(h.checkpointDurations, d)
+		case timingEventRollup:
+			h.lastRollup, h.lastRollupAt = d, m[1]
+		}
+	}
+
+	if n := len(h.checkpointDurations); n > healthLatencyWindow {
+		h.checkpointDurations = h.checkpointDurations[n-healthLatencyWindow:]
+	}
+	return h
+}
+
+// formatByteSize はバイト数を人間が読みやすい単位に変換します。
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// handleDebugHealth はトラッカー自身の健全性指標を表示します。
+func handleDebugHealth() error {
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		return fmt.Errorf("initializing storage: %w", err)
+	}
+
+	logData, err := store.LoadHookLog()
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", storage.HookLogFileName, err)
+	}
+	hooks := parseHookLog(logData)
+
+	files, err := store.InspectRecordFiles()
+	if err != nil {
+		return fmt.Errorf("inspecting record files: %w", err)
+	}
+
+	size, err := store.DataDirSize()
+	if err != nil {
+		return fmt.Errorf("measuring data directory: %w", err)
+	}
+
+	fmt.Println("=== トラッカーの健全性 ===")
+	fmt.Println()
+
+	if avg, max := hooks.averageLatency(); len(hooks.checkpointDurations) > 0 {
+		fmt.Printf("hookレイテンシ:     平均 %s / 最大 %s（直近%d件のチェックポイント）\n",
+			avg.Round(time.Microsecond), max.Round(time.Microsecond), len(hooks.checkpointDurations))
+	} else {
+		fmt.Println("hookレイテンシ:     記録なし")
+	}
+	fmt.Printf("hookの失敗:         %d件\n", hooks.failures)
+	if hooks.lastRollupAt != "" {
+		fmt.Printf("最終メトリクス更新: %s（%s）\n", hooks.lastRollup.Round(time.Microsecond), hooks.lastRollupAt)
+	} else {
+		fmt.Println("最終メトリクス更新: 記録なし")
+	}
+
+	malformed := 0
+	for _, f := range files {
+		malformed += f.Malformed
+	}
+	fmt.Printf("不正なレコード:     %d件（読み込み時にスキップ）\n", malformed)
+	for _, f := range files {
+		mark := "✓"
+		if f.Malformed > 0 {
+			mark = "✗"
+		}
+		fmt.Printf("  %s %-28s %6d件  不正 %d件  %s\n", mark, f.Name, f.Records, f.Malformed, formatByteSize(f.Bytes))
+	}
+	fmt.Printf("データサイズ:       %s（%s）\n", formatByteSize(size), store.GetAictDir())
+
+	if malformed > 0 {
+		fmt.Fprintln(os.Stderr, "\n不正なレコードがあります。'aict fsck' で確認し、'aict fsck --repair' で取り除いてください")
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestParseHookLog(t *testing.T) {
+	log := strings.Join([]string{
+		"[2025-01-01 10:00:00] pre-tool-use: Recording checkpoint for dev",
+		"[2025-01-01 10:00:00] checkpoint: completed in 10ms",
+		"[2025-01-01 10:00:01] pre-tool-use: Checkpoint recorded successfully",
+		"[2025-01-01 10:00:02] checkpoint: completed in 30ms",
+		"[2025-01-01 10:00:03] post-tool-use: Failed to record checkpoint (exit code: 1)",
+		"[2025-01-01 10:00:04] rollup: completed in 2ms",
+		"[2025-01-01 10:00:05] rollup: completed in 5ms",
+		"[2025-01-01 10:00:06] checkpoint: completed in bogus",
+	}, "\n")
+
+	h := parseHookLog([]byte(log))
+	avg, max := h.averageLatency()
+	if len(h.checkpointDurations) != 2 || avg != 20*time.Millisecond || max != 30*time.Millisecond {
+		t.Errorf("latency = %v avg %v max %v, want 2 samples avg 20ms max 30ms", h.checkpointDurations, avg, max)
+	}
+	if h.failures != 1 {
+		t.Errorf("failures = %d, want 1", h.failures)
+	}
+	if h.lastRollup != 5*time.Millisecond || h.lastRollupAt != "2025-01-01 10:00:05" {
+		t.Errorf("last rollup = %v at %q", h.lastRollup, h.lastRollupAt)
+	}
+}
+
+func TestParseHookLog_Window(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < healthLatencyWindow+10; i++ {
+		d := time.Millisecond
+		if i < 10 {
+			d = time.Second // ウィンドウ外の古い記録
+		}
+		b.WriteString("[2025-01-01 10:00:00] checkpoint: completed in " + d.String() + "\n")
+	}
+
+	h := parseHookLog([]byte(b.String()))
+	if avg, _ := h.averageLatency(); avg != time.Millisecond {
+		t.Errorf("average = %v, want 1ms (only the last %d samples)", avg, healthLatencyWindow)
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+	}
+	for _, tt := range tests {
+		if got := formatByteSize(tt.n); got != tt.want {
+			t.Errorf("formatByteSize(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestHandleDebug_Health(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	// チェックポイントとコミットで所要時間がhook.logに記録される
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	if err := recordCheckpoint("dev", "", ""); err != nil {
+		t.Fatalf("recordCheckpoint() error = %v", err)
+	}
+	testutil.GitCommit(t, tmpDir, "initial")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		t.Fatalf("NewAIctStorage() error = %v", err)
+	}
+	data, err := store.LoadHookLog()
+	if err != nil {
+		t.Fatalf("LoadHookLog() error = %v", err)
+	}
+	h := parseHookLog(data)
+	if len(h.checkpointDurations) != 1 {
+		t.Errorf("expected 1 checkpoint timing, got %d\n%s", len(h.checkpointDurations), data)
+	}
+	if h.lastRollupAt == "" {
+		t.Errorf("expected rollup timing in hook.log\n%s", data)
+	}
+
+	os.Args = []string{"aict", "debug", "health"}
+	if err := handleDebug(); err != nil {
+		t.Fatalf("handleDebug(health) error = %v", err)
+	}
+}
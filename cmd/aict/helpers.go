@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
 	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
@@ -19,6 +20,19 @@ func loadStorageAndConfig() (*storage.AIctStorage, *tracker.Config, error) {
 	if err != nil {
 		return nil, nil, fmt.Errorf("loading config: %w", err)
 	}
+	rawLineExtensions = cfg.GetDocsExtensions()
+	stripLicenseHeaders = !cfg.IncludeLicenseHeaders
 
 	return store, cfg, nil
 }
+
+// getGitCommonDir はgit共通ディレクトリ（通常は <repo>/.git）の絶対パスを返します。
+// worktreeでは全worktreeで共有されるディレクトリ、bareリポジトリではリポジトリ自体を返します。
+func getGitCommonDir() (string, error) {
+	executor := newExecutor()
+	output, err := executor.Run("rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to get git directory: %w", err)
+	}
+	return filepath.Abs(output)
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// git hookの管理ツール。これらを使うリポジトリでは .git/hooks/ を直接書き換えず、ツールの設定にaictを追加します。
+const (
+	hookManagerHusky    = "husky"    // .husky/<hook> のスクリプトを core.hooksPath 経由で実行
+	hookManagerLefthook = "lefthook" // lefthook.yml の定義から .git/hooks/ を生成
+)
+
+// lefthookConfigNames はlefthookが読み込む設定ファイル名（優先順）
+var lefthookConfigNames = []string{"lefthook.yml", "lefthook.yaml", ".lefthook.yml", ".lefthook.yaml"}
+
+// lefthookPostCommitSnippet はlefthook.ymlに追加するpost-commitの定義
+const lefthookPostCommitSnippet = `post-commit:
+  commands:
+    aict:
+      run: ` + postCommitHookCommand + `
+`
+
+// huskyLegacyHeader はhusky v4〜v8のhookスクリプトの先頭行（v9以降は不要）
+const huskyLegacyHeader = "#!/usr/bin/env sh\n. \"$(dirname -- \"$0\")/_/husky.sh\"\n\n"
+
+// gitHooksDir はgitがhookを実行するディレクトリを返します。
+// core.hooksPath が設定されていればそのディレクトリ（相対パスはリポジトリルート基準、~/ はホームディレクトリ）、
+// なければ共通gitディレクトリの hooks/ です。
+func gitHooksDir(repoRoot, gitDir string) string {
+	hooksPath, err := newExecutor().Run("config", "core.hooksPath")
+	if err != nil || hooksPath == "" {
+		return filepath.Join(gitDir, "hooks")
+	}
+	if strings.HasPrefix(hooksPath, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			hooksPath = filepath.Join(home, hooksPath[2:])
+		}
+	}
+	if !filepath.IsAbs(hooksPath) {
+		hooksPath = filepath.Join(repoRoot, hooksPath)
+	}
+	return filepath.Clean(hooksPath)
+}
+
+// detectHookManager はリポジトリが使っているgit hook管理ツールと、aictを追加する先のパスを返します。
+// lefthookは設定ファイル、huskyは .husky/post-commit を返します。どちらも使っていない場合は空文字列です。
+func detectHookManager(repoRoot string) (manager, path string) {
+	for _, name := range lefthookConfigNames {
+		p := filepath.Join(repoRoot, name)
+		if _, err := os.Stat(p); err == nil {
+			return hookManagerLefthook, p
+		}
+	}
+
+	huskyDir := filepath.Join(repoRoot, ".husky")
+	hooksPath, _ := newExecutor().Run("config", "core.hooksPath")
+	if info, err := os.Stat(huskyDir); (err == nil && info.IsDir()) || strings.Contains(filepath.ToSlash(hooksPath), ".husky") {
+		return hookManagerHusky, filepath.Join(huskyDir, "post-commit")
+	}
+	return "", ""
+}
+
+// setupManagedPostCommit は検出したhook管理ツールにaictのpost-commit呼び出しを追加します。
+func setupManagedPostCommit(manager, path string) error {
+	switch manager {
+	case hookManagerHusky:
+		if err := writeHuskyPostCommit(path); err != nil {
+			return err
+		}
+		fmt.Printf("✓ husky detected: added '%s' to %s\n", postCommitHookCommand, path)
+	case hookManagerLefthook:
+		if err := writeLefthookPostCommit(path); err != nil {
+			// 既存の post-commit 定義は構造を崩さないよう自動編集しない
+			fmt.Printf("Warning: %v\n", err)
+			fmt.Printf("Please add the following to %s:\n\n%s\n", path, lefthookPostCommitSnippet)
+			return nil
+		}
+		fmt.Printf("✓ lefthook detected: added post-commit command to %s\n", path)
+		fmt.Println("  Run 'lefthook install' if the post-commit hook is not installed yet")
+	}
+	return nil
+}
+
+// writeHuskyPostCommit は .husky/post-commit にaictの呼び出しを1行だけ追加します（ファイルがなければ作成）。
+func writeHuskyPostCommit(hookPath string) error {
+	var content string
+	data, err := os.ReadFile(hookPath)
+	switch {
+	case err == nil:
+		content = appendPostCommitCall(string(data))
+	case os.IsNotExist(err):
+		if _, err := os.Stat(filepath.Join(filepath.Dir(hookPath), "_", "husky.sh")); err == nil {
+			content = huskyLegacyHeader
+		}
+		content += postCommitHookCommand + "\n"
+	default:
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(hookPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(hookPath, []byte(content), 0755)
+}
+
+// writeLefthookPostCommit はlefthookの設定にaictのpost-commitコマンドを追加します。
+// YAMLの構造を保つため、post-commit が未定義なら末尾に追記し、aictの古い呼び出しは1つだけなら行内で置換します。
+// 既存の post-commit 定義への追加や重複した呼び出しは手動での編集を求めるエラーを返します。
+func writeLefthookPostCommit(configPath string) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+
+	calls := lefthookAICTCommands(content)
+	switch {
+	case len(calls) == 1 && calls[0] == postCommitHookCommand:
+		return nil
+	case len(calls) == 1:
+		content = strings.Replace(content, calls[0], postCommitHookCommand, 1)
+	case len(calls) > 1:
+		return fmt.Errorf("%s calls aict %d times; keep only '%s'", configPath, len(calls), postCommitHookCommand)
+	case hasTopLevelYAMLKey(content, "post-commit"):
+		return fmt.Errorf("post-commit is already defined in %s", configPath)
+	default:
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if content != "" {
+			content += "\n"
+		}
+		content += lefthookPostCommitSnippet
+	}
+	return os.WriteFile(configPath, []byte(content), 0644)
+}
+
+// lefthookAICTCommands はlefthook設定の run: に書かれたaictの呼び出しを返します。
+func lefthookAICTCommands(content string) []string {
+	var calls []string
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "-"))
+		if !strings.HasPrefix(trimmed, "run:") {
+			continue
+		}
+		cmd := strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmed, "run:")), `"'`)
+		if _, changed := stripAICTLines(cmd); changed {
+			calls = append(calls, cmd)
+		}
+	}
+	return calls
+}
+
+// hasTopLevelYAMLKey はYAMLにインデントなしのキーが定義されているかを判定します。
+func hasTopLevelYAMLKey(content, key string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, key+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// removeLefthookPostCommit はsetup-hooksが追加したpost-commit定義をlefthookの設定から除去します。
+// 手動で追加された定義は構造を崩さないよう編集せず、除去を促す警告のみ表示します。
+func removeLefthookPostCommit(configPath string) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil
+	}
+	content := string(data)
+	if strings.Contains(content, lefthookPostCommitSnippet) {
+		content = strings.Replace(content, lefthookPostCommitSnippet, "", 1)
+		content = strings.TrimRight(content, "\n")
+		if content != "" {
+			content += "\n"
+		}
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			return fmt.Errorf("updating %s: %w", configPath, err)
+		}
+		fmt.Printf("✓ Removed aict post-commit command from %s\n", configPath)
+		return nil
+	}
+	if len(lefthookAICTCommands(content)) > 0 {
+		fmt.Printf("Warning: %s still runs aict; remove the command manually\n", configPath)
+	}
+	return nil
+}
+
+// removeHuskyPostCommit は .husky/post-commit からaictの呼び出しを除去し、他のコマンドが残らなければファイルを削除します。
+func removeHuskyPostCommit(hookPath string) error {
+	data, err := os.ReadFile(hookPath)
+	if err != nil {
+		return nil
+	}
+	stripped, changed := stripAICTLines(string(data))
+	if !changed {
+		return nil
+	}
+	if strings.TrimSpace(strings.Replace(stripped, huskyLegacyHeader, "", 1)) == "" {
+		if err := os.Remove(hookPath); err != nil {
+			return fmt.Errorf("removing %s: %w", hookPath, err)
+		}
+		fmt.Printf("✓ Removed %s\n", hookPath)
+		return nil
+	}
+	if err := os.WriteFile(hookPath, []byte(stripped), 0755); err != nil {
+		return fmt.Errorf("updating %s: %w", hookPath, err)
+	}
+	fmt.Printf("✓ Removed aict commands from %s\n", hookPath)
+	return nil
+}
+
+// inspectPostCommit はpost-commitの設置先（hook管理ツールの設定、または .git/hooks/post-commit）を検査します。
+func inspectPostCommit(repoRoot, gitDir string) *hookComponent {
+	manager, path := detectHookManager(repoRoot)
+	switch manager {
+	case hookManagerHusky:
+		c := inspectPostCommitHook(path)
+		c.name = "post-commit hook (husky)"
+		if _, err := os.Stat(path); err != nil {
+			c.repair = func() error { return writeHuskyPostCommit(path) }
+		}
+		return c
+	case hookManagerLefthook:
+		return inspectLefthookConfig(path)
+	}
+	return inspectPostCommitHook(filepath.Join(gitHooksDir(repoRoot, gitDir), "post-commit"))
+}
+
+// inspectLefthookConfig はlefthookの設定がaictのpost-commitコマンドを1つだけ含むかを検査します。
+func inspectLefthookConfig(configPath string) *hookComponent {
+	c := &hookComponent{
+		name:   "post-commit (lefthook " + filepath.Base(configPath) + ")",
+		path:   configPath,
+		repair: func() error { return writeLefthookPostCommit(configPath) },
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		c.problems = append(c.problems, "not found")
+		return c
+	}
+	calls := lefthookAICTCommands(string(data))
+	switch {
+	case len(calls) == 0:
+		c.problems = append(c.problems, "aict command not configured")
+	case len(calls) > 1:
+		c.problems = append(c.problems, fmt.Sprintf("aict is called %d times (duplicated)", len(calls)))
+	case calls[0] != postCommitHookCommand:
+		c.problems = append(c.problems, fmt.Sprintf("stale aict call %q", calls[0]))
+	}
+	return c
+}
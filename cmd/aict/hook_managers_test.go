@@ -0,0 +1,270 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestDetectHookManager(t *testing.T) {
+	tests := []struct {
+		name        string
+		setup       func(t *testing.T, repo string)
+		wantManager string
+		wantPath    string
+	}{
+		{"none", func(t *testing.T, repo string) {}, "", ""},
+		{
+			name:        "husky directory",
+			setup:       func(t *testing.T, repo string) { os.MkdirAll(filepath.Join(repo, ".husky"), 0755) },
+			wantManager: hookManagerHusky,
+			wantPath:    filepath.Join(".husky", "post-commit"),
+		},
+		{
+			name: "husky core.hooksPath",
+			setup: func(t *testing.T, repo string) {
+				if out, err := exec.Command("git", "-C", repo, "config", "core.hooksPath", ".husky/_").CombinedOutput(); err != nil {
+					t.Fatalf("git config: %v: %s", err, out)
+				}
+			},
+			wantManager: hookManagerHusky,
+			wantPath:    filepath.Join(".husky", "post-commit"),
+		},
+		{
+			name:        "lefthook.yml",
+			setup:       func(t *testing.T, repo string) { writeTestFile(t, filepath.Join(repo, "lefthook.yml"), "", 0644) },
+			wantManager: hookManagerLefthook,
+			wantPath:    "lefthook.yml",
+		},
+		{
+			name: "lefthook takes precedence over husky",
+			setup: func(t *testing.T, repo string) {
+				os.MkdirAll(filepath.Join(repo, ".husky"), 0755)
+				writeTestFile(t, filepath.Join(repo, ".lefthook.yaml"), "", 0644)
+			},
+			wantManager: hookManagerLefthook,
+			wantPath:    ".lefthook.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := testutil.TempGitRepo(t)
+			originalDir, _ := os.Getwd()
+			defer os.Chdir(originalDir)
+			os.Chdir(tmpDir)
+
+			tt.setup(t, tmpDir)
+			manager, path := detectHookManager(tmpDir)
+			wantPath := ""
+			if tt.wantPath != "" {
+				wantPath = filepath.Join(tmpDir, tt.wantPath)
+			}
+			if manager != tt.wantManager || path != wantPath {
+				t.Errorf("detectHookManager() = (%q, %q), want (%q, %q)", manager, path, tt.wantManager, wantPath)
+			}
+		})
+	}
+}
+
+func TestGitHooksDir(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	gitDir := filepath.Join(tmpDir, ".git")
+	absHooks := filepath.Join(t.TempDir(), "shared-hooks")
+	tests := []struct {
+		hooksPath string
+		want      string
+	}{
+		{"", filepath.Join(gitDir, "hooks")},
+		{".githooks", filepath.Join(tmpDir, ".githooks")},
+		{absHooks, absHooks},
+	}
+	for _, tt := range tests {
+		if tt.hooksPath != "" {
+			if out, err := exec.Command("git", "config", "core.hooksPath", tt.hooksPath).CombinedOutput(); err != nil {
+				t.Fatalf("git config: %v: %s", err, out)
+			}
+		}
+		if got := gitHooksDir(tmpDir, gitDir); got != tt.want {
+			t.Errorf("gitHooksDir() with core.hooksPath=%q = %q, want %q", tt.hooksPath, got, tt.want)
+		}
+	}
+}
+
+func TestInstallHooks_CoreHooksPath(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	if out, err := exec.Command("git", "config", "core.hooksPath", ".githooks").CombinedOutput(); err != nil {
+		t.Fatalf("git config: %v: %s", err, out)
+	}
+	if err := installHooks(claudeSettingsProject, false); err != nil {
+		t.Fatalf("installHooks() error = %v", err)
+	}
+
+	hookPath := filepath.Join(tmpDir, ".githooks", "post-commit")
+	if data, err := os.ReadFile(hookPath); err != nil || !strings.Contains(string(data), "aict hook post-commit") {
+		t.Errorf("post-commit hook should be installed into core.hooksPath (%s): %v", hookPath, err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".git", "hooks", "post-commit")); !os.IsNotExist(err) {
+		t.Error("post-commit hook should not be written to .git/hooks when core.hooksPath is set")
+	}
+	if c := inspectPostCommit(tmpDir, filepath.Join(tmpDir, ".git")); c.path != hookPath || len(c.problems) != 0 {
+		t.Errorf("inspectPostCommit() = %s %v, want %s without problems", c.path, c.problems, hookPath)
+	}
+
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+	os.Args = []string{"aict", "uninstall"}
+	if err := handleUninstall(); err != nil {
+		t.Fatalf("handleUninstall() error = %v", err)
+	}
+	if _, err := os.Stat(hookPath); !os.IsNotExist(err) {
+		t.Error("uninstall should remove the hook from core.hooksPath")
+	}
+}
+
+func TestWriteLefthookPostCommit(t *testing.T) {
+	preCommit := "pre-commit:\n  commands:\n    lint:\n      run: make lint\n"
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"append to config without post-commit", preCommit, preCommit + "\n" + lefthookPostCommitSnippet, false},
+		{"empty config", "", lefthookPostCommitSnippet, false},
+		{"already configured", lefthookPostCommitSnippet, lefthookPostCommitSnippet, false},
+		{
+			name:    "replace stale call in place",
+			content: "post-commit:\n  commands:\n    track:\n      run: aict commit\n",
+			want:    "post-commit:\n  commands:\n    track:\n      run: aict hook post-commit\n",
+		},
+		{"existing post-commit is left to the user", "post-commit:\n  commands:\n    notify:\n      run: ./notify.sh\n", "", true},
+		{"duplicated calls", lefthookPostCommitSnippet + "  jobs:\n    - run: aict commit\n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "lefthook.yml")
+			writeTestFile(t, path, tt.content, 0644)
+
+			err := writeLefthookPostCommit(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("writeLefthookPostCommit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, _ := os.ReadFile(path)
+			if string(got) != tt.want {
+				t.Errorf("lefthook.yml = %q, want %q", got, tt.want)
+			}
+			if n := len(inspectLefthookConfig(path).problems); n != 0 {
+				t.Errorf("inspectLefthookConfig() found %d problem(s) after write", n)
+			}
+		})
+	}
+}
+
+func TestHandleSetupHooks_HookManagers(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		original string
+		want     string
+	}{
+		{
+			name:     "husky",
+			file:     filepath.Join(".husky", "post-commit"),
+			original: "npm run notify\n",
+			want:     "npm run notify\naict hook post-commit\n",
+		},
+		{
+			name:     "lefthook",
+			file:     "lefthook.yml",
+			original: "pre-commit:\n  commands:\n    lint:\n      run: make lint\n",
+			want:     "pre-commit:\n  commands:\n    lint:\n      run: make lint\n\n" + lefthookPostCommitSnippet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := testutil.TempGitRepo(t)
+			originalDir, _ := os.Getwd()
+			defer os.Chdir(originalDir)
+			os.Chdir(tmpDir)
+			origArgs := os.Args
+			defer func() { os.Args = origArgs }()
+
+			path := filepath.Join(tmpDir, tt.file)
+			writeTestFile(t, path, tt.original, 0755)
+
+			os.Args = []string{"aict", "setup-hooks"}
+			if err := handleSetupHooksV2(); err != nil {
+				t.Fatalf("handleSetupHooksV2() error = %v", err)
+			}
+
+			got, _ := os.ReadFile(path)
+			if string(got) != tt.want {
+				t.Errorf("%s = %q, want %q", tt.file, got, tt.want)
+			}
+			gitDir := filepath.Join(tmpDir, ".git")
+			if _, err := os.Stat(filepath.Join(gitDir, "hooks", "post-commit")); !os.IsNotExist(err) {
+				t.Error(".git/hooks/post-commit should not be written when a hook manager is used")
+			}
+			for _, c := range inspectHooks(tmpDir, gitDir) {
+				if len(c.problems) > 0 {
+					t.Errorf("%s: %s", c.name, strings.Join(c.problems, "; "))
+				}
+			}
+
+			// 2回目の実行で重複しない
+			if err := handleSetupHooksV2(); err != nil {
+				t.Fatalf("second handleSetupHooksV2() error = %v", err)
+			}
+			if again, _ := os.ReadFile(path); string(again) != tt.want {
+				t.Errorf("setup-hooks is not idempotent: %q", again)
+			}
+
+			os.Args = []string{"aict", "uninstall"}
+			if err := handleUninstall(); err != nil {
+				t.Fatalf("handleUninstall() error = %v", err)
+			}
+			if restored, _ := os.ReadFile(path); string(restored) != tt.original {
+				t.Errorf("after uninstall %s = %q, want %q", tt.file, restored, tt.original)
+			}
+		})
+	}
+}
+
+func TestRemoveHuskyPostCommit_DeletesAICTOnlyHook(t *testing.T) {
+	huskyDir := filepath.Join(t.TempDir(), ".husky")
+	writeTestFile(t, filepath.Join(huskyDir, "_", "husky.sh"), "", 0644)
+	hookPath := filepath.Join(huskyDir, "post-commit")
+
+	if err := writeHuskyPostCommit(hookPath); err != nil {
+		t.Fatalf("writeHuskyPostCommit() error = %v", err)
+	}
+	data, _ := os.ReadFile(hookPath)
+	if want := huskyLegacyHeader + postCommitHookCommand + "\n"; string(data) != want {
+		t.Errorf("post-commit = %q, want %q", data, want)
+	}
+
+	if err := removeHuskyPostCommit(hookPath); err != nil {
+		t.Fatalf("removeHuskyPostCommit() error = %v", err)
+	}
+	if _, err := os.Stat(hookPath); !os.IsNotExist(err) {
+		t.Error("post-commit created only for aict should be removed")
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// languageOther は拡張子のないファイルの言語名
+const languageOther = "Other"
+
+// languageByExtension は拡張子（小文字）から言語名への対応表
+var languageByExtension = map[string]string{
+	".go":    "Go",
+	".py":    "Python",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".mjs":   "JavaScript",
+	".cjs":   "JavaScript",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".java":  "Java",
+	".kt":    "Kotlin",
+	".kts":   "Kotlin",
+	".scala": "Scala",
+	".rb":    "Ruby",
+	".rs":    "Rust",
+	".c":     "C",
+	".h":     "C",
+	".cc":    "C++",
+	".cpp":   "C++",
+	".cxx":   "C++",
+	".hpp":   "C++",
+	".cs":    "C#",
+	".swift": "Swift",
+	".m":     "Objective-C",
+	".php":   "PHP",
+	".dart":  "Dart",
+	".sh":    "Shell",
+	".bash":  "Shell",
+	".sql":   "SQL",
+	".html":  "HTML",
+	".css":   "CSS",
+	".scss":  "SCSS",
+	".vue":   "Vue",
+	".md":    "Markdown",
+	".ipynb": "Jupyter Notebook",
+	".yaml":  "YAML",
+	".yml":   "YAML",
+	".json":  "JSON",
+}
+
+// detectLanguageFromPath はファイルパスの拡張子から言語名を判定します。
+// 対応表にない拡張子はその拡張子（例: ".proto"）、拡張子がなければ "Other" を返します。
+func detectLanguageFromPath(filePath string) string {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if ext == "" {
+		return languageOther
+	}
+	if lang, ok := languageByExtension[ext]; ok {
+		return lang
+	}
+	return ext
+}
+
+// buildLanguageStats はファイル別統計を言語ごとに集約し、sortKey に従って並べ替えます（同値は言語名順）。
+func buildLanguageStats(byFile map[string]*tracker.FileStats, sortKey string) []tracker.LanguageStats {
+	grouped := make(map[string]*tracker.LanguageStats)
+	for filePath, s := range byFile {
+		lang := detectLanguageFromPath(filePath)
+		l, exists := grouped[lang]
+		if !exists {
+			l = &tracker.LanguageStats{Language: lang}
+			grouped[lang] = l
+		}
+		l.Files++
+		l.AILines += s.AILines
+		l.HumanLines += s.HumanLines
+		l.TotalLines += s.TotalLines
+	}
+
+	stats := make([]tracker.LanguageStats, 0, len(grouped))
+	for _, l := range grouped {
+		l.Percentage = metrics.SafePercent(l.AILines, l.TotalLines)
+		stats = append(stats, *l)
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if sortKey == fileSortAI && a.Percentage != b.Percentage {
+			return a.Percentage > b.Percentage
+		}
+		if a.TotalLines != b.TotalLines {
+			return a.TotalLines > b.TotalLines
+		}
+		return a.Language < b.Language
+	})
+	return stats
+}
+
+// printLanguageStats は言語別の内訳をテーブル形式で出力します。
+func printLanguageStats(stats []tracker.LanguageStats) {
+	fmt.Println("By Language:")
+	fmt.Printf("  %-16s %6s %8s %8s %8s %7s\n", "Language", "Files", "Lines", "AI", "Human", "AI%")
+	for _, l := range stats {
+		fmt.Printf("  %-16s %6d %8d %8d %8d %6.1f%%\n", l.Language, l.Files, l.TotalLines, l.AILines, l.HumanLines, l.Percentage)
+	}
+	fmt.Println()
+}
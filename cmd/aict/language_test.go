@@ -0,0 +1,54 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestDetectLanguageFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"main.go", "Go"},
+		{"web/src/App.TSX", "TypeScript"},
+		{"scripts/build.sh", "Shell"},
+		{"api/service.proto", ".proto"},
+		{"Makefile", languageOther},
+	}
+
+	for _, tt := range tests {
+		if got := detectLanguageFromPath(tt.path); got != tt.want {
+			t.Errorf("detectLanguageFromPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestBuildLanguageStats(t *testing.T) {
+	byFile := map[string]*tracker.FileStats{
+		"main.go":        {Path: "main.go", TotalLines: 30, AILines: 10, HumanLines: 20},
+		"internal/a.go":  {Path: "internal/a.go", TotalLines: 10, AILines: 10, HumanLines: 0},
+		"web/app.ts":     {Path: "web/app.ts", TotalLines: 20, AILines: 20, HumanLines: 0},
+		"scripts/run.py": {Path: "scripts/run.py", TotalLines: 5, AILines: 0, HumanLines: 5},
+	}
+
+	got := buildLanguageStats(byFile, fileSortLines)
+	want := []tracker.LanguageStats{
+		{Language: "Go", Files: 2, TotalLines: 40, AILines: 20, HumanLines: 20, Percentage: 50},
+		{Language: "TypeScript", Files: 1, TotalLines: 20, AILines: 20, HumanLines: 0, Percentage: 100},
+		{Language: "Python", Files: 1, TotalLines: 5, AILines: 0, HumanLines: 5, Percentage: 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildLanguageStats(lines) =\n%+v\nwant\n%+v", got, want)
+	}
+
+	var order []string
+	for _, l := range buildLanguageStats(byFile, fileSortAI) {
+		order = append(order, l.Language)
+	}
+	if wantOrder := []string{"TypeScript", "Go", "Python"}; !reflect.DeepEqual(order, wantOrder) {
+		t.Errorf("buildLanguageStats(ai) order = %v, want %v", order, wantOrder)
+	}
+}
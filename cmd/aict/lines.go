@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/linecount"
+)
+
+// テキストエンコーディングの判定結果
+const (
+	encodingUTF8    = "utf-8"
+	encodingUTF16LE = "utf-16le"
+	encodingUTF16BE = "utf-16be"
+	encodingLegacy  = "non-utf-8" // Shift-JIS・EUC-JP・Latin-1 等（ASCII互換のため改行はバイトで判定できる）
+	encodingBinary  = "binary"
+)
+
+// encodingSniffLen はエンコーディング判定に使う先頭バイト数（git のバイナリ判定と同じ）
+const encodingSniffLen = 8000
+
+// detectTextEncoding はファイル内容のエンコーディングを推定します。
+// BOMでUTF-16を判定し、それ以外でNULを含む場合はバイナリとみなします。
+// 不正なUTF-8バイト列は Shift-JIS 等のASCII互換エンコーディングとして扱います。
+func detectTextEncoding(content []byte) string {
+	switch {
+	case bytes.HasPrefix(content, []byte{0xEF, 0xBB, 0xBF}):
+		return encodingUTF8
+	case bytes.HasPrefix(content, []byte{0xFF, 0xFE}):
+		return encodingUTF16LE
+	case bytes.HasPrefix(content, []byte{0xFE, 0xFF}):
+		return encodingUTF16BE
+	}
+
+	head := content
+	if len(head) > encodingSniffLen {
+		head = head[:encodingSniffLen]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return encodingBinary
+	}
+	if utf8.Valid(content) {
+		return encodingUTF8
+	}
+	return encodingLegacy
+}
+
+// countLines はファイル内容の行数を返します。
+// 改行コードは LF・CRLF・CR のいずれも1行として数え、末尾に改行のない最終行も1行とします
+// （git diff --numstat と同じ数え方。空ファイルは0行）。
+// UTF-16 はコード単位で改行を判定し、上位バイトが 0x0A の文字を改行と誤認しないようにします。
+func countLines(content []byte) int {
+	enc := detectTextEncoding(content)
+	if enc == encodingUTF16LE || enc == encodingUTF16BE {
+		return countUTF16Lines(content[2:], enc == encodingUTF16BE)
+	}
+
+	lines := 0
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '\n':
+			lines++
+		case '\r':
+			if i+1 < len(content) && content[i+1] == '\n' {
+				continue // CRLF は LF 側で数える
+			}
+			lines++
+		}
+	}
+	if n := len(content); n > 0 && content[n-1] != '\n' && content[n-1] != '\r' {
+		lines++
+	}
+	return lines
+}
+
+// countUTF16Lines はBOMを除いたUTF-16データの行数を返します。
+func countUTF16Lines(data []byte, bigEndian bool) int {
+	lines := 0
+	var last uint16
+	for i := 0; i+1 < len(data); i += 2 {
+		unit := uint16(data[i]) | uint16(data[i+1])<<8
+		if bigEndian {
+			unit = uint16(data[i])<<8 | uint16(data[i+1])
+		}
+		if unit == '\n' || (unit == '\r' && !nextUTF16UnitIs(data, i+2, '\n', bigEndian)) {
+			lines++
+		}
+		last = unit
+	}
+	if len(data) >= 2 && last != '\n' && last != '\r' {
+		lines++
+	}
+	return lines
+}
+
+func nextUTF16UnitIs(data []byte, i int, want uint16, bigEndian bool) bool {
+	if i+1 >= len(data) {
+		return false
+	}
+	if bigEndian {
+		return uint16(data[i])<<8|uint16(data[i+1]) == want
+	}
+	return uint16(data[i])|uint16(data[i+1])<<8 == want
+}
+
+// splitLines は改行コード（LF・CRLF・CR）を正規化して行に分割します。
+// 作業ツリー（core.autocrlf で CRLF）とHEAD（LF）の比較で全行が変更扱いにならないようにします。
+func splitLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// stripLicenseHeaders は物理行で数えるファイルの先頭のライセンスヘッダーを集計から除くかです。
+// 設定の include_license_headers が true の場合に loadStorageAndConfig が false にします。
+var stripLicenseHeaders = true
+
+// rawLineExtensions は行カウンタを使わず物理行で数える拡張子です。
+// 設定の docs に含まれる拡張子（.md 等）は本文も集計対象のため、loadStorageAndConfig が設定します。
+var rawLineExtensions []string
+
+// lineCounterFor はファイルに使う行カウンタを返します。物理行で数える場合は nil を返します。
+func lineCounterFor(path string) linecount.Counter {
+	for _, ext := range rawLineExtensions {
+		if strings.HasSuffix(path, ext) {
+			return nil
+		}
+	}
+	return linecount.For(path)
+}
+
+// countContentLines はファイルの集計対象行数を返します。
+// 拡張子にCounterが登録されている場合（.ipynb のコードセル、.md のコードブロック等）はその行数、
+// それ以外や解析に失敗した場合は countLines の物理行数を返します。
+// インラインマーカー（aict:ignore-start / aict:ignore-end、aict:generated）で除外された行と、
+// 物理行で数える場合の先頭のライセンスヘッダーは数えません。
+func countContentLines(path string, content []byte) int {
+	if lines, ok := countedLines(path, content); ok {
+		return len(lines)
+	}
+	return countLines(content)
+}
+
+// countedLines はCounterで抽出した行（Counterがない・解析に失敗した場合は物理行）から、
+// インラインマーカーで除外された行を取り除いて返します。物理行の場合は先頭のライセンスヘッダーも取り除きます。
+// Counter・マーカー・ライセンスヘッダーのいずれも該当しない場合は ok=false。
+func countedLines(path string, content []byte) ([]string, bool) {
+	lines, ok := counterLines(path, content)
+	marked := linecount.HasMarkers(content)
+	licensed := !ok && hasLicenseHeader(content)
+	if !ok {
+		if !marked && !licensed {
+			return nil, false
+		}
+		lines = splitLines(string(content))
+	}
+	if licensed {
+		lines = linecount.StripLicenseHeader(lines)
+	}
+	if marked {
+		lines = linecount.StripMarked(lines)
+	}
+	return lines, true
+}
+
+// hasLicenseHeader はライセンスヘッダーの除外が有効で、内容の先頭にライセンスヘッダーがある可能性があるかを返します。
+func hasLicenseHeader(content []byte) bool {
+	return stripLicenseHeaders && linecount.HasLicenseHeader(content)
+}
+
+// counterLines は登録済みCounterで集計対象の行を抽出します。Counterがない・解析に失敗した場合は ok=false。
+func counterLines(path string, content []byte) ([]string, bool) {
+	counter := lineCounterFor(path)
+	if counter == nil {
+		return nil, false
+	}
+	lines, err := counter.Lines(content)
+	if err != nil {
+		debugf("line counter for %s: %v (falling back to raw lines)", path, err)
+		return nil, false
+	}
+	return lines, true
+}
+
+// countedDiffStat は新旧の内容を countedLines で抽出した行同士で比較し、追加・削除行数を返します。
+// Counter・マーカー・ライセンスヘッダーのいずれも該当しない場合やCounterで解析できない場合は ok=false を返し、
+// 呼び出し元は通常のdiffを使います。
+// 存在しない側（新規・削除ファイル）は nil を渡します。
+func countedDiffStat(path string, oldContent, newContent []byte) (added, deleted int, ok bool) {
+	hasCounter := lineCounterFor(path) != nil
+	if !hasCounter && !needsLineFilter(oldContent) && !needsLineFilter(newContent) {
+		return 0, 0, false
+	}
+	sides := [2][]string{}
+	for i, content := range [2][]byte{oldContent, newContent} {
+		if content == nil {
+			continue
+		}
+		lines, ok := countedLines(path, content)
+		if !ok {
+			if hasCounter {
+				return 0, 0, false
+			}
+			lines = splitLines(string(content)) // マーカー・ライセンスヘッダーがあるのはもう一方の内容のみ
+		}
+		sides[i] = lines
+	}
+	added, deleted = linecount.DiffStat(sides[0], sides[1])
+	return added, deleted, true
+}
+
+// needsLineFilter は内容にインラインマーカーまたはライセンスヘッダーがある可能性があるかを返します。
+func needsLineFilter(content []byte) bool {
+	return linecount.HasMarkers(content) || hasLicenseHeader(content)
+}
+
+// applyLineCounters はCounterが登録されたファイルと、インラインマーカー・ライセンスヘッダーを含むファイルのnumstatを、
+// コミットと第1親の内容から再計算した値で置き換えます。
+// include が nil でない場合は、include が true を返すファイルのみ再計算します（不要なgit呼び出しを避けるため）。
+func applyLineCounters(numstatMap map[string][2]int, commit string, include func(string) bool) {
+	executor := newExecutor()
+	var paths []string
+	for path := range numstatMap {
+		if include == nil || include(path) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	marked := filesToRecount(executor, commit, paths)
+
+	for _, path := range paths {
+		if lineCounterFor(path) == nil && !marked[path] {
+			continue
+		}
+		var oldContent, newContent []byte
+		if out, err := executor.Run("show", commit+"^:"+path); err == nil {
+			oldContent = []byte(out)
+		}
+		if out, err := executor.Run("show", commit+":"+path); err == nil {
+			newContent = []byte(out)
+		}
+		if added, deleted, ok := countedDiffStat(path, oldContent, newContent); ok {
+			numstatMap[path] = [2]int{added, deleted}
+		}
+	}
+}
+
+// maxMarkerPathspecs は filesToRecount がパスを引数で渡す上限です（超える場合はツリー全体を検索して絞り込む）。
+const maxMarkerPathspecs = 500
+
+// filesToRecount はコミットまたは第1親の内容にインラインマーカーまたはライセンスのキーワードを含むファイルを返します。
+// 変更ファイルごとに内容を読まずに済むよう、リビジョンごとに1回の git grep で判定します
+// （キーワードがヘッダー以外にあるファイルも含むため、最終的な判定は countedDiffStat が行う）。
+func filesToRecount(executor gitexec.Executor, commit string, paths []string) map[string]bool {
+	marked := make(map[string]bool)
+	if len(paths) == 0 {
+		return marked
+	}
+	wanted := make(map[string]bool, len(paths))
+	var pathspecs []string
+	for _, path := range paths {
+		wanted[path] = true
+		if len(paths) <= maxMarkerPathspecs {
+			pathspecs = append(pathspecs, ":(literal)"+path)
+		}
+	}
+
+	patterns := []string{"-e", linecount.IgnoreStartMarker, "-e", linecount.GeneratedMarker}
+	if stripLicenseHeaders {
+		for _, kw := range linecount.LicenseKeywords {
+			patterns = append(patterns, "-e", kw)
+		}
+	}
+
+	for _, rev := range []string{commit, commit + "^"} {
+		args := append([]string{"grep", "-l", "-z", "-I", "-i", "-F"}, patterns...)
+		args = append(args, rev, "--")
+		// 一致なし（終了コード1）や親のないコミットはエラーになるため、マーカーなしとして扱う
+		output, err := executor.Run(append(args, pathspecs...)...)
+		if err != nil {
+			continue
+		}
+		for _, entry := range strings.Split(output, "\x00") {
+			path := strings.TrimPrefix(entry, rev+":")
+			if wanted[path] {
+				marked[path] = true
+			}
+		}
+	}
+	return marked
+}
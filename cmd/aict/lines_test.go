@@ -0,0 +1,336 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+// 混在エンコーディングのフィクスチャ
+const (
+	// Shift-JIS の "// 日本語\n" と "x := 1\n"
+	fixtureShiftJIS = "// \x93\xfa\x96\x7b\x8c\xea\nx := 1\n"
+	// UTF-16LE（BOM付き）の "a\r\nb\r\n"
+	fixtureUTF16LE = "\xff\xfea\x00\r\x00\n\x00b\x00\r\x00\n\x00"
+	// UTF-16BE（BOM付き）の "a\nb"
+	fixtureUTF16BE = "\xfe\xff\x00a\x00\n\x00b"
+	// UTF-16LE の U+0A0D（上位バイトが 0x0A の文字）1文字のみ
+	fixtureUTF16Gurmukhi = "\xff\xfe\x0d\x0a"
+)
+
+func TestDetectTextEncoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"ascii", "package main\n", encodingUTF8},
+		{"utf-8 japanese", "// 日本語\n", encodingUTF8},
+		{"utf-8 bom", "\xef\xbb\xbfpackage main\n", encodingUTF8},
+		{"shift-jis", fixtureShiftJIS, encodingLegacy},
+		{"utf-16le", fixtureUTF16LE, encodingUTF16LE},
+		{"utf-16be", fixtureUTF16BE, encodingUTF16BE},
+		{"binary", "\x89PNG\r\n\x1a\n\x00\x00", encodingBinary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectTextEncoding([]byte(tt.content)); got != tt.want {
+				t.Errorf("detectTextEncoding() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"empty", "", 0},
+		{"lf", "a\nb\n", 2},
+		{"crlf", "a\r\nb\r\n", 2},
+		{"cr only", "a\rb\r", 2},
+		{"mixed endings", "a\r\nb\nc\rd", 4},
+		{"no trailing newline", "a\nb", 2},
+		{"blank lines", "\n\n", 2},
+		{"shift-jis", fixtureShiftJIS, 2},
+		{"utf-16le crlf", fixtureUTF16LE, 2},
+		{"utf-16be no trailing newline", fixtureUTF16BE, 2},
+		{"utf-16 char with 0x0A byte", fixtureUTF16Gurmukhi, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countLines([]byte(tt.content)); got != tt.want {
+				t.Errorf("countLines(%q) = %d, want %d", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", nil},
+		{"a\nb\n", []string{"a", "b"}},
+		{"a\r\nb\r\n", []string{"a", "b"}},
+		{"a\rb", []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		if got := splitLines(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitLines(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+// CRLFの作業ツリーとLFのHEADで、改行コードの違いが変更行として数えられないこと
+func TestGetDetailedDiff_CRLFWorkingTree(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc main() {}\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	// core.autocrlf=true の環境で作業ツリーがCRLFになり、同じ行数で1行だけ変更した状態
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\r\n\r\nfunc main() { run() }\r\n")
+
+	added, deleted, _, err := getDetailedDiff("main.go")
+	if err != nil {
+		t.Fatalf("getDetailedDiff() error = %v", err)
+	}
+	if added != 1 || deleted != 0 {
+		t.Errorf("getDetailedDiff() = +%d -%d, want +1 -0 (line endings must not count as changes)", added, deleted)
+	}
+}
+
+// Shift-JIS・UTF-16・CRLFのファイルが混在してもチェックポイント→コミット→レポートが完走すること
+func TestPipeline_MixedEncodingFiles(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "sjis.go", fixtureShiftJIS)
+	testutil.CreateTestFile(t, tmpDir, "utf16.go", fixtureUTF16LE)
+	testutil.CreateTestFile(t, tmpDir, "crlf.go", "package main\r\n\r\nfunc crlf() {}\r\n")
+	if err := recordCheckpoint("Claude", "", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+
+	store, _, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	checkpoints, err := store.LoadCheckpoints()
+	if err != nil {
+		t.Fatalf("LoadCheckpoints() error = %v", err)
+	}
+	last := checkpoints[len(checkpoints)-1]
+	for path, want := range map[string]int{"sjis.go": 2, "utf16.go": 2, "crlf.go": 3} {
+		if got := last.Changes[path].Added; got != want {
+			t.Errorf("%s: added = %d, want %d", path, got, want)
+		}
+	}
+
+	testutil.GitCommit(t, tmpDir, "Add mixed encoding files")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	report, _, err := generateRangeReport(&ReportOptions{Range: "HEAD~1..HEAD", ByFile: true})
+	if err != nil {
+		t.Fatalf("generateRangeReport() error = %v", err)
+	}
+	if report == nil || report.Summary.AILines == 0 {
+		t.Errorf("expected AI lines for mixed encoding files, got %+v", report)
+	}
+}
+
+func TestPipeline_LineCounters(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	cfg.TrackedExtensions = append(cfg.TrackedExtensions, ".ipynb", ".md")
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+
+	// コードセル2行 + 出力・Markdownセル / コードブロック1行 + 本文
+	testutil.CreateTestFile(t, tmpDir, "analysis.ipynb", `{"cells": [
+ {"cell_type": "markdown", "source": ["# Analysis\n", "notes\n"]},
+ {"cell_type": "code", "source": ["import os\n", "print(os.getcwd())\n"], "outputs": [{"text": ["/tmp\n"]}]}
+], "nbformat": 4}
+`)
+	testutil.CreateTestFile(t, tmpDir, "README.md", "# Title\n\nSome prose.\n\n```sh\nmake build\n```\n")
+	if err := recordCheckpoint("Claude", "", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+
+	checkpoints, err := store.LoadCheckpoints()
+	if err != nil {
+		t.Fatalf("LoadCheckpoints() error = %v", err)
+	}
+	last := checkpoints[len(checkpoints)-1]
+	for path, want := range map[string]int{"analysis.ipynb": 2, "README.md": 1} {
+		if got := last.Changes[path].Added; got != want {
+			t.Errorf("%s: checkpoint added = %d, want %d", path, got, want)
+		}
+	}
+
+	commit := testutil.GitCommit(t, tmpDir, "Add notebook and readme")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	numstatMap := map[string][2]int{"analysis.ipynb": {5, 0}, "README.md": {7, 0}, "base.go": {1, 0}}
+	applyLineCounters(numstatMap, commit, nil)
+	want := map[string][2]int{"analysis.ipynb": {2, 0}, "README.md": {1, 0}, "base.go": {1, 0}}
+	if !reflect.DeepEqual(numstatMap, want) {
+		t.Errorf("applyLineCounters() = %v, want %v", numstatMap, want)
+	}
+
+	report, _, err := generateRangeReport(&ReportOptions{Range: "HEAD~1..HEAD"})
+	if err != nil {
+		t.Fatalf("generateRangeReport() error = %v", err)
+	}
+	if report == nil || report.Summary.AILines != 3 {
+		t.Errorf("expected 3 AI lines (notebook code + markdown code block), got %+v", report)
+	}
+}
+
+func TestPipeline_InlineMarkers(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+
+	// 追加6行のうち、ignoreブロック4行と aict:generated の1行を除いた1行のみを数える
+	testutil.CreateTestFile(t, tmpDir, "vendored.go", "package main\n// aict:ignore-start\nfunc a() {}\nfunc b() {}\n// aict:ignore-end\nvar table = load() // aict:generated\n")
+	if err := recordCheckpoint("Claude", "", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+
+	store, _, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	checkpoints, err := store.LoadCheckpoints()
+	if err != nil {
+		t.Fatalf("LoadCheckpoints() error = %v", err)
+	}
+	if got := checkpoints[len(checkpoints)-1].Changes["vendored.go"].Added; got != 1 {
+		t.Errorf("checkpoint added = %d, want 1", got)
+	}
+
+	commit := testutil.GitCommit(t, tmpDir, "Add vendored code")
+	numstatMap := map[string][2]int{"vendored.go": {6, 0}}
+	applyLineCounters(numstatMap, commit, nil)
+	if got := numstatMap["vendored.go"]; got != [2]int{1, 0} {
+		t.Errorf("applyLineCounters() = %v, want [1 0]", got)
+	}
+
+	// マーカーを外すとブロック内の行は追加として数え、マーカー行は削除として数えない
+	testutil.CreateTestFile(t, tmpDir, "vendored.go", "package main\nfunc a() {}\nfunc b() {}\nvar table = load() // aict:generated\n")
+	commit = testutil.GitCommit(t, tmpDir, "Unvendor")
+	numstatMap = map[string][2]int{"vendored.go": {0, 2}, "base.go": {1, 0}}
+	applyLineCounters(numstatMap, commit, nil)
+	want := map[string][2]int{"vendored.go": {2, 0}, "base.go": {1, 0}}
+	if !reflect.DeepEqual(numstatMap, want) {
+		t.Errorf("applyLineCounters() = %v, want %v", numstatMap, want)
+	}
+}
+
+func TestPipeline_LicenseHeaders(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	defer func() { stripLicenseHeaders = true }()
+
+	header := "// Copyright 2025 Example Inc.\n// SPDX-License-Identifier: Apache-2.0\n\n"
+	testutil.CreateTestFile(t, tmpDir, "a.go", header+"package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	// 年の更新だけのコミットは数えない、新規ファイルはヘッダーを除いた行数
+	testutil.CreateTestFile(t, tmpDir, "a.go", "// Copyright 2026 Example Inc.\n// SPDX-License-Identifier: Apache-2.0\n\npackage main\n")
+	testutil.CreateTestFile(t, tmpDir, "b.go", header+"package main\n\nfunc b() {}\n")
+	commit := testutil.GitCommit(t, tmpDir, "Bump year and add b")
+
+	tests := []struct {
+		name    string
+		include bool
+		want    map[string][2]int
+	}{
+		{"headers excluded", false, map[string][2]int{"a.go": {0, 0}, "b.go": {3, 0}}},
+		{"include_license_headers", true, map[string][2]int{"a.go": {1, 1}, "b.go": {6, 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, cfg, err := loadStorageAndConfig()
+			if err != nil {
+				t.Fatalf("loadStorageAndConfig() error = %v", err)
+			}
+			cfg.IncludeLicenseHeaders = tt.include
+			if err := store.SaveConfig(cfg); err != nil {
+				t.Fatalf("SaveConfig() error = %v", err)
+			}
+			if _, _, err := loadStorageAndConfig(); err != nil {
+				t.Fatalf("loadStorageAndConfig() error = %v", err)
+			}
+
+			numstatMap := map[string][2]int{"a.go": {1, 1}, "b.go": {6, 0}}
+			applyLineCounters(numstatMap, commit, nil)
+			if !reflect.DeepEqual(numstatMap, tt.want) {
+				t.Errorf("applyLineCounters() = %v, want %v", numstatMap, tt.want)
+			}
+		})
+	}
+
+	stripLicenseHeaders = true
+	if got := countContentLines("b.go", []byte(header+"package main\n")); got != 1 {
+		t.Errorf("countContentLines() = %d, want 1 (package clause only)", got)
+	}
+}
@@ -1,8 +1,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"runtime/debug"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/git"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/provenance"
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
 )
 
 const version = "1.5.1-beta.1"
@@ -28,7 +35,48 @@ func main() {
 
 	command := os.Args[1]
 
-	var err error
+	// コンテナ等で所有者の異なるリポジトリを扱う場合（オプトイン）
+	if os.Getenv(gitexec.SafeDirectoryEnv) != "" {
+		if err := gitexec.TrustDirectory(repoDirForSafeDirectory()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", gitexec.SafeDirectoryEnv, err)
+		}
+	}
+
+	// 1コマンド実行内でgitの参照系クエリ結果を共有（同一クエリは1回のみ実行）
+	cached := git.NewCachingExecutor(gitexec.NewExecutor())
+	newExecutor = func() gitexec.Executor { return cached }
+
+	err := runCommand(command)
+	if err == errUnknownCommand {
+		fmt.Printf("Unknown command: %s\n", command)
+		printUsage()
+		exitFunc(1)
+	}
+
+	restoreRepoOwnership()
+	recordTelemetry(command, err)
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if gitexec.IsDubiousOwnership(err) {
+			fmt.Fprintf(os.Stderr, "Hint: %s\n", gitexec.DubiousOwnershipHint(repoDirForSafeDirectory()))
+		}
+		exitFunc(1)
+	}
+}
+
+// errUnknownCommand は runCommand が未知のコマンドを受け取ったことを示します。
+var errUnknownCommand = errors.New("unknown command")
+
+// runCommand はコマンドを実行します。
+// パニックはここで捕捉してローカルにクラッシュレポートを残します（別goroutineのパニックは対象外）。
+func runCommand(command string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			handleCrash(command, r, debug.Stack())
+		}
+	}()
+
 	switch command {
 	case "init":
 		withHooks := len(os.Args) > 2 && os.Args[2] == "--with-hooks"
@@ -39,25 +87,103 @@ func main() {
 		err = handleCommit()
 	case "report":
 		err = handleRangeReport()
+	case "status":
+		err = handleStatus()
+	case "whoami":
+		err = handleWhoami()
 	case "sync":
 		err = handleSync()
+	case "telemetry":
+		err = handleTelemetry()
+	case "notes":
+		err = handleNotes()
+	case "import":
+		err = handleImport()
+	case "baseline":
+		err = handleBaseline()
 	case "setup-hooks":
 		err = handleSetupHooksV2()
+	case "hook":
+		err = handleHook()
+	case "hooks":
+		err = handleHooks()
 	case "debug":
 		err = handleDebug()
+	case "config":
+		err = handleConfig()
+	case "secret":
+		err = handleSecret()
+	case "audit-metrics":
+		err = handleAuditMetrics()
+	case "ownership":
+		err = handleOwnership()
+	case "reconcile":
+		err = handleReconcile()
+	case "aggregate":
+		err = handleAggregate()
+	case "grep-ai":
+		err = handleGrepAI()
+	case "why":
+		err = handleWhy()
+	case "blame":
+		err = handleBlame()
+	case "query":
+		err = handleQuery()
+	case "churn":
+		err = handleChurn()
+	case "check":
+		err = handleCheck()
+	case "ci":
+		err = handleCI()
+	case "reclassify":
+		err = handleReclassify()
+	case "badge":
+		err = handleBadge()
+	case "backstage-metadata":
+		err = handleBackstageMetadata()
+	case "uninstall":
+		err = handleUninstall()
+	case "completion":
+		err = handleCompletion()
+	case "__complete":
+		err = handleCompleteCandidates()
+	case "verify-setup":
+		err = handleVerifySetup()
+	case "daemon":
+		err = handleDaemon()
 	case "version", "--version", "-v":
-		fmt.Printf("AI Code Tracker (aict) version %s\n", version)
+		printVersion(len(os.Args) > 2 && os.Args[2] == "--verbose")
+	case "verify-binary":
+		err = handleVerifyBinary()
+	case "fsck":
+		err = handleFsck()
 	case "help", "--help", "-h":
 		printUsage()
 	default:
-		fmt.Printf("Unknown command: %s\n", command)
-		printUsage()
-		exitFunc(1)
+		err = errUnknownCommand
 	}
+	return err
+}
 
+// repoDirForSafeDirectory は safe.directory に指定するリポジトリのディレクトリを返します。
+// git はこの状態で rev-parse も拒否するため、.git を探して作業ツリーのルートを求めます（bareリポジトリ等では現在のディレクトリ）。
+func repoDirForSafeDirectory() string {
+	if root := storage.FindWorkTree(); root != "" {
+		return root
+	}
+	dir, _ := os.Getwd()
+	return dir
+}
+
+// restoreRepoOwnership はコンテナ内の root で実行した場合に、作成したデータの所有者をリポジトリの所有者に戻します。
+func restoreRepoOwnership() {
+	n, err := storage.FixOwnership()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		exitFunc(1)
+		fmt.Fprintf(os.Stderr, "Warning: could not restore file ownership: %v\n", err)
+		return
+	}
+	if n > 0 {
+		debugf("restored ownership of %d file(s) under .git", n)
 	}
 }
 
@@ -74,18 +200,101 @@ func printUsage() {
 	fmt.Println("    --author <name>            Author name (required)")
 	fmt.Println("    --model <model>            AI model name (for AI agents)")
 	fmt.Println("    --message <msg>            Optional message")
+	fmt.Println("    --weight <0-1>             Share of the changes to attribute to AI (e.g., 0.5 for pair programming)")
+	fmt.Println("  aict checkpoint migrate --to notes|file  Store checkpoints in git notes or in .git/aict")
+	fmt.Println("  aict import <file-or-dir>... Merge checkpoint files copied from other machines or branches")
+	fmt.Println("  aict import claude-transcripts [--range|--since <spec>] [--window <dur>] [--dry-run] <file-or-dir>...")
+	fmt.Println("                               Create authorship logs for past commits from Claude Code transcripts (~/.claude/projects)")
 	fmt.Println("  aict commit                  Generate Authorship Log from checkpoints")
+	fmt.Println("  aict status [--idle <dur>] [--format table|json] [--fields <paths>] [--watch <interval>]  Show tracking status (7-day AI%, records, hooks) and the Claude Code session in progress")
+	fmt.Println("  aict whoami [set <name>|clear]  Set the human author on a shared machine (AICT_WHOAMI overrides per shell)")
 	fmt.Println("  aict report [options]        Show code generation statistics")
 	fmt.Println("    --range <range>            Commit range (e.g., 'origin/main..HEAD')")
+	fmt.Println("    --commits <rev1>..<rev2>   Same as --range (e.g., 'v1.3.0..v1.4.0' for a release)")
 	fmt.Println("    --since <date>             Show commits since date (e.g., '7d', '2w', '1m')")
 	fmt.Println("    --format <format>          Output format: table or json (default: table)")
-	fmt.Println("  aict sync [push|fetch]       Sync authorship logs with remote")
-	fmt.Println("  aict setup-hooks             Setup Claude Code and Git hooks")
-	fmt.Println("  aict debug [show|clean|clear-notes]  Debug and cleanup commands")
+	fmt.Println("    --fields <paths>           JSON fields to output (e.g., 'summary.total_lines,summary.ai_percentage')")
+	fmt.Println("    --sample <pct>             Sample commits deterministically and extrapolate (e.g., '10%')")
+	fmt.Println("    --branch <name>            Branch to report on (with --since; default: HEAD)")
+	fmt.Println("    --by-author                Per-author added/deleted/AI% grouped by author_mappings")
+	fmt.Println("    --by-file, --by-dir        Per-file / per-directory AI% breakdown")
+	fmt.Println("    --by-language              Per-language AI% breakdown (detected from file extension)")
+	fmt.Println("    --context <name>           Report only on files in a named context (config \"contexts\")")
+	fmt.Println("    --depth <n>                Directory depth for --by-dir (default: 1)")
+	fmt.Println("    --sort <key>               Sort file/dir/language breakdowns by lines or ai (default: lines)")
+	fmt.Println("    --trend <period>           AI% movement per day, week or month with a sparkline")
+	fmt.Println("    --smooth <window>          Rolling average for --trend (e.g., '7d', '4w'), weighted by added lines")
+	fmt.Println("    --sizes                    Distribution of AI change sizes (p50/p90/max) and outliers")
+	fmt.Println("    --last <date>              Same as --since (e.g., '30d')")
+	fmt.Println("    --compare-to <spec>        Compare with 'previous' (same-length period before --since) or a commit range")
+	fmt.Println("    (no --range/--since)       Report commits since the latest baseline")
+	fmt.Println("  aict aggregate --repos <paths> | --repos-file <file> --since <date>|--range <spec>  Combined report across repositories")
+	fmt.Println("    --format <format>          Output format: table, json or csv (default: table)")
+	fmt.Println("  aict notes [push|fetch|sync] Sync authorship logs (Git notes) with a remote")
+	fmt.Println("    --remote <name>            Remote to use (default: origin)")
+	fmt.Println("  aict notes propagate [--range <spec>] [--dry-run]  Copy authorship logs to cherry-picked/rebased commits (matched by patch-id)")
+	fmt.Println("  aict sync [push|fetch]       Same as 'aict notes push|fetch' with origin")
+	fmt.Println("  aict baseline [create|list]  Record baseline snapshots (keeps the last baseline.retention)")
+	fmt.Println("    --label <text>             Label for the new baseline")
+	fmt.Println("    --scheduled                For cron/CI: create only when the latest baseline is older than baseline.interval_days")
+	fmt.Println("  aict setup-hooks             Setup Claude Code and Git hooks (and fetch authorship logs with 'git fetch')")
+	fmt.Println("    --push-notes               Also push authorship logs with 'git push'")
+	fmt.Println("    --settings <target>        project (.claude/settings.json, default), local (.claude/settings.local.json) or a file path")
+	fmt.Println("    --ci                       Non-interactive, idempotent install for CI containers (no 'aict init' needed)")
+	fmt.Println("  aict hook <event>            Run hook logic (pre-tool-use, post-tool-use, post-commit; called by hooks)")
+	fmt.Println("  aict hooks [status|repair]   Check installed hooks against this version / rewrite stale or duplicated entries")
+	fmt.Println("  aict debug [show|clean|clear-notes|health|redactions]  Debug, cleanup and tracker health commands")
 	fmt.Println("    show                       Display all checkpoint details")
 	fmt.Println("    clean                      Remove all checkpoint data")
 	fmt.Println("    clear-notes                Remove all Git notes (authorship logs)")
-	fmt.Println("  aict version                 Show version information")
+	fmt.Println("  aict fsck [--repair]         Validate .git/aict record files; --repair moves corrupt lines to .git/aict/quarantine/")
+	fmt.Println("  aict config [options]        Edit .git/aict/config.yaml (or config.json) in $VISUAL/$EDITOR")
+	fmt.Println("    --no-edit                  Print the current config")
+	fmt.Println("    --stdin                    Validate and save config JSON or YAML read from stdin")
+	fmt.Println("  aict config get <key>        Print a config value (dotted keys, e.g. events.webhooks)")
+	fmt.Println("  aict config set <key> <value>...  Set a config value (lists take multiple values)")
+	fmt.Println("    --add / --remove           Append to / remove from a list key (e.g. tracked_extensions)")
+	fmt.Println("  aict config validate [file]  Check config syntax, unknown keys and values")
+	fmt.Println("  aict config migrate          Convert config.json to config.yaml (keeps config.json.bak)")
+	fmt.Println("  aict secret [set|delete|check] <name>  Manage secrets referenced in config as ${secret:name}")
+	fmt.Println("    set                        Store the value read from stdin in the OS keychain")
+	fmt.Println("    check                      Verify secrets resolve (keychain, then AICT_SECRET_<NAME>)")
+	fmt.Println("  aict audit-metrics [--fix]   Recompute daily rollups from authorship logs and report (or repair) mismatches")
+	fmt.Println("  aict ownership [--rev <rev>] [--output <file>] [--fields <paths>] [<path>...]  Export per-line-range ai/human ownership as JSON")
+	fmt.Println("    --since <date>|all         Only lines committed since the date (default: adoption_date in config)")
+	fmt.Println("  aict ownership --top <n> | --by-dir [--depth <n>] [--sort ai|lines]  Show AI-generated code hotspots by file or directory")
+	fmt.Println("  aict reconcile --from <branch> [--commit <rev>] [--dry-run] [--force]  Carry authorship over to a squash-merged or rebased commit")
+	fmt.Println("  aict grep-ai [-i] [-F] <pattern> [<path>...]  Search AI-authored lines only (grep -n format)")
+	fmt.Println("  aict why <file>:<line>    Explain how a line was attributed (blame commit, note, author rule, checkpoints)")
+	fmt.Println("  aict blame [--rev <rev>] [--format text|html] [--output <file>] <file>  Annotate a file with per-line AI/human authorship")
+	fmt.Println("  aict query [--format json|csv] [--fields <names>] <expression>  Filter daily rollup and checkpoint records")
+	fmt.Println("  aict churn [options]         Added/deleted/net lines by AI vs human per period, and AI line survival")
+	fmt.Println("    --range/--since            Commits to analyze (default: --since 90d)")
+	fmt.Println("    --period <p>               day, week or month (default: week)")
+	fmt.Println("    --survival-days <n>        Share of AI-added lines still present n days later (default: 30, 0 = skip)")
+	fmt.Println("    --survival-sample <n>      Maximum commits to check with git blame (default: 50)")
+	fmt.Println("    --format table|json, --fields <paths>  Output format (--fields: JSON fields to output)")
+	fmt.Println("  aict check [options]         Fail when the AI percentage violates a threshold (CI gate)")
+	fmt.Println("    --range/--since            Commits to check")
+	fmt.Println("    --min-ai, --max-ai <pct>   Thresholds (default: --min-ai = target_ai_percentage)")
+	fmt.Println("    --annotate-files <pct>     Warn about each file at or above this AI% (GitHub file annotations)")
+	fmt.Println("  aict ci [options]            CI run: init if needed, fetch logs, detect the PR/push range, write job summary and outputs")
+	fmt.Println("    --config <file>            Config file to use (e.g., one committed to the repository)")
+	fmt.Println("    --min-ai, --max-ai <pct>   Optional thresholds (no threshold: report only)")
+	fmt.Println("  aict reclassify --to ai|human [filters] [--apply]  Reclassify recorded authors (preview by default)")
+	fmt.Println("    --author, --tool, --since, --until, --branch, --range  Select Authorship Log entries")
+	fmt.Println("  aict badge [options]         Render an SVG badge of the AI percentage (--since, --output, --label)")
+	fmt.Println("  aict backstage-metadata [options]  Print AI% metadata for Backstage (--since, --format yaml|json, --fields, --write)")
+	fmt.Println("  aict uninstall [--purge]     Remove hooks and Claude Code settings (--purge also deletes data)")
+	fmt.Println("  aict completion [bash|zsh]   Print shell completion script (authors/branches completed dynamically)")
+	fmt.Println("  aict telemetry [on|off|status]  Opt-in anonymous usage counts (command names and error classes only)")
+	fmt.Println("    --endpoint <url>           (on) POST the aggregated counts to this URL once a day")
+	fmt.Println("  aict verify-setup            Verify hooks and run an end-to-end tracked edit")
+	fmt.Println("  aict daemon [start|stop|status]  Keep report results in memory for fast repeated reports")
+	fmt.Println("  aict version [--verbose]     Show version information (--verbose: build commit, Go version, modules checksum)")
+	fmt.Println("  aict verify-binary --checksums <file|url> [--signature <file|url> --public-key <file>]")
+	fmt.Println("                               Verify this binary against the release's published SHA256SUMS")
+	fmt.Println("    --format text|json, --fields <paths>  Output format (--fields: JSON fields to output)")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  aict init")
@@ -95,10 +304,43 @@ func printUsage() {
 	fmt.Println("  aict report --since 7d        # 7 days ago")
 	fmt.Println("  aict report --since 2w        # 2 weeks ago")
 	fmt.Println("  aict report --since yesterday")
-	fmt.Println("  aict sync push")
+	fmt.Println("  aict query 'author~\"claude*\" and branch~\"feature/*\" and added>100 since 30d'")
+	fmt.Println("  aict notes sync")
+	fmt.Println("  aict baseline create --scheduled  # Run daily from cron/CI; snapshots weekly by default")
+	fmt.Println("  aict hooks repair             # After upgrading aict")
+	fmt.Println("  aict verify-setup")
 	fmt.Println("  aict debug show               # Show checkpoint details")
 	fmt.Println("  aict debug clean              # Clean checkpoints")
 	fmt.Println("  aict debug clear-notes        # Clear Git notes")
+	fmt.Println("  aict debug health             # Show tracker health metrics")
+	fmt.Println("  aict debug redactions         # Show paths/messages hidden by the redaction config")
+}
+
+// printVersion はバージョンを表示します。verbose の場合はバイナリに埋め込まれたビルド情報も表示します。
+func printVersion(verbose bool) {
+	fmt.Printf("AI Code Tracker (aict) version %s\n", version)
+	if !verbose {
+		return
+	}
+	info, err := provenance.Current()
+	if err != nil {
+		fmt.Printf("  %v\n", err)
+		return
+	}
+	commit := info.Commit
+	if commit == "" {
+		commit = "(unknown)"
+	}
+	fmt.Printf("  Commit:   %s", commit)
+	if info.CommitTime != "" {
+		fmt.Printf(" (%s)", info.CommitTime)
+	}
+	if info.Modified {
+		fmt.Print(" with uncommitted changes")
+	}
+	fmt.Println()
+	fmt.Printf("  Go:       %s %s (trimpath=%t, CGO_ENABLED=%s)\n", info.GoVersion, info.Target, info.Trimpath, info.CGOEnabled)
+	fmt.Printf("  Modules:  sha256 %s\n", info.ModulesHash)
 }
 
 func getGitUserName() string {
@@ -98,9 +98,11 @@ func TestGetGitUserName_Error(t *testing.T) {
 func TestMainCommand_Version(t *testing.T) {
 	origArgs := os.Args
 	origExit := exitFunc
+	origExecutor := newExecutor
 	defer func() {
 		os.Args = origArgs
 		exitFunc = origExit
+		newExecutor = origExecutor
 	}()
 
 	os.Args = []string{"aict", "version"}
@@ -113,9 +115,11 @@ func TestMainCommand_Version(t *testing.T) {
 func TestMainCommand_Help(t *testing.T) {
 	origArgs := os.Args
 	origExit := exitFunc
+	origExecutor := newExecutor
 	defer func() {
 		os.Args = origArgs
 		exitFunc = origExit
+		newExecutor = origExecutor
 	}()
 
 	os.Args = []string{"aict", "help"}
@@ -130,9 +134,11 @@ type exitPanic int
 func TestMainCommand_NoArgs(t *testing.T) {
 	origArgs := os.Args
 	origExit := exitFunc
+	origExecutor := newExecutor
 	defer func() {
 		os.Args = origArgs
 		exitFunc = origExit
+		newExecutor = origExecutor
 	}()
 
 	exitCode := -1
@@ -155,9 +161,11 @@ func TestMainCommand_NoArgs(t *testing.T) {
 func TestMainCommand_Unknown(t *testing.T) {
 	origArgs := os.Args
 	origExit := exitFunc
+	origExecutor := newExecutor
 	defer func() {
 		os.Args = origArgs
 		exitFunc = origExit
+		newExecutor = origExecutor
 	}()
 
 	exitCode := -1
@@ -180,9 +188,11 @@ func TestMainCommand_Unknown(t *testing.T) {
 func TestMainCommand_SyncError(t *testing.T) {
 	origArgs := os.Args
 	origExit := exitFunc
+	origExecutor := newExecutor
 	defer func() {
 		os.Args = origArgs
 		exitFunc = origExit
+		newExecutor = origExecutor
 	}()
 
 	exitCode := -1
@@ -205,9 +215,11 @@ func TestMainCommand_SyncError(t *testing.T) {
 func TestMainCommand_Checkpoint(t *testing.T) {
 	origArgs := os.Args
 	origExit := exitFunc
+	origExecutor := newExecutor
 	defer func() {
 		os.Args = origArgs
 		exitFunc = origExit
+		newExecutor = origExecutor
 	}()
 
 	os.Args = []string{"aict", "checkpoint"}
@@ -220,9 +232,11 @@ func TestMainCommand_Checkpoint(t *testing.T) {
 func TestMainCommand_VersionFlags(t *testing.T) {
 	origArgs := os.Args
 	origExit := exitFunc
+	origExecutor := newExecutor
 	defer func() {
 		os.Args = origArgs
 		exitFunc = origExit
+		newExecutor = origExecutor
 	}()
 
 	exitFunc = func(code int) { /* no-op */ }
@@ -236,9 +250,11 @@ func TestMainCommand_VersionFlags(t *testing.T) {
 func TestMainCommand_HelpFlag(t *testing.T) {
 	origArgs := os.Args
 	origExit := exitFunc
+	origExecutor := newExecutor
 	defer func() {
 		os.Args = origArgs
 		exitFunc = origExit
+		newExecutor = origExecutor
 	}()
 
 	exitFunc = func(code int) { /* no-op */ }
@@ -253,9 +269,11 @@ func TestMainCommand_HelpFlag(t *testing.T) {
 func TestMainCommand_DebugError(t *testing.T) {
 	origArgs := os.Args
 	origExit := exitFunc
+	origExecutor := newExecutor
 	defer func() {
 		os.Args = origArgs
 		exitFunc = origExit
+		newExecutor = origExecutor
 	}()
 
 	exitCode := -1
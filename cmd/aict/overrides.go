@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// overrideEngine は設定の overrides をレポートの集計時に適用します（Authorship Logは書き換えない）。
+type overrideEngine struct {
+	rules    []compiledOverride
+	mappings map[string]string
+	matched  map[string]bool // 集計中に一致したルール名
+}
+
+// compiledOverride は期間とブランチのコミットを解決済みのルールです。
+type compiledOverride struct {
+	tracker.OverrideRule
+	name    string
+	since   time.Time
+	until   time.Time
+	commits map[string]bool // branch 指定時にそのブランチから到達できるコミット
+}
+
+// loadOverrideEngine は設定の overrides を読み込みます。設定がない・ルールがない場合は nil です。
+func loadOverrideEngine() (*overrideEngine, error) {
+	_, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return nil, nil
+	}
+	return newOverrideEngine(cfg)
+}
+
+// newOverrideEngine は設定のルールを評価できる形に変換します。
+func newOverrideEngine(cfg *tracker.Config) (*overrideEngine, error) {
+	if cfg == nil || len(cfg.Overrides) == 0 {
+		return nil, nil
+	}
+	e := &overrideEngine{mappings: cfg.AuthorMappings, matched: make(map[string]bool)}
+	for i, rule := range cfg.Overrides {
+		since, until, err := rule.Window()
+		if err != nil {
+			return nil, fmt.Errorf("overrides[%d].%v", i, err)
+		}
+		c := compiledOverride{OverrideRule: rule, name: rule.Name, since: since, until: until}
+		if c.name == "" {
+			c.name = fmt.Sprintf("overrides[%d]", i)
+		}
+		if rule.Branch != "" {
+			output, err := newExecutor().Run("rev-list", "--end-of-options", rule.Branch)
+			if err != nil {
+				return nil, fmt.Errorf("overrides[%d].branch: %w", i, err)
+			}
+			c.commits = make(map[string]bool)
+			for _, hash := range strings.Fields(output) {
+				c.commits[hash] = true
+			}
+		}
+		e.rules = append(e.rules, c)
+	}
+	return e, nil
+}
+
+// forCommit はコミットの作成者エントリに適用する種別と重みを返す関数を返します。
+func (e *overrideEngine) forCommit(commit string, timestamp time.Time) func(*tracker.AuthorInfo) (tracker.AuthorType, float64) {
+	return func(author *tracker.AuthorInfo) (tracker.AuthorType, float64) {
+		return e.resolve(commit, timestamp, author)
+	}
+}
+
+// resolve は先頭から順にルールを評価し、最初に一致したルールの種別と重みを返します。
+// 一致するルールがない場合は元の種別と重み1です。
+func (e *overrideEngine) resolve(commit string, timestamp time.Time, author *tracker.AuthorInfo) (tracker.AuthorType, float64) {
+	for i := range e.rules {
+		r := &e.rules[i]
+		if !r.matches(commit, timestamp, author, e.mappings) {
+			continue
+		}
+		e.matched[r.name] = true
+		authorType, weight := author.Type, 1.0
+		if r.Type != "" {
+			authorType = r.Type
+		}
+		if r.Weight != nil {
+			weight = *r.Weight
+		}
+		return authorType, weight
+	}
+	return author.Type, 1
+}
+
+// matches はルールの期間・作成者・ブランチが全て一致するかを判定します。
+func (r *compiledOverride) matches(commit string, timestamp time.Time, author *tracker.AuthorInfo, mappings map[string]string) bool {
+	if !r.since.IsZero() && timestamp.Before(r.since) {
+		return false
+	}
+	if !r.until.IsZero() && !timestamp.Before(r.until) {
+		return false
+	}
+	if r.Author != "" && !matchAuthorPattern(r.Author, author.Name) && !matchAuthorPattern(r.Author, mappings[author.Name]) {
+		return false
+	}
+	if r.commits != nil && !r.commits[commit] {
+		return false
+	}
+	return true
+}
+
+// matchAuthorPattern は作成者名がglobに一致するかを判定します（空の名前は一致しない）。
+func matchAuthorPattern(pattern, name string) bool {
+	if name == "" {
+		return false
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// matchedNames は集計中に一致したルール名を名前順に返します。
+func (e *overrideEngine) matchedNames() []string {
+	if e == nil {
+		return nil
+	}
+	names := make([]string, 0, len(e.matched))
+	for name := range e.matched {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyWeight は行数に重みを掛けます（四捨五入）。
+func applyWeight(lines int, weight float64) int {
+	if weight == 1 {
+		return lines
+	}
+	return int(math.Round(float64(lines) * weight))
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestOverrideEngineResolve(t *testing.T) {
+	half := 0.5
+	cfg := &tracker.Config{
+		AuthorMappings: map[string]string{"bob@contractor.example": "contractor-bob"},
+		Overrides: []tracker.OverrideRule{
+			{Name: "migration week", Since: "2025-03-03", Until: "2025-03-07", Author: "contractor-*", Type: tracker.AuthorTypeAI},
+			{Name: "reweight bots", Author: "bot-*", Weight: &half},
+		},
+	}
+	engine, err := newOverrideEngine(cfg)
+	if err != nil {
+		t.Fatalf("newOverrideEngine() error = %v", err)
+	}
+
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 12, 0, 0, 0, time.Local) }
+	tests := []struct {
+		name       string
+		author     string
+		authorType tracker.AuthorType
+		timestamp  time.Time
+		wantType   tracker.AuthorType
+		wantWeight float64
+	}{
+		{"inside the window", "contractor-alice", tracker.AuthorTypeHuman, day(4), tracker.AuthorTypeAI, 1},
+		{"last day is included", "contractor-alice", tracker.AuthorTypeHuman, day(7), tracker.AuthorTypeAI, 1},
+		{"after the window", "contractor-alice", tracker.AuthorTypeHuman, day(8), tracker.AuthorTypeHuman, 1},
+		{"before the window", "contractor-alice", tracker.AuthorTypeHuman, day(2), tracker.AuthorTypeHuman, 1},
+		{"mapped author name", "bob@contractor.example", tracker.AuthorTypeHuman, day(5), tracker.AuthorTypeAI, 1},
+		{"other author", "alice", tracker.AuthorTypeHuman, day(5), tracker.AuthorTypeHuman, 1},
+		{"weight only keeps the type", "bot-renovate", tracker.AuthorTypeAI, day(20), tracker.AuthorTypeAI, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			author := &tracker.AuthorInfo{Name: tt.author, Type: tt.authorType}
+			gotType, gotWeight := engine.resolve("abc", tt.timestamp, author)
+			if gotType != tt.wantType || gotWeight != tt.wantWeight {
+				t.Errorf("resolve() = %s, %g; want %s, %g", gotType, gotWeight, tt.wantType, tt.wantWeight)
+			}
+		})
+	}
+
+	if got := engine.matchedNames(); !reflect.DeepEqual(got, []string{"migration week", "reweight bots"}) {
+		t.Errorf("matchedNames() = %v", got)
+	}
+}
+
+func TestCollectAuthorStats_Overrides(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	base := testutil.GitCommit(t, tmpDir, "Base commit")
+	testutil.CreateTestFile(t, tmpDir, "a.go", "package main\n\nfunc a() {}\n")
+	testutil.GitCommit(t, tmpDir, "Add a")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+	testutil.CreateTestFile(t, tmpDir, "b.go", "package main\n\nfunc b() {}\nfunc c() {}\n")
+	testutil.GitCommit(t, tmpDir, "Add b")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	rangeSpec := base + "..HEAD"
+	before, _, err := collectAuthorStats(rangeSpec, 1, nil)
+	if err != nil {
+		t.Fatalf("collectAuthorStats() error = %v", err)
+	}
+	if before.totalAI != 0 || before.totalHuman != 7 {
+		t.Fatalf("totals without overrides = %d/%d, want 0/7", before.totalAI, before.totalHuman)
+	}
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	half := 0.5
+	cfg.Overrides = []tracker.OverrideRule{
+		{Name: "past", Until: "2000-01-01", Author: "human", Type: tracker.AuthorTypeAI},
+		{Name: "migration", Since: "2000-01-01", Author: "hu*", Branch: "HEAD", Type: tracker.AuthorTypeAI, Weight: &half},
+	}
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	after, _, err := collectAuthorStats(rangeSpec, 1, nil)
+	if err != nil {
+		t.Fatalf("collectAuthorStats() error = %v", err)
+	}
+	// a.go 3行・b.go 4行をAIに分類し、重み0.5で四捨五入（2 + 2）
+	if after.totalAI != 4 || after.totalHuman != 0 {
+		t.Errorf("totals with overrides = %d/%d, want 4/0", after.totalAI, after.totalHuman)
+	}
+	if got := after.overrides.matchedNames(); !reflect.DeepEqual(got, []string{"migration"}) {
+		t.Errorf("matched rules = %v, want [migration]", got)
+	}
+
+	// Authorship Logは書き換えない
+	alog, err := gitnotes.NewNotesManager().GetAuthorshipLog("HEAD")
+	if err != nil || alog == nil {
+		t.Fatalf("GetAuthorshipLog() = %v, %v", alog, err)
+	}
+	if got := alog.Files["b.go"].Authors[0].Type; got != tracker.AuthorTypeHuman {
+		t.Errorf("recorded author type = %s, want human", got)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// gitIgnoresCase はリポジトリが大文字小文字を区別しないファイルシステム上にあるか
+// （git の core.ignorecase）を返します。macOS/Windows の既定では true です。
+func gitIgnoresCase() bool {
+	out, err := newExecutor().Run("config", "--bool", "core.ignorecase")
+	return err == nil && out == "true"
+}
+
+// pathCanonicalizer は大文字小文字だけが異なるパスを git が記録している表記に揃えます。
+// 大文字小文字を区別するファイルシステムでは何もしません。
+type pathCanonicalizer struct {
+	byFold map[string]string // strings.ToLower(path) -> gitの表記
+}
+
+// newPathCanonicalizer は known に含まれるパス（gitのインデックス等）を正とする変換器を作成します。
+func newPathCanonicalizer(known []string, ignoreCase bool) *pathCanonicalizer {
+	if !ignoreCase {
+		return &pathCanonicalizer{}
+	}
+	c := &pathCanonicalizer{byFold: make(map[string]string, len(known))}
+	for _, p := range known {
+		c.add(p)
+	}
+	return c
+}
+
+// add は p を正規の表記として登録します（既に登録済みの表記は上書きしません）。
+func (c *pathCanonicalizer) add(p string) {
+	if c.byFold == nil {
+		return
+	}
+	key := strings.ToLower(p)
+	if _, exists := c.byFold[key]; !exists {
+		c.byFold[key] = p
+	}
+}
+
+// canonical は p の正規の表記を返します。未登録のパスはそのまま返します。
+func (c *pathCanonicalizer) canonical(p string) string {
+	if c.byFold == nil {
+		return p
+	}
+	if known, ok := c.byFold[strings.ToLower(p)]; ok {
+		return known
+	}
+	return p
+}
+
+// canonicalizeCheckpointPaths はチェックポイントのファイルパスをコミットの変更ファイルの表記に揃えます。
+// 大文字小文字を区別しないファイルシステムでリネームした場合などに、同じファイルが別パスとして
+// 照合漏れ・二重計上されるのを防ぎます。
+func canonicalizeCheckpointPaths(checkpoints []*tracker.CheckpointV2, changedFiles map[string]bool, ignoreCase bool) {
+	if !ignoreCase {
+		return
+	}
+	known := make([]string, 0, len(changedFiles))
+	for f := range changedFiles {
+		known = append(known, f)
+	}
+	c := newPathCanonicalizer(known, true)
+
+	for _, cp := range checkpoints {
+		for p, change := range cp.Changes {
+			if cp2 := c.canonical(p); cp2 != p {
+				delete(cp.Changes, p)
+				cp.Changes[cp2] = change
+			}
+		}
+		for p, snap := range cp.Snapshot {
+			if cp2 := c.canonical(p); cp2 != p {
+				delete(cp.Snapshot, p)
+				cp.Snapshot[cp2] = snap
+			}
+		}
+	}
+}
+
+// readTrackedContent はスナップショット用にファイル内容を読み込みます。
+// シンボリックリンクはリンク先の内容ではなく git と同じくリンク先パスを内容とし、
+// リンク先ファイルと二重に計上されないようにします。
+func readTrackedContent(path string) ([]byte, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(target), nil
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestPathCanonicalizer(t *testing.T) {
+	c := newPathCanonicalizer([]string{"pkg/Foo.go", "main.go"}, true)
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"pkg/foo.go", "pkg/Foo.go"},
+		{"PKG/FOO.GO", "pkg/Foo.go"},
+		{"main.go", "main.go"},
+		{"other.go", "other.go"},
+	}
+	for _, tt := range tests {
+		if got := c.canonical(tt.in); got != tt.want {
+			t.Errorf("canonical(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	sensitive := newPathCanonicalizer([]string{"pkg/Foo.go"}, false)
+	sensitive.add("x.go")
+	if got := sensitive.canonical("pkg/foo.go"); got != "pkg/foo.go" {
+		t.Errorf("case-sensitive canonical should be identity, got %q", got)
+	}
+}
+
+func TestCanonicalizeCheckpointPaths(t *testing.T) {
+	newCheckpoints := func() []*tracker.CheckpointV2 {
+		return []*tracker.CheckpointV2{{
+			Changes:  map[string]tracker.Change{"foo.go": {Added: 3}},
+			Snapshot: map[string]tracker.FileSnapshot{"foo.go": {Hash: "h", Lines: 3}},
+		}}
+	}
+	changed := map[string]bool{"Foo.go": true}
+
+	cps := newCheckpoints()
+	canonicalizeCheckpointPaths(cps, changed, true)
+	if _, ok := cps[0].Changes["Foo.go"]; !ok || len(cps[0].Changes) != 1 {
+		t.Errorf("Changes = %v, want key Foo.go", cps[0].Changes)
+	}
+	if _, ok := cps[0].Snapshot["Foo.go"]; !ok || len(cps[0].Snapshot) != 1 {
+		t.Errorf("Snapshot = %v, want key Foo.go", cps[0].Snapshot)
+	}
+
+	cps = newCheckpoints()
+	canonicalizeCheckpointPaths(cps, changed, false)
+	if _, ok := cps[0].Changes["foo.go"]; !ok {
+		t.Errorf("case-sensitive repo should keep paths as recorded, got %v", cps[0].Changes)
+	}
+}
+
+func TestCaptureSnapshot_CaseInsensitiveRepo(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "Foo.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	if out, err := exec.Command("git", "config", "core.ignorecase", "true").CombinedOutput(); err != nil {
+		t.Fatalf("git config failed: %v\n%s", err, out)
+	}
+	// 大文字小文字を区別しないFSでは同一ファイルとなる表記揺れ（このFSでは別ファイルとして再現）
+	testutil.CreateTestFile(t, tmpDir, "foo.go", "package main\n")
+
+	snapshot, err := captureSnapshot([]string{".go"}, nil, nil, true)
+	if err != nil {
+		t.Fatalf("captureSnapshot() error = %v", err)
+	}
+	if len(snapshot) != 1 {
+		t.Fatalf("snapshot = %v, want a single entry", snapshot)
+	}
+	if _, ok := snapshot["Foo.go"]; !ok {
+		t.Errorf("snapshot should use the index spelling Foo.go, got %v", snapshot)
+	}
+}
+
+func TestCaptureSnapshot_SymlinkNotDoubleCounted(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "real.go", "package main\n\nfunc a() {}\nfunc b() {}\n")
+	if err := os.Symlink("real.go", filepath.Join(tmpDir, "link.go")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	snapshot, err := captureSnapshot([]string{".go"}, nil, nil, true)
+	if err != nil {
+		t.Fatalf("captureSnapshot() error = %v", err)
+	}
+	if got := snapshot["real.go"].Lines; got != 4 {
+		t.Errorf("real.go lines = %d, want 4", got)
+	}
+	// git と同じくシンボリックリンクはリンク先パス1行として扱う
+	if got := snapshot["link.go"].Lines; got != 1 {
+		t.Errorf("link.go lines = %d, want 1 (link target path, not target content)", got)
+	}
+}
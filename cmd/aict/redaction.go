@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/redact"
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// 伏せた対象の種類（redactions.jsonl の kind）
+const (
+	redactionKindPath    = "path"
+	redactionKindMessage = "message"
+)
+
+// redactAuthorshipLog は設定の redaction に従ってAuthorship Logのファイルパスと作成者エントリのメタデータを伏せ、
+// 伏せた内容を .git/aict/redactions.jsonl に記録します。伏せた件数を返します。
+// 照合はコミットのパスで済んでいるため、保存直前に呼び出してください。
+func redactAuthorshipLog(store *storage.AIctStorage, cfg *tracker.Config, log *tracker.AuthorshipLog) int {
+	if cfg == nil || cfg.Redaction == nil {
+		return 0
+	}
+	r, err := redact.New(cfg.Redaction)
+	if err != nil {
+		return 0 // 設定の読み込み時に検証済み
+	}
+
+	now := time.Now()
+	var entries []*tracker.RedactionLogEntry
+	paths := make([]string, 0, len(log.Files))
+	for p := range log.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		info := log.Files[p]
+		for i := range info.Authors {
+			entries = append(entries, redactMetadata(r, info.Authors[i].Metadata, log.Commit, now)...)
+		}
+		if redacted, reason := r.Path(p); reason != "" {
+			delete(log.Files, p)
+			log.Files[redacted] = info
+			entries = append(entries, &tracker.RedactionLogEntry{
+				Timestamp: now, Commit: log.Commit, Kind: redactionKindPath, Reason: reason, Redacted: redacted, Original: p,
+			})
+		}
+	}
+	appendRedactionLog(store, entries)
+	return len(entries)
+}
+
+// redactCheckpoint は設定の redaction に従ってチェックポイントのメタデータ（メッセージ等）を伏せます。
+// ファイルパスはコミット時の照合に使うため伏せません（Authorship Logの保存時に伏せます）。
+func redactCheckpoint(store *storage.AIctStorage, cfg *tracker.Config, cp *tracker.CheckpointV2) {
+	if cfg == nil || cfg.Redaction == nil {
+		return
+	}
+	r, err := redact.New(cfg.Redaction)
+	if err != nil {
+		return
+	}
+	appendRedactionLog(store, redactMetadata(r, cp.Metadata, "", time.Now()))
+}
+
+// redactMetadata はメタデータの値を伏せ、伏せた値ごとのレコードを返します。
+func redactMetadata(r *redact.Redactor, metadata map[string]string, commit string, now time.Time) []*tracker.RedactionLogEntry {
+	var entries []*tracker.RedactionLogEntry
+	for key, value := range metadata {
+		redacted, reasons := r.Text(value)
+		if len(reasons) == 0 {
+			continue
+		}
+		metadata[key] = redacted
+		for _, reason := range reasons {
+			entries = append(entries, &tracker.RedactionLogEntry{
+				Timestamp: now, Commit: commit, Kind: redactionKindMessage, Reason: reason, Redacted: redacted,
+			})
+		}
+	}
+	return entries
+}
+
+// appendRedactionLog は伏せた内容を記録します。記録に失敗しても伏せた結果は保存するため警告のみです。
+func appendRedactionLog(store *storage.AIctStorage, entries []*tracker.RedactionLogEntry) {
+	if store == nil || len(entries) == 0 {
+		return
+	}
+	if err := store.AppendRedactionLog(entries); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record redactions: %v\n", err)
+	}
+}
+
+// handleDebugRedactions は設定の redaction で伏せたパス・メッセージの記録を表示します。
+func handleDebugRedactions() error {
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		return fmt.Errorf("initializing storage: %w", err)
+	}
+	entries, err := store.LoadRedactionLog()
+	if err != nil {
+		return fmt.Errorf("%s の読み込みに失敗しました: %w", storage.RedactionLogFileName, err)
+	}
+	if len(entries) == 0 {
+		fmt.Println("伏せたパス・メッセージはありません")
+		return nil
+	}
+
+	fmt.Printf("=== 伏せたパス・メッセージ (%d件) ===\n\n", len(entries))
+	for _, e := range entries {
+		commit := shortCommit(e.Commit)
+		if commit == "" {
+			commit = "(checkpoint)"
+		}
+		detail := e.Redacted
+		if e.Original != "" {
+			detail = e.Original + " -> " + e.Redacted
+		}
+		fmt.Printf("%s  %-12s  %-7s  %-7s  %s\n", e.Timestamp.Local().Format("2006-01-02 15:04"), commit, e.Kind, e.Reason, detail)
+	}
+	return nil
+}
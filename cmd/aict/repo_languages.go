@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/ignore"
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+const (
+	// repoScanMaxFileSize はリポジトリ言語計測の対象とする最大ファイルサイズ（生成物・データファイルの除外）
+	repoScanMaxFileSize = 1 << 20
+	// repoLanguageSummaryLimit はレポートヘッダーに表示する言語数
+	repoLanguageSummaryLimit = 3
+)
+
+// nonProgrammingLanguages はリポジトリ言語構成に含めないマークアップ・データ形式
+var nonProgrammingLanguages = map[string]bool{
+	"Markdown": true,
+	"YAML":     true,
+	"JSON":     true,
+}
+
+// vendoredDirs はリポジトリ言語計測から除外するサードパーティコードのディレクトリ名
+var vendoredDirs = map[string]bool{
+	"vendor":       true,
+	"node_modules": true,
+	"third_party":  true,
+}
+
+// scanRepoLanguages はGit管理下のファイルを走査し、言語ごとのファイル数と行数を計測します。
+// GitHub linguist と同様に、ベンダーコード・バイナリ・巨大ファイル・マークアップ/データ形式は除外します。
+func scanRepoLanguages(exclude *ignore.Matcher) (*tracker.RepoLanguageProfile, error) {
+	executor := newExecutor()
+	output, err := executor.Run("ls-files")
+	if err != nil {
+		return nil, fmt.Errorf("listing files: %w", err)
+	}
+
+	grouped := make(map[string]*tracker.RepoLanguageShare)
+	total := 0
+	for _, path := range strings.Split(output, "\n") {
+		lang, ok := repoScanLanguage(path, exclude)
+		if !ok {
+			continue
+		}
+		lines, ok := countSourceLines(path)
+		if !ok {
+			continue
+		}
+
+		s, exists := grouped[lang]
+		if !exists {
+			s = &tracker.RepoLanguageShare{Language: lang}
+			grouped[lang] = s
+		}
+		s.Files++
+		s.Lines += lines
+		total += lines
+	}
+
+	profile := &tracker.RepoLanguageProfile{ScannedAt: time.Now()}
+	profile.Commit, _ = executor.Run("rev-parse", "HEAD")
+	for _, s := range grouped {
+		s.Percentage = metrics.SafePercent(s.Lines, total)
+		profile.Languages = append(profile.Languages, *s)
+	}
+	sort.Slice(profile.Languages, func(i, j int) bool {
+		a, b := profile.Languages[i], profile.Languages[j]
+		if a.Lines != b.Lines {
+			return a.Lines > b.Lines
+		}
+		return a.Language < b.Language
+	})
+	return profile, nil
+}
+
+// repoScanLanguage は計測対象のファイルであれば言語名を返します。
+func repoScanLanguage(path string, exclude *ignore.Matcher) (string, bool) {
+	if path == "" {
+		return "", false
+	}
+	for _, dir := range strings.Split(path, "/")[:strings.Count(path, "/")] {
+		if vendoredDirs[dir] {
+			return "", false
+		}
+	}
+	if exclude.Match(path) {
+		return "", false
+	}
+
+	lang := detectLanguageFromPath(path)
+	// 対応表にない拡張子（".proto" 等）と拡張子なしのファイルは計測しない
+	if lang == languageOther || strings.HasPrefix(lang, ".") || nonProgrammingLanguages[lang] {
+		return "", false
+	}
+	return lang, true
+}
+
+// countSourceLines はファイルの行数を返します。読めないファイル・巨大ファイル・バイナリは ok=false。
+func countSourceLines(path string) (int, bool) {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > repoScanMaxFileSize {
+		return 0, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	if detectTextEncoding(data) == encodingBinary {
+		return 0, false
+	}
+	return countLines(data), true
+}
+
+// repoLanguageSummary は上位 limit 言語を "Go 78.0%, Shell 12.0%" の形式で返します。
+func repoLanguageSummary(shares []tracker.RepoLanguageShare, limit int) string {
+	parts := make([]string, 0, limit)
+	for i, s := range shares {
+		if i >= limit {
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s %.1f%%", s.Language, s.Percentage))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/ignore"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestRepoScanLanguage(t *testing.T) {
+	exclude := ignore.Compile([]string{"*_generated.go"})
+
+	tests := []struct {
+		path     string
+		wantLang string
+		wantOK   bool
+	}{
+		{"main.go", "Go", true},
+		{"web/app.tsx", "TypeScript", true},
+		{"vendor/github.com/x/y.go", "", false},
+		{"web/node_modules/lib/index.js", "", false},
+		{"api/types_generated.go", "", false},
+		{"README.md", "", false},
+		{"config.yml", "", false},
+		{"schema.proto", "", false},
+		{"Makefile", "", false},
+		{"vendor.go", "Go", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			lang, ok := repoScanLanguage(tt.path, exclude)
+			if lang != tt.wantLang || ok != tt.wantOK {
+				t.Errorf("repoScanLanguage(%q) = (%q, %v), want (%q, %v)", tt.path, lang, ok, tt.wantLang, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRepoLanguageSummary(t *testing.T) {
+	shares := []tracker.RepoLanguageShare{
+		{Language: "Go", Percentage: 70},
+		{Language: "Shell", Percentage: 20},
+		{Language: "Python", Percentage: 7.5},
+		{Language: "C", Percentage: 2.5},
+	}
+	if got, want := repoLanguageSummary(shares, 3), "Go 70.0%, Shell 20.0%, Python 7.5%"; got != want {
+		t.Errorf("repoLanguageSummary() = %q, want %q", got, want)
+	}
+}
+
+func TestHandleInitV2_RecordsRepoLanguages(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	defer setStdinReader("n\n")()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc main() {}\n")
+	testutil.CreateTestFile(t, tmpDir, "tools/build.sh", "#!/bin/sh\necho build")
+	testutil.CreateTestFile(t, tmpDir, "vendor/lib/lib.go", strings.Repeat("// vendored\n", 50))
+	testutil.CreateTestFile(t, tmpDir, "docs/guide.md", "# Guide\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	if err := handleInitV2(); err != nil {
+		t.Fatalf("handleInitV2() error = %v", err)
+	}
+
+	_, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	if cfg.RepoLanguages == nil {
+		t.Fatal("RepoLanguages should be recorded at init")
+	}
+	if cfg.RepoLanguages.Commit == "" {
+		t.Error("RepoLanguages.Commit should be the HEAD at init")
+	}
+
+	langs := cfg.RepoLanguages.Languages
+	if len(langs) != 2 {
+		t.Fatalf("expected Go and Shell, got %+v", langs)
+	}
+	if langs[0].Language != "Go" || langs[0].Lines != 3 || langs[0].Percentage != 60 {
+		t.Errorf("first language = %+v, want Go with 3 lines (60%%)", langs[0])
+	}
+	if langs[1].Language != "Shell" || langs[1].Lines != 2 {
+		t.Errorf("second language = %+v, want Shell with 2 lines", langs[1])
+	}
+}
+
+func TestGenerateRangeReport_IncludesRepoLanguages(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		t.Fatalf("loadStorageAndConfig() error = %v", err)
+	}
+	cfg.RepoLanguages = &tracker.RepoLanguageProfile{
+		Languages: []tracker.RepoLanguageShare{{Language: "Go", Files: 1, Lines: 10, Percentage: 100}},
+	}
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+	testutil.CreateTestFile(t, tmpDir, "a.go", "package main\n\nfunc a() {}\n")
+	testutil.GitCommit(t, tmpDir, "Add a")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	report, _, err := generateRangeReport(&ReportOptions{Range: "HEAD~1..HEAD"})
+	if err != nil {
+		t.Fatalf("generateRangeReport() error = %v", err)
+	}
+	if report == nil || len(report.RepoLanguages) != 1 || report.RepoLanguages[0].Language != "Go" {
+		t.Errorf("report should carry the repository language mix, got %+v", report)
+	}
+}
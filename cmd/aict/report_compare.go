@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// compareToPrevious は --compare-to で直前の同じ長さの期間と比較する指定
+const compareToPrevious = "previous"
+
+// gitDateLayout は git log --since/--until に渡す日時の形式
+const gitDateLayout = "2006-01-02 15:04:05 -0700"
+
+// handleCompareReport は opts の範囲と compareTo（previous またはコミット範囲）のレポートを並べて表示します。
+func handleCompareReport(opts *ReportOptions, compareTo string) error {
+	prevOpts := *opts
+	prevOpts.Since = ""
+	prevLabel := compareTo
+	if compareTo == compareToPrevious {
+		head := "HEAD"
+		if opts.Branch != "" {
+			head = opts.Branch
+		}
+		start, end, err := previousPeriod(opts.Since, time.Now())
+		if err != nil {
+			return err
+		}
+		prevLabel = start.Local().Format("2006-01-02") + " – " + end.Local().Format("2006-01-02")
+		prevOpts.Range, err = convertWindowToRangeOn(start, end, head)
+		if err != nil {
+			return err
+		}
+	} else {
+		prevOpts.Range = compareTo
+		prevOpts.Branch = ""
+	}
+
+	curLabel := opts.Range
+	if opts.Since != "" {
+		curLabel = "since " + opts.Since
+	}
+
+	comparison := &tracker.ReportComparison{}
+	for _, side := range []struct {
+		opts  *ReportOptions
+		label string
+		dst   **tracker.Report
+	}{
+		{opts, curLabel, &comparison.Current},
+		{&prevOpts, prevLabel, &comparison.Previous},
+	} {
+		// 範囲内にコミットがない期間は0件のレポートとして比較する
+		report := &tracker.Report{}
+		if side.opts.Range != "" {
+			ensureRangeHistory(side.opts.Range)
+			r, _, err := computeRangeReport(side.opts)
+			if err != nil {
+				return err
+			}
+			if r != nil {
+				report = r
+			}
+		}
+		report.Range = side.label
+		*side.dst = report
+	}
+	comparison.Delta = compareReports(comparison.Current, comparison.Previous)
+
+	if opts.Fields != "" {
+		data, err := marshalJSONFields(comparison, opts.Fields)
+		if err != nil {
+			return fmt.Errorf("formatting JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	return formatCompareReport(comparison, opts.Format)
+}
+
+// previousPeriod は since から現在までの期間の直前にある、同じ長さの期間を返します。
+// since は git の日付指定（7d などの簡潔な表記を含む）で、git rev-parse --since で日時に解決します。
+func previousPeriod(since string, now time.Time) (start, end time.Time, err error) {
+	output, err := newExecutor().Run("rev-parse", "--since="+expandShorthandDate(since))
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("resolving --since %q: %w", since, err)
+	}
+	unix, err := strconv.ParseInt(strings.TrimPrefix(output, "--max-age="), 10, 64)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("resolving --since %q: unexpected output %q", since, output)
+	}
+	end = time.Unix(unix, 0)
+	if !end.Before(now) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--since %q is not in the past", since)
+	}
+	return end.Add(-now.Sub(end)), end, nil
+}
+
+// convertWindowToRangeOn は head から辿れる [start, end) にコミットされたコミットの範囲を返します。
+// 期間内にコミットがない場合は空文字列を返します。
+func convertWindowToRangeOn(start, end time.Time, head string) (string, error) {
+	executor := newExecutor()
+	args := []string{"log", "--since=" + start.Format(gitDateLayout), "--until=" + end.Add(-time.Second).Format(gitDateLayout), "--format=%H"}
+	if head != "HEAD" {
+		args = append(args, "--end-of-options")
+	}
+	output, err := executor.Run(append(args, head)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get commits between %s and %s: %w", start.Format("2006-01-02"), end.Format("2006-01-02"), err)
+	}
+	commits := strings.Fields(output)
+	if len(commits) == 0 {
+		return "", nil
+	}
+	// git log は新しい順（末尾が期間の最初のコミット）
+	return rangeFromCommit(executor, commits[len(commits)-1], commits[0]), nil
+}
+
+// compareReports は current と previous の差を返します。
+func compareReports(current, previous *tracker.Report) tracker.ReportDelta {
+	return tracker.ReportDelta{
+		Commits:            current.Commits - previous.Commits,
+		TotalLines:         current.Summary.TotalLines - previous.Summary.TotalLines,
+		AILines:            current.Summary.AILines - previous.Summary.AILines,
+		HumanLines:         current.Summary.HumanLines - previous.Summary.HumanLines,
+		AIPercentagePoints: current.Summary.AIPercentage - previous.Summary.AIPercentage,
+	}
+}
+
+// formatCompareReport は2つのレポートを並べて差分とともに表示します。
+func formatCompareReport(c *tracker.ReportComparison, format string) error {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			return fmt.Errorf("formatting JSON: %w", err)
+		}
+		fmt.Println(string(data))
+
+	case "table", "graph":
+		fmt.Println("AI Code Generation Comparison")
+		fmt.Println()
+		fmt.Printf("Current:  %s\n", c.Current.Range)
+		fmt.Printf("Previous: %s\n", c.Previous.Range)
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Println()
+		fmt.Printf("  %-14s %10s %10s %18s\n", "", "Current", "Previous", "Change")
+		rows := []struct {
+			label        string
+			current, old int
+		}{
+			{"Commits", c.Current.Commits, c.Previous.Commits},
+			{"Total lines", c.Current.Summary.TotalLines, c.Previous.Summary.TotalLines},
+			{"□ AI lines", c.Current.Summary.AILines, c.Previous.Summary.AILines},
+			{"○ Human lines", c.Current.Summary.HumanLines, c.Previous.Summary.HumanLines},
+		}
+		for _, r := range rows {
+			fmt.Printf("  %-14s %10d %10d %18s\n", r.label, r.current, r.old, formatCountChange(r.current, r.old))
+		}
+		fmt.Printf("  %-14s %9.1f%% %9.1f%% %18s\n", "AI %", c.Current.Summary.AIPercentage, c.Previous.Summary.AIPercentage,
+			fmt.Sprintf("%+.1fpt", c.Delta.AIPercentagePoints))
+		fmt.Println()
+
+	default:
+		return fmt.Errorf("unknown format: %s (available: table, json)", format)
+	}
+	return nil
+}
+
+// formatCountChange は件数の変化を "+12 (+20.0%)" の形式で返します（比較元が0の場合は増減のみ）。
+func formatCountChange(current, previous int) string {
+	diff := current - previous
+	if previous == 0 {
+		return fmt.Sprintf("%+d", diff)
+	}
+	return fmt.Sprintf("%+d (%+.1f%%)", diff, metrics.SafePercent(diff, previous))
+}
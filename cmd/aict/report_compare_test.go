@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestCompareReports(t *testing.T) {
+	current := &tracker.Report{Commits: 12, Summary: tracker.SummaryStats{TotalLines: 200, AILines: 120, HumanLines: 80, AIPercentage: 60}}
+	previous := &tracker.Report{Commits: 10, Summary: tracker.SummaryStats{TotalLines: 100, AILines: 40, HumanLines: 60, AIPercentage: 40}}
+
+	want := tracker.ReportDelta{Commits: 2, TotalLines: 100, AILines: 80, HumanLines: 20, AIPercentagePoints: 20}
+	if got := compareReports(current, previous); got != want {
+		t.Errorf("compareReports() = %+v, want %+v", got, want)
+	}
+
+	if got := formatCountChange(12, 10); got != "+2 (+20.0%)" {
+		t.Errorf("formatCountChange(12, 10) = %q", got)
+	}
+	if got := formatCountChange(5, 0); got != "+5" {
+		t.Errorf("formatCountChange(5, 0) = %q", got)
+	}
+}
+
+func TestHandleRangeReport_CompareToValidation(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--range", "HEAD~1..HEAD", "--compare-to", "previous"}, "requires --since"},
+		{[]string{"--last", "30d", "--compare-to", "previous", "--trend", "week"}, "cannot be combined"},
+		{[]string{"--last", "30d", "--since", "7d"}, "alias of --since"},
+	}
+	for _, tt := range tests {
+		os.Args = append([]string{"aict", "report"}, tt.args...)
+		if err := handleRangeReport(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("handleRangeReport(%v) error = %v, want %q", tt.args, err, tt.want)
+		}
+	}
+}
+
+func TestHandleRangeReport_CompareToPrevious(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+	t.Setenv("AICT_NO_DAEMON", "1")
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	nm := gitnotes.NewNotesManager()
+	commitAt := func(daysAgo int, file, content string, authorType tracker.AuthorType, lines int) {
+		t.Helper()
+		date := time.Now().AddDate(0, 0, -daysAgo).Format(time.RFC3339)
+		t.Setenv("GIT_COMMITTER_DATE", date)
+		t.Setenv("GIT_AUTHOR_DATE", date)
+		testutil.CreateTestFile(t, tmpDir, file, content)
+		commit := testutil.GitCommit(t, tmpDir, "Add "+file)
+		if authorType == "" {
+			return
+		}
+		if err := nm.AddAuthorshipLog(&tracker.AuthorshipLog{
+			Version: "1.0",
+			Commit:  commit,
+			Files: map[string]tracker.FileInfo{
+				file: {Authors: []tracker.AuthorInfo{{Name: string(authorType), Type: authorType, Lines: [][]int{{1, lines}}}}},
+			},
+		}); err != nil {
+			t.Fatalf("AddAuthorshipLog() error = %v", err)
+		}
+	}
+
+	commitAt(100, "base.go", "package main\n", "", 0)
+	// 前の期間（31〜60日前）: AIが3行
+	commitAt(45, "ai.go", "package main\n\nfunc a() {}\n", tracker.AuthorTypeAI, 3)
+	// 今の期間（30日以内）: 人間が2行
+	commitAt(10, "human.go", "package main\n\n", tracker.AuthorTypeHuman, 2)
+
+	os.Args = []string{"aict", "report", "--last", "30d", "--compare-to", "previous", "--format", "json"}
+	r, w, _ := os.Pipe()
+	origStdout := os.Stdout
+	os.Stdout = w
+	err := handleRangeReport()
+	w.Close()
+	os.Stdout = origStdout
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	if err != nil {
+		t.Fatalf("handleRangeReport() error = %v", err)
+	}
+
+	var c tracker.ReportComparison
+	if err := json.Unmarshal(buf.Bytes(), &c); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if c.Current.Commits != 1 || c.Current.Summary.HumanLines != 2 || c.Current.Summary.AILines != 0 {
+		t.Errorf("current = %+v, want 1 commit with 2 human lines", c.Current)
+	}
+	if c.Previous.Commits != 1 || c.Previous.Summary.AILines != 3 || c.Previous.Summary.HumanLines != 0 {
+		t.Errorf("previous = %+v, want 1 commit with 3 AI lines", c.Previous)
+	}
+	if c.Delta.AILines != -3 || c.Delta.HumanLines != 2 || c.Delta.AIPercentagePoints != -100 {
+		t.Errorf("delta = %+v", c.Delta)
+	}
+	if c.Current.Range != "since 30d" || !strings.Contains(c.Previous.Range, " – ") {
+		t.Errorf("ranges = %q / %q", c.Current.Range, c.Previous.Range)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/blame"
+	"github.com/y-hirakaw/ai-code-tracker/internal/git"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/ownership"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// maxRewriteBlameLines を超える行を削除・変更したファイルは、行ごとの -L 指定ではなくファイル全体を blame します。
+const maxRewriteBlameLines = 200
+
+// removedLines はコミットで削除・変更されたファイルの親側のパスと行番号です。
+type removedLines struct {
+	oldPath string
+	lines   []int
+}
+
+// recordRewrittenLines はコミットが削除・変更した親コミットの行を blame し、
+// その行を書いた作成者の種別ごとの行数を log の各ファイルの Rewritten に記録します。
+// 初回コミット・マージコミットでは記録せず、blame に失敗したファイルも記録しません（集計は削除行数のみになる）。
+func recordRewrittenLines(executor gitexec.Executor, log *tracker.AuthorshipLog) {
+	if len(log.Files) == 0 || git.IsMergeCommit(executor, log.Commit) {
+		return
+	}
+	parent, err := executor.Run("rev-parse", "--verify", "--quiet", log.Commit+"^")
+	if err != nil {
+		return // 初回コミット
+	}
+	output, err := executor.Run("diff", "--unified=0", "--no-color", "--no-ext-diff", "-M", parent, log.Commit)
+	if err != nil {
+		debugf("rewritten lines: diff failed: %v", err)
+		return
+	}
+
+	removed := parseRemovedLines(output)
+	var requests []blame.Request
+	newPaths := make(map[string]string) // 親側のパス → Authorship Logのパス
+	for path, r := range removed {
+		if _, ok := log.Files[path]; !ok || r.oldPath == "" || len(r.lines) == 0 {
+			continue
+		}
+		req := blame.Request{Path: r.oldPath, Lines: r.lines}
+		if len(r.lines) > maxRewriteBlameLines {
+			req.Lines = nil
+		}
+		requests = append(requests, req)
+		newPaths[r.oldPath] = path
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].Path < requests[j].Path })
+
+	logs := ownership.NewLogCache(gitnotes.NewNotesManagerWithExecutor(executor))
+	logs.Debugf = debugf
+	_ = blame.Stream(executor, parent, requests, blame.DefaultWorkers(), func(res blame.Result) error {
+		if res.Err != nil {
+			debugf("rewritten lines: skipping %s: %v", res.Path, res.Err)
+			return nil
+		}
+		path := newPaths[res.Path]
+		if rewritten := countRewrittenLines(res.Origins, removed[path].lines, logs); rewritten != nil {
+			info := log.Files[path]
+			info.Rewritten = rewritten
+			log.Files[path] = info
+		}
+		return nil
+	})
+}
+
+// countRewrittenLines は削除・変更された行を書いた作成者の種別ごとに行数を数えます。
+// ai_weight のある作成者の行は割合で按分します。Authorship Logのない行しかない場合は nil です。
+func countRewrittenLines(origins map[int]blame.Origin, lines []int, logs *ownership.LogCache) *tracker.RewrittenLines {
+	var ai, human float64
+	for _, n := range lines {
+		origin, ok := origins[n]
+		if !ok {
+			continue
+		}
+		author := logs.Author(origin)
+		if author == nil {
+			continue
+		}
+		share := author.AIShare()
+		ai += share
+		human += 1 - share
+	}
+	rewritten := &tracker.RewrittenLines{AI: int(math.Round(ai)), Human: int(math.Round(human))}
+	if rewritten.AI == 0 && rewritten.Human == 0 {
+		return nil
+	}
+	return rewritten
+}
+
+// parseRemovedLines は "git diff --unified=0" の出力から、ファイル（新しいパス）ごとに
+// 親側のパスと削除・変更された親側の行番号を返します。削除されたファイルは含めません。
+func parseRemovedLines(diffOutput string) map[string]*removedLines {
+	result := make(map[string]*removedLines)
+	var oldPath string
+	var current *removedLines
+	inHeader := false
+	for _, line := range strings.Split(diffOutput, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			inHeader, oldPath, current = true, "", nil
+		case inHeader && strings.HasPrefix(line, "--- "):
+			oldPath = diffPath(line[4:], "a/")
+		case inHeader && strings.HasPrefix(line, "+++ "):
+			if newPath := diffPath(line[4:], "b/"); newPath != "" {
+				current = &removedLines{oldPath: oldPath}
+				result[newPath] = current
+			}
+		case strings.HasPrefix(line, "@@"):
+			inHeader = false
+			if current == nil {
+				continue
+			}
+			for _, h := range parseHunkHeaders(line) {
+				for n := h[0]; n < h[0]+h[1]; n++ {
+					current.lines = append(current.lines, n)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// diffPath は diff のファイルヘッダのパス（"a/path"、引用符付き、"/dev/null"）から接頭辞を除いたパスを返します。
+// /dev/null の場合は空文字列です。
+func diffPath(s, prefix string) string {
+	s = strings.TrimSuffix(s, "\t")
+	if strings.HasPrefix(s, `"`) {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			s = unquoted
+		}
+	}
+	if s == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(s, prefix)
+}
+
+// accumulateRewrites はファイルで削除・変更された既存行のうち、もう一方の種別が書いた行を混在として集計します。
+// 削除をどちらの種別が行ったかはファイルの削除行数の按分（aiDeleted / humanDeleted）の比で配分します。
+func accumulateRewrites(result *authorStatsResult, rewritten *tracker.RewrittenLines, aiDeleted, humanDeleted int) {
+	if rewritten == nil || aiDeleted+humanDeleted == 0 {
+		return
+	}
+	total := float64(aiDeleted + humanDeleted)
+	mixed := &result.detailedMetrics.Mixed
+	mixed.AIEditedByHuman += int(math.Round(float64(rewritten.AI) * float64(humanDeleted) / total))
+	mixed.HumanEditedByAI += int(math.Round(float64(rewritten.Human) * float64(aiDeleted) / total))
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestParseRemovedLines(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want map[string]removedLines
+	}{
+		{
+			name: "変更と削除のhunk",
+			diff: "diff --git a/main.go b/main.go\nindex 1..2 100644\n--- a/main.go\n+++ b/main.go\n@@ -2,2 +2,3 @@ func main() {\n-\ta()\n-\tb()\n+\tc()\n@@ -10 +11,0 @@\n--- not a header\n",
+			want: map[string]removedLines{"main.go": {oldPath: "main.go", lines: []int{2, 3, 10}}},
+		},
+		{
+			name: "追加のみのhunk",
+			diff: "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -3,0 +4,2 @@\n+x\n+y\n",
+			want: map[string]removedLines{"a.go": {oldPath: "a.go"}},
+		},
+		{
+			name: "リネームと引用符付きパス",
+			diff: "diff --git a/old.go \"b/new file.go\"\nsimilarity index 90%\nrename from old.go\nrename to new file.go\n--- a/old.go\n+++ \"b/new file.go\"\n@@ -1 +1 @@\n-x\n+y\n",
+			want: map[string]removedLines{"new file.go": {oldPath: "old.go", lines: []int{1}}},
+		},
+		{
+			name: "新規ファイルと削除されたファイル",
+			diff: "diff --git a/new.go b/new.go\nnew file mode 100644\n--- /dev/null\n+++ b/new.go\n@@ -0,0 +1,2 @@\n+a\n+b\ndiff --git a/gone.go b/gone.go\ndeleted file mode 100644\n--- a/gone.go\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+			want: map[string]removedLines{"new.go": {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseRemovedLines(tt.diff)
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseRemovedLines() = %+v, want %+v", got, tt.want)
+			}
+			for path, want := range tt.want {
+				if r := got[path]; r == nil || r.oldPath != want.oldPath || !reflect.DeepEqual(r.lines, want.lines) {
+					t.Errorf("parseRemovedLines()[%q] = %+v, want %+v", path, r, want)
+				}
+			}
+		})
+	}
+}
+
+// TestHandleCommit_RewrittenLines はAIが書いた行を人間が変更したコミットを混在として集計することを検証する
+func TestHandleCommit_RewrittenLines(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	base := testutil.GitCommit(t, tmpDir, "initial")
+
+	// AIが4行のファイルを作成
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc a() {}\nfunc b() {}\n")
+	if err := recordCheckpoint("Claude", "", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+	testutil.GitCommit(t, tmpDir, "AI adds main.go")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	// 人間がAIの2行を書き換える
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc a() { println() }\nfunc b() { println() }\n")
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("human checkpoint error = %v", err)
+	}
+	testutil.GitCommit(t, tmpDir, "human edits main.go")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	alog, err := gitnotes.NewNotesManager().GetAuthorshipLog("HEAD")
+	if err != nil || alog == nil {
+		t.Fatalf("GetAuthorshipLog() = %v, %v", alog, err)
+	}
+	if r := alog.Files["main.go"].Rewritten; r == nil || r.AI != 2 || r.Human != 0 {
+		t.Errorf("Rewritten = %+v, want AI=2", r)
+	}
+
+	result, _, err := collectAuthorStats(base+"..HEAD", 1, nil)
+	if err != nil {
+		t.Fatalf("collectAuthorStats() error = %v", err)
+	}
+	if got := result.detailedMetrics.Mixed; got.AIEditedByHuman != 2 || got.HumanEditedByAI != 0 {
+		t.Errorf("Mixed = %+v, want AIEditedByHuman=2", got)
+	}
+
+	// 日次集計からも同じ値になる
+	fromRollup, _, ok := collectRollupStats(base + "..HEAD")
+	if !ok {
+		t.Fatal("collectRollupStats() should cover the range")
+	}
+	if fromRollup.detailedMetrics.Mixed != result.detailedMetrics.Mixed {
+		t.Errorf("rollup Mixed = %+v, per-commit Mixed = %+v", fromRollup.detailedMetrics.Mixed, result.detailedMetrics.Mixed)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// recordDailyRollup はコミット1件分の集計を daily_rollups.jsonl に加算します。
+// alog が nil の場合（追跡対象の変更なし）もコミットを記録し、期間レポートで網羅判定できるようにします。
+func recordDailyRollup(store *storage.AIctStorage, commitHash string, alog *tracker.AuthorshipLog, numstatMap map[string][2]int) {
+	start := time.Now()
+	executor := newExecutor()
+	date, err := executor.Run("log", "-1", "--format=%cs", commitHash)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to get commit date for rollup: %v\n", err)
+		return
+	}
+
+	branch, _ := executor.Run("rev-parse", "--abbrev-ref", "HEAD")
+	if branch == "HEAD" {
+		branch = "" // detached HEAD
+	}
+
+	delta := &tracker.DailyRollup{
+		Date:    date,
+		Branch:  branch,
+		Commits: []string{commitHash},
+	}
+
+	fillRollupDelta(delta, alog, numstatMap)
+
+	if err := store.MergeDailyRollup(delta); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update daily rollup: %v\n", err)
+		return
+	}
+	logTiming(store, timingEventRollup, time.Since(start))
+}
+
+// fillRollupDelta はコミット1件分のAuthorship Logとnumstatから集計値を delta に設定します。
+// alog が nil の場合は何もしません（集計値0のコミット）。
+func fillRollupDelta(delta *tracker.DailyRollup, alog *tracker.AuthorshipLog, numstatMap map[string][2]int) {
+	if alog == nil {
+		return
+	}
+	result := &authorStatsResult{byAuthor: make(map[string]*tracker.AuthorStats)}
+	authorsInCommit := processCommitFiles(result, alog, numstatMap)
+
+	m := result.detailedMetrics.WorkVolume
+	delta.AIAdded, delta.AIDeleted = m.AIAdded, m.AIDeleted
+	delta.HumanAdded, delta.HumanDeleted = m.HumanAdded, m.HumanDeleted
+	mixed := result.detailedMetrics.Mixed
+	delta.AIEditedByHuman, delta.HumanEditedByAI = mixed.AIEditedByHuman, mixed.HumanEditedByAI
+
+	delta.Authors = make(map[string]*tracker.RollupAuthor, len(result.byAuthor))
+	for name, stats := range result.byAuthor {
+		commits := 0
+		if authorsInCommit[name] {
+			commits = 1
+		}
+		delta.Authors[name] = &tracker.RollupAuthor{Type: stats.Type, Lines: stats.Lines, Deleted: stats.Deleted, Commits: commits, AILines: stats.AILines}
+	}
+}
+
+// collectRollupStats は日次集計からコミット範囲の統計を組み立てます。
+// 範囲内の全コミットが集計済みで、かつ範囲外のコミットを含む日次レコードがない場合のみ
+// ok=true を返します。それ以外はコミット単位の集計（collectAuthorStats）にフォールバックします。
+func collectRollupStats(rangeSpec string) (result *authorStatsResult, commitCount int, ok bool) {
+	rollups, commitCount, ok := loadRangeRollups(rangeSpec)
+	if !ok {
+		return nil, 0, false
+	}
+	result = &authorStatsResult{byAuthor: make(map[string]*tracker.AuthorStats)}
+	for _, r := range rollups {
+		addRollupToResult(result, r)
+	}
+	debugf("report built from %d daily rollups", len(rollups))
+	return result, commitCount, true
+}
+
+// loadRangeRollups はコミット範囲のコミットを含む日次レコードと範囲のコミット数を返します。
+// 範囲内の全コミットが集計済みで、かつ範囲外のコミットを含む日次レコードがない場合のみ ok=true です。
+func loadRangeRollups(rangeSpec string) (matchedRollups []*tracker.DailyRollup, commitCount int, ok bool) {
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		return nil, 0, false
+	}
+	rollups, err := store.LoadDailyRollups()
+	if err != nil || len(rollups) == 0 {
+		return nil, 0, false
+	}
+
+	commits, err := getCommitsInRange(rangeSpec)
+	if err != nil || len(commits) == 0 {
+		return nil, 0, false
+	}
+	inRange := make(map[string]bool, len(commits))
+	for _, c := range commits {
+		inRange[c] = true
+	}
+
+	covered := 0
+	for _, r := range rollups {
+		matched := 0
+		for _, c := range r.Commits {
+			if inRange[c] {
+				matched++
+			}
+		}
+		if matched == 0 {
+			continue
+		}
+		if matched != len(r.Commits) {
+			debugf("rollup %s/%s partially overlaps range, falling back to per-commit aggregation", r.Date, r.Branch)
+			return nil, 0, false
+		}
+		covered += matched
+		matchedRollups = append(matchedRollups, r)
+	}
+
+	if covered != len(commits) {
+		debugf("rollups cover %d of %d commits, falling back to per-commit aggregation", covered, len(commits))
+		return nil, 0, false
+	}
+	return matchedRollups, len(commits), true
+}
+
+// addRollupToResult は日次レコードを集計結果に加算します。
+func addRollupToResult(result *authorStatsResult, r *tracker.DailyRollup) {
+	accumulateMetrics(result, tracker.AuthorTypeAI, r.AIAdded, r.AIDeleted)
+	accumulateMetrics(result, tracker.AuthorTypeHuman, r.HumanAdded, r.HumanDeleted)
+	result.detailedMetrics.Mixed.AIEditedByHuman += r.AIEditedByHuman
+	result.detailedMetrics.Mixed.HumanEditedByAI += r.HumanEditedByAI
+
+	for name, a := range r.Authors {
+		stats, exists := result.byAuthor[name]
+		if !exists {
+			stats = &tracker.AuthorStats{Name: name, Type: a.Type}
+			result.byAuthor[name] = stats
+		}
+		stats.Lines += a.Lines
+		stats.Deleted += a.Deleted
+		stats.Commits += a.Commits
+		stats.AILines += a.AILines
+		if a.AILines == 0 && a.Type == tracker.AuthorTypeAI {
+			stats.AILines += a.Lines // ai_lines 導入前のレコード
+		}
+	}
+}
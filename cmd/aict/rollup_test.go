@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestDailyRollup_MatchesPerCommitStats(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	base := testutil.GitCommit(t, tmpDir, "Base commit")
+
+	testutil.CreateTestFile(t, tmpDir, "a.go", "package main\n\nfunc a() {}\n")
+	testutil.GitCommit(t, tmpDir, "Add a")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "b.go", "package main\n\nfunc b() {}\nfunc c() {}\n")
+	testutil.GitCommit(t, tmpDir, "Add b")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		t.Fatalf("NewAIctStorage() error = %v", err)
+	}
+	rollups, err := store.LoadDailyRollups()
+	if err != nil {
+		t.Fatalf("LoadDailyRollups() error = %v", err)
+	}
+	if len(rollups) != 1 || len(rollups[0].Commits) != 2 {
+		t.Fatalf("Expected 1 rollup with 2 commits, got %+v", rollups)
+	}
+
+	rangeSpec := base + "..HEAD"
+	fromRollup, count, ok := collectRollupStats(rangeSpec)
+	if !ok {
+		t.Fatal("collectRollupStats() should cover the range")
+	}
+	perCommit, wantCount, err := collectAuthorStats(rangeSpec, 1, nil)
+	if err != nil {
+		t.Fatalf("collectAuthorStats() error = %v", err)
+	}
+
+	if count != wantCount {
+		t.Errorf("commit count = %d, want %d", count, wantCount)
+	}
+	if fromRollup.totalAI != perCommit.totalAI || fromRollup.totalHuman != perCommit.totalHuman {
+		t.Errorf("rollup totals = %d/%d, per-commit totals = %d/%d",
+			fromRollup.totalAI, fromRollup.totalHuman, perCommit.totalAI, perCommit.totalHuman)
+	}
+	if fromRollup.detailedMetrics.WorkVolume != perCommit.detailedMetrics.WorkVolume {
+		t.Errorf("rollup work volume = %+v, per-commit = %+v",
+			fromRollup.detailedMetrics.WorkVolume, perCommit.detailedMetrics.WorkVolume)
+	}
+	for name, want := range perCommit.byAuthor {
+		got := fromRollup.byAuthor[name]
+		if got == nil || got.Lines != want.Lines || got.Commits != want.Commits {
+			t.Errorf("author %s: rollup = %+v, per-commit = %+v", name, got, want)
+		}
+	}
+
+	// 日次集計に含まれないコミット（aict commit未実行）がある場合はフォールバック
+	testutil.CreateTestFile(t, tmpDir, "c.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "Add c without aict commit")
+	if _, _, ok := collectRollupStats(base + "..HEAD"); ok {
+		t.Error("collectRollupStats() should fall back when a commit is missing from rollups")
+	}
+
+	// 範囲外のコミットを含む日次レコードがある場合もフォールバック
+	if _, _, ok := collectRollupStats("HEAD~2..HEAD"); ok {
+		t.Error("collectRollupStats() should fall back when a rollup partially overlaps the range")
+	}
+}
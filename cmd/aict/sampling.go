@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// sampleBuckets はサンプリング判定に使うハッシュ空間の分割数
+const sampleBuckets = 10000
+
+// parseSampleRate は "10%" や "10" 形式のサンプル率を 0〜1 の比率に変換します。
+func parseSampleRate(s string) (float64, error) {
+	numStr := strings.TrimSuffix(strings.TrimSpace(s), "%")
+	pct, err := strconv.ParseFloat(numStr, 64)
+	if err != nil || pct <= 0 || pct > 100 {
+		return 0, fmt.Errorf("invalid --sample value %q: expected a percentage between 0 and 100 (e.g., '10%%')", s)
+	}
+	return pct / 100, nil
+}
+
+// sampleBucket はコミットハッシュから決定的なバケット番号を求めます。
+func sampleBucket(commitHash string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(commitHash))
+	return h.Sum64() % sampleBuckets
+}
+
+// sampleCommits はコミットハッシュをシードとして決定的にサンプリングします。
+// 同じコミットは実行のたびに同じ判定になるため、範囲が伸びても既存の選択は変わりません。
+// 該当が0件の場合はバケット番号が最小のコミットを1件選びます。
+func sampleCommits(commits []string, rate float64) []string {
+	threshold := uint64(rate * sampleBuckets)
+	var sampled []string
+	for _, c := range commits {
+		if sampleBucket(c) < threshold {
+			sampled = append(sampled, c)
+		}
+	}
+
+	if len(sampled) == 0 && len(commits) > 0 {
+		best := commits[0]
+		for _, c := range commits[1:] {
+			if sampleBucket(c) < sampleBucket(best) {
+				best = c
+			}
+		}
+		sampled = []string{best}
+	}
+	return sampled
+}
+
+// extrapolateSample はサンプル集計結果を全コミット数に合わせて外挿します。
+func extrapolateSample(result *authorStatsResult, totalCommits int) {
+	if result.sampledCommits <= 0 || result.sampledCommits >= totalCommits {
+		return
+	}
+	factor := float64(totalCommits) / float64(result.sampledCommits)
+	scale := func(v int) int { return int(math.Round(float64(v) * factor)) }
+
+	result.totalAI = scale(result.totalAI)
+	result.totalHuman = scale(result.totalHuman)
+	for _, stats := range result.byAuthor {
+		stats.Lines = scale(stats.Lines)
+		stats.Deleted = scale(stats.Deleted)
+		stats.Commits = scale(stats.Commits)
+		stats.AILines = scale(stats.AILines)
+	}
+	for _, stats := range result.byFile {
+		stats.AILines = scale(stats.AILines)
+		stats.HumanLines = scale(stats.HumanLines)
+		stats.TotalLines = stats.AILines + stats.HumanLines
+	}
+
+	m := &result.detailedMetrics
+	m.Contributions.AIAdditions = scale(m.Contributions.AIAdditions)
+	m.Contributions.HumanAdditions = scale(m.Contributions.HumanAdditions)
+	m.WorkVolume.AIAdded = scale(m.WorkVolume.AIAdded)
+	m.WorkVolume.AIDeleted = scale(m.WorkVolume.AIDeleted)
+	m.WorkVolume.AIChanges = scale(m.WorkVolume.AIChanges)
+	m.WorkVolume.HumanAdded = scale(m.WorkVolume.HumanAdded)
+	m.WorkVolume.HumanDeleted = scale(m.WorkVolume.HumanDeleted)
+	m.WorkVolume.HumanChanges = scale(m.WorkVolume.HumanChanges)
+	m.NewFiles.AINewLines = scale(m.NewFiles.AINewLines)
+	m.NewFiles.HumanNewLines = scale(m.NewFiles.HumanNewLines)
+	m.Mixed.AIEditedByHuman = scale(m.Mixed.AIEditedByHuman)
+	m.Mixed.HumanEditedByAI = scale(m.Mixed.HumanEditedByAI)
+}
+
+// sampleMarginOfError はAI%の95%信頼区間の半幅（パーセントポイント）を概算します。
+// コミットを標本単位とし、有限母集団修正を適用した比率の標準誤差を使います。
+func sampleMarginOfError(aiPercentage float64, sampled, total int) float64 {
+	if sampled <= 0 || total <= 1 || sampled >= total {
+		return 0
+	}
+	p := aiPercentage / 100
+	fpc := float64(total-sampled) / float64(total-1)
+	return 1.96 * math.Sqrt(p*(1-p)/float64(sampled)*fpc) * 100
+}
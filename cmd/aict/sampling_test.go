@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestParseSampleRate(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    float64
+		wantErr bool
+	}{
+		{"10%", 0.10, false},
+		{"10", 0.10, false},
+		{"0.5%", 0.005, false},
+		{"100%", 1.0, false},
+		{"0%", 0, true},
+		{"150%", 0, true},
+		{"-5%", 0, true},
+		{"abc", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseSampleRate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSampleRate(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("parseSampleRate(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSampleCommits(t *testing.T) {
+	var commits []string
+	for i := 0; i < 1000; i++ {
+		commits = append(commits, fmt.Sprintf("%040x", i))
+	}
+
+	first := sampleCommits(commits, 0.1)
+	second := sampleCommits(commits, 0.1)
+	if len(first) != len(second) {
+		t.Fatalf("sampling is not deterministic: %d vs %d", len(first), len(second))
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("sampling is not deterministic at %d: %s vs %s", i, first[i], second[i])
+		}
+	}
+
+	// 10%前後が選ばれること
+	if len(first) < 50 || len(first) > 150 {
+		t.Errorf("sampled %d of 1000 commits at 10%%, expected roughly 100", len(first))
+	}
+
+	// 範囲が伸びても既存コミットの選択は変わらない
+	subset := sampleCommits(commits[:500], 0.1)
+	selected := make(map[string]bool)
+	for _, c := range first {
+		selected[c] = true
+	}
+	for _, c := range subset {
+		if !selected[c] {
+			t.Errorf("commit %s sampled from subset but not from full range", c)
+		}
+	}
+
+	if got := sampleCommits(commits, 1.0); len(got) != len(commits) {
+		t.Errorf("100%% sample returned %d commits, want %d", len(got), len(commits))
+	}
+
+	// 該当なしでも最低1件は選ぶ
+	if got := sampleCommits(commits[:3], 0.0001); len(got) != 1 {
+		t.Errorf("tiny sample returned %d commits, want 1", len(got))
+	}
+}
+
+func TestExtrapolateSample(t *testing.T) {
+	result := &authorStatsResult{
+		byAuthor: map[string]*tracker.AuthorStats{
+			"Claude Code": {Name: "Claude Code", Lines: 30, Commits: 2, AILines: 30},
+		},
+		totalAI:        30,
+		totalHuman:     10,
+		sampledCommits: 2,
+	}
+	result.detailedMetrics.WorkVolume.AIAdded = 30
+	result.detailedMetrics.Contributions.HumanAdditions = 10
+
+	extrapolateSample(result, 20)
+
+	if result.totalAI != 300 || result.totalHuman != 100 {
+		t.Errorf("totals = %d/%d, want 300/100", result.totalAI, result.totalHuman)
+	}
+	if got := result.byAuthor["Claude Code"]; got.Lines != 300 || got.Commits != 20 || got.AILines != 300 {
+		t.Errorf("author stats = %d lines/%d commits/%d AI lines, want 300/20/300", got.Lines, got.Commits, got.AILines)
+	}
+	if result.detailedMetrics.WorkVolume.AIAdded != 300 {
+		t.Errorf("AIAdded = %d, want 300", result.detailedMetrics.WorkVolume.AIAdded)
+	}
+	if result.detailedMetrics.Contributions.HumanAdditions != 100 {
+		t.Errorf("HumanAdditions = %d, want 100", result.detailedMetrics.Contributions.HumanAdditions)
+	}
+}
+
+func TestSampleMarginOfError(t *testing.T) {
+	tests := []struct {
+		name    string
+		pct     float64
+		sampled int
+		total   int
+		wantMin float64
+		wantMax float64
+	}{
+		{"full population", 50, 100, 100, 0, 0},
+		{"no sample", 50, 0, 100, 0, 0},
+		{"10% of 1000 at 50%", 50, 100, 1000, 9.0, 9.6},
+		{"all AI", 100, 100, 1000, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sampleMarginOfError(tt.pct, tt.sampled, tt.total)
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("sampleMarginOfError(%v, %d, %d) = %v, want in [%v, %v]",
+					tt.pct, tt.sampled, tt.total, got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
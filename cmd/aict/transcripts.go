@@ -0,0 +1,415 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/authorship"
+	"github.com/y-hirakaw/ai-code-tracker/internal/git"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// transcriptImportMessage は transcript から生成した作成者エントリのメタデータに記録するメッセージ
+const transcriptImportMessage = "Imported from Claude Code transcripts"
+
+// transcriptEdit はClaude Codeの transcript に記録されたファイル編集1件分の、書き込まれた行です。
+type transcriptEdit struct {
+	time  time.Time
+	path  string          // リポジトリルートからの相対パス
+	lines map[string]bool // 書き込まれた行（前後の空白を除き、空行は含めない）
+}
+
+// transcriptImportResult は transcript から生成したAuthorship Logの件数と行数です。
+type transcriptImportResult struct {
+	logs       []*tracker.AuthorshipLog
+	existing   int // Authorship Log記録済みでスキップしたコミット
+	outside    int // transcript の期間外でスキップしたコミット
+	aiLines    int
+	humanLines int
+}
+
+// handleImportClaudeTranscripts はClaude Codeの transcript（~/.claude/projects/<project>/*.jsonl）から
+// aict導入前のコミットのAuthorship Logを生成します。
+func handleImportClaudeTranscripts(args []string) error {
+	flags := flag.NewFlagSet("import claude-transcripts", flag.ExitOnError)
+	rangeFlag := flags.String("range", "", "Commit range to import (default: all of HEAD)")
+	since := flags.String("since", "", "Import commits since date (e.g., '6m', '2025-01-01')")
+	window := flags.Duration("window", 7*24*time.Hour, "Match lines Claude Code wrote within this period before each commit")
+	dryRun := flags.Bool("dry-run", false, "Show what would be imported without writing authorship logs")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: aict import claude-transcripts [options] <file-or-dir>...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		flags.Usage()
+		return fmt.Errorf("transcript file or directory is required (e.g., ~/.claude/projects/<project>)")
+	}
+	if *rangeFlag != "" && *since != "" {
+		return fmt.Errorf("--range and --since are mutually exclusive")
+	}
+	if *window <= 0 {
+		return fmt.Errorf("--window must be positive")
+	}
+
+	store, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return err
+	}
+	executor := newExecutor()
+	repoRoot, err := executor.Run("rev-parse", "--show-toplevel")
+	if err != nil {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	rangeSpec := *rangeFlag
+	if *since != "" {
+		if rangeSpec, err = convertSinceToRange(*since); err != nil {
+			return err
+		}
+	}
+	if rangeSpec == "" {
+		rangeSpec = "HEAD"
+	}
+
+	files, err := collectTranscriptFiles(flags.Args())
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no transcript files found (expected *.jsonl)")
+	}
+	var edits []transcriptEdit
+	for _, file := range files {
+		fileEdits, err := readClaudeTranscript(file, repoRoot)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", file, err)
+		}
+		edits = append(edits, fileEdits...)
+	}
+	if len(edits) == 0 {
+		fmt.Printf("No file edits in this repository found in %d transcript file(s)\n", len(files))
+		return nil
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].time.Before(edits[j].time) })
+
+	result, err := buildTranscriptLogs(rangeSpec, edits, *window, cfg)
+	if err != nil {
+		return err
+	}
+
+	if !*dryRun {
+		nm := gitnotes.NewNotesManagerWithExecutor(executor)
+		for _, log := range result.logs {
+			redactAuthorshipLog(store, cfg, log)
+			if err := nm.AddAuthorshipLog(log); err != nil {
+				return fmt.Errorf("saving authorship log for %s: %w", shortCommit(log.Commit), err)
+			}
+		}
+		if len(result.logs) > 0 {
+			// 取り込んだコミットを含む日次集計はAuthorship Logのない状態で集計されているため破棄
+			hashes := make([]string, 0, len(result.logs))
+			for _, log := range result.logs {
+				hashes = append(hashes, log.Commit)
+			}
+			if _, err := store.DropDailyRollups(hashes); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to invalidate daily rollups: %v\n", err)
+			}
+		}
+	}
+
+	verb := "Imported"
+	if *dryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("✓ %s authorship logs for %d commit(s) from %d transcript file(s) (%d edits)\n", verb, len(result.logs), len(files), len(edits))
+	fmt.Printf("  Lines: □ AI %d / ○ Human %d\n", result.aiLines, result.humanLines)
+	if result.existing > 0 {
+		fmt.Printf("  Skipped %d commit(s) that already have authorship logs\n", result.existing)
+	}
+	if result.outside > 0 {
+		fmt.Printf("  Skipped %d commit(s) outside the transcripts' period (%s to %s)\n", result.outside,
+			edits[0].time.Local().Format("2006-01-02"), edits[len(edits)-1].time.Local().Format("2006-01-02"))
+	}
+	return nil
+}
+
+// buildTranscriptLogs は範囲内のAuthorship Logのないコミットについて、追加行のうち
+// コミット前 window 以内に transcript で書き込まれた行と一致する行をAI、それ以外をコミットの作成者の行とします。
+// transcript の最初の編集より前、または最後の編集から window 以上後のコミットは判定できないため対象外です。
+func buildTranscriptLogs(rangeSpec string, edits []transcriptEdit, window time.Duration, cfg *tracker.Config) (*transcriptImportResult, error) {
+	executor := newExecutor()
+	output, err := executor.Run("log", "--reverse", "--no-merges", "--format=%H %cI %aN", "--end-of-options", rangeSpec)
+	if err != nil {
+		return nil, fmt.Errorf("listing commits: %w", err)
+	}
+	existing, err := gitnotes.NewNotesManagerWithExecutor(executor).ListAuthorshipLogs()
+	if err != nil {
+		return nil, fmt.Errorf("loading authorship logs: %w", err)
+	}
+
+	first, last := edits[0].time, edits[len(edits)-1].time
+	result := &transcriptImportResult{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		hash, authorName := fields[0], fields[2]
+		committed, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			continue
+		}
+		if _, ok := existing[hash]; ok {
+			result.existing++
+			continue
+		}
+		if committed.Before(first) || committed.Sub(last) > window {
+			result.outside++
+			continue
+		}
+
+		added, err := git.GetAddedLines(executor, hash)
+		if err != nil {
+			return nil, err
+		}
+		log := &tracker.AuthorshipLog{
+			Version:   authorship.AuthorshipLogVersion,
+			Commit:    hash,
+			Timestamp: committed,
+			Files:     make(map[string]tracker.FileInfo),
+		}
+		for path, lines := range added {
+			if !tracker.IsRecordedFile(path, cfg) {
+				continue
+			}
+			ai, human := matchTranscriptLines(lines, writtenLines(edits, path, committed.Add(-window), committed))
+			log.Files[path] = transcriptFileInfo(ai, human, authorName)
+			result.aiLines += len(ai)
+			result.humanLines += len(human)
+		}
+		if err := authorship.ValidateAuthorshipLog(log); err != nil {
+			return nil, fmt.Errorf("invalid authorship log for %s: %w", shortCommit(hash), err)
+		}
+		result.logs = append(result.logs, log)
+	}
+	return result, nil
+}
+
+// writtenLines は from より後、to 以前に transcript で path に書き込まれた行の集合を返します。
+func writtenLines(edits []transcriptEdit, path string, from, to time.Time) map[string]bool {
+	written := make(map[string]bool)
+	for _, e := range edits {
+		if e.path != path || !e.time.After(from) || e.time.After(to) {
+			continue
+		}
+		for l := range e.lines {
+			written[l] = true
+		}
+	}
+	return written
+}
+
+// matchTranscriptLines は追加行を transcript で書き込まれた行（AI）とそれ以外（人間）に分けて行番号を返します。
+// 空行は直前の空行でない追加行と同じ側に数えます（先頭の空行は人間）。
+func matchTranscriptLines(lines []git.AddedLine, written map[string]bool) (ai, human []int) {
+	prevAI := false
+	for _, l := range lines {
+		text := strings.TrimSpace(l.Text)
+		isAI := prevAI
+		if text != "" {
+			isAI = written[text]
+			prevAI = isAI
+		}
+		if isAI {
+			ai = append(ai, l.Line)
+		} else {
+			human = append(human, l.Line)
+		}
+	}
+	return ai, human
+}
+
+// transcriptFileInfo はAI・人間の行番号から作成者エントリを作成します。
+// 追加行のない（削除のみの）ファイルはコミットの作成者に帰属させます。
+func transcriptFileInfo(ai, human []int, authorName string) tracker.FileInfo {
+	var info tracker.FileInfo
+	if len(ai) > 0 {
+		info.Authors = append(info.Authors, tracker.AuthorInfo{
+			Name:     hookAIAuthor,
+			Type:     tracker.AuthorTypeAI,
+			Lines:    git.LineRanges(ai),
+			Metadata: map[string]string{"message": transcriptImportMessage},
+		})
+	}
+	if len(human) > 0 || len(ai) == 0 {
+		lines := git.LineRanges(human)
+		if lines == nil {
+			lines = [][]int{}
+		}
+		info.Authors = append(info.Authors, tracker.AuthorInfo{
+			Name:     authorName,
+			Type:     tracker.AuthorTypeHuman,
+			Lines:    lines,
+			Metadata: map[string]string{"message": transcriptImportMessage},
+		})
+	}
+	return info
+}
+
+// collectTranscriptFiles は引数のファイル・ディレクトリから transcript ファイル（*.jsonl）を集めます。
+func collectTranscriptFiles(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.HasSuffix(d.Name(), ".jsonl") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// transcriptEntry はClaude Codeの transcript の1行のうち、aictが使う項目です。
+type transcriptEntry struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	CWD       string    `json:"cwd"`
+	Message   struct {
+		Content json.RawMessage `json:"content"` // 文字列またはブロックの配列
+	} `json:"message"`
+}
+
+// transcriptToolUse はアシスタントのメッセージのツール呼び出しです。
+type transcriptToolUse struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Input struct {
+		FilePath  string `json:"file_path"`
+		Content   string `json:"content"`    // Write
+		NewString string `json:"new_string"` // Edit
+		Edits     []struct {
+			NewString string `json:"new_string"`
+		} `json:"edits"` // MultiEdit
+	} `json:"input"`
+}
+
+// readClaudeTranscript は transcript ファイルから repoRoot 配下のファイルへの Write/Edit/MultiEdit を読み込みます。
+// 解析できない行は読み飛ばします（transcript の形式はClaude Codeのバージョンで変わるため）。
+func readClaudeTranscript(path, repoRoot string) ([]transcriptEdit, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var edits []transcriptEdit
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			edits = append(edits, parseTranscriptLine(line, repoRoot)...)
+		}
+		if errors.Is(err, io.EOF) {
+			return edits, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseTranscriptLine は transcript の1行からファイル編集を取り出します。
+func parseTranscriptLine(line []byte, repoRoot string) []transcriptEdit {
+	var entry transcriptEntry
+	if err := json.Unmarshal(line, &entry); err != nil || entry.Type != "assistant" || entry.Timestamp.IsZero() {
+		return nil
+	}
+	var blocks []transcriptToolUse
+	if err := json.Unmarshal(entry.Message.Content, &blocks); err != nil {
+		return nil
+	}
+
+	var edits []transcriptEdit
+	for _, b := range blocks {
+		if b.Type != "tool_use" {
+			continue
+		}
+		var texts []string
+		switch b.Name {
+		case "Write":
+			texts = []string{b.Input.Content}
+		case "Edit":
+			texts = []string{b.Input.NewString}
+		case "MultiEdit":
+			for _, e := range b.Input.Edits {
+				texts = append(texts, e.NewString)
+			}
+		default:
+			continue
+		}
+		rel := transcriptRelPath(b.Input.FilePath, repoRoot, entry.CWD)
+		if rel == "" {
+			continue
+		}
+		e := transcriptEdit{time: entry.Timestamp, path: rel, lines: make(map[string]bool)}
+		for _, text := range texts {
+			for _, l := range strings.Split(text, "\n") {
+				if l = strings.TrimSpace(l); l != "" {
+					e.lines[l] = true
+				}
+			}
+		}
+		if len(e.lines) > 0 {
+			edits = append(edits, e)
+		}
+	}
+	return edits
+}
+
+// transcriptRelPath は編集したファイルの絶対パスをリポジトリルートからの相対パスにします。
+// リポジトリの外のファイルは、transcript 記録時の作業ディレクトリ（別の場所のクローン）からの相対パスとします。
+// どちらの配下でもない場合は空文字列です。
+func transcriptRelPath(filePath, repoRoot, cwd string) string {
+	if filePath == "" {
+		return ""
+	}
+	for _, root := range []string{repoRoot, cwd} {
+		if root == "" {
+			continue
+		}
+		rel, err := filepath.Rel(root, filePath)
+		if err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return ""
+}
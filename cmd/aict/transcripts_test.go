@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/git"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/storage"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestParseTranscriptLine(t *testing.T) {
+	ts := "2025-01-15T10:00:00.000Z"
+	tests := []struct {
+		name string
+		line string
+		want map[string][]string // パス → 書き込まれた行
+	}{
+		{"write", `{"type":"assistant","timestamp":"` + ts + `","message":{"content":[{"type":"tool_use","name":"Write","input":{"file_path":"/repo/a.go","content":"package main\n\n  func a() {}\n"}}]}}`,
+			map[string][]string{"a.go": {"func a() {}", "package main"}}},
+		{"edit_and_multiedit", `{"type":"assistant","timestamp":"` + ts + `","message":{"content":[{"type":"text","text":"ok"},{"type":"tool_use","name":"Edit","input":{"file_path":"/repo/dir/b.go","old_string":"x","new_string":"y := 1"}},{"type":"tool_use","name":"MultiEdit","input":{"file_path":"/repo/c.go","edits":[{"old_string":"a","new_string":"b"},{"old_string":"c","new_string":"d"}]}}]}}`,
+			map[string][]string{"dir/b.go": {"y := 1"}, "c.go": {"b", "d"}}},
+		{"別の場所のクローンは cwd からの相対パス", `{"type":"assistant","timestamp":"` + ts + `","cwd":"/old/clone","message":{"content":[{"type":"tool_use","name":"Write","input":{"file_path":"/old/clone/a.go","content":"x"}}]}}`,
+			map[string][]string{"a.go": {"x"}}},
+		{"リポジトリ外", `{"type":"assistant","timestamp":"` + ts + `","message":{"content":[{"type":"tool_use","name":"Write","input":{"file_path":"/etc/hosts","content":"x"}}]}}`, nil},
+		{"読み取りツール", `{"type":"assistant","timestamp":"` + ts + `","message":{"content":[{"type":"tool_use","name":"Read","input":{"file_path":"/repo/a.go"}}]}}`, nil},
+		{"ユーザーのメッセージ", `{"type":"user","timestamp":"` + ts + `","message":{"content":"please edit a.go"}}`, nil},
+		{"not json", `{"type":`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string][]string
+			for _, e := range parseTranscriptLine([]byte(tt.line), "/repo") {
+				if got == nil {
+					got = make(map[string][]string)
+				}
+				for l := range e.lines {
+					got[e.path] = append(got[e.path], l)
+				}
+			}
+			for _, lines := range got {
+				sort.Strings(lines)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTranscriptLine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchTranscriptLines(t *testing.T) {
+	lines := []git.AddedLine{{Line: 1, Text: "package main"}, {Line: 2, Text: ""}, {Line: 3, Text: "\tfunc ai() {}"}, {Line: 4, Text: ""}, {Line: 5, Text: "func human() {}"}}
+	ai, human := matchTranscriptLines(lines, map[string]bool{"func ai() {}": true})
+	if !reflect.DeepEqual(ai, []int{3, 4}) || !reflect.DeepEqual(human, []int{1, 2, 5}) {
+		t.Errorf("matchTranscriptLines() = ai %v, human %v; want ai [3 4], human [1 2 5]", ai, human)
+	}
+}
+
+// TestImportClaudeTranscripts は transcript から過去のコミットのAuthorship Logを生成する
+func TestImportClaudeTranscripts(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+	origDir, _ := os.Getwd()
+	origArgs := os.Args
+	defer func() {
+		os.Chdir(origDir)
+		os.Args = origArgs
+	}()
+	os.Chdir(tmpDir)
+	repoRoot, _ := filepath.EvalSymlinks(tmpDir)
+
+	transcripts := t.TempDir()
+	edited := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	line := fmt.Sprintf(`{"type":"assistant","timestamp":%q,"message":{"content":[{"type":"tool_use","name":"Edit","input":{"file_path":%q,"new_string":"func ai() {}\n"}}]}}`,
+		edited, filepath.Join(repoRoot, "main.go"))
+	if err := os.WriteFile(filepath.Join(transcripts, "session.jsonl"), []byte(line+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	base := testutil.GitCommit(t, tmpDir, "initial")
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc ai() {}\nfunc human() {}\n")
+	commit := testutil.GitCommit(t, tmpDir, "add main.go")
+
+	nm := gitnotes.NewNotesManager()
+	os.Args = []string{"aict", "import", "claude-transcripts", "--dry-run", transcripts}
+	if err := handleImport(); err != nil {
+		t.Fatalf("handleImport(--dry-run) error = %v", err)
+	}
+	if logs, _ := nm.ListAuthorshipLogs(); len(logs) != 0 {
+		t.Fatalf("--dry-run should not write authorship logs, got %d", len(logs))
+	}
+
+	os.Args = []string{"aict", "import", "claude-transcripts", transcripts}
+	if err := handleImport(); err != nil {
+		t.Fatalf("handleImport() error = %v", err)
+	}
+
+	alog, err := nm.GetAuthorshipLog(commit)
+	if err != nil || alog == nil {
+		t.Fatalf("authorship log for %s not created: %v", commit, err)
+	}
+	want := []tracker.AuthorInfo{
+		{Name: hookAIAuthor, Type: tracker.AuthorTypeAI, Lines: [][]int{{3}}, Metadata: map[string]string{"message": transcriptImportMessage}},
+		{Name: "Test User", Type: tracker.AuthorTypeHuman, Lines: [][]int{{1, 2}, {4}}, Metadata: map[string]string{"message": transcriptImportMessage}},
+	}
+	if got := alog.Files["main.go"].Authors; !reflect.DeepEqual(got, want) {
+		t.Errorf("main.go authors = %+v, want %+v", got, want)
+	}
+	if baseLog, err := nm.GetAuthorshipLog(base); err != nil || baseLog == nil || baseLog.Files["base.go"].Authors[0].Type != tracker.AuthorTypeHuman {
+		t.Errorf("initial commit should be recorded as human, got %+v (%v)", baseLog, err)
+	}
+
+	// 2回目は記録済みのコミットを上書きしない
+	os.Args = []string{"aict", "import", "claude-transcripts", transcripts}
+	origStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err = handleImport()
+	w.Close()
+	os.Stdout = origStdout
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	if err != nil {
+		t.Fatalf("second handleImport() error = %v", err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, "for 0 commit(s)") || !strings.Contains(output, "Skipped 2 commit(s) that already have authorship logs") {
+		t.Errorf("second import output = %q", output)
+	}
+}
+
+// TestImportClaudeTranscripts_DropsDailyRollups は取り込み後の report --since が
+// 取り込み前の日次集計ではなく取り込んだAuthorship Logで集計されることを検証する
+func TestImportClaudeTranscripts_DropsDailyRollups(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+	t.Setenv("AICT_NO_DAEMON", "1")
+	origDir, _ := os.Getwd()
+	origArgs := os.Args
+	defer func() {
+		os.Chdir(origDir)
+		os.Args = origArgs
+	}()
+	os.Chdir(tmpDir)
+	repoRoot, _ := filepath.EvalSymlinks(tmpDir)
+
+	transcripts := t.TempDir()
+	edited := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	line := fmt.Sprintf(`{"type":"assistant","timestamp":%q,"message":{"content":[{"type":"tool_use","name":"Edit","input":{"file_path":%q,"new_string":"func ai() {}\n"}}]}}`,
+		edited, filepath.Join(repoRoot, "main.go"))
+	if err := os.WriteFile(filepath.Join(transcripts, "session.jsonl"), []byte(line+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	// aict commit がAuthorship Logなしで日次集計だけを記録したコミット（追跡対象外だった時期など）
+	store, err := storage.NewAIctStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n\nfunc ai() {}\nfunc human() {}\n")
+	testutil.GitCommit(t, tmpDir, "add main.go")
+	head, err := newExecutor().Run("rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordDailyRollup(store, head, nil, nil)
+
+	reportSince := func() tracker.SummaryStats {
+		t.Helper()
+		os.Args = []string{"aict", "report", "--since", "1d", "--format", "json"}
+		r, w, _ := os.Pipe()
+		origStdout := os.Stdout
+		os.Stdout = w
+		err := handleRangeReport()
+		w.Close()
+		os.Stdout = origStdout
+		var buf bytes.Buffer
+		buf.ReadFrom(r)
+		if err != nil {
+			t.Fatalf("handleRangeReport() error = %v", err)
+		}
+		var report tracker.Report
+		if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		return report.Summary
+	}
+
+	if before := reportSince(); before.AILines != 0 || before.TotalLines != 0 {
+		t.Fatalf("report before import = %+v, want the empty daily rollups", before)
+	}
+
+	os.Args = []string{"aict", "import", "claude-transcripts", transcripts}
+	if err := handleImport(); err != nil {
+		t.Fatalf("handleImport() error = %v", err)
+	}
+
+	if after := reportSince(); after.AILines != 1 || after.HumanLines != 3 {
+		t.Errorf("report after import = %+v, want 1 AI / 3 human lines from the imported logs", after)
+	}
+}
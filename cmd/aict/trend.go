@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// sparkBlocks はスパークラインの文字（AI% 0〜100 を8段階で表す）
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// trendBarWidth は推移の表のAI%バーの幅（100%のときの文字数）
+const trendBarWidth = 20
+
+// smoothUnitDays は --smooth の単位ごとの日数（m は30日として数える）
+var smoothUnitDays = map[byte]int{'d': 1, 'w': 7, 'm': 30}
+
+// trendPeriodDays は推移の期間ごとの日数（month は30日として数える）
+var trendPeriodDays = map[string]int{churnPeriodDay: 1, churnPeriodWeek: 7, churnPeriodMonth: 30}
+
+// collectTrend はコミット範囲の追加行数を期間ごとに集計し、AI%の推移を返します。
+// useRollups が true で日次集計が範囲を網羅する場合は日次集計から、それ以外はコミット単位で集計します（aict churn と同じ）。
+func collectTrend(rangeSpec, period string, useRollups bool) (*tracker.Trend, error) {
+	var periods []tracker.ChurnPeriod
+	fromRollups := false
+	if useRollups {
+		periods, fromRollups = rollupTrendPeriods(rangeSpec, period)
+	}
+	if !fromRollups {
+		churn, _, err := collectChurn(rangeSpec, period)
+		if err != nil {
+			return nil, err
+		}
+		periods = churn.Periods
+	}
+
+	trend := &tracker.Trend{Period: period, Points: make([]tracker.TrendPoint, 0, len(periods))}
+	for _, p := range periods {
+		trend.Points = append(trend.Points, tracker.TrendPoint{
+			Start:        p.Start,
+			Commits:      p.Commits,
+			AILines:      p.AIAdded,
+			HumanLines:   p.HumanAdded,
+			AIPercentage: metrics.SafePercent(p.AIAdded, p.AIAdded+p.HumanAdded),
+		})
+	}
+	return trend, nil
+}
+
+// trendNeedsPerCommit は日次集計（記録時の分類・全ファイル）を推移に使えない設定かを返します。
+// aict report と同じく、overrides・artifacts がある場合はコミット単位で集計します。
+func trendNeedsPerCommit() bool {
+	_, cfg, err := loadStorageAndConfig()
+	if err != nil {
+		return false
+	}
+	return len(cfg.Overrides) > 0 || cfg.Artifacts != nil
+}
+
+// rollupTrendPeriods は日次集計を期間ごとに合算します。日次集計が範囲を網羅しない場合は ok=false です。
+func rollupTrendPeriods(rangeSpec, period string) (periods []tracker.ChurnPeriod, ok bool) {
+	rollups, _, ok := loadRangeRollups(rangeSpec)
+	if !ok {
+		return nil, false
+	}
+	byStart := make(map[string]*tracker.ChurnPeriod)
+	for _, r := range rollups {
+		date, err := time.Parse("2006-01-02", r.Date)
+		if err != nil {
+			debugf("rollup has invalid date %q, falling back to per-commit aggregation", r.Date)
+			return nil, false
+		}
+		start := churnPeriodStart(date, period)
+		p, exists := byStart[start]
+		if !exists {
+			p = &tracker.ChurnPeriod{Start: start}
+			byStart[start] = p
+		}
+		p.Commits += len(r.Commits)
+		p.AIAdded += r.AIAdded
+		p.AIDeleted += r.AIDeleted
+		p.HumanAdded += r.HumanAdded
+		p.HumanDeleted += r.HumanDeleted
+	}
+	for _, p := range byStart {
+		periods = append(periods, *p)
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Start < periods[j].Start })
+	debugf("trend built from %d daily rollups", len(rollups))
+	return periods, true
+}
+
+// smoothWindowPeriods は --smooth の期間（7d, 4w, 3m）を推移の期間の数に変換します。
+func smoothWindowPeriods(window, period string) (int, error) {
+	if len(window) < 2 {
+		return 0, fmt.Errorf("invalid --smooth %q (e.g., '7d', '4w', '3m')", window)
+	}
+	unitDays, ok := smoothUnitDays[window[len(window)-1]]
+	n, err := strconv.Atoi(window[:len(window)-1])
+	if !ok || err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid --smooth %q (e.g., '7d', '4w', '3m')", window)
+	}
+	periods := int(math.Round(float64(n*unitDays) / float64(trendPeriodDays[period])))
+	if periods < 1 {
+		return 0, fmt.Errorf("--smooth %s is shorter than one %s", window, period)
+	}
+	return periods, nil
+}
+
+// smoothTrend は各期間を最後とする n 期間（暦の上の期間で、コミットのない期間も数える）の追加行数を合算し、
+// そのAI%を SmoothedAIPercentage に設定します。行数で重み付けするため、追加行の少ない期間の値に引きずられません。
+func smoothTrend(trend *tracker.Trend, window string, n int) {
+	trend.Smooth = window
+	layout := "2006-01-02"
+	if trend.Period == churnPeriodMonth {
+		layout = "2006-01"
+	}
+	for i := range trend.Points {
+		start, err := time.Parse(layout, trend.Points[i].Start)
+		if err != nil {
+			continue
+		}
+		var windowStart string
+		switch trend.Period {
+		case churnPeriodMonth:
+			windowStart = start.AddDate(0, -(n - 1), 0).Format(layout)
+		case churnPeriodWeek:
+			windowStart = start.AddDate(0, 0, -7*(n-1)).Format(layout)
+		default:
+			windowStart = start.AddDate(0, 0, -(n - 1)).Format(layout)
+		}
+
+		ai, total := 0, 0
+		for j := i; j >= 0 && trend.Points[j].Start >= windowStart; j-- {
+			ai += trend.Points[j].AILines
+			total += trend.Points[j].AILines + trend.Points[j].HumanLines
+		}
+		if total > 0 {
+			pct := metrics.SafePercent(ai, total)
+			trend.Points[i].SmoothedAIPercentage = &pct
+		}
+	}
+}
+
+// trendValue はスパークライン・バーに使う期間のAI%を返します（平滑化時は移動平均）。値がない期間は ok=false です。
+func trendValue(p tracker.TrendPoint, smoothed bool) (pct float64, ok bool) {
+	if smoothed {
+		if p.SmoothedAIPercentage == nil {
+			return 0, false
+		}
+		return *p.SmoothedAIPercentage, true
+	}
+	return p.AIPercentage, p.AILines+p.HumanLines > 0
+}
+
+// sparkline はAI%の推移を1行の文字列で表します（smoothed が true の場合は移動平均）。値のない期間は空白です。
+func sparkline(points []tracker.TrendPoint, smoothed bool) string {
+	var b strings.Builder
+	for _, p := range points {
+		pct, ok := trendValue(p, smoothed)
+		if !ok {
+			b.WriteRune(' ')
+			continue
+		}
+		level := int(pct / 100 * float64(len(sparkBlocks)-1))
+		b.WriteRune(sparkBlocks[min(max(level, 0), len(sparkBlocks)-1)])
+	}
+	return b.String()
+}
+
+// printTrend はAI%の推移をスパークラインと期間ごとの表で表示します。
+// Change は値のある直前の期間からのAI%の増減（ポイント）です。--smooth 指定時はスパークライン・Change・バーに移動平均を使います。
+func printTrend(trend *tracker.Trend) {
+	smoothed := trend.Smooth != ""
+	if smoothed {
+		fmt.Printf("Trend (AI%% by %s, %s rolling average): %s\n", trend.Period, trend.Smooth, sparkline(trend.Points, true))
+		fmt.Printf("  %-12s %7s %9s %11s %7s %9s %8s\n", "Period", "Commits", "AI lines", "Human lines", "AI%", "Smoothed", "Change")
+	} else {
+		fmt.Printf("Trend (AI%% by %s): %s\n", trend.Period, sparkline(trend.Points, false))
+		fmt.Printf("  %-12s %7s %9s %11s %7s %8s\n", "Period", "Commits", "AI lines", "Human lines", "AI%", "Change")
+	}
+	prev := -1.0
+	for _, p := range trend.Points {
+		change, bar := "-", ""
+		if pct, ok := trendValue(p, smoothed); ok {
+			if prev >= 0 {
+				change = fmt.Sprintf("%+.1f", pct-prev)
+			}
+			prev = pct
+			bar = strings.Repeat("█", int(math.Round(pct/100*trendBarWidth)))
+		}
+		if !smoothed {
+			fmt.Printf("  %-12s %7d %9d %11d %6.1f%% %8s  %s\n", p.Start, p.Commits, p.AILines, p.HumanLines, p.AIPercentage, change, bar)
+			continue
+		}
+		avg := "-"
+		if p.SmoothedAIPercentage != nil {
+			avg = fmt.Sprintf("%.1f%%", *p.SmoothedAIPercentage)
+		}
+		fmt.Printf("  %-12s %7d %9d %11d %6.1f%% %9s %8s  %s\n", p.Start, p.Commits, p.AILines, p.HumanLines, p.AIPercentage, avg, change, bar)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []tracker.TrendPoint
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"0から100まで", []tracker.TrendPoint{
+			{AILines: 0, HumanLines: 10, AIPercentage: 0},
+			{AILines: 5, HumanLines: 5, AIPercentage: 50},
+			{AILines: 10, HumanLines: 0, AIPercentage: 100},
+		}, "▁▄█"},
+		{"追加行のない期間は空白", []tracker.TrendPoint{
+			{AILines: 3, HumanLines: 1, AIPercentage: 75},
+			{},
+			{AILines: 1, HumanLines: 3, AIPercentage: 25},
+		}, "▆ ▂"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sparkline(tt.points, false); got != tt.want {
+				t.Errorf("sparkline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmoothWindowPeriods(t *testing.T) {
+	tests := []struct {
+		window, period string
+		want           int
+		wantErr        bool
+	}{
+		{"7d", churnPeriodDay, 7, false},
+		{"2w", churnPeriodDay, 14, false},
+		{"4w", churnPeriodWeek, 4, false},
+		{"30d", churnPeriodWeek, 4, false},
+		{"3m", churnPeriodMonth, 3, false},
+		{"3d", churnPeriodWeek, 0, true},
+		{"0d", churnPeriodDay, 0, true},
+		{"7x", churnPeriodDay, 0, true},
+		{"d", churnPeriodDay, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := smoothWindowPeriods(tt.window, tt.period)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("smoothWindowPeriods(%q, %q) = %d, %v; want %d (error %v)", tt.window, tt.period, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// TestSmoothTrend はコミットのない日も窓に数え、行数で重み付けした移動平均になることを検証する
+func TestSmoothTrend(t *testing.T) {
+	trend := &tracker.Trend{Period: churnPeriodDay, Points: []tracker.TrendPoint{
+		{Start: "2025-03-01", AILines: 90, HumanLines: 10, AIPercentage: 90},
+		{Start: "2025-03-02", AILines: 0, HumanLines: 0},
+		{Start: "2025-03-03", AILines: 0, HumanLines: 100, AIPercentage: 0},
+		{Start: "2025-03-06", AILines: 1, HumanLines: 0, AIPercentage: 100},
+	}}
+	smoothTrend(trend, "3d", 3)
+
+	if trend.Smooth != "3d" {
+		t.Errorf("Smooth = %q, want 3d", trend.Smooth)
+	}
+	// 03-03: 03-01〜03-03 の 90/200 行、03-06: 03-04〜03-06 の 1/1 行
+	want := []float64{90, 90, 45, 100}
+	for i, p := range trend.Points {
+		if p.SmoothedAIPercentage == nil || *p.SmoothedAIPercentage != want[i] {
+			t.Errorf("point %s smoothed = %v, want %v", p.Start, p.SmoothedAIPercentage, want[i])
+		}
+	}
+	if got := sparkline(trend.Points, true); got != "▇▇▄█" {
+		t.Errorf("sparkline(smoothed) = %q", got)
+	}
+
+	// 窓の中に追加行がない期間は値なし
+	empty := &tracker.Trend{Period: churnPeriodWeek, Points: []tracker.TrendPoint{{Start: "2025-03-03"}}}
+	smoothTrend(empty, "2w", 2)
+	if empty.Points[0].SmoothedAIPercentage != nil {
+		t.Errorf("smoothed = %v, want nil for a window without lines", *empty.Points[0].SmoothedAIPercentage)
+	}
+}
+
+// TestCollectTrend は日次集計とコミット単位の集計で同じ推移になることを検証する
+func TestCollectTrend(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	testutil.CreateTestFile(t, tmpDir, "base.go", "package main\n")
+	base := testutil.GitCommit(t, tmpDir, "initial")
+
+	// AIが3行、人間が1行追加
+	if err := recordCheckpoint("human", "", ""); err != nil {
+		t.Fatalf("baseline checkpoint error = %v", err)
+	}
+	testutil.CreateTestFile(t, tmpDir, "ai.go", "package main\n\nfunc a() {}\n")
+	if err := recordCheckpoint("Claude", "", ""); err != nil {
+		t.Fatalf("AI checkpoint error = %v", err)
+	}
+	testutil.GitCommit(t, tmpDir, "AI adds ai.go")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+	testutil.CreateTestFile(t, tmpDir, "human.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "human adds human.go")
+	if err := handleCommit(); err != nil {
+		t.Fatalf("handleCommit() error = %v", err)
+	}
+
+	rangeSpec := base + "..HEAD"
+	if _, ok := rollupTrendPeriods(rangeSpec, churnPeriodMonth); !ok {
+		t.Fatal("rollupTrendPeriods() ok = false, want daily rollups to cover the range")
+	}
+
+	for _, useRollups := range []bool{true, false} {
+		trend, err := collectTrend(rangeSpec, churnPeriodMonth, useRollups)
+		if err != nil {
+			t.Fatalf("collectTrend(useRollups=%v) error = %v", useRollups, err)
+		}
+		if trend.Period != churnPeriodMonth || len(trend.Points) != 1 {
+			t.Fatalf("collectTrend(useRollups=%v) = %+v, want 1 monthly point", useRollups, trend)
+		}
+		p := trend.Points[0]
+		if p.Commits != 2 || p.AILines != 3 || p.HumanLines != 1 || p.AIPercentage != 75 {
+			t.Errorf("collectTrend(useRollups=%v) point = %+v, want 2 commits, 3 AI / 1 human lines (75%%)", useRollups, p)
+		}
+	}
+}
@@ -70,7 +70,7 @@ func findCheckpointBySnapshot(checkpoints []*tracker.CheckpointV2, targetFile st
 }
 
 // BuildAuthorshipLogFromDiff はdiffとauthorshipマッピングからAuthorship Logを作成します。
-// changedFiles内のファイルのうち、追跡対象の拡張子かつ除外パターンに該当しないもののみ含めます。
+// changedFiles内のファイルのうち、追跡対象の拡張子かつ除外パターンに該当しないもの（および設定の artifacts に一致するもの）のみ含めます。
 func BuildAuthorshipLogFromDiff(
 	diffMap map[string]tracker.Change,
 	authorMap map[string]*tracker.CheckpointV2,
@@ -90,18 +90,20 @@ func BuildAuthorshipLogFromDiff(
 			continue
 		}
 
-		if !tracker.IsTrackedFile(fpath, cfg) {
+		if !tracker.IsRecordedFile(fpath, cfg) {
 			continue
 		}
 
 		var authorName string
 		var authorType tracker.AuthorType
 		var metadata map[string]string
+		var aiWeight *float64
 
 		if cp, exists := authorMap[fpath]; exists {
 			authorName = cp.Author
 			authorType = cp.Type
 			metadata = cp.Metadata
+			aiWeight = cp.AIWeight
 		} else {
 			authorName = cfg.DefaultAuthor
 			authorType = tracker.AuthorTypeHuman
@@ -115,6 +117,7 @@ func BuildAuthorshipLogFromDiff(
 					Type:     authorType,
 					Lines:    change.Lines,
 					Metadata: metadata,
+					AIWeight: aiWeight,
 				},
 			},
 		}
@@ -149,10 +152,10 @@ func BuildAuthorshipLog(checkpoints []*tracker.CheckpointV2, commitHash string,
 				fileInfo = tracker.FileInfo{Authors: []tracker.AuthorInfo{}}
 			}
 
-			// 同じ作成者が既に存在するか確認
+			// 同じ作成者（AIへの帰属割合も同じ）が既に存在するか確認
 			authorIdx := -1
 			for i, author := range fileInfo.Authors {
-				if author.Name == cp.Author && author.Type == cp.Type {
+				if author.Name == cp.Author && author.Type == cp.Type && sameWeight(author.AIWeight, cp.AIWeight) {
 					authorIdx = i
 					break
 				}
@@ -171,6 +174,7 @@ func BuildAuthorshipLog(checkpoints []*tracker.CheckpointV2, commitHash string,
 					Type:     cp.Type,
 					Lines:    change.Lines,
 					Metadata: cp.Metadata,
+					AIWeight: cp.AIWeight,
 				})
 			}
 
@@ -181,6 +185,14 @@ func BuildAuthorshipLog(checkpoints []*tracker.CheckpointV2, commitHash string,
 	return log, nil
 }
 
+// sameWeight はAIへの帰属割合が同じか（どちらも未指定を含む）を判定します。
+func sameWeight(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
 // CountLines counts total lines from line ranges
 func CountLines(ranges [][]int) int {
 	total := 0
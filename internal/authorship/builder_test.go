@@ -91,6 +91,32 @@ func TestBuildAuthorshipLog(t *testing.T) {
 	}
 }
 
+// TestBuildAuthorshipLog_AIWeight はAIへの帰属割合が異なるチェックポイントを別エントリとして記録することを検証する
+func TestBuildAuthorshipLog_AIWeight(t *testing.T) {
+	half := 0.5
+	checkpoints := []*tracker.CheckpointV2{
+		{Author: "Alice", Type: tracker.AuthorTypeHuman, Changes: map[string]tracker.Change{"main.go": {Lines: [][]int{{1, 10}}}}},
+		{Author: "Alice", Type: tracker.AuthorTypeHuman, AIWeight: &half, Changes: map[string]tracker.Change{"main.go": {Lines: [][]int{{11, 20}}}}},
+		{Author: "Alice", Type: tracker.AuthorTypeHuman, AIWeight: &half, Changes: map[string]tracker.Change{"main.go": {Lines: [][]int{{21, 30}}}}},
+	}
+
+	log, err := BuildAuthorshipLog(checkpoints, "abc123", nil)
+	if err != nil {
+		t.Fatalf("BuildAuthorshipLog failed: %v", err)
+	}
+
+	authors := log.Files["main.go"].Authors
+	if len(authors) != 2 {
+		t.Fatalf("Expected 2 author entries, got %+v", authors)
+	}
+	if authors[0].AIWeight != nil || CountLines(authors[0].Lines) != 10 {
+		t.Errorf("unweighted entry = %+v", authors[0])
+	}
+	if authors[1].AIWeight == nil || *authors[1].AIWeight != 0.5 || CountLines(authors[1].Lines) != 20 {
+		t.Errorf("weighted entry = %+v", authors[1])
+	}
+}
+
 func TestBuildAuthorshipLogMultipleFiles(t *testing.T) {
 	checkpoints := []*tracker.CheckpointV2{
 		{
@@ -687,6 +713,25 @@ func TestValidateAuthorshipLog(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "AI weight out of range",
+			log: &tracker.AuthorshipLog{
+				Version: AuthorshipLogVersion,
+				Commit:  "abc123",
+				Files: map[string]tracker.FileInfo{
+					"test.go": {
+						Authors: []tracker.AuthorInfo{
+							{
+								Name:     "Test User",
+								Type:     tracker.AuthorTypeHuman,
+								AIWeight: func() *float64 { w := 1.5; return &w }(),
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -34,6 +34,10 @@ func ValidateAuthorshipLog(log *tracker.AuthorshipLog) error {
 			if author.Type != tracker.AuthorTypeHuman && author.Type != tracker.AuthorTypeAI {
 				return fmt.Errorf("file %s has invalid author type: %s", filepath, author.Type)
 			}
+
+			if author.AIWeight != nil && (*author.AIWeight < 0 || *author.AIWeight > 1) {
+				return fmt.Errorf("file %s has invalid ai_weight for %s: %v (must be between 0 and 1)", filepath, author.Name, *author.AIWeight)
+			}
 		}
 	}
 
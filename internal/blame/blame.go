@@ -0,0 +1,178 @@
+// Package blame runs `git blame --line-porcelain` for many files in a bounded worker pool
+// and streams the per-line origins back in request order.
+//
+// ファイルごとの git blame は独立したプロセスのため並列に実行し、
+// 結果は要求した順に呼び出し元のゴルーチンでコールバックに渡します（呼び出し側の同期は不要）。
+package blame
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+)
+
+// UncommittedHash は git blame が未コミットの行に割り当てるハッシュです。
+const UncommittedHash = "0000000000000000000000000000000000000000"
+
+// maxWorkers は DefaultWorkers の上限です（git プロセスを増やしすぎない）。
+const maxWorkers = 8
+
+// Origin は行を最後に変更したコミットとそのコミット時点の位置です。
+type Origin struct {
+	Commit string
+	Path   string    // コミット時点のファイルパス（リネーム追跡）
+	Line   int       // コミット時点の行番号
+	Time   time.Time // コミット日時（committer-time。未コミットの行は blame の実行時刻）
+	Text   string    // 現在の行の内容
+}
+
+// Request は1ファイル分の blame 対象です。Lines が空の場合はファイル全体を対象とします。
+type Request struct {
+	Path  string
+	Lines []int
+}
+
+// Result は1ファイル分の blame 結果です（行番号 → 変更元）。
+type Result struct {
+	Path    string
+	Origins map[int]Origin
+	Err     error
+}
+
+// DefaultWorkers はCPU数（上限 maxWorkers）を返します。
+func DefaultWorkers() int {
+	if n := runtime.NumCPU(); n < maxWorkers {
+		return n
+	}
+	return maxWorkers
+}
+
+// File は指定行を git blame し、行番号ごとの変更元を返します。
+// rev が空の場合は作業ツリー、lines が空の場合はファイル全体を対象とします。
+func File(executor gitexec.Executor, rev, path string, lines []int) (map[int]Origin, error) {
+	args := []string{"blame", "--line-porcelain"}
+	for _, n := range lines {
+		args = append(args, "-L", fmt.Sprintf("%d,%d", n, n))
+	}
+	if rev != "" {
+		args = append(args, rev)
+	}
+	args = append(args, "--", path)
+
+	output, err := executor.Run(args...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePorcelain(output), nil
+}
+
+// Stream は requests を workers 個の並列 git blame で処理し、要求の順に fn を呼び出します。
+// fn がエラーを返した場合は未着手のファイルを処理せずにそのエラーを返します。
+// ファイルごとの失敗は Result.Err で渡します。
+func Stream(executor gitexec.Executor, rev string, requests []Request, workers int, fn func(Result) error) error {
+	if len(requests) == 0 {
+		return nil
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(requests) {
+		workers = len(requests)
+	}
+
+	// 結果はファイルごとのバッファ付きチャネルで受け取り、ワーカーが送信で止まらないようにする
+	results := make([]chan Result, len(requests))
+	for i := range results {
+		results[i] = make(chan Result, 1)
+	}
+	jobs := make(chan int)
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				req := requests[i]
+				origins, err := File(executor, rev, req.Path, req.Lines)
+				results[i] <- Result{Path: req.Path, Origins: origins, Err: err}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := range requests {
+			select {
+			case jobs <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var err error
+	for i := range requests {
+		if err = fn(<-results[i]); err != nil {
+			break
+		}
+	}
+	close(done)
+	wg.Wait()
+	return err
+}
+
+// ParsePorcelain は `git blame --line-porcelain` の出力を解析します。
+// 各行は "<hash> <元の行番号> <現在の行番号>" のヘッダーで始まり、"filename" と内容行（タブ始まり）で終わります。
+func ParsePorcelain(output string) map[int]Origin {
+	origins := make(map[int]Origin)
+	var (
+		current Origin
+		final   int
+	)
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			if final > 0 {
+				current.Text = line[1:]
+				origins[final] = current
+			}
+			current, final = Origin{}, 0
+		case strings.HasPrefix(line, "filename "):
+			current.Path = strings.TrimPrefix(line, "filename ")
+		case strings.HasPrefix(line, "committer-time "):
+			if sec, err := strconv.ParseInt(strings.TrimPrefix(line, "committer-time "), 10, 64); err == nil {
+				current.Time = time.Unix(sec, 0)
+			}
+		default:
+			fields := strings.Fields(line)
+			if len(fields) >= 3 && len(fields[0]) >= 40 && isHexString(fields[0]) {
+				orig, err1 := strconv.Atoi(fields[1])
+				fin, err2 := strconv.Atoi(fields[2])
+				if err1 == nil && err2 == nil {
+					current = Origin{Commit: fields[0], Line: orig}
+					final = fin
+				}
+			}
+		}
+	}
+	// 出力の末尾の空白が除かれると、最終行が空行の場合は内容行（タブのみ）が失われる
+	if final > 0 && current.Path != "" {
+		origins[final] = current
+	}
+	return origins
+}
+
+func isHexString(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+package blame
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParsePorcelain(t *testing.T) {
+	hash := strings.Repeat("a", 40)
+	output := strings.Join([]string{
+		hash + " 3 7 1",
+		"author Claude",
+		"committer-time 1735689600",
+		"filename old/name.go",
+		"\t// TODO",
+		UncommittedHash + " 9 9 1",
+		"author Not Committed Yet",
+		"filename new/name.go",
+		"\twip",
+	}, "\n")
+
+	got := ParsePorcelain(output)
+	if o := got[7]; o.Commit != hash || o.Path != "old/name.go" || o.Line != 3 || o.Time.Unix() != 1735689600 || o.Text != "// TODO" {
+		t.Errorf("line 7 origin = %+v", o)
+	}
+	if o := got[9]; o.Commit != UncommittedHash {
+		t.Errorf("line 9 origin = %+v, want uncommitted", o)
+	}
+}
+
+func TestParsePorcelain_TrimmedEmptyLastLine(t *testing.T) {
+	hash := strings.Repeat("b", 40)
+	// executor.Run が出力を TrimSpace するため、空の最終行の内容行（タブのみ）は失われる
+	output := strings.Join([]string{
+		hash + " 1 1 2",
+		"filename a.go",
+		"	package main",
+		hash + " 2 2",
+		"filename a.go",
+	}, "\n")
+
+	got := ParsePorcelain(output)
+	if o := got[2]; o.Commit != hash || o.Path != "a.go" || o.Line != 2 {
+		t.Errorf("line 2 origin = %+v, want recorded without content line", o)
+	}
+}
+
+// fakeExecutor はファイル名を内容とする1行の blame 出力を返し、同時実行数の最大値を記録します。
+type fakeExecutor struct {
+	mu      sync.Mutex
+	running int
+	peak    int
+	calls   [][]string
+	fail    string // この名前のファイルはエラー
+}
+
+func (f *fakeExecutor) Run(args ...string) (string, error) {
+	f.mu.Lock()
+	f.running++
+	if f.running > f.peak {
+		f.peak = f.running
+	}
+	f.calls = append(f.calls, args)
+	f.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	f.mu.Lock()
+	f.running--
+	f.mu.Unlock()
+
+	path := args[len(args)-1]
+	if path == f.fail {
+		return "", errors.New("no such path")
+	}
+	return fmt.Sprintf("%s 1 1 1\nfilename %s\n\tline", strings.Repeat("b", 40), path), nil
+}
+
+func (f *fakeExecutor) RunInDir(dir string, args ...string) (string, error) { return f.Run(args...) }
+
+func (f *fakeExecutor) RunWithStdin(stdin string, args ...string) (string, error) {
+	return f.Run(args...)
+}
+
+func TestStream(t *testing.T) {
+	var requests []Request
+	for i := 0; i < 20; i++ {
+		requests = append(requests, Request{Path: fmt.Sprintf("f%02d.go", i)})
+	}
+	requests[3].Lines = []int{2, 5}
+
+	tests := []struct {
+		name     string
+		workers  int
+		wantPeak int
+	}{
+		{"serial", 1, 1},
+		{"bounded pool", 4, 4},
+		{"non-positive workers fall back to one", 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &fakeExecutor{fail: "f07.go"}
+			var got []string
+			err := Stream(exec, "HEAD", requests, tt.workers, func(r Result) error {
+				if r.Path == "f07.go" {
+					if r.Err == nil {
+						t.Error("f07.go should report the blame error")
+					}
+				} else if o := r.Origins[1]; o.Path != r.Path {
+					t.Errorf("%s origin = %+v", r.Path, o)
+				}
+				got = append(got, r.Path)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Stream() error = %v", err)
+			}
+
+			// 要求の順に結果が渡される
+			for i, r := range requests {
+				if i >= len(got) || got[i] != r.Path {
+					t.Fatalf("results out of order: %v", got)
+				}
+			}
+			if exec.peak > tt.wantPeak {
+				t.Errorf("peak concurrency = %d, want <= %d", exec.peak, tt.wantPeak)
+			}
+			if tt.wantPeak > 1 && exec.peak < 2 {
+				t.Errorf("peak concurrency = %d, want parallel blame", exec.peak)
+			}
+		})
+	}
+}
+
+func TestStream_Args(t *testing.T) {
+	exec := &fakeExecutor{}
+	requests := []Request{{Path: "a.go", Lines: []int{3, 8}}}
+	if err := Stream(exec, "", requests, 2, func(Result) error { return nil }); err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	want := "blame --line-porcelain -L 3,3 -L 8,8 -- a.go"
+	if got := strings.Join(exec.calls[0], " "); got != want {
+		t.Errorf("args = %q, want %q", got, want)
+	}
+}
+
+func TestStream_StopsOnCallbackError(t *testing.T) {
+	var requests []Request
+	for i := 0; i < 50; i++ {
+		requests = append(requests, Request{Path: fmt.Sprintf("f%02d.go", i)})
+	}
+	stop := errors.New("stop")
+
+	exec := &fakeExecutor{}
+	n := 0
+	err := Stream(exec, "HEAD", requests, 2, func(Result) error {
+		n++
+		if n == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("Stream() error = %v, want %v", err, stop)
+	}
+	if len(exec.calls) >= len(requests) {
+		t.Errorf("blamed %d files after the callback failed, want fewer than %d", len(exec.calls), len(requests))
+	}
+}
@@ -0,0 +1,126 @@
+// Package events sends checkpoint, commit and milestone events to a local message bus (NATS or Redis Streams)
+// and to outgoing webhooks.
+// 外部ライブラリを使わず、各プロトコルの送信に必要な最小限のコマンドのみ実装します。
+package events
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+const (
+	// EventCheckpoint はチェックポイント記録時のイベント
+	EventCheckpoint = "checkpoint"
+	// EventCommit はコミットのAuthorship Log記録時のイベント
+	EventCommit = "commit"
+	// EventMilestone は直近7日間のAI%が目標（target_ai_percentage）に達したコミットでのイベント
+	EventMilestone = "milestone"
+
+	// defaultSubjectPrefix は events.subject 未指定時のNATS subject / Redis stream名の接頭辞（aict.checkpoint 等）
+	defaultSubjectPrefix = "aict."
+	// publishTimeout は接続から送信完了確認までの上限（hookの実行を遅らせないよう短くする）
+	publishTimeout = time.Second
+
+	defaultNATSAddress  = "127.0.0.1:4222"
+	defaultRedisAddress = "127.0.0.1:6379"
+)
+
+// Event is the compact payload sent when a checkpoint or an Authorship Log is recorded
+type Event struct {
+	Event        string    `json:"event"` // checkpoint / commit / milestone
+	Repo         string    `json:"repo"`  // リポジトリのディレクトリ名
+	Branch       string    `json:"branch"`
+	AuthorType   string    `json:"author_type,omitempty"` // ai / human（checkpoint のみ）
+	Files        int       `json:"files"`
+	LinesAdded   int       `json:"lines_added"`
+	LinesDeleted int       `json:"lines_deleted"`
+	Timestamp    time.Time `json:"timestamp"`
+
+	// commit イベントのみ: コミットのAI/人間の行数
+	Commit       string  `json:"commit,omitempty"`
+	AILines      int     `json:"ai_lines,omitempty"`
+	HumanLines   int     `json:"human_lines,omitempty"`
+	AIPercentage float64 `json:"ai_percentage,omitempty"` // milestone では直近7日間のAI%
+
+	// milestone イベントのみ: 達した目標AI%
+	TargetAIPercentage float64 `json:"target_ai_percentage,omitempty"`
+}
+
+// Notify はイベントをメッセージバスと、そのイベントを購読するすべてのWebhookに送信します。
+// 一部の送信先が失敗しても残りには送信し、失敗をまとめて返します。
+// checkpoint イベントは hook からツールの使用ごとに送られるため、Webhookへは再送せず1回だけ送信します。
+func Notify(cfg *tracker.EventsConfig, ev Event) error {
+	var errs []error
+	if cfg.Bus != "" {
+		errs = append(errs, Publish(cfg, ev))
+	}
+	for _, w := range cfg.Webhooks {
+		switch {
+		case !subscribes(w, ev.Event):
+		case ev.Event == EventCheckpoint:
+			errs = append(errs, SendWebhookOnce(w, ev))
+		default:
+			errs = append(errs, SendWebhook(w, ev))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// subscribes はWebhookがイベントを通知対象にしているかを判定します。
+// events 未指定時は commit と milestone のみで、頻度の高い checkpoint は明示した場合だけ送信します。
+func subscribes(w tracker.WebhookConfig, event string) bool {
+	if len(w.Events) == 0 {
+		return event != EventCheckpoint
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Publish は設定のメッセージバスにイベントを1件送信します。
+// 送信先のサーバーが受理を返すまで待ち、タイムアウト・拒否はエラーとして返します。
+func Publish(cfg *tracker.EventsConfig, ev Event) error {
+	subject := cfg.Subject
+	if subject == "" {
+		subject = defaultSubjectPrefix + ev.Event
+	}
+
+	var (
+		address string
+		send    func(rw *bufio.ReadWriter, subject string, ev Event) error
+	)
+	switch cfg.Bus {
+	case "nats":
+		address, send = defaultNATSAddress, publishNATS
+	case "redis":
+		address, send = defaultRedisAddress, publishRedis
+	default:
+		return fmt.Errorf("unknown events bus %q", cfg.Bus)
+	}
+	if cfg.Address != "" {
+		address = cfg.Address
+	}
+
+	conn, err := net.DialTimeout("tcp", address, publishTimeout)
+	if err != nil {
+		return fmt.Errorf("connecting to %s at %s: %w", cfg.Bus, address, err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(publishTimeout)); err != nil {
+		return err
+	}
+
+	rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	if err := send(rw, subject, ev); err != nil {
+		return fmt.Errorf("publishing to %s at %s: %w", cfg.Bus, address, err)
+	}
+	return nil
+}
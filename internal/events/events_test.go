@@ -0,0 +1,171 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+var testEvent = Event{
+	Event:        "checkpoint",
+	Repo:         "myrepo",
+	Branch:       "main",
+	AuthorType:   "ai",
+	Files:        2,
+	LinesAdded:   10,
+	LinesDeleted: 3,
+	Timestamp:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+}
+
+// fakeServer は1接続だけ受け付け、handle の戻り値（受信内容）をチャネルで返すテスト用サーバーです。
+func fakeServer(t *testing.T, handle func(r *bufio.Reader, w io.Writer) string) (string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			received <- ""
+			return
+		}
+		defer conn.Close()
+		received <- handle(bufio.NewReader(conn), conn)
+	}()
+	return ln.Addr().String(), received
+}
+
+func TestPublish_NATS(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		wantErr bool
+	}{
+		{"accepted", "PONG\r\n", false},
+		{"rejected", "-ERR 'Permissions Violation for Publish to aict.checkpoint'\r\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, received := fakeServer(t, func(r *bufio.Reader, w io.Writer) string {
+				io.WriteString(w, "INFO {\"server_id\":\"test\"}\r\n")
+				var got strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return got.String()
+					}
+					got.WriteString(line)
+					if line == "PING\r\n" {
+						io.WriteString(w, tt.reply)
+						return got.String()
+					}
+				}
+			})
+
+			err := Publish(&tracker.EventsConfig{Bus: "nats", Address: addr}, testEvent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			lines := strings.Split(<-received, "\r\n")
+			if len(lines) < 4 || !strings.HasPrefix(lines[0], "CONNECT ") {
+				t.Fatalf("unexpected protocol lines: %q", lines)
+			}
+			payload, _ := json.Marshal(testEvent)
+			if want := "PUB aict.checkpoint " + strconv.Itoa(len(payload)); lines[1] != want {
+				t.Errorf("PUB line = %q, want %q", lines[1], want)
+			}
+			var ev Event
+			if err := json.Unmarshal([]byte(lines[2]), &ev); err != nil || ev != testEvent {
+				t.Errorf("payload = %s, want %+v", lines[2], testEvent)
+			}
+		})
+	}
+}
+
+func TestPublish_Redis(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		wantErr bool
+	}{
+		{"accepted", "$15\r\n1700000000000-0\r\n", false},
+		{"rejected", "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, received := fakeServer(t, func(r *bufio.Reader, w io.Writer) string {
+				args, err := readRESPArray(r)
+				if err != nil {
+					return ""
+				}
+				io.WriteString(w, tt.reply)
+				return strings.Join(args, " ")
+			})
+
+			err := Publish(&tracker.EventsConfig{Bus: "redis", Address: addr, Subject: "aict:events"}, testEvent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			want := "XADD aict:events * event checkpoint repo myrepo branch main author_type ai " +
+				"files 2 lines_added 10 lines_deleted 3 timestamp 2026-01-02T03:04:05Z"
+			if got := <-received; got != want {
+				t.Errorf("command = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestPublish_Unreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	if err := Publish(&tracker.EventsConfig{Bus: "nats", Address: addr}, testEvent); err == nil {
+		t.Error("Publish() should fail when the bus is not running")
+	}
+	if err := Publish(&tracker.EventsConfig{Bus: "kafka"}, testEvent); err == nil {
+		t.Error("Publish() should fail for an unknown bus")
+	}
+}
+
+// readRESPArray はRESPの配列（bulk string）を1つ読み込みます。
+func readRESPArray(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		if _, err := r.ReadString('\n'); err != nil { // $<len>
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, strings.TrimRight(arg, "\r\n"))
+	}
+	return args, nil
+}
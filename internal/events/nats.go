@@ -0,0 +1,56 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// publishNATS はNATSのテキストプロトコルでイベント（JSON）を送信します。
+// CONNECT → PUB → PING を送り、PONG を受け取った時点でサーバーが受理したとみなします。
+func publishNATS(rw *bufio.ReadWriter, subject string, ev Event) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	// 接続直後にサーバーから INFO が送られる
+	line, err := readLine(rw)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO") {
+		return fmt.Errorf("unexpected greeting %q", line)
+	}
+
+	fmt.Fprintf(rw, "CONNECT {\"verbose\":false,\"pedantic\":false,\"name\":\"aict\"}\r\n")
+	fmt.Fprintf(rw, "PUB %s %d\r\n%s\r\n", subject, len(payload), payload)
+	fmt.Fprintf(rw, "PING\r\n")
+	if err := rw.Flush(); err != nil {
+		return err
+	}
+
+	for {
+		line, err := readLine(rw)
+		if err != nil {
+			return err
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("server error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+		// INFO の再送・+OK などは読み飛ばす
+	}
+}
+
+// readLine はCRLF終端の1行を読み、終端を除いて返します。
+func readLine(rw *bufio.ReadWriter) (string, error) {
+	line, err := rw.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// publishRedis はRedis Streamsにイベントを1エントリとして追加します（XADD <stream> * field value ...）。
+// フィールドはイベントのJSONキーと同じ名前で、値はすべて文字列です。
+func publishRedis(rw *bufio.ReadWriter, stream string, ev Event) error {
+	args := []string{
+		"XADD", stream, "*",
+		"event", ev.Event,
+		"repo", ev.Repo,
+		"branch", ev.Branch,
+		"author_type", ev.AuthorType,
+		"files", strconv.Itoa(ev.Files),
+		"lines_added", strconv.Itoa(ev.LinesAdded),
+		"lines_deleted", strconv.Itoa(ev.LinesDeleted),
+		"timestamp", ev.Timestamp.UTC().Format(time.RFC3339),
+	}
+	if ev.Commit != "" {
+		args = append(args,
+			"commit", ev.Commit,
+			"ai_lines", strconv.Itoa(ev.AILines),
+			"human_lines", strconv.Itoa(ev.HumanLines),
+			"ai_percentage", strconv.FormatFloat(ev.AIPercentage, 'f', 1, 64),
+		)
+	}
+	if ev.TargetAIPercentage != 0 {
+		args = append(args, "target_ai_percentage", strconv.FormatFloat(ev.TargetAIPercentage, 'f', 1, 64))
+	}
+
+	// RESPの配列（bulk string）としてコマンドを送信
+	fmt.Fprintf(rw, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(rw, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := rw.Flush(); err != nil {
+		return err
+	}
+
+	// 成功時はエントリIDのbulk string、失敗時は "-ERR ..." が返る
+	line, err := readLine(rw)
+	if err != nil {
+		return err
+	}
+	if line == "" {
+		return fmt.Errorf("empty reply")
+	}
+	switch line[0] {
+	case '-':
+		return fmt.Errorf("server error: %s", line[1:])
+	case '$', '+':
+		return nil
+	}
+	return fmt.Errorf("unexpected reply %q", line)
+}
@@ -0,0 +1,204 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/secrets"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+const (
+	// defaultWebhookRetries は max_retries 未指定時の再送回数
+	defaultWebhookRetries = 3
+	// webhookTimeout は1回のHTTPリクエストのタイムアウト
+	webhookTimeout = 5 * time.Second
+)
+
+// webhookBackoff は1回目の再送までの待ち時間（以降は2倍ずつ延ばす）。テストで短縮できるよう変数にしています。
+var webhookBackoff = 500 * time.Millisecond
+
+// templateFuncs はペイロードのテンプレートで使える関数です。
+// json は値をJSONとしてエスケープして埋め込みます（例: {"text": {{json .Repo}}}）。
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// ValidateWebhook はWebhook設定のURLとテンプレートの構文、${secret:name} 参照の名前を検証します。
+// URL全体を秘密情報で参照する場合（"${secret:slack_url}"）、URLの形式は送信時に確認します。
+func ValidateWebhook(w tracker.WebhookConfig) error {
+	if err := validateSecretRefs(w); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(w.URL, "${secret:") {
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("url must be an http(s) URL, got %q", w.URL)
+		}
+	}
+	for _, e := range w.Events {
+		if e != EventCheckpoint && e != EventCommit && e != EventMilestone {
+			return fmt.Errorf("unknown event %q (want %s, %s or %s)", e, EventCheckpoint, EventCommit, EventMilestone)
+		}
+	}
+	if w.Template != "" {
+		if _, err := template.New("payload").Funcs(templateFuncs).Parse(w.Template); err != nil {
+			return fmt.Errorf("template: %w", err)
+		}
+	}
+	return nil
+}
+
+// RenderPayload はイベントをWebhookのペイロードに変換します。
+// テンプレート未指定時はイベントのJSON、指定時はテンプレートの出力がJSONとして正しいことを確認して返します。
+func RenderPayload(w tracker.WebhookConfig, ev Event) ([]byte, error) {
+	if w.Template == "" {
+		return json.Marshal(ev)
+	}
+	tmpl, err := template.New("payload").Funcs(templateFuncs).Option("missingkey=error").Parse(w.Template)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ev); err != nil {
+		return nil, fmt.Errorf("executing template: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("template output is not valid JSON: %s", strings.TrimSpace(buf.String()))
+	}
+	return buf.Bytes(), nil
+}
+
+func validateSecretRefs(w tracker.WebhookConfig) error {
+	values := []string{w.URL}
+	for _, v := range w.Headers {
+		values = append(values, v)
+	}
+	for _, v := range values {
+		for _, name := range secrets.References(v) {
+			if err := secrets.ValidateName(name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolveSecrets はURLとヘッダの ${secret:name} を秘密情報の値に置き換えた設定を返します。
+func resolveSecrets(w tracker.WebhookConfig) (tracker.WebhookConfig, error) {
+	resolved := w
+	var err error
+	if resolved.URL, err = secrets.Expand(w.URL); err != nil {
+		return w, err
+	}
+	if len(w.Headers) > 0 {
+		resolved.Headers = make(map[string]string, len(w.Headers))
+		for k, v := range w.Headers {
+			if resolved.Headers[k], err = secrets.Expand(v); err != nil {
+				return w, fmt.Errorf("header %s: %w", k, err)
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// SendWebhook はイベントをWebhookにPOSTします。URLとヘッダの ${secret:name} は送信直前に解決します。
+// 接続エラー・429・5xxの場合は指数バックオフで max_retries 回まで再送し、それ以外の4xxは再送しません。
+// エラーメッセージには秘密情報を含めないよう、設定に書かれたURL（参照のまま）を使います。
+func SendWebhook(w tracker.WebhookConfig, ev Event) error {
+	retries := w.MaxRetries
+	if retries == 0 {
+		retries = defaultWebhookRetries
+	} else if retries < 0 {
+		retries = 0
+	}
+	return sendWebhook(w, ev, retries, webhookTimeout)
+}
+
+// SendWebhookOnce はイベントを再送せずに1回だけ、hook向けの短いタイムアウトでPOSTします。
+// Claude Code の hook から毎回送られる checkpoint イベントがツールの実行を遅らせないようにするためのものです。
+func SendWebhookOnce(w tracker.WebhookConfig, ev Event) error {
+	return sendWebhook(w, ev, 0, publishTimeout)
+}
+
+func sendWebhook(w tracker.WebhookConfig, ev Event, retries int, timeout time.Duration) error {
+	payload, err := RenderPayload(w, ev)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", redactURL(w.URL), err)
+	}
+	configured := w
+	if w, err = resolveSecrets(w); err != nil {
+		return fmt.Errorf("webhook %s: %w", redactURL(configured.URL), err)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	delay := webhookBackoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := postWebhook(client, w, payload)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= retries {
+			return fmt.Errorf("webhook %s: %w (%d attempt(s))", redactURL(w.URL), err, attempt+1)
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// postWebhook は1回のPOSTを行い、失敗時は再送すべきかどうかを返します。
+func postWebhook(client *http.Client, w tracker.WebhookConfig, payload []byte) (retryable bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "aict")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err // url.Error はURL全体（トークンを含みうる）をメッセージに含むため外す
+		}
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("HTTP %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+}
+
+// redactURL はエラーメッセージ用にURLのパス・クエリ（Zapier等ではトークンを含む）を伏せます。
+// ${secret:name} で参照しているURLは参照のまま表示します。
+func redactURL(raw string) string {
+	if strings.HasPrefix(raw, "${secret:") {
+		return raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "(invalid url)"
+	}
+	return u.Scheme + "://" + u.Host + "/..."
+}
@@ -0,0 +1,227 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestRenderPayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "default payload is the event JSON",
+			want: `{"event":"checkpoint","repo":"myrepo","branch":"main","author_type":"ai","files":2,"lines_added":10,"lines_deleted":3,"timestamp":"2026-01-02T03:04:05Z"}`,
+		},
+		{
+			name:     "template with json escaping",
+			template: `{"text": {{json (printf "%s: %d lines by %s" .Repo .LinesAdded .AuthorType)}}}`,
+			want:     `{"text": "myrepo: 10 lines by ai"}`,
+		},
+		{
+			name:     "conditional milestone text",
+			template: `{"value1": {{if ge .LinesAdded 10}}"big change"{{else}}"small change"{{end}}}`,
+			want:     `{"value1": "big change"}`,
+		},
+		{"output is not JSON", `{"text": {{.Repo}}}`, "", true},
+		{"unknown field", `{"text": {{json .Unknown}}}`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderPayload(tracker.WebhookConfig{Template: tt.template}, testEvent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderPayload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("RenderPayload() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook tracker.WebhookConfig
+		wantErr string
+	}{
+		{"valid", tracker.WebhookConfig{URL: "https://hooks.zapier.com/hooks/catch/1/abc/", Events: []string{"commit"}}, ""},
+		{"missing scheme", tracker.WebhookConfig{URL: "hooks.zapier.com/x"}, "url"},
+		{"unknown event", tracker.WebhookConfig{URL: "https://example.com", Events: []string{"push"}}, "unknown event"},
+		{"template syntax error", tracker.WebhookConfig{URL: "https://example.com", Template: `{"a": {{json .Repo}`}, "template"},
+		{"whole URL from secret", tracker.WebhookConfig{URL: "${secret:slack_url}"}, ""},
+		{"secret in header", tracker.WebhookConfig{URL: "https://example.com", Headers: map[string]string{"Authorization": "Bearer ${secret:token}"}}, ""},
+		{"invalid secret name", tracker.WebhookConfig{URL: "https://example.com", Headers: map[string]string{"X-Key": "${secret:my key}"}}, "invalid secret name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWebhook(tt.webhook)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateWebhook() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateWebhook() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSendWebhook_Retry(t *testing.T) {
+	orig := webhookBackoff
+	webhookBackoff = time.Millisecond
+	defer func() { webhookBackoff = orig }()
+
+	tests := []struct {
+		name         string
+		statuses     []int // 試行ごとの応答（最後の値を以降も返す）
+		maxRetries   int
+		wantErr      bool
+		wantAttempts int32
+	}{
+		{"success", []int{200}, 0, false, 1},
+		{"retry on 5xx then success", []int{503, 500, 204}, 0, false, 3},
+		{"retry on 429", []int{429, 200}, 0, false, 2},
+		{"gives up after max retries", []int{502}, 2, true, 3},
+		{"no retry on 4xx", []int{400}, 0, true, 1},
+		{"retries disabled", []int{500}, -1, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&attempts, 1)
+				if r.Header.Get("Content-Type") != "application/json" || r.Header.Get("X-Token") != "secret" {
+					t.Errorf("headers = %v", r.Header)
+				}
+				body, _ := io.ReadAll(r.Body)
+				if !json.Valid(body) {
+					t.Errorf("body is not JSON: %s", body)
+				}
+				status := tt.statuses[len(tt.statuses)-1]
+				if int(n) <= len(tt.statuses) {
+					status = tt.statuses[n-1]
+				}
+				w.WriteHeader(status)
+			}))
+			defer srv.Close()
+
+			err := SendWebhook(tracker.WebhookConfig{URL: srv.URL + "/hook/token123", Headers: map[string]string{"X-Token": "secret"}, MaxRetries: tt.maxRetries}, testEvent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendWebhook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), "token123") {
+				t.Errorf("error should not include the URL path: %v", err)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestSendWebhook_Secrets(t *testing.T) {
+	t.Setenv("AICT_SECRET_BACKEND", "env")
+
+	var gotAuth, gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth, gotPath = r.Header.Get("Authorization"), r.URL.Path
+	}))
+	defer srv.Close()
+
+	t.Setenv("AICT_SECRET_HOOK_URL", srv.URL+"/hook/token123")
+	t.Setenv("AICT_SECRET_API_TOKEN", "s3cr3t")
+	w := tracker.WebhookConfig{URL: "${secret:hook_url}", Headers: map[string]string{"Authorization": "Bearer ${secret:api-token}"}}
+	if err := SendWebhook(w, testEvent); err != nil {
+		t.Fatalf("SendWebhook() error = %v", err)
+	}
+	if gotAuth != "Bearer s3cr3t" || gotPath != "/hook/token123" {
+		t.Errorf("Authorization = %q, path = %q", gotAuth, gotPath)
+	}
+
+	w.Headers["Authorization"] = "Bearer ${secret:missing}"
+	err := SendWebhook(w, testEvent)
+	if err == nil || !strings.Contains(err.Error(), `secret "missing"`) {
+		t.Fatalf("SendWebhook() error = %v, want missing secret", err)
+	}
+	if strings.Contains(err.Error(), "token123") {
+		t.Errorf("error should not include the resolved URL: %v", err)
+	}
+}
+
+func TestNotify_EventFilter(t *testing.T) {
+	var received []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	cfg := &tracker.EventsConfig{Webhooks: []tracker.WebhookConfig{
+		{URL: srv.URL + "/default"},
+		{URL: srv.URL + "/checkpoints", Events: []string{EventCheckpoint}},
+		{URL: srv.URL + "/commits", Events: []string{EventCommit}},
+	}}
+	if err := Notify(cfg, testEvent); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if len(received) != 1 || received[0] != "/checkpoints" {
+		t.Errorf("received = %v, want only /checkpoints for a checkpoint event", received)
+	}
+
+	received = nil
+	milestone := testEvent
+	milestone.Event = EventMilestone
+	if err := Notify(cfg, milestone); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if len(received) != 1 || received[0] != "/default" {
+		t.Errorf("received = %v, want only /default for a milestone event", received)
+	}
+}
+
+func TestNotify_CheckpointIsNotRetried(t *testing.T) {
+	orig := webhookBackoff
+	webhookBackoff = time.Millisecond
+	defer func() { webhookBackoff = orig }()
+
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	cfg := &tracker.EventsConfig{Webhooks: []tracker.WebhookConfig{
+		{URL: srv.URL, Events: []string{EventCheckpoint, EventCommit}, MaxRetries: 5},
+	}}
+
+	if err := Notify(cfg, testEvent); err == nil {
+		t.Fatal("Notify() error = nil, want HTTP 503")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("checkpoint attempts = %d, want 1 (no retries from hooks)", got)
+	}
+
+	atomic.StoreInt32(&attempts, 0)
+	commit := testEvent
+	commit.Event = EventCommit
+	Notify(cfg, commit)
+	if got := atomic.LoadInt32(&attempts); got != 6 {
+		t.Errorf("commit attempts = %d, want 6 (max_retries 5)", got)
+	}
+}
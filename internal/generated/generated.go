@@ -0,0 +1,124 @@
+// Package generated detects generated files (code generator output, minified bundles and lockfiles)
+// so that they don't skew the AI percentage.
+//
+// 判定はヒューリスティックです:
+//   - ロックファイル: ファイル名（package-lock.json, go.sum 等）で判定
+//   - 生成コード: 先頭付近に "Code generated ... DO NOT EDIT" 等のマーカーがある
+//   - minified: *.min.js / *.min.css、または平均行長が極端に長い JavaScript / CSS
+package generated
+
+import (
+	"bytes"
+	"path"
+	"strings"
+)
+
+// Detect が返す判定理由
+const (
+	ReasonLockfile = "lockfile"
+	ReasonMarker   = "generated marker"
+	ReasonMinified = "minified"
+)
+
+// headerSize は生成コードのマーカーを探す先頭のバイト数です。
+const headerSize = 8 * 1024
+
+const (
+	headerLines = 40 // マーカーを探す先頭の行数
+
+	// minified と判定する平均行長と、判定に必要な最小サイズ（数行の短いファイルは対象外）
+	minifiedAvgLineLength = 300
+	minifiedMinSize       = 2 * 1024
+)
+
+// lockfiles はパッケージマネージャが生成するロックファイルの名前です。
+var lockfiles = map[string]bool{
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+	"bun.lockb":           true,
+	"go.sum":              true,
+	"Cargo.lock":          true,
+	"Gemfile.lock":        true,
+	"composer.lock":       true,
+	"poetry.lock":         true,
+	"Pipfile.lock":        true,
+	"uv.lock":             true,
+	"mix.lock":            true,
+	"Podfile.lock":        true,
+	"Package.resolved":    true,
+	"pubspec.lock":        true,
+	"flake.lock":          true,
+	"packages.lock.json":  true,
+	"gradle.lockfile":     true,
+	".terraform.lock.hcl": true,
+	"paket.lock":          true,
+	"deno.lock":           true,
+	"shard.lock":          true,
+	"conan.lock":          true,
+	"MODULE.bazel.lock":   true,
+	"project.assets.json": true,
+	"packages-lock.json":  true,
+}
+
+// minifiableExts は minified かを内容で判定する拡張子です。
+var minifiableExts = map[string]bool{".js": true, ".mjs": true, ".cjs": true, ".css": true}
+
+// IsLockfile はパッケージマネージャのロックファイルかをファイル名で判定します。
+func IsLockfile(fpath string) bool {
+	return lockfiles[path.Base(fpath)]
+}
+
+// Detect は生成ファイルと判定した理由を返します（生成ファイルでなければ ""）。
+// content は先頭の一部でも構いません（マーカーは先頭 headerSize バイト、minified は渡された範囲で判定）。
+func Detect(fpath string, content []byte) string {
+	if IsLockfile(fpath) {
+		return ReasonLockfile
+	}
+	base := path.Base(fpath)
+	ext := path.Ext(base)
+	if minifiableExts[ext] && strings.HasSuffix(strings.TrimSuffix(base, ext), ".min") {
+		return ReasonMinified
+	}
+	if hasGeneratedMarker(content) {
+		return ReasonMarker
+	}
+	if minifiableExts[ext] && looksMinified(content) {
+		return ReasonMinified
+	}
+	return ""
+}
+
+// hasGeneratedMarker は先頭付近に生成コードのマーカーがあるかを判定します。
+// Go の規約（"// Code generated ... DO NOT EDIT."）のほか、他言語のジェネレータで一般的な
+// "@generated"、"<auto-generated"、"generated ... DO NOT EDIT" の形式を認識します。
+func hasGeneratedMarker(content []byte) bool {
+	if len(content) > headerSize {
+		content = content[:headerSize]
+	}
+	for i := 0; i < headerLines && len(content) > 0; i++ {
+		line := content
+		if idx := bytes.IndexByte(content, '\n'); idx >= 0 {
+			line, content = content[:idx], content[idx+1:]
+		} else {
+			content = nil
+		}
+		if bytes.Contains(line, []byte("@generated")) || bytes.Contains(line, []byte("<auto-generated")) {
+			return true
+		}
+		if bytes.Contains(line, []byte("DO NOT EDIT")) && bytes.Contains(bytes.ToLower(line), []byte("generated")) {
+			return true
+		}
+	}
+	return false
+}
+
+// looksMinified は平均行長が極端に長い（改行をほとんど含まない）内容かを判定します。
+func looksMinified(content []byte) bool {
+	if len(content) < minifiedMinSize {
+		return false
+	}
+	lines := bytes.Count(content, []byte{'\n'}) + 1
+	return len(content)/lines >= minifiedAvgLineLength
+}
@@ -0,0 +1,62 @@
+package generated
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	minifiedJS := "!function(){" + strings.Repeat("var a=1;", 500) + "}();"
+	longCode := strings.Repeat("const value = computeSomething(input);\n", 200)
+
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    string
+	}{
+		{"go generated", "api/user.pb.go", "// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: user.proto\n\npackage api\n", ReasonMarker},
+		{"marker after build tags", "z_gen.go", "//go:build linux\n\n// Code generated by mkerrors; DO NOT EDIT.\n\npackage unix\n", ReasonMarker},
+		{"python generated", "schema_pb2.py", "# -*- coding: utf-8 -*-\n# Generated by the protocol buffer compiler.  DO NOT EDIT!\n", ReasonMarker},
+		{"@generated", "Foo.java", "/**\n * @generated\n */\nclass Foo {}\n", ReasonMarker},
+		{"auto-generated", "Model.cs", "//------\n// <auto-generated>\n//     This code was generated by a tool.\n", ReasonMarker},
+		{"DO NOT EDIT without generated", "config.go", "// DO NOT EDIT this value by hand\npackage config\n", ""},
+		{"marker far below header", "late.go", longCode + "// Code generated by x. DO NOT EDIT.\n", ""},
+		{"handwritten", "main.go", "package main\n\nfunc main() {}\n", ""},
+		{"lockfile", "web/package-lock.json", "{\n}\n", ReasonLockfile},
+		{"go.sum", "go.sum", "", ReasonLockfile},
+		{"min.js by name", "static/jquery.min.js", "x", ReasonMinified},
+		{"min.css by name", "static/app.min.css", "x", ReasonMinified},
+		{"minified content", "dist/bundle.js", minifiedJS, ReasonMinified},
+		{"long regular js", "src/app.js", longCode, ""},
+		{"long line in non-js", "data.go", minifiedJS, ""},
+		{"short one-liner js", "tiny.js", "export const a = 1;", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.path, []byte(tt.content)); got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsLockfile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"yarn.lock", true},
+		{"frontend/pnpm-lock.yaml", true},
+		{"Cargo.lock", true},
+		{"package.json", false},
+		{"lock.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsLockfile(tt.path); got != tt.want {
+			t.Errorf("IsLockfile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
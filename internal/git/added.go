@@ -0,0 +1,72 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+)
+
+// AddedLine はコミットで追加された行の、コミット後のファイルでの行番号と内容です。
+type AddedLine struct {
+	Line int
+	Text string
+}
+
+// GetAddedLines はコミットで追加された行をファイル（コミット後のパス）ごとに返します。
+// 初回コミットも対象です。マージコミットは第1親との差分ではないため呼び出し側で除外してください。
+func GetAddedLines(executor gitexec.Executor, commit string) (map[string][]AddedLine, error) {
+	output, err := executor.Run("show", "--format=", "--unified=0", "--no-color", "--no-ext-diff", "-M", commit, "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff for %s: %w", commit, err)
+	}
+	return ParseAddedLines(output), nil
+}
+
+// ParseAddedLines は "git diff --unified=0" 形式の出力から追加行を解析します。
+// 行の削除のみのファイルは追加行なし（nil）で含め、削除されたファイルは含めません。
+func ParseAddedLines(output string) map[string][]AddedLine {
+	result := make(map[string][]AddedLine)
+	var path string
+	inHeader := false
+	newLine := 0
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			inHeader, path = true, ""
+		case inHeader && strings.HasPrefix(line, "+++ "):
+			if path = addedDiffPath(line[4:]); path != "" {
+				result[path] = nil // 削除のみの変更も含める
+			}
+		case strings.HasPrefix(line, "@@"):
+			inHeader = false
+			newLine = 0
+			for _, field := range strings.Fields(line) {
+				if strings.HasPrefix(field, "+") {
+					start, _, _ := strings.Cut(field[1:], ",")
+					newLine, _ = strconv.Atoi(start)
+					break
+				}
+			}
+		case !inHeader && path != "" && newLine > 0 && strings.HasPrefix(line, "+"):
+			result[path] = append(result[path], AddedLine{Line: newLine, Text: line[1:]})
+			newLine++
+		}
+	}
+	return result
+}
+
+// addedDiffPath は "+++ " に続くパス（"b/path"、引用符付き、"/dev/null"）からコミット後のパスを返します。
+func addedDiffPath(s string) string {
+	s = strings.TrimSuffix(s, "\t")
+	if strings.HasPrefix(s, `"`) {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			s = unquoted
+		}
+	}
+	if s == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(s, "b/")
+}
@@ -0,0 +1,49 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAddedLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect map[string][]AddedLine
+	}{
+		{
+			name:   "empty",
+			input:  "",
+			expect: map[string][]AddedLine{},
+		},
+		{
+			name: "new file and modified file",
+			input: "diff --git a/new.go b/new.go\nnew file mode 100644\n--- /dev/null\n+++ b/new.go\n" +
+				"@@ -0,0 +1,2 @@\n+package main\n+\n" +
+				"diff --git a/old.go b/old.go\n--- a/old.go\n+++ b/old.go\n" +
+				"@@ -3 +3 @@\n-a := 1\n+a := 2\n@@ -9,0 +10,1 @@\n++++x\n" +
+				"diff --git a/shrink.go b/shrink.go\n--- a/shrink.go\n+++ b/shrink.go\n@@ -5,2 +4,0 @@\n-x\n-y\n",
+			expect: map[string][]AddedLine{
+				"new.go":    {{Line: 1, Text: "package main"}, {Line: 2, Text: ""}},
+				"old.go":    {{Line: 3, Text: "a := 2"}, {Line: 10, Text: "+++x"}},
+				"shrink.go": nil,
+			},
+		},
+		{
+			name: "deleted file and quoted path",
+			input: "diff --git a/gone.go b/gone.go\ndeleted file mode 100644\n--- a/gone.go\n+++ /dev/null\n@@ -1 +0,0 @@\n-x\n" +
+				"diff --git \"a/dir/b c.go\" \"b/dir/b c.go\"\n--- \"a/dir/b c.go\"\n+++ \"b/dir/b c.go\"\n@@ -1,0 +2 @@\n+y\n\\ No newline at end of file\n",
+			expect: map[string][]AddedLine{
+				"dir/b c.go": {{Line: 2, Text: "y"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseAddedLines(tt.input); !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("ParseAddedLines() = %+v, want %+v", got, tt.expect)
+			}
+		})
+	}
+}
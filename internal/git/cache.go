@@ -0,0 +1,156 @@
+package git
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+)
+
+// revisionCommands はリビジョンを指定した場合にキャッシュ対象とする参照系gitサブコマンド
+// （作業ツリー・index・refs・設定に依存する diff・ls-files・show-ref・config 等はキャッシュしない）
+var revisionCommands = map[string]bool{
+	"rev-parse":  true,
+	"log":        true,
+	"show":       true,
+	"ls-tree":    true,
+	"merge-base": true,
+}
+
+// repoPathQueries は作業ディレクトリだけで結果が決まる rev-parse のオプション
+var repoPathQueries = map[string]bool{
+	"--show-toplevel":       true,
+	"--git-dir":             true,
+	"--git-common-dir":      true,
+	"--absolute-git-dir":    true,
+	"--show-cdup":           true,
+	"--show-prefix":         true,
+	"--is-inside-work-tree": true,
+}
+
+// cachedResult はキャッシュされたgit実行結果
+type cachedResult struct {
+	output string
+	err    error
+}
+
+// CachingExecutor は1コマンド実行内でgitの参照系クエリ結果をメモ化するExecutorです。
+// 結果が変わらないクエリ（コミットハッシュを指定した log・show 等と、リポジトリのパス）のみ対象で、
+// 同一ディレクトリ・同一引数のクエリは1回だけ実行されます。
+// それ以外のコマンド（commit, notes add, fetch等）や標準入力付きのコマンドを実行するとキャッシュは破棄されます。
+type CachingExecutor struct {
+	inner gitexec.Executor
+	mu    sync.Mutex
+	cache map[string]cachedResult
+}
+
+// NewCachingExecutor creates a CachingExecutor wrapping the given executor
+func NewCachingExecutor(inner gitexec.Executor) *CachingExecutor {
+	return &CachingExecutor{
+		inner: inner,
+		cache: make(map[string]cachedResult),
+	}
+}
+
+// Run executes a git command, returning the cached result for repeated read-only queries
+func (c *CachingExecutor) Run(args ...string) (string, error) {
+	// 作業ディレクトリが変わると結果も変わるためキーに含める
+	cwd, _ := os.Getwd()
+	return c.run(cwd, args, func() (string, error) {
+		return c.inner.Run(args...)
+	})
+}
+
+// RunInDir executes a git command in dir, returning the cached result for repeated read-only queries
+func (c *CachingExecutor) RunInDir(dir string, args ...string) (string, error) {
+	return c.run(dir, args, func() (string, error) {
+		return c.inner.RunInDir(dir, args...)
+	})
+}
+
+// RunWithStdin executes a git command with stdin input (never cached, invalidates the cache)
+func (c *CachingExecutor) RunWithStdin(stdin string, args ...string) (string, error) {
+	output, err := c.inner.RunWithStdin(stdin, args...)
+	c.Invalidate()
+	return output, err
+}
+
+// Invalidate clears all cached results
+func (c *CachingExecutor) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache = make(map[string]cachedResult)
+}
+
+func (c *CachingExecutor) run(dir string, args []string, exec func() (string, error)) (string, error) {
+	if !isCacheable(args) {
+		output, err := exec()
+		c.Invalidate()
+		return output, err
+	}
+
+	key := dir + "\x00" + strings.Join(args, "\x00")
+
+	c.mu.Lock()
+	if r, ok := c.cache[key]; ok {
+		c.mu.Unlock()
+		return r.output, r.err
+	}
+	c.mu.Unlock()
+
+	output, err := exec()
+
+	c.mu.Lock()
+	c.cache[key] = cachedResult{output: output, err: err}
+	c.mu.Unlock()
+
+	return output, err
+}
+
+// isCacheable は結果が変わらない参照系クエリかどうかを判定します。
+// リビジョン引数（"--" より前のオプション以外の引数）がすべてフルハッシュで固定されている場合と、
+// rev-parse でリポジトリのパスのみを問い合わせる場合が対象です。HEAD・ブランチ名等は移動するため対象外です。
+func isCacheable(args []string) bool {
+	if len(args) == 0 || !revisionCommands[args[0]] {
+		return false
+	}
+	revisions, pathQueries := 0, 0
+	for _, a := range args[1:] {
+		if a == "--" {
+			break
+		}
+		if strings.HasPrefix(a, "-") {
+			if args[0] == "rev-parse" && repoPathQueries[a] {
+				pathQueries++
+			}
+			continue
+		}
+		if !isPinnedRevision(a) {
+			return false
+		}
+		revisions++
+	}
+	return revisions > 0 || pathQueries > 0
+}
+
+// isPinnedRevision はリビジョン指定がフルハッシュ（SHA-1/SHA-256）のみから成るかを判定します。
+// A..B・A...B の範囲、^A の除外、A^・A~2・A^{tree} の相対指定、A:path のオブジェクト指定を受け付けます。
+func isPinnedRevision(rev string) bool {
+	if i := strings.Index(rev, ":"); i >= 0 {
+		rev = rev[:i]
+	}
+	if a, b, ok := strings.Cut(rev, ".."); ok {
+		return isPinnedRevision(a) && isPinnedRevision(strings.TrimPrefix(b, "."))
+	}
+	rev = strings.TrimPrefix(rev, "^")
+
+	n := 0
+	for n < len(rev) && strings.IndexByte("0123456789abcdef", rev[n]) >= 0 {
+		n++
+	}
+	if n != 40 && n != 64 {
+		return false
+	}
+	return n == len(rev) || rev[n] == '^' || rev[n] == '~'
+}
@@ -0,0 +1,145 @@
+package git
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+)
+
+const (
+	testCommit  = "0123456789abcdef0123456789abcdef01234567"
+	testCommit2 = "89abcdef0123456789abcdef0123456789abcdef"
+)
+
+func TestCachingExecutor_MemoizesReadOnlyQueries(t *testing.T) {
+	mock := gitexec.NewMockExecutor()
+	mock.RunFunc = func(args ...string) (string, error) {
+		return "2026-01-02", nil
+	}
+	c := NewCachingExecutor(mock)
+
+	for i := 0; i < 3; i++ {
+		out, err := c.Run("log", "-1", "--format=%cs", testCommit)
+		if err != nil || out != "2026-01-02" {
+			t.Fatalf("Run() = (%q, %v), want (\"2026-01-02\", nil)", out, err)
+		}
+	}
+
+	if calls := len(mock.GetCalls("Run")); calls != 1 {
+		t.Errorf("expected 1 underlying call, got %d", calls)
+	}
+}
+
+func TestCachingExecutor_CachesErrors(t *testing.T) {
+	mock := gitexec.NewMockExecutor()
+	mock.RunFunc = func(args ...string) (string, error) {
+		return "", fmt.Errorf("unknown revision")
+	}
+	c := NewCachingExecutor(mock)
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Run("rev-parse", testCommit+"~1"); err == nil {
+			t.Fatal("expected cached error")
+		}
+	}
+
+	if calls := len(mock.GetCalls("Run")); calls != 1 {
+		t.Errorf("expected 1 underlying call, got %d", calls)
+	}
+}
+
+func TestCachingExecutor_MutatingCommandInvalidates(t *testing.T) {
+	mock := gitexec.NewMockExecutor()
+	c := NewCachingExecutor(mock)
+
+	c.Run("show", testCommit)
+	c.Run("commit", "-m", "msg")
+	c.Run("commit", "-m", "msg")
+	c.Run("show", testCommit)
+
+	if calls := len(mock.GetCalls("Run")); calls != 4 {
+		t.Errorf("expected 4 underlying calls (mutating commands are never cached), got %d", calls)
+	}
+}
+
+func TestCachingExecutor_RunWithStdinInvalidates(t *testing.T) {
+	mock := gitexec.NewMockExecutor()
+	c := NewCachingExecutor(mock)
+
+	c.Run("show", testCommit)
+	c.RunWithStdin("{}", "notes", "add", "-f", "-F", "-", testCommit)
+	c.Run("show", testCommit)
+
+	if calls := len(mock.GetCalls("Run")); calls != 2 {
+		t.Errorf("expected 2 underlying calls (RunWithStdin clears the cache), got %d", calls)
+	}
+}
+
+func TestCachingExecutor_WorkingTreeQueriesNotCached(t *testing.T) {
+	mock := gitexec.NewMockExecutor()
+	c := NewCachingExecutor(mock)
+
+	for i := 0; i < 2; i++ {
+		c.Run("diff", "--numstat")
+		c.Run("ls-files", "--others", "--exclude-standard")
+		c.Run("rev-parse", "HEAD")
+	}
+
+	if calls := len(mock.GetCalls("Run")); calls != 6 {
+		t.Errorf("expected 6 underlying calls, got %d", calls)
+	}
+}
+
+func TestCachingExecutor_RunInDirKeyedByDir(t *testing.T) {
+	mock := gitexec.NewMockExecutor()
+	mock.RunInDirFunc = func(dir string, args ...string) (string, error) {
+		return dir, nil
+	}
+	c := NewCachingExecutor(mock)
+
+	a, _ := c.RunInDir("/repo/a", "rev-parse", "--show-toplevel")
+	b, _ := c.RunInDir("/repo/b", "rev-parse", "--show-toplevel")
+	c.RunInDir("/repo/a", "rev-parse", "--show-toplevel")
+
+	if a != "/repo/a" || b != "/repo/b" {
+		t.Errorf("results mixed between directories: a=%q b=%q", a, b)
+	}
+	if calls := len(mock.GetCalls("RunInDir")); calls != 2 {
+		t.Errorf("expected 2 underlying calls, got %d", calls)
+	}
+}
+
+func TestIsCacheable(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"rev-parse", "--show-toplevel"}, true},
+		{[]string{"rev-parse", testCommit + "^"}, true},
+		{[]string{"rev-parse", "HEAD"}, false},
+		{[]string{"rev-parse", "--abbrev-ref", "HEAD"}, false},
+		{[]string{"log", "-1", "--format=%cs", testCommit}, true},
+		{[]string{"log", "--format=%H", testCommit + ".." + testCommit2, "--", "main.go"}, true},
+		{[]string{"log", "--format=%H", testCommit + ".." + "main"}, false},
+		{[]string{"log", "--format=%H"}, false},
+		{[]string{"log", "--since=2026-01-01", "--all"}, false},
+		{[]string{"show", testCommit + ":main.go"}, true},
+		{[]string{"show", ":main.go"}, false},
+		{[]string{"ls-tree", "-r", testCommit[:7]}, false},
+		{[]string{"merge-base", testCommit, testCommit2}, true},
+		{[]string{"diff", testCommit, testCommit2}, false},
+		{[]string{"ls-files", "--others"}, false},
+		{[]string{"config", "user.name"}, false},
+		{[]string{"notes", "--ref=refs/aict/authorship", "show", testCommit}, false},
+		{[]string{"commit", "-m", "msg"}, false},
+		{[]string{"fetch", "origin"}, false},
+		{[]string{}, false},
+	}
+
+	for _, tt := range tests {
+		if got := isCacheable(tt.args); got != tt.want {
+			t.Errorf("isCacheable(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+)
+
+// CombinedLines はマージコミットの結合diff（git diff-tree -c）から求めた、マージ結果の行番号です。
+type CombinedLines struct {
+	Resolved [][]int // すべての親と異なる行（コンフリクト解消などマージ時に書かれた行）
+	Merged   [][]int // 第1親にはないが他の親から取り込んだ行
+}
+
+// IsMergeCommit はコミットが複数の親を持つかを返します。
+func IsMergeCommit(executor gitexec.Executor, commit string) bool {
+	_, err := executor.Run("rev-parse", "--verify", "--quiet", commit+"^2")
+	return err == nil
+}
+
+// GetCombinedLines はマージコミットの結合diffを取得し、ファイルごとの行番号を返します。
+func GetCombinedLines(executor gitexec.Executor, commit string) (map[string]CombinedLines, error) {
+	output, err := executor.Run("diff-tree", "-c", "-U0", "--no-commit-id", "--no-color", commit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get combined diff for %s: %w", commit, err)
+	}
+	return ParseCombinedDiff(output), nil
+}
+
+// ParseCombinedDiff は git diff-tree -c -U0 の出力を解析します。
+// 各行の先頭には親の数だけ "+"・"-"・" " が並び、すべて "+" の行はどの親にもない行、
+// 先頭（第1親）の列だけが "+" の行は他の親から取り込んだ行です。"-" を含む行はマージ結果にありません。
+func ParseCombinedDiff(output string) map[string]CombinedLines {
+	result := make(map[string]CombinedLines)
+
+	var path string
+	var resolved, merged []int
+	parents := 0
+	newLine := 0
+	flush := func() {
+		if path != "" && (len(resolved) > 0 || len(merged) > 0) {
+			result[path] = CombinedLines{Resolved: LineRanges(resolved), Merged: LineRanges(merged)}
+		}
+		resolved, merged = nil, nil
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --combined ") || strings.HasPrefix(line, "diff --cc "):
+			flush()
+			_, path, _ = strings.Cut(line[len("diff "):], " ")
+			if unquoted, err := strconv.Unquote(path); err == nil {
+				path = unquoted
+			}
+			parents = 0
+		case strings.HasPrefix(line, "@@@"):
+			// "@@@ -a,b -c,d +e,f @@@"（"@" の数は親の数 + 1）
+			parents = len(line) - len(strings.TrimLeft(line, "@")) - 1
+			newLine = 0
+			for _, field := range strings.Fields(line) {
+				if strings.HasPrefix(field, "+") {
+					start, _, _ := strings.Cut(field[1:], ",")
+					newLine, _ = strconv.Atoi(start)
+				}
+			}
+		case parents > 0 && len(line) >= parents:
+			prefix := line[:parents]
+			if strings.Contains(prefix, "-") || strings.Trim(prefix, "+ ") != "" {
+				continue
+			}
+			if strings.Trim(prefix, "+") == "" {
+				resolved = append(resolved, newLine)
+			} else if prefix[0] == '+' {
+				merged = append(merged, newLine)
+			}
+			newLine++
+		}
+	}
+	flush()
+	return result
+}
+
+// LineRanges は昇順の行番号を [[start, end], [single], ...] 形式にまとめます。
+func LineRanges(lines []int) [][]int {
+	var ranges [][]int
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, []int{lines[i]})
+		} else {
+			ranges = append(ranges, []int{lines[i], lines[j]})
+		}
+		i = j + 1
+	}
+	return ranges
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCombinedDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect map[string]CombinedLines
+	}{
+		{
+			name:   "empty",
+			input:  "",
+			expect: map[string]CombinedLines{},
+		},
+		{
+			name: "resolved and merged lines",
+			input: "diff --combined f.go\nindex 097825b,8591ad8..6cef892\n--- a/f.go\n+++ b/f.go\n" +
+				"@@@ -2,1 -2,1 +2,1 @@@\n- B-main\n -B-side\n++B-resolved\n" +
+				"@@@ -4,0 -4,1 +4,2 @@@\n+ side2\n+ side3\n",
+			expect: map[string]CombinedLines{
+				"f.go": {Resolved: [][]int{{2}}, Merged: [][]int{{4, 5}}},
+			},
+		},
+		{
+			name: "lines kept from the first parent are not counted",
+			input: "diff --combined a.go\n--- a/a.go\n+++ b/a.go\n" +
+				"@@@ -1,2 -1,1 +1,2 @@@\n -x\n +y\n++z\n" +
+				"diff --combined \"dir/b c.go\"\n--- a/dir/b c.go\n+++ b/dir/b c.go\n" +
+				"@@@ -10,0 -10,1 +10,1 @@@\n++new\n\\ No newline at end of file\n",
+			expect: map[string]CombinedLines{
+				"a.go":       {Resolved: [][]int{{2}}},
+				"dir/b c.go": {Resolved: [][]int{{10}}},
+			},
+		},
+		{
+			name:   "octopus merge",
+			input:  "diff --cc o.go\n--- a/o.go\n+++ b/o.go\n@@@@ -1,0 -1,0 -1,1 +1,2 @@@@\n+++one\n++ two\n",
+			expect: map[string]CombinedLines{"o.go": {Resolved: [][]int{{1}}, Merged: [][]int{{2}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseCombinedDiff(tt.input)
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("ParseCombinedDiff() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+)
+
+// PatchIDs はコミットごとの patch-id（git patch-id --stable）を返します。
+// cherry-pick・rebase で作り直されたコミットは、差分が同じであれば元のコミットと同じ patch-id になります。
+// マージコミットと変更のないコミットは含めません。
+func PatchIDs(executor gitexec.Executor, commits []string) (map[string]string, error) {
+	if len(commits) == 0 {
+		return map[string]string{}, nil
+	}
+	diffs, err := executor.RunWithStdin(strings.Join(commits, "\n")+"\n",
+		"log", "--stdin", "--no-walk=unsorted", "--no-merges", "--format=commit %H", "-p", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diffs for patch-id: %w", err)
+	}
+	if strings.TrimSpace(diffs) == "" {
+		return map[string]string{}, nil
+	}
+	output, err := executor.RunWithStdin(diffs+"\n", "patch-id", "--stable")
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute patch-id: %w", err)
+	}
+	return ParsePatchIDs(output), nil
+}
+
+// ParsePatchIDs は "git patch-id" の出力（"<patch-id> <commit>" の行）をコミットから patch-id へのマップにします。
+func ParsePatchIDs(output string) map[string]string {
+	ids := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			ids[fields[1]] = fields[0]
+		}
+	}
+	return ids
+}
@@ -0,0 +1,46 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+)
+
+func TestParsePatchIDs(t *testing.T) {
+	output := "f1e2d3 aaa111\n\nc4b5a6 bbb222\nmalformed\n"
+	want := map[string]string{"aaa111": "f1e2d3", "bbb222": "c4b5a6"}
+	if got := ParsePatchIDs(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePatchIDs() = %v, want %v", got, want)
+	}
+}
+
+func TestPatchIDs(t *testing.T) {
+	mock := gitexec.NewMockExecutor()
+	mock.RunWithStdinFunc = func(stdin string, args ...string) (string, error) {
+		switch args[0] {
+		case "log":
+			if stdin != "aaa111\nbbb222\n" {
+				t.Errorf("log stdin = %q", stdin)
+			}
+			return "commit aaa111\n\ndiff --git a/x b/x", nil
+		case "patch-id":
+			return "f1e2d3 aaa111", nil
+		}
+		return "", nil
+	}
+
+	got, err := PatchIDs(mock, []string{"aaa111", "bbb222"})
+	if err != nil {
+		t.Fatalf("PatchIDs() error = %v", err)
+	}
+	if want := map[string]string{"aaa111": "f1e2d3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PatchIDs() = %v, want %v", got, want)
+	}
+
+	// コミットがなければ git を実行しない
+	mock.Reset()
+	if got, _ := PatchIDs(mock, nil); len(got) != 0 || len(mock.CallLog) != 0 {
+		t.Errorf("PatchIDs(nil) = %v with %d calls", got, len(mock.CallLog))
+	}
+}
@@ -0,0 +1,93 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+)
+
+// IsShallowRepository はshallow clone（CIのdepth指定チェックアウト等）かどうかを判定します。
+func IsShallowRepository(executor gitexec.Executor) bool {
+	output, err := executor.Run("rev-parse", "--is-shallow-repository")
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(output) == "true"
+}
+
+// RefExists は指定したrefがローカルに存在するかを返します。
+func RefExists(executor gitexec.Executor, ref string) bool {
+	_, err := executor.Run("rev-parse", "--verify", "--quiet", "--end-of-options", ref)
+	return err == nil
+}
+
+// MergeBase は2つのリビジョンの共通祖先を返します。
+// shallow cloneで履歴が不足している場合はエラーになります。
+func MergeBase(executor gitexec.Executor, a, b string) (string, error) {
+	if err := gitexec.ValidateRevisionArg(a); err != nil {
+		return "", err
+	}
+	if err := gitexec.ValidateRevisionArg(b); err != nil {
+		return "", err
+	}
+	output, err := executor.Run("merge-base", a, b)
+	if err != nil {
+		return "", fmt.Errorf("failed to find merge base of %s and %s: %w", a, b, err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// Deepen はshallow cloneの履歴をdepthコミット分だけ追加取得します。
+func Deepen(executor gitexec.Executor, remote string, depth int) error {
+	if err := gitexec.ValidateRevisionArg(remote); err != nil {
+		return err
+	}
+	_, err := executor.Run("fetch", "--quiet", fmt.Sprintf("--deepen=%d", depth), remote)
+	if err != nil {
+		return fmt.Errorf("failed to deepen history from %s: %w", remote, err)
+	}
+	return nil
+}
+
+// SplitRange は "base..head" 形式のrange specを分割します。
+// "..." 形式や単一リビジョンの場合は ok=false を返します。
+func SplitRange(rangeSpec string) (base, head string, ok bool) {
+	if strings.Contains(rangeSpec, "...") {
+		return "", "", false
+	}
+	parts := strings.SplitN(rangeSpec, "..", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	base, head = parts[0], parts[1]
+	if base == "" {
+		base = "HEAD"
+	}
+	if head == "" {
+		head = "HEAD"
+	}
+	return base, head, true
+}
+
+// PinRange は "base..head" 形式のrange specの両端をコミットのフルハッシュに置き換えます。
+// 固定した範囲のクエリは CachingExecutor でキャッシュされるため、同じ範囲を繰り返し集計する場合に使います。
+// 解決できない場合や "base..head" 以外の形式は rangeSpec をそのまま返します。
+func PinRange(executor gitexec.Executor, rangeSpec string) string {
+	base, head, ok := SplitRange(rangeSpec)
+	if !ok {
+		return rangeSpec
+	}
+	pinned := make([]string, 0, 2)
+	for _, rev := range []string{base, head} {
+		if gitexec.ValidateRevisionArg(rev) != nil {
+			return rangeSpec
+		}
+		hash, err := executor.Run("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+		if err != nil || hash == "" {
+			return rangeSpec
+		}
+		pinned = append(pinned, strings.TrimSpace(hash))
+	}
+	return pinned[0] + ".." + pinned[1]
+}
@@ -0,0 +1,139 @@
+package git
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+)
+
+func TestIsShallowRepository(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		err    error
+		want   bool
+	}{
+		{name: "shallow", output: "true", want: true},
+		{name: "full clone", output: "false", want: false},
+		{name: "git error", err: fmt.Errorf("not a git repository"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := gitexec.NewMockExecutor()
+			mock.RunFunc = func(args ...string) (string, error) {
+				return tt.output, tt.err
+			}
+			if got := IsShallowRepository(mock); got != tt.want {
+				t.Errorf("IsShallowRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefExists(t *testing.T) {
+	mock := gitexec.NewMockExecutor()
+	mock.RunFunc = func(args ...string) (string, error) {
+		if args[len(args)-1] == "refs/aict/authorship" {
+			return "abc123", nil
+		}
+		return "", fmt.Errorf("exit status 1")
+	}
+
+	if !RefExists(mock, "refs/aict/authorship") {
+		t.Error("RefExists() = false for existing ref")
+	}
+	if RefExists(mock, "refs/missing") {
+		t.Error("RefExists() = true for missing ref")
+	}
+}
+
+func TestMergeBase(t *testing.T) {
+	mock := gitexec.NewMockExecutor()
+	mock.RunFunc = func(args ...string) (string, error) {
+		return "base123\n", nil
+	}
+
+	got, err := MergeBase(mock, "origin/main", "HEAD")
+	if err != nil {
+		t.Fatalf("MergeBase() error = %v", err)
+	}
+	if got != "base123" {
+		t.Errorf("MergeBase() = %q, want %q", got, "base123")
+	}
+
+	if _, err := MergeBase(mock, "--evil", "HEAD"); err == nil {
+		t.Error("MergeBase() should reject option-like revision")
+	}
+}
+
+func TestDeepen(t *testing.T) {
+	mock := gitexec.NewMockExecutor()
+	mock.RunFunc = func(args ...string) (string, error) {
+		return "", nil
+	}
+
+	if err := Deepen(mock, "origin", 50); err != nil {
+		t.Fatalf("Deepen() error = %v", err)
+	}
+
+	calls := mock.GetCalls("Run")
+	if len(calls) != 1 || calls[0].Args[2] != "--deepen=50" {
+		t.Errorf("unexpected git call: %+v", calls)
+	}
+}
+
+func TestSplitRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantBase string
+		wantHead string
+		wantOK   bool
+	}{
+		{"origin/main..HEAD", "origin/main", "HEAD", true},
+		{"abc123..", "abc123", "HEAD", true},
+		{"..feature", "HEAD", "feature", true},
+		{"main...feature", "", "", false},
+		{"HEAD", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			base, head, ok := SplitRange(tt.input)
+			if base != tt.wantBase || head != tt.wantHead || ok != tt.wantOK {
+				t.Errorf("SplitRange(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.input, base, head, ok, tt.wantBase, tt.wantHead, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPinRange(t *testing.T) {
+	mock := gitexec.NewMockExecutor()
+	mock.RunFunc = func(args ...string) (string, error) {
+		switch args[len(args)-1] {
+		case "origin/main^{commit}":
+			return testCommit, nil
+		case "HEAD^{commit}":
+			return testCommit2, nil
+		}
+		return "", fmt.Errorf("unknown revision")
+	}
+
+	tests := []struct {
+		rangeSpec string
+		want      string
+	}{
+		{"origin/main..HEAD", testCommit + ".." + testCommit2},
+		{"origin/main..", testCommit + ".." + testCommit2},
+		{"missing..HEAD", "missing..HEAD"},
+		{"origin/main...HEAD", "origin/main...HEAD"},
+		{"HEAD", "HEAD"},
+	}
+	for _, tt := range tests {
+		if got := PinRange(mock, tt.rangeSpec); got != tt.want {
+			t.Errorf("PinRange(%q) = %q, want %q", tt.rangeSpec, got, tt.want)
+		}
+	}
+}
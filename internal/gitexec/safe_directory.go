@@ -0,0 +1,55 @@
+package gitexec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// dubiousOwnershipMessage は所有者の異なるリポジトリに対する git のエラーメッセージの一部です
+// （Docker でホストのリポジトリをバインドマウントした場合など）。
+const dubiousOwnershipMessage = "detected dubious ownership"
+
+// SafeDirectoryEnv を設定すると、aict が起動する git に safe.directory としてリポジトリを渡します（オプトイン）。
+const SafeDirectoryEnv = "AICT_SAFE_DIRECTORY"
+
+// IsDubiousOwnership は git がリポジトリの所有者の違いを理由に実行を拒否したエラーかを判定します。
+func IsDubiousOwnership(err error) bool {
+	return err != nil && strings.Contains(err.Error(), dubiousOwnershipMessage)
+}
+
+// DubiousOwnershipHint は safe.directory の設定方法の案内を返します。
+func DubiousOwnershipHint(dir string) string {
+	if dir == "" {
+		dir = "<repository>"
+	}
+	return fmt.Sprintf("git refuses to use %s because it is owned by another user (common with Docker bind mounts).\n"+
+		"  Trust it permanently:   git config --global --add safe.directory %s\n"+
+		"  Or only for aict:       %s=1 aict ...", dir, dir, SafeDirectoryEnv)
+}
+
+// TrustDirectory はこのプロセスから起動する git に safe.directory=dir を渡します。
+// 環境変数の GIT_CONFIG_COUNT / GIT_CONFIG_KEY_n / GIT_CONFIG_VALUE_n（git 2.31以降）はコマンドラインの -c と同じ扱いで、
+// safe.directory が有効になります。リポジトリやグローバルの設定ファイルは変更しません。
+func TrustDirectory(dir string) error {
+	if dir == "" {
+		return errors.New("repository directory is unknown")
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+
+	n := 0
+	if count := os.Getenv("GIT_CONFIG_COUNT"); count != "" {
+		var err error
+		if n, err = strconv.Atoi(count); err != nil || n < 0 {
+			return fmt.Errorf("invalid GIT_CONFIG_COUNT %q", count)
+		}
+	}
+	os.Setenv(fmt.Sprintf("GIT_CONFIG_KEY_%d", n), "safe.directory")
+	os.Setenv(fmt.Sprintf("GIT_CONFIG_VALUE_%d", n), dir)
+	return os.Setenv("GIT_CONFIG_COUNT", strconv.Itoa(n+1))
+}
@@ -0,0 +1,82 @@
+package gitexec
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestIsDubiousOwnership(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"dubious ownership", errors.New("git rev-parse failed: exit status 128\nstderr: fatal: detected dubious ownership in repository at '/repo'"), true},
+		{"other git error", errors.New("fatal: not a git repository"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDubiousOwnership(tt.err); got != tt.want {
+				t.Errorf("IsDubiousOwnership() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	hint := DubiousOwnershipHint("/repo")
+	if !strings.Contains(hint, "git config --global --add safe.directory /repo") || !strings.Contains(hint, SafeDirectoryEnv+"=1") {
+		t.Errorf("DubiousOwnershipHint() = %q", hint)
+	}
+}
+
+func TestTrustDirectory_AppendsToExistingConfig(t *testing.T) {
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "core.pager")
+	t.Setenv("GIT_CONFIG_VALUE_0", "cat")
+	t.Setenv("GIT_CONFIG_KEY_1", "")
+	t.Setenv("GIT_CONFIG_VALUE_1", "")
+
+	dir := t.TempDir()
+	if err := TrustDirectory(dir); err != nil {
+		t.Fatalf("TrustDirectory() error = %v", err)
+	}
+	if os.Getenv("GIT_CONFIG_COUNT") != "2" || os.Getenv("GIT_CONFIG_KEY_1") != "safe.directory" || os.Getenv("GIT_CONFIG_KEY_0") != "core.pager" {
+		t.Errorf("env = COUNT=%s KEY_0=%s KEY_1=%s", os.Getenv("GIT_CONFIG_COUNT"), os.Getenv("GIT_CONFIG_KEY_0"), os.Getenv("GIT_CONFIG_KEY_1"))
+	}
+
+	t.Setenv("GIT_CONFIG_COUNT", "x")
+	if err := TrustDirectory(dir); err == nil {
+		t.Error("TrustDirectory() should reject an invalid GIT_CONFIG_COUNT")
+	}
+}
+
+// TestTrustDirectory_DubiousOwnership は root で他ユーザー所有のリポジトリを操作する（Docker のバインドマウント相当）。
+func TestTrustDirectory_DubiousOwnership(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires root to create a repository owned by another user")
+	}
+	for _, key := range []string{"GIT_CONFIG_COUNT", "GIT_CONFIG_KEY_0", "GIT_CONFIG_VALUE_0"} {
+		t.Setenv(key, "")
+	}
+	os.Unsetenv("GIT_CONFIG_COUNT")
+
+	repo, _ := setupGitRepo(t)
+	if err := exec.Command("chown", "-R", "1000:1000", repo).Run(); err != nil {
+		t.Skipf("chown failed: %v", err)
+	}
+
+	executor := NewExecutor()
+	if _, err := executor.RunInDir(repo, "rev-parse", "--show-toplevel"); !IsDubiousOwnership(err) {
+		t.Skipf("git did not report dubious ownership (safe.directory configured globally?): %v", err)
+	}
+
+	if err := TrustDirectory(repo); err != nil {
+		t.Fatalf("TrustDirectory() error = %v", err)
+	}
+	if _, err := executor.RunInDir(repo, "rev-parse", "--show-toplevel"); err != nil {
+		t.Errorf("git should accept the trusted repository: %v", err)
+	}
+}
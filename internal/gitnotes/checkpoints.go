@@ -0,0 +1,106 @@
+package gitnotes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// CheckpointNotesRef はチェックポイントを保存する git notes のref（checkpoint_storage: notes の場合）
+	CheckpointNotesRef = "refs/aict/checkpoints"
+	// CheckpointNotesFullRef は git notes --ref=CheckpointNotesRef が実際に更新するrefです。
+	CheckpointNotesFullRef = "refs/notes/" + CheckpointNotesRef
+)
+
+// ListCheckpointNotes はチェックポイントのノートを、ノートを付けたコミットごとに返します（内容はJSONL）。
+// ノートがない場合は空のmapを返します。
+func (nm *NotesManager) ListCheckpointNotes() (map[string]string, error) {
+	output, err := nm.executor.Run("notes", "--ref="+CheckpointNotesRef, "list")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoint notes: %w", err)
+	}
+	notes := make(map[string]string)
+	if output == "" {
+		return notes, nil
+	}
+
+	// "<ノートのblob> <コミット>" の各行から、ノートの内容を cat-file --batch で1回の実行で読む
+	var blobs, commits []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		blobs = append(blobs, fields[0])
+		commits = append(commits, fields[1])
+	}
+	contents, err := nm.catBlobs(blobs)
+	if err != nil {
+		return nil, err
+	}
+	for i, commit := range commits {
+		notes[commit] = contents[i]
+	}
+	return notes, nil
+}
+
+// catBlobs は複数のblobの内容を git cat-file --batch で読み込みます。
+func (nm *NotesManager) catBlobs(blobs []string) ([]string, error) {
+	output, err := nm.executor.RunWithStdin(strings.Join(blobs, "\n")+"\n", "cat-file", "--batch")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint notes: %w", err)
+	}
+
+	// 出力は各blobについて "<oid> blob <size>\n<内容>\n"
+	contents := make([]string, 0, len(blobs))
+	rest := output
+	for range blobs {
+		header, body, ok := strings.Cut(rest, "\n")
+		fields := strings.Fields(header)
+		if !ok || len(fields) != 3 || fields[1] != "blob" {
+			return nil, fmt.Errorf("unexpected git cat-file output: %q", header)
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil || size > len(body) {
+			return nil, fmt.Errorf("unexpected git cat-file output: %q", header)
+		}
+		contents = append(contents, body[:size])
+		rest = strings.TrimPrefix(body[size:], "\n")
+	}
+	return contents, nil
+}
+
+// SetCheckpointNote はコミットのチェックポイントのノートを data（JSONL）で置き換えます。
+func (nm *NotesManager) SetCheckpointNote(commit, data string) error {
+	// チェックポイントはスナップショットを含み大きくなり得るため、引数ではなく標準入力で渡す
+	if _, err := nm.executor.RunWithStdin(data, "notes", "--ref="+CheckpointNotesRef, "add", "-f", "-F", "-", "--", commit); err != nil {
+		return fmt.Errorf("failed to save checkpoints for %s: %w", commit, err)
+	}
+	return nil
+}
+
+// RemoveCheckpointNotes は指定したコミットのチェックポイントのノートを削除します。
+// refごと削除せずノートの削除として記録するため、他のマシンのノートと git notes merge で正しく統合されます。
+func (nm *NotesManager) RemoveCheckpointNotes(commits []string) error {
+	if len(commits) == 0 {
+		return nil
+	}
+	args := append([]string{"notes", "--ref=" + CheckpointNotesRef, "remove", "--ignore-missing", "--"}, commits...)
+	if _, err := nm.executor.Run(args...); err != nil {
+		return fmt.Errorf("failed to remove checkpoint notes: %w", err)
+	}
+	return nil
+}
+
+// GetCheckpointNote はコミットのチェックポイントのノート（JSONL）を返します。ノートがない場合は空文字列です。
+func (nm *NotesManager) GetCheckpointNote(commit string) (string, error) {
+	output, err := nm.executor.Run("notes", "--ref="+CheckpointNotesRef, "show", "--", commit)
+	if err != nil {
+		if isNoteNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get checkpoints for %s: %w", commit, err)
+	}
+	return output, nil
+}
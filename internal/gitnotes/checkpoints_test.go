@@ -0,0 +1,104 @@
+package gitnotes
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+)
+
+func TestCheckpointNotes(t *testing.T) {
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.CreateTestFile(t, tmpDir, "a.go", "package a\n")
+	first := testutil.GitCommit(t, tmpDir, "first")
+	testutil.CreateTestFile(t, tmpDir, "b.go", "package b\n")
+	second := testutil.GitCommit(t, tmpDir, "second")
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	executor := gitexec.NewExecutor()
+	nm := NewNotesManagerWithExecutor(executor)
+	// ノートの一覧は完全なハッシュで返る
+	first, _ = executor.Run("rev-parse", first)
+	second, _ = executor.Run("rev-parse", second)
+
+	notes, err := nm.ListCheckpointNotes()
+	if err != nil || len(notes) != 0 {
+		t.Fatalf("ListCheckpointNotes() without notes = %v, %v", notes, err)
+	}
+	if got, err := nm.GetCheckpointNote(first); err != nil || got != "" {
+		t.Fatalf("GetCheckpointNote() without a note = %q, %v", got, err)
+	}
+
+	if err := nm.SetCheckpointNote(first, "{\"author\":\"a\"}\n{\"author\":\"b\"}\n"); err != nil {
+		t.Fatalf("SetCheckpointNote() error = %v", err)
+	}
+	if err := nm.SetCheckpointNote(second, "{\"author\":\"c\"}\n"); err != nil {
+		t.Fatalf("SetCheckpointNote() error = %v", err)
+	}
+
+	notes, err = nm.ListCheckpointNotes()
+	if err != nil {
+		t.Fatalf("ListCheckpointNotes() error = %v", err)
+	}
+	want := map[string]string{
+		first:  "{\"author\":\"a\"}\n{\"author\":\"b\"}\n",
+		second: "{\"author\":\"c\"}\n",
+	}
+	if !reflect.DeepEqual(notes, want) {
+		t.Errorf("ListCheckpointNotes() = %q, want %q", notes, want)
+	}
+
+	if err := nm.RemoveCheckpointNotes([]string{first}); err != nil {
+		t.Fatalf("RemoveCheckpointNotes() error = %v", err)
+	}
+	notes, _ = nm.ListCheckpointNotes()
+	if _, ok := notes[first]; ok || len(notes) != 1 {
+		t.Errorf("notes after removal = %q", notes)
+	}
+}
+
+func TestCatBlobs_UnexpectedOutput(t *testing.T) {
+	mockExec := gitexec.NewMockExecutor()
+	mockExec.RunWithStdinFunc = func(stdin string, args ...string) (string, error) {
+		return "abc missing\n", nil
+	}
+	nm := NewNotesManagerWithExecutor(mockExec)
+	if _, err := nm.catBlobs([]string{"abc"}); err == nil {
+		t.Error("catBlobs() should fail on a missing object")
+	}
+}
+
+func TestFetchAndPushCheckpoints(t *testing.T) {
+	mockExec := gitexec.NewMockExecutor()
+	mockExec.RunFunc = func(args ...string) (string, error) { return "", nil }
+	nm := NewNotesManagerWithExecutor(mockExec)
+
+	if err := nm.FetchCheckpoints("origin"); err != nil {
+		t.Fatalf("FetchCheckpoints() error = %v", err)
+	}
+	if err := nm.PushCheckpoints("origin"); err != nil {
+		t.Fatalf("PushCheckpoints() error = %v", err)
+	}
+
+	var got []string
+	for _, c := range mockExec.GetCalls("Run") {
+		got = append(got, strings.Join(c.Args, " "))
+	}
+	want := []string{
+		"fetch --no-tags origin +refs/notes/refs/aict/checkpoints:refs/notes/refs/aict/remotes/origin/checkpoints",
+		"rev-parse --verify --quiet --end-of-options refs/notes/refs/aict/checkpoints",
+		// チェックポイントはJSONLのため、同じコミットのノートは行を結合して取り込む
+		"notes --ref=refs/aict/checkpoints merge --strategy=cat_sort_uniq --quiet refs/notes/refs/aict/remotes/origin/checkpoints",
+		"rev-parse --verify --quiet --end-of-options refs/notes/refs/aict/checkpoints",
+		"push origin refs/notes/refs/aict/checkpoints:refs/notes/refs/aict/checkpoints",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("git calls =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
@@ -143,7 +144,24 @@ func parseAuthorshipLogsOutput(output string) map[string]*tracker.AuthorshipLog
 	return logs
 }
 
-// ListAuthorshipLogs lists all commits that have Authorship Logs
+// CountAuthorshipLogs returns the number of commits that have Authorship Logs.
+// ノートの内容は読み込まず notes list の行数のみを数えます。
+func (nm *NotesManager) CountAuthorshipLogs() int {
+	output, err := nm.executor.Run("notes", "--ref="+AuthorshipNotesRef, "list")
+	if err != nil {
+		return 0 // No notes exist yet
+	}
+	count := 0
+	for _, line := range strings.Split(output, "\n") {
+		if len(strings.Fields(line)) == 2 {
+			count++
+		}
+	}
+	return count
+}
+
+// ListAuthorshipLogs lists all commits that have Authorship Logs.
+// notes list でノートのblobを列挙し、git cat-file --batch の1プロセスで全件読み込みます（コミットごとの notes show によるN+1問題の解消）。
 func (nm *NotesManager) ListAuthorshipLogs() (map[string]*tracker.AuthorshipLog, error) {
 	output, err := nm.executor.Run("notes", "--ref="+AuthorshipNotesRef, "list")
 	if err != nil {
@@ -151,31 +169,65 @@ func (nm *NotesManager) ListAuthorshipLogs() (map[string]*tracker.AuthorshipLog,
 		return make(map[string]*tracker.AuthorshipLog), nil
 	}
 
-	logs := make(map[string]*tracker.AuthorshipLog)
-	lines := strings.Split(output, "\n")
-
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-
-		// Format: "noteHash commitHash"
+	// Format: "noteHash commitHash"
+	var noteBlobs, commits []string
+	for _, line := range strings.Split(output, "\n") {
 		parts := strings.Fields(line)
 		if len(parts) != 2 {
 			continue
 		}
+		noteBlobs = append(noteBlobs, parts[0])
+		commits = append(commits, parts[1])
+	}
+
+	logs := make(map[string]*tracker.AuthorshipLog, len(commits))
+	if len(noteBlobs) == 0 {
+		return logs, nil
+	}
+
+	batch, err := nm.executor.RunWithStdin(strings.Join(noteBlobs, "\n")+"\n", "cat-file", "--batch")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorship logs: %w", err)
+	}
 
-		commitHash := parts[1]
-		alog, err := nm.GetAuthorshipLog(commitHash)
-		if err != nil {
-			log.Printf("Warning: failed to get authorship log for commit %s: %v", commitHash, err)
+	for i, content := range splitCatFileBatch(batch, len(noteBlobs)) {
+		if content == nil {
+			log.Printf("Warning: failed to get authorship log for commit %s: note %s is missing", commits[i], noteBlobs[i])
 			continue
 		}
-
-		if alog != nil {
-			logs[commitHash] = alog
+		var alog tracker.AuthorshipLog
+		if err := json.Unmarshal([]byte(*content), &alog); err != nil {
+			log.Printf("Warning: failed to get authorship log for commit %s: failed to parse authorship log: %v", commits[i], err)
+			continue
 		}
+		logs[commits[i]] = &alog
 	}
 
 	return logs, nil
 }
+
+// splitCatFileBatch は git cat-file --batch の出力を要求順のオブジェクト内容に分割します（見つからないオブジェクトは nil）。
+// 形式: "<sha> <type> <size>\n<content (size bytes)>\n" または "<object> missing\n"
+func splitCatFileBatch(output string, count int) []*string {
+	contents := make([]*string, count)
+	remaining := output
+	for i := 0; i < count; i++ {
+		headerEnd := strings.Index(remaining, "\n")
+		if headerEnd == -1 {
+			break
+		}
+		fields := strings.Fields(remaining[:headerEnd])
+		remaining = remaining[headerEnd+1:]
+		if len(fields) < 3 {
+			continue
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil || size < 0 || len(remaining) < size {
+			break
+		}
+		content := remaining[:size]
+		contents[i] = &content
+		remaining = strings.TrimPrefix(remaining[size:], "\n")
+	}
+	return contents
+}
@@ -225,22 +225,23 @@ func TestListAuthorshipLogs(t *testing.T) {
 		if args[2] == "list" {
 			return mockListOutput, nil
 		}
-		if args[2] == "show" {
-			// args[3] is "--" (end of options marker), args[4] is commitHash
-			if args[4] == "commit1" {
-				return string(json1), nil
-			}
-			if args[4] == "commit2" {
-				return string(json2), nil
-			}
-		}
 		return "", fmt.Errorf("unexpected command: %v", args)
 	}
+	// ノートのblobは cat-file --batch で一括取得する
+	mockExec.RunWithStdinFunc = func(stdin string, args ...string) (string, error) {
+		if stdin != "note123\nnote456\n" {
+			return "", fmt.Errorf("unexpected stdin: %q", stdin)
+		}
+		return fmt.Sprintf("note123 blob %d\n%s\nnote456 blob %d\n%s\n", len(json1), json1, len(json2), json2), nil
+	}
 
 	logs, err := nm.ListAuthorshipLogs()
 	if err != nil {
 		t.Fatalf("ListAuthorshipLogs failed: %v", err)
 	}
+	if calls := mockExec.GetCalls("Run"); len(calls) != 1 {
+		t.Errorf("expected only 'notes list' via Run, got %d calls", len(calls))
+	}
 
 	if len(logs) != 2 {
 		t.Errorf("Expected 2 logs, got %d", len(logs))
@@ -417,3 +418,19 @@ func TestIsNoteNotFound(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitCatFileBatch(t *testing.T) {
+	output := "aaa blob 5\nhello\nbbb missing\nccc blob 0\n\nddd blob 3\nx\ny\n"
+	got := splitCatFileBatch(output, 4)
+
+	want := []string{"hello", "<nil>", "", "x\ny"}
+	for i, w := range want {
+		s := "<nil>"
+		if got[i] != nil {
+			s = *got[i]
+		}
+		if s != w {
+			t.Errorf("object %d = %q, want %q", i, s, w)
+		}
+	}
+}
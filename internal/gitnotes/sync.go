@@ -0,0 +1,132 @@
+package gitnotes
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AuthorshipNotesFullRef は git notes --ref=AuthorshipNotesRef が実際に更新するrefです
+// （git notes は refs/notes/ で始まらないrefに refs/notes/ を付けるため）。
+// push・fetch の refspec やrefの存在確認にはこちらを使います。
+const AuthorshipNotesFullRef = "refs/notes/" + AuthorshipNotesRef
+
+// AuthorshipPushRefspec はAuthorship Logをリモートの同じrefにpushする refspec です。
+const AuthorshipPushRefspec = AuthorshipNotesFullRef + ":" + AuthorshipNotesFullRef
+
+// ErrNoRemoteNotes はリモートにAuthorship Logがまだないことを示します。
+var ErrNoRemoteNotes = errors.New("no authorship logs on the remote")
+
+// ErrNoLocalNotes はローカルにAuthorship Logがまだないことを示します。
+var ErrNoLocalNotes = errors.New("no authorship logs in this repository")
+
+// syncedNotes はリモートと同期するノートのrefです。
+type syncedNotes struct {
+	ref      string // git notes --ref に渡すref
+	name     string // リモートから取得したノートを置くrefの末尾
+	strategy string // 同じコミットのノートが両方で変更されていた場合の git notes merge の戦略
+}
+
+var (
+	// Authorship LogはJSONのため内容を結合できず、ローカルを優先する
+	authorshipNotes = syncedNotes{ref: AuthorshipNotesRef, name: "authorship", strategy: "ours"}
+	// チェックポイントはJSONLのため、両方の行を重複を除いて結合する
+	checkpointNotes = syncedNotes{ref: CheckpointNotesRef, name: "checkpoints", strategy: "cat_sort_uniq"}
+)
+
+func (n syncedNotes) fullRef() string {
+	return "refs/notes/" + n.ref
+}
+
+func (n syncedNotes) remoteRef(remote string) string {
+	return "refs/notes/refs/aict/remotes/" + remote + "/" + n.name
+}
+
+func (n syncedNotes) fetchRefspec(remote string) string {
+	return "+" + n.fullRef() + ":" + n.remoteRef(remote)
+}
+
+// RemoteNotesRef はリモートから取得したAuthorship Logを置くrefです（ローカルのrefへは git notes merge で取り込む）。
+func RemoteNotesRef(remote string) string {
+	return authorshipNotes.remoteRef(remote)
+}
+
+// FetchRefspec はリモートのAuthorship Logを RemoteNotesRef に取得する refspec です。
+// ローカルのrefを直接上書きしないため、各自のコミットで作られたノートは失われません。
+func FetchRefspec(remote string) string {
+	return authorshipNotes.fetchRefspec(remote)
+}
+
+// HasLocalNotes はローカルにAuthorship Logのrefがあるかを返します。
+func (nm *NotesManager) HasLocalNotes() bool {
+	return nm.hasRef(authorshipNotes.fullRef())
+}
+
+func (nm *NotesManager) hasRef(ref string) bool {
+	_, err := nm.executor.Run("rev-parse", "--verify", "--quiet", "--end-of-options", ref)
+	return err == nil
+}
+
+// Fetch はリモートのAuthorship Logを取得し、ローカルのノートに取り込みます。
+// リモートにノートがない場合は ErrNoRemoteNotes を返します。
+func (nm *NotesManager) Fetch(remote string) error {
+	return nm.fetch(authorshipNotes, remote)
+}
+
+// FetchCheckpoints はリモートのチェックポイントのノートを取得し、ローカルのノートに取り込みます。
+// リモートにノートがない場合は ErrNoRemoteNotes を返します。
+func (nm *NotesManager) FetchCheckpoints(remote string) error {
+	return nm.fetch(checkpointNotes, remote)
+}
+
+func (nm *NotesManager) fetch(n syncedNotes, remote string) error {
+	if _, err := nm.executor.Run("fetch", "--no-tags", remote, n.fetchRefspec(remote)); err != nil {
+		if strings.Contains(err.Error(), "couldn't find remote ref") {
+			return ErrNoRemoteNotes
+		}
+		return err
+	}
+	return nm.mergeRemote(n, remote)
+}
+
+// MergeRemote は RemoteNotesRef のノートをローカルのノートに取り込みます。
+// 同じコミットのノートが両方にある場合はローカルを優先します（JSONのため内容を結合できない）。
+func (nm *NotesManager) MergeRemote(remote string) error {
+	return nm.mergeRemote(authorshipNotes, remote)
+}
+
+func (nm *NotesManager) mergeRemote(n syncedNotes, remote string) error {
+	remoteRef := n.remoteRef(remote)
+	if !nm.hasRef(n.fullRef()) {
+		if _, err := nm.executor.Run("update-ref", n.fullRef(), remoteRef); err != nil {
+			return fmt.Errorf("failed to create %s: %w", n.fullRef(), err)
+		}
+		return nil
+	}
+	if _, err := nm.executor.Run("notes", "--ref="+n.ref, "merge", "--strategy="+n.strategy, "--quiet", remoteRef); err != nil {
+		return fmt.Errorf("failed to merge %s: %w", remoteRef, err)
+	}
+	return nil
+}
+
+// Push はローカルのAuthorship Logをリモートにpushします。
+// リモートのノートが先に進んでいる場合は拒否されるため、先に Fetch で取り込んでください。
+func (nm *NotesManager) Push(remote string) error {
+	return nm.push(authorshipNotes, remote)
+}
+
+// PushCheckpoints はローカルのチェックポイントのノートをリモートにpushします。
+// ノートがない場合は ErrNoLocalNotes を返します。
+func (nm *NotesManager) PushCheckpoints(remote string) error {
+	return nm.push(checkpointNotes, remote)
+}
+
+func (nm *NotesManager) push(n syncedNotes, remote string) error {
+	if !nm.hasRef(n.fullRef()) {
+		return ErrNoLocalNotes
+	}
+	if _, err := nm.executor.Run("push", remote, n.fullRef()+":"+n.fullRef()); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Package ignore implements gitignore-style path matching for exclude_patterns and .aictignore.
+//
+// gitignore と同じ規則で判定します:
+//   - 空行と # で始まる行は無視（\# と \! でエスケープ）
+//   - ! で始まるパターンは除外の取り消し（後に書いたパターンが優先）
+//   - / で終わるパターンはディレクトリにのみ一致し、その配下のファイルをすべて除外
+//   - 先頭または途中に / を含むパターンはルートからの相対パス、含まないパターンは任意の階層の名前に一致
+//   - * ? [a-z] は / 以外に一致し、** は0個以上のディレクトリに一致（先頭の **/、途中の /**/、末尾の /**）
+//
+// gitignore と同様に、除外されたディレクトリの配下のファイルは ! で取り消せません。
+package ignore
+
+import (
+	"path"
+	"strings"
+)
+
+// FileName はリポジトリルートに置く除外設定ファイルの名前です。
+const FileName = ".aictignore"
+
+// Matcher はコンパイル済みのパターンの一覧です。ゼロ値（nil）は何も除外しません。
+type Matcher struct {
+	rules []rule
+}
+
+type rule struct {
+	segments []string // / で区切ったパターン（"**" はそのまま）
+	negate   bool
+	dirOnly  bool
+	anchored bool // ルートからの相対パスとして照合する
+}
+
+// Compile はパターンの行（.aictignore の各行や exclude_patterns の各要素）をコンパイルします。
+func Compile(lines []string) *Matcher {
+	m := &Matcher{}
+	for _, line := range lines {
+		if r, ok := parseRule(line); ok {
+			m.rules = append(m.rules, r)
+		}
+	}
+	return m
+}
+
+// ParseFile はファイルの内容を行に分割します（CRLF 対応）。
+func ParseFile(data []byte) []string {
+	return strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+}
+
+func parseRule(line string) (rule, bool) {
+	line = trimTrailingSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule{}, false
+	}
+
+	var r rule
+	switch {
+	case strings.HasPrefix(line, "!"):
+		r.negate = true
+		line = line[1:]
+	case strings.HasPrefix(line, `\!`), strings.HasPrefix(line, `\#`):
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		r.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return rule{}, false
+	}
+	r.segments = strings.Split(line, "/")
+	return r, true
+}
+
+// trimTrailingSpace は末尾の空白を取り除きます（\ でエスケープした空白は残す）。
+func trimTrailingSpace(line string) string {
+	end := len(line)
+	for end > 0 && line[end-1] == ' ' {
+		if end >= 2 && line[end-2] == '\\' {
+			break
+		}
+		end--
+	}
+	return line[:end]
+}
+
+// Match はリポジトリルートからの相対パス（/ 区切り）のファイルが除外されるかを判定します。
+// 親ディレクトリのいずれかが除外される場合も true を返します。
+func (m *Matcher) Match(filePath string) bool {
+	if m == nil || len(m.rules) == 0 {
+		return false
+	}
+	parts := strings.Split(strings.Trim(path.Clean("/"+filePath), "/"), "/")
+	for i := 1; i < len(parts); i++ {
+		if m.matchPath(parts[:i], true) {
+			return true
+		}
+	}
+	return m.matchPath(parts, false)
+}
+
+// matchPath は最後に一致したパターンで除外するかを決めます。
+func (m *Matcher) matchPath(parts []string, isDir bool) bool {
+	excluded := false
+	for _, r := range m.rules {
+		if r.negate == !excluded {
+			continue // 結果が変わらないパターンは照合しない
+		}
+		if r.matches(parts, isDir) {
+			excluded = !r.negate
+		}
+	}
+	return excluded
+}
+
+func (r rule) matches(parts []string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	if !r.anchored {
+		return matchSegment(r.segments[0], parts[len(parts)-1])
+	}
+	return matchSegments(r.segments, parts)
+}
+
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		// 末尾の ** は1つ以上、それ以外は0個以上のディレクトリに一致
+		first := 0
+		if len(pattern) == 1 {
+			first = 1
+		}
+		for i := first; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 || !matchSegment(pattern[0], parts[0]) {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
+
+func matchSegment(pattern, name string) bool {
+	ok, err := path.Match(pattern, name)
+	return err == nil && ok
+}
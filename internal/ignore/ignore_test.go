@@ -0,0 +1,58 @@
+package ignore
+
+import "testing"
+
+func TestMatcher_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		want     bool
+	}{
+		{"basename at any level", []string{"*_test.go"}, "pkg/a/foo_test.go", true},
+		{"basename no match", []string{"*_test.go"}, "pkg/foo.go", false},
+		{"directory name at any level", []string{"vendor/"}, "third_party/vendor/lib/a.go", true},
+		{"dir-only pattern does not match file", []string{"build/"}, "cmd/build", false},
+		{"anchored prefix", []string{"vendor/*"}, "vendor/lib/foo.go", true},
+		{"anchored does not match nested", []string{"vendor/*"}, "src/vendor/foo.go", false},
+		{"leading slash anchors", []string{"/gen"}, "gen/a.go", true},
+		{"leading slash not nested", []string{"/gen"}, "pkg/gen/a.go", false},
+		{"double star prefix", []string{"**/generated/*.pb.go"}, "api/v1/generated/user.pb.go", true},
+		{"double star prefix at root", []string{"**/generated/*.pb.go"}, "generated/user.pb.go", true},
+		{"double star middle", []string{"proto/**/*.pb.go"}, "proto/a/b/c.pb.go", true},
+		{"double star middle zero dirs", []string{"proto/**/*.pb.go"}, "proto/c.pb.go", true},
+		{"double star suffix", []string{"docs/**"}, "docs/a/b.md", true},
+		{"star does not cross slash", []string{"src/*.go"}, "src/a/b.go", false},
+		{"character class", []string{"*.[ch]"}, "lib/x.h", true},
+		{"negation re-includes", []string{"*.pb.go", "!keep.pb.go"}, "api/keep.pb.go", false},
+		{"later pattern wins", []string{"!keep.go", "*.go"}, "keep.go", true},
+		{"negation cannot re-include under excluded dir", []string{"vendor/", "!vendor/keep.go"}, "vendor/keep.go", true},
+		{"comments and blank lines", []string{"# *.go", "", "   "}, "main.go", false},
+		{"escaped hash", []string{`\#notes`}, "#notes", true},
+		{"trailing spaces trimmed", []string{"*.min.js   "}, "web/app.min.js", true},
+		{"exact file name", []string{"Makefile"}, "sub/Makefile", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Compile(tt.patterns).Match(tt.path); got != tt.want {
+				t.Errorf("Compile(%q).Match(%q) = %v, want %v", tt.patterns, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcher_Nil(t *testing.T) {
+	var m *Matcher
+	if m.Match("a.go") {
+		t.Error("nil Matcher should not exclude anything")
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	lines := ParseFile([]byte("vendor/\r\n*.pb.go\n"))
+	m := Compile(lines)
+	if !m.Match("vendor/a.go") || !m.Match("api/x.pb.go") || m.Match("main.go") {
+		t.Errorf("ParseFile() = %q", lines)
+	}
+}
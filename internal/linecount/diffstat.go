@@ -0,0 +1,62 @@
+package linecount
+
+// maxLCSCells はLCSテーブルの最大セル数。これを超える場合は多重集合の差分で近似します。
+const maxLCSCells = 4_000_000
+
+// DiffStat は抽出済みの行同士を比較し、追加行数と削除行数を返します。
+// 共通の先頭・末尾を除いた区間をLCSで比較するため、git diff --numstat と近い値になります。
+func DiffStat(oldLines, newLines []string) (added, deleted int) {
+	for len(oldLines) > 0 && len(newLines) > 0 && oldLines[0] == newLines[0] {
+		oldLines, newLines = oldLines[1:], newLines[1:]
+	}
+	for len(oldLines) > 0 && len(newLines) > 0 && oldLines[len(oldLines)-1] == newLines[len(newLines)-1] {
+		oldLines, newLines = oldLines[:len(oldLines)-1], newLines[:len(newLines)-1]
+	}
+	if len(oldLines) == 0 || len(newLines) == 0 {
+		return len(newLines), len(oldLines)
+	}
+
+	var common int
+	if len(oldLines)*len(newLines) <= maxLCSCells {
+		common = lcsLength(oldLines, newLines)
+	} else {
+		common = multisetCommon(oldLines, newLines)
+	}
+	return len(newLines) - common, len(oldLines) - common
+}
+
+// lcsLength は最長共通部分列の長さを2行分のテーブルで計算します。
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] >= cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// multisetCommon は行の並び順を無視して共通する行数を数えます（大きなファイル向けの近似）。
+func multisetCommon(a, b []string) int {
+	counts := make(map[string]int, len(a))
+	for _, line := range a {
+		counts[line]++
+	}
+	common := 0
+	for _, line := range b {
+		if counts[line] > 0 {
+			counts[line]--
+			common++
+		}
+	}
+	return common
+}
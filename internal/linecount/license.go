@@ -0,0 +1,119 @@
+package linecount
+
+import (
+	"bytes"
+	"strings"
+)
+
+// licenseScanBytes はライセンスヘッダーを探すファイル先頭のバイト数です。
+const licenseScanBytes = 4096
+
+// LicenseKeywords はコメントブロックをライセンスヘッダーとみなすキーワードです（小文字、大文字小文字を区別しない）。
+var LicenseKeywords = []string{
+	"copyright",
+	"spdx-license-identifier",
+	"licensed under",
+	"all rights reserved",
+	"permission is hereby granted",
+}
+
+// HasLicenseHeader はファイル先頭にライセンスヘッダーがある可能性があるかを返します（StripLicenseHeader が必要かの事前判定）。
+func HasLicenseHeader(content []byte) bool {
+	head := content
+	if len(head) > licenseScanBytes {
+		head = head[:licenseScanBytes]
+	}
+	return containsLicenseKeyword(string(bytes.ToLower(head)))
+}
+
+// StripLicenseHeader はファイル先頭のコメントブロックのうち、ライセンスキーワードを含むもの（直後の空行を含む）を取り除いた行を返します。
+// 先頭のシバン行・空行・その他のコメントブロック（ビルドタグやパッケージのドキュメント等）は残し、
+// 最初のコード行より後は対象としません。
+func StripLicenseHeader(lines []string) []string {
+	var kept []string
+	i := 0
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "#!") {
+		kept = append(kept, lines[0])
+		i = 1
+	}
+	for i < len(lines) {
+		if strings.TrimSpace(lines[i]) == "" {
+			kept = append(kept, lines[i])
+			i++
+			continue
+		}
+		end := commentBlockEnd(lines, i)
+		if end == i {
+			break // コード行
+		}
+		if containsLicenseKeyword(strings.ToLower(strings.Join(lines[i:end], "\n"))) {
+			// ヘッダー直後の区切りの空行もヘッダーの一部として除く
+			for end < len(lines) && strings.TrimSpace(lines[end]) == "" {
+				end++
+			}
+		} else {
+			kept = append(kept, lines[i:end]...)
+		}
+		i = end
+	}
+	return append(kept, lines[i:]...)
+}
+
+// commentBlockEnd は lines[start] から始まるコメントブロックの直後の位置を返します。
+// コメント行でない場合は start を返します。
+// ブロックコメント（/* */・<!-- -->）は閉じ記号まで、行コメントは同じ記号で始まる行が続く間を1ブロックとします。
+func commentBlockEnd(lines []string, start int) int {
+	first := strings.TrimSpace(lines[start])
+	for _, block := range [][2]string{{"/*", "*/"}, {"<!--", "-->"}} {
+		if !strings.HasPrefix(first, block[0]) {
+			continue
+		}
+		for i := start; i < len(lines); i++ {
+			rest := lines[i]
+			if i == start {
+				rest = first[len(block[0]):]
+			}
+			if strings.Contains(rest, block[1]) {
+				return i + 1
+			}
+		}
+		return len(lines) // 閉じられていないコメントはファイル末尾まで
+	}
+
+	leader := lineCommentLeader(first)
+	if leader == "" {
+		return start
+	}
+	end := start + 1
+	for end < len(lines) && lineCommentLeader(strings.TrimSpace(lines[end])) == leader {
+		end++
+	}
+	return end
+}
+
+// lineCommentLeader は行コメントの記号を返します。
+// "#include" や "--flag" のような記号に続けて文字が書かれた行は、"//" を除きコメントとみなしません。
+func lineCommentLeader(line string) string {
+	if strings.HasPrefix(line, "//") {
+		return "//"
+	}
+	for _, leader := range []string{"#", "--", ";", "%"} {
+		if !strings.HasPrefix(line, leader) {
+			continue
+		}
+		rest := line[len(leader):]
+		if rest == "" || rest[0] == ' ' || rest[0] == '\t' || rest[0] == leader[0] {
+			return leader
+		}
+	}
+	return ""
+}
+
+func containsLicenseKeyword(lower string) bool {
+	for _, kw := range LicenseKeywords {
+		if strings.Contains(lower, kw) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+// Package linecount provides per-extension line counters for files whose raw lines do not reflect code.
+package linecount
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Counter はファイル内容から集計対象の行を抽出します。
+// 返した行がそのまま追加・削除行数の計算に使われます。
+type Counter interface {
+	Lines(content []byte) ([]string, error)
+}
+
+var (
+	mu       sync.RWMutex
+	counters = map[string]Counter{
+		".ipynb":    NotebookCounter{},
+		".md":       MarkdownCounter{},
+		".markdown": MarkdownCounter{},
+	}
+)
+
+// Register は拡張子（".ipynb" のようにドット付き、大文字小文字は区別しない）にCounterを登録します。
+// c が nil の場合は登録を解除し、その拡張子は通常の行数カウントに戻ります。
+func Register(ext string, c Counter) {
+	mu.Lock()
+	defer mu.Unlock()
+	ext = strings.ToLower(ext)
+	if c == nil {
+		delete(counters, ext)
+		return
+	}
+	counters[ext] = c
+}
+
+// For はパスの拡張子に登録されたCounterを返します。未登録の場合は nil を返します。
+func For(path string) Counter {
+	mu.RLock()
+	defer mu.RUnlock()
+	return counters[strings.ToLower(filepath.Ext(path))]
+}
+
+// splitRawLines は改行コードを正規化して行に分割します。末尾の改行は空行として数えません。
+func splitRawLines(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
@@ -0,0 +1,270 @@
+package linecount
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		path string
+		want Counter
+	}{
+		{"analysis.ipynb", NotebookCounter{}},
+		{"docs/README.md", MarkdownCounter{}},
+		{"docs/GUIDE.MD", MarkdownCounter{}},
+		{"notes.markdown", MarkdownCounter{}},
+		{"main.go", nil},
+		{"Makefile", nil},
+	}
+	for _, tt := range tests {
+		if got := For(tt.path); got != tt.want {
+			t.Errorf("For(%q) = %#v, want %#v", tt.path, got, tt.want)
+		}
+	}
+}
+
+type fixedCounter struct{ lines []string }
+
+func (c fixedCounter) Lines([]byte) ([]string, error) { return c.lines, nil }
+
+func TestRegister(t *testing.T) {
+	Register(".Rmd", fixedCounter{lines: []string{"x"}})
+	defer Register(".rmd", nil)
+
+	if For("report.rmd") == nil {
+		t.Fatal("registered counter should be returned")
+	}
+	Register(".rmd", nil)
+	if For("report.rmd") != nil {
+		t.Error("counter should be unregistered with nil")
+	}
+}
+
+func TestNotebookCounter(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "source as array",
+			content: `{"cells": [
+				{"cell_type": "markdown", "source": ["# Title\n", "prose"]},
+				{"cell_type": "code", "source": ["import os\n", "print(1)\n"], "outputs": [{"text": ["1\n"]}]}
+			], "nbformat": 4}`,
+			want: []string{"import os", "print(1)"},
+		},
+		{
+			name:    "source as string",
+			content: `{"cells": [{"cell_type": "code", "source": "a = 1\r\nb = 2"}], "nbformat": 4}`,
+			want:    []string{"a = 1", "b = 2"},
+		},
+		{
+			name:    "empty code cell",
+			content: `{"cells": [{"cell_type": "code", "source": []}], "nbformat": 4}`,
+			want:    nil,
+		},
+		{name: "invalid json", content: `{"cells": [`, wantErr: true},
+		{name: "nbformat 3", content: `{"worksheets": [], "nbformat": 3}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NotebookCounter{}.Lines([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Lines() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownCounter(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "backtick fence",
+			content: "# Title\n\nprose\n\n```go\nfunc main() {}\n```\n\nmore prose\n",
+			want:    []string{"func main() {}"},
+		},
+		{
+			name:    "tilde fence containing backticks",
+			content: "~~~\n```\nx\n~~~\n",
+			want:    []string{"```", "x"},
+		},
+		{
+			name:    "closing fence must be at least as long",
+			content: "````\na\n```\nb\n````\n",
+			want:    []string{"a", "```", "b"},
+		},
+		{
+			name:    "unclosed fence runs to end",
+			content: "text\n```\na\nb",
+			want:    []string{"a", "b"},
+		},
+		{
+			name:    "indented code is not a fence",
+			content: "    ```\n    code\n",
+			want:    nil,
+		},
+		{
+			name:    "inline backticks are not a fence",
+			content: "```not a fence```\nprose\n",
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarkdownCounter{}.Lines([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Lines() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffStat(t *testing.T) {
+	tests := []struct {
+		name        string
+		old, new    []string
+		wantAdded   int
+		wantDeleted int
+	}{
+		{"identical", []string{"a", "b"}, []string{"a", "b"}, 0, 0},
+		{"new file", nil, []string{"a", "b"}, 2, 0},
+		{"deleted file", []string{"a", "b"}, nil, 0, 2},
+		{"insert in middle", []string{"a", "c"}, []string{"a", "b", "c"}, 1, 0},
+		{"modify line", []string{"a", "b", "c"}, []string{"a", "x", "c"}, 1, 1},
+		{"reorder", []string{"a", "b", "c"}, []string{"c", "a", "b"}, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, deleted := DiffStat(tt.old, tt.new)
+			if added != tt.wantAdded || deleted != tt.wantDeleted {
+				t.Errorf("DiffStat() = (%d, %d), want (%d, %d)", added, deleted, tt.wantAdded, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestStripMarked(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{
+			name:  "ignore block",
+			lines: []string{"a", "// aict:ignore-start vendored from lib v1.2", "v1", "v2", "// aict:ignore-end", "b"},
+			want:  []string{"a", "b"},
+		},
+		{
+			name:  "other comment syntaxes",
+			lines: []string{"# aict:ignore-start", "x", "# aict:ignore-end", "<!-- aict:ignore-start -->", "y", "<!-- aict:ignore-end -->", "/** aict:ignore-start */", "z", "/* aict:ignore-end */", "kept"},
+			want:  []string{"kept"},
+		},
+		{
+			name:  "unclosed block runs to end",
+			lines: []string{"a", "// aict:ignore-start", "b", "c"},
+			want:  []string{"a"},
+		},
+		{
+			name:  "generated line",
+			lines: []string{"x := 1", "y := lookup() // aict:generated", "z := 3"},
+			want:  []string{"x := 1", "z := 3"},
+		},
+		{
+			name:  "markers in string literals are not comments",
+			lines: []string{`start := "aict:ignore-start"`, `s := "// aict:generated-by"`, "x := 1 // see aict:ignore-start docs"},
+			want:  []string{`start := "aict:ignore-start"`, `s := "// aict:generated-by"`, "x := 1 // see aict:ignore-start docs"},
+		},
+		{
+			name:  "ignore-start after code is not a block marker",
+			lines: []string{"x := 1 // aict:ignore-start", "y := 2"},
+			want:  []string{"x := 1 // aict:ignore-start", "y := 2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StripMarked(tt.lines)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StripMarked() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripLicenseHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{
+			name:  "line comment header",
+			lines: []string{"// Copyright 2025 Example Inc.", "// SPDX-License-Identifier: MIT", "", "package main"},
+			want:  []string{"package main"},
+		},
+		{
+			name:  "block comment header",
+			lines: []string{"/*", " * Licensed under the Apache License, Version 2.0", " */", "#include <stdio.h>"},
+			want:  []string{"#include <stdio.h>"},
+		},
+		{
+			name:  "shebang and other comments are kept",
+			lines: []string{"#!/usr/bin/env python3", "# -*- coding: utf-8 -*-", "", "# Copyright (c) 2025 Example", "# All rights reserved.", "", "# Module docs", "import os"},
+			want:  []string{"#!/usr/bin/env python3", "# -*- coding: utf-8 -*-", "", "# Module docs", "import os"},
+		},
+		{
+			name:  "html comment header",
+			lines: []string{"<!-- Copyright 2025 Example -->", "<p>hi</p>"},
+			want:  []string{"<p>hi</p>"},
+		},
+		{
+			name:  "keyword after the first code line is kept",
+			lines: []string{"package main", "// Copyright notice printed by --version", "var x = 1"},
+			want:  []string{"package main", "// Copyright notice printed by --version", "var x = 1"},
+		},
+		{
+			name:  "preprocessor line is code",
+			lines: []string{"#define COPYRIGHT \"Example\"", "int x;"},
+			want:  []string{"#define COPYRIGHT \"Example\"", "int x;"},
+		},
+		{
+			name:  "no header",
+			lines: []string{"// Package foo does things.", "package foo"},
+			want:  []string{"// Package foo does things.", "package foo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StripLicenseHeader(tt.lines)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StripLicenseHeader() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasLicenseHeader(t *testing.T) {
+	if !HasLicenseHeader([]byte("// COPYRIGHT 2025\npackage main\n")) {
+		t.Error("HasLicenseHeader() should match keywords case-insensitively")
+	}
+	if HasLicenseHeader([]byte("package main\n")) {
+		t.Error("HasLicenseHeader() = true for a file without keywords")
+	}
+	late := append(bytes.Repeat([]byte("x\n"), licenseScanBytes), []byte("// Copyright\n")...)
+	if HasLicenseHeader(late) {
+		t.Error("HasLicenseHeader() should only scan the head of the file")
+	}
+}
@@ -0,0 +1,54 @@
+package linecount
+
+import "strings"
+
+// MarkdownCounter はMarkdownのフェンス付きコードブロック（``` / ~~~）内の行のみを数えます。
+// フェンス行そのものと本文は集計対象外です。閉じられていないフェンスはファイル末尾まで続くとみなします。
+type MarkdownCounter struct{}
+
+// Lines implements Counter.
+func (MarkdownCounter) Lines(content []byte) ([]string, error) {
+	var lines []string
+	var fenceChar byte
+	fenceLen := 0
+
+	for _, line := range splitRawLines(string(content)) {
+		char, n := parseFence(line)
+		if fenceLen == 0 {
+			if n > 0 {
+				fenceChar, fenceLen = char, n
+			}
+			continue
+		}
+		// 閉じフェンス: 開きと同じ文字で同じ長さ以上、後ろに情報文字列を持たない
+		if char == fenceChar && n >= fenceLen && strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), string(char))) == "" {
+			fenceLen = 0
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// parseFence は行がコードフェンス（インデント3文字以下で ``` または ~~~ が3つ以上）かを判定し、
+// フェンス文字と長さを返します。フェンスでない場合は長さ0を返します。
+func parseFence(line string) (byte, int) {
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	if indent > 3 {
+		return 0, 0
+	}
+	rest := line[indent:]
+	if rest == "" || (rest[0] != '`' && rest[0] != '~') {
+		return 0, 0
+	}
+	char := rest[0]
+	n := len(rest) - len(strings.TrimLeft(rest, string(char)))
+	if n < 3 {
+		return 0, 0
+	}
+	// バッククォートのフェンスは情報文字列にバッククォートを含められない
+	if char == '`' && strings.Contains(rest[n:], "`") {
+		return 0, 0
+	}
+	return char, n
+}
@@ -0,0 +1,70 @@
+package linecount
+
+import (
+	"bytes"
+	"strings"
+)
+
+// インラインマーカー。コメントに書いた行を集計から除外します（埋め込んだベンダーコードやライセンスヘッダー向け）。
+const (
+	// IgnoreStartMarker から IgnoreEndMarker までの行（マーカー行を含む）を除外します。
+	// 閉じられていない場合はファイル末尾まで除外します。
+	IgnoreStartMarker = "aict:ignore-start"
+	IgnoreEndMarker   = "aict:ignore-end"
+	// GeneratedMarker は行末のコメントに書いた行だけを除外します（例: `x := 42 // aict:generated`）。
+	GeneratedMarker = "aict:generated"
+)
+
+// commentLeaders はマーカーの直前に置けるコメント記号です。
+// 文字列リテラル内の "aict:ignore-start" 等をマーカーと誤認しないよう、コメント記号の直後のみを対象とします。
+var commentLeaders = []string{"//", "/*", "<!--", "#", "--", ";", "%", "*"}
+
+// HasMarkers は内容にインラインマーカーが含まれる可能性があるかを返します（StripMarked が必要かの事前判定）。
+func HasMarkers(content []byte) bool {
+	return bytes.Contains(content, []byte(IgnoreStartMarker)) || bytes.Contains(content, []byte(GeneratedMarker))
+}
+
+// StripMarked はインラインマーカーで除外された行を取り除いた行を返します。
+func StripMarked(lines []string) []string {
+	var kept []string
+	ignoring := false
+	for _, line := range lines {
+		if ignoring {
+			if isMarkerComment(line, IgnoreEndMarker, true) {
+				ignoring = false
+			}
+			continue
+		}
+		if isMarkerComment(line, IgnoreStartMarker, true) {
+			ignoring = true
+			continue
+		}
+		if isMarkerComment(line, GeneratedMarker, false) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return kept
+}
+
+// isMarkerComment は行がコメントとしてマーカーを含むかを判定します。
+// マーカーの直前がコメント記号、直後が行末・空白・コメントの閉じ記号である必要があります。
+// wholeLine が true の場合はコメントだけの行（コメント記号の前にコードがない行）に限ります。
+func isMarkerComment(line, marker string, wholeLine bool) bool {
+	idx := strings.LastIndex(line, marker)
+	if idx < 0 {
+		return false
+	}
+	after := line[idx+len(marker):]
+	if after != "" && after[0] != ' ' && after[0] != '\t' && !strings.HasPrefix(after, "*/") && !strings.HasPrefix(after, "-->") {
+		return false
+	}
+	before := strings.TrimRight(line[:idx], " \t")
+	for _, leader := range commentLeaders {
+		if strings.HasSuffix(before, leader) {
+			// "/** aict:ignore-start */" のようにコメント記号が重なる場合も、コメント記号だけならコメント行とみなす
+			return !wholeLine || strings.Trim(before, " \t/*#<!-;%") == ""
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package linecount
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// NotebookCounter はJupyter Notebook（nbformat 4）のコードセルのソース行のみを数えます。
+// 出力・メタデータ・Markdownセルは集計対象外です。
+type NotebookCounter struct{}
+
+type notebook struct {
+	Cells *[]notebookCell `json:"cells"`
+}
+
+type notebookCell struct {
+	CellType string          `json:"cell_type"`
+	Source   json.RawMessage `json:"source"`
+}
+
+// Lines implements Counter.
+func (NotebookCounter) Lines(content []byte) ([]string, error) {
+	var nb notebook
+	if err := json.Unmarshal(content, &nb); err != nil {
+		return nil, fmt.Errorf("parsing notebook: %w", err)
+	}
+	if nb.Cells == nil {
+		return nil, fmt.Errorf("parsing notebook: no cells (nbformat 4 required)")
+	}
+
+	var lines []string
+	for i, cell := range *nb.Cells {
+		if cell.CellType != "code" {
+			continue
+		}
+		source, err := cellSource(cell.Source)
+		if err != nil {
+			return nil, fmt.Errorf("parsing notebook cell %d: %w", i, err)
+		}
+		lines = append(lines, splitRawLines(source)...)
+	}
+	return lines, nil
+}
+
+// cellSource はセルの source（文字列または文字列の配列）を1つの文字列に連結します。
+func cellSource(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return "", nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	var parts []string
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return "", err
+	}
+	return strings.Join(parts, ""), nil
+}
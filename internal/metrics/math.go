@@ -0,0 +1,75 @@
+// Package metrics provides shared arithmetic helpers for line-count reports.
+package metrics
+
+import (
+	"math"
+	"sort"
+)
+
+// SafePercent は part/total を百分率で返します。total が0以下の場合は0を返します。
+func SafePercent(part, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// SplitByRatio は total を weights の比率で整数に按分します。
+// 最大剰余法で端数を配分するため、結果の合計は常に total と一致します。
+// weights の合計が0以下の場合はすべて0を返します。
+func SplitByRatio(total int, weights []int) []int {
+	parts := make([]int, len(weights))
+
+	sum := 0
+	for _, w := range weights {
+		if w > 0 {
+			sum += w
+		}
+	}
+	if sum == 0 || total == 0 {
+		return parts
+	}
+
+	type remainder struct {
+		index int
+		value int // total*w % sum（端数の大きさ）
+	}
+	remainders := make([]remainder, 0, len(weights))
+
+	assigned := 0
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		scaled := total * w
+		parts[i] = scaled / sum
+		assigned += parts[i]
+		remainders = append(remainders, remainder{index: i, value: scaled % sum})
+	}
+
+	// 端数の大きい順に1ずつ配分（同値は先頭優先で決定的に）
+	sort.SliceStable(remainders, func(a, b int) bool {
+		return remainders[a].value > remainders[b].value
+	})
+	for i := 0; i < total-assigned; i++ {
+		parts[remainders[i%len(remainders)].index]++
+	}
+
+	return parts
+}
+
+// Percentile は昇順に並んだ sorted の p パーセンタイル（0〜100）を最近傍順位法で返します。
+// sorted が空の場合は0を返します。
+func Percentile(sorted []int, p float64) int {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
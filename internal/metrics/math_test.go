@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSafePercent(t *testing.T) {
+	tests := []struct {
+		name  string
+		part  int
+		total int
+		want  float64
+	}{
+		{"通常", 30, 120, 25},
+		{"全量", 10, 10, 100},
+		{"ゼロ除算", 5, 0, 0},
+		{"負の合計", 5, -1, 0},
+		{"部分ゼロ", 0, 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SafePercent(tt.part, tt.total); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("SafePercent(%d, %d) = %v, want %v", tt.part, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitByRatio(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int
+		weights []int
+		want    []int
+	}{
+		{"割り切れる", 41, []int{30, 11}, []int{30, 11}},
+		{"端数を最大剰余で配分", 10, []int{1, 1, 1}, []int{4, 3, 3}},
+		{"端数の大きい方に配分", 10, []int{30, 11}, []int{7, 3}},
+		{"合計ゼロの重み", 10, []int{0, 0}, []int{0, 0}},
+		{"ゼロ重みを含む", 5, []int{0, 2, 3}, []int{0, 2, 3}},
+		{"total=0", 0, []int{1, 2}, []int{0, 0}},
+		{"空", 10, nil, []int{}},
+		{"単独", 7, []int{3}, []int{7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitByRatio(tt.total, tt.weights)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitByRatio(%d, %v) = %v, want %v", tt.total, tt.weights, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitByRatio_PreservesTotal(t *testing.T) {
+	weights := []int{7, 13, 1, 29, 3}
+	for total := 0; total < 200; total++ {
+		sum := 0
+		for _, p := range SplitByRatio(total, weights) {
+			sum += p
+		}
+		if sum != total {
+			t.Fatalf("SplitByRatio(%d, %v) sums to %d", total, weights, sum)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 100}
+	tests := []struct {
+		name   string
+		values []int
+		p      float64
+		want   int
+	}{
+		{"中央値", sorted, 50, 5},
+		{"p90", sorted, 90, 9},
+		{"最大", sorted, 100, 100},
+		{"p0は最小", sorted, 0, 1},
+		{"1件", []int{7}, 90, 7},
+		{"空", nil, 50, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Percentile(tt.values, tt.p); got != tt.want {
+				t.Errorf("Percentile(%v, %v) = %d, want %d", tt.values, tt.p, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package ownership
+
+import (
+	"github.com/y-hirakaw/ai-code-tracker/internal/blame"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// LineAuthor はAuthorship Logのファイル情報から、コミット時点の行番号の作成者を返します。
+// 作成者が1人のファイルはその作成者、複数の場合は行範囲に行番号を含む作成者を返します（該当なしは nil）。
+func LineAuthor(info tracker.FileInfo, line int) *tracker.AuthorInfo {
+	if len(info.Authors) == 1 {
+		return &info.Authors[0]
+	}
+	for i, a := range info.Authors {
+		for _, r := range a.Lines {
+			if (len(r) == 1 && r[0] == line) || (len(r) == 2 && r[0] <= line && line <= r[1]) {
+				return &info.Authors[i]
+			}
+		}
+	}
+	return nil
+}
+
+// LogCache はコミットごとのAuthorship Logを1回だけ読み込みます。
+// ゴルーチン間で共有しないでください（blame.Stream のコールバックは呼び出し元のゴルーチンで実行されます）。
+type LogCache struct {
+	// Debugf はAuthorship Logの読み込み失敗を報告します（nil の場合は報告しない）。
+	Debugf func(format string, args ...interface{})
+
+	nm     *gitnotes.NotesManager
+	logs   map[string]*tracker.AuthorshipLog
+	loaded bool // Preload 済み（logs にないコミットはAuthorship Logがない）
+}
+
+// NewLogCache は nm からAuthorship Logを読み込むキャッシュを作成します。
+func NewLogCache(nm *gitnotes.NotesManager) *LogCache {
+	return &LogCache{nm: nm, logs: make(map[string]*tracker.AuthorshipLog)}
+}
+
+// NewLogCacheFromLogs は読み込み済みのAuthorship Log（コミット → ログ）からキャッシュを作成します。
+// logs にないコミットはAuthorship Logがないものとして扱います。
+func NewLogCacheFromLogs(logs map[string]*tracker.AuthorshipLog) *LogCache {
+	return &LogCache{logs: logs, loaded: true}
+}
+
+// Preload はすべてのAuthorship Logを一括で読み込みます（多数のコミットを参照する場合に、コミットごとの notes show を避ける）。
+// 失敗した場合はコミットごとの読み込みを続けます。
+func (c *LogCache) Preload() {
+	logs, err := c.nm.ListAuthorshipLogs()
+	if err != nil {
+		c.debugf("preloading authorship logs: %v", err)
+		return
+	}
+	c.logs, c.loaded = logs, true
+}
+
+// Author は blame の変更元に対応する作成者を返します。
+// 未コミットの行、Authorship Logのないコミット、記録のないファイルは nil を返します。
+func (c *LogCache) Author(origin blame.Origin) *tracker.AuthorInfo {
+	if origin.Commit == "" || origin.Commit == blame.UncommittedHash {
+		return nil
+	}
+	alog := c.log(origin.Commit)
+	if alog == nil {
+		return nil
+	}
+	info, ok := alog.Files[origin.Path]
+	if !ok {
+		return nil
+	}
+	return LineAuthor(info, origin.Line)
+}
+
+// HasLog はコミットにAuthorship Logがあるかを返します。
+func (c *LogCache) HasLog(commit string) bool {
+	return c.log(commit) != nil
+}
+
+// log はコミットのAuthorship Logを返します（Preload していない場合は初回参照時に読み込む）。
+func (c *LogCache) log(commit string) *tracker.AuthorshipLog {
+	alog, ok := c.logs[commit]
+	if !ok && !c.loaded {
+		var err error
+		alog, err = c.nm.GetAuthorshipLog(commit)
+		if err != nil {
+			c.debugf("reading authorship log for %s: %v", commit, err)
+		}
+		c.logs[commit] = alog
+	}
+	return alog
+}
+
+func (c *LogCache) debugf(format string, args ...interface{}) {
+	if c.Debugf != nil {
+		c.Debugf(format, args...)
+	}
+}
@@ -0,0 +1,200 @@
+// Package ownership attributes the lines of a revision to AI or human authors
+// by combining git blame with the Authorship Logs.
+//
+// aict ownership・aict grep-ai のほか、レポートやWebビューなど現在のコードベースの帰属を扱う機能で共有します。
+package ownership
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/blame"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+const (
+	// MapVersion は OwnershipMap の出力形式のバージョン
+	MapVersion = 1
+	// TypeUnknown はAuthorship Logのないコミットの行の種別
+	TypeUnknown = "unknown"
+)
+
+// Analyzer はリビジョンのファイルを git blame し、行範囲ごとの作成者をまとめた OwnershipMap を作成します。
+type Analyzer struct {
+	// Include は対象とするファイルを判定します（nil の場合はすべてのファイル）。
+	Include func(path string) bool
+	// Workers は並列に実行する git blame の数です（0 以下の場合は blame.DefaultWorkers）。
+	Workers int
+	// Debugf はスキップしたファイルなどを報告します（nil の場合は報告しない）。
+	Debugf func(format string, args ...interface{})
+	// Since がゼロ値でない場合、それより前にコミットされた行を対象から除きます（導入前の既存コード）。
+	// 未コミットの行は常に対象です。
+	Since time.Time
+
+	executor gitexec.Executor
+	logs     *LogCache
+}
+
+// NewAnalyzer は executor で git を実行し、Authorship Logを一括で読み込む Analyzer を作成します。
+func NewAnalyzer(executor gitexec.Executor) *Analyzer {
+	return &Analyzer{
+		executor: executor,
+		logs:     NewLogCache(gitnotes.NewNotesManagerWithExecutor(executor)),
+	}
+}
+
+// Analyze は rev の対象ファイルを git blame し、行範囲ごとの作成者を組み立てます。
+// paths を指定した場合はそのパス（ディレクトリ可）配下のファイルのみを対象とします。
+func (a *Analyzer) Analyze(rev string, paths []string) (*tracker.OwnershipMap, error) {
+	commit, err := a.executor.Run("rev-parse", "--verify", "--end-of-options", rev+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("invalid revision %q: %w", rev, err)
+	}
+
+	args := []string{"ls-tree", "-r", "--name-only", commit, "--"}
+	listing, err := a.executor.Run(append(args, paths...)...)
+	if err != nil {
+		return nil, fmt.Errorf("listing files: %w", err)
+	}
+
+	m := &tracker.OwnershipMap{
+		Version:     MapVersion,
+		Commit:      commit,
+		GeneratedAt: time.Now().UTC(),
+		Files:       []tracker.FileOwnership{},
+	}
+	if !a.Since.IsZero() {
+		since := a.Since.UTC()
+		m.Since = &since
+	}
+	excluded := 0
+	a.logs.Debugf = a.Debugf
+	a.logs.Preload()
+
+	var requests []blame.Request
+	for _, path := range strings.Split(listing, "\n") {
+		if path != "" && (a.Include == nil || a.Include(path)) {
+			requests = append(requests, blame.Request{Path: path})
+		}
+	}
+
+	workers := a.Workers
+	if workers <= 0 {
+		workers = blame.DefaultWorkers()
+	}
+
+	// ファイルごとの git blame を並列に実行し、ls-tree の順に行範囲を組み立てる
+	err = blame.Stream(a.executor, commit, requests, workers, func(r blame.Result) error {
+		if r.Err != nil {
+			if a.Debugf != nil {
+				a.Debugf("ownership: skipping %s: %v", r.Path, r.Err)
+			}
+			return nil
+		}
+		excluded += a.excludeBeforeSince(r.Origins)
+		if ranges := BuildRanges(r.Origins, a.logs); len(ranges) > 0 {
+			m.Files = append(m.Files, NewFileOwnership(r.Path, ranges))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.Summary = a.summarize(m.Files)
+	m.Summary.ExcludedLines = excluded
+	return m, nil
+}
+
+// excludeBeforeSince は Since より前にコミットされた行を origins から除き、除いた行数を返します。
+func (a *Analyzer) excludeBeforeSince(origins map[int]blame.Origin) int {
+	if a.Since.IsZero() {
+		return 0
+	}
+	excluded := 0
+	for n, o := range origins {
+		if o.Commit != blame.UncommittedHash && !o.Time.IsZero() && o.Time.Before(a.Since) {
+			delete(origins, n)
+			excluded++
+		}
+	}
+	return excluded
+}
+
+// NewFileOwnership は行範囲から種別ごとの行数とAIの割合を集計したファイルの作成者情報を返します。
+func NewFileOwnership(path string, ranges []tracker.OwnershipRange) tracker.FileOwnership {
+	f := tracker.FileOwnership{Path: path, Ranges: ranges}
+	for _, r := range ranges {
+		n := r.End - r.Start + 1
+		switch r.Type {
+		case string(tracker.AuthorTypeAI):
+			f.AILines += n
+		case string(tracker.AuthorTypeHuman):
+			f.HumanLines += n
+		default:
+			f.UnknownLines += n
+		}
+	}
+	f.AIPercentage = metrics.SafePercent(f.AILines, f.AILines+f.HumanLines+f.UnknownLines)
+	return f
+}
+
+// summarize は全ファイルの行数と、行を最後に変更したコミットのうちAuthorship Logがあるものの数を集計します。
+func (a *Analyzer) summarize(files []tracker.FileOwnership) tracker.OwnershipSummary {
+	var s tracker.OwnershipSummary
+	commits := make(map[string]bool)
+	for _, f := range files {
+		s.AILines += f.AILines
+		s.HumanLines += f.HumanLines
+		s.UnknownLines += f.UnknownLines
+		for _, r := range f.Ranges {
+			if r.Commit != blame.UncommittedHash {
+				commits[r.Commit] = true
+			}
+		}
+	}
+	s.AIPercentage = metrics.SafePercent(s.AILines, s.AILines+s.HumanLines+s.UnknownLines)
+	s.Commits = len(commits)
+	for commit := range commits {
+		if a.logs.HasLog(commit) {
+			s.CommitsWithLogs++
+		}
+	}
+	return s
+}
+
+// BuildRanges は行ごとの変更元を、同じコミット・同じ作成者の連続する行範囲にまとめます。
+func BuildRanges(origins map[int]blame.Origin, logs *LogCache) []tracker.OwnershipRange {
+	lines := make([]int, 0, len(origins))
+	for n := range origins {
+		lines = append(lines, n)
+	}
+	sort.Ints(lines)
+
+	var ranges []tracker.OwnershipRange
+	for _, n := range lines {
+		origin := origins[n]
+		r := tracker.OwnershipRange{Start: n, End: n, Type: TypeUnknown, Commit: origin.Commit}
+		if a := logs.Author(origin); a != nil {
+			r.Type = string(a.Type)
+			r.Author = a.Name
+			if a.Type == tracker.AuthorTypeAI {
+				r.Tool = a.Metadata["model"]
+			}
+		}
+
+		if last := len(ranges) - 1; last >= 0 {
+			prev := &ranges[last]
+			if prev.End == n-1 && prev.Commit == r.Commit && prev.Type == r.Type && prev.Author == r.Author && prev.Tool == r.Tool {
+				prev.End = n
+				continue
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
@@ -0,0 +1,146 @@
+package ownership
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/blame"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestLineAuthor(t *testing.T) {
+	single := tracker.FileInfo{Authors: []tracker.AuthorInfo{{Name: "Claude", Type: tracker.AuthorTypeAI, Lines: [][]int{{1, 2}}}}}
+	if got := LineAuthor(single, 99); got == nil || got.Name != "Claude" {
+		t.Errorf("single author = %+v, want Claude", got)
+	}
+
+	mixed := tracker.FileInfo{Authors: []tracker.AuthorInfo{
+		{Name: "dev", Type: tracker.AuthorTypeHuman, Lines: [][]int{{1, 5}}},
+		{Name: "Claude", Type: tracker.AuthorTypeAI, Lines: [][]int{{6, 10}, {12}}},
+	}}
+	for line, want := range map[int]string{3: "dev", 8: "Claude", 12: "Claude", 11: ""} {
+		got := LineAuthor(mixed, line)
+		name := ""
+		if got != nil {
+			name = got.Name
+		}
+		if name != want {
+			t.Errorf("LineAuthor(mixed, %d) = %q, want %q", line, name, want)
+		}
+	}
+}
+
+func TestBuildRanges(t *testing.T) {
+	logs := NewLogCacheFromLogs(map[string]*tracker.AuthorshipLog{
+		"c1": {Files: map[string]tracker.FileInfo{
+			"a.go": {Authors: []tracker.AuthorInfo{{Name: "Claude Code", Type: tracker.AuthorTypeAI, Lines: [][]int{{1, 3}}, Metadata: map[string]string{"model": "sonnet"}}}},
+		}},
+		"c2": {Files: map[string]tracker.FileInfo{
+			"a.go": {Authors: []tracker.AuthorInfo{{Name: "dev", Type: tracker.AuthorTypeHuman, Lines: [][]int{{1, 1}}}}},
+		}},
+		"c3": nil, // Authorship Logなし
+	})
+
+	origins := map[int]blame.Origin{
+		1: {Commit: "c1", Path: "a.go", Line: 1},
+		2: {Commit: "c1", Path: "a.go", Line: 2},
+		3: {Commit: "c2", Path: "a.go", Line: 1},
+		4: {Commit: "c1", Path: "a.go", Line: 3},
+		5: {Commit: "c3", Path: "a.go", Line: 1},
+		6: {Commit: blame.UncommittedHash, Path: "a.go", Line: 6},
+	}
+
+	got := BuildRanges(origins, logs)
+	want := []tracker.OwnershipRange{
+		{Start: 1, End: 2, Type: "ai", Author: "Claude Code", Tool: "sonnet", Commit: "c1"},
+		{Start: 3, End: 3, Type: "human", Author: "dev", Commit: "c2"},
+		{Start: 4, End: 4, Type: "ai", Author: "Claude Code", Tool: "sonnet", Commit: "c1"},
+		{Start: 5, End: 5, Type: TypeUnknown, Commit: "c3"},
+		{Start: 6, End: 6, Type: TypeUnknown, Commit: blame.UncommittedHash},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d ranges, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("range %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNewFileOwnership(t *testing.T) {
+	f := NewFileOwnership("a.go", []tracker.OwnershipRange{
+		{Start: 1, End: 3, Type: "ai"},
+		{Start: 4, End: 4, Type: "human"},
+		{Start: 5, End: 9, Type: TypeUnknown},
+		{Start: 10, End: 11, Type: "ai"},
+	})
+	if f.AILines != 5 || f.HumanLines != 1 || f.UnknownLines != 5 {
+		t.Errorf("totals = ai %d / human %d / unknown %d, want 5/1/5", f.AILines, f.HumanLines, f.UnknownLines)
+	}
+	if f.AIPercentage < 45.4 || f.AIPercentage > 45.5 {
+		t.Errorf("AIPercentage = %.2f, want 5/11", f.AIPercentage)
+	}
+}
+
+func TestAnalyzer_Analyze(t *testing.T) {
+	commit := strings.Repeat("c", 40)
+	mock := gitexec.NewMockExecutor()
+	mock.RunFunc = func(args ...string) (string, error) {
+		switch args[0] {
+		case "rev-parse":
+			if args[len(args)-1] != "HEAD^{commit}" {
+				return "", errors.New("unknown revision")
+			}
+			return commit, nil
+		case "ls-tree":
+			return "main.go\nREADME.md\nbroken.go", nil
+		case "blame":
+			path := args[len(args)-1]
+			if path == "broken.go" {
+				return "", errors.New("no such path")
+			}
+			return fmt.Sprintf("%s 1 1 1\nfilename %s\n\tpackage main\n%s 2 2 1\nfilename %s\n\t", commit, path, commit, path), nil
+		}
+		// notes list: Authorship Logなし
+		return "", errors.New("no notes")
+	}
+
+	var skipped []string
+	a := NewAnalyzer(mock)
+	a.Include = func(path string) bool { return strings.HasSuffix(path, ".go") }
+	a.Workers = 1 // MockExecutor はゴルーチン間で共有できない
+	a.Debugf = func(format string, args ...interface{}) { skipped = append(skipped, fmt.Sprintf(format, args...)) }
+
+	m, err := a.Analyze("HEAD", nil)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if m.Commit != commit || m.Version != MapVersion {
+		t.Errorf("commit/version = %s/%d", m.Commit, m.Version)
+	}
+	if len(m.Files) != 1 || m.Files[0].Path != "main.go" {
+		t.Fatalf("files = %+v, want only main.go", m.Files)
+	}
+	want := tracker.OwnershipRange{Start: 1, End: 2, Type: TypeUnknown, Commit: commit}
+	if r := m.Files[0].Ranges; len(r) != 1 || r[0] != want {
+		t.Errorf("ranges = %+v, want [%+v]", r, want)
+	}
+	if f := m.Files[0]; f.UnknownLines != 2 || f.AILines != 0 || f.HumanLines != 0 {
+		t.Errorf("file totals = %+v, want 2 unknown lines", f)
+	}
+	wantSummary := tracker.OwnershipSummary{UnknownLines: 2, Commits: 1}
+	if m.Summary != wantSummary {
+		t.Errorf("summary = %+v, want %+v", m.Summary, wantSummary)
+	}
+	if len(skipped) != 1 || !strings.Contains(skipped[0], "broken.go") {
+		t.Errorf("debug messages = %v, want broken.go skipped", skipped)
+	}
+
+	if _, err := a.Analyze("no-such-rev", nil); err == nil {
+		t.Error("expected error for invalid revision")
+	}
+}
@@ -0,0 +1,189 @@
+// Package provenance reports how the running aict binary was built and verifies a binary
+// against published release checksums (SHA256SUMS) and their Ed25519 signature.
+//
+// ビルド情報は Go が実行ファイルに埋め込む runtime/debug.BuildInfo（VCSのコミット・変更有無、
+// モジュールのバージョンとチェックサム）から取得します。リリースは `make release` で
+// -trimpath・空の buildid・CGO_ENABLED=0 でビルドするため、同じコミットからは同じバイナリになります。
+package provenance
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// Info is the build provenance embedded in a binary
+type Info struct {
+	GoVersion   string `json:"go_version"`
+	Module      string `json:"module"`
+	Commit      string `json:"commit,omitempty"`      // vcs.revision（VCS情報なしでビルドした場合は空）
+	CommitTime  string `json:"commit_time,omitempty"` // vcs.time
+	Modified    bool   `json:"modified"`              // 未コミットの変更を含むツリーからビルドした
+	Trimpath    bool   `json:"trimpath"`              // -trimpath でビルドした（再現可能なビルドの条件）
+	CGOEnabled  string `json:"cgo_enabled,omitempty"`
+	Target      string `json:"target,omitempty"` // GOOS/GOARCH
+	ModulesHash string `json:"modules_sha256"`   // 依存モジュールのパス・バージョン・チェックサムのSHA-256
+}
+
+// Current はこのバイナリのビルド情報を返します。
+func Current() (*Info, error) {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil, errors.New("build information is not available in this binary")
+	}
+	return FromBuildInfo(bi), nil
+}
+
+// FromBuildInfo は BuildInfo からビルド情報を作成します。
+func FromBuildInfo(bi *debug.BuildInfo) *Info {
+	info := &Info{GoVersion: bi.GoVersion, Module: bi.Main.Path}
+	var goos, goarch string
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Commit = s.Value
+		case "vcs.time":
+			info.CommitTime = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		case "-trimpath":
+			info.Trimpath = s.Value == "true"
+		case "CGO_ENABLED":
+			info.CGOEnabled = s.Value
+		case "GOOS":
+			goos = s.Value
+		case "GOARCH":
+			goarch = s.Value
+		}
+	}
+	if goos != "" && goarch != "" {
+		info.Target = goos + "/" + goarch
+	}
+	info.ModulesHash = modulesHash(bi)
+	return info
+}
+
+// modulesHash はメインモジュールと依存モジュールの "path version sum" 行（パス順）のSHA-256です。
+// 依存を差し替えたビルドは異なる値になります。
+func modulesHash(bi *debug.BuildInfo) string {
+	var lines []string
+	add := func(m *debug.Module) {
+		for m.Replace != nil {
+			m = m.Replace
+		}
+		lines = append(lines, m.Path+" "+m.Version+" "+m.Sum)
+	}
+	for _, dep := range bi.Deps {
+		add(dep)
+	}
+	sort.Strings(lines)
+	main := bi.Main
+	add(&main)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// FileSHA256 はファイルのSHA-256を16進数で返します。
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ParseChecksums は sha256sum 形式（"<hex>  <name>"、バイナリモードの "*<name>" も可）の一覧を
+// ファイル名から小文字の16進数へのマップにします。
+func ParseChecksums(data []byte) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected \"<sha256>  <file>\"", n)
+		}
+		sum := strings.ToLower(fields[0])
+		if b, err := hex.DecodeString(sum); err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("line %d: invalid SHA-256 %q", n, fields[0])
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = sum
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(sums) == 0 {
+		return nil, errors.New("no checksums found")
+	}
+	return sums, nil
+}
+
+// ParsePublicKey はEd25519の公開鍵を読み込みます。
+// PEM（"PUBLIC KEY"、openssl pkey -pubout の出力）、または32バイトの鍵のBase64・16進数を受け付けます。
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing public key: %w", err)
+		}
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key is %T, want Ed25519", key)
+		}
+		return pub, nil
+	}
+	raw, err := decodeBytes(data, ed25519.PublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// VerifySignature は data のEd25519署名を検証します。
+// 署名は64バイトのバイナリ（openssl pkeyutl -sign -rawin の出力）、またはそのBase64・16進数です。
+func VerifySignature(pub ed25519.PublicKey, data, signature []byte) error {
+	sig := signature
+	if len(sig) != ed25519.SignatureSize {
+		decoded, err := decodeBytes(signature, ed25519.SignatureSize)
+		if err != nil {
+			return fmt.Errorf("parsing signature: %w", err)
+		}
+		sig = decoded
+	}
+	if !ed25519.Verify(pub, data, sig) {
+		return errors.New("signature does not match")
+	}
+	return nil
+}
+
+// decodeBytes はBase64または16進数のテキストを size バイトにデコードします。
+func decodeBytes(data []byte, size int) ([]byte, error) {
+	text := strings.TrimSpace(string(data))
+	if b, err := hex.DecodeString(text); err == nil && len(b) == size {
+		return b, nil
+	}
+	if b, err := base64.StdEncoding.DecodeString(text); err == nil && len(b) == size {
+		return b, nil
+	}
+	return nil, fmt.Errorf("expected %d bytes as base64 or hex", size)
+}
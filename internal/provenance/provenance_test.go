@@ -0,0 +1,99 @@
+package provenance
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestFromBuildInfo(t *testing.T) {
+	bi := &debug.BuildInfo{
+		GoVersion: "go1.21.0",
+		Main:      debug.Module{Path: "github.com/y-hirakaw/ai-code-tracker", Version: "(devel)"},
+		Settings: []debug.BuildSetting{
+			{Key: "-trimpath", Value: "true"},
+			{Key: "CGO_ENABLED", Value: "0"},
+			{Key: "GOOS", Value: "linux"},
+			{Key: "GOARCH", Value: "amd64"},
+			{Key: "vcs.revision", Value: "abc123"},
+			{Key: "vcs.time", Value: "2026-01-01T00:00:00Z"},
+			{Key: "vcs.modified", Value: "false"},
+		},
+	}
+	info := FromBuildInfo(bi)
+	if info.Commit != "abc123" || info.Modified || !info.Trimpath || info.Target != "linux/amd64" || info.CGOEnabled != "0" {
+		t.Errorf("FromBuildInfo() = %+v", info)
+	}
+
+	// 依存モジュールのチェックサムが変わればハッシュも変わる
+	withDep := *bi
+	withDep.Deps = []*debug.Module{{Path: "example.com/dep", Version: "v1.0.0", Sum: "h1:aaa="}}
+	changed := withDep
+	changed.Deps = []*debug.Module{{Path: "example.com/dep", Version: "v1.0.0", Sum: "h1:bbb="}}
+	if FromBuildInfo(&withDep).ModulesHash == FromBuildInfo(&changed).ModulesHash || info.ModulesHash == FromBuildInfo(&withDep).ModulesHash {
+		t.Error("ModulesHash should change with dependency checksums")
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	sums, err := ParseChecksums([]byte("# release\n" + sum + "  aict-linux-amd64\n" + strings.ToUpper(sum) + " *aict-windows-amd64.exe\n"))
+	if err != nil {
+		t.Fatalf("ParseChecksums() error = %v", err)
+	}
+	if len(sums) != 2 || sums["aict-linux-amd64"] != sum || sums["aict-windows-amd64.exe"] != sum {
+		t.Errorf("ParseChecksums() = %v", sums)
+	}
+
+	for _, bad := range []string{"", "abc  aict\n", sum + "\n"} {
+		if _, err := ParseChecksums([]byte(bad)); err == nil {
+			t.Errorf("ParseChecksums(%q) should fail", bad)
+		}
+	}
+}
+
+func TestFileSHA256(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bin")
+	os.WriteFile(path, []byte("hello"), 0644)
+	got, err := FileSHA256(path)
+	if err != nil || got != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("FileSHA256() = %s, %v", got, err)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("abc  aict\n")
+	sig := ed25519.Sign(priv, data)
+
+	der, _ := x509.MarshalPKIXPublicKey(pub)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	for _, keyData := range [][]byte{pemKey, []byte(base64.StdEncoding.EncodeToString(pub) + "\n")} {
+		key, err := ParsePublicKey(keyData)
+		if err != nil {
+			t.Fatalf("ParsePublicKey() error = %v", err)
+		}
+		for _, s := range [][]byte{sig, []byte(base64.StdEncoding.EncodeToString(sig))} {
+			if err := VerifySignature(key, data, s); err != nil {
+				t.Errorf("VerifySignature() error = %v", err)
+			}
+		}
+		if err := VerifySignature(key, []byte("tampered"), sig); err == nil {
+			t.Error("VerifySignature() should fail for modified data")
+		}
+	}
+
+	if _, err := ParsePublicKey([]byte("not a key")); err == nil {
+		t.Error("ParsePublicKey() should fail for invalid input")
+	}
+}
@@ -0,0 +1,79 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenWord   tokenKind = iota // フィールド名・キーワード・クォートなしの値
+	tokenString                  // クォートされた値
+	tokenOp                      // 比較演算子
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int // 式の先頭からのバイト位置（エラー表示用）
+}
+
+// operators は長いものから順に照合します（">=" を ">" より先に）。
+var operators = []string{"!=", "!~", ">=", "<=", "=", "~", ">", "<"}
+
+// tokenize は式をトークンに分割します。"author=claude" のように空白なしで書いた比較も分割します。
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i})
+			i++
+		case c == '"' || c == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(s) && s[j] != c; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				b.WriteByte(s[j])
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("unterminated quote at position %d", i+1)
+			}
+			tokens = append(tokens, token{tokenString, b.String(), i})
+			i = j + 1
+		default:
+			if op := matchOperator(s[i:]); op != "" {
+				tokens = append(tokens, token{tokenOp, op, i})
+				i += len(op)
+				continue
+			}
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t\n()\"'", rune(s[j])) && matchOperator(s[j:]) == "" {
+				j++
+			}
+			tokens = append(tokens, token{tokenWord, s[i:j], i})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+func matchOperator(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
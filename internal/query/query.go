@@ -0,0 +1,320 @@
+// Package query implements the filter expression language of 'aict query'.
+//
+// 式の例:
+//
+//	author=claude and branch~"feature/*" and added>100 since 30d
+//	(type=ai or author~"*bot*") and not branch=main until 2026-01-31
+//
+// 比較演算子は = != ~ !~（globマッチ）> >= < <= で、文字列の比較は大文字小文字を区別しません。
+// since / until は date フィールドの範囲指定で、直前の and を省略できます。
+package query
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldType はフィールドの値の型です。
+type FieldType int
+
+const (
+	String FieldType = iota
+	Number
+	Date
+)
+
+// Schema はフィールド名と型の対応です。
+type Schema map[string]FieldType
+
+// Record は1件のレコードです。値は String なら string、Number なら int、Date なら time.Time です。
+type Record map[string]interface{}
+
+// Expr は評価可能なフィルタ式です。
+type Expr interface {
+	Match(r Record) bool
+}
+
+// DateField は since / until が比較するフィールド名です。
+const DateField = "date"
+
+// Parse は式を解析します。相対日付（30d 等）は now を基準に日単位で解釈します。
+// 空の式はすべてのレコードにマッチします。
+func Parse(expr string, schema Schema, now time.Time) (Expr, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return matchAll{}, nil
+	}
+	p := &parser{tokens: tokens, schema: schema, now: now}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.tokens[p.pos].text, p.tokens[p.pos].offset+1)
+	}
+	return e, nil
+}
+
+type matchAll struct{}
+
+func (matchAll) Match(Record) bool { return true }
+
+type andExpr struct{ left, right Expr }
+
+func (e andExpr) Match(r Record) bool { return e.left.Match(r) && e.right.Match(r) }
+
+type orExpr struct{ left, right Expr }
+
+func (e orExpr) Match(r Record) bool { return e.left.Match(r) || e.right.Match(r) }
+
+type notExpr struct{ inner Expr }
+
+func (e notExpr) Match(r Record) bool { return !e.inner.Match(r) }
+
+// compareExpr は「フィールド 演算子 値」の比較です。
+type compareExpr struct {
+	field string
+	op    string
+	str   string    // String（小文字化済み）
+	num   int       // Number
+	date  time.Time // Date（日付のみ、UTC）
+}
+
+func (e compareExpr) Match(r Record) bool {
+	switch v := r[e.field].(type) {
+	case string:
+		v = strings.ToLower(v)
+		switch e.op {
+		case "=":
+			return v == e.str
+		case "!=":
+			return v != e.str
+		case "~":
+			ok, _ := path.Match(e.str, v)
+			return ok
+		case "!~":
+			ok, _ := path.Match(e.str, v)
+			return !ok
+		}
+	case int:
+		return compareInts(v, e.num, e.op)
+	case time.Time:
+		d := truncateDay(v)
+		return compareInts(int(d.Sub(e.date)/(24*time.Hour)), 0, e.op)
+	}
+	return false
+}
+
+func compareInts(a, b int, op string) bool {
+	switch op {
+	case "=":
+		return a == b
+	case "!=":
+		return a != b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	}
+	return false
+}
+
+// truncateDay は時刻のローカル日付をUTCの0時として返します（日付同士の比較用）。
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	schema Schema
+	now    time.Time
+}
+
+func (p *parser) peek() *token {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+// peekKeyword は次のトークンが（クォートされていない）キーワード kw かを判定します。
+func (p *parser) peekKeyword(kw string) bool {
+	t := p.peek()
+	return t != nil && t.kind == tokenWord && strings.EqualFold(t.text, kw)
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.peekKeyword("and"):
+			p.pos++
+		case p.peekKeyword("since"), p.peekKeyword("until"):
+			// "added>100 since 30d" のように since / until の前の and は省略できる
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	switch {
+	case t.kind == tokenLParen:
+		open := t.offset
+		p.pos++
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if next := p.peek(); next == nil || next.kind != tokenRParen {
+			return nil, fmt.Errorf("missing ')' for '(' at position %d", open+1)
+		}
+		p.pos++
+		return e, nil
+	case p.peekKeyword("not"):
+		p.pos++
+		e, err := p.parseUnary()
+		return notExpr{e}, err
+	case p.peekKeyword("since"), p.peekKeyword("until"):
+		p.pos++
+		op := ">="
+		if strings.EqualFold(t.text, "until") {
+			op = "<="
+		}
+		return p.parseComparison(DateField, op, strings.ToLower(t.text))
+	case t.kind == tokenWord:
+		p.pos++
+		op := p.peek()
+		if op == nil || op.kind != tokenOp {
+			return nil, fmt.Errorf("expected an operator (= != ~ !~ > >= < <=) after %q", t.text)
+		}
+		p.pos++
+		return p.parseComparison(t.text, op.text, t.text)
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.offset+1)
+}
+
+// parseComparison は値を読み、フィールドの型に合わせた比較式を作ります。
+func (p *parser) parseComparison(field, op, label string) (Expr, error) {
+	field = strings.ToLower(field)
+	ft, ok := p.schema[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q (available: %s)", field, strings.Join(p.fieldNames(), ", "))
+	}
+	v := p.peek()
+	if v == nil || (v.kind != tokenWord && v.kind != tokenString) {
+		return nil, fmt.Errorf("expected a value after %s", label)
+	}
+	p.pos++
+
+	e := compareExpr{field: field, op: op}
+	switch ft {
+	case String:
+		if op != "=" && op != "!=" && op != "~" && op != "!~" {
+			return nil, fmt.Errorf("operator %s is not supported for text field %q", op, field)
+		}
+		e.str = strings.ToLower(v.text)
+		if _, err := path.Match(e.str, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q for %s", v.text, field)
+		}
+	case Number:
+		if op == "~" || op == "!~" {
+			return nil, fmt.Errorf("operator %s is not supported for number field %q", op, field)
+		}
+		n, err := strconv.Atoi(v.text)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects a number, got %q", field, v.text)
+		}
+		e.num = n
+	case Date:
+		if op == "~" || op == "!~" {
+			return nil, fmt.Errorf("operator %s is not supported for date field %q", op, field)
+		}
+		d, err := parseDate(v.text, p.now)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", label, err)
+		}
+		e.date = d
+	}
+	return e, nil
+}
+
+func (p *parser) fieldNames() []string {
+	names := make([]string, 0, len(p.schema))
+	for name := range p.schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseDate は YYYY-MM-DD、または now からの相対日付（7d, 2w, 1m, 1y, today, yesterday）を日付に変換します。
+func parseDate(s string, now time.Time) (time.Time, error) {
+	today := truncateDay(now)
+	switch strings.ToLower(s) {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	if len(s) >= 2 {
+		if n, err := strconv.Atoi(s[:len(s)-1]); err == nil && n >= 0 {
+			switch s[len(s)-1] {
+			case 'd':
+				return today.AddDate(0, 0, -n), nil
+			case 'w':
+				return today.AddDate(0, 0, -7*n), nil
+			case 'm':
+				return today.AddDate(0, -n, 0), nil
+			case 'y':
+				return today.AddDate(-n, 0, 0), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD, 7d, 2w, 1m, 1y, today or yesterday)", s)
+}
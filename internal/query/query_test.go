@@ -0,0 +1,86 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSchema = Schema{
+	"author": String,
+	"branch": String,
+	"added":  Number,
+	"date":   Date,
+}
+
+var testNow = time.Date(2026, 3, 31, 15, 0, 0, 0, time.UTC)
+
+func TestParse_Match(t *testing.T) {
+	record := Record{
+		"author": "Claude Code",
+		"branch": "feature/login",
+		"added":  150,
+		"date":   time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"", true},
+		{`author~"claude*" and branch~"feature/*" and added>100 since 30d`, true},
+		{`author~claude* and added>100 since 7d`, false},
+		{"author=claude", false},
+		{`author="claude code"`, true},
+		{"AUTHOR!=alice", true},
+		{"added>=150 and added<=150", true},
+		{"added<100 or branch=main", false},
+		{"added<100 or branch~feature/*", true},
+		{"not branch=main", true},
+		{"not (branch=main or author~claude*)", false},
+		{"date=2026-03-10", true},
+		{"since 2026-03-11", false},
+		{"until 2026-03-10", true},
+		{"since 1m until yesterday", true},
+		{"branch!~feature/*", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := Parse(tt.expr, testSchema, testNow)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := e.Match(record); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"model=gpt", `unknown field "model"`},
+		{"added>lots", "expects a number"},
+		{"added~1*", "not supported for number field"},
+		{"author>alice", "not supported for text field"},
+		{"since last-week", "invalid date"},
+		{"author", "expected an operator"},
+		{"author=", "expected a value"},
+		{"(author=a or added>1", "missing ')'"},
+		{`author="unterminated`, "unterminated quote"},
+		{"author=a added>1", `unexpected "added"`},
+		{"author=a and", "unexpected end"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr, testSchema, testNow)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
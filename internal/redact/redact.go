@@ -0,0 +1,201 @@
+// Package redact hides secret-looking strings in file paths and messages before they are
+// written to Authorship Logs and checkpoints (Git notes are shared with the remote).
+//
+// 判定は設定の正規表現と、既定のヒューリスティックです:
+//   - パス: ファイル名に秘密情報を表す語（secret, password, credentials, api-key, private_key 等）や
+//     ランダムな文字列を含む場合、ディレクトリと拡張子を残してファイル名を伏せる
+//   - メッセージ: "password=..." 等のキーワードに続く値、既知のトークン形式（AWS・GitHub等）、
+//     英大文字・小文字・数字が混在するエントロピーの高い文字列を伏せる
+package redact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// Placeholder はメッセージ中の伏せた部分を置き換える文字列です。
+const Placeholder = "<redacted>"
+
+// 伏せた理由
+const (
+	ReasonPattern = "pattern" // 設定の redaction.patterns に一致
+	ReasonKeyword = "keyword" // 秘密情報を表す語を含む
+	ReasonToken   = "token"   // 既知のトークン形式
+	ReasonEntropy = "entropy" // エントロピーの高い文字列
+)
+
+const (
+	entropyMinLength = 20  // エントロピーを判定する最小の長さ
+	entropyThreshold = 3.5 // 1文字あたりのシャノンエントロピー（bit）の下限
+)
+
+// pathKeywords はファイル名の単語（英数字以外で区切る）のうち、単独で秘密情報を表す語です。
+// "secret" は handlers_secret.go 等のコードにも使われるため、単独では対象にしません。
+var pathKeywords = map[string]bool{
+	"secrets": true, "password": true, "passwords": true, "passwd": true,
+	"credential": true, "credentials": true, "apikey": true, "apikeys": true, "privatekey": true,
+}
+
+// pathKeywordPairs は直前の語と組み合わせて秘密情報を表す語です（api-key, private_key, client_secret 等）。
+var pathKeywordPairs = map[string]map[string]bool{
+	"key":    {"api": true, "private": true, "secret": true, "access": true, "signing": true},
+	"keys":   {"api": true, "private": true, "secret": true, "access": true, "signing": true},
+	"secret": {"client": true, "app": true, "shared": true},
+}
+
+var (
+	wordSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+
+	// keywordValue は "password=..."・"api_key: ..." 等のキーワードと値です（値のみ伏せる）。
+	keywordValue = regexp.MustCompile(`(?i)\b((?:api[_-]?key|secret|token|password|passwd|pwd|credentials?)[A-Za-z0-9_-]*\s*[:=]\s*)("[^"]+"|'[^']+'|\S{8,})`)
+
+	// knownTokens は既知のサービスのトークン形式です。
+	knownTokens = regexp.MustCompile(`\b(?:AKIA[0-9A-Z]{16}|gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,}|xox[abprs]-[A-Za-z0-9-]{10,}|glpat-[A-Za-z0-9_-]{20,})`)
+
+	// candidateToken はエントロピーを判定する文字列（Base64・URLセーフな文字の連続）です。
+	candidateToken = regexp.MustCompile(`[A-Za-z0-9+/_=-]{20,}`)
+)
+
+// Redactor はパスとメッセージを伏せます。nil の Redactor は何も伏せません。
+type Redactor struct {
+	patterns   []*regexp.Regexp
+	heuristics bool
+}
+
+// New は設定から Redactor を作成します。設定が nil の場合は nil を返します。
+func New(cfg *tracker.RedactionConfig) (*Redactor, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	r := &Redactor{heuristics: !cfg.DisableHeuristics}
+	for i, expr := range cfg.Patterns {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("patterns[%d]: %w", i, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Path はファイル名が秘密情報らしい場合に "<dir>/redacted-<hash><ext>" に置き換えたパスと理由を返します。
+// ハッシュは元のパスから求めるため、同じファイルはコミットをまたいで同じパスになります。
+// 伏せない場合は元のパスと空文字列を返します。
+func (r *Redactor) Path(p string) (string, string) {
+	if r == nil {
+		return p, ""
+	}
+	reason := ""
+	for _, re := range r.patterns {
+		if re.MatchString(p) {
+			reason = ReasonPattern
+			break
+		}
+	}
+	base := path.Base(p)
+	ext := path.Ext(base)
+	if reason == "" && r.heuristics {
+		reason = pathReason(strings.TrimSuffix(base, ext))
+	}
+	if reason == "" {
+		return p, ""
+	}
+
+	sum := sha256.Sum256([]byte(p))
+	redacted := "redacted-" + hex.EncodeToString(sum[:])[:8] + ext
+	if dir := path.Dir(p); dir != "." {
+		redacted = dir + "/" + redacted
+	}
+	return redacted, reason
+}
+
+// pathReason は拡張子を除いたファイル名を判定します。
+func pathReason(name string) string {
+	if isHighEntropy(name) {
+		return ReasonEntropy
+	}
+	words := wordSeparator.Split(strings.ToLower(name), -1)
+	for i, w := range words {
+		if pathKeywords[w] {
+			return ReasonKeyword
+		}
+		if i > 0 && pathKeywordPairs[w][words[i-1]] {
+			return ReasonKeyword
+		}
+	}
+	return ""
+}
+
+// Text はメッセージのうち秘密情報らしい部分を Placeholder に置き換えた文字列と、伏せた理由を返します。
+// 伏せない場合は元の文字列と nil を返します。
+func (r *Redactor) Text(s string) (string, []string) {
+	if r == nil || s == "" {
+		return s, nil
+	}
+	var reasons []string
+	replace := func(re *regexp.Regexp, reason string, repl func(string) string) {
+		out := re.ReplaceAllStringFunc(s, repl)
+		if out != s {
+			s = out
+			reasons = append(reasons, reason)
+		}
+	}
+
+	for _, re := range r.patterns {
+		replace(re, ReasonPattern, func(string) string { return Placeholder })
+	}
+	if r.heuristics {
+		replace(keywordValue, ReasonKeyword, func(m string) string {
+			if strings.HasSuffix(m, Placeholder) {
+				return m
+			}
+			return keywordValue.ReplaceAllString(m, "${1}"+Placeholder)
+		})
+		replace(knownTokens, ReasonToken, func(string) string { return Placeholder })
+		replace(candidateToken, ReasonEntropy, func(m string) string {
+			if isHighEntropy(m) {
+				return Placeholder
+			}
+			return m
+		})
+	}
+	return s, reasons
+}
+
+// isHighEntropy は英大文字・小文字と2文字以上の数字を含む、エントロピーの高い文字列かを判定します。
+// コミットハッシュやUUID（小文字の16進数）、通常の識別子は対象外です。
+func isHighEntropy(s string) bool {
+	if len(s) < entropyMinLength {
+		return false
+	}
+	var upper, lower bool
+	digits := 0
+	counts := make(map[rune]int)
+	for _, c := range s {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= '0' && c <= '9':
+			digits++
+		}
+		counts[c]++
+	}
+	if !upper || !lower || digits < 2 {
+		return false
+	}
+	entropy := 0.0
+	for _, n := range counts {
+		p := float64(n) / float64(len(s))
+		entropy -= p * math.Log2(p)
+	}
+	return entropy >= entropyThreshold
+}
@@ -0,0 +1,120 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// runCommand はキーチェーンのコマンドを実行します（テストで差し替え可能）。
+// 戻り値の exitCode はコマンドが起動できなかった場合 -1 です。
+var runCommand = func(stdin string, name string, args ...string) (stdout string, exitCode int, err error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var out, errOut bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return out.String(), exitErr.ExitCode(), fmt.Errorf("%s: %s", name, strings.TrimSpace(errOut.String()))
+	}
+	if err != nil {
+		return "", -1, err
+	}
+	return out.String(), 0, nil
+}
+
+// cliKeychain はコマンドラインツール（macOS の security、Linux の secret-tool）経由のキーチェーンです。
+type cliKeychain struct {
+	name     string
+	tool     string
+	getArgs  func(name string) []string
+	setArgs  func(name, value string) (args []string, stdin string, err error) // 値は ps 等から見えないよう標準入力で渡す
+	delArgs  func(name string) []string
+	notFound int // 未登録時の終了コード
+}
+
+func (k *cliKeychain) Name() string { return k.name }
+
+func (k *cliKeychain) Get(name string) (string, error) {
+	out, code, err := runCommand("", k.tool, k.getArgs(name)...)
+	if code == k.notFound {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(out, "\n"), nil
+}
+
+func (k *cliKeychain) Set(name, value string) error {
+	args, stdin, err := k.setArgs(name, value)
+	if err != nil {
+		return err
+	}
+	_, _, err = runCommand(stdin, k.tool, args...)
+	return err
+}
+
+func (k *cliKeychain) Delete(name string) error {
+	_, code, err := runCommand("", k.tool, k.delArgs(name)...)
+	if code == k.notFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// macOSKeychain は security コマンドでログインキーチェーンの汎用パスワードを読み書きします。
+// 保存時は値が引数に載らないよう、security -i の標準入力にコマンドを書き込みます。
+func macOSKeychain() *cliKeychain {
+	return &cliKeychain{
+		name: "macOS keychain",
+		tool: "security",
+		getArgs: func(name string) []string {
+			return []string{"find-generic-password", "-s", Service, "-a", name, "-w"}
+		},
+		setArgs: func(name, value string) ([]string, string, error) {
+			// security -i は1行を1コマンドとして読むため、改行を含む値は渡せない
+			if strings.ContainsAny(value, "\r\n") {
+				return nil, "", fmt.Errorf("macOS keychain: secret values cannot contain newlines")
+			}
+			// -U: 既存の項目を更新
+			command := []string{"add-generic-password", "-U", "-s", Service, "-a", name, "-w", value}
+			for i, arg := range command {
+				command[i] = securityQuote(arg)
+			}
+			return []string{"-i"}, strings.Join(command, " ") + "\n", nil
+		},
+		delArgs: func(name string) []string {
+			return []string{"delete-generic-password", "-s", Service, "-a", name}
+		},
+		notFound: 44, // errSecItemNotFound
+	}
+}
+
+// secretServiceKeychain は secret-tool（libsecret）で Secret Service（GNOME Keyring、KWallet 等）を読み書きします。
+// 値は引数ではなく標準入力で渡します。
+func secretServiceKeychain() *cliKeychain {
+	return &cliKeychain{
+		name: "Secret Service",
+		tool: "secret-tool",
+		getArgs: func(name string) []string {
+			return []string{"lookup", "service", Service, "account", name}
+		},
+		setArgs: func(name, value string) ([]string, string, error) {
+			return []string{"store", "--label", Service + " " + name, "service", Service, "account", name}, value, nil
+		},
+		delArgs: func(name string) []string {
+			return []string{"clear", "service", Service, "account", name}
+		},
+		notFound: 1,
+	}
+}
+
+// securityQuote は security -i のコマンド行の引数を二重引用符で囲みます（\ と " はバックスラッシュでエスケープ）。
+func securityQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
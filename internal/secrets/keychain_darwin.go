@@ -0,0 +1,5 @@
+package secrets
+
+func newKeychain() (Provider, error) {
+	return macOSKeychain(), nil
+}
@@ -0,0 +1,13 @@
+//go:build !darwin && !windows
+
+package secrets
+
+import "os/exec"
+
+// newKeychain は secret-tool がインストールされていれば Secret Service を使います。
+func newKeychain() (Provider, error) {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil, ErrUnavailable
+	}
+	return secretServiceKeychain(), nil
+}
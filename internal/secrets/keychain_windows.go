@@ -0,0 +1,112 @@
+package secrets
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// Windows 資格情報マネージャーの汎用資格情報（対象名 "aict:<name>"）として保存します。
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW  = advapi32.NewProc("CredReadW")
+	procCredWriteW = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+// credential は Win32 の CREDENTIALW 構造体です。
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+type windowsCredentials struct{}
+
+func newKeychain() (Provider, error) {
+	if err := advapi32.Load(); err != nil {
+		return nil, ErrUnavailable
+	}
+	return windowsCredentials{}, nil
+}
+
+func (windowsCredentials) Name() string { return "Windows Credential Manager" }
+
+func credTarget(name string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(Service + ":" + name)
+}
+
+func (windowsCredentials) Get(name string) (string, error) {
+	target, err := credTarget(name)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	ret, _, callErr := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if ret == 0 {
+		if callErr == errorNotFound {
+			return "", ErrNotFound
+		}
+		return "", callErr
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	if cred.CredentialBlobSize == 0 {
+		return "", nil
+	}
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+func (windowsCredentials) Set(name, value string) error {
+	target, err := credTarget(name)
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return err
+	}
+	blob := []byte(value)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	if ret, _, callErr := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0); ret == 0 {
+		return callErr
+	}
+	return nil
+}
+
+func (windowsCredentials) Delete(name string) error {
+	target, err := credTarget(name)
+	if err != nil {
+		return err
+	}
+	if ret, _, callErr := procCredDelete.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0); ret == 0 {
+		if callErr == errorNotFound {
+			return ErrNotFound
+		}
+		return callErr
+	}
+	return nil
+}
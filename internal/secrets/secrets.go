@@ -0,0 +1,115 @@
+// Package secrets resolves secrets referenced from config as ${secret:name}.
+//
+// 秘密情報（Webhookのトークン等）は設定ファイルに平文で書かず、名前で参照します:
+//
+//	"headers": {"Authorization": "Bearer ${secret:webhook_token}"}
+//
+// 値は OS のキーチェーン（macOS キーチェーン、Windows 資格情報マネージャー、Linux の Secret Service）から読み、
+// 見つからない場合・キーチェーンが使えない場合は環境変数 AICT_SECRET_<NAME>（例: AICT_SECRET_WEBHOOK_TOKEN）を使います。
+// AICT_SECRET_BACKEND=env でキーチェーンを使わず環境変数のみを参照します（CI 向け）。
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Service はキーチェーンに保存する項目のサービス名です。
+	Service = "aict"
+	// EnvPrefix は環境変数で秘密情報を渡す場合の接頭辞です。
+	EnvPrefix = "AICT_SECRET_"
+	// BackendEnv はバックエンドを選ぶ環境変数です（"env" でキーチェーンを使わない）。
+	BackendEnv = "AICT_SECRET_BACKEND"
+)
+
+var (
+	// ErrNotFound は秘密情報が登録されていないことを表します。
+	ErrNotFound = errors.New("secret not found")
+	// ErrUnavailable はキーチェーンが使えない環境であることを表します。
+	ErrUnavailable = errors.New("no OS keychain available")
+)
+
+// Provider は秘密情報の保存先です。
+type Provider interface {
+	Name() string
+	Get(name string) (string, error)
+	Set(name, value string) error
+	Delete(name string) error
+}
+
+// namePattern は秘密情報の名前に使える文字です（環境変数名に変換できる範囲）。
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// referencePattern は設定値の中の ${secret:name} 参照です。
+var referencePattern = regexp.MustCompile(`\$\{secret:([^}]*)\}`)
+
+// ValidateName は秘密情報の名前を検証します。
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q (use letters, digits, '_', '.', '-')", name)
+	}
+	return nil
+}
+
+// EnvName は秘密情報の名前に対応する環境変数名を返します（webhook.token → AICT_SECRET_WEBHOOK_TOKEN）。
+func EnvName(name string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(name))
+}
+
+// Keychain は現在の環境で使うキーチェーンを返します。使えない場合は ErrUnavailable を返します。
+func Keychain() (Provider, error) {
+	if os.Getenv(BackendEnv) == "env" {
+		return nil, ErrUnavailable
+	}
+	return newKeychain()
+}
+
+// Lookup は秘密情報をキーチェーン、環境変数の順に探し、値と取得元（"keychain" 等の Provider 名または環境変数名）を返します。
+func Lookup(name string) (value, source string, err error) {
+	if err := ValidateName(name); err != nil {
+		return "", "", err
+	}
+	if kc, err := Keychain(); err == nil {
+		value, err := kc.Get(name)
+		if err == nil {
+			return value, kc.Name(), nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return "", "", fmt.Errorf("reading secret %q from %s: %w", name, kc.Name(), err)
+		}
+	}
+	if value, ok := os.LookupEnv(EnvName(name)); ok {
+		return value, EnvName(name), nil
+	}
+	return "", "", fmt.Errorf("secret %q: %w (store it with 'aict secret set %s' or set %s)", name, ErrNotFound, name, EnvName(name))
+}
+
+// References は文字列に含まれる ${secret:name} の名前を出現順に返します。
+func References(s string) []string {
+	var names []string
+	for _, m := range referencePattern.FindAllStringSubmatch(s, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// Expand は文字列の ${secret:name} を秘密情報の値に置き換えます。参照がなければそのまま返します。
+func Expand(s string) (string, error) {
+	var firstErr error
+	out := referencePattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := referencePattern.FindStringSubmatch(ref)[1]
+		value, _, err := Lookup(name)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return value
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return out, nil
+}
@@ -0,0 +1,148 @@
+package secrets
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// securityArg は security -i のコマンド行の引数（二重引用符で囲んだ引数または空白区切りの語）
+var securityArg = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|\S+`)
+
+// fakeKeychain は runCommand を差し替えてキーチェーンのコマンドを記録します。
+func fakeKeychain(t *testing.T, store map[string]string, notFound int) *[][]string {
+	t.Helper()
+	var calls [][]string
+	orig := runCommand
+	t.Cleanup(func() { runCommand = orig })
+	runCommand = func(stdin, name string, args ...string) (string, int, error) {
+		calls = append(calls, append([]string{name}, args...))
+		if len(args) == 1 && args[0] == "-i" {
+			// security -i: 標準入力のコマンド行を引数に分解
+			args = nil
+			for _, arg := range securityArg.FindAllString(strings.TrimSuffix(stdin, "\n"), -1) {
+				if unquoted, err := strconv.Unquote(arg); err == nil {
+					arg = unquoted
+				}
+				args = append(args, arg)
+			}
+		}
+		account := args[len(args)-1]
+		switch args[0] {
+		case "find-generic-password":
+			account = args[4]
+			fallthrough
+		case "lookup":
+			if v, ok := store[account]; ok {
+				return v + "\n", 0, nil
+			}
+			return "", notFound, errors.New("not found")
+		case "add-generic-password":
+			store[args[5]] = args[7]
+		case "store":
+			store[account] = stdin
+		}
+		return "", 0, nil
+	}
+	return &calls
+}
+
+func TestCLIKeychain(t *testing.T) {
+	tests := []struct {
+		name     string
+		keychain *cliKeychain
+	}{
+		{"macOS", macOSKeychain()},
+		{"secret-tool", secretServiceKeychain()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := map[string]string{}
+			calls := fakeKeychain(t, store, tt.keychain.notFound)
+
+			if _, err := tt.keychain.Get("token"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get() error = %v, want ErrNotFound", err)
+			}
+			const value = `s3 "cr\3t`
+			if err := tt.keychain.Set("token", value); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			got, err := tt.keychain.Get("token")
+			if err != nil || got != value {
+				t.Errorf("Get() = %q, %v, want %q", got, err, value)
+			}
+			for _, call := range *calls {
+				if call[0] != tt.keychain.tool {
+					t.Errorf("command = %v, want %s", call, tt.keychain.tool)
+				}
+				// 値はコマンドライン引数に載せない（ps やプロセスアカウンティングから見える）
+				for _, arg := range call[1:] {
+					if strings.Contains(arg, "cr\\3t") {
+						t.Errorf("secret value passed as an argument: %v", call)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMacOSKeychain_RejectsNewlines(t *testing.T) {
+	calls := fakeKeychain(t, map[string]string{}, 44)
+	if err := macOSKeychain().Set("token", "line1\nline2"); err == nil {
+		t.Error("Set() error = nil, want an error for a multi-line value")
+	}
+	if len(*calls) != 0 {
+		t.Errorf("security was run for a rejected value: %v", *calls)
+	}
+}
+
+func TestLookup_EnvFallback(t *testing.T) {
+	t.Setenv(BackendEnv, "env")
+	t.Setenv("AICT_SECRET_WEBHOOK_TOKEN", "from-env")
+
+	value, source, err := Lookup("webhook.token")
+	if err != nil || value != "from-env" || source != "AICT_SECRET_WEBHOOK_TOKEN" {
+		t.Errorf("Lookup() = %q, %q, %v", value, source, err)
+	}
+	if _, _, err := Lookup("missing"); !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "AICT_SECRET_MISSING") {
+		t.Errorf("Lookup() error = %v, want ErrNotFound with env hint", err)
+	}
+	if _, _, err := Lookup("bad name"); err == nil || !strings.Contains(err.Error(), "invalid secret name") {
+		t.Errorf("Lookup() error = %v, want invalid name", err)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	t.Setenv(BackendEnv, "env")
+	t.Setenv("AICT_SECRET_TOKEN", "abc")
+	t.Setenv("AICT_SECRET_HOST", "example.com")
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"no references", "no references", false},
+		{"Bearer ${secret:token}", "Bearer abc", false},
+		{"https://${secret:host}/hook/${secret:token}", "https://example.com/hook/abc", false},
+		{"${secret:unknown}", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Expand(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := References("a ${secret:x} b ${secret:y.z}"); !reflect.DeepEqual(got, []string{"x", "y.z"}) {
+		t.Errorf("References() = %q", got)
+	}
+}
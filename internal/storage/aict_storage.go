@@ -6,10 +6,16 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
-	"syscall"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/y-hirakaw/ai-code-tracker/internal/events"
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/ignore"
+	"github.com/y-hirakaw/ai-code-tracker/internal/redact"
 	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
 )
 
@@ -27,6 +33,9 @@ const (
 // AIctStorage manages .git/aict/ directory
 type AIctStorage struct {
 	gitDir string // .git/aict/
+
+	checkpointStorage string                 // チェックポイントの保存先（空の場合は設定から読み込む）
+	notes             *gitnotes.NotesManager // checkpoint_storage: notes の場合のノート操作（テストで差し替え可能）
 }
 
 // NewAIctStorage creates a new AIctStorage instance
@@ -49,7 +58,11 @@ func NewAIctStorage() (*AIctStorage, error) {
 // lockCheckpointsFile はチェックポイントファイルのアドバイザリロックを取得します。
 // SaveCheckpointとrewriteCheckpointsの競合を防止。
 func (s *AIctStorage) lockCheckpointsFile() (*os.File, error) {
-	lockPath := filepath.Join(s.gitDir, CheckpointsDirName, LatestFileName+".lock")
+	return lockFile(filepath.Join(s.gitDir, CheckpointsDirName, LatestFileName+".lock"))
+}
+
+// lockFile は指定パスのロックファイルで排他的アドバイザリロックを取得します。
+func lockFile(lockPath string) (*os.File, error) {
 	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
 		return nil, fmt.Errorf("creating lock directory: %w", err)
 	}
@@ -59,7 +72,7 @@ func (s *AIctStorage) lockCheckpointsFile() (*os.File, error) {
 		return nil, fmt.Errorf("opening lock file: %w", err)
 	}
 
-	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+	if err := lockFileHandle(f); err != nil {
 		f.Close()
 		return nil, fmt.Errorf("acquiring lock: %w", err)
 	}
@@ -70,7 +83,7 @@ func (s *AIctStorage) lockCheckpointsFile() (*os.File, error) {
 // unlockCheckpointsFile はアドバイザリロックを解放します。
 func unlockCheckpointsFile(f *os.File) {
 	if f != nil {
-		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		unlockFileHandle(f)
 		f.Close()
 	}
 }
@@ -90,6 +103,11 @@ func (s *AIctStorage) SaveCheckpoint(cp *tracker.CheckpointV2) error {
 	}
 	defer unlockCheckpointsFile(lockFile)
 
+	// notes の場合は取得時のHEADコミットのノートに追記（最初のコミット前はファイルに保存）
+	if cp.BaseCommit != "" && s.usesCheckpointNotes() {
+		return s.appendCheckpointNote(cp)
+	}
+
 	checkpointsFile := filepath.Join(checkpointsDir, LatestFileName)
 
 	// 旧JSON配列形式の場合、JSONL形式にマイグレーション
@@ -104,33 +122,74 @@ func (s *AIctStorage) SaveCheckpoint(cp *tracker.CheckpointV2) error {
 	}
 	data = append(data, '\n')
 
-	// ファイルに追記（O_APPENDは小さな書き込みに対してアトミック）
-	f, err := os.OpenFile(checkpointsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	// ロック保持中に1回の書き込みで追記（hook・コマンドが同時に記録しても行が混ざらない）
+	return appendJSONLLine(checkpointsFile, data)
+}
+
+// appendJSONLLine は改行で終わる1行をJSONLファイルに追記します。
+// 中断された書き込みで末尾が改行で終わっていない場合は先に改行を補い、
+// 途中の行と新しい行が1行に連結されるのを防ぎます（途中の行は aict fsck で隔離できます）。
+// 呼び出し元がファイルのロックを保持していることが前提です。
+func appendJSONLLine(path string, line []byte) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
 
-	_, err = f.Write(data)
-	return err
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if size := info.Size(); size > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, size-1); err != nil {
+			return err
+		}
+		if last[0] != '\n' {
+			line = append([]byte{'\n'}, line...)
+		}
+	}
+
+	if _, err := f.Write(line); err != nil {
+		return err
+	}
+	return nil
 }
 
 // LoadCheckpoints loads all checkpoints from latest.json.
 // JSON配列（旧形式）とJSONL（新形式）の両方を自動判別して読み込みます。
+// checkpoint_storage が notes の場合はノートのチェックポイントも合わせて時刻順に返します。
 func (s *AIctStorage) LoadCheckpoints() ([]*tracker.CheckpointV2, error) {
-	checkpointsFile := filepath.Join(s.gitDir, CheckpointsDirName, LatestFileName)
-	return loadCheckpointsFromFile(checkpointsFile)
+	checkpoints, err := loadCheckpointsFromFile(s.checkpointsFilePath())
+	if err != nil || !s.usesCheckpointNotes() {
+		return checkpoints, err
+	}
+	inNotes, err := s.loadCheckpointNotes()
+	if err != nil {
+		return nil, err
+	}
+	checkpoints = append(checkpoints, inNotes...)
+	sortCheckpoints(checkpoints)
+	return checkpoints, nil
+}
+
+// checkpointsFilePath はチェックポイントファイル（latest.json）のパスを返します。
+func (s *AIctStorage) checkpointsFilePath() string {
+	return filepath.Join(s.gitDir, CheckpointsDirName, LatestFileName)
 }
 
 // loadCheckpointsFromFile reads checkpoints from a file, auto-detecting format.
+// 大きなファイルはメモリマップ + 行オフセット索引で読み込み、行ごとのコピーを避けます。
 func loadCheckpointsFromFile(path string) ([]*tracker.CheckpointV2, error) {
-	data, err := os.ReadFile(path)
+	data, release, err := readFileData(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return []*tracker.CheckpointV2{}, nil
 		}
 		return nil, err
 	}
+	defer release()
 
 	data = bytes.TrimSpace(data)
 	if len(data) == 0 {
@@ -146,21 +205,24 @@ func loadCheckpointsFromFile(path string) ([]*tracker.CheckpointV2, error) {
 		return checkpoints, nil
 	}
 
-	// JSONL形式: 1行1JSONオブジェクト（不正な行はスキップ）
-	var checkpoints []*tracker.CheckpointV2
-	for _, line := range bytes.Split(data, []byte{'\n'}) {
-		line = bytes.TrimSpace(line)
-		if len(line) == 0 {
-			continue
-		}
+	// json.Unmarshal は値をコピーするため、release後もチェックポイントは有効
+	return parseCheckpointsJSONL(data), nil
+}
+
+// parseCheckpointsJSONL はJSONL形式のチェックポイントを読み込みます（不正な行はスキップ）。
+// 追記途中の末尾行は snapshotJSONL で除外し、完全な行のみを索引化します。
+func parseCheckpointsJSONL(data []byte) []*tracker.CheckpointV2 {
+	offsets := indexLines(snapshotJSONL(data))
+	checkpoints := make([]*tracker.CheckpointV2, 0, len(offsets))
+	for _, off := range offsets {
 		var cp tracker.CheckpointV2
-		if err := json.Unmarshal(line, &cp); err != nil {
+		if err := json.Unmarshal(data[off[0]:off[1]], &cp); err != nil {
 			log.Printf("Warning: skipping invalid JSONL line in checkpoints: %v", err)
 			continue
 		}
 		checkpoints = append(checkpoints, &cp)
 	}
-	return checkpoints, nil
+	return checkpoints
 }
 
 // migrateToJSONLIfNeeded は旧JSON配列形式のチェックポイントファイルを
@@ -385,6 +447,17 @@ func (s *AIctStorage) rewriteCheckpoints(checkpoints []*tracker.CheckpointV2) er
 // rewriteCheckpointsLocked はロック保持済みの状態でチェックポイントを書き直します。
 // 呼び出し元がロックを保持していることが前提です。
 func (s *AIctStorage) rewriteCheckpointsLocked(checkpoints []*tracker.CheckpointV2) error {
+	if s.usesCheckpointNotes() {
+		withoutBase, withBase := splitByBaseCommit(checkpoints)
+		if err := s.writeCheckpointNotes(withBase); err != nil {
+			return err
+		}
+		if len(withoutBase) == 0 {
+			return s.removeCheckpointsFile()
+		}
+		checkpoints = withoutBase
+	}
+
 	checkpointsDir := filepath.Join(s.gitDir, CheckpointsDirName)
 	if err := os.MkdirAll(checkpointsDir, 0755); err != nil {
 		return err
@@ -412,32 +485,79 @@ func (s *AIctStorage) rewriteCheckpointsLocked(checkpoints []*tracker.Checkpoint
 
 // clearCheckpointsLocked はロック保持済みの状態でチェックポイントを削除します。
 func (s *AIctStorage) clearCheckpointsLocked() error {
-	checkpointsFile := filepath.Join(s.gitDir, CheckpointsDirName, LatestFileName)
-	err := os.Remove(checkpointsFile)
+	if s.usesCheckpointNotes() {
+		if err := s.writeCheckpointNotes(nil); err != nil {
+			return err
+		}
+	}
+	return s.removeCheckpointsFile()
+}
+
+// removeCheckpointsFile はチェックポイントファイルを削除します（ファイルがない場合は何もしない）。
+func (s *AIctStorage) removeCheckpointsFile() error {
+	err := os.Remove(s.checkpointsFilePath())
 	if os.IsNotExist(err) {
 		return nil
 	}
 	return err
 }
 
-// SaveConfig saves config.json
+// SaveConfig saves config.yaml if it exists, otherwise config.json
 func (s *AIctStorage) SaveConfig(cfg *tracker.Config) error {
-	configFile := filepath.Join(s.gitDir, ConfigFileName)
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	configFile := s.ConfigPath()
+	data, err := MarshalConfig(cfg, IsYAMLConfigPath(configFile))
 	if err != nil {
 		return err
 	}
 	return os.WriteFile(configFile, data, 0644)
 }
 
-// LoadConfig loads config.json
+// LoadConfig loads config.yaml if it exists, otherwise config.json,
+// together with the .aictignore file at the work tree root
 func (s *AIctStorage) LoadConfig() (*tracker.Config, error) {
-	configFile := filepath.Join(s.gitDir, ConfigFileName)
+	configFile := s.ConfigPath()
 	data, err := os.ReadFile(configFile)
 	if err != nil {
 		return nil, err
 	}
 
+	data, err = ConfigToJSON(data, IsYAMLConfigPath(configFile))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(configFile), err)
+	}
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.IgnorePatterns, err = LoadIgnorePatterns(); err != nil {
+		return nil, err
+	}
+	if s.checkpointStorage == "" {
+		s.checkpointStorage = cfg.CheckpointStorage
+	}
+	return cfg, nil
+}
+
+// LoadIgnorePatterns は作業ツリーのルートの .aictignore を行の一覧として読み込みます。
+// ファイルがない場合・bareリポジトリの場合は nil を返します。
+func LoadIgnorePatterns() ([]string, error) {
+	root := FindWorkTree()
+	if root == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(filepath.Join(root, ignore.FileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", ignore.FileName, err)
+	}
+	return ignore.ParseFile(data), nil
+}
+
+// ParseConfig parses and validates config JSON
+func ParseConfig(data []byte) (*tracker.Config, error) {
 	var cfg tracker.Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, err
@@ -474,6 +594,126 @@ func validateConfig(cfg *tracker.Config) error {
 		return fmt.Errorf("checkpoint_ttl_hours must be >= 0, got %d", cfg.CheckpointTTLHours)
 	}
 
+	if cfg.CheckpointStorage != "" && cfg.CheckpointStorage != CheckpointStorageFile && cfg.CheckpointStorage != CheckpointStorageNotes {
+		return fmt.Errorf("checkpoint_storage must be %q or %q, got %q", CheckpointStorageFile, CheckpointStorageNotes, cfg.CheckpointStorage)
+	}
+
+	if cfg.Baseline != nil {
+		if cfg.Baseline.IntervalDays < 0 {
+			return fmt.Errorf("baseline.interval_days must be >= 0, got %d", cfg.Baseline.IntervalDays)
+		}
+		if cfg.Baseline.Retention < 0 {
+			return fmt.Errorf("baseline.retention must be >= 0, got %d", cfg.Baseline.Retention)
+		}
+	}
+
+	if cfg.Docs != nil {
+		if cfg.Docs.TargetAIPercentage < 0 || cfg.Docs.TargetAIPercentage > 100 {
+			return fmt.Errorf("docs.target_ai_percentage must be between 0 and 100, got %.1f", cfg.Docs.TargetAIPercentage)
+		}
+		for i, ext := range cfg.Docs.Extensions {
+			if !strings.HasPrefix(ext, ".") {
+				return fmt.Errorf("docs.extensions[%d]: must start with '.', got %q", i, ext)
+			}
+		}
+	}
+
+	if cfg.Artifacts != nil {
+		for i, pattern := range cfg.Artifacts.Patterns {
+			if pattern == "" {
+				return fmt.Errorf("artifacts.patterns[%d]: must not be empty", i)
+			}
+		}
+	}
+
+	if cfg.AdoptionDate != "" {
+		if _, err := tracker.ParseDateTime(cfg.AdoptionDate); err != nil {
+			return fmt.Errorf("adoption_date must be YYYY-MM-DD or RFC3339, got %q", cfg.AdoptionDate)
+		}
+	}
+
+	for i, rule := range cfg.Overrides {
+		if _, _, err := rule.Window(); err != nil {
+			return fmt.Errorf("overrides[%d].%v", i, err)
+		}
+		if rule.Type != "" && rule.Type != tracker.AuthorTypeAI && rule.Type != tracker.AuthorTypeHuman {
+			return fmt.Errorf("overrides[%d].type must be \"ai\" or \"human\", got %q", i, rule.Type)
+		}
+		if rule.Weight != nil && *rule.Weight < 0 {
+			return fmt.Errorf("overrides[%d].weight must be >= 0, got %g", i, *rule.Weight)
+		}
+		if rule.Type == "" && rule.Weight == nil {
+			return fmt.Errorf("overrides[%d]: set type and/or weight", i)
+		}
+		if rule.Since == "" && rule.Until == "" && rule.Author == "" && rule.Branch == "" {
+			return fmt.Errorf("overrides[%d]: set at least one of since, until, author or branch", i)
+		}
+		if _, err := path.Match(rule.Author, ""); err != nil {
+			return fmt.Errorf("overrides[%d].author: invalid pattern %q", i, rule.Author)
+		}
+		if strings.HasPrefix(rule.Branch, "-") {
+			return fmt.Errorf("overrides[%d].branch must not start with '-', got %q", i, rule.Branch)
+		}
+	}
+
+	if _, err := redact.New(cfg.Redaction); err != nil {
+		return fmt.Errorf("redaction.%w", err)
+	}
+
+	if cfg.Events != nil {
+		if cfg.Events.Bus != "" && cfg.Events.Bus != "nats" && cfg.Events.Bus != "redis" {
+			return fmt.Errorf("events.bus must be \"nats\" or \"redis\", got %q", cfg.Events.Bus)
+		}
+		if strings.ContainsAny(cfg.Events.Subject, " \t\r\n") {
+			return fmt.Errorf("events.subject must not contain whitespace, got %q", cfg.Events.Subject)
+		}
+		if cfg.Events.Bus == "" && len(cfg.Events.Webhooks) == 0 {
+			return fmt.Errorf("events: set bus or at least one webhook")
+		}
+		for i, w := range cfg.Events.Webhooks {
+			if err := events.ValidateWebhook(w); err != nil {
+				return fmt.Errorf("events.webhooks[%d]: %w", i, err)
+			}
+		}
+	}
+
+	if cfg.Badge != nil {
+		for i, th := range cfg.Badge.Thresholds {
+			if th.Min < 0 || th.Min > 100 {
+				return fmt.Errorf("badge.thresholds[%d]: min must be between 0 and 100, got %.1f", i, th.Min)
+			}
+			if th.Color == "" {
+				return fmt.Errorf("badge.thresholds[%d]: color must not be empty", i)
+			}
+		}
+	}
+
+	for i, p := range cfg.CommitPatterns {
+		if p.Name == "" {
+			return fmt.Errorf("commit_patterns[%d]: name must not be empty", i)
+		}
+		if p.AuthorPattern == "" && p.MessagePattern == "" {
+			return fmt.Errorf("commit_patterns[%d]: author_pattern or message_pattern is required", i)
+		}
+		for field, expr := range map[string]string{"author_pattern": p.AuthorPattern, "message_pattern": p.MessagePattern} {
+			if _, err := regexp.Compile(expr); err != nil {
+				return fmt.Errorf("commit_patterns[%d]: invalid %s: %w", i, field, err)
+			}
+		}
+	}
+
+	for name, ctx := range cfg.Contexts {
+		if name == "" {
+			return fmt.Errorf("contexts: name must not be empty")
+		}
+		if len(ctx.Paths) == 0 {
+			return fmt.Errorf("contexts.%s: paths must not be empty", name)
+		}
+		if ctx.TargetAIPercentage < 0 || ctx.TargetAIPercentage > 100 {
+			return fmt.Errorf("contexts.%s: target_ai_percentage must be between 0 and 100, got %.1f", name, ctx.TargetAIPercentage)
+		}
+	}
+
 	return nil
 }
 
@@ -482,7 +722,9 @@ func (s *AIctStorage) GetAictDir() string {
 	return s.gitDir
 }
 
-// findGitDir finds .git directory from current directory
+// findGitDir finds .git directory from current directory.
+// 通常の .git ディレクトリに加え、worktree（.git がファイル）とbareリポジトリにも対応します。
+// worktreeの場合は全worktreeで共有される共通gitディレクトリを返します。
 func findGitDir() (string, error) {
 	dir, err := os.Getwd()
 	if err != nil {
@@ -491,8 +733,16 @@ func findGitDir() (string, error) {
 
 	for {
 		gitDir := filepath.Join(dir, ".git")
-		if info, err := os.Stat(gitDir); err == nil && info.IsDir() {
-			return gitDir, nil
+		if info, err := os.Stat(gitDir); err == nil {
+			if info.IsDir() {
+				return gitDir, nil
+			}
+			// worktree / submodule: .git は "gitdir: <path>" を含むファイル
+			return resolveGitFile(gitDir)
+		}
+
+		if isBareGitDir(dir) {
+			return dir, nil
 		}
 
 		parent := filepath.Dir(dir)
@@ -502,3 +752,65 @@ func findGitDir() (string, error) {
 		dir = parent
 	}
 }
+
+// FindWorkTree は現在のディレクトリから .git（ディレクトリまたはファイル）を持つ作業ツリーのルートを探します。
+// worktree ではそのworktreeのルートを返し、見つからない場合（bareリポジトリ等）は空文字列を返します。
+func FindWorkTree() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// resolveGitFile は "gitdir: <path>" 形式の .git ファイルを解決します。
+// 参照先に commondir がある場合（git worktree）は共通gitディレクトリを返します。
+func resolveGitFile(gitFile string) (string, error) {
+	data, err := os.ReadFile(gitFile)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", gitFile, err)
+	}
+
+	content := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(content, "gitdir:") {
+		return "", fmt.Errorf("invalid .git file: %s", gitFile)
+	}
+
+	gitDir := strings.TrimSpace(strings.TrimPrefix(content, "gitdir:"))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(gitFile), gitDir)
+	}
+
+	commonDir, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir, nil // submodule等: commondirなし
+	}
+
+	common := strings.TrimSpace(string(commonDir))
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(gitDir, common)
+	}
+	return filepath.Clean(common), nil
+}
+
+// isBareGitDir はディレクトリがbareリポジトリ（HEAD, objects/, refs/ を直下に持つ）か判定します。
+func isBareGitDir(dir string) bool {
+	if info, err := os.Stat(filepath.Join(dir, "HEAD")); err != nil || info.IsDir() {
+		return false
+	}
+	for _, sub := range []string{"objects", "refs"} {
+		if info, err := os.Stat(filepath.Join(dir, sub)); err != nil || !info.IsDir() {
+			return false
+		}
+	}
+	return true
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -366,6 +367,7 @@ func TestSaveCheckpointJSONLFormat(t *testing.T) {
 }
 
 func TestValidateConfig(t *testing.T) {
+	negativeWeight := -0.5
 	tests := []struct {
 		name    string
 		cfg     *tracker.Config
@@ -439,6 +441,249 @@ func TestValidateConfig(t *testing.T) {
 			wantErr: true,
 			errMsg:  "default_author",
 		},
+		{
+			name: "valid contexts",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				Contexts: map[string]tracker.ContextConfig{
+					"frontend": {Paths: []string{"frontend"}, TargetAIPercentage: 60},
+					"backend":  {Paths: []string{"backend", "api"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "context without paths",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				Contexts:           map[string]tracker.ContextConfig{"frontend": {}},
+			},
+			wantErr: true,
+			errMsg:  "contexts.frontend: paths",
+		},
+		{
+			name: "context target out of range",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				Contexts: map[string]tracker.ContextConfig{
+					"backend": {Paths: []string{"backend"}, TargetAIPercentage: 120},
+				},
+			},
+			wantErr: true,
+			errMsg:  "contexts.backend: target_ai_percentage",
+		},
+		{
+			name: "docs target out of range",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go", ".md"},
+				DefaultAuthor:      "dev",
+				Docs:               &tracker.DocsConfig{TargetAIPercentage: -1},
+			},
+			wantErr: true,
+			errMsg:  "docs.target_ai_percentage",
+		},
+		{
+			name: "docs extension without dot",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go", ".md"},
+				DefaultAuthor:      "dev",
+				Docs:               &tracker.DocsConfig{Extensions: []string{"md"}},
+			},
+			wantErr: true,
+			errMsg:  "docs.extensions[0]",
+		},
+		{
+			name: "empty artifacts pattern",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				Artifacts:          &tracker.ArtifactsConfig{Patterns: []string{"*.sql", ""}},
+			},
+			wantErr: true,
+			errMsg:  "artifacts.patterns[1]",
+		},
+		{
+			name: "unknown events bus",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				Events:             &tracker.EventsConfig{Bus: "kafka"},
+			},
+			wantErr: true,
+			errMsg:  "events.bus",
+		},
+		{
+			name: "events subject with whitespace",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				Events:             &tracker.EventsConfig{Bus: "nats", Subject: "aict checkpoint"},
+			},
+			wantErr: true,
+			errMsg:  "events.subject",
+		},
+		{
+			name: "events without bus or webhooks",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				Events:             &tracker.EventsConfig{},
+			},
+			wantErr: true,
+			errMsg:  "set bus or at least one webhook",
+		},
+		{
+			name: "invalid webhook url",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				Events:             &tracker.EventsConfig{Webhooks: []tracker.WebhookConfig{{URL: "ftp://example.com"}}},
+			},
+			wantErr: true,
+			errMsg:  "events.webhooks[0]",
+		},
+		{
+			name: "valid commit pattern",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				CommitPatterns:     []tracker.CommitPattern{{Name: "Aider", AuthorPattern: `\(aider\)$`}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "commit pattern without patterns",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				CommitPatterns:     []tracker.CommitPattern{{Name: "Aider"}},
+			},
+			wantErr: true,
+			errMsg:  "commit_patterns[0]: author_pattern or message_pattern",
+		},
+		{
+			name: "commit pattern with invalid regex",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				CommitPatterns:     []tracker.CommitPattern{{Name: "Aider", MessagePattern: "("}},
+			},
+			wantErr: true,
+			errMsg:  "commit_patterns[0]: invalid message_pattern",
+		},
+		{
+			name: "valid override",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				Overrides:          []tracker.OverrideRule{{Name: "migration week", Since: "2025-03-03", Until: "2025-03-07", Author: "contractor-*", Type: tracker.AuthorTypeAI}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "checkpoint storage notes",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				CheckpointStorage:  "notes",
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown checkpoint storage",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				CheckpointStorage:  "s3",
+			},
+			wantErr: true,
+			errMsg:  "checkpoint_storage",
+		},
+		{
+			name: "override with invalid date",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				Overrides:          []tracker.OverrideRule{{Since: "03/03/2025", Type: tracker.AuthorTypeAI}},
+			},
+			wantErr: true,
+			errMsg:  "overrides[0].since",
+		},
+		{
+			name: "invalid adoption date",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				AdoptionDate:       "2025/03/01",
+			},
+			wantErr: true,
+			errMsg:  "adoption_date",
+		},
+		{
+			name: "override with unknown type",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				Overrides:          []tracker.OverrideRule{{Author: "bob", Type: "bot"}},
+			},
+			wantErr: true,
+			errMsg:  "overrides[0].type",
+		},
+		{
+			name: "override with negative weight",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				Overrides:          []tracker.OverrideRule{{Author: "bob", Weight: &negativeWeight}},
+			},
+			wantErr: true,
+			errMsg:  "overrides[0].weight",
+		},
+		{
+			name: "override without type or weight",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				Overrides:          []tracker.OverrideRule{{Author: "bob"}},
+			},
+			wantErr: true,
+			errMsg:  "overrides[0]: set type and/or weight",
+		},
+		{
+			name: "override without selector",
+			cfg: &tracker.Config{
+				TargetAIPercentage: 80,
+				TrackedExtensions:  []string{".go"},
+				DefaultAuthor:      "dev",
+				Overrides:          []tracker.OverrideRule{{Type: tracker.AuthorTypeAI}},
+			},
+			wantErr: true,
+			errMsg:  "overrides[0]: set at least one",
+		},
 	}
 
 	for _, tt := range tests {
@@ -888,6 +1133,79 @@ func TestLockCheckpointsFile_BasicLockUnlock(t *testing.T) {
 	unlockCheckpointsFile(lockFile)
 }
 
+func TestSaveCheckpoint_ConcurrentWriters(t *testing.T) {
+	store, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	// hook・コマンドが別プロセスで同時に記録する状況を、別々のストレージ（別のロックファイル記述子）で再現
+	// PIPE_BUFを超える行でも途中で他の行が混ざらないことを確認する
+	const writers, perWriter = 8, 10
+	metadata := map[string]string{"note": strings.Repeat("x", 8192)}
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*perWriter)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			s := &AIctStorage{gitDir: store.gitDir}
+			for i := 0; i < perWriter; i++ {
+				errs <- s.SaveCheckpoint(&tracker.CheckpointV2{
+					Timestamp: time.Now(),
+					Author:    fmt.Sprintf("writer-%d", w),
+					Type:      tracker.AuthorTypeAI,
+					Metadata:  metadata,
+					Changes:   map[string]tracker.Change{},
+				})
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("SaveCheckpoint() error = %v", err)
+		}
+	}
+
+	checks, err := store.CheckRecordFiles()
+	if err != nil {
+		t.Fatalf("CheckRecordFiles() error = %v", err)
+	}
+	for _, c := range checks {
+		if c.Name == LatestFileName && (c.Records != writers*perWriter || len(c.Corrupt) != 0) {
+			t.Errorf("%s: records = %d, corrupt = %d, want %d and 0", c.Name, c.Records, len(c.Corrupt), writers*perWriter)
+		}
+	}
+}
+
+func TestSaveCheckpoint_TerminatesTornTail(t *testing.T) {
+	store, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	if err := store.SaveCheckpoint(&tracker.CheckpointV2{Author: "first", Type: tracker.AuthorTypeHuman}); err != nil {
+		t.Fatal(err)
+	}
+	// 中断された書き込みで改行のない途中の行が残った状態
+	path := store.checkpointsFilePath()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"author":"torn`)
+	f.Close()
+
+	if err := store.SaveCheckpoint(&tracker.CheckpointV2{Author: "second", Type: tracker.AuthorTypeAI}); err != nil {
+		t.Fatal(err)
+	}
+	checkpoints, err := store.LoadCheckpoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checkpoints) != 2 || checkpoints[0].Author != "first" || checkpoints[1].Author != "second" {
+		t.Errorf("checkpoints = %+v, want first and second with the torn line isolated", checkpoints)
+	}
+}
+
 func TestUnlockCheckpointsFile_Nil(t *testing.T) {
 	// nil引数でパニックしないことを確認
 	unlockCheckpointsFile(nil)
@@ -984,3 +1302,70 @@ func TestPurgeExpiredCheckpoints_CustomTTL_Long(t *testing.T) {
 		t.Fatalf("expected 1 remaining, got %d", len(remaining))
 	}
 }
+
+func TestFindGitDir_Worktree(t *testing.T) {
+	tmpDir := t.TempDir()
+	mainGit := filepath.Join(tmpDir, "main", ".git")
+	worktreeGit := filepath.Join(mainGit, "worktrees", "wt")
+	if err := os.MkdirAll(worktreeGit, 0755); err != nil {
+		t.Fatalf("Failed to create worktree git dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreeGit, "commondir"), []byte("../..\n"), 0644); err != nil {
+		t.Fatalf("Failed to write commondir: %v", err)
+	}
+
+	wtDir := filepath.Join(tmpDir, "wt")
+	if err := os.MkdirAll(filepath.Join(wtDir, "sub"), 0755); err != nil {
+		t.Fatalf("Failed to create worktree dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wtDir, ".git"), []byte("gitdir: "+worktreeGit+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .git file: %v", err)
+	}
+
+	oldDir, _ := os.Getwd()
+	defer os.Chdir(oldDir)
+	os.Chdir(filepath.Join(wtDir, "sub"))
+
+	got, err := findGitDir()
+	if err != nil {
+		t.Fatalf("findGitDir() error = %v", err)
+	}
+	if got != mainGit {
+		t.Errorf("findGitDir() = %q, want %q", got, mainGit)
+	}
+}
+
+func TestFindGitDir_BareRepository(t *testing.T) {
+	bareDir := filepath.Join(t.TempDir(), "repo.git")
+	for _, sub := range []string{"objects", "refs"} {
+		if err := os.MkdirAll(filepath.Join(bareDir, sub), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", sub, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(bareDir, "HEAD"), []byte("ref: refs/heads/main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write HEAD: %v", err)
+	}
+
+	oldDir, _ := os.Getwd()
+	defer os.Chdir(oldDir)
+	os.Chdir(bareDir)
+
+	got, err := findGitDir()
+	if err != nil {
+		t.Fatalf("findGitDir() error = %v", err)
+	}
+	if got != bareDir {
+		t.Errorf("findGitDir() = %q, want %q", got, bareDir)
+	}
+}
+
+func TestResolveGitFile_Invalid(t *testing.T) {
+	gitFile := filepath.Join(t.TempDir(), ".git")
+	if err := os.WriteFile(gitFile, []byte("not a gitdir"), 0644); err != nil {
+		t.Fatalf("Failed to write .git file: %v", err)
+	}
+
+	if _, err := resolveGitFile(gitFile); err == nil {
+		t.Error("expected error for invalid .git file")
+	}
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// BaselinesFileName はベースラインのファイル名（.git/aict/ 直下、JSONL、古い順）
+const BaselinesFileName = "baselines.jsonl"
+
+// LoadBaselines はベースラインを古い順に読み込みます。
+// ファイルが存在しない場合は空スライスを返します。
+func (s *AIctStorage) LoadBaselines() ([]*tracker.Baseline, error) {
+	return loadBaselinesFromFile(filepath.Join(s.gitDir, BaselinesFileName))
+}
+
+// AddBaseline はベースラインを追加し、新しい順に keep 件を超えた古いものを削除します。
+// 削除した件数を返します。keep が0以下の場合は削除しません。
+func (s *AIctStorage) AddBaseline(b *tracker.Baseline, keep int) (int, error) {
+	baselinesFile := filepath.Join(s.gitDir, BaselinesFileName)
+
+	lock, err := lockFile(baselinesFile + ".lock")
+	if err != nil {
+		return 0, fmt.Errorf("acquiring baseline lock: %w", err)
+	}
+	defer unlockCheckpointsFile(lock)
+
+	baselines, err := loadBaselinesFromFile(baselinesFile)
+	if err != nil {
+		return 0, err
+	}
+	baselines = append(baselines, b)
+
+	pruned := 0
+	if keep > 0 && len(baselines) > keep {
+		pruned = len(baselines) - keep
+		baselines = baselines[pruned:]
+	}
+
+	var buf bytes.Buffer
+	for _, b := range baselines {
+		line, err := json.Marshal(b)
+		if err != nil {
+			return 0, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	tmpFile := baselinesFile + ".tmp"
+	if err := os.WriteFile(tmpFile, buf.Bytes(), 0644); err != nil {
+		return 0, fmt.Errorf("write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, baselinesFile); err != nil {
+		os.Remove(tmpFile)
+		return 0, fmt.Errorf("rename temp file: %w", err)
+	}
+	return pruned, nil
+}
+
+func loadBaselinesFromFile(path string) ([]*tracker.Baseline, error) {
+	records, _, err := readJSONLFile[tracker.Baseline](path)
+	return records, err
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestAddBaseline_Retention(t *testing.T) {
+	s, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	baselines, err := s.LoadBaselines()
+	if err != nil || len(baselines) != 0 {
+		t.Fatalf("LoadBaselines() on empty = %v, %v", baselines, err)
+	}
+
+	tests := []struct {
+		commit     string
+		keep       int
+		wantPruned int
+		wantFirst  string
+	}{
+		{"c1", 2, 0, "c1"},
+		{"c2", 2, 0, "c1"},
+		{"c3", 2, 1, "c2"},
+		{"c4", 0, 0, "c2"}, // keep=0 は削除しない
+		{"c5", 1, 3, "c5"},
+	}
+
+	for _, tt := range tests {
+		pruned, err := s.AddBaseline(&tracker.Baseline{CreatedAt: time.Now(), Commit: tt.commit}, tt.keep)
+		if err != nil {
+			t.Fatalf("AddBaseline(%s) error = %v", tt.commit, err)
+		}
+		if pruned != tt.wantPruned {
+			t.Errorf("AddBaseline(%s) pruned = %d, want %d", tt.commit, pruned, tt.wantPruned)
+		}
+		baselines, err := s.LoadBaselines()
+		if err != nil {
+			t.Fatalf("LoadBaselines() error = %v", err)
+		}
+		if baselines[0].Commit != tt.wantFirst || baselines[len(baselines)-1].Commit != tt.commit {
+			t.Errorf("after %s: oldest = %s, newest = %s", tt.commit, baselines[0].Commit, baselines[len(baselines)-1].Commit)
+		}
+	}
+}
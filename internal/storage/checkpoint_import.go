@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// ReadCheckpointsFile は他のマシン・ブランチからコピーしたチェックポイントファイルを読み込みます。
+// latest.json と同様に JSON配列（旧形式）とJSONLの両方を読み込めます。
+func ReadCheckpointsFile(path string) ([]*tracker.CheckpointV2, error) {
+	return loadCheckpointsFromFile(path)
+}
+
+// checkpointKey はチェックポイントの重複判定に使うキー（時刻・作成者・取得時のHEADコミット）です。
+type checkpointKey struct {
+	timestamp  time.Time
+	author     string
+	baseCommit string
+}
+
+func keyOf(cp *tracker.CheckpointV2) checkpointKey {
+	// 読み込み元によってタイムゾーンの表現が異なるため、UTCに揃えて比較する
+	return checkpointKey{timestamp: cp.Timestamp.UTC(), author: cp.Author, baseCommit: cp.BaseCommit}
+}
+
+// ImportCheckpoints は保存済みのチェックポイントに checkpoints を追加し、追加した件数を返します。
+// 保存済み・checkpoints 内で時刻・作成者・BaseCommitが同じものは重複として追加しません。
+// Load→Merge→Rewrite全体をロック保護します。
+func (s *AIctStorage) ImportCheckpoints(checkpoints []*tracker.CheckpointV2) (int, error) {
+	lockFile, err := s.lockCheckpointsFile()
+	if err != nil {
+		return 0, fmt.Errorf("acquiring checkpoint lock: %w", err)
+	}
+	defer unlockCheckpointsFile(lockFile)
+
+	existing, err := s.LoadCheckpoints()
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[checkpointKey]bool, len(existing)+len(checkpoints))
+	for _, cp := range existing {
+		seen[keyOf(cp)] = true
+	}
+
+	merged := existing
+	added := 0
+	for _, cp := range checkpoints {
+		key := keyOf(cp)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, cp)
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+
+	sortCheckpoints(merged)
+	return added, s.rewriteCheckpointsLocked(merged)
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestImportCheckpoints(t *testing.T) {
+	store, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	base := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	jst := time.FixedZone("JST", 9*60*60)
+	cp := func(author, baseCommit string, ts time.Time) *tracker.CheckpointV2 {
+		return &tracker.CheckpointV2{Author: author, BaseCommit: baseCommit, Timestamp: ts, Type: tracker.AuthorTypeAI}
+	}
+
+	if err := store.SaveCheckpoint(cp("claude", "abc", base)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		incoming  []*tracker.CheckpointV2
+		wantAdded int
+		wantTotal int
+	}{
+		{"same checkpoint in another time zone", []*tracker.CheckpointV2{cp("claude", "abc", base.In(jst))}, 0, 1},
+		{"different author", []*tracker.CheckpointV2{cp("cursor", "abc", base)}, 1, 2},
+		{"different base commit", []*tracker.CheckpointV2{cp("claude", "def", base)}, 1, 3},
+		{"duplicates within the import", []*tracker.CheckpointV2{
+			cp("claude", "abc", base.Add(-time.Minute)),
+			cp("claude", "abc", base.Add(-time.Minute)),
+		}, 1, 4},
+		{"nothing new", []*tracker.CheckpointV2{cp("cursor", "abc", base)}, 0, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, err := store.ImportCheckpoints(tt.incoming)
+			if err != nil {
+				t.Fatalf("ImportCheckpoints() error = %v", err)
+			}
+			if added != tt.wantAdded {
+				t.Errorf("added = %d, want %d", added, tt.wantAdded)
+			}
+			checkpoints, _ := store.LoadCheckpoints()
+			if len(checkpoints) != tt.wantTotal {
+				t.Errorf("total = %d, want %d", len(checkpoints), tt.wantTotal)
+			}
+		})
+	}
+
+	// 取り込んだ結果は時刻順に並ぶ
+	checkpoints, _ := store.LoadCheckpoints()
+	for i := 1; i < len(checkpoints); i++ {
+		if checkpoints[i].Timestamp.Before(checkpoints[i-1].Timestamp) {
+			t.Errorf("checkpoints are not sorted by timestamp: %v before %v", checkpoints[i-1].Timestamp, checkpoints[i].Timestamp)
+		}
+	}
+}
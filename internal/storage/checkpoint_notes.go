@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/gitnotes"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// チェックポイントの保存先（設定の checkpoint_storage）
+const (
+	// CheckpointStorageFile は .git/aict/checkpoints/latest.json（JSONL）に保存します（デフォルト）。
+	CheckpointStorageFile = "file"
+	// CheckpointStorageNotes は取得時のHEADコミットに git notes（refs/aict/checkpoints）として保存します。
+	// aict notes push/fetch でリモートと同期でき、他のマシンのチェックポイントと git notes merge で統合されます。
+	CheckpointStorageNotes = "notes"
+)
+
+// CheckpointStorage はチェックポイントの保存先を返します。
+// 設定の checkpoint_storage（LoadConfig で読み込んだ値）に従い、未設定・設定を読み込めない場合は file です。
+func (s *AIctStorage) CheckpointStorage() string {
+	if s.checkpointStorage == "" {
+		s.LoadConfig()
+	}
+	if s.checkpointStorage == "" {
+		s.checkpointStorage = CheckpointStorageFile
+	}
+	return s.checkpointStorage
+}
+
+// SetCheckpointStorage は設定を読み込まずにチェックポイントの保存先を指定します。
+func (s *AIctStorage) SetCheckpointStorage(mode string) {
+	s.checkpointStorage = mode
+}
+
+// usesCheckpointNotes はチェックポイントをノートに保存するかを返します。
+func (s *AIctStorage) usesCheckpointNotes() bool {
+	return s.CheckpointStorage() == CheckpointStorageNotes
+}
+
+func (s *AIctStorage) notesManager() *gitnotes.NotesManager {
+	if s.notes == nil {
+		s.notes = gitnotes.NewNotesManager()
+	}
+	return s.notes
+}
+
+// loadCheckpointNotes はノートに保存したチェックポイントを読み込みます。
+func (s *AIctStorage) loadCheckpointNotes() ([]*tracker.CheckpointV2, error) {
+	notes, err := s.notesManager().ListCheckpointNotes()
+	if err != nil {
+		return nil, err
+	}
+	var checkpoints []*tracker.CheckpointV2
+	for _, data := range notes {
+		checkpoints = append(checkpoints, parseCheckpointsJSONL([]byte(terminateLine(data)))...)
+	}
+	return checkpoints, nil
+}
+
+// appendCheckpointNote はチェックポイントをBaseCommitのノートに追記します。
+// 呼び出し元がロックを保持していることが前提です。
+func (s *AIctStorage) appendCheckpointNote(cp *tracker.CheckpointV2) error {
+	nm := s.notesManager()
+	existing, err := nm.GetCheckpointNote(cp.BaseCommit)
+	if err != nil {
+		return err
+	}
+	data, err := marshalCheckpointsJSONL([]*tracker.CheckpointV2{cp})
+	if err != nil {
+		return err
+	}
+	return nm.SetCheckpointNote(cp.BaseCommit, terminateLine(existing)+string(data))
+}
+
+// writeCheckpointNotes はノートのチェックポイントを checkpoints（BaseCommitのあるもの）と一致させます。
+// 内容の変わらないノートは書き直さず、チェックポイントがなくなったコミットのノートは削除します。
+// 呼び出し元がロックを保持していることが前提です。
+func (s *AIctStorage) writeCheckpointNotes(checkpoints []*tracker.CheckpointV2) error {
+	nm := s.notesManager()
+	existing, err := nm.ListCheckpointNotes()
+	if err != nil {
+		return err
+	}
+
+	groups := make(map[string][]*tracker.CheckpointV2)
+	var commits []string
+	for _, cp := range checkpoints {
+		if _, ok := groups[cp.BaseCommit]; !ok {
+			commits = append(commits, cp.BaseCommit)
+		}
+		groups[cp.BaseCommit] = append(groups[cp.BaseCommit], cp)
+	}
+
+	for _, commit := range commits {
+		data, err := marshalCheckpointsJSONL(groups[commit])
+		if err != nil {
+			return err
+		}
+		if terminateLine(existing[commit]) == string(data) {
+			continue
+		}
+		if err := nm.SetCheckpointNote(commit, string(data)); err != nil {
+			return err
+		}
+	}
+
+	var stale []string
+	for commit := range existing {
+		if _, ok := groups[commit]; !ok {
+			stale = append(stale, commit)
+		}
+	}
+	sort.Strings(stale)
+	return nm.RemoveCheckpointNotes(stale)
+}
+
+// splitByBaseCommit はBaseCommitのないチェックポイント（最初のコミット前）とあるものに分けます。
+// ノートはコミットに付けるため、BaseCommitのないものは notes の場合もファイルに保存します。
+func splitByBaseCommit(checkpoints []*tracker.CheckpointV2) (withoutBase, withBase []*tracker.CheckpointV2) {
+	for _, cp := range checkpoints {
+		if cp.BaseCommit == "" {
+			withoutBase = append(withoutBase, cp)
+		} else {
+			withBase = append(withBase, cp)
+		}
+	}
+	return withoutBase, withBase
+}
+
+// sortCheckpoints はファイルとノートから読み込んだチェックポイントを時刻順に並べます。
+func sortCheckpoints(checkpoints []*tracker.CheckpointV2) {
+	sort.SliceStable(checkpoints, func(i, j int) bool {
+		return checkpoints[i].Timestamp.Before(checkpoints[j].Timestamp)
+	})
+}
+
+// terminateLine は空でない文字列が改行で終わるようにします（git notes show の出力は末尾の改行が除かれる）。
+func terminateLine(data string) string {
+	if data != "" && !strings.HasSuffix(data, "\n") {
+		return data + "\n"
+	}
+	return data
+}
+
+// MigrateCheckpoints はチェックポイントの保存先を to（file / notes）に切り替え、
+// 保存済みのチェックポイントを移して移した件数を返します。設定の checkpoint_storage も更新します。
+func (s *AIctStorage) MigrateCheckpoints(to string) (int, error) {
+	if to != CheckpointStorageFile && to != CheckpointStorageNotes {
+		return 0, fmt.Errorf("checkpoint storage must be %q or %q, got %q", CheckpointStorageFile, CheckpointStorageNotes, to)
+	}
+	cfg, err := s.LoadConfig()
+	if err != nil {
+		return 0, fmt.Errorf("loading config: %w", err)
+	}
+
+	lockFile, err := s.lockCheckpointsFile()
+	if err != nil {
+		return 0, fmt.Errorf("acquiring checkpoint lock: %w", err)
+	}
+	defer unlockCheckpointsFile(lockFile)
+
+	// 現在の設定に関わらず両方から読み込む（途中で中断した移行も続きから行える）
+	inFile, err := loadCheckpointsFromFile(s.checkpointsFilePath())
+	if err != nil {
+		return 0, err
+	}
+	inNotes, err := s.loadCheckpointNotes()
+	if err != nil {
+		return 0, err
+	}
+	all := append(append([]*tracker.CheckpointV2{}, inFile...), inNotes...)
+	sortCheckpoints(all)
+
+	// 移行途中でも全てのチェックポイントが読めるよう、notes への移行は設定を先に、file への移行は後に更新する
+	saveConfig := func() error {
+		cfg.CheckpointStorage = to
+		if to == CheckpointStorageFile {
+			cfg.CheckpointStorage = ""
+		}
+		if err := s.SaveConfig(cfg); err != nil {
+			return fmt.Errorf("saving config: %w", err)
+		}
+		return nil
+	}
+
+	s.checkpointStorage = to
+	if to == CheckpointStorageNotes {
+		if err := saveConfig(); err != nil {
+			return 0, err
+		}
+		_, withBase := splitByBaseCommit(inFile)
+		return len(withBase), s.rewriteCheckpointsLocked(all)
+	}
+
+	if len(all) == 0 {
+		if err := s.removeCheckpointsFile(); err != nil {
+			return 0, err
+		}
+	} else if err := s.rewriteCheckpointsLocked(all); err != nil {
+		return 0, err
+	}
+	if err := s.writeCheckpointNotes(nil); err != nil {
+		return 0, err
+	}
+	return len(inNotes), saveConfig()
+}
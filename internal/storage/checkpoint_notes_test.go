@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/testutil"
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// createNotesTestStorage はコミットのある実Gitリポジトリでストレージを作成し、HEADのハッシュを返します。
+func createNotesTestStorage(t *testing.T) (*AIctStorage, string) {
+	t.Helper()
+	tmpDir := testutil.TempGitRepo(t)
+	testutil.InitAICT(t, tmpDir)
+	testutil.CreateTestFile(t, tmpDir, "main.go", "package main\n")
+	testutil.GitCommit(t, tmpDir, "initial")
+
+	oldDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(oldDir) })
+	os.Chdir(tmpDir)
+
+	head, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewAIctStorage()
+	if err != nil {
+		t.Fatalf("NewAIctStorage() error = %v", err)
+	}
+	return store, strings.TrimSpace(string(head))
+}
+
+func checkpointAt(author, baseCommit string, ts time.Time) *tracker.CheckpointV2 {
+	cp := testutil.CreateTestCheckpoint(author, tracker.AuthorTypeAI)
+	cp.BaseCommit = baseCommit
+	cp.Timestamp = ts
+	cp.Changes["main.go"] = tracker.Change{Added: 1}
+	return cp
+}
+
+func checkpointAuthors(t *testing.T, store *AIctStorage) string {
+	t.Helper()
+	checkpoints, err := store.LoadCheckpoints()
+	if err != nil {
+		t.Fatalf("LoadCheckpoints() error = %v", err)
+	}
+	var authors []string
+	for _, cp := range checkpoints {
+		authors = append(authors, cp.Author)
+	}
+	return strings.Join(authors, ",")
+}
+
+func TestCheckpointNotesStorage(t *testing.T) {
+	store, head := createNotesTestStorage(t)
+	now := time.Now().Truncate(time.Second)
+
+	// file の間に保存したチェックポイント（BaseCommitのないものを含む）
+	if err := store.SaveCheckpoint(checkpointAt("before-first-commit", "", now.Add(-3*time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveCheckpoint(checkpointAt("claude", head, now.Add(-2*time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+
+	moved, err := store.MigrateCheckpoints(CheckpointStorageNotes)
+	if err != nil {
+		t.Fatalf("MigrateCheckpoints(notes) error = %v", err)
+	}
+	if moved != 1 {
+		t.Errorf("moved = %d, want 1", moved)
+	}
+	cfg, _ := store.LoadConfig()
+	if cfg.CheckpointStorage != CheckpointStorageNotes {
+		t.Errorf("config checkpoint_storage = %q, want notes", cfg.CheckpointStorage)
+	}
+	inFile, _ := loadCheckpointsFromFile(store.checkpointsFilePath())
+	if len(inFile) != 1 || inFile[0].Author != "before-first-commit" {
+		t.Errorf("file should keep only checkpoints without a base commit, got %d", len(inFile))
+	}
+
+	// 新しいストレージも設定から notes を使う
+	store, _ = NewAIctStorage()
+	if err := store.SaveCheckpoint(checkpointAt("cursor", head, now.Add(-time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+	if got := checkpointAuthors(t, store); got != "before-first-commit,claude,cursor" {
+		t.Errorf("checkpoints = %s", got)
+	}
+	note, err := store.notesManager().GetCheckpointNote(head)
+	if err != nil || strings.Count(note, "\n") != 1 {
+		t.Errorf("note on HEAD = %q, %v; want two JSONL lines", note, err)
+	}
+
+	// 照合と同様に、読み込んだチェックポイントのタイムスタンプで消費する
+	loaded, _ := store.LoadCheckpoints()
+	if err := store.RemoveConsumedCheckpoints(map[time.Time]bool{loaded[0].Timestamp: true}); err != nil {
+		t.Fatalf("RemoveConsumedCheckpoints() error = %v", err)
+	}
+	if got := checkpointAuthors(t, store); got != "claude,cursor" {
+		t.Errorf("checkpoints after consuming = %s", got)
+	}
+
+	moved, err = store.MigrateCheckpoints(CheckpointStorageFile)
+	if err != nil {
+		t.Fatalf("MigrateCheckpoints(file) error = %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("moved = %d, want 2", moved)
+	}
+	if notes, _ := store.notesManager().ListCheckpointNotes(); len(notes) != 0 {
+		t.Errorf("notes should be removed after migrating to file, got %d", len(notes))
+	}
+	if got := checkpointAuthors(t, store); got != "claude,cursor" {
+		t.Errorf("checkpoints after migrating back = %s", got)
+	}
+	cfg, _ = store.LoadConfig()
+	if cfg.CheckpointStorage != "" {
+		t.Errorf("config checkpoint_storage = %q, want unset", cfg.CheckpointStorage)
+	}
+}
+
+func TestCheckpointNotesStorage_Clear(t *testing.T) {
+	store, head := createNotesTestStorage(t)
+	store.SetCheckpointStorage(CheckpointStorageNotes)
+
+	if err := store.SaveCheckpoint(checkpointAt("claude", head, time.Now())); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ClearCheckpoints(); err != nil {
+		t.Fatalf("ClearCheckpoints() error = %v", err)
+	}
+	if got := checkpointAuthors(t, store); got != "" {
+		t.Errorf("checkpoints after clear = %s", got)
+	}
+}
+
+func TestMigrateCheckpoints_InvalidStorage(t *testing.T) {
+	store, _ := createNotesTestStorage(t)
+	if _, err := store.MigrateCheckpoints("s3"); err == nil {
+		t.Error("MigrateCheckpoints() should reject an unknown storage")
+	}
+}
@@ -0,0 +1,270 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+	"github.com/y-hirakaw/ai-code-tracker/internal/yaml"
+)
+
+// ConfigYAMLFileName はYAML形式の設定ファイル名（.git/aict/ 直下）。
+// 存在する場合は config.json より優先して読み書きします。
+const ConfigYAMLFileName = "config.yaml"
+
+// ConfigPath は読み書きの対象となる設定ファイルのパスを返します（config.yaml があればそちら）。
+func (s *AIctStorage) ConfigPath() string {
+	yamlFile := filepath.Join(s.gitDir, ConfigYAMLFileName)
+	if _, err := os.Stat(yamlFile); err == nil {
+		return yamlFile
+	}
+	return filepath.Join(s.gitDir, ConfigFileName)
+}
+
+// IsYAMLConfigPath は拡張子（.yaml / .yml）からYAML形式の設定ファイルかを判定します。
+func IsYAMLConfigPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// ConfigToJSON はYAML形式の設定をJSONに変換します。JSON形式の場合はそのまま返します。
+func ConfigToJSON(data []byte, isYAML bool) ([]byte, error) {
+	if !isYAML {
+		return data, nil
+	}
+	return yaml.ToJSON(data)
+}
+
+// MarshalConfig は設定をJSON（インデント付き）またはYAMLに変換します。
+func MarshalConfig(cfg *tracker.Config, isYAML bool) ([]byte, error) {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil || !isYAML {
+		return data, err
+	}
+	return yaml.FromJSON(data)
+}
+
+// ParseConfigStrict は未知のキーをエラーとして扱う ParseConfig です。
+// aict config validate や設定の編集時に、キーの打ち間違いが黙って無視されるのを防ぎます。
+func ParseConfigStrict(data []byte, isYAML bool) (*tracker.Config, error) {
+	jsonData, err := ConfigToJSON(data, isYAML)
+	if err != nil {
+		return nil, err
+	}
+	unknown, err := UnknownConfigKeys(jsonData)
+	if err != nil {
+		return nil, err
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown config keys:\n  %s", strings.Join(unknown, "\n  "))
+	}
+	return ParseConfig(jsonData)
+}
+
+// UnknownConfigKeys は tracker.Config のJSONタグに存在しないキーを
+// "events.webhooks[0].url" 形式のパスと、近いキー名の候補付きで返します。
+func UnknownConfigKeys(jsonData []byte) ([]string, error) {
+	var raw interface{}
+	if err := json.Unmarshal(jsonData, &raw); err != nil {
+		return nil, err
+	}
+	var unknown []string
+	collectUnknownKeys(raw, reflect.TypeOf(tracker.Config{}), "", &unknown)
+	return unknown, nil
+}
+
+// collectUnknownKeys は値と型を並行してたどり、構造体のフィールドに対応しないキーを集めます。
+// 型が合わない値は json.Unmarshal のエラーに任せ、ここでは扱いません。
+func collectUnknownKeys(v interface{}, t reflect.Type, path string, unknown *[]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := jsonFields(t)
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			field, ok := fields[k]
+			if !ok {
+				msg := fmt.Sprintf("%s: unknown key", joinKeyPath(path, k))
+				if s := suggestKey(k, fields); s != "" {
+					msg += fmt.Sprintf(" (did you mean %q?)", s)
+				}
+				*unknown = append(*unknown, msg)
+				continue
+			}
+			collectUnknownKeys(obj[k], field, joinKeyPath(path, k), unknown)
+		}
+	case reflect.Map:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			collectUnknownKeys(obj[k], t.Elem(), joinKeyPath(path, k), unknown)
+		}
+	case reflect.Slice:
+		arr, ok := v.([]interface{})
+		if !ok {
+			return
+		}
+		for i, elem := range arr {
+			collectUnknownKeys(elem, t.Elem(), fmt.Sprintf("%s[%d]", path, i), unknown)
+		}
+	}
+}
+
+// ConfigFieldType は "docs.target_ai_percentage" のようなドット区切りのキーの型を返します。
+// マップ（contexts, author_mappings）のキーは任意の名前を受け付けます。
+func ConfigFieldType(key string) (reflect.Type, error) {
+	t := reflect.TypeOf(tracker.Config{})
+	path := ""
+	for _, part := range strings.Split(key, ".") {
+		if part == "" {
+			return nil, fmt.Errorf("invalid config key %q", key)
+		}
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := jsonFields(t)
+			ft, ok := fields[part]
+			if !ok {
+				msg := fmt.Sprintf("unknown config key %q", joinKeyPath(path, part))
+				if s := suggestKey(part, fields); s != "" {
+					msg += fmt.Sprintf(" (did you mean %q?)", joinKeyPath(path, s))
+				}
+				return nil, fmt.Errorf("%s", msg)
+			}
+			t = ft
+		case reflect.Map:
+			t = t.Elem()
+		default:
+			return nil, fmt.Errorf("config key %q has no field %q", path, part)
+		}
+		path = joinKeyPath(path, part)
+	}
+	return t, nil
+}
+
+// jsonFields は構造体のJSONキー名からフィールドの型への対応を返します。
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func joinKeyPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// suggestKey は編集距離が近い（キー長の1/3以下、最大3）既知のキーを返します。
+func suggestKey(key string, fields map[string]reflect.Type) string {
+	best, bestDist := "", 0
+	for name := range fields {
+		d := editDistance(strings.ToLower(key), name)
+		if best == "" || d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	limit := len(key) / 3
+	if limit > 3 {
+		limit = 3
+	}
+	if bestDist == 0 || bestDist <= limit {
+		return best
+	}
+	return ""
+}
+
+// editDistance はレーベンシュタイン距離を返します。
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// MigrateConfigToYAML は config.json を config.yaml に変換し、元のファイルを config.json.bak として残します。
+// 未知のキーは移行せず、そのパスを返します。
+func (s *AIctStorage) MigrateConfigToYAML() (dropped []string, err error) {
+	jsonFile := filepath.Join(s.gitDir, ConfigFileName)
+	yamlFile := filepath.Join(s.gitDir, ConfigYAMLFileName)
+	if _, err := os.Stat(yamlFile); err == nil {
+		return nil, fmt.Errorf("%s already exists", ConfigYAMLFileName)
+	}
+
+	data, err := os.ReadFile(jsonFile)
+	if err != nil {
+		return nil, err
+	}
+	dropped, err = UnknownConfigKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigFileName, err)
+	}
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigFileName, err)
+	}
+	out, err := MarshalConfig(cfg, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(yamlFile, out, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(jsonFile, jsonFile+".bak"); err != nil {
+		os.Remove(yamlFile)
+		return nil, err
+	}
+	return dropped, nil
+}
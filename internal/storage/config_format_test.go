@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestUnknownConfigKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want []string
+	}{
+		{
+			name: "all keys known",
+			json: `{"target_ai_percentage": 80, "contexts": {"web": {"paths": ["web"]}}, "events": {"webhooks": [{"url": "https://example.com"}]}}`,
+			want: nil,
+		},
+		{
+			name: "typo at top level with suggestion",
+			json: `{"target_ai_percentge": 80, "trackd_extensions": [".go"]}`,
+			want: []string{
+				`target_ai_percentge: unknown key (did you mean "target_ai_percentage"?)`,
+				`trackd_extensions: unknown key (did you mean "tracked_extensions"?)`,
+			},
+		},
+		{
+			name: "nested keys with path",
+			json: `{"contexts": {"web": {"path": ["web"]}}, "events": {"webhooks": [{"url": "https://example.com", "retries": 1}]}}`,
+			want: []string{
+				`contexts.web.path: unknown key (did you mean "paths"?)`,
+				`events.webhooks[0].retries: unknown key`,
+			},
+		},
+		{
+			name: "no suggestion for unrelated key",
+			json: `{"colour": "red"}`,
+			want: []string{"colour: unknown key"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnknownConfigKeys([]byte(tt.json))
+			if err != nil {
+				t.Fatalf("UnknownConfigKeys() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnknownConfigKeys() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConfigStrict(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		isYAML  bool
+		wantErr string
+	}{
+		{"valid yaml", "target_ai_percentage: 80\ntracked_extensions:\n  - .go\ndefault_author: dev\n", true, ""},
+		{"unknown key in yaml", "target_ai_percentage: 80\ndefault_autor: me\n", true, `did you mean "default_author"`},
+		{"yaml syntax error", "target_ai_percentage: 80\n  tracked_extensions: []\n", true, "line 2"},
+		{"invalid value", `{"target_ai_percentage": 150}`, false, "target_ai_percentage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfigStrict([]byte(tt.data), tt.isYAML)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ParseConfigStrict() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseConfigStrict() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMigrateConfigToYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	store, err := NewAIctStorage()
+	if err != nil {
+		t.Fatalf("NewAIctStorage failed: %v", err)
+	}
+	jsonFile := filepath.Join(store.GetAictDir(), ConfigFileName)
+	os.WriteFile(jsonFile, []byte(`{"target_ai_percentage": 60, "tracked_extensions": [".go"], "exclude_patterns": ["vendor/*"],`+
+		` "author_mappings": {"bot": "Claude Code"}, "default_author": "me", "legacy_option": true}`), 0644)
+
+	dropped, err := store.MigrateConfigToYAML()
+	if err != nil {
+		t.Fatalf("MigrateConfigToYAML() error = %v", err)
+	}
+	if len(dropped) != 1 || !strings.HasPrefix(dropped[0], "legacy_option") {
+		t.Errorf("dropped = %q, want legacy_option", dropped)
+	}
+	if _, err := os.Stat(jsonFile + ".bak"); err != nil {
+		t.Errorf("backup should be kept: %v", err)
+	}
+	if got := store.ConfigPath(); filepath.Base(got) != ConfigYAMLFileName {
+		t.Errorf("ConfigPath() = %s, want %s", got, ConfigYAMLFileName)
+	}
+
+	cfg, err := store.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	want := &tracker.Config{
+		TargetAIPercentage: 60,
+		TrackedExtensions:  []string{".go"},
+		ExcludePatterns:    []string{"vendor/*"},
+		AuthorMappings:     map[string]string{"bot": "Claude Code"},
+		DefaultAuthor:      "me",
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("LoadConfig() = %+v, want %+v", cfg, want)
+	}
+
+	// 保存は config.yaml に行われる
+	cfg.DefaultAuthor = "dev"
+	if err := store.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(store.GetAictDir(), ConfigYAMLFileName))
+	if !strings.Contains(string(data), "default_author: dev\n") {
+		t.Errorf("config.yaml = %s, want default_author", data)
+	}
+
+	if _, err := store.MigrateConfigToYAML(); err == nil {
+		t.Error("MigrateConfigToYAML() should fail when config.yaml already exists")
+	}
+}
+
+func TestConfigFieldType(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    string
+		wantErr string
+	}{
+		{key: "tracked_extensions", want: "[]string"},
+		{key: "target_ai_percentage", want: "float64"},
+		{key: "badge.label", want: "string"},
+		{key: "contexts.web.paths", want: "[]string"},
+		{key: "trackd_extensions", wantErr: `did you mean "tracked_extensions"`},
+		{key: "badge..label", wantErr: "invalid config key"},
+		{key: "default_author.name", wantErr: `has no field "name"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := ConfigFieldType(tt.key)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ConfigFieldType() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConfigFieldType() error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ConfigFieldType() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// CrashDirName はクラッシュレポートを保存する .git/aict/ 配下のディレクトリ
+	CrashDirName = "crash"
+	// maxCrashReports は保持するクラッシュレポートの数（古いものから削除）
+	maxCrashReports = 20
+)
+
+// SaveCrashReport はクラッシュレポートを .git/aict/crash/ に保存し、保存したパスを返します。
+// 保持数を超えた古いレポートは削除します。
+func (s *AIctStorage) SaveCrashReport(report []byte, now time.Time) (string, error) {
+	return SaveCrashReportIn(filepath.Join(s.gitDir, CrashDirName), report, now)
+}
+
+// SaveCrashReportIn は指定ディレクトリにクラッシュレポートを保存します（リポジトリ外で実行された場合用）。
+func SaveCrashReportIn(dir string, report []byte, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("crash-%s-%d.txt", now.UTC().Format("20060102-150405"), os.Getpid())
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, report, 0644); err != nil {
+		return "", err
+	}
+	pruneCrashReports(dir)
+	return path, nil
+}
+
+// pruneCrashReports は古いクラッシュレポートを削除して maxCrashReports 件に保ちます（ファイル名が時刻順）。
+func pruneCrashReports(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), "crash-") {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) <= maxCrashReports {
+		return
+	}
+	sort.Strings(names)
+	for _, name := range names[:len(names)-maxCrashReports] {
+		os.Remove(filepath.Join(dir, name))
+	}
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveCrashReportPrunesOldReports(t *testing.T) {
+	s, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	start := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	var last string
+	for i := 0; i < maxCrashReports+3; i++ {
+		path, err := s.SaveCrashReport([]byte("report"), start.Add(time.Duration(i)*time.Second))
+		if err != nil {
+			t.Fatalf("SaveCrashReport() error = %v", err)
+		}
+		last = path
+	}
+
+	dir := filepath.Join(s.GetAictDir(), CrashDirName)
+	if filepath.Dir(last) != dir {
+		t.Errorf("report path = %s, want it under %s", last, dir)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != maxCrashReports {
+		t.Errorf("kept %d reports, want %d", len(entries), maxCrashReports)
+	}
+	// 最も古い3件が削除されていること
+	for _, e := range entries {
+		if strings.Contains(e.Name(), "20250115-100000-") || strings.Contains(e.Name(), "20250115-100002-") {
+			t.Errorf("old report %s should have been pruned", e.Name())
+		}
+	}
+	if _, err := os.Stat(last); err != nil {
+		t.Errorf("newest report missing: %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// QuarantineDirName は aict fsck --repair が取り除いた不正な行の保存先（.git/aict/ 直下）
+const QuarantineDirName = "quarantine"
+
+// RecordFileCheck is the result of validating one JSONL record file
+type RecordFileCheck struct {
+	Name    string        // .git/aict/ からの相対パス
+	Records int           // デコードできたレコード数
+	Corrupt []CorruptLine // デコードできない行
+	Legacy  bool          // 旧JSON配列形式のチェックポイントファイル（行単位の検証・修復の対象外）
+}
+
+// recordFileDecoders は検証するレコードファイルと、各行をデコードする関数です。
+var recordFileDecoders = []struct {
+	name   string
+	decode func([]byte) error
+}{
+	{filepath.Join(CheckpointsDirName, LatestFileName), decodeAs[tracker.CheckpointV2]},
+	{RollupsFileName, decodeAs[tracker.DailyRollup]},
+	{BaselinesFileName, decodeAs[tracker.Baseline]},
+	{ReclassifyLogFileName, decodeAs[tracker.ReclassifyLogEntry]},
+	{RedactionLogFileName, decodeAs[tracker.RedactionLogEntry]},
+}
+
+func decodeAs[T any](b []byte) error {
+	return json.Unmarshal(b, new(T))
+}
+
+// CheckRecordFiles は .git/aict のJSONLレコードファイルを1行ずつ検証します。存在しないファイルは0件です。
+func (s *AIctStorage) CheckRecordFiles() ([]RecordFileCheck, error) {
+	result := make([]RecordFileCheck, 0, len(recordFileDecoders))
+	for _, f := range recordFileDecoders {
+		check, _, err := s.scanRecordFile(f.name, f.decode)
+		if err != nil {
+			return nil, fmt.Errorf("checking %s: %w", f.name, err)
+		}
+		result = append(result, *check)
+	}
+	return result, nil
+}
+
+// RepairRecordFiles はロックを取得して各レコードファイルから不正な行を取り除き、
+// 取り除いた行を .git/aict/quarantine/<file>.<時刻>.jsonl に移します。
+// ロック中は追記が行われないため、改行で終わらない末尾行も不正な行として移します。
+// 修復したファイルの検証結果と、保存先（修復しなかったファイルは空）を返します。
+func (s *AIctStorage) RepairRecordFiles() ([]RecordFileCheck, []string, error) {
+	var checks []RecordFileCheck
+	var quarantined []string
+	for _, f := range recordFileDecoders {
+		check, dest, err := s.repairRecordFile(f.name, f.decode)
+		if err != nil {
+			return nil, nil, fmt.Errorf("repairing %s: %w", f.name, err)
+		}
+		checks = append(checks, *check)
+		quarantined = append(quarantined, dest)
+	}
+	return checks, quarantined, nil
+}
+
+func (s *AIctStorage) repairRecordFile(name string, decode func([]byte) error) (*RecordFileCheck, string, error) {
+	path := filepath.Join(s.gitDir, name)
+	lock, err := lockFile(path + ".lock")
+	if err != nil {
+		return nil, "", fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlockCheckpointsFile(lock)
+
+	check, valid, err := s.scanRecordFile(name, decode)
+	if err != nil || check.Legacy || len(check.Corrupt) == 0 {
+		return check, "", err
+	}
+
+	var bad bytes.Buffer
+	for _, c := range check.Corrupt {
+		bad.Write(c.Data)
+		bad.WriteByte('\n')
+	}
+	dir := filepath.Join(s.gitDir, QuarantineDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, "", err
+	}
+	dest := filepath.Join(dir, fmt.Sprintf("%s.%s.jsonl", strings.ReplaceAll(name, string(filepath.Separator), "_"), time.Now().Format("20060102-150405")))
+	if err := os.WriteFile(dest, bad.Bytes(), 0644); err != nil {
+		return nil, "", fmt.Errorf("writing quarantine file: %w", err)
+	}
+
+	tmpFile := path + ".fsck.tmp"
+	if err := os.WriteFile(tmpFile, valid, 0644); err != nil {
+		return nil, "", fmt.Errorf("write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		os.Remove(tmpFile)
+		return nil, "", fmt.Errorf("rename temp file: %w", err)
+	}
+	return check, dest, nil
+}
+
+// scanRecordFile はレコードファイルを検証し、デコードできた行だけを連結したデータも返します。
+// 改行で終わらない不正な末尾行は、追記途中の可能性があるため Corrupt の最後に含めます（検証のみの場合も表示のため）。
+func (s *AIctStorage) scanRecordFile(name string, decode func([]byte) error) (*RecordFileCheck, []byte, error) {
+	check := &RecordFileCheck{Name: name}
+	f, err := os.Open(filepath.Join(s.gitDir, name))
+	if os.IsNotExist(err) {
+		return check, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	// 旧JSON配列形式（LoadCheckpoints が次の保存時にJSONLへ移行する）は行単位で扱えない
+	head := make([]byte, 512)
+	n, _ := f.Read(head)
+	if trimmed := bytes.TrimSpace(head[:n]); len(trimmed) > 0 && trimmed[0] == '[' {
+		check.Legacy = true
+		return check, nil, nil
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		return nil, nil, err
+	}
+
+	var valid bytes.Buffer
+	scanner := NewJSONLScanner(f, decode)
+	for scanner.Scan() {
+		check.Records++
+		valid.Write(scanner.Record())
+		valid.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	check.Corrupt = scanner.Corrupt()
+	if torn := scanner.Torn(); torn != nil {
+		check.Corrupt = append(check.Corrupt, CorruptLine{Line: scanner.Line(), Err: "incomplete final line", Data: torn})
+	}
+	return check, valid.Bytes(), nil
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordFiles_CheckAndRepair(t *testing.T) {
+	s, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	rollups := filepath.Join(s.GetAictDir(), RollupsFileName)
+	content := "{\"date\":\"2025-01-01\"}\n{broken\n{\"date\":\"2025-01-02\"}\n{\"date\":"
+	if err := os.WriteFile(rollups, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	checks, err := s.CheckRecordFiles()
+	if err != nil {
+		t.Fatalf("CheckRecordFiles() error = %v", err)
+	}
+	var rollupCheck *RecordFileCheck
+	for i := range checks {
+		if checks[i].Name == RollupsFileName {
+			rollupCheck = &checks[i]
+		} else if len(checks[i].Corrupt) != 0 || checks[i].Records != 0 {
+			t.Errorf("%s = %+v, want empty", checks[i].Name, checks[i])
+		}
+	}
+	if rollupCheck == nil || rollupCheck.Records != 2 || len(rollupCheck.Corrupt) != 2 ||
+		rollupCheck.Corrupt[0].Line != 2 || rollupCheck.Corrupt[1].Line != 4 {
+		t.Fatalf("rollups check = %+v, want 2 records and corrupt lines 2, 4", rollupCheck)
+	}
+	if data, _ := os.ReadFile(rollups); string(data) != content {
+		t.Error("CheckRecordFiles() must not modify the file")
+	}
+
+	_, quarantined, err := s.RepairRecordFiles()
+	if err != nil {
+		t.Fatalf("RepairRecordFiles() error = %v", err)
+	}
+	var dest string
+	for _, q := range quarantined {
+		if q != "" {
+			dest = q
+		}
+	}
+	if !strings.HasPrefix(dest, filepath.Join(s.GetAictDir(), QuarantineDirName, RollupsFileName)) {
+		t.Fatalf("quarantine files = %v", quarantined)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "{broken\n{\"date\":\n" {
+		t.Errorf("quarantined lines = %q", data)
+	}
+	loaded, err := s.LoadDailyRollups()
+	if err != nil || len(loaded) != 2 {
+		t.Errorf("LoadDailyRollups() after repair = %d rollups, %v", len(loaded), err)
+	}
+
+	checks, _ = s.CheckRecordFiles()
+	for _, c := range checks {
+		if len(c.Corrupt) != 0 {
+			t.Errorf("%s still has corrupt lines after repair: %+v", c.Name, c.Corrupt)
+		}
+	}
+}
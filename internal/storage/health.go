@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HookLogFileName はhookスクリプトと計測記録が追記するログのファイル名（.git/aict/ 直下）
+const HookLogFileName = "hook.log"
+
+// hookLogTimeFormat はhookスクリプトの `date '+%Y-%m-%d %H:%M:%S'` と同じ形式
+const hookLogTimeFormat = "2006-01-02 15:04:05"
+
+// RecordFileHealth はJSONLレコードファイル1つ分の健全性情報です。
+type RecordFileHealth struct {
+	Name      string // .git/aict/ からの相対パス
+	Records   int    // 読み込み可能なレコード数
+	Malformed int    // 読み込み時にスキップされる不正な行の数
+	Bytes     int64
+}
+
+// AppendHookLog はhookスクリプトと同じ形式でhook.logに1行追記します。
+func (s *AIctStorage) AppendHookLog(message string) error {
+	f, err := os.OpenFile(filepath.Join(s.gitDir, HookLogFileName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "[%s] %s\n", time.Now().Format(hookLogTimeFormat), message)
+	return err
+}
+
+// LoadHookLog はhook.logの内容を返します。ファイルが存在しない場合は空を返します。
+func (s *AIctStorage) LoadHookLog() ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(s.gitDir, HookLogFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// InspectRecordFiles はチェックポイント・日次集計・再分類ログの各ファイルについて
+// レコード数と不正な行の数を返します。存在しないファイルは0件として扱います。
+func (s *AIctStorage) InspectRecordFiles() ([]RecordFileHealth, error) {
+	names := []string{
+		filepath.Join(CheckpointsDirName, LatestFileName),
+		RollupsFileName,
+		ReclassifyLogFileName,
+	}
+
+	result := make([]RecordFileHealth, 0, len(names))
+	for _, name := range names {
+		h := RecordFileHealth{Name: name}
+		data, err := os.ReadFile(filepath.Join(s.gitDir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		h.Bytes = int64(len(data))
+		h.Records, h.Malformed = countJSONLRecords(data)
+		result = append(result, h)
+	}
+	return result, nil
+}
+
+// countJSONLRecords はJSONLデータの有効なレコード数と不正な行数を数えます。
+// 旧JSON配列形式のチェックポイントファイルは配列要素数をレコード数とします。
+func countJSONLRecords(data []byte) (records, malformed int) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return 0, 1
+		}
+		return len(items), 0
+	}
+
+	data = snapshotJSONL(data)
+	for _, off := range indexLines(data) {
+		if json.Valid(data[off[0]:off[1]]) {
+			records++
+		} else {
+			malformed++
+		}
+	}
+	return records, malformed
+}
+
+// DataDirSize は .git/aict/ 配下のファイルサイズの合計を返します。
+func (s *AIctStorage) DataDirSize() (int64, error) {
+	var total int64
+	err := filepath.WalkDir(s.gitDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// 走査中に削除された一時ファイルは無視
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCountJSONLRecords(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantRecords   int
+		wantMalformed int
+	}{
+		{"empty", "", 0, 0},
+		{"valid lines", "{\"a\":1}\n{\"b\":2}\n", 2, 0},
+		{"malformed line", "{\"a\":1}\nnot json\n{\"b\":2}\n", 2, 1},
+		{"torn final line is not malformed", "{\"a\":1}\n{\"b\":", 1, 0},
+		{"legacy array", `[{"a":1},{"b":2}]`, 2, 0},
+		{"broken legacy array", `[{"a":1},`, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, malformed := countJSONLRecords([]byte(tt.input))
+			if records != tt.wantRecords || malformed != tt.wantMalformed {
+				t.Errorf("countJSONLRecords() = (%d, %d), want (%d, %d)", records, malformed, tt.wantRecords, tt.wantMalformed)
+			}
+		})
+	}
+}
+
+func TestInspectRecordFilesAndDataDirSize(t *testing.T) {
+	s, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	dir := filepath.Join(s.GetAictDir(), CheckpointsDirName)
+	os.MkdirAll(dir, 0755)
+	content := "{\"author\":\"a\"}\ngarbage\n"
+	if err := os.WriteFile(filepath.Join(dir, LatestFileName), []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	files, err := s.InspectRecordFiles()
+	if err != nil {
+		t.Fatalf("InspectRecordFiles() error = %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expected 3 record files, got %d", len(files))
+	}
+	cp := files[0]
+	if cp.Records != 1 || cp.Malformed != 1 || cp.Bytes != int64(len(content)) {
+		t.Errorf("checkpoints health = %+v", cp)
+	}
+	if files[1].Records != 0 || files[1].Bytes != 0 {
+		t.Errorf("missing rollups file should be empty, got %+v", files[1])
+	}
+
+	size, err := s.DataDirSize()
+	if err != nil {
+		t.Fatalf("DataDirSize() error = %v", err)
+	}
+	if size < int64(len(content)) {
+		t.Errorf("DataDirSize() = %d, want >= %d", size, len(content))
+	}
+}
+
+func TestAppendHookLog(t *testing.T) {
+	s, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	if data, err := s.LoadHookLog(); err != nil || data != nil {
+		t.Fatalf("LoadHookLog() on missing file = %q, %v", data, err)
+	}
+
+	if err := s.AppendHookLog("checkpoint: completed in 1ms"); err != nil {
+		t.Fatalf("AppendHookLog() error = %v", err)
+	}
+	data, err := s.LoadHookLog()
+	if err != nil {
+		t.Fatalf("LoadHookLog() error = %v", err)
+	}
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "] checkpoint: completed in 1ms") {
+		t.Errorf("unexpected hook log line: %q", line)
+	}
+}
@@ -0,0 +1,228 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// mmapThreshold はメモリマップ読み込みを使用する最小ファイルサイズ。
+// 小さなファイルではmmapのシステムコールコストの方が大きいため通常読み込みを使う。
+const mmapThreshold = 64 * 1024
+
+// readFileData はファイル全体を読み込みます。
+// mmapThreshold 以上のファイルはメモリマップし、失敗時は通常読み込みにフォールバックします。
+// 戻り値の release は読み込んだデータを使い終わった後に必ず呼び出してください。
+//
+// チェックポイントファイルの書き換えは tmp+rename、削除は unlink で行うため、
+// マップ中のファイルが切り詰められることはありません。
+func readFileData(path string) ([]byte, func(), error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	size := info.Size()
+	if size == 0 {
+		return nil, func() {}, nil
+	}
+
+	if size >= mmapThreshold && size == int64(int(size)) {
+		if data, release, err := mapFile(f, int(size)); err == nil {
+			return data, release, nil
+		}
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() {}, nil
+}
+
+// snapshotJSONL はJSONLデータを最後の完全な行境界までに切り詰めたスナップショットを返します。
+// 追記はロックを取らない読み込みと並行して行われるため、末尾に書き込み途中の行が
+// 含まれることがあります。改行で終わらない末尾行がJSONとして完結していない場合は
+// 未完了の書き込みとみなして除外し、読み手から部分書き込みが見えないようにします。
+// 改行なしでも完結したJSONの末尾行（手動編集等）はそのまま含めます。
+func snapshotJSONL(data []byte) []byte {
+	end := bytes.LastIndexByte(data, '\n') + 1
+	if end == len(data) {
+		return data
+	}
+	tail := bytes.TrimSpace(data[end:])
+	if len(tail) == 0 || json.Valid(tail) {
+		return data
+	}
+	return data[:end]
+}
+
+// indexLines はJSONLデータの各行の [開始, 終了) オフセットを返します。
+// 空行・空白のみの行は除外し、行をコピーせずに参照できるようにします。
+func indexLines(data []byte) [][2]int {
+	offsets := make([][2]int, 0, bytes.Count(data, []byte{'\n'})+1)
+	start := 0
+	for start < len(data) {
+		end := bytes.IndexByte(data[start:], '\n')
+		if end == -1 {
+			end = len(data)
+		} else {
+			end += start
+		}
+
+		lineStart, lineEnd := start, end
+		for lineStart < lineEnd && isSpace(data[lineStart]) {
+			lineStart++
+		}
+		for lineEnd > lineStart && isSpace(data[lineEnd-1]) {
+			lineEnd--
+		}
+		if lineStart < lineEnd {
+			offsets = append(offsets, [2]int{lineStart, lineEnd})
+		}
+
+		start = end + 1
+	}
+	return offsets
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+// CorruptLine is a JSONL line skipped because it could not be decoded
+type CorruptLine struct {
+	Line int    // 1始まりの行番号
+	Err  string // デコードエラー
+	Data []byte // 行の内容（前後の空白を除く）
+}
+
+// JSONLScanner はJSONLを1行ずつ読み込むイテレータです。ファイル全体をメモリに載せません。
+// デコードできない行はスキップして Corrupt に記録し、読み込みは継続します。
+// 改行で終わらない末尾行がデコードできない場合は追記途中とみなし、不正な行には数えません（snapshotJSONL と同じ）。
+//
+//	scanner := NewJSONLScanner(f, nil)
+//	for scanner.Scan() {
+//		use(scanner.Record())
+//	}
+//	if err := scanner.Err(); err != nil { ... }
+type JSONLScanner struct {
+	reader   *bufio.Reader
+	validate func([]byte) error
+	line     int
+	record   []byte
+	corrupt  []CorruptLine
+	torn     []byte
+	err      error
+	done     bool
+}
+
+// NewJSONLScanner は r を読み込む JSONLScanner を作成します。
+// validate は各行の検証（型へのデコード等）で、nil の場合はJSONとして正しいかのみ検証します。
+func NewJSONLScanner(r io.Reader, validate func([]byte) error) *JSONLScanner {
+	if validate == nil {
+		validate = func(b []byte) error {
+			if !json.Valid(b) {
+				return errors.New("invalid JSON")
+			}
+			return nil
+		}
+	}
+	return &JSONLScanner{reader: bufio.NewReader(r), validate: validate}
+}
+
+// Scan は次の有効なレコードに進みます。レコードがなくなるか読み込みエラーの場合は false を返します。
+func (s *JSONLScanner) Scan() bool {
+	for !s.done {
+		raw, err := s.reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			s.err, s.done = err, true
+			return false
+		}
+		complete := err == nil
+		if !complete {
+			s.done = true
+		}
+		if len(raw) == 0 {
+			continue
+		}
+		s.line++
+		line := bytes.TrimSpace(raw)
+		if len(line) == 0 {
+			continue
+		}
+		if verr := s.validate(line); verr != nil {
+			if complete || json.Valid(line) {
+				s.corrupt = append(s.corrupt, CorruptLine{Line: s.line, Err: verr.Error(), Data: append([]byte(nil), line...)})
+			} else {
+				s.torn = append([]byte(nil), line...)
+			}
+			continue
+		}
+		s.record = line
+		return true
+	}
+	return false
+}
+
+// Record は現在のレコードを返します。次の Scan で上書きされるため、保持する場合はコピーしてください。
+func (s *JSONLScanner) Record() []byte { return s.record }
+
+// Line は現在のレコードの行番号（1始まり）を返します。
+func (s *JSONLScanner) Line() int { return s.line }
+
+// Corrupt はこれまでにスキップした不正な行を返します。
+func (s *JSONLScanner) Corrupt() []CorruptLine { return s.corrupt }
+
+// Torn は追記途中とみなして読み飛ばした末尾行を返します（なければ nil）。
+func (s *JSONLScanner) Torn() []byte { return s.torn }
+
+// Err は読み込みエラーを返します（不正な行はエラーに含めません）。
+func (s *JSONLScanner) Err() error { return s.err }
+
+// readJSONLFile はJSONLファイルの各行を T にデコードして返します。
+// デコードできない行はスキップして警告を出力し、不正な行として返します。ファイルが存在しない場合は空です。
+func readJSONLFile[T any](path string) ([]*T, []CorruptLine, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return []*T{}, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	records := []*T{}
+	var decoded *T
+	scanner := NewJSONLScanner(f, func(b []byte) error {
+		v := new(T)
+		if err := json.Unmarshal(b, v); err != nil {
+			return err
+		}
+		decoded = v
+		return nil
+	})
+	for scanner.Scan() {
+		records = append(records, decoded)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if corrupt := scanner.Corrupt(); len(corrupt) > 0 {
+		log.Printf("Warning: skipped %d corrupt line(s) in %s (line %d: %s); run 'aict fsck --repair'",
+			len(corrupt), filepath.Base(path), corrupt[0].Line, corrupt[0].Err)
+		return records, corrupt, nil
+	}
+	return records, nil, nil
+}
@@ -0,0 +1,213 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestIndexLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "empty", input: "", want: nil},
+		{name: "single line without newline", input: `{"a":1}`, want: []string{`{"a":1}`}},
+		{name: "trailing newline", input: "{\"a\":1}\n{\"b\":2}\n", want: []string{`{"a":1}`, `{"b":2}`}},
+		{name: "blank and CRLF lines", input: "{\"a\":1}\r\n\n  \n{\"b\":2}", want: []string{`{"a":1}`, `{"b":2}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte(tt.input)
+			offsets := indexLines(data)
+			if len(offsets) != len(tt.want) {
+				t.Fatalf("indexLines() returned %d lines, want %d", len(offsets), len(tt.want))
+			}
+			for i, off := range offsets {
+				if got := string(data[off[0]:off[1]]); got != tt.want[i] {
+					t.Errorf("line %d = %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshotJSONL(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "empty", input: "", want: ""},
+		{name: "complete lines", input: "{\"a\":1}\n{\"b\":2}\n", want: "{\"a\":1}\n{\"b\":2}\n"},
+		{name: "torn final line", input: "{\"a\":1}\n{\"b\":", want: "{\"a\":1}\n"},
+		{name: "torn only line", input: "{\"a\"", want: ""},
+		{name: "complete final line without newline", input: "{\"a\":1}\n{\"b\":2}", want: "{\"a\":1}\n{\"b\":2}"},
+		{name: "trailing whitespace", input: "{\"a\":1}\n  ", want: "{\"a\":1}\n  "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(snapshotJSONL([]byte(tt.input))); got != tt.want {
+				t.Errorf("snapshotJSONL(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadCheckpointsFromFile_TornFinalLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LatestFileName)
+	writeCheckpointsFile(t, path, 3)
+
+	// 書き込み途中の追記を再現（改行なしの不完全な行）
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	f.WriteString(`{"timestamp":"2025-01-01T00:00:03Z","author":"au`)
+	f.Close()
+
+	checkpoints, err := loadCheckpointsFromFile(path)
+	if err != nil {
+		t.Fatalf("loadCheckpointsFromFile() error = %v", err)
+	}
+	if len(checkpoints) != 3 {
+		t.Errorf("loaded %d checkpoints, want 3 (torn line must be ignored)", len(checkpoints))
+	}
+}
+
+// writeCheckpointsFile は指定件数のチェックポイントをJSONL形式で書き込みます。
+func writeCheckpointsFile(t testing.TB, path string, n int) {
+	t.Helper()
+	checkpoints := make([]*tracker.CheckpointV2, n)
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range checkpoints {
+		checkpoints[i] = &tracker.CheckpointV2{
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+			Author:    fmt.Sprintf("author-%d", i%3),
+			Type:      tracker.AuthorTypeHuman,
+			Changes: map[string]tracker.Change{
+				fmt.Sprintf("pkg/file%d.go", i%50): {Added: i % 7, Lines: [][]int{{1, i%7 + 1}}},
+			},
+			Snapshot: map[string]tracker.FileSnapshot{
+				fmt.Sprintf("pkg/file%d.go", i%50): {Hash: fmt.Sprintf("%064d", i), Lines: 100},
+			},
+		}
+	}
+	data, err := marshalCheckpointsJSONL(checkpoints)
+	if err != nil {
+		t.Fatalf("marshalCheckpointsJSONL() error = %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
+
+func TestLoadCheckpointsFromFile_MappedLargeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LatestFileName)
+	writeCheckpointsFile(t, path, 2000)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Size() < mmapThreshold {
+		t.Fatalf("test file too small to exercise mmap path: %d bytes", info.Size())
+	}
+
+	checkpoints, err := loadCheckpointsFromFile(path)
+	if err != nil {
+		t.Fatalf("loadCheckpointsFromFile() error = %v", err)
+	}
+	if len(checkpoints) != 2000 {
+		t.Fatalf("loaded %d checkpoints, want 2000", len(checkpoints))
+	}
+	if checkpoints[1999].Author != "author-1" {
+		t.Errorf("last checkpoint author = %q, want author-1", checkpoints[1999].Author)
+	}
+}
+
+func TestReadFileData_Empty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.jsonl")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	data, release, err := readFileData(path)
+	if err != nil {
+		t.Fatalf("readFileData() error = %v", err)
+	}
+	defer release()
+	if len(data) != 0 {
+		t.Errorf("expected empty data, got %d bytes", len(data))
+	}
+}
+
+func BenchmarkLoadCheckpointsFromFile(b *testing.B) {
+	path := filepath.Join(b.TempDir(), LatestFileName)
+	writeCheckpointsFile(b, path, 10000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := loadCheckpointsFromFile(path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestJSONLScanner(t *testing.T) {
+	input := "{\"a\":1}\n\nnot json\n  {\"b\":2}  \n[1,\n{\"c\":"
+	scanner := NewJSONLScanner(strings.NewReader(input), nil)
+	var records []string
+	var lines []int
+	for scanner.Scan() {
+		records = append(records, string(scanner.Record()))
+		lines = append(lines, scanner.Line())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if fmt.Sprint(records) != `[{"a":1} {"b":2}]` || fmt.Sprint(lines) != "[1 4]" {
+		t.Errorf("records = %v at lines %v", records, lines)
+	}
+	corrupt := scanner.Corrupt()
+	if len(corrupt) != 2 || corrupt[0].Line != 3 || string(corrupt[0].Data) != "not json" || corrupt[1].Line != 5 {
+		t.Errorf("Corrupt() = %+v, want lines 3 and 5", corrupt)
+	}
+	// 改行で終わらない末尾行は追記途中とみなし、不正な行に数えない
+	if string(scanner.Torn()) != `{"c":` {
+		t.Errorf("Torn() = %q", scanner.Torn())
+	}
+}
+
+func TestReadJSONLFile_SkipsCorruptLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baselines.jsonl")
+	content := "{\"commit\":\"a\"}\n{\"commit\":123}\n{\"commit\":\"b\"}\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	baselines, corrupt, err := readJSONLFile[tracker.Baseline](path)
+	if err != nil {
+		t.Fatalf("readJSONLFile() error = %v", err)
+	}
+	if len(baselines) != 2 || baselines[0].Commit != "a" || baselines[1].Commit != "b" {
+		t.Errorf("baselines = %+v", baselines)
+	}
+	if len(corrupt) != 1 || corrupt[0].Line != 2 {
+		t.Errorf("corrupt = %+v, want line 2", corrupt)
+	}
+
+	missing, corrupt, err := readJSONLFile[tracker.Baseline](filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err != nil || len(missing) != 0 || corrupt != nil {
+		t.Errorf("readJSONLFile(missing) = %v, %v, %v", missing, corrupt, err)
+	}
+}
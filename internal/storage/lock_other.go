@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package storage
+
+import "os"
+
+// lockFileHandle はファイルロック非対応プラットフォームでは何もしません（単一プロセスでの利用を前提）。
+func lockFileHandle(f *os.File) error {
+	return nil
+}
+
+// unlockFileHandle はファイルロック非対応プラットフォームでは何もしません。
+func unlockFileHandle(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,38 @@
+//go:build windows
+
+package storage
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// syscall パッケージに LockFileEx がないため kernel32.dll から呼び出します。
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+// lockfileExclusiveLock は LockFileEx の LOCKFILE_EXCLUSIVE_LOCK フラグ
+const lockfileExclusiveLock = 0x2
+
+// lockFileHandle はファイル先頭1バイトの排他ロックを取得します（取得できるまで待機）。
+// ロック専用のファイルなので、先頭1バイトのロックでファイル全体のロックとして扱います。
+func lockFileHandle(f *os.File) error {
+	var ol syscall.Overlapped
+	if ret, _, err := procLockFileEx.Call(f.Fd(), lockfileExclusiveLock, 0, 1, 0, uintptr(unsafe.Pointer(&ol))); ret == 0 {
+		return err
+	}
+	return nil
+}
+
+// unlockFileHandle は lockFileHandle で取得したロックを解放します。
+func unlockFileHandle(f *os.File) error {
+	var ol syscall.Overlapped
+	if ret, _, err := procUnlockFileEx.Call(f.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(&ol))); ret == 0 {
+		return err
+	}
+	return nil
+}
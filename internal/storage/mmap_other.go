@@ -0,0 +1,13 @@
+//go:build !unix
+
+package storage
+
+import (
+	"errors"
+	"os"
+)
+
+// mapFile はmmap非対応プラットフォームでは常にエラーを返し、通常読み込みにフォールバックさせます。
+func mapFile(f *os.File, size int) ([]byte, func(), error) {
+	return nil, nil, errors.New("mmap is not supported on this platform")
+}
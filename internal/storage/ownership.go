@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"errors"
+	"os"
+)
+
+var errOwnershipUnsupported = errors.New("file ownership is not supported on this platform")
+
+// OwnershipStatus は実行ユーザーとリポジトリの所有者の関係です（コンテナ内での実行の診断用）。
+type OwnershipStatus struct {
+	RepoUID    int  // .git ディレクトリの所有者
+	ProcessUID int  // 実行ユーザー（実効uid）
+	Mismatch   bool // 所有者と実行ユーザーが異なる
+	Writable   bool // .git/aict に書き込める
+}
+
+// CheckOwnership は実行ユーザーとリポジトリの所有者を比較し、.git/aict に書き込めるかを確認します。
+// uid のないOSでは ok=false を返します。
+func (s *AIctStorage) CheckOwnership() (status OwnershipStatus, ok bool) {
+	gitDir, err := findGitDir()
+	if err != nil {
+		return status, false
+	}
+	uid, _, err := fileOwner(gitDir)
+	if err != nil {
+		return status, false
+	}
+	status.RepoUID = uid
+	status.ProcessUID = os.Geteuid()
+	status.Mismatch = uid != status.ProcessUID
+
+	f, err := os.CreateTemp(s.gitDir, ".write-test-*")
+	if err == nil {
+		f.Close()
+		os.Remove(f.Name())
+		status.Writable = true
+	}
+	return status, true
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package storage
+
+// fileOwner はファイルの所有者を返します（uid のないOSでは未対応）。
+func fileOwner(path string) (uid, gid int, err error) {
+	return 0, 0, errOwnershipUnsupported
+}
+
+// FixOwnership は uid のないOSでは何もしません。
+func FixOwnership() (int, error) {
+	return 0, nil
+}
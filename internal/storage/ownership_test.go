@@ -0,0 +1,84 @@
+//go:build unix
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestCheckOwnership(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, ".git"), 0755)
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	store, err := NewAIctStorage()
+	if err != nil {
+		t.Fatalf("NewAIctStorage failed: %v", err)
+	}
+	status, ok := store.CheckOwnership()
+	if !ok {
+		t.Fatal("CheckOwnership() ok = false")
+	}
+	if status.Mismatch || !status.Writable || status.ProcessUID != os.Geteuid() {
+		t.Errorf("CheckOwnership() = %+v, want writable without mismatch", status)
+	}
+}
+
+// TestFixOwnership はコンテナ内の root がホストのユーザー所有のリポジトリに書き込む状況を再現します。
+func TestFixOwnership(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires root")
+	}
+	const hostUID, hostGID = 1000, 1000
+
+	tmpDir := t.TempDir()
+	gitDir := filepath.Join(tmpDir, ".git")
+	os.MkdirAll(filepath.Join(gitDir, "objects"), 0755)
+	for _, dir := range []string{gitDir, filepath.Join(gitDir, "objects")} {
+		if err := os.Chown(dir, hostUID, hostGID); err != nil {
+			t.Skipf("chown failed: %v", err)
+		}
+	}
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	store, err := NewAIctStorage()
+	if err != nil {
+		t.Fatalf("NewAIctStorage failed: %v", err)
+	}
+	if status, _ := store.CheckOwnership(); !status.Mismatch || status.RepoUID != hostUID {
+		t.Errorf("CheckOwnership() = %+v, want mismatch with uid %d", status, hostUID)
+	}
+	if err := store.SaveConfig(&tracker.Config{TrackedExtensions: []string{".go"}, DefaultAuthor: "dev"}); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	notesObject := filepath.Join(gitDir, "objects", "ab")
+	os.MkdirAll(notesObject, 0755)
+
+	fixed, err := FixOwnership()
+	if err != nil {
+		t.Fatalf("FixOwnership() error = %v", err)
+	}
+	if fixed != 3 { // .git/aict, config.json, objects/ab
+		t.Errorf("FixOwnership() fixed %d, want 3", fixed)
+	}
+	for _, path := range []string{store.GetAictDir(), store.ConfigPath(), notesObject} {
+		if uid, gid, _ := fileOwner(path); uid != hostUID || gid != hostGID {
+			t.Errorf("%s owned by %d:%d, want %d:%d", path, uid, gid, hostUID, hostGID)
+		}
+	}
+
+	// リポジトリ自体が root の所有であれば何もしない
+	os.Chown(gitDir, 0, 0)
+	os.MkdirAll(filepath.Join(gitDir, "refs"), 0755)
+	if fixed, err := FixOwnership(); err != nil || fixed != 0 {
+		t.Errorf("FixOwnership() on root-owned repo = %d, %v, want 0", fixed, err)
+	}
+}
@@ -0,0 +1,57 @@
+//go:build unix
+
+package storage
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// fileOwner はファイルの所有者の uid / gid を返します。
+func fileOwner(path string) (uid, gid int, err error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, errOwnershipUnsupported
+	}
+	return int(st.Uid), int(st.Gid), nil
+}
+
+// FixOwnership は root で実行している場合に、.git 配下で root が所有するファイル
+// （.git/aict のデータ、git notes のオブジェクト・参照など）の所有者をリポジトリの所有者に戻し、変更した数を返します。
+// コンテナ内の root でバインドマウントしたリポジトリを操作しても、ホストのユーザーが後から書き込めるようにするためです。
+// root 以外で実行している場合や、リポジトリ自体が root の所有の場合は何もしません。
+func FixOwnership() (int, error) {
+	if os.Geteuid() != 0 {
+		return 0, nil
+	}
+	gitDir, err := findGitDir()
+	if err != nil {
+		return 0, nil // リポジトリ外（version 等）
+	}
+	uid, gid, err := fileOwner(gitDir)
+	if err != nil || uid == 0 {
+		return 0, err
+	}
+
+	fixed := 0
+	err = filepath.WalkDir(gitDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // 読めないエントリは対象外
+		}
+		if owner, _, err := fileOwner(path); err != nil || owner != 0 {
+			return nil
+		}
+		if err := os.Lchown(path, uid, gid); err != nil {
+			return err
+		}
+		fixed++
+		return nil
+	})
+	return fixed, err
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// ReclassifyLogFileName は再分類の監査ログのファイル名（.git/aict/ 直下、JSONL）
+const ReclassifyLogFileName = "reclassify_log.jsonl"
+
+// AppendReclassifyLog は再分類の監査レコードを1行追記します。
+func (s *AIctStorage) AppendReclassifyLog(entry *tracker.ReclassifyLogEntry) error {
+	logFile := filepath.Join(s.gitDir, ReclassifyLogFileName)
+
+	lock, err := lockFile(logFile + ".lock")
+	if err != nil {
+		return fmt.Errorf("acquiring reclassify log lock: %w", err)
+	}
+	defer unlockCheckpointsFile(lock)
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal reclassify log entry: %w", err)
+	}
+
+	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open reclassify log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write reclassify log: %w", err)
+	}
+	return nil
+}
+
+// LoadReclassifyLog は再分類の監査ログを古い順に読み込みます。
+// ファイルが存在しない場合は空スライスを返します。デコードできない行はスキップします（aict fsck で修復）。
+func (s *AIctStorage) LoadReclassifyLog() ([]*tracker.ReclassifyLogEntry, error) {
+	entries, _, err := readJSONLFile[tracker.ReclassifyLogEntry](filepath.Join(s.gitDir, ReclassifyLogFileName))
+	return entries, err
+}
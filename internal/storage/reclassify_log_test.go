@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestReclassifyLog_AppendAndLoad(t *testing.T) {
+	s, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	entries, err := s.LoadReclassifyLog()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("LoadReclassifyLog() on empty = %v, %v", entries, err)
+	}
+
+	for _, to := range []tracker.AuthorType{tracker.AuthorTypeAI, tracker.AuthorTypeHuman} {
+		entry := &tracker.ReclassifyLogEntry{
+			Timestamp: time.Now(),
+			To:        to,
+			Filters:   map[string]string{"author": "dev"},
+			Changes:   []tracker.ReclassifyChange{{Commit: "abc", Author: "dev", Files: 1, Lines: 3}},
+		}
+		if err := s.AppendReclassifyLog(entry); err != nil {
+			t.Fatalf("AppendReclassifyLog() error = %v", err)
+		}
+	}
+
+	entries, err = s.LoadReclassifyLog()
+	if err != nil {
+		t.Fatalf("LoadReclassifyLog() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].To != tracker.AuthorTypeAI || entries[1].To != tracker.AuthorTypeHuman {
+		t.Errorf("entries = %+v", entries)
+	}
+}
+
+func TestLoadReclassifyLog_IgnoresTornFinalLine(t *testing.T) {
+	s, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	entry := &tracker.ReclassifyLogEntry{Timestamp: time.Now(), To: tracker.AuthorTypeAI}
+	if err := s.AppendReclassifyLog(entry); err != nil {
+		t.Fatalf("AppendReclassifyLog() error = %v", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.GetAictDir(), ReclassifyLogFileName), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	f.WriteString(`{"timestamp":"2025-01-01T00:00:00Z","to":"hu`)
+	f.Close()
+
+	entries, err := s.LoadReclassifyLog()
+	if err != nil {
+		t.Fatalf("LoadReclassifyLog() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("loaded %d entries, want 1", len(entries))
+	}
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// RedactionLogFileName は伏せたパス・メッセージの記録のファイル名（.git/aict/ 直下、JSONL）
+const RedactionLogFileName = "redactions.jsonl"
+
+// AppendRedactionLog は伏せたパス・メッセージのレコードを追記します。
+func (s *AIctStorage) AppendRedactionLog(entries []*tracker.RedactionLogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	logFile := filepath.Join(s.gitDir, RedactionLogFileName)
+
+	lock, err := lockFile(logFile + ".lock")
+	if err != nil {
+		return fmt.Errorf("acquiring redaction log lock: %w", err)
+	}
+	defer unlockCheckpointsFile(lock)
+
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("marshal redaction log entry: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open redaction log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("write redaction log: %w", err)
+	}
+	return nil
+}
+
+// LoadRedactionLog は伏せたパス・メッセージの記録を古い順に読み込みます。
+// ファイルが存在しない場合は空スライスを返します。デコードできない行はスキップします（aict fsck で修復）。
+func (s *AIctStorage) LoadRedactionLog() ([]*tracker.RedactionLogEntry, error) {
+	entries, _, err := readJSONLFile[tracker.RedactionLogEntry](filepath.Join(s.gitDir, RedactionLogFileName))
+	return entries, err
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestRedactionLog_AppendAndLoad(t *testing.T) {
+	s, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	entries, err := s.LoadRedactionLog()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("LoadRedactionLog() on empty = %v, %v", entries, err)
+	}
+
+	if err := s.AppendRedactionLog(nil); err != nil {
+		t.Fatalf("AppendRedactionLog(nil) error = %v", err)
+	}
+	now := time.Now()
+	err = s.AppendRedactionLog([]*tracker.RedactionLogEntry{
+		{Timestamp: now, Commit: "abc", Kind: "path", Reason: "keyword", Redacted: "config/redacted-1a2b3c4d.json", Original: "config/prod-api-key.json"},
+		{Timestamp: now, Commit: "abc", Kind: "message", Reason: "token", Redacted: "use <redacted>"},
+	})
+	if err != nil {
+		t.Fatalf("AppendRedactionLog() error = %v", err)
+	}
+
+	entries, err = s.LoadRedactionLog()
+	if err != nil {
+		t.Fatalf("LoadRedactionLog() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Original != "config/prod-api-key.json" || entries[1].Kind != "message" {
+		t.Errorf("entries = %+v", entries)
+	}
+}
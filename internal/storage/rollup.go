@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// RollupsFileName は日次集計レコードのファイル名（.git/aict/ 直下、JSONL）
+const RollupsFileName = "daily_rollups.jsonl"
+
+// LoadDailyRollups loads all daily rollup records.
+// ファイルが存在しない場合は空スライスを返します。
+func (s *AIctStorage) LoadDailyRollups() ([]*tracker.DailyRollup, error) {
+	return loadRollupsFromFile(filepath.Join(s.gitDir, RollupsFileName))
+}
+
+// MergeDailyRollup は1コミット分の集計を同じ日付・ブランチのレコードに加算します。
+// delta.Commits に含まれるコミットが既に集計済みの場合は何もしません（amend後の再実行等）。
+func (s *AIctStorage) MergeDailyRollup(delta *tracker.DailyRollup) error {
+	rollupsFile := filepath.Join(s.gitDir, RollupsFileName)
+
+	lock, err := lockFile(rollupsFile + ".lock")
+	if err != nil {
+		return fmt.Errorf("acquiring rollup lock: %w", err)
+	}
+	defer unlockCheckpointsFile(lock)
+
+	rollups, err := loadRollupsFromFile(rollupsFile)
+	if err != nil {
+		return err
+	}
+
+	var target *tracker.DailyRollup
+	for _, r := range rollups {
+		for _, c := range r.Commits {
+			for _, dc := range delta.Commits {
+				if c == dc {
+					return nil
+				}
+			}
+		}
+		if r.Date == delta.Date && r.Branch == delta.Branch {
+			target = r
+		}
+	}
+
+	if target == nil {
+		target = &tracker.DailyRollup{Date: delta.Date, Branch: delta.Branch}
+		rollups = append(rollups, target)
+	}
+	target.Add(delta)
+
+	return writeRollupsFile(rollupsFile, rollups)
+}
+
+// DropDailyRollups は指定コミットのいずれかを含む日次集計レコードを削除し、削除件数を返します。
+// Authorship Logを書き換えた後に呼び出すと、該当期間のレポートはコミット単位の集計にフォールバックします。
+func (s *AIctStorage) DropDailyRollups(commits []string) (int, error) {
+	rollupsFile := filepath.Join(s.gitDir, RollupsFileName)
+
+	lock, err := lockFile(rollupsFile + ".lock")
+	if err != nil {
+		return 0, fmt.Errorf("acquiring rollup lock: %w", err)
+	}
+	defer unlockCheckpointsFile(lock)
+
+	rollups, err := loadRollupsFromFile(rollupsFile)
+	if err != nil {
+		return 0, err
+	}
+
+	drop := make(map[string]bool, len(commits))
+	for _, c := range commits {
+		drop[c] = true
+	}
+
+	kept := rollups[:0]
+	for _, r := range rollups {
+		stale := false
+		for _, c := range r.Commits {
+			if drop[c] {
+				stale = true
+				break
+			}
+		}
+		if !stale {
+			kept = append(kept, r)
+		}
+	}
+
+	dropped := len(rollups) - len(kept)
+	if dropped == 0 {
+		return 0, nil
+	}
+	return dropped, writeRollupsFile(rollupsFile, kept)
+}
+
+// UpdateDailyRollups はロックを保持したまま日次集計を読み込み、update の結果で書き直します。
+// update が nil を返した場合は書き込みません。
+func (s *AIctStorage) UpdateDailyRollups(update func([]*tracker.DailyRollup) []*tracker.DailyRollup) error {
+	rollupsFile := filepath.Join(s.gitDir, RollupsFileName)
+
+	lock, err := lockFile(rollupsFile + ".lock")
+	if err != nil {
+		return fmt.Errorf("acquiring rollup lock: %w", err)
+	}
+	defer unlockCheckpointsFile(lock)
+
+	rollups, err := loadRollupsFromFile(rollupsFile)
+	if err != nil {
+		return err
+	}
+	updated := update(rollups)
+	if updated == nil {
+		return nil
+	}
+	return writeRollupsFile(rollupsFile, updated)
+}
+
+func loadRollupsFromFile(path string) ([]*tracker.DailyRollup, error) {
+	records, _, err := readJSONLFile[tracker.DailyRollup](path)
+	return records, err
+}
+
+// writeRollupsFile は一時ファイル + rename で日次集計ファイルを書き直します。
+func writeRollupsFile(path string, rollups []*tracker.DailyRollup) error {
+	var buf bytes.Buffer
+	for _, r := range rollups {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	return nil
+}
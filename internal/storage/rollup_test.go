@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestMergeDailyRollup(t *testing.T) {
+	store, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	rollups, err := store.LoadDailyRollups()
+	if err != nil {
+		t.Fatalf("LoadDailyRollups failed: %v", err)
+	}
+	if len(rollups) != 0 {
+		t.Fatalf("Expected no rollups, got %d", len(rollups))
+	}
+
+	deltas := []*tracker.DailyRollup{
+		{
+			Date: "2026-01-10", Branch: "main", AIAdded: 10, HumanAdded: 5, Commits: []string{"c1"},
+			Authors: map[string]*tracker.RollupAuthor{"Claude Code": {Type: tracker.AuthorTypeAI, Lines: 10, Commits: 1}},
+		},
+		{
+			Date: "2026-01-10", Branch: "main", AIAdded: 4, HumanDeleted: 2, Commits: []string{"c2"},
+			Authors: map[string]*tracker.RollupAuthor{"Claude Code": {Type: tracker.AuthorTypeAI, Lines: 4, Commits: 1}},
+		},
+		{Date: "2026-01-10", Branch: "feature", HumanAdded: 7, Commits: []string{"c3"}},
+		{Date: "2026-01-11", Branch: "main", AIAdded: 1, Commits: []string{"c4"}},
+		// 集計済みコミットの再加算は無視される
+		{Date: "2026-01-10", Branch: "main", AIAdded: 100, Commits: []string{"c1"}},
+	}
+	for _, d := range deltas {
+		if err := store.MergeDailyRollup(d); err != nil {
+			t.Fatalf("MergeDailyRollup failed: %v", err)
+		}
+	}
+
+	rollups, err = store.LoadDailyRollups()
+	if err != nil {
+		t.Fatalf("LoadDailyRollups failed: %v", err)
+	}
+	if len(rollups) != 3 {
+		t.Fatalf("Expected 3 rollups (per date and branch), got %d", len(rollups))
+	}
+
+	main := rollups[0]
+	if main.Date != "2026-01-10" || main.Branch != "main" {
+		t.Fatalf("Unexpected first rollup: %s/%s", main.Date, main.Branch)
+	}
+	if main.AIAdded != 14 || main.HumanAdded != 5 || main.HumanDeleted != 2 {
+		t.Errorf("Unexpected totals: ai_added=%d human_added=%d human_deleted=%d",
+			main.AIAdded, main.HumanAdded, main.HumanDeleted)
+	}
+	if len(main.Commits) != 2 {
+		t.Errorf("Expected 2 commits, got %v", main.Commits)
+	}
+	if a := main.Authors["Claude Code"]; a == nil || a.Lines != 14 || a.Commits != 2 {
+		t.Errorf("Unexpected author totals: %+v", a)
+	}
+}
+
+func TestDropDailyRollups(t *testing.T) {
+	store, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	for _, d := range []*tracker.DailyRollup{
+		{Date: "2026-01-10", Branch: "main", AIAdded: 10, Commits: []string{"c1"}},
+		{Date: "2026-01-10", Branch: "main", AIAdded: 5, Commits: []string{"c2"}},
+		{Date: "2026-01-11", Branch: "main", HumanAdded: 3, Commits: []string{"c3"}},
+	} {
+		if err := store.MergeDailyRollup(d); err != nil {
+			t.Fatalf("MergeDailyRollup failed: %v", err)
+		}
+	}
+
+	dropped, err := store.DropDailyRollups([]string{"c2", "unknown"})
+	if err != nil {
+		t.Fatalf("DropDailyRollups failed: %v", err)
+	}
+	if dropped != 1 {
+		t.Errorf("dropped = %d, want 1", dropped)
+	}
+
+	rollups, err := store.LoadDailyRollups()
+	if err != nil {
+		t.Fatalf("LoadDailyRollups failed: %v", err)
+	}
+	if len(rollups) != 1 || rollups[0].Date != "2026-01-11" {
+		t.Errorf("remaining rollups = %+v, want only 2026-01-11", rollups)
+	}
+
+	if dropped, err := store.DropDailyRollups([]string{"c1"}); err != nil || dropped != 0 {
+		t.Errorf("DropDailyRollups(no match) = %d, %v", dropped, err)
+	}
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// SessionFileName は直近のClaude Codeセッションの状態のファイル名（.git/aict/ 直下、JSON）
+const SessionFileName = "session.json"
+
+// LoadSession は直近のセッションの状態を読み込みます。
+// ファイルが存在しない場合は nil を返します。
+func (s *AIctStorage) LoadSession() (*tracker.AISession, error) {
+	return loadSessionFromFile(filepath.Join(s.gitDir, SessionFileName))
+}
+
+// UpdateSession はロックを取得してセッションの状態を読み込み、update の結果で置き換えます。
+// update には状態がない場合 nil が渡されます。update が nil を返した場合は書き込みません。
+func (s *AIctStorage) UpdateSession(update func(*tracker.AISession) *tracker.AISession) error {
+	sessionFile := filepath.Join(s.gitDir, SessionFileName)
+
+	lock, err := lockFile(sessionFile + ".lock")
+	if err != nil {
+		return fmt.Errorf("acquiring session lock: %w", err)
+	}
+	defer unlockCheckpointsFile(lock)
+
+	current, err := loadSessionFromFile(sessionFile)
+	if err != nil {
+		return err
+	}
+	next := update(current)
+	if next == nil {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(next, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal session: %w", err)
+	}
+	tmpFile := sessionFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, sessionFile); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	return nil
+}
+
+func loadSessionFromFile(path string) (*tracker.AISession, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var session tracker.AISession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", SessionFileName, err)
+	}
+	return &session, nil
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestSession_UpdateAndLoad(t *testing.T) {
+	s, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	session, err := s.LoadSession()
+	if err != nil || session != nil {
+		t.Fatalf("LoadSession() on empty = %v, %v", session, err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		err := s.UpdateSession(func(current *tracker.AISession) *tracker.AISession {
+			if current == nil {
+				current = &tracker.AISession{SessionID: "s1", StartedAt: start}
+			}
+			current.ToolUses++
+			current.AIAdded += 5
+			return current
+		})
+		if err != nil {
+			t.Fatalf("UpdateSession() error = %v", err)
+		}
+	}
+
+	// nil を返した場合は書き込まない
+	if err := s.UpdateSession(func(*tracker.AISession) *tracker.AISession { return nil }); err != nil {
+		t.Fatalf("UpdateSession(nil) error = %v", err)
+	}
+
+	session, err = s.LoadSession()
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	if session == nil || session.SessionID != "s1" || session.ToolUses != 2 || session.AIAdded != 10 || !session.StartedAt.Equal(start) {
+		t.Errorf("session = %+v, want s1 with 2 tool uses and 10 AI lines", session)
+	}
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+// WhoamiFileName は `aict whoami set` で設定した開発者のファイル名（.git/aict/ 直下、JSON）
+const WhoamiFileName = "whoami.json"
+
+// LoadWhoami は設定中の開発者を読み込みます。
+// 設定されていない場合は nil を返します。
+func (s *AIctStorage) LoadWhoami() (*tracker.ActiveIdentity, error) {
+	data, err := os.ReadFile(filepath.Join(s.gitDir, WhoamiFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var identity tracker.ActiveIdentity
+	if err := json.Unmarshal(data, &identity); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", WhoamiFileName, err)
+	}
+	if identity.Name == "" {
+		return nil, nil
+	}
+	return &identity, nil
+}
+
+// SaveWhoami は開発者を設定します。
+func (s *AIctStorage) SaveWhoami(identity *tracker.ActiveIdentity) error {
+	data, err := json.MarshalIndent(identity, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal identity: %w", err)
+	}
+	whoamiFile := filepath.Join(s.gitDir, WhoamiFileName)
+	tmpFile := whoamiFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, whoamiFile); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	return nil
+}
+
+// ClearWhoami は開発者の設定を削除します。設定されていない場合も成功します。
+func (s *AIctStorage) ClearWhoami() error {
+	err := os.Remove(filepath.Join(s.gitDir, WhoamiFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/tracker"
+)
+
+func TestWhoami_SaveLoadClear(t *testing.T) {
+	s, cleanup := createTestStorage(t)
+	defer cleanup()
+
+	identity, err := s.LoadWhoami()
+	if err != nil || identity != nil {
+		t.Fatalf("LoadWhoami() on empty = %v, %v", identity, err)
+	}
+
+	setAt := time.Now()
+	if err := s.SaveWhoami(&tracker.ActiveIdentity{Name: "Alice", SetAt: setAt}); err != nil {
+		t.Fatalf("SaveWhoami() error = %v", err)
+	}
+	identity, err = s.LoadWhoami()
+	if err != nil || identity == nil || identity.Name != "Alice" || !identity.SetAt.Equal(setAt) {
+		t.Fatalf("LoadWhoami() = %+v, %v, want Alice", identity, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := s.ClearWhoami(); err != nil {
+			t.Fatalf("ClearWhoami() #%d error = %v", i+1, err)
+		}
+	}
+	if identity, err := s.LoadWhoami(); err != nil || identity != nil {
+		t.Errorf("LoadWhoami() after clear = %v, %v", identity, err)
+	}
+}
@@ -0,0 +1,185 @@
+// Package telemetry keeps opt-in, anonymous usage statistics (command and error-class counts only)
+// and sends them to a user-configured endpoint.
+//
+// リポジトリのパス・ファイル名・作成者名・コミット・設定内容は記録せず、利用者ごとのIDも持ちません。
+// aict telemetry on で有効化するまでは何も記録・送信しません。
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+const (
+	// EndpointEnv は送信先のURLを上書きする環境変数
+	EndpointEnv = "AICT_TELEMETRY_ENDPOINT"
+	// DoNotTrackEnv が設定されている場合は有効化していても記録・送信しません（一般的な DO_NOT_TRACK の慣習）
+	DoNotTrackEnv = "DO_NOT_TRACK"
+
+	// SendInterval は集計を送信する間隔
+	SendInterval = 24 * time.Hour
+	// sendTimeout は送信1回のタイムアウト（コマンドの終了を長く待たせない）
+	sendTimeout = 3 * time.Second
+)
+
+// State は利用者ごとのテレメトリ設定と、未送信の集計です。
+type State struct {
+	Enabled  bool           `json:"enabled"`
+	Endpoint string         `json:"endpoint,omitempty"`
+	Since    time.Time      `json:"since"`              // 未送信の集計の開始時刻
+	LastSent time.Time      `json:"last_sent"`          // 最後に送信した時刻
+	Commands map[string]int `json:"commands,omitempty"` // コマンド名 -> 実行回数
+	Errors   map[string]int `json:"errors,omitempty"`   // エラー分類 -> 回数
+}
+
+// Payload は送信する内容です（State の集計に aict のバージョンとOS・アーキテクチャを加えたもの）。
+type Payload struct {
+	Version     string         `json:"version"`
+	OS          string         `json:"os"`
+	Arch        string         `json:"arch"`
+	PeriodStart time.Time      `json:"period_start"`
+	PeriodEnd   time.Time      `json:"period_end"`
+	Commands    map[string]int `json:"commands"`
+	Errors      map[string]int `json:"errors"`
+}
+
+// DefaultPath は設定ファイルのパス（ユーザー設定ディレクトリの aict/telemetry.json）を返します。
+// リポジトリではなく利用者単位の設定のため .git/aict には置きません。
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating user config directory: %w", err)
+	}
+	return filepath.Join(dir, "aict", "telemetry.json"), nil
+}
+
+// DoNotTrack は DO_NOT_TRACK 環境変数で記録・送信が禁止されているかを返します。
+func DoNotTrack() bool {
+	v := os.Getenv(DoNotTrackEnv)
+	return v != "" && v != "0" && v != "false"
+}
+
+// Load は設定ファイルを読み込みます。ファイルがない場合は無効の State を返します。
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Save は設定ファイルを書き込みます（一時ファイルからの rename で途中の状態を残さない）。
+// 複数のaictが同時に保存しても一時ファイルを共有しないよう、一時ファイル名はプロセスごとに一意にします。
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // rename に成功した場合は何もしない
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// EffectiveEndpoint は送信先のURLを返します（環境変数 AICT_TELEMETRY_ENDPOINT が優先）。
+// 既定の送信先はないため、未設定の場合は集計をローカルに保持するだけです。
+func (s *State) EffectiveEndpoint() string {
+	if e := os.Getenv(EndpointEnv); e != "" {
+		return e
+	}
+	return s.Endpoint
+}
+
+// Record はコマンドの実行回数と、エラーがあればその分類を加算します。無効の場合は何もしません。
+func (s *State) Record(command, errorClass string, now time.Time) {
+	if !s.Enabled || DoNotTrack() {
+		return
+	}
+	if s.Since.IsZero() {
+		s.Since = now
+	}
+	if s.Commands == nil {
+		s.Commands = make(map[string]int)
+	}
+	s.Commands[command]++
+	if errorClass != "" {
+		if s.Errors == nil {
+			s.Errors = make(map[string]int)
+		}
+		s.Errors[errorClass]++
+	}
+}
+
+// Reset は未送信の集計を破棄します。
+func (s *State) Reset() {
+	s.Since = time.Time{}
+	s.Commands = nil
+	s.Errors = nil
+}
+
+// Due は送信の時期か（有効・送信先あり・集計あり・前回の送信から SendInterval 経過）を返します。
+func (s *State) Due(now time.Time) bool {
+	return s.Enabled && !DoNotTrack() && s.EffectiveEndpoint() != "" && len(s.Commands) > 0 && now.Sub(s.LastSent) >= SendInterval
+}
+
+// Send は集計を送信先にPOSTし、成功したら集計を破棄して送信時刻を記録します。
+func (s *State) Send(version string, now time.Time) error {
+	payload := Payload{
+		Version:     version,
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		PeriodStart: s.Since,
+		PeriodEnd:   now,
+		Commands:    s.Commands,
+		Errors:      s.Errors,
+	}
+	if payload.Errors == nil {
+		payload.Errors = map[string]int{}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: sendTimeout}
+	resp, err := client.Post(s.EffectiveEndpoint(), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+
+	s.Reset()
+	s.LastSent = now
+	return nil
+}
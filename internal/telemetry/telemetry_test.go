@@ -0,0 +1,136 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecord(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		enabled      bool
+		doNotTrack   string
+		wantCommands map[string]int
+		wantErrors   map[string]int
+	}{
+		{"disabled", false, "", nil, nil},
+		{"enabled", true, "", map[string]int{"report": 2, "commit": 1}, map[string]int{"config": 1}},
+		{"do not track", true, "1", nil, nil},
+		{"do not track set to 0", true, "0", map[string]int{"report": 2, "commit": 1}, map[string]int{"config": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(DoNotTrackEnv, tt.doNotTrack)
+			s := &State{Enabled: tt.enabled}
+			s.Record("report", "", now)
+			s.Record("report", "config", now.Add(time.Minute))
+			s.Record("commit", "", now.Add(2*time.Minute))
+
+			if !reflect.DeepEqual(s.Commands, tt.wantCommands) || !reflect.DeepEqual(s.Errors, tt.wantErrors) {
+				t.Errorf("Record() commands = %v errors = %v, want %v %v", s.Commands, s.Errors, tt.wantCommands, tt.wantErrors)
+			}
+			if tt.wantCommands != nil && !s.Since.Equal(now) {
+				t.Errorf("Since = %v, want %v", s.Since, now)
+			}
+		})
+	}
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		state State
+		env   string
+		want  bool
+	}{
+		{"never sent", State{Enabled: true, Endpoint: "https://example.com/t", Commands: map[string]int{"report": 1}}, "", true},
+		{"sent recently", State{Enabled: true, Endpoint: "https://example.com/t", Commands: map[string]int{"report": 1}, LastSent: now.Add(-time.Hour)}, "", false},
+		{"sent a day ago", State{Enabled: true, Endpoint: "https://example.com/t", Commands: map[string]int{"report": 1}, LastSent: now.Add(-SendInterval)}, "", true},
+		{"no endpoint", State{Enabled: true, Commands: map[string]int{"report": 1}}, "", false},
+		{"endpoint from env", State{Enabled: true, Commands: map[string]int{"report": 1}}, "https://example.com/env", true},
+		{"nothing to send", State{Enabled: true, Endpoint: "https://example.com/t"}, "", false},
+		{"disabled", State{Endpoint: "https://example.com/t", Commands: map[string]int{"report": 1}}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EndpointEnv, tt.env)
+			t.Setenv(DoNotTrackEnv, "")
+			if got := tt.state.Due(now); got != tt.want {
+				t.Errorf("Due() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	t.Setenv(EndpointEnv, "")
+	var got Payload
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	since := time.Date(2025, 1, 14, 9, 0, 0, 0, time.UTC)
+	now := since.Add(25 * time.Hour)
+	s := &State{Enabled: true, Endpoint: server.URL, Since: since, Commands: map[string]int{"report": 3}, Errors: map[string]int{"usage": 1}}
+
+	status = http.StatusInternalServerError
+	if err := s.Send("1.0.0", now); err == nil {
+		t.Fatal("Send() should fail on a 5xx response")
+	}
+	if s.Commands == nil || !s.LastSent.IsZero() {
+		t.Fatalf("failed Send() should keep the counts: %+v", s)
+	}
+
+	status = http.StatusNoContent
+	if err := s.Send("1.0.0", now); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got.Version != "1.0.0" || got.Commands["report"] != 3 || got.Errors["usage"] != 1 || !got.PeriodStart.Equal(since) || !got.PeriodEnd.Equal(now) {
+		t.Errorf("payload = %+v", got)
+	}
+	if s.Commands != nil || s.Errors != nil || !s.Since.IsZero() || !s.LastSent.Equal(now) {
+		t.Errorf("state after Send() = %+v, want counts reset and LastSent = now", s)
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aict", "telemetry.json")
+
+	s, err := Load(path)
+	if err != nil || s.Enabled {
+		t.Fatalf("Load() of a missing file = %+v, %v; want disabled state", s, err)
+	}
+
+	s.Enabled = true
+	s.Endpoint = "https://example.com/t"
+	s.Commands = map[string]int{"report": 1}
+	if err := s.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, s) {
+		t.Errorf("Load() = %+v, want %+v", loaded, s)
+	}
+
+	// 一時ファイルを残さない
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries after Save, want only telemetry.json", len(entries))
+	}
+}
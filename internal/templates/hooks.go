@@ -1,118 +1,27 @@
 package templates
 
-// PreToolUseHook template - records human checkpoint before Claude Code edits
-const PreToolUseHook = `#!/bin/bash
-
-# AI Code Tracker - PreToolUse Hook (SPEC.md)
-# Records human checkpoint before Claude Code makes edits
-
-set -e
-
-# Get project directory
-PROJECT_DIR="${CLAUDE_PROJECT_DIR:-$(pwd)}"
-
-# Log file
-LOG_FILE="$PROJECT_DIR/.git/aict/hook.log"
-
-# Check if AI Code Tracker is initialized
-if [[ ! -d "$PROJECT_DIR/.git/aict" ]]; then
-    exit 0
-fi
-
-# Try to find aict binary
-if command -v aict >/dev/null 2>&1; then
-    AICT_BIN="aict"
-elif [[ -f "$PROJECT_DIR/bin/aict" ]]; then
-    AICT_BIN="$PROJECT_DIR/bin/aict"
-else
-    echo "[$(date '+%Y-%m-%d %H:%M:%S')] pre-tool-use: aict binary not found" >> "$LOG_FILE"
-    exit 0
-fi
-
-# Get git user name
-GIT_USER=$(git config user.name 2>/dev/null || echo "Developer")
-
-# Record human checkpoint before AI edits
-echo "[$(date '+%Y-%m-%d %H:%M:%S')] pre-tool-use: Recording checkpoint for $GIT_USER" >> "$LOG_FILE"
-if "$AICT_BIN" checkpoint --author "$GIT_USER" --message "Before Claude Code edits" 2>> "$LOG_FILE"; then
-    echo "[$(date '+%Y-%m-%d %H:%M:%S')] pre-tool-use: Checkpoint recorded successfully" >> "$LOG_FILE"
-else
-    echo "[$(date '+%Y-%m-%d %H:%M:%S')] pre-tool-use: Failed to record checkpoint (exit code: $?)" >> "$LOG_FILE"
-fi
-
-exit 0`
-
-// PostToolUseHook template - records AI checkpoint after Claude Code edits
-const PostToolUseHook = `#!/bin/bash
-
-# AI Code Tracker - PostToolUse Hook (SPEC.md)
-# Records AI checkpoint after Claude Code edits
-
-set -e
-
-# Get project directory
-PROJECT_DIR="${CLAUDE_PROJECT_DIR:-$(pwd)}"
-
-# Log file
-LOG_FILE="$PROJECT_DIR/.git/aict/hook.log"
-
-# Check if AI Code Tracker is initialized
-if [[ ! -d "$PROJECT_DIR/.git/aict" ]]; then
-    exit 0
-fi
-
-# Try to find aict binary
-if command -v aict >/dev/null 2>&1; then
-    AICT_BIN="aict"
-elif [[ -f "$PROJECT_DIR/bin/aict" ]]; then
-    AICT_BIN="$PROJECT_DIR/bin/aict"
-else
-    echo "[$(date '+%Y-%m-%d %H:%M:%S')] post-tool-use: aict binary not found" >> "$LOG_FILE"
-    exit 0
-fi
-
-# Record AI checkpoint after edits
-echo "[$(date '+%Y-%m-%d %H:%M:%S')] post-tool-use: Recording checkpoint for Claude Code" >> "$LOG_FILE"
-if "$AICT_BIN" checkpoint --author "Claude Code" --message "Claude Code edits" 2>> "$LOG_FILE"; then
-    echo "[$(date '+%Y-%m-%d %H:%M:%S')] post-tool-use: Checkpoint recorded successfully" >> "$LOG_FILE"
-else
-    echo "[$(date '+%Y-%m-%d %H:%M:%S')] post-tool-use: Failed to record checkpoint (exit code: $?)" >> "$LOG_FILE"
-fi
-
-exit 0`
-
-// PostCommitHook template - generates Authorship Log after commit
-const PostCommitHook = `#!/bin/bash
+// PostCommitHook template - git post-commit shim that delegates to 'aict hook post-commit'
+// git hookはファイルとして置く必要があるため、処理本体はGo側に置きこのスクリプトは呼び出しのみ行う。
+// Git for Windows も同梱の sh でhookを実行するため、全プラットフォームで共用する。
+const PostCommitHook = `#!/bin/sh
 
 # AI Code Tracker - Git Post-Commit Hook (SPEC.md)
-# Generates Authorship Log from checkpoints
-
-set -e
-
-# Get project directory
-PROJECT_DIR="$(git rev-parse --show-toplevel)"
+# Generates Authorship Log from checkpoints via 'aict hook post-commit'
 
-# Try to find aict binary
 if command -v aict >/dev/null 2>&1; then
-    AICT_BIN="aict"
-elif [[ -f "$PROJECT_DIR/bin/aict" ]]; then
-    AICT_BIN="$PROJECT_DIR/bin/aict"
+    aict hook post-commit
 else
-    exit 0
+    PROJECT_DIR="$(git rev-parse --show-toplevel)"
+    if [ -f "$PROJECT_DIR/bin/aict" ]; then
+        "$PROJECT_DIR/bin/aict" hook post-commit
+    fi
 fi
 
-# Check if AI Code Tracker is initialized
-if [[ ! -d "$PROJECT_DIR/.git/aict" ]]; then
-    exit 0
-fi
-
-# Generate Authorship Log from checkpoints
-"$AICT_BIN" commit 2>/dev/null || true
-
 exit 0`
 
 // ClaudeSettingsJSON template for Claude Code hook configuration
-// hookスクリプトが存在しない場合でもエラーにならないよう test -x でガード (#5)
+// hookの処理は 'aict hook <event>' がGoで行うため、シェルやスクリプトの実行権限に依存しない（Windowsでも同じ設定を使う）
+// aict がPATHにない環境でもツールの実行を妨げないよう、post-commit hookと同じく bin/aict にフォールバックし、見つからなければ何もしない。
 const ClaudeSettingsJSON = `{
   "hooks": {
     "PreToolUse": [
@@ -121,7 +30,7 @@ const ClaudeSettingsJSON = `{
         "hooks": [
           {
             "type": "command",
-            "command": "test -x \"$CLAUDE_PROJECT_DIR/.git/aict/hooks/pre-tool-use.sh\" && \"$CLAUDE_PROJECT_DIR/.git/aict/hooks/pre-tool-use.sh\" || true"
+            "command": "if command -v aict >/dev/null 2>&1; then aict hook pre-tool-use; elif [ -f \"$CLAUDE_PROJECT_DIR/bin/aict\" ]; then \"$CLAUDE_PROJECT_DIR/bin/aict\" hook pre-tool-use; fi || true"
           }
         ]
       }
@@ -132,7 +41,17 @@ const ClaudeSettingsJSON = `{
         "hooks": [
           {
             "type": "command",
-            "command": "test -x \"$CLAUDE_PROJECT_DIR/.git/aict/hooks/post-tool-use.sh\" && \"$CLAUDE_PROJECT_DIR/.git/aict/hooks/post-tool-use.sh\" || true"
+            "command": "if command -v aict >/dev/null 2>&1; then aict hook post-tool-use; elif [ -f \"$CLAUDE_PROJECT_DIR/bin/aict\" ]; then \"$CLAUDE_PROJECT_DIR/bin/aict\" hook post-tool-use; fi || true"
+          }
+        ]
+      }
+    ],
+    "Stop": [
+      {
+        "hooks": [
+          {
+            "type": "command",
+            "command": "if command -v aict >/dev/null 2>&1; then aict hook stop; elif [ -f \"$CLAUDE_PROJECT_DIR/bin/aict\" ]; then \"$CLAUDE_PROJECT_DIR/bin/aict\" hook stop; fi || true"
           }
         ]
       }
@@ -6,14 +6,30 @@ import (
 	"testing"
 )
 
-func TestHooksContent(t *testing.T) {
-	// Verify hooks start with shebang
-	hooks := []string{PreToolUseHook, PostToolUseHook, PostCommitHook}
+func TestPostCommitHookContent(t *testing.T) {
+	if !strings.HasPrefix(PostCommitHook, "#!/bin/sh") {
+		t.Error("PostCommitHook should start with #!/bin/sh")
+	}
+	if !strings.HasSuffix(strings.TrimSpace(PostCommitHook), "exit 0") {
+		t.Error("PostCommitHook should end with 'exit 0'")
+	}
+	if !strings.Contains(PostCommitHook, "aict hook post-commit") {
+		t.Error("PostCommitHook should delegate to 'aict hook post-commit'")
+	}
+}
 
-	for i, hook := range hooks {
-		if !strings.HasPrefix(hook, "#!/bin/bash") {
-			t.Errorf("Hook %d should start with #!/bin/bash", i)
-		}
+func TestPostCommitHookContainsAICTBinaryDetection(t *testing.T) {
+	if !strings.Contains(PostCommitHook, "command -v aict") {
+		t.Error("PostCommitHook should contain 'command -v aict' for binary detection")
+	}
+	if !strings.Contains(PostCommitHook, "bin/aict") {
+		t.Error("PostCommitHook should contain 'bin/aict' fallback path")
+	}
+}
+
+func TestPostCommitHookUsesGitRevParse(t *testing.T) {
+	if !strings.Contains(PostCommitHook, "git rev-parse --show-toplevel") {
+		t.Error("PostCommitHook should use 'git rev-parse --show-toplevel' for repo root detection")
 	}
 }
 
@@ -39,45 +55,7 @@ func TestSettingsJSON(t *testing.T) {
 	}
 }
 
-func TestHooksExitCleanly(t *testing.T) {
-	hooks := map[string]string{
-		"PreToolUseHook":  PreToolUseHook,
-		"PostToolUseHook": PostToolUseHook,
-		"PostCommitHook":  PostCommitHook,
-	}
-
-	for name, hook := range hooks {
-		if !strings.HasSuffix(strings.TrimSpace(hook), "exit 0") {
-			t.Errorf("%s should end with 'exit 0'", name)
-		}
-	}
-}
-
-func TestHooksContainAICTBinaryDetection(t *testing.T) {
-	hooks := map[string]string{
-		"PreToolUseHook":  PreToolUseHook,
-		"PostToolUseHook": PostToolUseHook,
-		"PostCommitHook":  PostCommitHook,
-	}
-
-	for name, hook := range hooks {
-		if !strings.Contains(hook, "command -v aict") {
-			t.Errorf("%s should contain 'command -v aict' for binary detection", name)
-		}
-		if !strings.Contains(hook, "bin/aict") {
-			t.Errorf("%s should contain 'bin/aict' fallback path", name)
-		}
-	}
-}
-
-func TestPostCommitHookUsesGitRevParse(t *testing.T) {
-	if !strings.Contains(PostCommitHook, "git rev-parse --show-toplevel") {
-		t.Error("PostCommitHook should use 'git rev-parse --show-toplevel' for repo root detection")
-	}
-}
-
-func TestClaudeSettingsHasTestGuard(t *testing.T) {
-	// Verify each hook command has test -x guard (#5)
+func TestClaudeSettingsUsesGoHooks(t *testing.T) {
 	var settings struct {
 		Hooks map[string][]struct {
 			Hooks []struct {
@@ -89,34 +67,21 @@ func TestClaudeSettingsHasTestGuard(t *testing.T) {
 		t.Fatalf("Failed to parse ClaudeSettingsJSON: %v", err)
 	}
 
-	for _, hookName := range []string{"PreToolUse", "PostToolUse"} {
-		entries, ok := settings.Hooks[hookName]
+	tests := []struct {
+		hookName string
+		want     string
+	}{
+		{"PreToolUse", `if command -v aict >/dev/null 2>&1; then aict hook pre-tool-use; elif [ -f "$CLAUDE_PROJECT_DIR/bin/aict" ]; then "$CLAUDE_PROJECT_DIR/bin/aict" hook pre-tool-use; fi || true`},
+		{"PostToolUse", `if command -v aict >/dev/null 2>&1; then aict hook post-tool-use; elif [ -f "$CLAUDE_PROJECT_DIR/bin/aict" ]; then "$CLAUDE_PROJECT_DIR/bin/aict" hook post-tool-use; fi || true`},
+		{"Stop", `if command -v aict >/dev/null 2>&1; then aict hook stop; elif [ -f "$CLAUDE_PROJECT_DIR/bin/aict" ]; then "$CLAUDE_PROJECT_DIR/bin/aict" hook stop; fi || true`},
+	}
+	for _, tt := range tests {
+		entries, ok := settings.Hooks[tt.hookName]
 		if !ok || len(entries) == 0 || len(entries[0].Hooks) == 0 {
-			t.Fatalf("%s hook entry not found", hookName)
-		}
-		cmd := entries[0].Hooks[0].Command
-		if !strings.HasPrefix(cmd, "test -x") {
-			t.Errorf("%s: command should start with 'test -x', got: %s", hookName, cmd)
-		}
-		if !strings.HasSuffix(cmd, "|| true") {
-			t.Errorf("%s: command should end with '|| true', got: %s", hookName, cmd)
-		}
-		if !strings.Contains(cmd, "&&") {
-			t.Errorf("%s: command should contain '&&' to chain execution, got: %s", hookName, cmd)
+			t.Fatalf("%s hook entry not found", tt.hookName)
 		}
-	}
-}
-
-func TestHooksCheckAICTInitialized(t *testing.T) {
-	hooks := map[string]string{
-		"PreToolUseHook":  PreToolUseHook,
-		"PostToolUseHook": PostToolUseHook,
-		"PostCommitHook":  PostCommitHook,
-	}
-
-	for name, hook := range hooks {
-		if !strings.Contains(hook, ".git/aict") {
-			t.Errorf("%s should check for .git/aict directory", name)
+		if cmd := entries[0].Hooks[0].Command; cmd != tt.want {
+			t.Errorf("%s: command = %q, want %q", tt.hookName, cmd, tt.want)
 		}
 	}
 }
@@ -1,6 +1,9 @@
 package tracker
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // DefaultAINames is the list of common AI agent name patterns (case-insensitive substring match)
 var DefaultAINames = []string{"claude", "ai", "assistant", "bot", "copilot", "chatgpt"}
@@ -11,18 +14,26 @@ var DefaultAINames = []string{"claude", "ai", "assistant", "bot", "copilot", "ch
 //  2. AuthorMappings alias resolution, then common AI name check
 //  3. Common AI name patterns (case-insensitive substring match)
 func IsAIAgent(author string, configuredAgents []string, authorMappings map[string]string) bool {
+	return AIAgentMatch(author, configuredAgents, authorMappings) != ""
+}
+
+// AIAgentMatch returns which rule of IsAIAgent classifies the author as an AI agent,
+// or "" when the author is not an AI agent (used by 'aict why' to explain attribution).
+func AIAgentMatch(author string, configuredAgents []string, authorMappings map[string]string) string {
 	// 1. 設定ファイルのAIエージェントリストと完全一致でチェック
 	for _, agent := range configuredAgents {
 		if author == agent {
-			return true
+			return fmt.Sprintf("ai_agents entry %q", agent)
 		}
 	}
 
 	// 2. AuthorMappingsでエイリアス解決
 	resolved := author
+	via := ""
 	if authorMappings != nil {
 		if mapping, exists := authorMappings[author]; exists {
 			resolved = mapping
+			via = fmt.Sprintf("author_mappings %q -> %q, ", author, mapping)
 		}
 	}
 
@@ -30,9 +41,9 @@ func IsAIAgent(author string, configuredAgents []string, authorMappings map[stri
 	resolvedLower := strings.ToLower(resolved)
 	for _, aiName := range DefaultAINames {
 		if strings.Contains(resolvedLower, aiName) {
-			return true
+			return fmt.Sprintf("%sname pattern %q", via, aiName)
 		}
 	}
 
-	return false
+	return ""
 }
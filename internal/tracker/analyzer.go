@@ -6,6 +6,7 @@ import (
 
 	"github.com/y-hirakaw/ai-code-tracker/internal/git"
 	"github.com/y-hirakaw/ai-code-tracker/internal/gitexec"
+	"github.com/y-hirakaw/ai-code-tracker/internal/metrics"
 )
 
 type Analyzer struct {
@@ -77,9 +78,7 @@ func (a *Analyzer) AnalyzeFromGitDiff(diff string, currentMetrics *AnalysisResul
 		LastUpdated: currentMetrics.LastUpdated,
 	}
 
-	if result.TotalLines > 0 {
-		result.Percentage = float64(result.AILines) / float64(result.TotalLines) * 100
-	}
+	result.Percentage = metrics.SafePercent(result.AILines, result.TotalLines)
 
 	return result, nil
 }
@@ -138,11 +137,7 @@ func (a *Analyzer) shouldTrackFile(filepath string) bool {
 
 // calculatePercentage calculates AI percentage from AI and human lines
 func calculatePercentage(aiLines, humanLines int) float64 {
-	total := aiLines + humanLines
-	if total == 0 {
-		return 0.0
-	}
-	return float64(aiLines) / float64(total) * 100
+	return metrics.SafePercent(aiLines, aiLines+humanLines)
 }
 
 // aggregateLinesByAuthor adds lines to appropriate counter based on author type
@@ -301,16 +296,16 @@ func (a *Analyzer) analyzeFromFiles(before, after *Checkpoint, isAI bool) *Analy
 }
 
 func (a *Analyzer) GenerateReport(result *AnalysisResult) string {
-	progress := result.Percentage / a.config.TargetAIPercentage * 100
+	progress := 0.0
+	if a.config.TargetAIPercentage > 0 {
+		progress = result.Percentage / a.config.TargetAIPercentage * 100
+	}
 	if progress > 100 {
 		progress = 100
 	}
 
 	addedLines := result.AILines + result.HumanLines
-	humanPercentage := 0.0
-	if addedLines > 0 {
-		humanPercentage = float64(result.HumanLines) / float64(addedLines) * 100
-	}
+	humanPercentage := metrics.SafePercent(result.HumanLines, addedLines)
 
 	report := fmt.Sprintf(`AI Code Tracking Report
 ======================
@@ -115,6 +115,28 @@ func TestIsAIAgentNilMappings(t *testing.T) {
 	}
 }
 
+func TestAIAgentMatch(t *testing.T) {
+	configuredAgents := []string{"Internal Helper"}
+	authorMappings := map[string]string{"cc": "Claude Code"}
+
+	tests := []struct {
+		author string
+		want   string
+	}{
+		{"Internal Helper", `ai_agents entry "Internal Helper"`},
+		{"cc", `author_mappings "cc" -> "Claude Code", name pattern "claude"`},
+		{"GitHub Copilot", `name pattern "copilot"`},
+		{"John Doe", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.author, func(t *testing.T) {
+			if got := AIAgentMatch(tt.author, configuredAgents, authorMappings); got != tt.want {
+				t.Errorf("AIAgentMatch(%q) = %q, want %q", tt.author, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestShouldTrackFile(t *testing.T) {
 	config := &Config{
 		TrackedExtensions: []string{".go", ".js", ".py"},
@@ -1,6 +1,10 @@
 package tracker
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/y-hirakaw/ai-code-tracker/internal/ignore"
+)
 
 // MatchesPattern performs simple wildcard pattern matching.
 // Supports prefix wildcard (*_test.go), suffix wildcard (vendor/*), and exact match.
@@ -17,8 +21,23 @@ func MatchesPattern(fpath, pattern string) bool {
 	return fpath == pattern
 }
 
+// ExcludeMatcher compiles exclude_patterns followed by the .aictignore lines with gitignore semantics.
+// .aictignore は exclude_patterns の後に評価されるため、! で exclude_patterns の除外を取り消せます。
+func (c *Config) ExcludeMatcher() *ignore.Matcher {
+	patterns := make([]string, 0, len(c.ExcludePatterns)+len(c.IgnorePatterns))
+	patterns = append(patterns, c.ExcludePatterns...)
+	patterns = append(patterns, c.IgnorePatterns...)
+	return ignore.Compile(patterns)
+}
+
+// IsExcludedFile checks if a file matches exclude_patterns or .aictignore.
+// 多数のファイルを判定する場合は ExcludeMatcher を一度だけコンパイルして使ってください。
+func IsExcludedFile(fpath string, cfg *Config) bool {
+	return cfg.ExcludeMatcher().Match(fpath)
+}
+
 // IsTrackedFile checks if a file should be tracked based on config.
-// A file is tracked if it has a tracked extension and does not match any exclude pattern.
+// A file is tracked if it has a tracked extension and is not excluded by exclude_patterns or .aictignore.
 func IsTrackedFile(fpath string, cfg *Config) bool {
 	hasValidExt := false
 	for _, ext := range cfg.TrackedExtensions {
@@ -30,11 +49,45 @@ func IsTrackedFile(fpath string, cfg *Config) bool {
 	if !hasValidExt {
 		return false
 	}
+	return !IsExcludedFile(fpath, cfg)
+}
 
-	for _, pattern := range cfg.ExcludePatterns {
-		if MatchesPattern(fpath, pattern) {
+// IsArtifactFile checks if a file is an opt-in artifact (config "artifacts").
+// tracked_extensions の対象ファイル（行数で集計）と exclude_patterns・.aictignore で除外したファイルは対象外です。
+func IsArtifactFile(fpath string, cfg *Config) bool {
+	if cfg.Artifacts == nil || !matchesAnyPattern(fpath, cfg.Artifacts.Patterns) {
+		return false
+	}
+	for _, ext := range cfg.TrackedExtensions {
+		if strings.HasSuffix(fpath, ext) {
 			return false
 		}
 	}
-	return true
+	return !IsExcludedFile(fpath, cfg)
+}
+
+// IsRecordedFile checks if a file's changes are recorded in checkpoints and Authorship Logs
+// (tracked files and artifacts).
+func IsRecordedFile(fpath string, cfg *Config) bool {
+	return IsTrackedFile(fpath, cfg) || IsArtifactFile(fpath, cfg)
+}
+
+func matchesAnyPattern(fpath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if MatchesPattern(fpath, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDocFile checks if a file belongs to the docs category (config "docs").
+// docs が未設定の場合は常に false を返します。
+func IsDocFile(fpath string, cfg *Config) bool {
+	for _, ext := range cfg.GetDocsExtensions() {
+		if strings.HasSuffix(fpath, ext) {
+			return true
+		}
+	}
+	return false
 }
@@ -73,3 +73,97 @@ func TestIsTrackedFile_EmptyConfig(t *testing.T) {
 		t.Error("IsTrackedFile should return false with empty extensions")
 	}
 }
+
+func TestIsDocFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		docs  *DocsConfig
+		fpath string
+		want  bool
+	}{
+		{"docs not configured", nil, "README.md", false},
+		{"default extensions md", &DocsConfig{}, "docs/guide.md", true},
+		{"default extensions rst", &DocsConfig{}, "docs/index.rst", true},
+		{"default extensions adoc", &DocsConfig{}, "manual.adoc", true},
+		{"code file", &DocsConfig{}, "main.go", false},
+		{"custom extensions", &DocsConfig{Extensions: []string{".txt"}}, "notes.txt", true},
+		{"custom extensions replace defaults", &DocsConfig{Extensions: []string{".txt"}}, "README.md", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Docs: tt.docs}
+			if got := IsDocFile(tt.fpath, cfg); got != tt.want {
+				t.Errorf("IsDocFile(%q) = %v, want %v", tt.fpath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsArtifactFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		artifacts *ArtifactsConfig
+		fpath     string
+		want      bool
+	}{
+		{"artifacts not configured", nil, "db/001_init.sql", false},
+		{"suffix pattern", &ArtifactsConfig{Patterns: []string{"*.sql"}}, "db/001_init.sql", true},
+		{"prefix pattern", &ArtifactsConfig{Patterns: []string{"schemas/*"}}, "schemas/user.json", true},
+		{"exact match", &ArtifactsConfig{Patterns: []string{"Dockerfile"}}, "Dockerfile", true},
+		{"no match", &ArtifactsConfig{Patterns: []string{"*.sql"}}, "config.yaml", false},
+		{"tracked extension is counted by line", &ArtifactsConfig{Patterns: []string{"*.go"}}, "main.go", false},
+		{"excluded", &ArtifactsConfig{Patterns: []string{"*.sql"}}, "vendor/dump.sql", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{TrackedExtensions: []string{".go"}, ExcludePatterns: []string{"vendor/*"}, Artifacts: tt.artifacts}
+			if got := IsArtifactFile(tt.fpath, cfg); got != tt.want {
+				t.Errorf("IsArtifactFile(%q) = %v, want %v", tt.fpath, got, tt.want)
+			}
+			if got := IsRecordedFile(tt.fpath, cfg); got != (tt.want || IsTrackedFile(tt.fpath, cfg)) {
+				t.Errorf("IsRecordedFile(%q) = %v", tt.fpath, got)
+			}
+		})
+	}
+}
+
+func TestGetDocsTarget(t *testing.T) {
+	cfg := &Config{TargetAIPercentage: 80, Docs: &DocsConfig{}}
+	if got := cfg.GetDocsTarget(); got != 80 {
+		t.Errorf("GetDocsTarget() = %.1f, want 80 (overall target)", got)
+	}
+	cfg.Docs.TargetAIPercentage = 40
+	if got := cfg.GetDocsTarget(); got != 40 {
+		t.Errorf("GetDocsTarget() = %.1f, want 40", got)
+	}
+}
+
+func TestIsTrackedFile_IgnorePatterns(t *testing.T) {
+	cfg := &Config{
+		TrackedExtensions: []string{".go"},
+		ExcludePatterns:   []string{"*_test.go", "vendor/*"},
+		IgnorePatterns:    []string{"# generated code", "**/gen/*.pb.go", "vendor/", "!keep_test.go"},
+	}
+
+	tests := []struct {
+		name     string
+		fpath    string
+		expected bool
+	}{
+		{"regular file", "internal/app.go", true},
+		{"generated protobuf", "api/gen/user.pb.go", false},
+		{"nested vendor dir", "tools/vendor/lib.go", false},
+		{"exclude_patterns still apply", "app_test.go", false},
+		{".aictignore negation overrides exclude_patterns", "pkg/keep_test.go", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTrackedFile(tt.fpath, cfg); got != tt.expected {
+				t.Errorf("IsTrackedFile(%q) = %v, want %v", tt.fpath, got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package tracker
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Legacy Checkpoint struct (for backward compatibility)
 type Checkpoint struct {
@@ -71,6 +74,9 @@ type DetailedMetrics struct {
 
 	// 新規作成（完全新規のコードのみ）
 	NewFiles NewFileMetrics `json:"new_files,omitempty"`
+
+	// 混在（一方が書いた既存行をもう一方が削除・変更した行）
+	Mixed MixedMetrics `json:"mixed,omitempty"`
 }
 
 // ContributionMetrics represents code contributions (net additions)
@@ -95,21 +101,194 @@ type NewFileMetrics struct {
 	HumanNewLines int `json:"human_new_lines"`
 }
 
+// MixedMetrics represents existing lines rewritten (modified or deleted) by the other author type
+type MixedMetrics struct {
+	AIEditedByHuman int `json:"ai_edited_by_human"` // AIが書いた行を人間が削除・変更した行数
+	HumanEditedByAI int `json:"human_edited_by_ai"` // 人間が書いた行をAIが削除・変更した行数
+}
+
 type FileStats struct {
-	Path       string `json:"path"`
-	TotalLines int    `json:"total_lines"`
-	AILines    int    `json:"ai_lines"`
-	HumanLines int    `json:"human_lines"`
+	Path       string  `json:"path"`
+	TotalLines int     `json:"total_lines"`
+	AILines    int     `json:"ai_lines"`
+	HumanLines int     `json:"human_lines"`
+	Percentage float64 `json:"ai_percentage,omitempty"` // TotalLines に占めるAI行の割合（report --by-file/--by-dir）
 }
 
 type Config struct {
-	TargetAIPercentage float64           `json:"target_ai_percentage"`
-	TrackedExtensions  []string          `json:"tracked_extensions"`
-	ExcludePatterns    []string          `json:"exclude_patterns"`
-	AuthorMappings     map[string]string `json:"author_mappings"`
-	DefaultAuthor      string            `json:"default_author,omitempty"`       // SPEC.md準拠
-	AIAgents           []string          `json:"ai_agents,omitempty"`            // SPEC.md準拠
-	CheckpointTTLHours int              `json:"checkpoint_ttl_hours,omitempty"` // 0=デフォルト24時間
+	TargetAIPercentage    float64           `json:"target_ai_percentage"`
+	TrackedExtensions     []string          `json:"tracked_extensions"`
+	ExcludePatterns       []string          `json:"exclude_patterns"`
+	AuthorMappings        map[string]string `json:"author_mappings"`
+	DefaultAuthor         string            `json:"default_author,omitempty"`          // SPEC.md準拠
+	AIAgents              []string          `json:"ai_agents,omitempty"`               // SPEC.md準拠
+	CheckpointTTLHours    int               `json:"checkpoint_ttl_hours,omitempty"`    // 0=デフォルト24時間
+	CheckpointStorage     string            `json:"checkpoint_storage,omitempty"`      // チェックポイントの保存先: "file"（デフォルト）または "notes"（git notes）
+	DashboardURL          string            `json:"dashboard_url,omitempty"`           // backstage-metadata に出力するダッシュボードURL
+	IncludeGenerated      bool              `json:"include_generated,omitempty"`       // true=生成コード・minified・ロックファイルも集計（デフォルトは除外）
+	IncludeLicenseHeaders bool              `json:"include_license_headers,omitempty"` // true=ファイル先頭のライセンスヘッダーも集計（デフォルトは除外）
+	AdoptionDate          string            `json:"adoption_date,omitempty"`           // aict ownership の既定の --since（導入日以降にコミットされた行のみを帰属の対象にする）
+
+	Contexts map[string]ContextConfig `json:"contexts,omitempty"` // 名前付きトラッキングコンテキスト（例: frontend, backend）
+	Badge    *BadgeConfig             `json:"badge,omitempty"`    // aict badge の表示設定
+
+	RepoLanguages *RepoLanguageProfile `json:"repo_languages,omitempty"` // aict init 時に計測したリポジトリの言語構成
+
+	CommitPatterns []CommitPattern `json:"commit_patterns,omitempty"` // フックなしでAIツールのコミットを判定するパターン（例: Aider）
+
+	Baseline *BaselineConfig `json:"baseline,omitempty"` // aict baseline の作成間隔と保持数
+
+	Docs *DocsConfig `json:"docs,omitempty"` // ドキュメントをコードと分けて集計する設定（未設定時は分けない）
+
+	Artifacts *ArtifactsConfig `json:"artifacts,omitempty"` // 拡張子で追跡しないファイルをファイル数で集計する設定（オプトイン）
+
+	Events *EventsConfig `json:"events,omitempty"` // チェックポイント・コミット記録時の通知先（未設定時は送信しない）
+
+	Overrides []OverrideRule `json:"overrides,omitempty"` // レポート集計時に適用する作成者種別・重みの上書き（Authorship Logは書き換えない）
+
+	Redaction *RedactionConfig `json:"redaction,omitempty"` // Authorship Log・チェックポイントに保存する前にパス・メッセージの秘密情報らしき文字列を伏せる（未設定時は伏せない）
+
+	IgnorePatterns []string `json:"-"` // リポジトリルートの .aictignore の行（LoadConfig が読み込み、設定ファイルには保存しない）
+}
+
+// EventsConfig sends a compact event to a local message bus and/or outgoing webhooks
+// each time a checkpoint or an Authorship Log is recorded
+type EventsConfig struct {
+	Bus     string `json:"bus,omitempty"`     // "nats" または "redis"（未指定時はバスに送信しない）
+	Address string `json:"address,omitempty"` // host:port（未指定時は localhost の既定ポート）
+	Subject string `json:"subject,omitempty"` // NATSのsubject / Redisのstream名（未指定時は aict.<event>）
+
+	Webhooks []WebhookConfig `json:"webhooks,omitempty"` // HTTP POSTで通知するWebhook（Zapier・IFTTT・Slack等）
+}
+
+// WebhookConfig is an outgoing webhook with an optional Go-templated JSON payload
+type WebhookConfig struct {
+	URL        string            `json:"url"`
+	Events     []string          `json:"events,omitempty"`      // 通知するイベント（checkpoint, commit, milestone。未指定時は commit と milestone）
+	Template   string            `json:"template,omitempty"`    // text/template 形式のJSONペイロード（未指定時はイベントのJSON）
+	Headers    map[string]string `json:"headers,omitempty"`     // 追加のHTTPヘッダ（認証トークン等）
+	MaxRetries int               `json:"max_retries,omitempty"` // 失敗時の再送回数（0=デフォルト3回、負の値=再送しない）
+}
+
+// RedactionConfig hides secret-looking file names and message fragments before they are persisted
+type RedactionConfig struct {
+	Patterns          []string `json:"patterns,omitempty"`           // 伏せる正規表現（パスは全体に一致すればファイル名、メッセージは一致部分）
+	DisableHeuristics bool     `json:"disable_heuristics,omitempty"` // true=既定のキーワード・トークン形式・エントロピー判定を使わない
+}
+
+// ArtifactsConfig opts in files outside tracked_extensions (JSON schemas, SQL migrations, config files, ...).
+// 一致したファイルは行数ではなく変更ファイル数で集計し、レポートでは別枠で表示します。
+type ArtifactsConfig struct {
+	Patterns []string `json:"patterns"` // exclude_patterns と同じ書式（*.sql, schemas/*, Dockerfile 等）
+}
+
+// DefaultDocsExtensions は docs.extensions 未指定時にドキュメントとして扱う拡張子
+var DefaultDocsExtensions = []string{".md", ".rst", ".adoc"}
+
+// DocsConfig reports documentation files separately from code, with their own AI target
+type DocsConfig struct {
+	Extensions         []string `json:"extensions,omitempty"`           // ドキュメントとして扱う拡張子（未指定時は DefaultDocsExtensions）
+	TargetAIPercentage float64  `json:"target_ai_percentage,omitempty"` // 0=全体の target_ai_percentage を使用
+}
+
+// BaselineConfig controls scheduled baseline snapshots created by 'aict baseline create --scheduled'
+type BaselineConfig struct {
+	IntervalDays int `json:"interval_days,omitempty"` // --scheduled で新しいベースラインを作成する間隔（0=デフォルト7日）
+	Retention    int `json:"retention,omitempty"`     // 保持するベースライン数（0=デフォルト8件）
+}
+
+// Baseline is a recorded snapshot point; reports without --range/--since cover the commits after the latest one
+type Baseline struct {
+	CreatedAt time.Time `json:"created_at"`
+	Commit    string    `json:"commit"`
+	Branch    string    `json:"branch,omitempty"`
+	Label     string    `json:"label,omitempty"`
+	Scheduled bool      `json:"scheduled,omitempty"` // --scheduled（cron/CI）で作成された
+}
+
+// CommitPattern detects commits made by an AI tool from the git author name or commit message
+// (e.g. Aider's "(aider)" author suffix or "Co-authored-by: aider (<model>)" trailer).
+// いずれかのパターンに一致したコミットの変更は全てAIの変更として記録される
+type CommitPattern struct {
+	Name           string `json:"name"`                      // Authorship Logに記録する作成者名（例: "Aider"）
+	AuthorPattern  string `json:"author_pattern,omitempty"`  // git作成者名に対する正規表現
+	MessagePattern string `json:"message_pattern,omitempty"` // コミットメッセージ（トレーラー含む）に対する正規表現。名前付きグループ model はモデル名として記録
+}
+
+// OverrideRule reclassifies or reweights matching authorship entries when reports are computed.
+// Authorship Logは書き換えず、集計のたびに先頭から順に評価して最初に一致したルールを適用する
+type OverrideRule struct {
+	Name   string     `json:"name,omitempty"`   // レポートに表示するルール名（未指定時は overrides[番号]）
+	Since  string     `json:"since,omitempty"`  // この日以降のコミット（YYYY-MM-DD またはRFC3339）
+	Until  string     `json:"until,omitempty"`  // この日までのコミット（YYYY-MM-DD はその日を含む）
+	Author string     `json:"author,omitempty"` // 作成者名のglob（author_mappings解決後の名前でも一致）
+	Branch string     `json:"branch,omitempty"` // このブランチから到達できるコミットのみ
+	Type   AuthorType `json:"type,omitempty"`   // 上書き後の種別（ai / human、未指定時は元の種別）
+	Weight *float64   `json:"weight,omitempty"` // 行数に掛ける重み（0以上、未指定時は1）
+}
+
+// ParseDateTime は設定・オプションの日時を解釈します。
+// YYYY-MM-DD はローカル時刻のその日の開始、それ以外はRFC3339として解釈します。
+func ParseDateTime(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// Window はルールの期間を [since, until) で返します（未指定の端はゼロ値）。
+// 日付のみの指定はローカル時刻で解釈し、until はその日の終わりまでを含みます。
+func (r OverrideRule) Window() (since, until time.Time, err error) {
+	parse := func(value string, endOfDay bool) (time.Time, error) {
+		if value == "" {
+			return time.Time{}, nil
+		}
+		t, err := ParseDateTime(value)
+		if err == nil && endOfDay && len(value) == len("2006-01-02") {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, err
+	}
+	if since, err = parse(r.Since, false); err != nil {
+		return since, until, fmt.Errorf("since: %w", err)
+	}
+	if until, err = parse(r.Until, true); err != nil {
+		return since, until, fmt.Errorf("until: %w", err)
+	}
+	return since, until, nil
+}
+
+// RepoLanguageProfile is the language mix of the repository measured at init
+type RepoLanguageProfile struct {
+	ScannedAt time.Time           `json:"scanned_at"`
+	Commit    string              `json:"commit,omitempty"` // 計測時のHEAD
+	Languages []RepoLanguageShare `json:"languages"`        // 行数の多い順
+}
+
+// RepoLanguageShare is one language's share of the repository's code lines
+type RepoLanguageShare struct {
+	Language   string  `json:"language"`
+	Files      int     `json:"files"`
+	Lines      int     `json:"lines"`
+	Percentage float64 `json:"percentage"`
+}
+
+// BadgeConfig customizes the SVG badge rendered by 'aict badge'
+type BadgeConfig struct {
+	Label      string           `json:"label,omitempty"`      // 左側のラベル（デフォルト: "AI code"）
+	Thresholds []BadgeThreshold `json:"thresholds,omitempty"` // AI%に応じた色（未指定時はデフォルトの閾値）
+}
+
+// BadgeThreshold colors the badge when AI% >= Min
+type BadgeThreshold struct {
+	Min   float64 `json:"min"`
+	Color string  `json:"color"` // shields.io の色名（brightgreen, yellow など）または "#rrggbb"
+}
+
+// ContextConfig defines a named subset of the repository with its own AI target
+type ContextConfig struct {
+	Paths              []string `json:"paths"`                          // 対象ディレクトリ（リポジトリルートからの相対パス）
+	TargetAIPercentage float64  `json:"target_ai_percentage,omitempty"` // 0=全体の target_ai_percentage を使用
 }
 
 // GetCheckpointTTL はチェックポイントのTTLをtime.Durationで返します。
@@ -121,6 +300,44 @@ func (c *Config) GetCheckpointTTL() time.Duration {
 	return 24 * time.Hour
 }
 
+// GetDocsExtensions はドキュメントとして扱う拡張子を返します。
+// docs が未設定の場合は nil（コードとドキュメントを分けない）を返します。
+func (c *Config) GetDocsExtensions() []string {
+	if c.Docs == nil {
+		return nil
+	}
+	if len(c.Docs.Extensions) > 0 {
+		return c.Docs.Extensions
+	}
+	return DefaultDocsExtensions
+}
+
+// GetDocsTarget はドキュメントの目標AI%を返します（未設定時は全体の目標値）。
+func (c *Config) GetDocsTarget() float64 {
+	if c.Docs != nil && c.Docs.TargetAIPercentage > 0 {
+		return c.Docs.TargetAIPercentage
+	}
+	return c.TargetAIPercentage
+}
+
+// GetBaselineInterval は --scheduled でベースラインを作成する間隔を返します。
+// 未設定の場合、デフォルト7日を返します。
+func (c *Config) GetBaselineInterval() time.Duration {
+	if c.Baseline != nil && c.Baseline.IntervalDays > 0 {
+		return time.Duration(c.Baseline.IntervalDays) * 24 * time.Hour
+	}
+	return 7 * 24 * time.Hour
+}
+
+// GetBaselineRetention は保持するベースライン数を返します。
+// 未設定の場合、デフォルト8件（週次で約2か月分）を返します。
+func (c *Config) GetBaselineRetention() int {
+	if c.Baseline != nil && c.Baseline.Retention > 0 {
+		return c.Baseline.Retention
+	}
+	return 8
+}
+
 // SPEC.md準拠の型定義
 
 // AuthorType represents the type of code author
@@ -136,12 +353,20 @@ type Change struct {
 	Added   int     `json:"added"`
 	Deleted int     `json:"deleted"`
 	Lines   [][]int `json:"lines"` // [[start, end], [single], ...]
+
+	// 再帰属（作成者の再分類など）用のdiff hunkヘッダ。作業ツリーがなくても変更位置を復元できる
+	Hunks          [][4]int `json:"hunks,omitempty"`           // [[old_start, old_count, new_start, new_count], ...]
+	HunksTruncated bool     `json:"hunks_truncated,omitempty"` // 上限超過によりHunksを記録しなかった
+
+	// 変更前のファイルにコンフリクトマーカーがあった（マージコンフリクトの解消）
+	ConflictResolution bool `json:"conflict_resolution,omitempty"`
 }
 
 // FileSnapshot represents a snapshot of a file at a specific point in time
 type FileSnapshot struct {
-	Hash  string `json:"hash"`  // SHA-256 hash of file content
-	Lines int    `json:"lines"` // Total number of lines
+	Hash       string `json:"hash"`                 // SHA-256 hash of file content
+	Lines      int    `json:"lines"`                // Total number of lines
+	Conflicted bool   `json:"conflicted,omitempty"` // マージのコンフリクトマーカーを含む
 }
 
 // CheckpointV2 represents a development checkpoint (SPEC.md準拠)
@@ -153,6 +378,49 @@ type CheckpointV2 struct {
 	Changes    map[string]Change       `json:"changes"`            // filepath -> Change
 	Snapshot   map[string]FileSnapshot `json:"snapshot"`            // filepath -> FileSnapshot (current state)
 	BaseCommit string                  `json:"base_commit,omitempty"` // チェックポイント取得時のHEADハッシュ
+	AIWeight   *float64                `json:"ai_weight,omitempty"`   // 変更のうちAIに帰属させる割合（0〜1、nil=Typeに従う）
+}
+
+// AISession is the latest Claude Code session seen by the hooks (.git/aict/session.json)
+// 行数はセッション中の各チェックポイントの変更行数の合計で、コミットをまたいで累計します。
+type AISession struct {
+	SessionID    string     `json:"session_id"`
+	StartedAt    time.Time  `json:"started_at"`
+	LastActivity time.Time  `json:"last_activity"`        // 最後にhookがチェックポイントを記録した日時
+	StoppedAt    *time.Time `json:"stopped_at,omitempty"` // Stop hook（応答の完了）の日時。以降にツール使用があれば nil に戻す
+	LastTool     string     `json:"last_tool,omitempty"`
+	ToolUses     int        `json:"tool_uses"`
+	AIAdded      int        `json:"ai_added"`
+	AIDeleted    int        `json:"ai_deleted"`
+	HumanAdded   int        `json:"human_added"`
+	HumanDeleted int        `json:"human_deleted"`
+}
+
+// ActiveIdentity is the human author set by `aict whoami set` (.git/aict/whoami.json)
+// 共有の作業端末で開発者が交代する場合に、git config user.name より優先して使います。
+type ActiveIdentity struct {
+	Name  string    `json:"name"`
+	SetAt time.Time `json:"set_at"`
+}
+
+// SessionStatus is the output of aict status
+type SessionStatus struct {
+	State              string          `json:"state"` // running, waiting, inactive, none
+	Session            *AISession      `json:"session,omitempty"`
+	PendingCheckpoints int             `json:"pending_checkpoints"` // 前回のコミット以降のチェックポイント数
+	Tracking           *TrackingStatus `json:"tracking,omitempty"`
+}
+
+// TrackingStatus is the repository-level tracking summary shown by aict status
+// AI%は日次集計（daily_rollups.jsonl）の直近7日間・その前の7日間の追加行から求めます（追加行がない場合は nil）。
+type TrackingStatus struct {
+	Branch               string     `json:"branch,omitempty"` // 空文字列は detached HEAD
+	AIPercentage         *float64   `json:"ai_percentage,omitempty"`
+	PreviousAIPercentage *float64   `json:"previous_ai_percentage,omitempty"`
+	TargetAIPercentage   float64    `json:"target_ai_percentage"`
+	AuthorshipLogs       int        `json:"authorship_logs"` // Authorship Logのあるコミット数
+	LastCheckpoint       *time.Time `json:"last_checkpoint,omitempty"`
+	HookProblems         int        `json:"hook_problems"` // aict hooks status が報告する問題の数
 }
 
 // AuthorshipLog represents commit-level authorship information
@@ -165,7 +433,15 @@ type AuthorshipLog struct {
 
 // FileInfo contains author information for a single file
 type FileInfo struct {
-	Authors []AuthorInfo `json:"authors"`
+	Authors   []AuthorInfo    `json:"authors"`
+	Rewritten *RewrittenLines `json:"rewritten,omitempty"` // このコミットで削除・変更した既存行の元の作成者種別
+}
+
+// RewrittenLines はコミットが削除・変更した親コミットの行数を、その行の元の作成者種別ごとに数えたものです。
+// 元の行にAuthorship Logがない場合はどちらにも数えません。
+type RewrittenLines struct {
+	AI    int `json:"ai,omitempty"`
+	Human int `json:"human,omitempty"`
 }
 
 // AuthorInfo represents a single author's contribution to a file
@@ -174,6 +450,19 @@ type AuthorInfo struct {
 	Type     AuthorType        `json:"type"`
 	Lines    [][]int           `json:"lines"` // [[start, end], ...]
 	Metadata map[string]string `json:"metadata,omitempty"`
+	AIWeight *float64          `json:"ai_weight,omitempty"` // 行のうちAIに帰属させる割合（0〜1、nil=Typeに従う）
+}
+
+// AIShare は作成者エントリの行のうちAIに帰属させる割合を返します。
+// AIWeight が指定されていればその値、なければ Type が ai なら1、それ以外は0です。
+func (a *AuthorInfo) AIShare() float64 {
+	if a.AIWeight != nil {
+		return *a.AIWeight
+	}
+	if a.Type == AuthorTypeAI {
+		return 1
+	}
+	return 0
 }
 
 // Report represents generated code generation report
@@ -184,7 +473,276 @@ type Report struct {
 	Period   *Period      `json:"period,omitempty"`
 	Summary  SummaryStats `json:"summary"`
 	ByFile   []FileStats  `json:"by_file,omitempty"`
+	ByDir    []FileStats  `json:"by_dir,omitempty"`
 	ByAuthor []AuthorStats `json:"by_author,omitempty"`
+	Sample   *SampleInfo   `json:"sample,omitempty"`
+
+	AuthorBreakdown []AuthorBreakdown `json:"author_breakdown,omitempty"`
+	ByLanguage      []LanguageStats   `json:"by_language,omitempty"`
+
+	Context            string         `json:"context,omitempty"`              // --context 指定時のコンテキスト名
+	TargetAIPercentage float64        `json:"target_ai_percentage,omitempty"` // --context 指定時のコンテキストの目標AI%
+	ByContext          []ContextStats `json:"by_context,omitempty"`
+
+	RepoLanguages []RepoLanguageShare `json:"repo_languages,omitempty"` // 設定に記録されたリポジトリの言語構成
+
+	// 設定の docs がある場合のコード・ドキュメント別の集計（Summary はその合計）
+	Code *CategoryStats `json:"code,omitempty"`
+	Docs *CategoryStats `json:"docs,omitempty"`
+
+	Artifacts *ArtifactStats `json:"artifacts,omitempty"` // 設定の artifacts に一致したファイルの変更数（行数の集計には含まない）
+
+	Overrides []string `json:"overrides,omitempty"` // 集計時に適用された設定の overrides のルール名
+
+	Trend *Trend `json:"trend,omitempty"` // --trend 指定時の期間ごとのAI%の推移
+
+	AIChangeSizes *AIChangeSizes `json:"ai_change_sizes,omitempty"` // --sizes 指定時のAIの変更の大きさの分布
+}
+
+// Trend holds AI percentage per period for report --trend
+type Trend struct {
+	Period string       `json:"period"`           // day, week, month
+	Smooth string       `json:"smooth,omitempty"` // --smooth の移動平均の期間（7d など）
+	Points []TrendPoint `json:"points"`
+}
+
+// AIChangeSizes holds the distribution of AI-added lines per file per commit for report --sizes
+// チェックポイントはコミット時に消費されるため、Authorship LogのファイルごとのAIの追加行数を1件の変更として扱います。
+type AIChangeSizes struct {
+	Changes          int        `json:"changes"` // AIが行を追加した（コミット, ファイル）の数
+	Mean             float64    `json:"mean"`
+	P50              int        `json:"p50"`
+	P90              int        `json:"p90"`
+	Max              int        `json:"max"`
+	OutlierThreshold float64    `json:"outlier_threshold,omitempty"` // これを超える変更を外れ値とする（Q3 + 3×IQR）
+	Outliers         []AIChange `json:"outliers,omitempty"`          // 大きい順
+}
+
+// AIChange is the AI-added lines of one file in one commit
+type AIChange struct {
+	Commit string `json:"commit"`
+	Path   string `json:"path"`
+	Lines  int    `json:"lines"`
+}
+
+// AggregateReport is the combined report of several repositories (aict aggregate)
+type AggregateReport struct {
+	Range   string       `json:"range"` // 各リポジトリに適用した範囲（"since 30d" など）
+	Commits int          `json:"commits"`
+	Summary SummaryStats `json:"summary"`
+	Repos   []RepoReport `json:"repos"`
+}
+
+// RepoReport holds one repository's totals in an AggregateReport
+type RepoReport struct {
+	Name    string       `json:"name"`
+	Path    string       `json:"path"`
+	Commits int          `json:"commits"`
+	Summary SummaryStats `json:"summary"`
+	Error   string       `json:"error,omitempty"` // 集計できなかった理由（合計には含めない）
+}
+
+// ReportComparison holds two reports and their difference for report --compare-to
+type ReportComparison struct {
+	Current  *Report     `json:"current"`
+	Previous *Report     `json:"previous"`
+	Delta    ReportDelta `json:"delta"` // current - previous
+}
+
+// ReportDelta holds the change from the compared report to the current one
+type ReportDelta struct {
+	Commits            int     `json:"commits"`
+	TotalLines         int     `json:"total_lines"`
+	AILines            int     `json:"ai_lines"`
+	HumanLines         int     `json:"human_lines"`
+	AIPercentagePoints float64 `json:"ai_percentage_points"` // AI%の差（ポイント）
+}
+
+// TrendPoint holds added lines and AI percentage for one period
+type TrendPoint struct {
+	Start        string  `json:"start"` // 期間の開始日（month は YYYY-MM）
+	Commits      int     `json:"commits"`
+	AILines      int     `json:"ai_lines"`
+	HumanLines   int     `json:"human_lines"`
+	AIPercentage float64 `json:"ai_percentage"`
+	// SmoothedAIPercentage は --smooth の期間の追加行数で求めた移動平均のAI%（期間内に追加行がなければ nil）
+	SmoothedAIPercentage *float64 `json:"smoothed_ai_percentage,omitempty"`
+}
+
+// ArtifactStats counts changes to files matched by config "artifacts" by file, not by line.
+// 1コミットでの1ファイルの変更を1件とし、その変更の行を多く書いた側（AI/人間）に数えます。
+type ArtifactStats struct {
+	Files        int     `json:"files"`   // 範囲内で変更されたファイル数（重複なし）
+	Changes      int     `json:"changes"` // ファイル変更の件数（コミット×ファイル）
+	AIChanges    int     `json:"ai_changes"`
+	HumanChanges int     `json:"human_changes"`
+	AIPercentage float64 `json:"ai_percentage"`
+}
+
+// CategoryStats is the code or docs part of a report when config "docs" is set
+type CategoryStats struct {
+	Summary            SummaryStats    `json:"summary"`
+	Metrics            DetailedMetrics `json:"metrics"`
+	TargetAIPercentage float64         `json:"target_ai_percentage"`
+}
+
+// OwnershipMap is the per-file, per-line-range authorship export of 'aict ownership'
+type OwnershipMap struct {
+	Version     int              `json:"version"`
+	Commit      string           `json:"commit"` // 対象リビジョンの解決済みハッシュ
+	GeneratedAt time.Time        `json:"generated_at"`
+	Since       *time.Time       `json:"since,omitempty"` // この日時以降にコミットされた行のみを対象にした場合の基準（導入日）
+	Summary     OwnershipSummary `json:"summary"`
+	Files       []FileOwnership  `json:"files"`
+}
+
+// ChurnReport represents added, deleted and net lines per period (aict churn)
+type ChurnReport struct {
+	Range    string         `json:"range"`
+	Period   string         `json:"period"` // day / week / month
+	Commits  int            `json:"commits"`
+	Periods  []ChurnPeriod  `json:"periods"`
+	Total    ChurnPeriod    `json:"total"`
+	Survival *SurvivalStats `json:"survival,omitempty"`
+}
+
+// ChurnPeriod totals the lines of the commits in one period
+type ChurnPeriod struct {
+	Start        string `json:"start"` // 期間の開始日（YYYY-MM-DD、month は YYYY-MM）
+	Commits      int    `json:"commits"`
+	AIAdded      int    `json:"ai_added"`
+	AIDeleted    int    `json:"ai_deleted"`
+	AINet        int    `json:"ai_net"`
+	HumanAdded   int    `json:"human_added"`
+	HumanDeleted int    `json:"human_deleted"`
+	HumanNet     int    `json:"human_net"`
+}
+
+// SurvivalStats represents how many AI-added lines still exist N days after their commit
+type SurvivalStats struct {
+	Days            int     `json:"days"`
+	EligibleCommits int     `json:"eligible_commits"` // N日以上前のAIの追加行があるコミット数
+	SampledCommits  int     `json:"sampled_commits"`  // そのうち blame で確認したコミット数
+	AIAdded         int     `json:"ai_added"`         // 確認したコミットのAIの追加行数
+	AISurviving     int     `json:"ai_surviving"`     // そのうちN日後も残っている行数
+	Rate            float64 `json:"rate"`             // AISurviving / AIAdded（%）
+}
+
+// OwnershipSummary totals the line ownership of all files in an OwnershipMap
+type OwnershipSummary struct {
+	AILines         int     `json:"ai_lines"`
+	HumanLines      int     `json:"human_lines"`
+	UnknownLines    int     `json:"unknown_lines"`
+	AIPercentage    float64 `json:"ai_percentage"`            // 全行に対するAIの行の割合
+	Commits         int     `json:"commits"`                  // 行を最後に変更したコミットの数
+	CommitsWithLogs int     `json:"commits_with_logs"`        // そのうちAuthorship Logがあるコミットの数
+	ExcludedLines   int     `json:"excluded_lines,omitempty"` // Since より前にコミットされ、集計から除いた行数
+}
+
+// FileOwnership lists contiguous line ranges of a file grouped by author
+type FileOwnership struct {
+	Path         string           `json:"path"`
+	AILines      int              `json:"ai_lines"`
+	HumanLines   int              `json:"human_lines"`
+	UnknownLines int              `json:"unknown_lines"`
+	AIPercentage float64          `json:"ai_percentage"` // ファイルの全行に対するAIの行の割合
+	Ranges       []OwnershipRange `json:"ranges"`
+}
+
+// OwnershipRange is a contiguous range of lines [Start, End] written by the same author in the same commit
+type OwnershipRange struct {
+	Start  int    `json:"start"`
+	End    int    `json:"end"`
+	Type   string `json:"type"`             // ai, human, unknown（Authorship Logなし）
+	Author string `json:"author,omitempty"` // Authorship Logの作成者名
+	Tool   string `json:"tool,omitempty"`   // AIのモデル名（チェックポイントの --model）
+	Commit string `json:"commit"`           // 行を最後に変更したコミット
+}
+
+// DailyRollup is a per-day, per-branch aggregate of committed authorship (daily_rollups.jsonl)
+type DailyRollup struct {
+	Date            string                   `json:"date"`             // コミット日（YYYY-MM-DD）
+	Branch          string                   `json:"branch,omitempty"` // コミット時のブランチ
+	AIAdded         int                      `json:"ai_added"`
+	AIDeleted       int                      `json:"ai_deleted"`
+	HumanAdded      int                      `json:"human_added"`
+	HumanDeleted    int                      `json:"human_deleted"`
+	AIEditedByHuman int                      `json:"ai_edited_by_human,omitempty"` // AIが書いた行を人間が削除・変更した行数
+	HumanEditedByAI int                      `json:"human_edited_by_ai,omitempty"` // 人間が書いた行をAIが削除・変更した行数
+	Commits         []string                 `json:"commits"`                      // 集計済みコミット（重複加算防止・範囲照合用）
+	Authors         map[string]*RollupAuthor `json:"authors,omitempty"`
+}
+
+// Add は delta の集計値とコミットを r に加算します。
+func (r *DailyRollup) Add(delta *DailyRollup) {
+	r.AIAdded += delta.AIAdded
+	r.AIDeleted += delta.AIDeleted
+	r.HumanAdded += delta.HumanAdded
+	r.HumanDeleted += delta.HumanDeleted
+	r.AIEditedByHuman += delta.AIEditedByHuman
+	r.HumanEditedByAI += delta.HumanEditedByAI
+	r.Commits = append(r.Commits, delta.Commits...)
+
+	for name, a := range delta.Authors {
+		if r.Authors == nil {
+			r.Authors = make(map[string]*RollupAuthor)
+		}
+		existing, ok := r.Authors[name]
+		if !ok {
+			existing = &RollupAuthor{Type: a.Type}
+			r.Authors[name] = existing
+		}
+		existing.Lines += a.Lines
+		existing.Deleted += a.Deleted
+		existing.Commits += a.Commits
+		existing.AILines += a.AILines
+	}
+}
+
+// RollupAuthor holds per-author totals within a DailyRollup
+type RollupAuthor struct {
+	Type    AuthorType `json:"type"`
+	Lines   int        `json:"lines"`
+	Deleted int        `json:"deleted,omitempty"`
+	Commits int        `json:"commits"`
+	AILines int        `json:"ai_lines,omitempty"` // Lines のうちAIに帰属した行数（未記録の旧レコードは Type から判定）
+}
+
+// RedactionLogEntry records one path or message hidden by the redaction settings (redactions.jsonl)
+// .git/aict はリモートと共有しないため、パスは確認用に元のパスも記録します（メッセージの元の値は記録しない）。
+type RedactionLogEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Commit    string    `json:"commit,omitempty"` // チェックポイントの場合は空
+	Kind      string    `json:"kind"`             // "path" または "message"
+	Reason    string    `json:"reason"`           // pattern, keyword, token, entropy
+	Redacted  string    `json:"redacted"`
+	Original  string    `json:"original,omitempty"`
+}
+
+// ReclassifyLogEntry is an audit record written by 'aict reclassify --apply' (reclassify_log.jsonl)
+type ReclassifyLogEntry struct {
+	Timestamp time.Time          `json:"timestamp"`
+	User      string             `json:"user,omitempty"` // 実行者（git config user.name）
+	To        AuthorType         `json:"to"`
+	Filters   map[string]string  `json:"filters"` // 指定されたフィルタ（author, since, until, branch, range, tool）
+	Changes   []ReclassifyChange `json:"changes"`
+}
+
+// ReclassifyChange describes one author entry reclassified within a commit's Authorship Log
+type ReclassifyChange struct {
+	Commit string     `json:"commit"`
+	Author string     `json:"author"`
+	From   AuthorType `json:"from"`
+	Files  int        `json:"files"`
+	Lines  int        `json:"lines"`
+}
+
+// SampleInfo describes sampling applied to a report (--sample)
+type SampleInfo struct {
+	Rate           float64 `json:"rate"`            // 指定サンプル率（0〜1）
+	SampledCommits int     `json:"sampled_commits"` // 実際に集計したコミット数
+	TotalCommits   int     `json:"total_commits"`   // 範囲内の全コミット数
+	MarginOfError  float64 `json:"margin_of_error"` // AI%の95%信頼区間の半幅（ポイント）
 }
 
 // Period represents a time period
@@ -206,6 +764,39 @@ type AuthorStats struct {
 	Name       string     `json:"name"`
 	Type       AuthorType `json:"type"`
 	Lines      int        `json:"lines"`
+	Deleted    int        `json:"deleted,omitempty"`
 	Percentage float64    `json:"percentage"`
 	Commits    int        `json:"commits,omitempty"`
+	AILines    int        `json:"ai_lines,omitempty"` // Lines のうちAIに帰属した行数（ai_weight による按分を含む）
+}
+
+// LanguageStats represents statistics per programming language (report --by-language)
+type LanguageStats struct {
+	Language   string  `json:"language"`
+	Files      int     `json:"files"`
+	TotalLines int     `json:"total_lines"`
+	AILines    int     `json:"ai_lines"`
+	HumanLines int     `json:"human_lines"`
+	Percentage float64 `json:"ai_percentage"` // TotalLines に占めるAI行の割合
+}
+
+// ContextStats represents statistics per tracking context (config "contexts")
+type ContextStats struct {
+	Name               string  `json:"name"` // コンテキスト名（どのコンテキストにも属さないファイルは "(unassigned)"）
+	TotalLines         int     `json:"total_lines"`
+	AILines            int     `json:"ai_lines"`
+	HumanLines         int     `json:"human_lines"`
+	Percentage         float64 `json:"ai_percentage"`
+	TargetAIPercentage float64 `json:"target_ai_percentage,omitempty"`
+}
+
+// AuthorBreakdown is a per-author summary grouped by AuthorMappings (report --by-author)
+type AuthorBreakdown struct {
+	Name         string   `json:"name"`              // AuthorMappings解決後の名前
+	Aliases      []string `json:"aliases,omitempty"` // 集約された元の作成者名
+	Added        int      `json:"added"`
+	Deleted      int      `json:"deleted"`
+	AILines      int      `json:"ai_lines"`
+	AIPercentage float64  `json:"ai_percentage"` // Added に占めるAI行の割合
+	Commits      int      `json:"commits"`
 }
@@ -99,3 +99,14 @@ func TestGetDisplayBranch(t *testing.T) {
 		})
 	}
 }
+
+func TestDailyRollupAdd_AuthorAILines(t *testing.T) {
+	r := &DailyRollup{}
+	r.Add(&DailyRollup{Commits: []string{"a"}, Authors: map[string]*RollupAuthor{"dev": {Type: AuthorTypeHuman, Lines: 10, AILines: 5, Commits: 1}}})
+	r.Add(&DailyRollup{Commits: []string{"b"}, Authors: map[string]*RollupAuthor{"dev": {Type: AuthorTypeHuman, Lines: 4, AILines: 2, Commits: 1}}})
+
+	dev := r.Authors["dev"]
+	if dev.Lines != 14 || dev.AILines != 7 || dev.Commits != 2 {
+		t.Errorf("merged author = %+v, want Lines=14 AILines=7 Commits=2", dev)
+	}
+}
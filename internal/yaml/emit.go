@@ -0,0 +1,159 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// member はキーの順序を保ったマッピングの1要素です。
+type member struct {
+	key   string
+	value interface{}
+}
+
+// orderedMap はJSONのオブジェクトをキーの出現順のまま保持します。
+type orderedMap []member
+
+// FromJSON はJSONをブロック形式のYAMLに変換します。キーの順序はJSONのまま保持します。
+func FromJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := decodeOrdered(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	switch v := v.(type) {
+	case orderedMap:
+		if len(v) == 0 {
+			buf.WriteString("{}\n")
+		}
+		writeMapping(&buf, v, 0)
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString("[]\n")
+		}
+		writeSequence(&buf, v, 0)
+	default:
+		buf.WriteString(formatScalar(v) + "\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeOrdered はトークン列からオブジェクトを orderedMap として読みます。
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		m := orderedMap{}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, member{key: keyTok.(string), value: value})
+		}
+		_, err := dec.Token() // '}'
+		return m, err
+	case json.Delim('['):
+		seq := []interface{}{}
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, value)
+		}
+		_, err := dec.Token() // ']'
+		return seq, err
+	}
+	return tok, nil
+}
+
+func writeMapping(buf *bytes.Buffer, m orderedMap, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for _, kv := range m {
+		buf.WriteString(pad + formatString(kv.key) + ":")
+		writeNested(buf, kv.value, indent)
+	}
+}
+
+func writeSequence(buf *bytes.Buffer, seq []interface{}, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for _, item := range seq {
+		// マッピングの要素は1つ目のキーを "- " と同じ行に書く
+		if m, ok := item.(orderedMap); ok && len(m) > 0 {
+			var inner bytes.Buffer
+			writeMapping(&inner, m, indent+2)
+			buf.WriteString(pad + "- " + strings.TrimPrefix(inner.String(), pad+"  "))
+			continue
+		}
+		buf.WriteString(pad + "-")
+		writeNested(buf, item, indent)
+	}
+}
+
+// writeNested は "key:" または "-" の後ろに値を書きます（空でないコレクションは次行から字下げ）。
+func writeNested(buf *bytes.Buffer, value interface{}, indent int) {
+	switch v := value.(type) {
+	case orderedMap:
+		if len(v) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		buf.WriteString("\n")
+		writeMapping(buf, v, indent+2)
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteString("\n")
+		writeSequence(buf, v, indent+2)
+	default:
+		buf.WriteString(" " + formatScalar(v) + "\n")
+	}
+}
+
+func formatScalar(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return fmt.Sprint(v)
+	case json.Number:
+		return v.String()
+	case string:
+		return formatString(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// plainPattern はクォートなしで書ける文字列（記号で始まらず、コメント・区切りと紛らわしい文字を含まない）です。
+var plainPattern = regexp.MustCompile(`^[A-Za-z0-9_./$(][A-Za-z0-9_./$()*+=@ -]*$`)
+
+// formatString は文字列をプレーンスカラーまたはダブルクォート文字列にします。
+// 数値・真偽値・null と読めてしまう文字列や、末尾が空白の文字列はクォートします。
+func formatString(s string) string {
+	if plainPattern.MatchString(s) && !strings.HasSuffix(s, " ") {
+		if v, err := parseScalar(s, 0); err == nil && v == s {
+			return s
+		}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimRight(buf.String(), "\n")
+}
@@ -0,0 +1,459 @@
+// Package yaml implements the subset of YAML used by aict's config.yaml.
+// 外部ライブラリに依存しないよう、設定ファイルに必要な構文のみを扱います:
+//   - ブロック形式のマッピング・シーケンス（"- key: value" 形式の要素を含む）
+//   - プレーン・シングルクォート・ダブルクォートのスカラー、数値、true/false、null/~
+//   - 1行のフロー形式（[a, b] / {k: v}）、ブロックスカラー（| と >）、# コメント
+//
+// アンカー・エイリアス・タグ・複数ドキュメントには対応しません。
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// line は空行・コメント行を除いた1行です。
+type line struct {
+	num    int    // 1始まりの行番号
+	indent int    // 先頭の空白数
+	text   string // インデントと行末コメントを除いた内容
+	raw    string // ブロックスカラー用の元の行（改行なし）
+}
+
+// parser は行単位の再帰下降パーサです。
+type parser struct {
+	lines []line
+	pos   int
+	all   []string // ブロックスカラー用に空行・コメント行も含めた元の行
+}
+
+// ToJSON はYAMLをJSONに変換します。構文エラーは行番号付きで返します。
+func ToJSON(data []byte) ([]byte, error) {
+	v, err := Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// Unmarshal はYAMLを map[string]interface{} / []interface{} / string / json.Number / bool / nil に変換します。
+// 空のドキュメントは空のマッピングとして扱います。
+func Unmarshal(data []byte) (interface{}, error) {
+	p := &parser{all: strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")}
+	for i, raw := range p.all {
+		if strings.TrimSpace(raw) == "---" && len(p.lines) == 0 {
+			continue // 先頭のドキュメント開始記号
+		}
+		trimmed := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		text := strings.TrimRight(stripComment(trimmed), " \t")
+		if text == "" {
+			continue
+		}
+		p.lines = append(p.lines, line{num: i + 1, indent: len(raw) - len(trimmed), text: text, raw: raw})
+	}
+	if len(p.lines) == 0 {
+		return map[string]interface{}{}, nil
+	}
+
+	v, err := p.parseBlock(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		return nil, fmt.Errorf("line %d: unexpected indentation", l.num)
+	}
+	return v, nil
+}
+
+// parseBlock は現在行から始まる indent のブロック（マッピングまたはシーケンス）を読みます。
+func (p *parser) parseBlock(indent int) (interface{}, error) {
+	l := p.lines[p.pos]
+	if l.indent != indent {
+		return nil, fmt.Errorf("line %d: unexpected indentation", l.num)
+	}
+	if isSeqItem(l.text) {
+		return p.parseSequence(indent)
+	}
+	if _, _, ok := splitKeyValue(l.text); ok {
+		return p.parseMapping(indent)
+	}
+	// ブロックの位置に置かれた単独のスカラー（ドキュメント全体がスカラー等）
+	p.pos++
+	return parseScalar(l.text, l.num)
+}
+
+func (p *parser) parseMapping(indent int) (interface{}, error) {
+	m := make(map[string]interface{})
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.num)
+		}
+		if isSeqItem(l.text) {
+			return nil, fmt.Errorf("line %d: sequence item in a mapping", l.num)
+		}
+		rawKey, rawValue, ok := splitKeyValue(l.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\", got %q", l.num, l.text)
+		}
+		key, err := parseKey(rawKey, l.num)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", l.num, key)
+		}
+		p.pos++
+
+		value, err := p.parseValue(rawValue, indent, l)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = value
+	}
+	return m, nil
+}
+
+func (p *parser) parseSequence(indent int) (interface{}, error) {
+	seq := []interface{}{}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.num)
+		}
+		if !isSeqItem(l.text) {
+			break // 親マッピングの次のキー（シーケンスをキーと同じインデントで書いた場合）
+		}
+		rest := strings.TrimLeft(l.text[1:], " ")
+		if rest == "" {
+			p.pos++
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				v, err := p.parseBlock(p.lines[p.pos].indent)
+				if err != nil {
+					return nil, err
+				}
+				seq = append(seq, v)
+			} else {
+				seq = append(seq, nil)
+			}
+			continue
+		}
+
+		// "- key: value" はダッシュの後ろの位置をインデントとするマッピング
+		if _, _, ok := splitKeyValue(rest); ok || isSeqItem(rest) {
+			p.lines[p.pos].indent = indent + len(l.text) - len(rest)
+			p.lines[p.pos].text = rest
+			v, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, v)
+			continue
+		}
+
+		p.pos++
+		v, err := p.parseValue(rest, indent, l)
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, v)
+	}
+	return seq, nil
+}
+
+// parseValue はキーまたは "- " の後ろの値を読みます。空の場合は次行からのブロックを読みます。
+func (p *parser) parseValue(raw string, indent int, l line) (interface{}, error) {
+	switch {
+	case raw == "":
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent {
+				return p.parseBlock(next.indent)
+			}
+			// キーと同じインデントの "- " はそのキーの値のシーケンス
+			if next.indent == indent && isSeqItem(next.text) {
+				return p.parseSequence(indent)
+			}
+		}
+		return nil, nil
+	case raw == "|" || raw == "|-" || raw == ">" || raw == ">-":
+		return p.parseBlockScalar(raw, indent, l), nil
+	}
+	return parseScalar(raw, l.num)
+}
+
+// parseBlockScalar は | （改行を保持）と > （改行を空白に折り畳む）のブロックスカラーを読みます。
+// 末尾の "-" は最後の改行を除きます。
+func (p *parser) parseBlockScalar(indicator string, indent int, header line) string {
+	var body []string
+	blockIndent := -1
+	for i := header.num; i < len(p.all); i++ { // header.num は1始まりのため次の行
+		raw := p.all[i]
+		if strings.TrimSpace(raw) == "" {
+			body = append(body, "")
+			continue
+		}
+		n := len(raw) - len(strings.TrimLeft(raw, " "))
+		if n <= indent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = n
+		}
+		if n < blockIndent {
+			break
+		}
+		body = append(body, raw[blockIndent:])
+	}
+	for len(body) > 0 && body[len(body)-1] == "" {
+		body = body[:len(body)-1]
+	}
+
+	// 読み込んだ行を行リストから読み飛ばす
+	last := header.num + len(body)
+	for p.pos < len(p.lines) && p.lines[p.pos].num <= last {
+		p.pos++
+	}
+
+	sep := "\n"
+	if strings.HasPrefix(indicator, ">") {
+		sep = " "
+	}
+	s := strings.Join(body, sep)
+	if !strings.HasSuffix(indicator, "-") && s != "" {
+		s += "\n"
+	}
+	return s
+}
+
+// isSeqItem はシーケンスの要素行（"- " で始まるか "-" のみ）かを判定します。
+func isSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKeyValue は "key: value" / "key:" をキーと値に分けます（クォート内の ": " は区切りとみなしません）。
+func splitKeyValue(text string) (key, value string, ok bool) {
+	if text == "" || text[0] == '[' || text[0] == '{' {
+		return "", "", false
+	}
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && i == 0:
+			quote = c
+		case c == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// stripComment は行末の # コメントを除きます（クォート内と、空白に続かない # は除きません）。
+func stripComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.ContainsRune(" [{,:-", rune(text[i-1])) {
+				quote = c
+			}
+		case c == '#' && (i == 0 || text[i-1] == ' '):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+func parseKey(raw string, num int) (string, error) {
+	v, err := parseScalar(raw, num)
+	if err != nil {
+		return "", err
+	}
+	switch k := v.(type) {
+	case string:
+		return k, nil
+	case nil:
+		return "", fmt.Errorf("line %d: empty key", num)
+	default:
+		return raw, nil // 数値・真偽値のキーは文字列として扱う
+	}
+}
+
+var numberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+// parseScalar は1行の値（スカラーまたはフロー形式）を解釈します。
+func parseScalar(raw string, num int) (interface{}, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	switch raw[0] {
+	case '[', '{':
+		f := &flowParser{s: raw, num: num}
+		v, err := f.parse()
+		if err != nil {
+			return nil, err
+		}
+		if f.skipSpace(); f.i != len(f.s) {
+			return nil, fmt.Errorf("line %d: unexpected %q after flow collection", num, f.s[f.i:])
+		}
+		return v, nil
+	case '"':
+		var s string
+		if err := json.Unmarshal([]byte(raw), &s); err != nil {
+			return nil, fmt.Errorf("line %d: invalid double-quoted string %s", num, raw)
+		}
+		return s, nil
+	case '\'':
+		if len(raw) < 2 || raw[len(raw)-1] != '\'' {
+			return nil, fmt.Errorf("line %d: unterminated single-quoted string %s", num, raw)
+		}
+		return strings.ReplaceAll(raw[1:len(raw)-1], "''", "'"), nil
+	case '&', '*', '!', '|', '>', '%', '@', '`':
+		return nil, fmt.Errorf("line %d: unsupported YAML syntax %q", num, raw)
+	}
+
+	switch raw {
+	case "null", "Null", "NULL", "~":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if numberPattern.MatchString(raw) {
+		return json.Number(raw), nil
+	}
+	return raw, nil
+}
+
+// flowParser は1行のフロー形式（[a, "b", {k: v}]）を読みます。
+type flowParser struct {
+	s   string
+	i   int
+	num int
+}
+
+func (f *flowParser) skipSpace() {
+	for f.i < len(f.s) && f.s[f.i] == ' ' {
+		f.i++
+	}
+}
+
+func (f *flowParser) parse() (interface{}, error) {
+	f.skipSpace()
+	if f.i >= len(f.s) {
+		return nil, fmt.Errorf("line %d: unterminated flow collection", f.num)
+	}
+	switch f.s[f.i] {
+	case '[':
+		seq := []interface{}{}
+		err := f.parseItems(']', func() error {
+			v, err := f.parse()
+			seq = append(seq, v)
+			return err
+		})
+		return seq, err
+	case '{':
+		m := make(map[string]interface{})
+		err := f.parseItems('}', func() error {
+			rawKey := f.token(":,}")
+			if f.i >= len(f.s) || f.s[f.i] != ':' {
+				return fmt.Errorf("line %d: expected ':' after key %q", f.num, rawKey)
+			}
+			f.i++
+			key, err := parseKey(rawKey, f.num)
+			if err != nil {
+				return err
+			}
+			v, err := f.parse()
+			m[key] = v
+			return err
+		})
+		return m, err
+	}
+	return parseScalar(f.token(",]}"), f.num)
+}
+
+// parseItems は開き括弧から閉じ括弧までのカンマ区切りの要素を item で読みます。
+func (f *flowParser) parseItems(closer byte, item func() error) error {
+	f.i++ // 開き括弧
+	f.skipSpace()
+	if f.i < len(f.s) && f.s[f.i] == closer {
+		f.i++
+		return nil
+	}
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		f.skipSpace()
+		if f.i >= len(f.s) {
+			return fmt.Errorf("line %d: unterminated flow collection (multi-line flow collections are not supported)", f.num)
+		}
+		switch f.s[f.i] {
+		case ',':
+			f.i++
+		case closer:
+			f.i++
+			return nil
+		default:
+			return fmt.Errorf("line %d: expected ',' or %q in flow collection", f.num, closer)
+		}
+	}
+}
+
+// token はクォートを考慮して区切り文字の手前までを返します。
+func (f *flowParser) token(stops string) string {
+	f.skipSpace()
+	start := f.i
+	if f.i < len(f.s) && (f.s[f.i] == '"' || f.s[f.i] == '\'') {
+		quote := f.s[f.i]
+		for f.i++; f.i < len(f.s) && f.s[f.i] != quote; f.i++ {
+			if f.s[f.i] == '\\' && quote == '"' {
+				f.i++
+			}
+		}
+		f.i++
+	} else {
+		for f.i < len(f.s) && !strings.ContainsRune(stops, rune(f.s[f.i])) {
+			f.i++
+		}
+	}
+	if f.i > len(f.s) {
+		f.i = len(f.s)
+	}
+	return strings.TrimSpace(f.s[start:f.i])
+}
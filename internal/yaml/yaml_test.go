@@ -0,0 +1,108 @@
+package yaml
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestToJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{
+			name: "mapping with scalars",
+			yaml: "target_ai_percentage: 80\nenabled: true\nname: my repo\nempty: ~\n",
+			want: `{"empty":null,"enabled":true,"name":"my repo","target_ai_percentage":80}`,
+		},
+		{
+			name: "sequences at key indent and nested",
+			yaml: "tracked_extensions:\n- .go\n- \".py\"\nexclude_patterns:\n  - '*_test.go'\n",
+			want: `{"exclude_patterns":["*_test.go"],"tracked_extensions":[".go",".py"]}`,
+		},
+		{
+			name: "sequence of mappings",
+			yaml: "webhooks:\n  - url: https://example.com/hook\n    events: [commit]\n  - url: http://localhost:8080\n",
+			want: `{"webhooks":[{"events":["commit"],"url":"https://example.com/hook"},{"url":"http://localhost:8080"}]}`,
+		},
+		{
+			name: "comments and flow mapping",
+			yaml: "# header\nauthor_mappings: {alice: Alice, \"bob\": Bob}  # inline\npattern: '#not-a-comment'\n",
+			want: `{"author_mappings":{"alice":"Alice","bob":"Bob"},"pattern":"#not-a-comment"}`,
+		},
+		{
+			name: "block scalars",
+			yaml: "template: |\n  {\"text\": {{json .Repo}}}\n  # kept\nfolded: >-\n  a\n  b\nnext: 1\n",
+			want: `{"folded":"a b","next":1,"template":"{\"text\": {{json .Repo}}}\n# kept\n"}`,
+		},
+		{
+			name: "empty document",
+			yaml: "# only a comment\n",
+			want: `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToJSON([]byte(tt.yaml))
+			if err != nil {
+				t.Fatalf("ToJSON() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ToJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToJSON_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"tab indentation", "a:\n\tb: 1\n", "line 2: tabs"},
+		{"bad indentation", "a: 1\n  b: 2\n", "line 2: unexpected indentation"},
+		{"duplicate key", "a: 1\nb: 2\na: 3\n", "line 3: duplicate key \"a\""},
+		{"missing colon", "a: 1\njust text\n", "line 2: expected \"key: value\""},
+		{"unterminated flow", "a: [1, 2\n", "line 1: unterminated flow"},
+		{"anchor", "a: &x 1\n", "line 1: unsupported YAML syntax"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ToJSON([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ToJSON() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFromJSON_RoundTrip(t *testing.T) {
+	input := `{"target_ai_percentage":80,"tracked_extensions":[".go",".py"],"exclude_patterns":[],` +
+		`"author_mappings":{},"default_author":"yes","commit_pattern":"^(feat|fix): ",` +
+		`"events":{"webhooks":[{"url":"https://example.com/x?a=1#b","events":["commit"],"template":"{\"a\": {{json .Repo}}}\n"}]},` +
+		`"ratio":0.5,"flag":false,"none":null,"version":"1.0"}`
+
+	out, err := FromJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("FromJSON() error = %v", err)
+	}
+	if !strings.HasPrefix(string(out), "target_ai_percentage: 80\ntracked_extensions:\n  - .go\n") {
+		t.Errorf("FromJSON() should keep key order, got:\n%s", out)
+	}
+
+	back, err := ToJSON(out)
+	if err != nil {
+		t.Fatalf("ToJSON(FromJSON()) error = %v\n%s", err, out)
+	}
+	var want, got interface{}
+	json.Unmarshal([]byte(input), &want)
+	json.Unmarshal(back, &got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch:\n got %s\nwant %s\nyaml:\n%s", back, input, out)
+	}
+}